@@ -3,6 +3,9 @@ package agent
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"math"
+	"strings"
 	"sync"
 	"testing"
 	"time"
@@ -146,6 +149,125 @@ func TestMessage(t *testing.T) {
 			t.Error("Expected error for empty payload")
 		}
 	})
+
+	t.Run("SetJSON and DecodeJSON round-trip a struct", func(t *testing.T) {
+		type TestPayload struct {
+			Name string   `json:"name"`
+			Tags []string `json:"tags"`
+		}
+
+		msg := &Message{Type: "test"}
+		if err := msg.SetJSON(TestPayload{Name: "test", Tags: []string{"a", "b"}}); err != nil {
+			t.Fatalf("SetJSON() error = %v", err)
+		}
+		if msg.GetMetadataString(MetadataKeyContentType, "") != ContentTypeJSON {
+			t.Errorf("Expected content-type %s, got %v", ContentTypeJSON, msg.Metadata)
+		}
+
+		var result TestPayload
+		if err := msg.DecodeJSON(&result); err != nil {
+			t.Fatalf("DecodeJSON() error = %v", err)
+		}
+		if result.Name != "test" || len(result.Tags) != 2 {
+			t.Errorf("Unexpected payload: %+v", result)
+		}
+
+		if err := msg.SetJSON(make(chan int)); err == nil {
+			t.Error("Expected error for unserializable value")
+		}
+	})
+
+	t.Run("DecodeJSON returns error for invalid JSON", func(t *testing.T) {
+		msg := &Message{Type: "test", Payload: "not json"}
+		var result map[string]any
+		if err := msg.DecodeJSON(&result); err == nil {
+			t.Error("Expected error for invalid JSON payload")
+		}
+	})
+
+	t.Run("DecodeJSON and SetJSON handle empty and nil messages", func(t *testing.T) {
+		var result map[string]any
+		if err := (&Message{}).DecodeJSON(&result); err == nil || !strings.Contains(err.Error(), "empty") {
+			t.Errorf("DecodeJSON() of an empty payload error = %v", err)
+		}
+		var nilMsg *Message
+		if err := nilMsg.DecodeJSON(&result); err == nil {
+			t.Error("Expected error decoding a nil message")
+		}
+		if err := nilMsg.SetJSON(result); err == nil {
+			t.Error("Expected error encoding into a nil message")
+		}
+	})
+
+	t.Run("NewErrorMessage creates error envelope", func(t *testing.T) {
+		msg := NewErrorMessage("fetcher", errors.New("timeout"))
+		if !msg.IsError() || msg.Type != MessageTypeError {
+			t.Fatalf("Expected error message, got %s", msg)
+		}
+
+		details, ok := msg.ErrorDetails()
+		if !ok || details.Agent != "fetcher" || details.Error != "timeout" {
+			t.Errorf("Unexpected error details: %+v", details)
+		}
+		if !msg.Clone().IsError() {
+			t.Error("Clone lost the error status")
+		}
+
+		plain := NewMessage("result", "ok")
+		if plain.IsError() {
+			t.Error("Expected plain message not to be an error")
+		}
+		if _, ok := plain.ErrorDetails(); ok {
+			t.Error("Expected no error details for plain message")
+		}
+	})
+
+	t.Run("typed metadata survives JSON round-trip", func(t *testing.T) {
+		msg := NewMessage("result", "ok").
+			WithMetadata("name", "a").
+			WithMetadata("cached", true).
+			WithMetadata("count", 3).
+			WithMetadata("score", 0.5)
+
+		data, err := json.Marshal(msg)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		var decoded Message
+		if err := json.Unmarshal(data, &decoded); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+
+		for _, m := range []*Message{msg, &decoded} {
+			if s, ok := m.GetString("name"); !ok || s != "a" {
+				t.Errorf("GetString = %q, %v", s, ok)
+			}
+			if b, ok := m.GetBool("cached"); !ok || !b {
+				t.Errorf("GetBool = %v, %v", b, ok)
+			}
+			if n, ok := m.GetInt("count"); !ok || n != 3 {
+				t.Errorf("GetInt = %d, %v", n, ok)
+			}
+			if f, ok := m.GetFloat("count"); !ok || f != 3 {
+				t.Errorf("GetFloat of int = %v, %v", f, ok)
+			}
+			if f, ok := m.GetFloat("score"); !ok || f != 0.5 {
+				t.Errorf("GetFloat = %v, %v", f, ok)
+			}
+			if _, ok := m.GetInt("score"); ok {
+				t.Error("Expected GetInt to reject a fractional value")
+			}
+			if _, ok := (&Message{Metadata: map[string]any{"big": math.Pow(2, 63)}}).GetInt("big"); ok {
+				t.Error("Expected GetInt to reject 2^63, which overflows int")
+			}
+			if _, ok := m.GetBool("name"); ok {
+				t.Error("Expected GetBool to reject a string")
+			}
+			if _, ok := m.GetString("missing"); ok {
+				t.Error("Expected missing key to report false")
+			}
+		}
+	})
 }
 
 // Test Agent interface implementation
@@ -282,6 +404,57 @@ func TestLocalRuntime(t *testing.T) {
 		}
 	})
 
+	t.Run("Call propagates the request ID", func(t *testing.T) {
+		rt := NewLocalRuntime()
+		agent := NewMockAgent("agent1", "test")
+		agent.ready = true
+		var seen string
+		agent.execFn = func(ctx context.Context, input *Message) (*Message, error) {
+			seen = RequestIDFromContext(ctx)
+			return NewMessage("response", nil).WithMetadata(MetadataKeyRequestID, seen), nil
+		}
+		_ = rt.Register(agent)
+
+		// NewMessage's default request ID gives way to the parent's in the context
+		ctx := ContextWithRequestID(context.Background(), "req-1")
+		input := NewMessage("request", nil)
+		if input.RequestID() != input.ID {
+			t.Fatalf("NewMessage request ID = %q, want the message ID %q", input.RequestID(), input.ID)
+		}
+		response, err := rt.Call(ctx, "agent1", input)
+		if err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if seen != "req-1" || response.RequestID() != "req-1" {
+			t.Errorf("agent saw request ID %q and replied with %q, want req-1", seen, response.RequestID())
+		}
+		if input.RequestID() != input.ID {
+			t.Errorf("Call modified the caller's input, request ID = %q", input.RequestID())
+		}
+
+		// An explicitly set request ID is kept
+		explicit := NewMessage("request", nil).WithMetadata(MetadataKeyRequestID, "req-2")
+		if _, err := rt.Call(ctx, "agent1", explicit); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if seen != "req-2" {
+			t.Errorf("agent saw request ID %q, want the explicit req-2", seen)
+		}
+
+		// Without one in the context, the message's own request ID is used
+		input = NewMessage("request", nil)
+		if _, err := rt.Call(context.Background(), "agent1", input); err != nil {
+			t.Fatalf("Call failed: %v", err)
+		}
+		if seen != input.ID {
+			t.Errorf("agent saw request ID %q, want the message's own %q", seen, input.ID)
+		}
+
+		if _, err := rt.Call(context.Background(), "agent1", nil); err != nil {
+			t.Fatalf("Call with nil input failed: %v", err)
+		}
+	})
+
 	t.Run("Call fails for non-existent agent", func(t *testing.T) {
 		rt := NewLocalRuntime()
 		ctx := context.Background()
@@ -445,6 +618,139 @@ func TestLocalRuntime(t *testing.T) {
 			t.Error("Expected error when stopping non-started runtime")
 		}
 	})
+
+	t.Run("Stop drains in-flight calls", func(t *testing.T) {
+		rt := NewLocalRuntime()
+		agent := NewMockAgent("slow", "test")
+		release := make(chan struct{})
+		started := make(chan struct{})
+		agent.execFn = func(ctx context.Context, input *Message) (*Message, error) {
+			close(started)
+			<-release
+			return NewMessage("response", nil), nil
+		}
+		_ = rt.Register(agent)
+
+		ctx := context.Background()
+		if err := rt.Start(ctx); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+
+		callErr := make(chan error, 1)
+		go func() {
+			_, err := rt.Call(ctx, "slow", NewMessage("request", nil))
+			callErr <- err
+		}()
+		<-started
+
+		stopErr := make(chan error, 1)
+		go func() { stopErr <- rt.Stop(ctx) }()
+
+		select {
+		case err := <-stopErr:
+			t.Fatalf("Stop returned before the call finished: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if _, err := rt.Call(ctx, "slow", NewMessage("request", nil)); err == nil {
+			t.Error("Expected new calls to be rejected while stopping")
+		}
+		if !agent.Ready() {
+			t.Error("Agent should not be stopped while a call is in flight")
+		}
+
+		close(release)
+		if err := <-callErr; err != nil {
+			t.Errorf("In-flight call failed: %v", err)
+		}
+		if err := <-stopErr; err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+		if agent.Ready() {
+			t.Error("Agent should be stopped after drain")
+		}
+	})
+
+	t.Run("Stop reports agents that did not drain", func(t *testing.T) {
+		rt := NewLocalRuntime()
+		agent := NewMockAgent("stuck", "test")
+		release := make(chan struct{})
+		defer close(release)
+		started := make(chan struct{})
+		agent.execFn = func(ctx context.Context, input *Message) (*Message, error) {
+			close(started)
+			<-release
+			return nil, nil
+		}
+		_ = rt.Register(agent)
+		_ = rt.Register(NewMockAgent("idle", "test"))
+
+		if err := rt.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		go func() { _, _ = rt.Call(context.Background(), "stuck", NewMessage("request", nil)) }()
+		<-started
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		err := rt.Stop(ctx)
+		if !errors.Is(err, ErrNotDrained) {
+			t.Fatalf("Expected ErrNotDrained, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "stuck") || strings.Contains(err.Error(), "idle") {
+			t.Errorf("Expected only the stuck agent in %q", err)
+		}
+		if agent.Ready() {
+			t.Error("Agent should be stopped after the deadline")
+		}
+	})
+
+	t.Run("Stop drains sent messages", func(t *testing.T) {
+		rt := NewLocalRuntime()
+		_ = rt.Register(NewMockAgent("worker", "test"))
+		if err := rt.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		ch, _ := rt.Recv("worker")
+		for i := 0; i < 3; i++ {
+			if err := rt.Send("worker", NewMessage("job", i)); err != nil {
+				t.Fatalf("Send failed: %v", err)
+			}
+		}
+
+		stopErr := make(chan error, 1)
+		go func() { stopErr <- rt.Stop(context.Background()) }()
+		select {
+		case err := <-stopErr:
+			t.Fatalf("Stop returned with messages still queued: %v", err)
+		case <-time.After(50 * time.Millisecond):
+		}
+		if err := rt.Send("worker", NewMessage("job", 3)); err == nil {
+			t.Error("Expected new messages to be rejected while stopping")
+		}
+
+		for i := 0; i < 3; i++ {
+			<-ch
+		}
+		if err := <-stopErr; err != nil {
+			t.Errorf("Stop failed: %v", err)
+		}
+	})
+
+	t.Run("Stop reports undelivered messages", func(t *testing.T) {
+		rt := NewLocalRuntime()
+		_ = rt.Register(NewMockAgent("worker", "test"))
+		if err := rt.Start(context.Background()); err != nil {
+			t.Fatalf("Start failed: %v", err)
+		}
+		_, _ = rt.Recv("worker")
+		_ = rt.Send("worker", NewMessage("job", nil))
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		if err := rt.Stop(ctx); !errors.Is(err, ErrNotDrained) {
+			t.Fatalf("Expected ErrNotDrained, got %v", err)
+		}
+	})
 }
 
 // Benchmark tests
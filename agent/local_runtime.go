@@ -2,34 +2,47 @@ package agent
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"sort"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/graph"
 	"golang.org/x/sync/errgroup"
 )
 
+// ErrNotDrained is returned by Stop when agents still had calls in flight or
+// undelivered messages at the context deadline.
+var ErrNotDrained = errors.New("agents did not drain before shutdown")
+
 // LocalRuntime is a single-process runtime for agent coordination.
 // It uses in-memory channels for message passing and is suitable for
 // applications that run all agents in a single Go binary.
 //
 // LocalRuntime is thread-safe and can be used concurrently.
 type LocalRuntime struct {
-	mu       sync.RWMutex
-	agents   map[string]Agent
-	channels map[string]chan *Message
-	order    []string // Registration order for deterministic startup
-	started  bool
+	mu        sync.RWMutex
+	agents    map[string]Agent
+	channels  map[string]chan *Message
+	receivers map[string]bool          // Channels returned by Recv, drained on Stop
+	inflight  map[string]*atomic.Int64 // Active Execute calls per agent
+	order     []string                 // Registration order for deterministic startup
+	started   bool
+	draining  bool // Set while Stop waits for in-flight calls
 }
 
 // NewLocalRuntime creates a new local runtime.
 func NewLocalRuntime() *LocalRuntime {
 	return &LocalRuntime{
-		agents:   make(map[string]Agent),
-		channels: make(map[string]chan *Message),
-		order:    make([]string, 0),
+		agents:    make(map[string]Agent),
+		channels:  make(map[string]chan *Message),
+		receivers: make(map[string]bool),
+		inflight:  make(map[string]*atomic.Int64),
+		order:     make([]string, 0),
 	}
 }
 
@@ -45,6 +58,7 @@ func (r *LocalRuntime) Register(agent Agent) error {
 
 	r.agents[name] = agent
 	r.channels[name] = make(chan *Message, 100)
+	r.inflight[name] = new(atomic.Int64)
 	r.order = append(r.order, name)
 	return nil
 }
@@ -59,6 +73,8 @@ func (r *LocalRuntime) Unregister(name string) error {
 	}
 
 	delete(r.agents, name)
+	delete(r.inflight, name)
+	delete(r.receivers, name)
 	if ch, exists := r.channels[name]; exists {
 		close(ch)
 		delete(r.channels, name)
@@ -98,19 +114,34 @@ func (r *LocalRuntime) List() []string {
 }
 
 // Call sends a message to an agent and waits for a synchronous response.
+// Calls are rejected while the runtime is stopping.
 func (r *LocalRuntime) Call(ctx context.Context, target string, input *Message) (*Message, error) {
 	r.mu.RLock()
+	if r.draining {
+		r.mu.RUnlock()
+		return nil, fmt.Errorf("runtime is stopping")
+	}
 	a, exists := r.agents[target]
+	inflight := r.inflight[target]
+	if exists && inflight != nil {
+		// Registered under the lock so Stop cannot miss the call
+		inflight.Add(1)
+	}
 	r.mu.RUnlock()
 
 	if !exists {
 		return nil, fmt.Errorf("agent %s not found", target)
 	}
+	if inflight != nil {
+		defer inflight.Add(-1)
+	}
 
 	if !a.Ready() {
 		return nil, fmt.Errorf("agent %s not ready", target)
 	}
 
+	// Correlate everything this call triggers under one request ID
+	ctx, input = WithRequestID(ctx, input)
 	return a.Execute(ctx, input)
 }
 
@@ -144,11 +175,17 @@ func (r *LocalRuntime) CallParallel(ctx context.Context, targets []string, input
 }
 
 // Send sends a message to an agent asynchronously.
+// Messages are rejected while the runtime is stopping.
 func (r *LocalRuntime) Send(target string, msg *Message) error {
 	r.mu.RLock()
+	draining := r.draining
 	ch, ok := r.channels[target]
 	r.mu.RUnlock()
 
+	if draining {
+		return fmt.Errorf("runtime is stopping")
+	}
+
 	if !ok {
 		// Create channel if it doesn't exist
 		r.mu.Lock()
@@ -175,6 +212,7 @@ func (r *LocalRuntime) Recv(source string) (<-chan *Message, error) {
 	if _, ok := r.channels[source]; !ok {
 		r.channels[source] = make(chan *Message, 100)
 	}
+	r.receivers[source] = true
 
 	return r.channels[source], nil
 }
@@ -258,7 +296,12 @@ func (r *LocalRuntime) Start(ctx context.Context) error {
 	return nil
 }
 
-// Stop gracefully shuts down all registered agents.
+// Stop gracefully shuts down all registered agents. It stops accepting new
+// calls and messages, waits for each agent's in-flight calls to finish and
+// for messages already sent to it to be received (if anything called Recv
+// for it), then stops the agent. Waiting is bounded by ctx: agents still
+// busy at its deadline are stopped anyway and reported in an error wrapping
+// ErrNotDrained.
 func (r *LocalRuntime) Stop(ctx context.Context) error {
 	r.mu.Lock()
 	if !r.started {
@@ -266,21 +309,40 @@ func (r *LocalRuntime) Stop(ctx context.Context) error {
 		return fmt.Errorf("runtime not started")
 	}
 	agents := make([]Agent, 0, len(r.agents))
-	for _, a := range r.agents {
+	inflight := make(map[string]*atomic.Int64, len(r.agents))
+	queued := make(map[string]chan *Message, len(r.agents))
+	for name, a := range r.agents {
 		agents = append(agents, a)
+		inflight[name] = r.inflight[name]
+		if r.receivers[name] {
+			queued[name] = r.channels[name]
+		}
 	}
 	r.started = false
+	r.draining = true
 	r.mu.Unlock()
 
+	defer func() {
+		r.mu.Lock()
+		r.draining = false
+		r.mu.Unlock()
+	}()
+
 	var wg sync.WaitGroup
 	var mu sync.Mutex
 	var firstErr error
+	var undrained []string
 
-	// Stop all agents concurrently
+	// Drain and stop all agents concurrently
 	for _, agent := range agents {
 		wg.Add(1)
 		go func(a Agent) {
 			defer wg.Done()
+			if !waitDrained(ctx, inflight[a.Name()], queued[a.Name()]) {
+				mu.Lock()
+				undrained = append(undrained, a.Name())
+				mu.Unlock()
+			}
 			if err := a.Stop(ctx); err != nil {
 				mu.Lock()
 				if firstErr == nil {
@@ -292,9 +354,38 @@ func (r *LocalRuntime) Stop(ctx context.Context) error {
 	}
 
 	wg.Wait()
+
+	if len(undrained) > 0 {
+		sort.Strings(undrained)
+		return errors.Join(fmt.Errorf("%w: %s", ErrNotDrained, strings.Join(undrained, ", ")), firstErr)
+	}
 	return firstErr
 }
 
+// waitDrained polls until inflight reaches zero and queued (nil if nothing
+// receives from it) is empty, reporting false if ctx is done first.
+func waitDrained(ctx context.Context, inflight *atomic.Int64, queued chan *Message) bool {
+	drained := func() bool {
+		return (inflight == nil || inflight.Load() == 0) && len(queued) == 0
+	}
+	if drained() {
+		return true
+	}
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return drained()
+		case <-ticker.C:
+			if drained() {
+				return true
+			}
+		}
+	}
+}
+
 // StartAgentsPhased starts all registered agents in dependency order.
 // The dependencies map specifies which agents each agent depends on (agent name -> dependency names).
 // Agents are started in phases based on their dependencies:
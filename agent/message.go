@@ -6,6 +6,7 @@ import (
 	"maps"
 	"time"
 
+	internalagent "github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/google/uuid"
 )
 
@@ -32,15 +33,20 @@ type Message struct {
 
 // NewMessage creates a new message with the given type and payload.
 // The payload is automatically serialized to JSON.
-// A unique ID and timestamp are automatically generated.
+// A unique ID, timestamp, and request ID are automatically generated. The
+// request ID starts out as the message ID; until it is replaced with
+// WithMetadata(MetadataKeyRequestID, id), WithRequestID swaps it for the
+// request ID in the context, so messages built inside an agent stay
+// correlated with the request that triggered them.
 func NewMessage(msgType string, payload any) *Message {
 	payloadJSON, _ := json.Marshal(payload)
+	id := uuid.New().String()
 	return &Message{
-		ID:        uuid.New().String(),
+		ID:        id,
 		Type:      msgType,
 		Payload:   string(payloadJSON),
 		Timestamp: time.Now().UTC().Format(time.RFC3339),
-		Metadata:  make(map[string]any),
+		Metadata:  map[string]any{MetadataKeyRequestID: id},
 	}
 }
 
@@ -78,6 +84,46 @@ func (m *Message) GetMetadataString(key, defaultValue string) string {
 	return defaultValue
 }
 
+// GetString returns the metadata value for key if it is a string.
+func (m *Message) GetString(key string) (string, bool) {
+	s, ok := m.GetMetadata(key, nil).(string)
+	return s, ok
+}
+
+// GetBool returns the metadata value for key if it is a bool.
+func (m *Message) GetBool(key string) (bool, bool) {
+	b, ok := m.GetMetadata(key, nil).(bool)
+	return b, ok
+}
+
+// GetFloat returns the metadata value for key if it is a number.
+// Metadata decoded from JSON holds every number as a float64, so any
+// numeric type is accepted.
+func (m *Message) GetFloat(key string) (float64, bool) {
+	return internalagent.MetadataFloat(m.Metadata, key)
+}
+
+// GetInt returns the metadata value for key if it is a whole number.
+// Floats with a fractional part are rejected rather than truncated.
+func (m *Message) GetInt(key string) (int, bool) {
+	return internalagent.MetadataInt(m.Metadata, key)
+}
+
+// Metadata keys for the usage an agent reports on its response messages.
+const (
+	MetadataKeyModel        = internalagent.MetadataKeyModel
+	MetadataKeyInputTokens  = internalagent.MetadataKeyInputTokens
+	MetadataKeyOutputTokens = internalagent.MetadataKeyOutputTokens
+	MetadataKeyTotalTokens  = internalagent.MetadataKeyTotalTokens
+	MetadataKeyCostUSD      = internalagent.MetadataKeyCostUSD
+)
+
+// MetadataFloat returns the numeric value under key in md as a float64,
+// accepting any numeric type.
+func MetadataFloat(md map[string]any, key string) (float64, bool) {
+	return internalagent.MetadataFloat(md, key)
+}
+
 // UnmarshalPayload deserializes the message payload into the provided value.
 // The value should be a pointer to the desired type.
 //
@@ -92,6 +138,43 @@ func (m *Message) UnmarshalPayload(v any) error {
 	return json.Unmarshal([]byte(m.Payload), v)
 }
 
+// Payload content types, recorded under MetadataKeyContentType.
+const (
+	// MetadataKeyContentType is the metadata key hinting at the payload encoding.
+	MetadataKeyContentType = internalagent.MetadataKeyContentType
+	// ContentTypeJSON marks a JSON payload.
+	ContentTypeJSON = internalagent.ContentTypeJSON
+)
+
+// DecodeJSON deserializes the JSON payload into v, which should be a pointer.
+// Unlike UnmarshalPayload, errors say that the payload could not be decoded.
+//
+//	var req AnalysisRequest
+//	if err := msg.DecodeJSON(&req); err != nil {
+//	    return nil, err
+//	}
+func (m *Message) DecodeJSON(v any) error {
+	if m == nil {
+		return internalagent.DecodeJSONPayload("", v)
+	}
+	return internalagent.DecodeJSONPayload(m.Payload, v)
+}
+
+// SetJSON serializes v into the payload and sets the content-type
+// metadata hint to application/json.
+func (m *Message) SetJSON(v any) error {
+	if m == nil {
+		return internalagent.ErrNilMessage
+	}
+	payload, err := internalagent.EncodeJSONPayload(v)
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	m.WithMetadata(MetadataKeyContentType, ContentTypeJSON)
+	return nil
+}
+
 // MarshalPayload is a convenience method that returns the payload as JSON bytes.
 // This is equivalent to []byte(m.Payload).
 func (m *Message) MarshalPayload() []byte {
@@ -116,3 +199,43 @@ func (m *Message) Clone() *Message {
 func (m *Message) String() string {
 	return fmt.Sprintf("Message{ID:%s, Type:%s, Timestamp:%s}", m.ID, m.Type, m.Timestamp)
 }
+
+// Error messages let an agent report a failure as a regular message so
+// downstream agents and aggregators can skip it, substitute a default, or
+// escalate it instead of the whole pipeline aborting.
+const (
+	// MessageTypeError is the type of error messages.
+	MessageTypeError = internalagent.MessageTypeError
+	// MetadataKeyStatus is the metadata key holding a message's status.
+	MetadataKeyStatus = internalagent.MetadataKeyStatus
+	// StatusError marks a message as reporting a failure.
+	StatusError = internalagent.StatusError
+)
+
+// ErrorDetails is the payload of an error message.
+type ErrorDetails = internalagent.ErrorDetails
+
+// NewErrorMessage creates an error message reporting that agentName failed with err.
+//
+//	if err != nil {
+//	    return agent.NewErrorMessage(a.Name(), err), nil
+//	}
+func NewErrorMessage(agentName string, err error) *Message {
+	return NewMessage(MessageTypeError, internalagent.NewErrorDetails(agentName, err)).
+		WithMetadata(MetadataKeyStatus, StatusError)
+}
+
+// IsError reports whether the message is an error message.
+func (m *Message) IsError() bool {
+	return m != nil && internalagent.IsErrorStatus(m.Metadata)
+}
+
+// ErrorDetails returns the details of an error message, or false if the
+// message is not an error message.
+func (m *Message) ErrorDetails() (*ErrorDetails, bool) {
+	if !m.IsError() {
+		return nil, false
+	}
+	details := internalagent.ParseErrorDetails(m.Payload)
+	return &details, true
+}
@@ -0,0 +1,54 @@
+package agent
+
+import (
+	"context"
+
+	internalagent "github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/google/uuid"
+)
+
+// MetadataKeyRequestID is the metadata key holding the correlation ID shared
+// by every message of one multi-agent request.
+const MetadataKeyRequestID = internalagent.MetadataKeyRequestID
+
+// ContextWithRequestID returns a copy of ctx carrying the request ID id.
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return internalagent.ContextWithRequestID(ctx, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if none.
+func RequestIDFromContext(ctx context.Context) string {
+	return internalagent.RequestIDFromContext(ctx)
+}
+
+// RequestID returns the message's request ID, or "" if it has none.
+func (m *Message) RequestID() string {
+	if m == nil {
+		return ""
+	}
+	id, _ := m.GetString(MetadataKeyRequestID)
+	return id
+}
+
+// WithRequestID resolves the request ID for msg: its own, else the one in
+// ctx, else a new one. The default ID NewMessage assigns (the message's own
+// ID) gives way to the one in ctx. It returns ctx and msg both carrying the
+// resolved ID; msg is cloned rather than modified when the ID has to change.
+func WithRequestID(ctx context.Context, msg *Message) (context.Context, *Message) {
+	id := msg.RequestID()
+	if parent := RequestIDFromContext(ctx); parent != "" && (id == "" || id == msg.ID) {
+		id = parent
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	if msg != nil && msg.RequestID() != id {
+		msg = msg.Clone()
+		msg.Metadata[MetadataKeyRequestID] = id
+	}
+	if RequestIDFromContext(ctx) != id {
+		ctx = ContextWithRequestID(ctx, id)
+	}
+	return ctx, msg
+}
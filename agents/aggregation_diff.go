@@ -0,0 +1,79 @@
+package agents
+
+import (
+	"slices"
+	"strings"
+)
+
+// AggregationDiff describes how an aggregation changed between two runs on
+// similar inputs, e.g. a recorded baseline and the current run
+type AggregationDiff struct {
+	ContentChanged  bool           `json:"content_changed"`
+	PreviousContent string         `json:"previous_content"`
+	CurrentContent  string         `json:"current_content"`
+	StrategyChanged bool           `json:"strategy_changed,omitempty"`
+	ConsensusBefore float64        `json:"consensus_before"`
+	ConsensusAfter  float64        `json:"consensus_after"`
+	ConsensusDelta  float64        `json:"consensus_delta"`
+	VoteChanges     map[string]int `json:"vote_changes,omitempty"` // Per-content vote delta; zero deltas omitted
+	SourcesAdded    []string       `json:"sources_added,omitempty"`
+	SourcesRemoved  []string       `json:"sources_removed,omitempty"`
+}
+
+// HasChanges reports whether the selected content, strategy, consensus
+// level, votes or sources differ between the two runs
+func (d *AggregationDiff) HasChanges() bool {
+	return d.ContentChanged || d.StrategyChanged || d.ConsensusDelta != 0 ||
+		len(d.VoteChanges) > 0 || len(d.SourcesAdded) > 0 || len(d.SourcesRemoved) > 0
+}
+
+// CompareAggregations reports the changes from a to b. Selected content is
+// compared ignoring surrounding whitespace. A nil result compares as empty.
+func CompareAggregations(a, b *AggregationResult) *AggregationDiff {
+	if a == nil {
+		a = &AggregationResult{}
+	}
+	if b == nil {
+		b = &AggregationResult{}
+	}
+
+	diff := &AggregationDiff{
+		ContentChanged:  strings.TrimSpace(a.AggregatedContent) != strings.TrimSpace(b.AggregatedContent),
+		PreviousContent: a.AggregatedContent,
+		CurrentContent:  b.AggregatedContent,
+		StrategyChanged: a.Strategy != b.Strategy,
+		ConsensusBefore: a.ConsensusLevel,
+		ConsensusAfter:  b.ConsensusLevel,
+		ConsensusDelta:  b.ConsensusLevel - a.ConsensusLevel,
+	}
+
+	for content, votes := range b.VoteDistribution {
+		if delta := votes - a.VoteDistribution[content]; delta != 0 {
+			if diff.VoteChanges == nil {
+				diff.VoteChanges = make(map[string]int)
+			}
+			diff.VoteChanges[content] = delta
+		}
+	}
+	for content, votes := range a.VoteDistribution {
+		if _, ok := b.VoteDistribution[content]; !ok && votes != 0 {
+			if diff.VoteChanges == nil {
+				diff.VoteChanges = make(map[string]int)
+			}
+			diff.VoteChanges[content] = -votes
+		}
+	}
+
+	for _, source := range b.Sources {
+		if !slices.Contains(a.Sources, source) {
+			diff.SourcesAdded = append(diff.SourcesAdded, source)
+		}
+	}
+	for _, source := range a.Sources {
+		if !slices.Contains(b.Sources, source) {
+			diff.SourcesRemoved = append(diff.SourcesRemoved, source)
+		}
+	}
+
+	return diff
+}
@@ -0,0 +1,80 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareAggregations(t *testing.T) {
+	ctx := context.Background()
+	aggAgent := &AggregatorAgent{
+		def:         agent.AgentDef{Model: "gpt-4"},
+		provider:    new(MockProvider),
+		config:      AggregatorConfig{AggregationStrategy: StrategyVotingMajority},
+		rt:          NewMockRuntime(),
+		inputBuffer: make(map[string]*AgentInput),
+	}
+
+	baseline, err := aggAgent.aggregate(ctx, []*AgentInput{
+		{AgentName: "agent1", Content: "approve", Confidence: 0.8},
+		{AgentName: "agent2", Content: "approve", Confidence: 0.8},
+		{AgentName: "agent3", Content: "approve", Confidence: 0.8},
+		{AgentName: "agent4", Content: "reject", Confidence: 0.8},
+		{AgentName: "agent5", Content: "reject", Confidence: 0.8},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]int{"approve": 3, "reject": 2}, baseline.VoteDistribution)
+
+	t.Run("identical runs", func(t *testing.T) {
+		diff := CompareAggregations(baseline, baseline)
+		assert.False(t, diff.HasChanges())
+	})
+
+	t.Run("upstream change flips the decision", func(t *testing.T) {
+		current, err := aggAgent.aggregate(ctx, []*AgentInput{
+			{AgentName: "agent1", Content: "approve", Confidence: 0.8},
+			{AgentName: "agent2", Content: "reject", Confidence: 0.8},
+			{AgentName: "agent3", Content: "escalate", Confidence: 0.8},
+			{AgentName: "agent4", Content: "reject", Confidence: 0.8},
+			{AgentName: "agent6", Content: "reject", Confidence: 0.8},
+		})
+		require.NoError(t, err)
+
+		diff := CompareAggregations(baseline, current)
+		assert.True(t, diff.HasChanges())
+		assert.True(t, diff.ContentChanged)
+		assert.Equal(t, "approve", diff.PreviousContent)
+		assert.Equal(t, "reject", diff.CurrentContent)
+		assert.False(t, diff.StrategyChanged)
+		assert.InDelta(t, 0.0, diff.ConsensusDelta, 1e-9)
+		assert.Equal(t, map[string]int{"approve": -2, "reject": 1, "escalate": 1}, diff.VoteChanges)
+		assert.Equal(t, []string{"agent6"}, diff.SourcesAdded)
+		assert.Equal(t, []string{"agent5"}, diff.SourcesRemoved)
+	})
+
+	t.Run("consensus drop without a flip", func(t *testing.T) {
+		current := &AggregationResult{
+			AggregatedContent: " approve\n",
+			Strategy:          StrategyVotingMajority,
+			ConsensusLevel:    0.4,
+			Sources:           baseline.Sources,
+			VoteDistribution:  map[string]int{"approve": 2, "reject": 1, "escalate": 1, "abstain": 1},
+		}
+
+		diff := CompareAggregations(baseline, current)
+		assert.False(t, diff.ContentChanged, "surrounding whitespace is not a content change")
+		assert.InDelta(t, -0.2, diff.ConsensusDelta, 1e-9)
+		assert.Equal(t, map[string]int{"approve": -1, "reject": -1, "escalate": 1, "abstain": 1}, diff.VoteChanges)
+	})
+
+	t.Run("nil results", func(t *testing.T) {
+		diff := CompareAggregations(nil, baseline)
+		assert.True(t, diff.ContentChanged)
+		assert.Equal(t, baseline.Sources, diff.SourcesAdded)
+		assert.False(t, CompareAggregations(nil, nil).HasChanges())
+	})
+}
@@ -3,16 +3,22 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"reflect"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
+	"text/template"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/internal/aggregation"
 	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/llm/schema"
 	"github.com/aixgo-dev/aixgo/internal/observability"
 	"github.com/aixgo-dev/aixgo/pkg/security"
 	pb "github.com/aixgo-dev/aixgo/proto"
@@ -23,6 +29,7 @@ type AggregatorConfig struct {
 	AggregationStrategy  string             `yaml:"aggregation_strategy"`
 	ConflictResolution   string             `yaml:"conflict_resolution"`
 	DeduplicationMethod  string             `yaml:"deduplication_method"`
+	Deduplicate          bool               `yaml:"deduplicate"`
 	SummarizationEnabled bool               `yaml:"summarization_enabled"`
 	MaxInputSources      int                `yaml:"max_input_sources"`
 	TimeoutMs            int                `yaml:"timeout_ms"`
@@ -31,8 +38,79 @@ type AggregatorConfig struct {
 	ConsensusThreshold   float64            `yaml:"consensus_threshold"`
 	Temperature          float64            `yaml:"temperature"`
 	MaxTokens            int                `yaml:"max_tokens"`
+
+	// InputPreprocessor names the transform applied to each input's content
+	// before aggregation, e.g. "extract-json-field:answer" or "strip-markdown|trim".
+	// See RegisterInputPreprocessor for adding custom transforms.
+	InputPreprocessor string `yaml:"input_preprocessor"`
+
+	// AllowedSources lists the sources whose outputs may be aggregated
+	// (default: the agents declared in the runtime, so an input naming
+	// anything else is rejected). Sources that are not agents, such as an
+	// external channel, must be approved here alongside the agents. A
+	// message's source is the input it arrived on; claims in its metadata are
+	// not trusted.
+	AllowedSources []string `yaml:"allowed_sources"`
+
+	// UnexpectedSources controls inputs from sources not in AllowedSources:
+	// UnexpectedSourcesReject (default) drops them, UnexpectedSourcesLog
+	// aggregates them but logs a warning.
+	UnexpectedSources string `yaml:"unexpected_sources"`
+
+	// QuorumConfidence is the combined confidence buffered inputs must reach
+	// before an aggregation window proceeds (default: 0, no quorum). Each
+	// input contributes its confidence clamped to [0, 1], so a few confident
+	// agents can meet quorum where many unsure ones do not. Inputs stay
+	// buffered until quorum is met or QuorumTimeoutMs elapses. Execute
+	// rejects an input that falls short of it with ErrQuorumNotMet.
+	QuorumConfidence float64 `yaml:"quorum_confidence"`
+
+	// QuorumHalfLifeMs decays each input's quorum contribution by half for
+	// every QuorumHalfLifeMs of age, so stale inputs count for less
+	// (default: 0, no decay).
+	QuorumHalfLifeMs int `yaml:"quorum_half_life_ms"`
+
+	// QuorumTimeoutMs is how long the oldest buffered input waits for
+	// quorum before the window aggregates anyway, flagged Degraded
+	// (default: four half-lives with decay, since decayed inputs may never
+	// reach quorum, otherwise 0, wait indefinitely).
+	QuorumTimeoutMs int `yaml:"quorum_timeout_ms"`
+
+	// MinInputSources is how many sources must report before a window
+	// aggregates early (default: 0, aggregate when the timeout elapses). An
+	// early close still waits for QuorumConfidence. If the timeout elapses
+	// with fewer inputs, those that arrived are still aggregated but the
+	// result is flagged Degraded. It cannot exceed the number of inputs.
+	MinInputSources int `yaml:"min_input_sources"`
+
+	// ConflictMinSupport is how many sources a content group needs to count
+	// as a competing option when voting strategies record conflicts
+	// (default: 1, any disagreement is recorded).
+	ConflictMinSupport int `yaml:"conflict_min_support"`
+
+	// PromptTemplates overrides the system prompt of LLM-backed strategies,
+	// keyed by strategy name (e.g. "consensus"). Each value is a Go
+	// text/template executed with PromptTemplateData; strategies without an
+	// entry use the built-in prompt.
+	PromptTemplates map[string]string `yaml:"prompt_templates"`
+
+	// MaxPromptTokens caps the estimated size of LLM aggregation prompts
+	// (default: 0, no limit). Tokens are estimated at ~4 characters each.
+	MaxPromptTokens int `yaml:"max_prompt_tokens"`
+
+	// OverBudget controls prompts over MaxPromptTokens: OverBudgetTruncate
+	// (default) drops the lowest-confidence inputs, reported in the result's
+	// DroppedSources; OverBudgetHierarchical switches to the hierarchical
+	// strategy, which summarizes inputs in groups.
+	OverBudget string `yaml:"over_budget"`
 }
 
+// Policies for inputs from unexpected sources
+const (
+	UnexpectedSourcesReject = "reject"
+	UnexpectedSourcesLog    = "log"
+)
+
 // AgentInput represents input from a single agent
 type AgentInput struct {
 	AgentName  string         `json:"agent_name"`
@@ -54,6 +132,12 @@ type AggregationResult struct {
 	TokensUsed        int                  `json:"tokens_used"`
 	ProcessingTimeMs  int64                `json:"processing_time_ms"`
 	SemanticClusters  []SemanticCluster    `json:"semantic_clusters,omitempty"`
+	DegradedParsing   bool                 `json:"degraded_parsing,omitempty"`
+	Degraded          bool                 `json:"degraded,omitempty"`
+	QuorumConfidence  float64              `json:"quorum_confidence,omitempty"`
+	VoteDistribution  map[string]int       `json:"vote_distribution,omitempty"`
+	MergedSources     map[string][]string  `json:"merged_sources,omitempty"`
+	DroppedSources    []string             `json:"dropped_sources,omitempty"`
 }
 
 // ConflictResolution describes how conflicts were resolved
@@ -62,6 +146,16 @@ type ConflictResolution struct {
 	Sources    []string `json:"conflicting_sources"`
 	Resolution string   `json:"resolution"`
 	Reasoning  string   `json:"reasoning"`
+
+	// Options and Rule are set by the deterministic voting strategies
+	Options []ConflictOption `json:"options,omitempty"`
+	Rule    string           `json:"rule,omitempty"`
+}
+
+// ConflictOption is one competing output in a conflict and its supporters
+type ConflictOption struct {
+	Content string   `json:"content"`
+	Sources []string `json:"sources"`
 }
 
 // SemanticCluster groups semantically similar inputs
@@ -80,8 +174,12 @@ type AggregatorAgent struct {
 	config   AggregatorConfig
 	rt       agent.Runtime
 
+	// promptTemplates are config.PromptTemplates, parsed once
+	promptTemplates map[string]*template.Template
+
 	// AI-specific fields for aggregation
 	inputBuffer      map[string]*AgentInput
+	quorumWaitLogged bool // Logged that the buffered inputs await quorum
 	bufferMu         sync.RWMutex
 	aggregationStats AggregationStats
 	statsMu          sync.Mutex
@@ -96,6 +194,10 @@ type AggregationStats struct {
 	ProcessingTimes   []time.Duration
 }
 
+// ErrQuorumNotMet is returned by Execute when its input's confidence falls
+// short of QuorumConfidence
+var ErrQuorumNotMet = errors.New("aggregator: quorum not met")
+
 // Aggregation strategies
 const (
 	// LLM-powered strategies
@@ -142,34 +244,77 @@ func NewAggregatorAgent(def agent.AgentDef, rt agent.Runtime) (agent.Agent, erro
 	if config.AggregationStrategy == "" {
 		config.AggregationStrategy = StrategyConsensus
 	}
+	if _, err := parsePreprocessorSpec(config.InputPreprocessor); err != nil {
+		return nil, fmt.Errorf("invalid aggregator config: %w", err)
+	}
+	if _, err := resolveDeduplicationMethod(config.DeduplicationMethod, config.Deduplicate); err != nil {
+		return nil, fmt.Errorf("invalid aggregator config: %w", err)
+	}
+	switch config.UnexpectedSources {
+	case "":
+		config.UnexpectedSources = UnexpectedSourcesReject
+	case UnexpectedSourcesReject, UnexpectedSourcesLog:
+	default:
+		return nil, fmt.Errorf("invalid aggregator config: unexpected_sources must be %q or %q, got %q",
+			UnexpectedSourcesReject, UnexpectedSourcesLog, config.UnexpectedSources)
+	}
+	if config.QuorumConfidence < 0 || config.QuorumHalfLifeMs < 0 || config.QuorumTimeoutMs < 0 {
+		return nil, fmt.Errorf("invalid aggregator config: quorum_confidence, quorum_half_life_ms and quorum_timeout_ms must not be negative")
+	}
+	if config.QuorumTimeoutMs == 0 {
+		config.QuorumTimeoutMs = 4 * config.QuorumHalfLifeMs
+	}
+	if config.MinInputSources < 0 || config.MinInputSources > len(def.Inputs) {
+		return nil, fmt.Errorf("invalid aggregator config: min_input_sources must be between 0 and the %d inputs, got %d",
+			len(def.Inputs), config.MinInputSources)
+	}
+	promptTemplates, err := parsePromptTemplates(config.PromptTemplates)
+	if err != nil {
+		return nil, fmt.Errorf("invalid aggregator config: %w", err)
+	}
+	if err := validateOverBudget(&config); err != nil {
+		return nil, fmt.Errorf("invalid aggregator config: %w", err)
+	}
 
 	// Initialize provider
 	prov, err := initializeProvider(def.Model)
 	if err != nil {
 		return nil, fmt.Errorf("failed to initialize LLM provider: %w", err)
 	}
+	probe, err := readinessProbeFromDef(def, ProviderReadinessCheck(prov))
+	if err != nil {
+		return nil, err
+	}
+	base := NewBaseAgent(def)
+	base.SetReadinessProbe(probe)
 
 	return &AggregatorAgent{
-		BaseAgent:   NewBaseAgent(def),
-		def:         def,
-		provider:    prov,
-		config:      config,
-		rt:          rt,
-		inputBuffer: make(map[string]*AgentInput),
+		BaseAgent:       base,
+		def:             def,
+		provider:        withTokenMetrics(def.Name, prov),
+		config:          config,
+		rt:              rt,
+		promptTemplates: promptTemplates,
+		inputBuffer:     make(map[string]*AgentInput),
 	}, nil
 }
 
-// Execute performs synchronous aggregation
+// Execute performs synchronous aggregation. Error messages are returned
+// unchanged so the failure reaches the caller.
 func (a *AggregatorAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
 	if !a.Ready() {
 		return nil, fmt.Errorf("agent not ready")
 	}
+	if input.IsError() {
+		return input, nil
+	}
 
 	// Convert input message to AgentInput
-	agentInput := &AgentInput{
-		AgentName: "input",
-		Content:   input.Payload,
-		Timestamp: time.Now(),
+	agentInput := newAgentInput("input", input)
+	if quorum := a.config.QuorumConfidence; quorum > 0 {
+		if achieved := a.quorumConfidence([]*AgentInput{agentInput}, agentInput.Timestamp); achieved < quorum {
+			return nil, fmt.Errorf("%w: confidence %.2f of %.2f", ErrQuorumNotMet, achieved, quorum)
+		}
 	}
 
 	// Perform aggregation and return result
@@ -210,7 +355,8 @@ func (a *AggregatorAgent) Start(ctx context.Context) error {
 	}
 
 	// Process inputs with timeout-based aggregation windows
-	ticker := time.NewTicker(time.Duration(a.config.TimeoutMs) * time.Millisecond)
+	window := time.Duration(a.config.TimeoutMs) * time.Millisecond
+	ticker := time.NewTicker(window)
 	defer ticker.Stop()
 
 	validator := &security.StringValidator{
@@ -219,6 +365,8 @@ func (a *AggregatorAgent) Start(ctx context.Context) error {
 		DisallowControlChars: true,
 	}
 
+	allowed := a.allowedSources()
+
 	for {
 		select {
 		case <-ctx.Done():
@@ -238,7 +386,21 @@ func (a *AggregatorAgent) Start(ctx context.Context) error {
 							log.Printf("Aggregator input validation error from source %d: %v", i, err)
 							continue
 						}
-						a.bufferInput(a.def.Inputs[i].Source, msg)
+						source, ok := a.checkSource(allowed, a.def.Inputs[i].Source)
+						if !ok {
+							continue
+						}
+						// Aggregate the remaining sources without the failed one
+						if details, failed := msg.ErrorDetails(); failed {
+							log.Printf("Aggregator skipping failed input from %s: %s", source, details.Error)
+							continue
+						}
+						a.bufferInput(source, msg)
+
+						// Enough sources reported: close the window early
+						if a.minInputsReached() && a.processAggregation(ctx) {
+							ticker.Reset(window)
+						}
 					}
 				default:
 					continue
@@ -248,11 +410,49 @@ func (a *AggregatorAgent) Start(ctx context.Context) error {
 	}
 }
 
+// allowedSources returns the set of sources permitted to contribute inputs:
+// the approved AllowedSources, else the agents declared in the runtime
+func (a *AggregatorAgent) allowedSources() map[string]bool {
+	sources := a.config.AllowedSources
+	if len(sources) == 0 && a.rt != nil {
+		sources = a.rt.List()
+	}
+
+	allowed := make(map[string]bool, len(sources))
+	for _, s := range sources {
+		allowed[s] = true
+	}
+	return allowed
+}
+
+// checkSource reports whether a message received on the input configured with
+// source may be aggregated. The source comes from the agent's own input
+// definition, never from the message, so a producer cannot impersonate
+// another. Inputs from unexpected sources are dropped unless the policy is
+// UnexpectedSourcesLog.
+func (a *AggregatorAgent) checkSource(allowed map[string]bool, source string) (string, bool) {
+	if allowed[source] {
+		return source, true
+	}
+	if a.config.UnexpectedSources == UnexpectedSourcesLog {
+		log.Printf("Aggregator accepting input from unexpected source %q", source)
+		return source, true
+	}
+	log.Printf("Aggregator rejected input from unexpected source %q", source)
+	return "", false
+}
+
 // bufferInput adds an input to the aggregation buffer
 func (a *AggregatorAgent) bufferInput(source string, msg *agent.Message) {
+	input := newAgentInput(source, msg)
+
 	a.bufferMu.Lock()
 	defer a.bufferMu.Unlock()
+	a.inputBuffer[source] = input
+}
 
+// newAgentInput reads the confidence and embedding a JSON payload reports
+func newAgentInput(source string, msg *agent.Message) *AgentInput {
 	input := &AgentInput{
 		AgentName: source,
 		Content:   msg.Payload,
@@ -265,10 +465,20 @@ func (a *AggregatorAgent) bufferInput(source string, msg *agent.Message) {
 		if conf, ok := metadata["confidence"].(float64); ok {
 			input.Confidence = conf
 		}
+		input.Embedding = parseEmbedding(metadata["embedding"])
 		input.Metadata = metadata
 	}
+	return input
+}
 
-	a.inputBuffer[source] = input
+// minInputsReached reports whether MinInputSources inputs are buffered
+func (a *AggregatorAgent) minInputsReached() bool {
+	if a.config.MinInputSources <= 0 {
+		return false
+	}
+	a.bufferMu.RLock()
+	defer a.bufferMu.RUnlock()
+	return len(a.inputBuffer) >= a.config.MinInputSources
 }
 
 // hasBufferedInputs checks if there are inputs to process
@@ -278,8 +488,9 @@ func (a *AggregatorAgent) hasBufferedInputs() bool {
 	return len(a.inputBuffer) > 0
 }
 
-// processAggregation performs AI-powered aggregation
-func (a *AggregatorAgent) processAggregation(ctx context.Context) {
+// processAggregation performs AI-powered aggregation. It reports false if the
+// window stays open because the buffered inputs fall short of the quorum.
+func (a *AggregatorAgent) processAggregation(ctx context.Context) bool {
 	startTime := time.Now()
 
 	a.bufferMu.Lock()
@@ -287,25 +498,44 @@ func (a *AggregatorAgent) processAggregation(ctx context.Context) {
 	for _, input := range a.inputBuffer {
 		inputs = append(inputs, input)
 	}
+	quorumTimedOut := false
+	if quorum := a.config.QuorumConfidence; quorum > 0 && len(inputs) > 0 {
+		if achieved := a.quorumConfidence(inputs, startTime); achieved < quorum {
+			timeout := time.Duration(a.config.QuorumTimeoutMs) * time.Millisecond
+			if timeout <= 0 || startTime.Sub(oldestInput(inputs)) < timeout {
+				if !a.quorumWaitLogged {
+					log.Printf("Aggregator waiting for quorum: confidence %.2f of %.2f from %d inputs", achieved, quorum, len(inputs))
+					a.quorumWaitLogged = true
+				}
+				a.bufferMu.Unlock()
+				return false
+			}
+			log.Printf("Aggregator quorum timed out at confidence %.2f of %.2f, result degraded", achieved, quorum)
+			quorumTimedOut = true
+		}
+	}
 	// Clear buffer
 	a.inputBuffer = make(map[string]*AgentInput)
+	a.quorumWaitLogged = false
 	a.bufferMu.Unlock()
 
 	if len(inputs) == 0 {
-		return
+		return true
 	}
 
-	span := observability.StartSpan("aggregator.aggregate", map[string]any{
-		"input_count": len(inputs),
-		"strategy":    a.config.AggregationStrategy,
-	})
-	defer span.End()
-
 	// Perform aggregation based on strategy
 	result, err := a.aggregate(ctx, inputs)
 	if err != nil {
 		log.Printf("Aggregation error: %v", err)
-		return
+		return true
+	}
+
+	if minimum := a.config.MinInputSources; len(inputs) < minimum {
+		log.Printf("Aggregator window closed with %d of %d required inputs, result degraded", len(inputs), minimum)
+		result.Degraded = true
+	}
+	if quorumTimedOut {
+		result.Degraded = true
 	}
 
 	result.ProcessingTimeMs = time.Since(startTime).Milliseconds()
@@ -313,6 +543,7 @@ func (a *AggregatorAgent) processAggregation(ctx context.Context) {
 
 	// Update statistics
 	a.updateStats(result, time.Since(startTime))
+	return true
 }
 
 // aggregate performs the actual AI-powered aggregation
@@ -323,6 +554,79 @@ func (a *AggregatorAgent) aggregate(ctx context.Context, inputs []*AgentInput) (
 		strategy = StrategyConsensus
 	}
 
+	_, span := observability.StartSpanWithContext(ctx, "aggregator.aggregate", map[string]any{
+		"input_count": len(inputs),
+		"strategy":    strategy,
+	})
+	defer span.End()
+
+	sources := a.extractSources(inputs)
+	inputs, err := a.preprocessInputs(inputs)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+
+	// Measured before strategies that reweight input confidences
+	quorum := a.quorumConfidence(inputs, time.Now())
+
+	inputs, merged, err := a.dedupInputs(inputs)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+
+	strategy, inputs, dropped := a.fitPromptBudget(strategy, inputs)
+
+	result, err := a.aggregateWith(ctx, strategy, inputs)
+	if err != nil {
+		span.SetError(err)
+		return nil, err
+	}
+	result.QuorumConfidence = quorum
+	if merged != nil {
+		// Strategies only saw the representatives; report every contributor
+		result.Sources = sources
+		result.MergedSources = merged
+	}
+	if dropped != nil {
+		result.Sources = slices.DeleteFunc(result.Sources, func(source string) bool {
+			return slices.Contains(dropped, source)
+		})
+		result.DroppedSources = dropped
+	}
+	return result, nil
+}
+
+// quorumConfidence sums the inputs' confidences, each clamped to [0, 1] and
+// decayed by its age when QuorumHalfLifeMs is set
+func (a *AggregatorAgent) quorumConfidence(inputs []*AgentInput, now time.Time) float64 {
+	halfLife := time.Duration(a.config.QuorumHalfLifeMs) * time.Millisecond
+
+	total := 0.0
+	for _, input := range inputs {
+		confidence := math.Max(0, math.Min(1, input.Confidence))
+		if age := now.Sub(input.Timestamp); halfLife > 0 && age > 0 {
+			confidence *= math.Pow(0.5, float64(age)/float64(halfLife))
+		}
+		total += confidence
+	}
+	return total
+}
+
+// oldestInput returns the earliest timestamp among inputs
+func oldestInput(inputs []*AgentInput) time.Time {
+	oldest := inputs[0].Timestamp
+	for _, input := range inputs[1:] {
+		if input.Timestamp.Before(oldest) {
+			oldest = input.Timestamp
+		}
+	}
+	return oldest
+}
+
+// aggregateWith runs the named aggregation strategy
+func (a *AggregatorAgent) aggregateWith(ctx context.Context, strategy string, inputs []*AgentInput) (*AggregationResult, error) {
 	switch strategy {
 	// LLM-powered strategies
 	case StrategyConsensus:
@@ -351,6 +655,32 @@ func (a *AggregatorAgent) aggregate(ctx context.Context, inputs []*AgentInput) (
 	}
 }
 
+// preprocessInputs applies the configured InputPreprocessor to each input.
+// Inputs are copied so buffered originals are left untouched. An input the
+// preprocessor cannot handle is passed through unchanged.
+func (a *AggregatorAgent) preprocessInputs(inputs []*AgentInput) ([]*AgentInput, error) {
+	steps, err := parsePreprocessorSpec(a.config.InputPreprocessor)
+	if err != nil {
+		return nil, err
+	}
+	if len(steps) == 0 {
+		return inputs, nil
+	}
+
+	out := make([]*AgentInput, len(inputs))
+	for i, input := range inputs {
+		cp := *input
+		content, err := runPreprocessors(steps, input.Content)
+		if err != nil {
+			log.Printf("Aggregator input preprocessing failed for %s: %v", input.AgentName, err)
+		} else {
+			cp.Content = content
+		}
+		out[i] = &cp
+	}
+	return out, nil
+}
+
 // aggregateByConsensus uses LLM to find consensus among inputs
 func (a *AggregatorAgent) aggregateByConsensus(ctx context.Context, inputs []*AgentInput) (*AggregationResult, error) {
 	// Build prompt for consensus finding
@@ -362,7 +692,7 @@ func (a *AggregatorAgent) aggregateByConsensus(ctx context.Context, inputs []*Ag
 	req := provider.StructuredRequest{
 		CompletionRequest: provider.CompletionRequest{
 			Messages: []provider.Message{
-				{Role: "system", Content: a.getAggregatorSystemPrompt()},
+				{Role: "system", Content: a.systemPrompt(StrategyConsensus, len(inputs), a.getAggregatorSystemPrompt())},
 				{Role: "user", Content: prompt},
 			},
 			Model:       a.def.Model,
@@ -373,20 +703,26 @@ func (a *AggregatorAgent) aggregateByConsensus(ctx context.Context, inputs []*Ag
 		StrictSchema:   true,
 	}
 
+	var result AggregationResult
 	resp, err := a.provider.CreateStructured(ctx, req)
-	if err != nil {
-		return nil, fmt.Errorf("LLM aggregation failed: %w", err)
+	if err == nil {
+		err = json.Unmarshal(resp.Data, &result)
 	}
-
-	// Parse response
-	var result AggregationResult
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse aggregation result: %w", err)
+	if err != nil {
+		// Models with weak JSON support may reject the schema or return
+		// invalid data; retry without it rather than failing the aggregation
+		log.Printf("Structured consensus aggregation failed, retrying unstructured: %v", err)
+		fallback, fallbackErr := a.aggregateByConsensusUnstructured(ctx, req.CompletionRequest)
+		if fallbackErr != nil {
+			return nil, fmt.Errorf("LLM aggregation failed: %w (structured: %v)", fallbackErr, err)
+		}
+		result = *fallback
+	} else {
+		result.TokensUsed = resp.Usage.TotalTokens
 	}
 
 	// Add metadata
 	result.Strategy = StrategyConsensus
-	result.TokensUsed = resp.Usage.TotalTokens
 	result.Sources = a.extractSources(inputs)
 
 	// Calculate consensus level
@@ -395,6 +731,42 @@ func (a *AggregatorAgent) aggregateByConsensus(ctx context.Context, inputs []*Ag
 	return &result, nil
 }
 
+// aggregateByConsensusUnstructured runs the consensus request as a plain
+// completion and parses the answer best-effort: a JSON object in the reply is
+// used if it has aggregated content, otherwise the reply text itself is. The
+// result is marked DegradedParsing.
+func (a *AggregatorAgent) aggregateByConsensusUnstructured(ctx context.Context, req provider.CompletionRequest) (*AggregationResult, error) {
+	req.Messages = append(append([]provider.Message{}, req.Messages...), provider.Message{
+		Role:    "user",
+		Content: `Respond with a JSON object with an "aggregated_content" string field and an optional "conflicts_resolved" array.`,
+	})
+
+	resp, err := a.provider.CreateCompletion(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &AggregationResult{}
+	if obj, err := firstJSONObject(resp.Content); err == nil {
+		if data, err := json.Marshal(obj); err == nil {
+			var parsed AggregationResult
+			if json.Unmarshal(data, &parsed) == nil && parsed.AggregatedContent != "" {
+				result = &parsed
+			}
+		}
+	}
+	if result.AggregatedContent == "" {
+		result = &AggregationResult{AggregatedContent: strings.TrimSpace(resp.Content)}
+	}
+	if result.AggregatedContent == "" {
+		return nil, fmt.Errorf("unstructured aggregation returned no content")
+	}
+
+	result.TokensUsed = resp.Usage.TotalTokens
+	result.DegradedParsing = true
+	return result, nil
+}
+
 // aggregateBySemantic groups inputs by semantic similarity
 func (a *AggregatorAgent) aggregateBySemantic(ctx context.Context, inputs []*AgentInput) (*AggregationResult, error) {
 	// Group inputs into semantic clusters
@@ -405,7 +777,7 @@ func (a *AggregatorAgent) aggregateBySemantic(ctx context.Context, inputs []*Age
 
 	req := provider.CompletionRequest{
 		Messages: []provider.Message{
-			{Role: "system", Content: a.getSemanticSystemPrompt()},
+			{Role: "system", Content: a.systemPrompt(StrategySemantic, len(inputs), a.getSemanticSystemPrompt())},
 			{Role: "user", Content: prompt},
 		},
 		Model:       a.def.Model,
@@ -437,7 +809,7 @@ func (a *AggregatorAgent) aggregateByWeight(ctx context.Context, inputs []*Agent
 
 	req := provider.CompletionRequest{
 		Messages: []provider.Message{
-			{Role: "system", Content: a.getWeightedSystemPrompt()},
+			{Role: "system", Content: a.systemPrompt(StrategyWeighted, len(inputs), a.getWeightedSystemPrompt())},
 			{Role: "user", Content: prompt},
 		},
 		Model:       a.def.Model,
@@ -479,7 +851,7 @@ func (a *AggregatorAgent) aggregateHierarchical(ctx context.Context, inputs []*A
 
 	req := provider.CompletionRequest{
 		Messages: []provider.Message{
-			{Role: "system", Content: a.getHierarchicalSystemPrompt()},
+			{Role: "system", Content: a.systemPrompt(StrategyHierarchical, len(inputs), a.getHierarchicalSystemPrompt())},
 			{Role: "user", Content: finalPrompt},
 		},
 		Model:       a.def.Model,
@@ -515,7 +887,7 @@ Task: Create a unified, coherent response that incorporates insights from all so
 
 	req := provider.CompletionRequest{
 		Messages: []provider.Message{
-			{Role: "system", Content: a.getRAGSystemPrompt()},
+			{Role: "system", Content: a.systemPrompt(StrategyRAG, len(inputs), a.getRAGSystemPrompt())},
 			{Role: "user", Content: prompt},
 		},
 		Model:       a.def.Model,
@@ -554,6 +926,8 @@ func (a *AggregatorAgent) aggregateByVotingMajority(inputs []*AgentInput) (*Aggr
 		Sources:           a.extractSources(inputs),
 		TokensUsed:        0, // No LLM calls
 		SummaryInsights:   result.Explanation,
+		VoteDistribution:  result.Votes,
+		ConflictsSolved:   a.votingConflicts(votingInputs, result),
 	}, nil
 }
 
@@ -572,6 +946,8 @@ func (a *AggregatorAgent) aggregateByVotingUnanimous(inputs []*AgentInput) (*Agg
 		Sources:           a.extractSources(inputs),
 		TokensUsed:        0, // No LLM calls
 		SummaryInsights:   result.Explanation,
+		VoteDistribution:  result.Votes,
+		ConflictsSolved:   a.votingConflicts(votingInputs, result),
 	}, nil
 }
 
@@ -590,6 +966,8 @@ func (a *AggregatorAgent) aggregateByVotingWeighted(inputs []*AgentInput) (*Aggr
 		Sources:           a.extractSources(inputs),
 		TokensUsed:        0, // No LLM calls
 		SummaryInsights:   result.Explanation,
+		VoteDistribution:  result.Votes,
+		ConflictsSolved:   a.votingConflicts(votingInputs, result),
 	}, nil
 }
 
@@ -608,9 +986,33 @@ func (a *AggregatorAgent) aggregateByVotingConfidence(inputs []*AgentInput) (*Ag
 		Sources:           a.extractSources(inputs),
 		TokensUsed:        0, // No LLM calls
 		SummaryInsights:   result.Explanation,
+		VoteDistribution:  result.Votes,
+		ConflictsSolved:   a.votingConflicts(votingInputs, result),
 	}, nil
 }
 
+// votingConflicts records the competing options when inputs split into
+// groups of at least ConflictMinSupport sources, and the rule that chose
+// between them, so zero-LLM aggregations are auditable.
+func (a *AggregatorAgent) votingConflicts(inputs []aggregation.VotingInput, result *aggregation.VotingResult) []ConflictResolution {
+	groups := aggregation.DetectConflict(inputs, a.config.ConflictMinSupport)
+	if groups == nil {
+		return nil
+	}
+
+	conflict := ConflictResolution{
+		Topic:      fmt.Sprintf("%d competing outputs", len(groups)),
+		Resolution: result.SelectedContent,
+		Reasoning:  result.Explanation,
+		Rule:       result.Strategy,
+	}
+	for _, group := range groups {
+		conflict.Options = append(conflict.Options, ConflictOption{Content: group.Content, Sources: group.Sources})
+		conflict.Sources = append(conflict.Sources, group.Sources...)
+	}
+	return []ConflictResolution{conflict}
+}
+
 // convertToVotingInputs converts AgentInput to aggregation.VotingInput
 func (a *AggregatorAgent) convertToVotingInputs(inputs []*AgentInput) []aggregation.VotingInput {
 	result := make([]aggregation.VotingInput, len(inputs))
@@ -693,29 +1095,30 @@ func (a *AggregatorAgent) getRAGSystemPrompt() string {
 
 // Utility methods
 
+// aggregationSchema is built on first use and shared, since it does not
+// depend on the agent's configuration
+var aggregationSchema = sync.OnceValue(newAggregationSchema)
+
 func (a *AggregatorAgent) buildAggregationSchema() json.RawMessage {
-	schema := map[string]any{
-		"type": "object",
-		"properties": map[string]any{
-			"aggregated_content": map[string]any{"type": "string"},
-			"conflicts_resolved": map[string]any{
-				"type": "array",
-				"items": map[string]any{
-					"type": "object",
-					"properties": map[string]any{
-						"topic":      map[string]any{"type": "string"},
-						"resolution": map[string]any{"type": "string"},
-						"reasoning":  map[string]any{"type": "string"},
-					},
-				},
-			},
-			"summary_insights": map[string]any{"type": "string"},
-		},
-		"required": []string{"aggregated_content"},
-	}
+	return aggregationSchema()
+}
+
+func newAggregationSchema() json.RawMessage {
+	return schema.FromType(reflect.TypeOf(consensusResponse{}))
+}
 
-	data, _ := json.Marshal(schema)
-	return data
+// consensusResponse is the structured output the consensus strategy asks
+// for; it decodes into AggregationResult
+type consensusResponse struct {
+	AggregatedContent string              `json:"aggregated_content" validate:"required"`
+	ConflictsSolved   []consensusConflict `json:"conflicts_resolved"`
+	SummaryInsights   string              `json:"summary_insights"`
+}
+
+type consensusConflict struct {
+	Topic      string `json:"topic"`
+	Resolution string `json:"resolution"`
+	Reasoning  string `json:"reasoning"`
 }
 
 func (a *AggregatorAgent) extractSources(inputs []*AgentInput) []string {
@@ -1116,87 +1519,5 @@ func (a *AggregatorAgent) sendResult(result *AggregationResult) {
 // calculateTextSimilarity computes similarity between two text strings
 // using Levenshtein distance normalized to 0-1 range
 func (a *AggregatorAgent) calculateTextSimilarity(text1, text2 string) float64 {
-	if text1 == text2 {
-		return 1.0
-	}
-	if text1 == "" || text2 == "" {
-		return 0.0
-	}
-
-	// Calculate Levenshtein distance
-	distance := levenshteinDistance(text1, text2)
-	maxLen := maxOf2(len(text1), len(text2))
-	if maxLen == 0 {
-		return 1.0
-	}
-
-	// Normalize to 0-1 range (1 = identical, 0 = completely different)
-	similarity := 1.0 - float64(distance)/float64(maxLen)
-	if similarity < 0 {
-		similarity = 0
-	}
-	return similarity
-}
-
-// levenshteinDistance calculates the edit distance between two strings
-func levenshteinDistance(s1, s2 string) int {
-	if len(s1) == 0 {
-		return len(s2)
-	}
-	if len(s2) == 0 {
-		return len(s1)
-	}
-
-	// Create matrix
-	matrix := make([][]int, len(s1)+1)
-	for i := range matrix {
-		matrix[i] = make([]int, len(s2)+1)
-	}
-
-	// Initialize first column and row
-	for i := 0; i <= len(s1); i++ {
-		matrix[i][0] = i
-	}
-	for j := 0; j <= len(s2); j++ {
-		matrix[0][j] = j
-	}
-
-	// Fill matrix
-	for i := 1; i <= len(s1); i++ {
-		for j := 1; j <= len(s2); j++ {
-			cost := 1
-			if s1[i-1] == s2[j-1] {
-				cost = 0
-			}
-			matrix[i][j] = minOf3(
-				matrix[i-1][j]+1,      // deletion
-				matrix[i][j-1]+1,      // insertion
-				matrix[i-1][j-1]+cost, // substitution
-			)
-		}
-	}
-
-	return matrix[len(s1)][len(s2)]
-}
-
-// minOf3 returns the minimum of three integers
-func minOf3(a, b, c int) int {
-	if a < b {
-		if a < c {
-			return a
-		}
-		return c
-	}
-	if b < c {
-		return b
-	}
-	return c
-}
-
-// maxOf2 returns the maximum of two integers
-func maxOf2(a, b int) int {
-	if a > b {
-		return a
-	}
-	return b
+	return aggregation.TextSimilarity(text1, text2)
 }
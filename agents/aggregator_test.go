@@ -3,6 +3,8 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
+	"fmt"
 	"testing"
 	"time"
 
@@ -279,6 +281,85 @@ func TestAggregatorSchemaBuilding(t *testing.T) {
 	assert.Contains(t, required, "aggregated_content")
 }
 
+func TestAggregatorConsensusUnstructuredFallback(t *testing.T) {
+	ctx := context.Background()
+	inputs := []*AgentInput{
+		{AgentName: "agent1", Content: "Solution A is optimal", Confidence: 0.8},
+		{AgentName: "agent2", Content: "Solution A with minor changes", Confidence: 0.7},
+	}
+
+	tests := []struct {
+		name       string
+		structured *provider.StructuredResponse
+		structErr  error
+		completion string
+		expected   string
+		conflicts  int
+	}{
+		{
+			name:       "schema rejected, JSON in prose",
+			structErr:  errors.New("response_format not supported"),
+			completion: "Here you go:\n```json\n{\"aggregated_content\": \"Solution A\", \"conflicts_resolved\": [{\"topic\": \"choice\"}]}\n```",
+			expected:   "Solution A",
+			conflicts:  1,
+		},
+		{
+			name:       "invalid structured data, plain text reply",
+			structured: &provider.StructuredResponse{Data: []byte("not json")},
+			completion: "  Both agents prefer Solution A.  ",
+			expected:   "Both agents prefer Solution A.",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mockProvider := new(MockProvider)
+			aggAgent := &AggregatorAgent{
+				provider:    mockProvider,
+				config:      AggregatorConfig{AggregationStrategy: StrategyConsensus},
+				inputBuffer: make(map[string]*AgentInput),
+			}
+
+			mockProvider.On("CreateStructured", ctx, mock.Anything).Return(tt.structured, tt.structErr).Once()
+			mockProvider.On("CreateCompletion", ctx, mock.Anything).Return(&provider.CompletionResponse{
+				Content: tt.completion,
+				Usage:   provider.Usage{TotalTokens: 42},
+			}, nil).Once()
+
+			result, err := aggAgent.aggregate(ctx, inputs)
+			require.NoError(t, err)
+			assert.True(t, result.DegradedParsing)
+			assert.Equal(t, tt.expected, result.AggregatedContent)
+			assert.Equal(t, StrategyConsensus, result.Strategy)
+			assert.Equal(t, 42, result.TokensUsed)
+			assert.Len(t, result.ConflictsSolved, tt.conflicts)
+			assert.Equal(t, []string{"agent1", "agent2"}, result.Sources)
+
+			data, err := json.Marshal(result)
+			require.NoError(t, err)
+			assert.Contains(t, string(data), `"degraded_parsing":true`)
+			mockProvider.AssertExpectations(t)
+		})
+	}
+
+	t.Run("both attempts fail", func(t *testing.T) {
+		mockProvider := new(MockProvider)
+		aggAgent := &AggregatorAgent{
+			provider:    mockProvider,
+			config:      AggregatorConfig{AggregationStrategy: StrategyConsensus},
+			inputBuffer: make(map[string]*AgentInput),
+		}
+
+		mockProvider.On("CreateStructured", ctx, mock.Anything).Return(nil, errors.New("schema error")).Once()
+		mockProvider.On("CreateCompletion", ctx, mock.Anything).Return(nil, errors.New("rate limited")).Once()
+
+		_, err := aggAgent.aggregate(ctx, inputs)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "rate limited")
+		assert.Contains(t, err.Error(), "schema error")
+	})
+}
+
 func TestAggregatorConsensusCalculation(t *testing.T) {
 	aggAgent := &AggregatorAgent{
 		config: AggregatorConfig{
@@ -456,6 +537,47 @@ func TestAggregator_VotingMajority(t *testing.T) {
 	})
 }
 
+// TestAggregator_VotingConflicts tests deterministic conflict records
+func TestAggregator_VotingConflicts(t *testing.T) {
+	ctx := context.Background()
+	aggAgent := &AggregatorAgent{
+		config:      AggregatorConfig{ConflictMinSupport: 2},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+	inputs := []*AgentInput{
+		{AgentName: "agent1", Content: "Option X", Confidence: 0.7},
+		{AgentName: "agent2", Content: "Option Y", Confidence: 0.95},
+		{AgentName: "agent3", Content: "Option X", Confidence: 0.8},
+		{AgentName: "agent4", Content: "Option Y", Confidence: 0.9},
+	}
+
+	for _, strategy := range []string{StrategyVotingMajority, StrategyVotingWeighted, StrategyVotingConfidence} {
+		t.Run(strategy, func(t *testing.T) {
+			aggAgent.config.AggregationStrategy = strategy
+			result, err := aggAgent.aggregate(ctx, inputs)
+			require.NoError(t, err)
+			require.Len(t, result.ConflictsSolved, 1)
+
+			conflict := result.ConflictsSolved[0]
+			assert.Equal(t, []ConflictOption{
+				{Content: "Option X", Sources: []string{"agent1", "agent3"}},
+				{Content: "Option Y", Sources: []string{"agent2", "agent4"}},
+			}, conflict.Options)
+			assert.ElementsMatch(t, []string{"agent1", "agent2", "agent3", "agent4"}, conflict.Sources)
+			assert.Equal(t, result.AggregatedContent, conflict.Resolution)
+			assert.NotEmpty(t, conflict.Rule)
+			assert.NotEmpty(t, conflict.Reasoning)
+		})
+	}
+
+	t.Run("lone_dissenter_below_threshold", func(t *testing.T) {
+		aggAgent.config.AggregationStrategy = StrategyVotingMajority
+		result, err := aggAgent.aggregate(ctx, inputs[:3])
+		require.NoError(t, err)
+		assert.Empty(t, result.ConflictsSolved)
+	})
+}
+
 // TestAggregator_VotingUnanimous tests the voting_unanimous deterministic strategy
 func TestAggregator_VotingUnanimous(t *testing.T) {
 	ctx := context.Background()
@@ -1003,3 +1125,262 @@ func TestAggregator_DefaultStrategy(t *testing.T) {
 	require.NoError(t, err)
 	assert.Greater(t, result.TokensUsed, 0, "Default strategy should use LLM")
 }
+
+func TestAggregatorSourceAllowlist(t *testing.T) {
+	tests := []struct {
+		name     string
+		config   AggregatorConfig
+		channel  string
+		expected string
+		accepted bool
+	}{
+		{
+			name:     "input source",
+			config:   AggregatorConfig{UnexpectedSources: UnexpectedSourcesReject},
+			channel:  "agent1",
+			expected: "agent1",
+			accepted: true,
+		},
+		{
+			name:     "undeclared input source is rejected by default",
+			config:   AggregatorConfig{UnexpectedSources: UnexpectedSourcesReject},
+			channel:  "stray",
+			accepted: false,
+		},
+		{
+			name:     "approved source need not be an agent",
+			config:   AggregatorConfig{AllowedSources: []string{"agent1", "stray"}, UnexpectedSources: UnexpectedSourcesReject},
+			channel:  "stray",
+			expected: "stray",
+			accepted: true,
+		},
+		{
+			name:     "explicit allowlist narrows inputs",
+			config:   AggregatorConfig{AllowedSources: []string{"agent2"}, UnexpectedSources: UnexpectedSourcesReject},
+			channel:  "agent1",
+			accepted: false,
+		},
+		{
+			name:     "reject policy drops unexpected source",
+			config:   AggregatorConfig{AllowedSources: []string{"agent2"}, UnexpectedSources: UnexpectedSourcesReject},
+			channel:  "intruder",
+			accepted: false,
+		},
+		{
+			name:     "log policy accepts unexpected source",
+			config:   AggregatorConfig{AllowedSources: []string{"agent2"}, UnexpectedSources: UnexpectedSourcesLog},
+			channel:  "agent1",
+			expected: "agent1",
+			accepted: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// stray is configured as an input but is not a declared agent
+			rt := NewMockRuntime()
+			rt.agents = []string{"agg", "agent1", "agent2"}
+			aggAgent := &AggregatorAgent{
+				def: agent.AgentDef{
+					Inputs: []agent.Input{{Source: "agent1"}, {Source: "agent2"}, {Source: "stray"}},
+				},
+				config: tt.config,
+				rt:     rt,
+			}
+
+			source, ok := aggAgent.checkSource(aggAgent.allowedSources(), tt.channel)
+			assert.Equal(t, tt.accepted, ok)
+			assert.Equal(t, tt.expected, source)
+		})
+	}
+}
+
+func TestAggregatorExecutePassesErrorMessages(t *testing.T) {
+	mockProvider := provider.NewMockProvider("test")
+	base := NewBaseAgent(agent.AgentDef{Name: "agg"})
+	base.SetReady(true)
+	aggAgent := &AggregatorAgent{
+		BaseAgent:   base,
+		provider:    mockProvider,
+		config:      AggregatorConfig{AggregationStrategy: "consensus"},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+
+	failure := agent.NewErrorMessage("researcher", errors.New("rate limited"))
+	result, err := aggAgent.Execute(context.Background(), failure)
+	require.NoError(t, err)
+	assert.Same(t, failure, result)
+	assert.Empty(t, mockProvider.CompletionCalls)
+}
+
+func TestAggregatorQuorumConfidence(t *testing.T) {
+	now := time.Now()
+	inputs := func(confidences ...float64) []*AgentInput {
+		out := make([]*AgentInput, len(confidences))
+		for i, c := range confidences {
+			out[i] = &AgentInput{AgentName: fmt.Sprintf("agent%d", i), Content: "yes", Confidence: c, Timestamp: now}
+		}
+		return out
+	}
+
+	aggAgent := &AggregatorAgent{config: AggregatorConfig{QuorumConfidence: 2.5}}
+
+	// Three confident agents outweigh five unsure ones
+	assert.InDelta(t, 2.7, aggAgent.quorumConfidence(inputs(0.9, 0.9, 0.9), now), 1e-9)
+	assert.InDelta(t, 1.5, aggAgent.quorumConfidence(inputs(0.3, 0.3, 0.3, 0.3, 0.3), now), 1e-9)
+
+	// Out-of-range confidences are clamped
+	assert.InDelta(t, 1.0, aggAgent.quorumConfidence(inputs(5, -1), now), 1e-9)
+
+	// With a half-life, an input one half-life old counts half
+	aggAgent.config.QuorumHalfLifeMs = 1000
+	stale := inputs(0.8)
+	stale[0].Timestamp = now.Add(-time.Second)
+	assert.InDelta(t, 0.4, aggAgent.quorumConfidence(stale, now), 1e-9)
+}
+
+func TestAggregatorQuorumGatesWindow(t *testing.T) {
+	rt := NewMockRuntime()
+	rt.On("Send", "out", mock.Anything).Return(nil)
+	out := make(chan *agent.Message, 1)
+	rt.channels["out"] = out
+
+	aggAgent := &AggregatorAgent{
+		def:         agent.AgentDef{Name: "agg", Outputs: []agent.Output{{Target: "out"}}},
+		config:      AggregatorConfig{AggregationStrategy: StrategyVotingMajority, QuorumConfidence: 1.5},
+		rt:          rt,
+		inputBuffer: make(map[string]*AgentInput),
+	}
+	ctx := context.Background()
+
+	// Below quorum: nothing is sent and inputs stay buffered
+	aggAgent.bufferInput("agent1", &agent.Message{Message: &pb.Message{Payload: `{"answer": "yes", "confidence": 0.9}`}})
+	aggAgent.processAggregation(ctx)
+	assert.Empty(t, out)
+	assert.True(t, aggAgent.hasBufferedInputs())
+
+	// A second confident input meets quorum
+	aggAgent.bufferInput("agent2", &agent.Message{Message: &pb.Message{Payload: `{"answer": "yes", "confidence": 0.8}`}})
+	aggAgent.processAggregation(ctx)
+	require.Len(t, out, 1)
+	assert.False(t, aggAgent.hasBufferedInputs())
+
+	var result AggregationResult
+	require.NoError(t, json.Unmarshal([]byte((<-out).Payload), &result))
+	assert.InDelta(t, 1.7, result.QuorumConfidence, 1e-6)
+	assert.False(t, result.Degraded)
+
+	// Inputs that wait past the quorum timeout are aggregated, degraded
+	aggAgent.config.QuorumTimeoutMs = 1000
+	aggAgent.bufferInput("agent1", &agent.Message{Message: &pb.Message{Payload: `{"answer": "yes", "confidence": 0.9}`}})
+	aggAgent.processAggregation(ctx)
+	assert.Empty(t, out)
+	aggAgent.inputBuffer["agent1"].Timestamp = time.Now().Add(-2 * time.Second)
+	require.True(t, aggAgent.processAggregation(ctx))
+	require.Len(t, out, 1)
+	require.NoError(t, json.Unmarshal([]byte((<-out).Payload), &result))
+	assert.True(t, result.Degraded)
+}
+
+func TestAggregatorExecuteGatesOnQuorum(t *testing.T) {
+	base := NewBaseAgent(agent.AgentDef{Name: "agg"})
+	base.SetReady(true)
+	aggAgent := &AggregatorAgent{
+		BaseAgent:   base,
+		config:      AggregatorConfig{AggregationStrategy: StrategyVotingMajority, QuorumConfidence: 0.8},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+	ctx := context.Background()
+
+	_, err := aggAgent.Execute(ctx, &agent.Message{Message: &pb.Message{Payload: `{"answer": "yes", "confidence": 0.5}`}})
+	assert.ErrorIs(t, err, ErrQuorumNotMet)
+
+	result, err := aggAgent.Execute(ctx, &agent.Message{Message: &pb.Message{Payload: `{"answer": "yes", "confidence": 0.9}`}})
+	require.NoError(t, err)
+	assert.Equal(t, "aggregation_result", result.Type)
+}
+
+func TestAggregatorMinInputSources(t *testing.T) {
+	run := func(t *testing.T, config AggregatorConfig, wait time.Duration, votes ...string) (AggregationResult, bool) {
+		t.Helper()
+		rt := NewMockRuntime()
+		rt.On("Send", "out", mock.Anything).Return(nil)
+		out := make(chan *agent.Message, 1)
+		rt.channels["out"] = out
+
+		var inputs []agent.Input
+		for i, vote := range votes {
+			source := fmt.Sprintf("agent%d", i)
+			ch := make(chan *agent.Message, 1)
+			ch <- &agent.Message{Message: &pb.Message{Payload: vote}}
+			rt.On("Recv", source).Return((<-chan *agent.Message)(ch), nil)
+			inputs = append(inputs, agent.Input{Source: source})
+			rt.agents = append(rt.agents, source)
+		}
+		// A source that never reports
+		rt.On("Recv", "silent").Return((<-chan *agent.Message)(make(chan *agent.Message)), nil)
+		inputs = append(inputs, agent.Input{Source: "silent"})
+		rt.agents = append(rt.agents, "silent")
+
+		aggAgent := &AggregatorAgent{
+			BaseAgent: NewBaseAgent(agent.AgentDef{Name: "agg"}),
+			def:       agent.AgentDef{Name: "agg", Inputs: inputs, Outputs: []agent.Output{{Target: "out"}}},
+			config:      config,
+			rt:          rt,
+			inputBuffer: make(map[string]*AgentInput),
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		go func() { _ = aggAgent.Start(ctx) }()
+
+		select {
+		case msg := <-out:
+			var result AggregationResult
+			require.NoError(t, json.Unmarshal([]byte(msg.Payload), &result))
+			return result, true
+		case <-time.After(wait):
+			return AggregationResult{}, false
+		}
+	}
+
+	t.Run("satisfied", func(t *testing.T) {
+		// The window would last a minute, but two sources close it early
+		config := AggregatorConfig{AggregationStrategy: StrategyVotingMajority, TimeoutMs: 60000, MinInputSources: 2}
+		result, ok := run(t, config, 5*time.Second, "yes", "yes")
+		require.True(t, ok, "no aggregation result")
+		assert.False(t, result.Degraded)
+		assert.Len(t, result.Sources, 2)
+	})
+
+	t.Run("degraded", func(t *testing.T) {
+		config := AggregatorConfig{AggregationStrategy: StrategyVotingMajority, TimeoutMs: 50, MinInputSources: 3}
+		result, ok := run(t, config, 5*time.Second, "yes")
+		require.True(t, ok, "no aggregation result")
+		assert.True(t, result.Degraded)
+		assert.Equal(t, []string{"agent0"}, result.Sources)
+		assert.Equal(t, "yes", result.AggregatedContent)
+	})
+
+	t.Run("early close waits for quorum", func(t *testing.T) {
+		config := AggregatorConfig{
+			AggregationStrategy: StrategyVotingMajority,
+			TimeoutMs:           60000,
+			MinInputSources:     2,
+			QuorumConfidence:    1.5,
+		}
+		_, ok := run(t, config, 200*time.Millisecond,
+			`{"answer": "yes", "confidence": 0.3}`, `{"answer": "yes", "confidence": 0.3}`)
+		assert.False(t, ok, "window closed early without quorum")
+	})
+
+	t.Run("more than the inputs is rejected", func(t *testing.T) {
+		def := agent.AgentDef{
+			Name:   "agg",
+			Inputs: []agent.Input{{Source: "a"}, {Source: "b"}},
+			Extra:  map[string]any{"aggregator_config": map[string]any{"MinInputSources": 3}},
+		}
+		_, err := NewAggregatorAgent(def, NewMockRuntime())
+		assert.ErrorContains(t, err, "min_input_sources")
+	})
+}
@@ -0,0 +1,66 @@
+package agents
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/observability"
+	"github.com/aixgo-dev/aixgo/internal/orchestration"
+	"github.com/aixgo-dev/aixgo/internal/runtime"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+// retainingExporter keeps its spans on shutdown so they can be inspected
+// after the tracer provider flushes
+type retainingExporter struct {
+	*tracetest.InMemoryExporter
+}
+
+func (retainingExporter) Shutdown(context.Context) error { return nil }
+
+func TestAggregatorTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	require.NoError(t, observability.Init(observability.Config{Enabled: true, Exporter: retainingExporter{exporter}}))
+
+	def := agent.AgentDef{Name: "aggregator", Role: "aggregator", Model: "gpt-4"}
+	aggAgent := &AggregatorAgent{
+		BaseAgent:   NewBaseAgent(def),
+		def:         def,
+		provider:    new(MockProvider),
+		config:      AggregatorConfig{AggregationStrategy: StrategyVotingMajority},
+		rt:          NewMockRuntime(),
+		inputBuffer: make(map[string]*AgentInput),
+	}
+
+	rt := runtime.NewLocalRuntime()
+	require.NoError(t, rt.Register(aggAgent))
+	require.NoError(t, rt.Start(context.Background()))
+
+	pipeline := orchestration.NewSequential("review", rt, []string{"aggregator"})
+	_, err := pipeline.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "approve"}})
+	require.NoError(t, err)
+	require.NoError(t, observability.Shutdown(context.Background()))
+
+	spans := exporter.GetSpans()
+	byName := make(map[string]tracetest.SpanStub, len(spans))
+	for _, s := range spans {
+		byName[s.Name] = s
+	}
+	root, ok := byName["orchestration.sequential.review"]
+	require.True(t, ok, "missing orchestrator span in %v", spans)
+	call, ok := byName["runtime.call.aggregator"]
+	require.True(t, ok, "missing runtime call span")
+	agg, ok := byName["aggregator.aggregate"]
+	require.True(t, ok, "missing aggregator span")
+
+	assert.Equal(t, root.SpanContext.SpanID(), call.Parent.SpanID())
+	assert.Equal(t, call.SpanContext.SpanID(), agg.Parent.SpanID())
+	assert.Equal(t, root.SpanContext.TraceID(), agg.SpanContext.TraceID())
+	assert.Contains(t, agg.Attributes, attribute.Int("input_count", 1))
+	assert.Contains(t, agg.Attributes, attribute.String("strategy", StrategyVotingMajority))
+}
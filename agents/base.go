@@ -13,6 +13,7 @@ type BaseAgent struct {
 	name   string
 	role   string
 	ready  bool
+	probe  *ReadinessProbe
 	mu     sync.RWMutex
 	ctx    context.Context
 	cancel context.CancelFunc
@@ -41,11 +42,30 @@ func (b *BaseAgent) Role() string {
 	return b.role
 }
 
-// Ready returns whether the agent is ready
+// Ready returns whether the agent is ready. If a readiness probe is set,
+// the agent is only ready while its dependencies pass the probe.
 func (b *BaseAgent) Ready() bool {
+	b.mu.RLock()
+	ready, probe := b.ready, b.probe
+	b.mu.RUnlock()
+	if !ready {
+		return false
+	}
+	return probe == nil || probe.Ready()
+}
+
+// SetReadinessProbe attaches a dependency probe consulted by Ready
+func (b *BaseAgent) SetReadinessProbe(probe *ReadinessProbe) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probe = probe
+}
+
+// ReadinessProbe returns the attached readiness probe, or nil
+func (b *BaseAgent) ReadinessProbe() *ReadinessProbe {
 	b.mu.RLock()
 	defer b.mu.RUnlock()
-	return b.ready
+	return b.probe
 }
 
 // SetReady sets the ready state
@@ -5,9 +5,14 @@ import (
 	"encoding/json"
 	"fmt"
 	"log"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/aggregation"
+	"github.com/aixgo-dev/aixgo/internal/llm"
 	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 	"github.com/aixgo-dev/aixgo/internal/observability"
 	"github.com/aixgo-dev/aixgo/pkg/security"
@@ -23,8 +28,30 @@ type ClassifierConfig struct {
 	FewShotExamples     []Example  `yaml:"few_shot_examples"`
 	Temperature         float64    `yaml:"temperature"`
 	MaxTokens           int        `yaml:"max_tokens"`
+	MaxRetries          int        `yaml:"max_retries"` // Attempts for malformed or invalid output (default 3)
+
+	// Out-of-set labels map to the most similar category, or to
+	// UnknownLabel (when set) if similarity is below LabelMatchThreshold
+	// (default 0.5)
+	UnknownLabel        string  `yaml:"unknown_label"`
+	LabelMatchThreshold float64 `yaml:"label_match_threshold"`
 }
 
+// defaultLabelMatchThreshold is the LabelMatchThreshold used when none is set
+const defaultLabelMatchThreshold = 0.5
+
+// Label fallbacks applied when the model answers outside the category set
+const (
+	LabelFallbackNearest = "nearest"
+	LabelFallbackUnknown = "unknown"
+)
+
+// Metadata keys set on classification messages whose label was normalized
+const (
+	MetadataKeyRawCategory   = "raw_category"
+	MetadataKeyLabelFallback = "label_fallback"
+)
+
 // Category represents a classification category with metadata for better LLM understanding
 type Category struct {
 	Name        string   `yaml:"name"`
@@ -42,12 +69,14 @@ type Example struct {
 
 // ClassificationResult with AI-specific metrics
 type ClassificationResult struct {
-	Category       string             `json:"category"`
-	Confidence     float64            `json:"confidence"`
+	Category       string             `json:"category" validate:"required"`
+	Confidence     float64            `json:"confidence" validate:"gte=0,lte=1"`
 	Reasoning      string             `json:"reasoning"`
 	Alternatives   []AlternativeClass `json:"alternatives,omitempty"`
 	TokensUsed     int                `json:"tokens_used"`
 	PromptStrategy string             `json:"prompt_strategy"`
+	RawCategory    string             `json:"raw_category,omitempty"`
+	LabelFallback  string             `json:"label_fallback,omitempty"`
 }
 
 type AlternativeClass struct {
@@ -66,9 +95,12 @@ type ClassifierAgent struct {
 	promptCache     map[string]string
 	categoryEmbeds  map[string][]float64
 	performanceData []ClassificationMetrics
+	schemaOnce      sync.Once
+	schema          json.RawMessage
 
 	// State management
 	ready  bool
+	probe  atomic.Pointer[ReadinessProbe]
 	ctx    context.Context
 	cancel context.CancelFunc
 }
@@ -104,6 +136,9 @@ func NewClassifierAgent(def agent.AgentDef, rt agent.Runtime) (agent.Agent, erro
 	if config.ConfidenceThreshold == 0 {
 		config.ConfidenceThreshold = 0.7
 	}
+	if config.LabelMatchThreshold == 0 {
+		config.LabelMatchThreshold = defaultLabelMatchThreshold
+	}
 
 	// Initialize provider based on model
 	prov, err := initializeProvider(def.Model)
@@ -111,16 +146,23 @@ func NewClassifierAgent(def agent.AgentDef, rt agent.Runtime) (agent.Agent, erro
 		return nil, fmt.Errorf("failed to initialize LLM provider: %w", err)
 	}
 
-	return &ClassifierAgent{
+	probe, err := readinessProbeFromDef(def, ProviderReadinessCheck(prov))
+	if err != nil {
+		return nil, err
+	}
+
+	c := &ClassifierAgent{
 		def:             def,
-		provider:        prov,
+		provider:        withTokenMetrics(def.Name, prov),
 		config:          config,
 		rt:              rt,
 		promptCache:     make(map[string]string),
 		categoryEmbeds:  make(map[string][]float64),
 		performanceData: make([]ClassificationMetrics, 0, 1000),
 		ready:           true,
-	}, nil
+	}
+	c.probe.Store(probe)
+	return c, nil
 }
 
 // Name returns the agent name
@@ -133,9 +175,19 @@ func (c *ClassifierAgent) Role() string {
 	return c.def.Role
 }
 
-// Ready returns whether the agent is ready
+// Ready returns whether the agent is ready and, if a readiness probe is
+// set, whether its provider is reachable
 func (c *ClassifierAgent) Ready() bool {
-	return c.ready
+	if !c.ready {
+		return false
+	}
+	probe := c.probe.Load()
+	return probe == nil || probe.Ready()
+}
+
+// SetReadinessProbe attaches a dependency probe consulted by Ready
+func (c *ClassifierAgent) SetReadinessProbe(probe *ReadinessProbe) {
+	c.probe.Store(probe)
 }
 
 // Stop gracefully stops the agent
@@ -186,6 +238,7 @@ func (c *ClassifierAgent) Execute(ctx context.Context, input *agent.Message) (*a
 		Type:      "classification",
 		Payload:   string(resultJSON),
 		Timestamp: time.Now().Format(time.RFC3339),
+		Metadata:  result.fallbackMetadata(),
 	}}, nil
 }
 
@@ -250,37 +303,28 @@ func (c *ClassifierAgent) classify(ctx context.Context, input string) (*Classifi
 	// Build optimized prompt using Chain-of-Thought for reasoning
 	prompt := c.buildClassificationPrompt(input)
 
-	// Prepare structured request for better LLM output
-	schema := c.buildResponseSchema()
-
-	req := provider.StructuredRequest{
-		CompletionRequest: provider.CompletionRequest{
-			Messages: []provider.Message{
-				{Role: "system", Content: c.getSystemPrompt()},
-				{Role: "user", Content: prompt},
-			},
-			Model:       c.def.Model,
-			Temperature: c.config.Temperature,
-			MaxTokens:   c.config.MaxTokens,
-		},
-		ResponseSchema: schema,
-		StrictSchema:   true,
-	}
-
-	// Call LLM with structured output
-	resp, err := c.provider.CreateStructured(ctx, req)
+	// Call LLM with structured output; malformed or invalid responses are
+	// repaired or retried with validation feedback
+	usage := &usageRecorder{Provider: c.provider}
+	client := llm.NewClient(usage, llm.ClientConfig{
+		DefaultModel:     c.def.Model,
+		MaxRetries:       c.config.MaxRetries,
+		StrictValidation: true,
+	})
+	result, err := llm.CreateStructured[ClassificationResult](ctx, client, prompt, &llm.CreateOptions{
+		SystemPrompt: c.getSystemPrompt(),
+		Temperature:  c.config.Temperature,
+		MaxTokens:    c.config.MaxTokens,
+		Schema:       c.responseSchema(),
+	})
 	if err != nil {
 		return nil, fmt.Errorf("LLM classification failed: %w", err)
 	}
 
-	// Parse structured response
-	var result ClassificationResult
-	if err := json.Unmarshal(resp.Data, &result); err != nil {
-		return nil, fmt.Errorf("failed to parse classification result: %w", err)
-	}
+	c.normalizeCategory(result)
 
 	// Add AI metrics
-	result.TokensUsed = resp.Usage.TotalTokens
+	result.TokensUsed = usage.total
 	result.PromptStrategy = c.getPromptStrategy()
 
 	// Record performance metrics for optimization
@@ -288,12 +332,27 @@ func (c *ClassifierAgent) classify(ctx context.Context, input string) (*Classifi
 		Timestamp:       startTime,
 		InputLength:     len(input),
 		ResponseLatency: time.Since(startTime),
-		TokensUsed:      resp.Usage.TotalTokens,
+		TokensUsed:      usage.total,
 		Confidence:      result.Confidence,
 		Success:         result.Confidence >= c.config.ConfidenceThreshold,
 	})
 
-	return &result, nil
+	return result, nil
+}
+
+// usageRecorder sums token usage across the structured calls of one
+// classification, including validation retries
+type usageRecorder struct {
+	provider.Provider
+	total int
+}
+
+func (u *usageRecorder) CreateStructured(ctx context.Context, req provider.StructuredRequest) (*provider.StructuredResponse, error) {
+	resp, err := u.Provider.CreateStructured(ctx, req)
+	if resp != nil {
+		u.total += resp.Usage.TotalTokens
+	}
+	return resp, err
 }
 
 // buildClassificationPrompt creates an optimized prompt with few-shot examples
@@ -378,6 +437,15 @@ func (c *ClassifierAgent) formatFewShotExamples() string {
 	return result
 }
 
+// responseSchema returns the response schema, building it on first use.
+// Categories are fixed after construction, so every call can share it.
+func (c *ClassifierAgent) responseSchema() json.RawMessage {
+	c.schemaOnce.Do(func() {
+		c.schema = c.buildResponseSchema()
+	})
+	return c.schema
+}
+
 // buildResponseSchema creates JSON schema for structured output
 func (c *ClassifierAgent) buildResponseSchema() json.RawMessage {
 	schema := map[string]any{
@@ -422,6 +490,78 @@ func (c *ClassifierAgent) getCategoryNames() []string {
 	return names
 }
 
+// normalizeCategory maps a label outside the category set, or several
+// labels in a single-label answer, onto one allowed label. The most similar
+// (candidate, category) pair wins, earlier ones on ties; matches below
+// LabelMatchThreshold go to UnknownLabel when configured. Confidence is
+// scaled by the match similarity and split across multiple labels, and the
+// model's answer is kept in RawCategory. Similarity is bounded, so an
+// oversized answer cannot make matching quadratic.
+func (c *ClassifierAgent) normalizeCategory(result *ClassificationResult) {
+	if len(c.config.Categories) == 0 {
+		return
+	}
+	threshold := c.config.LabelMatchThreshold
+	if threshold <= 0 {
+		threshold = defaultLabelMatchThreshold
+	}
+
+	allowed := c.getCategoryNames()
+	if c.config.UnknownLabel != "" {
+		allowed = append(allowed, c.config.UnknownLabel)
+	}
+	raw := strings.TrimSpace(result.Category)
+	for _, name := range allowed {
+		if strings.EqualFold(raw, name) {
+			result.Category = name
+			return
+		}
+	}
+
+	candidates := strings.FieldsFunc(strings.ToLower(raw), func(r rune) bool {
+		return r == ',' || r == ';' || r == '|' || r == '/' || r == '\n'
+	})
+	best, bestScore := "", -1.0
+	for _, candidate := range candidates {
+		candidate = strings.TrimSpace(candidate)
+		for _, name := range c.getCategoryNames() {
+			if score := aggregation.BoundedTextSimilarity(candidate, strings.ToLower(name)); score > bestScore {
+				best, bestScore = name, score
+			}
+		}
+	}
+	if bestScore < 0 {
+		bestScore = 0
+	}
+
+	result.RawCategory = result.Category
+	result.Confidence *= bestScore
+	if labels := len(candidates); labels > 1 {
+		result.Confidence /= float64(labels)
+	}
+
+	if best == "" || (bestScore < threshold && c.config.UnknownLabel != "") {
+		result.Category = c.config.UnknownLabel
+		result.LabelFallback = LabelFallbackUnknown
+	} else {
+		result.Category = best
+		result.LabelFallback = LabelFallbackNearest
+	}
+	log.Printf("Classifier mapped out-of-set label %q to %q (%s)", result.RawCategory, result.Category, result.LabelFallback)
+}
+
+// fallbackMetadata returns message metadata describing label normalization,
+// or nil when the model's label was used as is
+func (r *ClassificationResult) fallbackMetadata() map[string]interface{} {
+	if r.LabelFallback == "" {
+		return nil
+	}
+	return map[string]interface{}{
+		MetadataKeyRawCategory:   r.RawCategory,
+		MetadataKeyLabelFallback: r.LabelFallback,
+	}
+}
+
 // getPromptStrategy returns the current prompting strategy
 func (c *ClassifierAgent) getPromptStrategy() string {
 	if len(c.config.FewShotExamples) > 0 {
@@ -478,6 +618,7 @@ func (c *ClassifierAgent) sendResult(result *ClassificationResult, originalMsg *
 		Type:      "classification",
 		Payload:   string(resultJSON),
 		Timestamp: time.Now().Format(time.RFC3339),
+		Metadata:  result.fallbackMetadata(),
 	}}
 
 	for _, o := range c.def.Outputs {
@@ -3,13 +3,16 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	pb "github.com/aixgo-dev/aixgo/proto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
 )
 
 // MockProvider for testing LLM interactions
@@ -57,6 +60,7 @@ func (m *MockProvider) ListModels(ctx context.Context) ([]provider.ModelInfo, er
 type MockRuntime struct {
 	mock.Mock
 	channels map[string]chan *agent.Message
+	agents   []string // reported by List
 }
 
 func NewMockRuntime() *MockRuntime {
@@ -114,7 +118,7 @@ func (m *MockRuntime) Get(name string) (agent.Agent, error) {
 }
 
 func (m *MockRuntime) List() []string {
-	return []string{}
+	return append([]string{}, m.agents...)
 }
 
 func (m *MockRuntime) Start(ctx context.Context) error {
@@ -185,7 +189,9 @@ func TestClassifierAgentClassify(t *testing.T) {
 
 	resultJSON, _ := json.Marshal(mockResult)
 
-	mockProvider.On("CreateStructured", ctx, mock.Anything).Return(&provider.StructuredResponse{
+	// The classifier always requests a strict schema
+	strict := mock.MatchedBy(func(req provider.StructuredRequest) bool { return req.StrictSchema })
+	mockProvider.On("CreateStructured", ctx, strict).Return(&provider.StructuredResponse{
 		Data: resultJSON,
 		CompletionResponse: provider.CompletionResponse{
 			Usage: provider.Usage{
@@ -277,6 +283,11 @@ func TestClassifierResponseSchema(t *testing.T) {
 	enum := categoryProp["enum"].([]any)
 	assert.Contains(t, enum, "category1")
 	assert.Contains(t, enum, "category2")
+
+	// The schema used for requests is built once and reused
+	first := classifierAgent.responseSchema()
+	assert.JSONEq(t, string(schemaJSON), string(first))
+	assert.Same(t, &first[0], &classifierAgent.responseSchema()[0])
 }
 
 func TestClassifierPerformanceTracking(t *testing.T) {
@@ -366,3 +377,150 @@ func TestClassifierSystemPrompt(t *testing.T) {
 	assert.Contains(t, prompt2, "Custom context for classification")
 	assert.Contains(t, prompt2, "expert classification AI")
 }
+
+func TestClassifierLabelFallback(t *testing.T) {
+	categories := []Category{{Name: "billing"}, {Name: "technical"}, {Name: "account"}}
+
+	tests := []struct {
+		name           string
+		unknownLabel   string
+		modelCategory  string
+		wantCategory   string
+		wantFallback   string
+		wantConfidence float64
+	}{
+		{"exact label", "", "technical", "technical", "", 0.9},
+		{"case drift", "", "Technical", "technical", "", 0.9},
+		{"near miss", "", "billings", "billing", LabelFallbackNearest, 0.9 * (1 - 1.0/8)},
+		{"multiple labels", "", "billing, technical", "billing", LabelFallbackNearest, 0.45},
+		{"out of vocabulary", "other", "weather forecast", "other", LabelFallbackUnknown, -1},
+		{"unknown label is allowed", "other", "other", "other", "", 0.9},
+		{"oversized label", "other", strings.Repeat("billing ", 1000), "other", LabelFallbackUnknown, -1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockProvider := new(MockProvider)
+			classifierAgent := &ClassifierAgent{
+				def:      agent.AgentDef{Name: "classifier", Model: "gpt-4"},
+				provider: mockProvider,
+				config: ClassifierConfig{
+					Categories:          categories,
+					ConfidenceThreshold: 0.7,
+					UnknownLabel:        tt.unknownLabel,
+				},
+				rt:              NewMockRuntime(),
+				promptCache:     make(map[string]string),
+				performanceData: []ClassificationMetrics{},
+				ready:           true,
+			}
+
+			resultJSON, _ := json.Marshal(ClassificationResult{Category: tt.modelCategory, Confidence: 0.9})
+			mockProvider.On("CreateStructured", ctx, mock.Anything).Return(&provider.StructuredResponse{Data: resultJSON}, nil)
+
+			msg, err := classifierAgent.Execute(ctx, &agent.Message{Message: &pb.Message{Payload: "my invoice is wrong"}})
+			require.NoError(t, err)
+
+			var result ClassificationResult
+			require.NoError(t, json.Unmarshal([]byte(msg.Payload), &result))
+			assert.Equal(t, tt.wantCategory, result.Category)
+			assert.Equal(t, tt.wantFallback, result.LabelFallback)
+			if tt.wantConfidence >= 0 {
+				assert.InDelta(t, tt.wantConfidence, result.Confidence, 1e-9)
+			} else {
+				assert.Less(t, result.Confidence, 0.45)
+			}
+
+			if tt.wantFallback == "" {
+				assert.Nil(t, msg.Metadata)
+				return
+			}
+			assert.Equal(t, tt.modelCategory, msg.Metadata[MetadataKeyRawCategory])
+			assert.Equal(t, tt.wantFallback, msg.Metadata[MetadataKeyLabelFallback])
+		})
+	}
+}
+
+func TestClassifierMalformedOutput(t *testing.T) {
+	structured := func(data string, tokens int) *provider.StructuredResponse {
+		return &provider.StructuredResponse{
+			Data: json.RawMessage(data),
+			CompletionResponse: provider.CompletionResponse{
+				Content: data,
+				Usage:   provider.Usage{TotalTokens: tokens},
+			},
+		}
+	}
+
+	tests := []struct {
+		name           string
+		responses      []*provider.StructuredResponse
+		wantConfidence float64
+		wantTokens     int
+		wantErr        string
+	}{
+		{
+			name:           "repaired after prose",
+			responses:      []*provider.StructuredResponse{structured(`Here you go: {"category": "technical", "confidence": 0.8, "reasoning": "code"}`, 40)},
+			wantConfidence: 0.8,
+			wantTokens:     40,
+		},
+		{
+			name: "retried after malformed JSON",
+			responses: []*provider.StructuredResponse{
+				structured(`{"category": "technical", "confidence": `, 30),
+				structured(`{"category": "technical", "confidence": 0.8, "reasoning": "code"}`, 50),
+			},
+			wantConfidence: 0.8,
+			wantTokens:     80,
+		},
+		{
+			name:       "zero confidence is valid",
+			responses:  []*provider.StructuredResponse{structured(`{"category": "technical", "confidence": 0, "reasoning": "unsure"}`, 20)},
+			wantTokens: 20,
+		},
+		{
+			name: "invalid confidence is an error",
+			responses: []*provider.StructuredResponse{
+				structured(`{"category": "technical", "confidence": 7}`, 10),
+				structured(`{"category": "technical", "confidence": 8}`, 10),
+			},
+			wantErr: "validation failed after 2 attempts",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			mockProvider := new(MockProvider)
+			for _, resp := range tt.responses {
+				mockProvider.On("CreateStructured", ctx, mock.Anything).Return(resp, nil).Once()
+			}
+			classifierAgent := &ClassifierAgent{
+				def:      agent.AgentDef{Name: "classifier", Model: "gpt-4"},
+				provider: mockProvider,
+				config: ClassifierConfig{
+					Categories:          []Category{{Name: "technical"}, {Name: "business"}},
+					ConfidenceThreshold: 0.7,
+					MaxRetries:          2,
+				},
+				rt:              NewMockRuntime(),
+				promptCache:     make(map[string]string),
+				performanceData: []ClassificationMetrics{},
+			}
+
+			result, err := classifierAgent.classify(ctx, "How do I fix this segfault?")
+			mockProvider.AssertExpectations(t)
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, "technical", result.Category)
+			assert.Equal(t, tt.wantConfidence, result.Confidence)
+			assert.Equal(t, tt.wantTokens, result.TokensUsed)
+		})
+	}
+}
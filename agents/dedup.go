@@ -0,0 +1,158 @@
+package agents
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/aggregation"
+)
+
+// Deduplication methods for AggregatorConfig.DeduplicationMethod
+const (
+	DeduplicationExact       = "exact"       // Equal after case and whitespace normalization
+	DeduplicationLevenshtein = "levenshtein" // Normalized edit-distance similarity
+	DeduplicationEmbedding   = "embedding"   // Cosine similarity of input embeddings
+)
+
+// defaultDeduplicationThreshold applies when SemanticSimilarity is unset
+const defaultDeduplicationThreshold = 0.85
+
+// deduplicationAliases maps older configuration names to their methods. They
+// predate deduplication, so they only take effect with Deduplicate set.
+var deduplicationAliases = map[string]string{
+	"exact_match": DeduplicationExact,
+	"semantic":    DeduplicationEmbedding,
+}
+
+// resolveDeduplicationMethod returns the canonical name of method, or "" if
+// deduplication is off. The method names turn it on. Other values were
+// accepted and ignored before deduplication existed, so they keep doing
+// nothing unless enabled opts in, which resolves the aliases, defaults an
+// empty method to exact and rejects unknown names.
+func resolveDeduplicationMethod(method string, enabled bool) (string, error) {
+	switch method {
+	case DeduplicationExact, DeduplicationLevenshtein, DeduplicationEmbedding:
+		return method, nil
+	}
+	if !enabled {
+		return "", nil
+	}
+	if method == "" {
+		return DeduplicationExact, nil
+	}
+	if alias, ok := deduplicationAliases[method]; ok {
+		return alias, nil
+	}
+	return "", fmt.Errorf("unknown deduplication method %q (want %q, %q or %q)",
+		method, DeduplicationExact, DeduplicationLevenshtein, DeduplicationEmbedding)
+}
+
+// dedupInputs collapses inputs whose similarity to an earlier input reaches
+// the SemanticSimilarity threshold. Each group is represented by its most
+// confident member, kept at the position of the group's first input. The
+// returned map lists, per representative, the agents merged into it.
+func (a *AggregatorAgent) dedupInputs(inputs []*AgentInput) ([]*AgentInput, map[string][]string, error) {
+	method, err := resolveDeduplicationMethod(a.config.DeduplicationMethod, a.config.Deduplicate)
+	if err != nil {
+		return nil, nil, err
+	}
+	if method == "" || len(inputs) < 2 {
+		return inputs, nil, nil
+	}
+	threshold := a.config.SemanticSimilarity
+	if threshold == 0 {
+		threshold = defaultDeduplicationThreshold
+	}
+
+	// Compare each input against the first member of every group so far
+	var groups [][]*AgentInput
+	for _, input := range inputs {
+		placed := false
+		for i, group := range groups {
+			if inputSimilarity(method, group[0], input) >= threshold {
+				groups[i] = append(group, input)
+				placed = true
+				break
+			}
+		}
+		if !placed {
+			groups = append(groups, []*AgentInput{input})
+		}
+	}
+	if len(groups) == len(inputs) {
+		return inputs, nil, nil
+	}
+
+	deduped := make([]*AgentInput, 0, len(groups))
+	merged := make(map[string][]string)
+	for _, group := range groups {
+		rep := group[0]
+		for _, member := range group[1:] {
+			if member.Confidence > rep.Confidence {
+				rep = member
+			}
+		}
+		deduped = append(deduped, rep)
+		for _, member := range group {
+			if member != rep {
+				merged[rep.AgentName] = append(merged[rep.AgentName], member.AgentName)
+			}
+		}
+	}
+	return deduped, merged, nil
+}
+
+// inputSimilarity scores two inputs in [0, 1] using method. Embedding
+// comparison falls back to text similarity when either input has no
+// embedding.
+func inputSimilarity(method string, x, y *AgentInput) float64 {
+	switch method {
+	case DeduplicationExact:
+		if normalizeForDedup(x.Content) == normalizeForDedup(y.Content) {
+			return 1
+		}
+		return 0
+	case DeduplicationEmbedding:
+		if len(x.Embedding) > 0 && len(x.Embedding) == len(y.Embedding) {
+			return embeddingSimilarity(x.Embedding, y.Embedding)
+		}
+	}
+	return aggregation.BoundedTextSimilarity(normalizeForDedup(x.Content), normalizeForDedup(y.Content))
+}
+
+// normalizeForDedup lowercases content and collapses whitespace
+func normalizeForDedup(content string) string {
+	return strings.Join(strings.Fields(strings.ToLower(content)), " ")
+}
+
+// embeddingSimilarity returns the cosine similarity of two equal-length vectors
+func embeddingSimilarity(x, y []float64) float64 {
+	var dot, normX, normY float64
+	for i := range x {
+		dot += x[i] * y[i]
+		normX += x[i] * x[i]
+		normY += y[i] * y[i]
+	}
+	if normX == 0 || normY == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normX) * math.Sqrt(normY))
+}
+
+// parseEmbedding reads a numeric array decoded from JSON
+func parseEmbedding(v any) []float64 {
+	values, ok := v.([]any)
+	if !ok || len(values) == 0 {
+		return nil
+	}
+	embedding := make([]float64, len(values))
+	for i, value := range values {
+		f, ok := value.(float64)
+		if !ok {
+			return nil
+		}
+		embedding[i] = f
+	}
+	return embedding
+}
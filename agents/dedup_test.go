@@ -0,0 +1,134 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregatorDeduplication(t *testing.T) {
+	// Three paraphrases of the same answer, with embeddings close together
+	inputs := func() []*AgentInput {
+		return []*AgentInput{
+			{AgentName: "agent1", Content: "Solution A is optimal", Confidence: 0.6, Embedding: []float64{1, 0.1, 0}},
+			{AgentName: "agent2", Content: "Solution B is better", Confidence: 0.7, Embedding: []float64{0, 1, 0}},
+			{AgentName: "agent3", Content: "The best choice is A", Confidence: 0.9, Embedding: []float64{0.95, 0.12, 0}},
+			{AgentName: "agent4", Content: "Option A works best", Confidence: 0.5, Embedding: []float64{1, 0.05, 0.02}},
+			{AgentName: "agent5", Content: "Needs more data", Confidence: 0.4, Embedding: []float64{0, 0, 1}},
+		}
+	}
+
+	t.Run("prompt contains only deduplicated inputs", func(t *testing.T) {
+		ctx := context.Background()
+		mockProvider := new(MockProvider)
+		aggAgent := &AggregatorAgent{
+			def:      agent.AgentDef{Model: "gpt-4"},
+			provider: mockProvider,
+			config: AggregatorConfig{
+				AggregationStrategy: StrategyConsensus,
+				DeduplicationMethod: DeduplicationEmbedding,
+				SemanticSimilarity:  0.95,
+			},
+			inputBuffer: make(map[string]*AgentInput),
+		}
+
+		var prompt string
+		resultJSON, _ := json.Marshal(AggregationResult{AggregatedContent: "A"})
+		mockProvider.On("CreateStructured", ctx, mock.MatchedBy(func(req provider.StructuredRequest) bool {
+			prompt = req.Messages[len(req.Messages)-1].Content
+			return true
+		})).Return(&provider.StructuredResponse{Data: resultJSON}, nil).Once()
+
+		result, err := aggAgent.aggregate(ctx, inputs())
+		require.NoError(t, err)
+
+		// agent3 represents the paraphrases as their most confident member
+		for _, kept := range []string{"The best choice is A", "Solution B is better", "Needs more data"} {
+			assert.Contains(t, prompt, kept)
+		}
+		for _, dropped := range []string{"Solution A is optimal", "Option A works best"} {
+			assert.NotContains(t, prompt, dropped)
+		}
+		assert.Equal(t, []string{"agent1", "agent2", "agent3", "agent4", "agent5"}, result.Sources)
+		assert.Equal(t, map[string][]string{"agent3": {"agent1", "agent4"}}, result.MergedSources)
+		mockProvider.AssertExpectations(t)
+	})
+
+	tests := []struct {
+		name    string
+		method  string
+		inputs  []*AgentInput
+		wantLen int
+	}{
+		{
+			name:   "exact ignores case and whitespace",
+			method: DeduplicationExact,
+			inputs: []*AgentInput{
+				{AgentName: "a", Content: "Solution A"},
+				{AgentName: "b", Content: "  solution   a "},
+				{AgentName: "c", Content: "Solution A."},
+			},
+			wantLen: 2,
+		},
+		{
+			name:   "levenshtein merges near-identical text",
+			method: DeduplicationLevenshtein,
+			inputs: []*AgentInput{
+				{AgentName: "a", Content: "Solution A is optimal"},
+				{AgentName: "b", Content: "Solution A is optimal!"},
+				{AgentName: "c", Content: "Needs more data"},
+			},
+			wantLen: 2,
+		},
+		{
+			name:   "embedding falls back to text without embeddings",
+			method: DeduplicationEmbedding,
+			inputs: []*AgentInput{
+				{AgentName: "a", Content: "Solution A is optimal"},
+				{AgentName: "b", Content: "solution a is optimal"},
+			},
+			wantLen: 1,
+		},
+		{
+			name:   "disabled",
+			method: "",
+			inputs: []*AgentInput{
+				{AgentName: "a", Content: "Solution A"},
+				{AgentName: "b", Content: "Solution A"},
+			},
+			wantLen: 2,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			aggAgent := &AggregatorAgent{config: AggregatorConfig{DeduplicationMethod: tt.method}}
+			deduped, _, err := aggAgent.dedupInputs(tt.inputs)
+			require.NoError(t, err)
+			assert.Len(t, deduped, tt.wantLen)
+		})
+	}
+
+	t.Run("legacy values need deduplicate", func(t *testing.T) {
+		for _, method := range []string{"semantic", "exact_match", "fuzzy"} {
+			aggAgent := &AggregatorAgent{config: AggregatorConfig{DeduplicationMethod: method}}
+			deduped, _, err := aggAgent.dedupInputs(inputs())
+			require.NoError(t, err, method)
+			assert.Len(t, deduped, 5, method)
+		}
+
+		aggAgent := &AggregatorAgent{config: AggregatorConfig{DeduplicationMethod: "semantic", Deduplicate: true, SemanticSimilarity: 0.95}}
+		deduped, _, err := aggAgent.dedupInputs(inputs())
+		require.NoError(t, err)
+		assert.Len(t, deduped, 3)
+
+		aggAgent = &AggregatorAgent{config: AggregatorConfig{DeduplicationMethod: "fuzzy", Deduplicate: true}}
+		_, _, err = aggAgent.dedupInputs(inputs())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,123 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/observability"
+	"github.com/sashabaranov/go-openai"
+)
+
+// tokenMetricsProvider records the token usage of each call in the
+// per-agent prompt and completion token histograms
+type tokenMetricsProvider struct {
+	provider.Provider
+	agent string
+}
+
+// withTokenMetrics wraps prov so its calls are recorded under agentName.
+// A nil provider stays nil.
+func withTokenMetrics(agentName string, prov provider.Provider) provider.Provider {
+	if prov == nil {
+		return nil
+	}
+	if p, ok := prov.(*tokenMetricsProvider); ok {
+		prov = p.Provider
+	}
+	return &tokenMetricsProvider{Provider: prov, agent: agentName}
+}
+
+func (p *tokenMetricsProvider) CreateCompletion(ctx context.Context, req provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	resp, err := p.Provider.CreateCompletion(ctx, req)
+	if err == nil && resp != nil {
+		observability.RecordAgentTokens(p.agent, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	return resp, err
+}
+
+func (p *tokenMetricsProvider) CreateStructured(ctx context.Context, req provider.StructuredRequest) (*provider.StructuredResponse, error) {
+	resp, err := p.Provider.CreateStructured(ctx, req)
+	if err == nil && resp != nil {
+		observability.RecordAgentTokens(p.agent, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	return resp, err
+}
+
+func (p *tokenMetricsProvider) CreateStreaming(ctx context.Context, req provider.CompletionRequest) (provider.Stream, error) {
+	stream, err := p.Provider.CreateStreaming(ctx, req)
+	if err != nil || stream == nil {
+		return stream, err
+	}
+	return &tokenMetricsStream{Stream: stream, agent: p.agent}, nil
+}
+
+// usageStream is implemented by streams that report their token usage, such
+// as provider.OllamaStream
+type usageStream interface {
+	Usage() provider.Usage
+}
+
+// tokenMetricsStream records a stream's usage once it finishes: when Recv
+// returns io.EOF, or on Close if the caller stops reading early. Streams
+// that do not report usage are not recorded.
+type tokenMetricsStream struct {
+	provider.Stream
+	agent    string
+	recorded sync.Once
+}
+
+func (s *tokenMetricsStream) Recv() (*provider.StreamChunk, error) {
+	chunk, err := s.Stream.Recv()
+	if errors.Is(err, io.EOF) {
+		s.record()
+	}
+	return chunk, err
+}
+
+func (s *tokenMetricsStream) Close() error {
+	s.record()
+	return s.Stream.Close()
+}
+
+// Usage returns the wrapped stream's usage, if it reports any
+func (s *tokenMetricsStream) Usage() provider.Usage {
+	if u, ok := s.Stream.(usageStream); ok {
+		return u.Usage()
+	}
+	return provider.Usage{}
+}
+
+func (s *tokenMetricsStream) record() {
+	s.recorded.Do(func() {
+		if usage := s.Usage(); usage.PromptTokens > 0 || usage.CompletionTokens > 0 {
+			observability.RecordAgentTokens(s.agent, usage.PromptTokens, usage.CompletionTokens)
+		}
+	})
+}
+
+// tokenMetricsClient records token usage for ReAct agents that call an
+// OpenAI-compatible client directly
+type tokenMetricsClient struct {
+	OpenAIClient
+	agent string
+}
+
+// withClientTokenMetrics wraps client so its calls are recorded under
+// agentName. A nil client stays nil.
+func withClientTokenMetrics(agentName string, client OpenAIClient) OpenAIClient {
+	if client == nil {
+		return nil
+	}
+	return &tokenMetricsClient{OpenAIClient: client, agent: agentName}
+}
+
+func (c *tokenMetricsClient) CreateChatCompletion(ctx context.Context, req openai.ChatCompletionRequest) (openai.ChatCompletionResponse, error) {
+	resp, err := c.OpenAIClient.CreateChatCompletion(ctx, req)
+	if err == nil {
+		observability.RecordAgentTokens(c.agent, resp.Usage.PromptTokens, resp.Usage.CompletionTokens)
+	}
+	return resp, err
+}
@@ -0,0 +1,144 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/observability"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// agentHistogram returns the histogram of metric for agent, or nil
+func agentHistogram(t *testing.T, metric, agent string) *dto.Histogram {
+	t.Helper()
+	families, err := prometheus.DefaultGatherer.Gather()
+	if err != nil {
+		t.Fatalf("Gather() error = %v", err)
+	}
+	for _, f := range families {
+		if f.GetName() != metric {
+			continue
+		}
+		for _, m := range f.GetMetric() {
+			for _, l := range m.GetLabel() {
+				if l.GetName() == "agent" && l.GetValue() == agent {
+					return m.GetHistogram()
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func TestTokenMetricsProvider(t *testing.T) {
+	observability.InitMetrics()
+
+	ctx := context.Background()
+
+	// A typical call and a long-context outlier
+	small := provider.NewMockProvider("test").AddCompletionResponse(&provider.CompletionResponse{
+		Content: "small",
+		Usage:   provider.Usage{PromptTokens: 100, CompletionTokens: 20, TotalTokens: 120},
+	})
+	large := provider.NewMockProvider("test").AddStructuredResponse(&provider.StructuredResponse{
+		Data:               []byte(`{}`),
+		CompletionResponse: provider.CompletionResponse{Usage: provider.Usage{PromptTokens: 50000, CompletionTokens: 300}},
+	})
+
+	prov := withTokenMetrics("rag-agent", small)
+	if withTokenMetrics("rag-agent", prov).(*tokenMetricsProvider).Provider != small {
+		t.Error("rewrapping should not record calls twice")
+	}
+	if _, err := prov.CreateCompletion(ctx, provider.CompletionRequest{}); err != nil {
+		t.Fatalf("CreateCompletion() error = %v", err)
+	}
+	if _, err := withTokenMetrics("rag-agent", large).CreateStructured(ctx, provider.StructuredRequest{}); err != nil {
+		t.Fatalf("CreateStructured() error = %v", err)
+	}
+
+	prompt := agentHistogram(t, "aixgo_agent_prompt_tokens", "rag-agent")
+	if prompt == nil {
+		t.Fatal("aixgo_agent_prompt_tokens not recorded for rag-agent")
+	}
+	if prompt.GetSampleCount() != 2 || prompt.GetSampleSum() != 50100 {
+		t.Errorf("prompt tokens count = %d, sum = %v, want 2 and 50100", prompt.GetSampleCount(), prompt.GetSampleSum())
+	}
+
+	completion := agentHistogram(t, "aixgo_agent_completion_tokens", "rag-agent")
+	if completion == nil || completion.GetSampleSum() != 320 {
+		t.Errorf("completion tokens histogram = %v, want sum 320", completion)
+	}
+
+	if withTokenMetrics("none", nil) != nil {
+		t.Error("withTokenMetrics(nil) should return nil")
+	}
+}
+
+// usageStreamStub streams chunks then io.EOF and reports usage like
+// provider.OllamaStream
+type usageStreamStub struct {
+	chunks []*provider.StreamChunk
+	usage  provider.Usage
+}
+
+func (s *usageStreamStub) Recv() (*provider.StreamChunk, error) {
+	if len(s.chunks) == 0 {
+		return nil, io.EOF
+	}
+	chunk := s.chunks[0]
+	s.chunks = s.chunks[1:]
+	return chunk, nil
+}
+
+func (s *usageStreamStub) Close() error          { return nil }
+func (s *usageStreamStub) Usage() provider.Usage { return s.usage }
+
+// streamingProvider returns stream from CreateStreaming
+type streamingProvider struct {
+	*provider.MockProvider
+	stream provider.Stream
+}
+
+func (p *streamingProvider) CreateStreaming(ctx context.Context, req provider.CompletionRequest) (provider.Stream, error) {
+	return p.stream, nil
+}
+
+func TestTokenMetricsProvider_Streaming(t *testing.T) {
+	observability.InitMetrics()
+
+	prov := withTokenMetrics("stream-agent", &streamingProvider{
+		MockProvider: provider.NewMockProvider("test"),
+		stream: &usageStreamStub{
+			chunks: []*provider.StreamChunk{{Delta: "hel"}, {Delta: "lo", FinishReason: "stop"}},
+			usage:  provider.Usage{PromptTokens: 40, CompletionTokens: 2},
+		},
+	})
+
+	stream, err := prov.CreateStreaming(context.Background(), provider.CompletionRequest{})
+	if err != nil {
+		t.Fatalf("CreateStreaming() error = %v", err)
+	}
+	for {
+		if _, err := stream.Recv(); err != nil {
+			if !errors.Is(err, io.EOF) {
+				t.Fatalf("Recv() error = %v", err)
+			}
+			break
+		}
+	}
+	_ = stream.Close()
+
+	// Finishing and then closing the stream records it once
+	prompt := agentHistogram(t, "aixgo_agent_prompt_tokens", "stream-agent")
+	if prompt == nil || prompt.GetSampleCount() != 1 || prompt.GetSampleSum() != 40 {
+		t.Errorf("prompt tokens histogram = %v, want one sample of 40", prompt)
+	}
+	completion := agentHistogram(t, "aixgo_agent_completion_tokens", "stream-agent")
+	if completion == nil || completion.GetSampleSum() != 2 {
+		t.Errorf("completion tokens histogram = %v, want sum 2", completion)
+	}
+}
@@ -189,15 +189,21 @@ func NewPlannerAgent(def agent.AgentDef, rt agent.Runtime) (agent.Agent, error)
 		return nil, fmt.Errorf("failed to initialize LLM provider: %w", err)
 	}
 
+	probe, err := readinessProbeFromDef(def, ProviderReadinessCheck(prov))
+	if err != nil {
+		return nil, err
+	}
+
 	baseAgent := NewBaseAgent(def)
 	if baseAgent == nil {
 		return nil, fmt.Errorf("failed to create BaseAgent")
 	}
+	baseAgent.SetReadinessProbe(probe)
 
 	return &PlannerAgent{
 		BaseAgent:      baseAgent,
 		def:            def,
-		provider:       prov,
+		provider:       withTokenMetrics(def.Name, prov),
 		config:         config,
 		rt:             rt,
 		planCache:      make(map[string]*ReasoningPlan),
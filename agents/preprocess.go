@@ -0,0 +1,184 @@
+package agents
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// InputPreprocessor transforms an agent's raw output before aggregation.
+// arg is the optional argument given after a colon in the preprocessor spec
+// (e.g. "answer" in "extract-json-field:answer").
+type InputPreprocessor func(content, arg string) (string, error)
+
+// Built-in input preprocessors
+const (
+	PreprocessorTrim             = "trim"
+	PreprocessorLowercase        = "lowercase"
+	PreprocessorStripMarkdown    = "strip-markdown"
+	PreprocessorExtractJSONField = "extract-json-field"
+)
+
+var (
+	preprocessors   = make(map[string]InputPreprocessor)
+	preprocessorsMu sync.RWMutex
+)
+
+func init() {
+	RegisterInputPreprocessor(PreprocessorTrim, trimPreprocessor)
+	RegisterInputPreprocessor(PreprocessorLowercase, lowercasePreprocessor)
+	RegisterInputPreprocessor(PreprocessorStripMarkdown, stripMarkdownPreprocessor)
+	RegisterInputPreprocessor(PreprocessorExtractJSONField, extractJSONFieldPreprocessor)
+}
+
+// RegisterInputPreprocessor makes a preprocessor available to AggregatorConfig.InputPreprocessor
+// under name. Registering an existing name replaces it.
+func RegisterInputPreprocessor(name string, fn InputPreprocessor) {
+	preprocessorsMu.Lock()
+	defer preprocessorsMu.Unlock()
+	preprocessors[name] = fn
+}
+
+// ListInputPreprocessors returns the names of all registered preprocessors
+func ListInputPreprocessors() []string {
+	preprocessorsMu.RLock()
+	defer preprocessorsMu.RUnlock()
+
+	names := make([]string, 0, len(preprocessors))
+	for name := range preprocessors {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// preprocessStep is one resolved stage of a preprocessor pipeline
+type preprocessStep struct {
+	name string
+	arg  string
+	fn   InputPreprocessor
+}
+
+// parsePreprocessorSpec resolves a spec such as "extract-json-field:answer|trim"
+// into its pipeline stages. Stages are separated by "|" and run left to right.
+func parsePreprocessorSpec(spec string) ([]preprocessStep, error) {
+	spec = strings.TrimSpace(spec)
+	if spec == "" {
+		return nil, nil
+	}
+
+	preprocessorsMu.RLock()
+	defer preprocessorsMu.RUnlock()
+
+	var steps []preprocessStep
+	for _, stage := range strings.Split(spec, "|") {
+		name, arg, _ := strings.Cut(strings.TrimSpace(stage), ":")
+		fn, ok := preprocessors[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown input preprocessor: %q", name)
+		}
+		steps = append(steps, preprocessStep{name: name, arg: arg, fn: fn})
+	}
+	return steps, nil
+}
+
+// runPreprocessors applies steps to content in order
+func runPreprocessors(steps []preprocessStep, content string) (string, error) {
+	for _, step := range steps {
+		out, err := step.fn(content, step.arg)
+		if err != nil {
+			return content, fmt.Errorf("%s: %w", step.name, err)
+		}
+		content = out
+	}
+	return content, nil
+}
+
+func trimPreprocessor(content, _ string) (string, error) {
+	return strings.TrimSpace(content), nil
+}
+
+func lowercasePreprocessor(content, _ string) (string, error) {
+	return strings.ToLower(content), nil
+}
+
+var (
+	mdCodeFence  = regexp.MustCompile("(?m)^\\s*```[^\\n]*$")
+	mdImage      = regexp.MustCompile(`!\[([^\]]*)\]\([^)]*\)`)
+	mdLink       = regexp.MustCompile(`\[([^\]]+)\]\([^)]*\)`)
+	mdHeading    = regexp.MustCompile(`(?m)^\s{0,3}#{1,6}\s+`)
+	mdBlockquote = regexp.MustCompile(`(?m)^\s{0,3}>\s?`)
+	mdListItem   = regexp.MustCompile(`(?m)^(\s*)(?:[-*+]|\d+[.)])\s+`)
+	mdBold       = regexp.MustCompile(`(\*\*|__)(.+?)(\*\*|__)`)
+	mdItalic     = regexp.MustCompile(`(^|[^\w*])[*_]([^*_\n]+)[*_]`)
+	mdInlineCode = regexp.MustCompile("`([^`]+)`")
+)
+
+// stripMarkdownPreprocessor removes common Markdown syntax, keeping the text
+func stripMarkdownPreprocessor(content, _ string) (string, error) {
+	out := mdCodeFence.ReplaceAllString(content, "")
+	out = mdImage.ReplaceAllString(out, "$1")
+	out = mdLink.ReplaceAllString(out, "$1")
+	out = mdHeading.ReplaceAllString(out, "")
+	out = mdBlockquote.ReplaceAllString(out, "")
+	out = mdListItem.ReplaceAllString(out, "$1")
+	out = mdBold.ReplaceAllString(out, "$2")
+	out = mdItalic.ReplaceAllString(out, "$1$2")
+	out = mdInlineCode.ReplaceAllString(out, "$1")
+	return strings.TrimSpace(out), nil
+}
+
+// extractJSONFieldPreprocessor finds the first JSON object in content (which
+// may be surrounded by prose or a code fence) and returns the field named by
+// arg. Nested fields use dots, e.g. "result.answer". Non-string values are
+// returned as JSON.
+func extractJSONFieldPreprocessor(content, field string) (string, error) {
+	if field == "" {
+		return "", fmt.Errorf("field name required, e.g. %s:answer", PreprocessorExtractJSONField)
+	}
+
+	obj, err := firstJSONObject(content)
+	if err != nil {
+		return "", err
+	}
+
+	var value any = obj
+	for _, key := range strings.Split(field, ".") {
+		m, ok := value.(map[string]any)
+		if !ok {
+			return "", fmt.Errorf("field %q not found", field)
+		}
+		if value, ok = m[key]; !ok {
+			return "", fmt.Errorf("field %q not found", field)
+		}
+	}
+
+	if s, ok := value.(string); ok {
+		return s, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode field %q: %w", field, err)
+	}
+	return string(data), nil
+}
+
+// firstJSONObject decodes the first JSON object that appears in text
+func firstJSONObject(text string) (map[string]any, error) {
+	for start := strings.Index(text, "{"); start != -1; {
+		var obj map[string]any
+		dec := json.NewDecoder(strings.NewReader(text[start:]))
+		if err := dec.Decode(&obj); err == nil {
+			return obj, nil
+		}
+		next := strings.Index(text[start+1:], "{")
+		if next == -1 {
+			break
+		}
+		start += next + 1
+	}
+	return nil, fmt.Errorf("no JSON object found")
+}
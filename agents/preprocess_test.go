@@ -0,0 +1,127 @@
+package agents
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInputPreprocessors(t *testing.T) {
+	tests := []struct {
+		name     string
+		spec     string
+		input    string
+		expected string
+	}{
+		{"trim", "trim", "  answer \n", "answer"},
+		{"lowercase", "lowercase", "Solution A", "solution a"},
+		{
+			name:     "strip markdown",
+			spec:     "strip-markdown",
+			input:    "## Answer\n\n**Solution A** is [best](http://x) with `code` and *emphasis*\n- item",
+			expected: "Answer\n\nSolution A is best with code and emphasis\nitem",
+		},
+		{
+			name:     "strip code fence",
+			spec:     "strip-markdown",
+			input:    "```json\n{\"a\": 1}\n```",
+			expected: "{\"a\": 1}",
+		},
+		{
+			name:     "extract field from prose",
+			spec:     "extract-json-field:answer",
+			input:    "Sure! Here is my answer: {\"answer\": \"Solution A\", \"confidence\": 0.9} Hope that helps.",
+			expected: "Solution A",
+		},
+		{
+			name:     "extract nested field",
+			spec:     "extract-json-field:result.answer",
+			input:    "```json\n{\"result\": {\"answer\": \"B\"}}\n```",
+			expected: "B",
+		},
+		{
+			name:     "extract non-string field",
+			spec:     "extract-json-field:scores",
+			input:    `{"scores": [1, 2]}`,
+			expected: "[1,2]",
+		},
+		{
+			name:     "skips braces that are not JSON",
+			spec:     "extract-json-field:answer",
+			input:    `Using {placeholders} here: {"answer": "C"}`,
+			expected: "C",
+		},
+		{"pipeline", "extract-json-field:answer|trim|lowercase", `{"answer": "  Solution A "}`, "solution a"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			steps, err := parsePreprocessorSpec(tt.spec)
+			require.NoError(t, err)
+
+			out, err := runPreprocessors(steps, tt.input)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, out)
+		})
+	}
+}
+
+func TestInputPreprocessorErrors(t *testing.T) {
+	_, err := parsePreprocessorSpec("does-not-exist")
+	assert.Error(t, err)
+
+	steps, err := parsePreprocessorSpec("extract-json-field:answer")
+	require.NoError(t, err)
+
+	_, err = runPreprocessors(steps, "no json here")
+	assert.Error(t, err)
+
+	_, err = runPreprocessors(steps, `{"other": "x"}`)
+	assert.Error(t, err)
+
+	steps, err = parsePreprocessorSpec("extract-json-field")
+	require.NoError(t, err)
+	_, err = runPreprocessors(steps, `{"answer": "x"}`)
+	assert.Error(t, err, "field name is required")
+}
+
+func TestRegisterInputPreprocessor(t *testing.T) {
+	RegisterInputPreprocessor("test-prefix", func(content, arg string) (string, error) {
+		return strings.TrimPrefix(content, arg), nil
+	})
+	assert.Contains(t, ListInputPreprocessors(), "test-prefix")
+
+	steps, err := parsePreprocessorSpec("test-prefix:Answer:")
+	require.NoError(t, err)
+
+	out, err := runPreprocessors(steps, "Answer:42")
+	require.NoError(t, err)
+	assert.Equal(t, "42", out)
+}
+
+func TestAggregator_InputPreprocessor(t *testing.T) {
+	aggAgent := &AggregatorAgent{
+		config: AggregatorConfig{
+			AggregationStrategy: StrategyVotingMajority,
+			InputPreprocessor:   "extract-json-field:answer|lowercase",
+		},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+
+	inputs := []*AgentInput{
+		{AgentName: "agent1", Content: `I think {"answer": "Solution A"}`},
+		{AgentName: "agent2", Content: "```json\n{\"answer\": \"solution a\"}\n```"},
+		{AgentName: "agent3", Content: `{"answer": "Solution B"}`},
+		{AgentName: "agent4", Content: "not json"}, // passed through unchanged
+	}
+
+	result, err := aggAgent.aggregate(context.Background(), inputs)
+	require.NoError(t, err)
+	assert.Equal(t, "solution a", result.AggregatedContent)
+
+	// Original inputs are not modified
+	assert.Equal(t, `I think {"answer": "Solution A"}`, inputs[0].Content)
+}
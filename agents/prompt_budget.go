@@ -0,0 +1,137 @@
+package agents
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"unicode/utf8"
+)
+
+// Policies for inputs that exceed MaxPromptTokens
+const (
+	OverBudgetTruncate     = "truncate"
+	OverBudgetHierarchical = "hierarchical"
+)
+
+// estimateTokens approximates token count at ~4 characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
+
+// estimatePromptTokens approximates the size of the request an LLM strategy
+// sends for inputs: its system prompt plus every input, as in the consensus
+// prompt.
+func (a *AggregatorAgent) estimatePromptTokens(strategy string, inputs []*AgentInput) int {
+	system := a.systemPrompt(strategy, len(inputs), a.builtinSystemPrompt(strategy))
+	return estimateTokens(system) + estimateTokens(a.buildConsensusPrompt(inputs))
+}
+
+// builtinSystemPrompt returns the default system prompt of an LLM strategy
+func (a *AggregatorAgent) builtinSystemPrompt(strategy string) string {
+	switch strategy {
+	case StrategySemantic:
+		return a.getSemanticSystemPrompt()
+	case StrategyWeighted:
+		return a.getWeightedSystemPrompt()
+	case StrategyHierarchical:
+		return a.getHierarchicalSystemPrompt()
+	case StrategyRAG:
+		return a.getRAGSystemPrompt()
+	default:
+		return a.getAggregatorSystemPrompt()
+	}
+}
+
+// fitPromptBudget keeps an LLM strategy's prompt within MaxPromptTokens. With
+// OverBudgetHierarchical it switches to the hierarchical strategy, which
+// summarizes inputs in groups; otherwise it drops the lowest-confidence
+// inputs until the prompt fits, trimming the last one if it alone is too
+// large. It returns the strategy and inputs to use and the dropped sources.
+func (a *AggregatorAgent) fitPromptBudget(strategy string, inputs []*AgentInput) (string, []*AgentInput, []string) {
+	budget := a.config.MaxPromptTokens
+	if budget <= 0 || !promptTemplateStrategies[strategy] || strategy == StrategyHierarchical {
+		return strategy, inputs, nil
+	}
+	estimate := a.estimatePromptTokens(strategy, inputs)
+	if estimate <= budget {
+		return strategy, inputs, nil
+	}
+
+	if a.config.OverBudget == OverBudgetHierarchical {
+		log.Printf("Aggregator prompt of ~%d tokens exceeds budget of %d, switching to %s", estimate, budget, StrategyHierarchical)
+		return StrategyHierarchical, inputs, nil
+	}
+
+	// Drop least confident first; among equals, the latest input
+	byConfidence := make([]int, len(inputs))
+	for i := range byConfidence {
+		byConfidence[i] = i
+	}
+	sort.SliceStable(byConfidence, func(i, j int) bool {
+		if inputs[byConfidence[i]].Confidence != inputs[byConfidence[j]].Confidence {
+			return inputs[byConfidence[i]].Confidence < inputs[byConfidence[j]].Confidence
+		}
+		return byConfidence[i] > byConfidence[j]
+	})
+
+	dropped := make(map[int]bool)
+	kept := inputs
+	for _, idx := range byConfidence[:len(inputs)-1] {
+		dropped[idx] = true
+		kept = make([]*AgentInput, 0, len(inputs)-len(dropped))
+		for i, input := range inputs {
+			if !dropped[i] {
+				kept = append(kept, input)
+			}
+		}
+		if a.estimatePromptTokens(strategy, kept) <= budget {
+			break
+		}
+	}
+
+	if len(kept) == 1 {
+		if over := a.estimatePromptTokens(strategy, kept) - budget; over > 0 {
+			trimmed := *kept[0]
+			trimmed.Content = truncateBytes(trimmed.Content, len(trimmed.Content)-over*4)
+			kept = []*AgentInput{&trimmed}
+			log.Printf("Aggregator trimmed input from %s to fit prompt budget of %d tokens", trimmed.AgentName, budget)
+		}
+	}
+
+	var droppedSources []string
+	for i, input := range inputs {
+		if dropped[i] {
+			droppedSources = append(droppedSources, input.AgentName)
+		}
+	}
+	log.Printf("Aggregator prompt of ~%d tokens exceeds budget of %d, dropped inputs from %v", estimate, budget, droppedSources)
+	return strategy, kept, droppedSources
+}
+
+// truncateBytes cuts text to at most n bytes, backing up to a rune boundary
+// so a multi-byte character is never split
+func truncateBytes(text string, n int) string {
+	if n >= len(text) {
+		return text
+	}
+	n = max(0, n)
+	for n > 0 && !utf8.RuneStart(text[n]) {
+		n--
+	}
+	return text[:n]
+}
+
+// validateOverBudget checks the OverBudget policy, defaulting it to truncate
+func validateOverBudget(config *AggregatorConfig) error {
+	switch config.OverBudget {
+	case "":
+		config.OverBudget = OverBudgetTruncate
+	case OverBudgetTruncate, OverBudgetHierarchical:
+	default:
+		return fmt.Errorf("over_budget must be %q or %q, got %q", OverBudgetTruncate, OverBudgetHierarchical, config.OverBudget)
+	}
+	if config.MaxPromptTokens < 0 {
+		return fmt.Errorf("max_prompt_tokens must not be negative")
+	}
+	return nil
+}
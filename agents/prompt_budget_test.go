@@ -0,0 +1,115 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// oversizedInputs returns inputs of ~500 tokens each
+func oversizedInputs() []*AgentInput {
+	return []*AgentInput{
+		{AgentName: "agent1", Content: strings.Repeat("alpha ", 330), Confidence: 0.9},
+		{AgentName: "agent2", Content: strings.Repeat("beta ", 400), Confidence: 0.3},
+		{AgentName: "agent3", Content: strings.Repeat("gamma ", 330), Confidence: 0.8},
+		{AgentName: "agent4", Content: strings.Repeat("delta ", 330), Confidence: 0.2},
+	}
+}
+
+func TestAggregator_PromptBudgetTruncates(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := new(MockProvider)
+	aggAgent := &AggregatorAgent{
+		provider: mockProvider,
+		config: AggregatorConfig{
+			AggregationStrategy: StrategyConsensus,
+			MaxPromptTokens:     1200,
+		},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+
+	var promptTokens int
+	data, _ := json.Marshal(AggregationResult{AggregatedContent: "merged"})
+	mockProvider.On("CreateStructured", ctx, mock.Anything).Run(func(args mock.Arguments) {
+		req := args.Get(1).(provider.StructuredRequest)
+		for _, msg := range req.Messages {
+			promptTokens += estimateTokens(msg.Content)
+		}
+	}).Return(&provider.StructuredResponse{Data: data}, nil).Once()
+
+	result, err := aggAgent.aggregate(ctx, oversizedInputs())
+	require.NoError(t, err)
+	assert.LessOrEqual(t, promptTokens, 1200, "prompt should fit the budget")
+	assert.Equal(t, []string{"agent2", "agent4"}, result.DroppedSources, "least confident inputs are dropped")
+	assert.ElementsMatch(t, []string{"agent1", "agent3"}, result.Sources)
+	assert.Equal(t, StrategyConsensus, result.Strategy)
+}
+
+func TestAggregator_PromptBudgetTrimsSingleInput(t *testing.T) {
+	aggAgent := &AggregatorAgent{config: AggregatorConfig{MaxPromptTokens: 300}}
+	inputs := oversizedInputs()
+
+	strategy, kept, dropped := aggAgent.fitPromptBudget(StrategyWeighted, inputs)
+	assert.Equal(t, StrategyWeighted, strategy)
+	require.Len(t, kept, 1)
+	assert.Equal(t, "agent1", kept[0].AgentName)
+	assert.Len(t, dropped, 3)
+	assert.LessOrEqual(t, aggAgent.estimatePromptTokens(strategy, kept), 300)
+	assert.Len(t, inputs[0].Content, 1980, "buffered input is not modified")
+
+	// Multi-byte content is trimmed on a rune boundary
+	inputs[0].Content = strings.Repeat("é日", 500)
+	_, kept, _ = aggAgent.fitPromptBudget(StrategyWeighted, inputs)
+	require.Len(t, kept, 1)
+	assert.True(t, utf8.ValidString(kept[0].Content), "trimmed content is valid UTF-8")
+	assert.Less(t, len(kept[0].Content), len(inputs[0].Content))
+}
+
+func TestTruncateBytes(t *testing.T) {
+	assert.Equal(t, "ab", truncateBytes("ab", 5))
+	assert.Equal(t, "a", truncateBytes("ab", 1))
+	assert.Equal(t, "", truncateBytes("ab", -1))
+	assert.Equal(t, "a", truncateBytes("a日", 3), "a three-byte rune is not split")
+	assert.Equal(t, "a日", truncateBytes("a日b", 4))
+}
+
+func TestAggregator_PromptBudgetHierarchical(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := new(MockProvider)
+	aggAgent := &AggregatorAgent{
+		provider: mockProvider,
+		config: AggregatorConfig{
+			AggregationStrategy: StrategyConsensus,
+			MaxPromptTokens:     1200,
+			OverBudget:          OverBudgetHierarchical,
+		},
+		inputBuffer: make(map[string]*AgentInput),
+	}
+	mockProvider.On("CreateCompletion", ctx, mock.Anything).Return(&provider.CompletionResponse{Content: "summary"}, nil)
+
+	result, err := aggAgent.aggregate(ctx, oversizedInputs())
+	require.NoError(t, err)
+	assert.Equal(t, StrategyHierarchical, result.Strategy)
+	assert.Empty(t, result.DroppedSources)
+	mockProvider.AssertNotCalled(t, "CreateStructured")
+}
+
+func TestAggregator_PromptBudgetUnderLimit(t *testing.T) {
+	aggAgent := &AggregatorAgent{config: AggregatorConfig{MaxPromptTokens: 100000}}
+	inputs := oversizedInputs()
+
+	strategy, kept, dropped := aggAgent.fitPromptBudget(StrategyConsensus, inputs)
+	assert.Equal(t, StrategyConsensus, strategy)
+	assert.Equal(t, inputs, kept)
+	assert.Nil(t, dropped)
+
+	config := AggregatorConfig{OverBudget: "summarize"}
+	assert.Error(t, validateOverBudget(&config))
+}
@@ -0,0 +1,73 @@
+package agents
+
+import (
+	"fmt"
+	"log"
+	"strings"
+	"text/template"
+)
+
+// PromptTemplateData is the data available to AggregatorConfig.PromptTemplates
+type PromptTemplateData struct {
+	// Strategy is the aggregation strategy being run
+	Strategy string
+	// InputCount is the number of inputs being aggregated
+	InputCount int
+}
+
+// promptTemplateStrategies are the strategies whose system prompt can be
+// overridden, i.e. those that call an LLM
+var promptTemplateStrategies = map[string]bool{
+	StrategyConsensus:    true,
+	StrategySemantic:     true,
+	StrategyWeighted:     true,
+	StrategyHierarchical: true,
+	StrategyRAG:          true,
+}
+
+// parsePromptTemplates parses each template as a Go text/template, checking
+// that it is keyed by an LLM-backed strategy and renders
+func parsePromptTemplates(templates map[string]string) (map[string]*template.Template, error) {
+	if len(templates) == 0 {
+		return nil, nil
+	}
+	parsed := make(map[string]*template.Template, len(templates))
+	for strategy, text := range templates {
+		if !promptTemplateStrategies[strategy] {
+			return nil, fmt.Errorf("prompt template for unknown strategy %q", strategy)
+		}
+		tmpl, err := template.New(strategy).Option("missingkey=error").Parse(text)
+		if err != nil {
+			return nil, fmt.Errorf("parse prompt template for %s: %w", strategy, err)
+		}
+		if _, err := renderPromptTemplate(tmpl, PromptTemplateData{Strategy: strategy}); err != nil {
+			return nil, err
+		}
+		parsed[strategy] = tmpl
+	}
+	return parsed, nil
+}
+
+// renderPromptTemplate executes a parsed prompt template with data
+func renderPromptTemplate(tmpl *template.Template, data PromptTemplateData) (string, error) {
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, data); err != nil {
+		return "", fmt.Errorf("render prompt template for %s: %w", tmpl.Name(), err)
+	}
+	return sb.String(), nil
+}
+
+// systemPrompt returns the system prompt for strategy: the configured
+// template if there is one, otherwise builtin
+func (a *AggregatorAgent) systemPrompt(strategy string, inputCount int, builtin string) string {
+	tmpl, ok := a.promptTemplates[strategy]
+	if !ok {
+		return builtin
+	}
+	prompt, err := renderPromptTemplate(tmpl, PromptTemplateData{Strategy: strategy, InputCount: inputCount})
+	if err != nil {
+		log.Printf("Aggregator using built-in system prompt: %v", err)
+		return builtin
+	}
+	return prompt
+}
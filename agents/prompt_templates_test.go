@@ -0,0 +1,57 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregator_PromptTemplates(t *testing.T) {
+	ctx := context.Background()
+	mockProvider := new(MockProvider)
+	templates, err := parsePromptTemplates(map[string]string{
+		StrategyConsensus: "Merge {{.InputCount}} answers tersely ({{.Strategy}}).",
+	})
+	require.NoError(t, err)
+	aggAgent := &AggregatorAgent{
+		provider:        mockProvider,
+		config:          AggregatorConfig{AggregationStrategy: StrategyConsensus},
+		promptTemplates: templates,
+		inputBuffer:     make(map[string]*AgentInput),
+	}
+	inputs := []*AgentInput{
+		{AgentName: "agent1", Content: "A"},
+		{AgentName: "agent2", Content: "A"},
+		{AgentName: "agent3", Content: "B"},
+	}
+
+	data, _ := json.Marshal(AggregationResult{AggregatedContent: "A"})
+	mockProvider.On("CreateStructured", ctx, mock.MatchedBy(func(req provider.StructuredRequest) bool {
+		return req.Messages[0].Content == "Merge 3 answers tersely (consensus)."
+	})).Return(&provider.StructuredResponse{Data: data}, nil).Once()
+
+	result, err := aggAgent.aggregate(ctx, inputs)
+	require.NoError(t, err)
+	assert.Equal(t, "A", result.AggregatedContent)
+	mockProvider.AssertExpectations(t)
+
+	// Strategies without a template keep the built-in prompt
+	assert.Equal(t, aggAgent.getSemanticSystemPrompt(),
+		aggAgent.systemPrompt(StrategySemantic, 3, aggAgent.getSemanticSystemPrompt()))
+}
+
+func TestParsePromptTemplates(t *testing.T) {
+	parse := func(templates map[string]string) error {
+		_, err := parsePromptTemplates(templates)
+		return err
+	}
+	assert.NoError(t, parse(map[string]string{StrategyRAG: "Cite sources for {{.InputCount}} inputs"}))
+	assert.Error(t, parse(map[string]string{StrategyRAG: "{{.InputCount"}), "parse error")
+	assert.Error(t, parse(map[string]string{StrategyRAG: "{{.Missing}}"}), "unknown field")
+	assert.Error(t, parse(map[string]string{StrategyVotingMajority: "x"}), "voting makes no LLM call")
+}
@@ -3,6 +3,7 @@ package agents
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"os"
@@ -16,6 +17,7 @@ import (
 	"github.com/aixgo-dev/aixgo/internal/observability"
 	"github.com/aixgo-dev/aixgo/pkg/mcp"
 	"github.com/aixgo-dev/aixgo/pkg/security"
+	"github.com/aixgo-dev/aixgo/pkg/tools"
 	pb "github.com/aixgo-dev/aixgo/proto"
 	"github.com/sashabaranov/go-openai"
 )
@@ -36,8 +38,35 @@ type ReActAgent struct {
 	mcpClient    *mcp.Client
 	mcpSessions  map[string]*mcp.Session
 	toolRegistry *mcp.ToolRegistry
+	localTools   *tools.Registry
+	maxSteps     int
 }
 
+// ReActConfig holds configuration for the ReAct reasoning loop
+type ReActConfig struct {
+	// MaxSteps limits the number of LLM completions per input (default: 5)
+	MaxSteps int `yaml:"max_steps" json:"max_steps"`
+}
+
+// ReActStep is one entry of a ReAct reasoning trace: the model's thought and,
+// unless it is the final answer, the tool it called and what it observed.
+type ReActStep struct {
+	Step        int    `json:"step"`
+	Thought     string `json:"thought,omitempty"`
+	Action      string `json:"action,omitempty"`
+	Arguments   string `json:"arguments,omitempty"`
+	Observation string `json:"observation,omitempty"`
+	Error       string `json:"error,omitempty"`
+}
+
+// MetadataKeyReActTrace is the response metadata key holding the []ReActStep
+// trace of the reasoning that produced the answer.
+const MetadataKeyReActTrace = "react_trace"
+
+// ErrMaxStepsReached is returned when the model is still calling tools after
+// the configured maximum number of steps.
+var ErrMaxStepsReached = errors.New("react: max steps reached without a final answer")
+
 // GuidedStepResult represents the result of a single tool execution in guided mode
 type GuidedStepResult struct {
 	Iteration int    `json:"iteration"`
@@ -83,6 +112,14 @@ func NewReActAgentWithClient(def agent.AgentDef, rt agent.Runtime, client OpenAI
 
 // NewReActAgentWithProvider creates a new ReActAgent with custom client and provider
 func NewReActAgentWithProvider(def agent.AgentDef, rt agent.Runtime, client OpenAIClient, prov provider.Provider) (agent.Agent, error) {
+	var config ReActConfig
+	if err := def.UnmarshalKey("react_config", &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal react config: %w", err)
+	}
+	if config.MaxSteps <= 0 {
+		config.MaxSteps = 5
+	}
+
 	tools := make(map[string]func(context.Context, map[string]any) (any, error))
 	for _, t := range def.Tools {
 		validator := llm.NewValidator(t.InputSchema)
@@ -95,17 +132,27 @@ func NewReActAgentWithProvider(def agent.AgentDef, rt agent.Runtime, client Open
 		}
 	}
 
+	base := NewBaseAgent(def)
+	if prov != nil {
+		probe, err := readinessProbeFromDef(def, ProviderReadinessCheck(prov))
+		if err != nil {
+			return nil, err
+		}
+		base.SetReadinessProbe(probe)
+	}
+
 	agent := &ReActAgent{
-		BaseAgent:    NewBaseAgent(def),
+		BaseAgent:    base,
 		def:          def,
-		client:       client,
-		provider:     prov,
+		client:       withClientTokenMetrics(def.Name, client),
+		provider:     withTokenMetrics(def.Name, prov),
 		model:        def.Model,
 		tools:        tools,
 		rt:           rt,
 		mcpClient:    mcp.NewClient(),
 		mcpSessions:  make(map[string]*mcp.Session),
 		toolRegistry: mcp.NewToolRegistry(),
+		maxSteps:     config.MaxSteps,
 	}
 
 	return agent, nil
@@ -166,9 +213,15 @@ func (r *ReActAgent) ConnectMCPServers(ctx context.Context, serverConfigs []mcp.
 	return nil
 }
 
+// SetToolRegistry makes the in-process tools in reg available to the agent,
+// alongside its defined tools and those of connected MCP servers.
+func (r *ReActAgent) SetToolRegistry(reg *tools.Registry) {
+	r.localTools = reg
+}
+
 // SetProvider sets the LLM provider for this agent
 func (r *ReActAgent) SetProvider(prov provider.Provider) {
-	r.provider = prov
+	r.provider = withTokenMetrics(r.def.Name, prov)
 }
 
 // Execute performs synchronous ReAct execution
@@ -184,7 +237,7 @@ func (r *ReActAgent) Execute(ctx context.Context, input *agent.Message) (*agent.
 	}
 
 	// Use the existing think method to process the input
-	result, err := r.think(ctx, inputStr)
+	result, trace, err := r.think(ctx, inputStr)
 	if err != nil {
 		return nil, err
 	}
@@ -192,8 +245,9 @@ func (r *ReActAgent) Execute(ctx context.Context, input *agent.Message) (*agent.
 	// Convert result back to Message
 	return &agent.Message{
 		Message: &pb.Message{
-			Type:    "react_response",
-			Payload: result,
+			Type:     "react_response",
+			Payload:  result,
+			Metadata: traceMetadata(trace),
 		},
 	}, nil
 }
@@ -237,7 +291,7 @@ func (r *ReActAgent) Start(ctx context.Context) error {
 		}
 
 		span := observability.StartSpan("react.think", map[string]any{"input": inputPayload})
-		res, err := r.think(ctx, inputPayload)
+		res, trace, err := r.think(ctx, inputPayload)
 		span.End()
 		if err != nil {
 			log.Printf("ReAct error: %v", err)
@@ -248,6 +302,7 @@ func (r *ReActAgent) Start(ctx context.Context) error {
 			Type:      "analysis",
 			Payload:   res,
 			Timestamp: time.Now().Format(time.RFC3339),
+			Metadata:  traceMetadata(trace),
 		}}
 		for _, o := range r.def.Outputs {
 			if err := r.rt.Send(o.Target, out); err != nil {
@@ -258,111 +313,77 @@ func (r *ReActAgent) Start(ctx context.Context) error {
 	return nil
 }
 
-func (r *ReActAgent) think(ctx context.Context, input string) (string, error) {
+// think runs the reasoning loop for input and returns the final answer with
+// its trace. Guided mode reports its steps in the answer and has no trace.
+func (r *ReActAgent) think(ctx context.Context, input string) (string, []ReActStep, error) {
 	// Check if guided mode is enabled
 	if r.def.GuidedConfig != nil && r.def.GuidedConfig.Enabled {
-		return r.thinkGuided(ctx, input)
+		result, err := r.thinkGuided(ctx, input)
+		return result, nil, err
 	}
 
-	// Use provider if available (HuggingFace or other provider-based implementations)
-	if r.provider != nil {
-		return r.thinkWithProvider(ctx, input)
-	}
-
-	// Fall back to OpenAI client
-	if r.client == nil {
-		return "", fmt.Errorf("no LLM client or provider configured")
-	}
-
-	return r.thinkWithOpenAI(ctx, input)
-}
-
-func (r *ReActAgent) thinkWithProvider(ctx context.Context, input string) (string, error) {
-	// Build tools from both agent definition and MCP servers
-	allTools := r.buildProviderTools()
-
-	// Build messages
-	messages := []provider.Message{
+	return r.reactLoop(ctx, []provider.Message{
 		{Role: "system", Content: r.def.Prompt},
 		{Role: "user", Content: input},
-	}
+	})
+}
 
-	req := provider.CompletionRequest{
-		Messages:    messages,
-		Model:       r.model,
-		Tools:       allTools,
-		Temperature: 0.7,
-		MaxTokens:   2000,
-	}
+// reactLoop runs thought/action/observation cycles: each completion that
+// calls tools has them executed and their observations appended to the
+// conversation, until the model answers without calling a tool or maxSteps
+// completions have been made.
+func (r *ReActAgent) reactLoop(ctx context.Context, messages []provider.Message) (string, []ReActStep, error) {
+	var trace []ReActStep
 
-	resp, err := r.provider.CreateCompletion(ctx, req)
-	if err != nil {
-		return "", fmt.Errorf("provider completion: %w", err)
-	}
+	for step := 1; step <= r.maxSteps; step++ {
+		var resp *provider.CompletionResponse
+		var err error
 
-	// Handle tool calls
-	if len(resp.ToolCalls) > 0 {
-		call := resp.ToolCalls[0]
-		result, err := r.executeProviderTool(ctx, call)
+		// Use provider if available (HuggingFace or other provider-based implementations)
+		if r.provider != nil {
+			resp, err = r.guidedProviderCall(ctx, messages)
+		} else if r.client != nil {
+			resp, err = r.guidedOpenAICall(ctx, messages)
+		} else {
+			return "", trace, fmt.Errorf("no LLM client or provider configured")
+		}
 		if err != nil {
-			return "", fmt.Errorf("tool execution: %w", err)
+			return "", trace, fmt.Errorf("react step %d: %w", step, err)
 		}
-		return fmt.Sprintf("Tool %s → %v", call.Function.Name, result), nil
-	}
 
-	return resp.Content, nil
-}
+		// No tool calls means the model has answered
+		if len(resp.ToolCalls) == 0 {
+			trace = append(trace, ReActStep{Step: step, Thought: resp.Content})
+			return resp.Content, trace, nil
+		}
+
+		messages = append(messages, provider.Message{Role: "assistant", Content: resp.Content, ToolCalls: resp.ToolCalls})
+		for _, call := range resp.ToolCalls {
+			result, err := r.executeProviderTool(ctx, call)
+			entry := ReActStep{
+				Step:      step,
+				Thought:   resp.Content,
+				Action:    call.Function.Name,
+				Arguments: string(call.Function.Arguments),
+			}
+			if err != nil {
+				// Errors are observations too, so the model can recover
+				entry.Error = err.Error()
+				entry.Observation = fmt.Sprintf("Error: %v", err)
+			} else {
+				entry.Observation = fmt.Sprintf("%v", result)
+			}
+			trace = append(trace, entry)
 
-func (r *ReActAgent) thinkWithOpenAI(ctx context.Context, input string) (string, error) {
-	tools := make([]openai.Tool, len(r.def.Tools))
-	for i, t := range r.def.Tools {
-		tools[i] = openai.Tool{
-			Type: "function",
-			Function: &openai.FunctionDefinition{
-				Name:        t.Name,
-				Description: t.Description,
-				Parameters:  json.RawMessage(mustMarshal(t.InputSchema)),
-			},
+			messages = append(messages, provider.Message{
+				Role:       "tool",
+				Content:    entry.Observation,
+				ToolCallID: call.ID,
+			})
 		}
 	}
 
-	req := openai.ChatCompletionRequest{
-		Model: r.model,
-		Messages: []openai.ChatCompletionMessage{
-			{Role: "system", Content: r.def.Prompt},
-			{Role: "user", Content: input},
-		},
-		Tools: tools,
-	}
-
-	resp, err := r.client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return "", err
-	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
-	}
-
-	if len(resp.Choices[0].Message.ToolCalls) > 0 {
-		call := resp.Choices[0].Message.ToolCalls[0]
-		var args map[string]any
-		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
-			return "", fmt.Errorf("failed to unmarshal tool arguments: %w", err)
-		}
-
-		toolFunc, ok := r.tools[call.Function.Name]
-		if !ok {
-			return "", fmt.Errorf("unknown tool: %s", call.Function.Name)
-		}
-
-		result, err := toolFunc(ctx, args)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("Tool %s → %v", call.Function.Name, result), nil
-	}
-	return resp.Choices[0].Message.Content, nil
+	return "", trace, fmt.Errorf("%w (%d)", ErrMaxStepsReached, r.maxSteps)
 }
 
 // thinkGuided performs step-by-step guided execution with verification
@@ -449,7 +470,7 @@ func (r *ReActAgent) thinkGuided(ctx context.Context, input string) (string, err
 	return r.formatGuidedResult(stepResults), nil
 }
 
-// guidedProviderCall makes a provider completion call for guided execution
+// guidedProviderCall makes a provider completion call offering the agent's tools
 func (r *ReActAgent) guidedProviderCall(ctx context.Context, messages []provider.Message) (*provider.CompletionResponse, error) {
 	allTools := r.buildProviderTools()
 	req := provider.CompletionRequest{
@@ -462,26 +483,38 @@ func (r *ReActAgent) guidedProviderCall(ctx context.Context, messages []provider
 	return r.provider.CreateCompletion(ctx, req)
 }
 
-// guidedOpenAICall makes an OpenAI completion call for guided execution
+// guidedOpenAICall makes an OpenAI completion call offering the agent's tools
 func (r *ReActAgent) guidedOpenAICall(ctx context.Context, messages []provider.Message) (*provider.CompletionResponse, error) {
 	// Convert provider messages to OpenAI messages
 	openaiMessages := make([]openai.ChatCompletionMessage, len(messages))
 	for i, msg := range messages {
 		openaiMessages[i] = openai.ChatCompletionMessage{
-			Role:    msg.Role,
-			Content: msg.Content,
+			Role:       msg.Role,
+			Content:    msg.Content,
+			ToolCallID: msg.ToolCallID,
+		}
+		for _, tc := range msg.ToolCalls {
+			openaiMessages[i].ToolCalls = append(openaiMessages[i].ToolCalls, openai.ToolCall{
+				ID:   tc.ID,
+				Type: openai.ToolType(tc.Type),
+				Function: openai.FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: string(tc.Function.Arguments),
+				},
+			})
 		}
 	}
 
 	// Build OpenAI tools
-	tools := make([]openai.Tool, len(r.def.Tools))
-	for i, t := range r.def.Tools {
+	providerTools := r.buildProviderTools()
+	tools := make([]openai.Tool, len(providerTools))
+	for i, t := range providerTools {
 		tools[i] = openai.Tool{
 			Type: "function",
 			Function: &openai.FunctionDefinition{
 				Name:        t.Name,
 				Description: t.Description,
-				Parameters:  json.RawMessage(mustMarshal(t.InputSchema)),
+				Parameters:  t.Parameters,
 			},
 		}
 	}
@@ -637,6 +670,17 @@ func (r *ReActAgent) buildProviderTools() []provider.Tool {
 		})
 	}
 
+	// Add in-process tools
+	if r.localTools != nil {
+		for _, t := range r.localTools.List() {
+			tools = append(tools, provider.Tool{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			})
+		}
+	}
+
 	// Add MCP tools
 	mcpTools := r.toolRegistry.ListTools()
 	for _, mcpTool := range mcpTools {
@@ -681,7 +725,18 @@ func (r *ReActAgent) executeProviderTool(ctx context.Context, call provider.Tool
 		return toolFunc(ctx, args)
 	}
 
-	// 5. Check if MCP tool
+	// 5. Check if in-process tool
+	if r.localTools != nil {
+		if _, ok := r.localTools.Get(call.Function.Name); ok {
+			out, err := r.localTools.Invoke(ctx, call.Function.Name, mustMarshal(args))
+			if err != nil {
+				return nil, err
+			}
+			return string(out), nil
+		}
+	}
+
+	// 6. Check if MCP tool
 	if r.toolRegistry.HasTool(call.Function.Name) {
 		return r.executeMCPTool(ctx, call.Function.Name, args)
 	}
@@ -818,7 +873,7 @@ func (r *ReActAgent) ExecuteWithSession(ctx context.Context, input *agent.Messag
 	}
 
 	// Execute with conversation history
-	result, err := r.thinkWithHistory(ctx, inputStr, history)
+	result, trace, err := r.thinkWithHistory(ctx, inputStr, history)
 	if err != nil {
 		return nil, err
 	}
@@ -828,10 +883,20 @@ func (r *ReActAgent) ExecuteWithSession(ctx context.Context, input *agent.Messag
 			Type:      "react_response",
 			Payload:   result,
 			Timestamp: time.Now().Format(time.RFC3339),
+			Metadata:  traceMetadata(trace),
 		},
 	}, nil
 }
 
+// traceMetadata returns response metadata carrying the reasoning trace, or
+// nil when there is no trace.
+func traceMetadata(trace []ReActStep) map[string]any {
+	if len(trace) == 0 {
+		return nil
+	}
+	return map[string]any{MetadataKeyReActTrace: trace}
+}
+
 // SessionProvider is a minimal interface for session access during execution.
 // This avoids import cycles with pkg/session.
 type SessionProvider interface {
@@ -839,24 +904,7 @@ type SessionProvider interface {
 }
 
 // thinkWithHistory performs LLM reasoning with conversation history.
-func (r *ReActAgent) thinkWithHistory(ctx context.Context, input string, history []*publicAgent.Message) (string, error) {
-	// Use provider if available
-	if r.provider != nil {
-		return r.thinkWithProviderAndHistory(ctx, input, history)
-	}
-
-	// Fall back to OpenAI client
-	if r.client == nil {
-		return "", fmt.Errorf("no LLM client or provider configured")
-	}
-
-	return r.thinkWithOpenAIAndHistory(ctx, input, history)
-}
-
-// thinkWithProviderAndHistory performs provider-based reasoning with history.
-func (r *ReActAgent) thinkWithProviderAndHistory(ctx context.Context, input string, history []*publicAgent.Message) (string, error) {
-	allTools := r.buildProviderTools()
-
+func (r *ReActAgent) thinkWithHistory(ctx context.Context, input string, history []*publicAgent.Message) (string, []ReActStep, error) {
 	// Build messages including history
 	messages := []provider.Message{
 		{Role: "system", Content: r.def.Prompt},
@@ -887,107 +935,5 @@ func (r *ReActAgent) thinkWithProviderAndHistory(ctx context.Context, input stri
 	// Add current input
 	messages = append(messages, provider.Message{Role: "user", Content: input})
 
-	req := provider.CompletionRequest{
-		Messages:    messages,
-		Model:       r.model,
-		Tools:       allTools,
-		Temperature: 0.7,
-		MaxTokens:   2000,
-	}
-
-	resp, err := r.provider.CreateCompletion(ctx, req)
-	if err != nil {
-		return "", fmt.Errorf("provider completion: %w", err)
-	}
-
-	// Handle tool calls
-	if len(resp.ToolCalls) > 0 {
-		call := resp.ToolCalls[0]
-		result, err := r.executeProviderTool(ctx, call)
-		if err != nil {
-			return "", fmt.Errorf("tool execution: %w", err)
-		}
-		return fmt.Sprintf("Tool %s → %v", call.Function.Name, result), nil
-	}
-
-	return resp.Content, nil
-}
-
-// thinkWithOpenAIAndHistory performs OpenAI reasoning with history.
-func (r *ReActAgent) thinkWithOpenAIAndHistory(ctx context.Context, input string, history []*publicAgent.Message) (string, error) {
-	tools := make([]openai.Tool, len(r.def.Tools))
-	for i, t := range r.def.Tools {
-		tools[i] = openai.Tool{
-			Type: "function",
-			Function: &openai.FunctionDefinition{
-				Name:        t.Name,
-				Description: t.Description,
-				Parameters:  json.RawMessage(mustMarshal(t.InputSchema)),
-			},
-		}
-	}
-
-	// Build messages including history
-	messages := []openai.ChatCompletionMessage{
-		{Role: "system", Content: r.def.Prompt},
-	}
-
-	// Add conversation history
-	for _, msg := range history {
-		role := "user"
-		if msg.Type == "assistant" || msg.Type == "react_response" {
-			role = "assistant"
-		}
-		content := msg.Payload
-		if content == "" {
-			var payload map[string]string
-			if err := msg.UnmarshalPayload(&payload); err == nil {
-				if c, ok := payload["content"]; ok {
-					content = c
-				}
-			}
-		}
-		if content != "" {
-			messages = append(messages, openai.ChatCompletionMessage{Role: role, Content: content})
-		}
-	}
-
-	// Add current input
-	messages = append(messages, openai.ChatCompletionMessage{Role: "user", Content: input})
-
-	req := openai.ChatCompletionRequest{
-		Model:    r.model,
-		Messages: messages,
-		Tools:    tools,
-	}
-
-	resp, err := r.client.CreateChatCompletion(ctx, req)
-	if err != nil {
-		return "", err
-	}
-
-	if len(resp.Choices) == 0 {
-		return "", fmt.Errorf("no choices in response")
-	}
-
-	if len(resp.Choices[0].Message.ToolCalls) > 0 {
-		call := resp.Choices[0].Message.ToolCalls[0]
-		var args map[string]any
-		if err := json.Unmarshal([]byte(call.Function.Arguments), &args); err != nil {
-			return "", fmt.Errorf("failed to unmarshal tool arguments: %w", err)
-		}
-
-		toolFunc, ok := r.tools[call.Function.Name]
-		if !ok {
-			return "", fmt.Errorf("unknown tool: %s", call.Function.Name)
-		}
-
-		result, err := toolFunc(ctx, args)
-		if err != nil {
-			return "", err
-		}
-		return fmt.Sprintf("Tool %s → %v", call.Function.Name, result), nil
-	}
-
-	return resp.Choices[0].Message.Content, nil
+	return r.reactLoop(ctx, messages)
 }
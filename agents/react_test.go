@@ -0,0 +1,140 @@
+package agents
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/mcp"
+	"github.com/aixgo-dev/aixgo/pkg/tools"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestReActAgent(t *testing.T, def agent.AgentDef, prov provider.Provider) *ReActAgent {
+	t.Helper()
+	a, err := NewReActAgentWithProvider(def, nil, nil, prov)
+	require.NoError(t, err)
+	react := a.(*ReActAgent)
+	react.SetReady(true)
+	return react
+}
+
+func toolCall(name, args string) provider.ToolCall {
+	return provider.ToolCall{
+		ID:       "call-" + name,
+		Type:     "function",
+		Function: provider.FunctionCall{Name: name, Arguments: json.RawMessage(args)},
+	}
+}
+
+func TestReActAgent_ToolThenAnswer(t *testing.T) {
+	ctx := context.Background()
+
+	server := mcp.NewServer("react-test-tools")
+	require.NoError(t, server.RegisterTool(mcp.Tool{
+		Name:        "lookup_population",
+		Description: "Looks up a city's population",
+		Handler: func(ctx context.Context, args mcp.Args) (any, error) {
+			return args.String("city") + ": 2.1 million", nil
+		},
+	}))
+	mcp.RegisterLocalServer(server)
+
+	mp := new(MockProvider)
+	mp.On("CreateCompletion", mock.Anything, mock.Anything).Return(&provider.CompletionResponse{
+		Content:   "I should look up the population.",
+		ToolCalls: []provider.ToolCall{toolCall("lookup_population", `{"city":"Paris"}`)},
+	}, nil).Once()
+	mp.On("CreateCompletion", mock.Anything, mock.Anything).Return(&provider.CompletionResponse{
+		Content: "Paris has about 2.1 million people.",
+	}, nil).Once()
+
+	react := newTestReActAgent(t, agent.AgentDef{Name: "researcher", Prompt: "You answer questions."}, mp)
+	require.NoError(t, react.ConnectMCPServers(ctx, []mcp.ServerConfig{{Name: "react-test-tools", Transport: "local"}}))
+
+	out, err := react.Execute(ctx, &agent.Message{Message: &pb.Message{Payload: "How many people live in Paris?"}})
+	require.NoError(t, err)
+	assert.Equal(t, "Paris has about 2.1 million people.", out.Payload)
+
+	trace, ok := out.Metadata[MetadataKeyReActTrace].([]ReActStep)
+	require.True(t, ok, "response metadata should carry the trace")
+	require.Len(t, trace, 2)
+	assert.Equal(t, ReActStep{
+		Step:        1,
+		Thought:     "I should look up the population.",
+		Action:      "lookup_population",
+		Arguments:   `{"city":"Paris"}`,
+		Observation: "Paris: 2.1 million",
+	}, trace[0])
+	assert.Equal(t, ReActStep{Step: 2, Thought: "Paris has about 2.1 million people."}, trace[1])
+
+	// The observation is fed back to the model
+	mp.AssertNumberOfCalls(t, "CreateCompletion", 2)
+	second := mp.Calls[1].Arguments.Get(1).(provider.CompletionRequest)
+	require.Len(t, second.Messages, 4)
+	assert.Equal(t, "assistant", second.Messages[2].Role)
+	assert.Equal(t, []provider.ToolCall{toolCall("lookup_population", `{"city":"Paris"}`)}, second.Messages[2].ToolCalls)
+	assert.Equal(t, provider.Message{
+		Role:       "tool",
+		Content:    "Paris: 2.1 million",
+		ToolCallID: "call-lookup_population",
+	}, second.Messages[3])
+}
+
+func TestReActAgent_MaxSteps(t *testing.T) {
+	mp := new(MockProvider)
+	mp.On("CreateCompletion", mock.Anything, mock.Anything).Return(&provider.CompletionResponse{
+		ToolCalls: []provider.ToolCall{toolCall("missing_tool", `{}`)},
+	}, nil)
+
+	def := agent.AgentDef{
+		Name:  "looper",
+		Extra: map[string]any{"react_config": map[string]any{"max_steps": 2}},
+	}
+	react := newTestReActAgent(t, def, mp)
+
+	_, trace, err := react.think(context.Background(), "loop forever")
+	assert.ErrorIs(t, err, ErrMaxStepsReached)
+	mp.AssertNumberOfCalls(t, "CreateCompletion", 2)
+
+	// Tool failures are observed rather than aborting the loop
+	require.Len(t, trace, 2)
+	assert.Contains(t, trace[0].Error, "unknown tool")
+	assert.Contains(t, trace[0].Observation, "Error:")
+}
+
+func TestReActAgent_ToolRegistry(t *testing.T) {
+	reg := tools.NewRegistry()
+	require.NoError(t, reg.RegisterFunc("add",
+		json.RawMessage(`{"type":"object","properties":{"a":{"type":"number"},"b":{"type":"number"}},"required":["a","b"]}`),
+		func(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+			var in struct{ A, B float64 }
+			if err := json.Unmarshal(args, &in); err != nil {
+				return nil, err
+			}
+			return json.Marshal(in.A + in.B)
+		}))
+
+	mp := new(MockProvider)
+	mp.On("CreateCompletion", mock.Anything, mock.MatchedBy(func(req provider.CompletionRequest) bool {
+		return len(req.Tools) == 1 && req.Tools[0].Name == "add"
+	})).Return(&provider.CompletionResponse{
+		ToolCalls: []provider.ToolCall{toolCall("add", `{"a":2,"b":3}`)},
+	}, nil).Once()
+	mp.On("CreateCompletion", mock.Anything, mock.Anything).Return(&provider.CompletionResponse{Content: "5"}, nil).Once()
+
+	react := newTestReActAgent(t, agent.AgentDef{Name: "calc"}, mp)
+	react.SetToolRegistry(reg)
+
+	answer, trace, err := react.think(context.Background(), "What is 2 + 3?")
+	require.NoError(t, err)
+	assert.Equal(t, "5", answer)
+	require.Len(t, trace, 2)
+	assert.Equal(t, "5", trace[0].Observation)
+	assert.Empty(t, trace[0].Error)
+}
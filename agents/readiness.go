@@ -0,0 +1,200 @@
+package agents
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+const (
+	// DefaultReadinessTTL is how long a readiness result is cached before re-checking
+	DefaultReadinessTTL = 30 * time.Second
+
+	// DefaultReadinessTimeout bounds how long a single dependency check may take
+	DefaultReadinessTimeout = 5 * time.Second
+)
+
+// ReadinessCheck is a named dependency check used by a ReadinessProbe.
+// Check returns nil when the dependency can serve requests.
+type ReadinessCheck struct {
+	Name  string
+	Check func(ctx context.Context) error
+}
+
+// ReadinessProbe checks an agent's external dependencies (LLM provider,
+// vector store, ...) and caches the result so Ready() stays cheap enough to
+// call on every request. The zero value is not usable; use NewReadinessProbe.
+type ReadinessProbe struct {
+	checks    []ReadinessCheck
+	ttl       time.Duration
+	timeout   time.Duration
+	lastCheck time.Time
+	lastErr   error
+	running   chan struct{} // closed when the in-flight run finishes
+	mu        sync.Mutex
+}
+
+// ReadinessConfig enables a readiness probe on a built-in agent. It is read
+// from the definition's readiness key:
+//
+//	readiness:
+//	  ttl: 30s
+//	  timeout: 5s
+type ReadinessConfig struct {
+	TTL     agent.Duration `json:"ttl"`
+	Timeout agent.Duration `json:"timeout"`
+}
+
+// ReadinessOption configures a ReadinessProbe
+type ReadinessOption func(*ReadinessProbe)
+
+// WithReadinessTTL sets how long a check result is cached
+func WithReadinessTTL(ttl time.Duration) ReadinessOption {
+	return func(p *ReadinessProbe) {
+		if ttl > 0 {
+			p.ttl = ttl
+		}
+	}
+}
+
+// WithReadinessTimeout sets the timeout applied to each probe run
+func WithReadinessTimeout(timeout time.Duration) ReadinessOption {
+	return func(p *ReadinessProbe) {
+		if timeout > 0 {
+			p.timeout = timeout
+		}
+	}
+}
+
+// NewReadinessProbe creates a probe that runs the given checks
+func NewReadinessProbe(checks []ReadinessCheck, opts ...ReadinessOption) *ReadinessProbe {
+	p := &ReadinessProbe{
+		checks:  checks,
+		ttl:     DefaultReadinessTTL,
+		timeout: DefaultReadinessTimeout,
+	}
+	for _, opt := range opts {
+		opt(p)
+	}
+	return p
+}
+
+// readinessProbeFromDef returns a probe running checks when def sets the
+// readiness key, or nil when it does not
+func readinessProbeFromDef(def agent.AgentDef, checks ...ReadinessCheck) (*ReadinessProbe, error) {
+	if _, ok := def.Extra["readiness"]; !ok {
+		return nil, nil
+	}
+	var config ReadinessConfig
+	if err := def.UnmarshalKey("readiness", &config); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal readiness config: %w", err)
+	}
+	return NewReadinessProbe(checks,
+		WithReadinessTTL(config.TTL.Duration),
+		WithReadinessTimeout(config.Timeout.Duration),
+	), nil
+}
+
+// Check returns the cached readiness result, re-running the dependency checks
+// if the cached result has expired. The first failing check is returned.
+// Concurrent callers share a single run, which is not cut short when one of
+// them gives up.
+func (p *ReadinessProbe) Check(ctx context.Context) error {
+	p.mu.Lock()
+	if !p.lastCheck.IsZero() && time.Since(p.lastCheck) < p.ttl {
+		err := p.lastErr
+		p.mu.Unlock()
+		return err
+	}
+	if running := p.running; running != nil {
+		p.mu.Unlock()
+		select {
+		case <-running:
+			return p.LastError()
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	running := make(chan struct{})
+	p.running = running
+	p.mu.Unlock()
+
+	err := p.runChecks(context.WithoutCancel(ctx))
+
+	p.mu.Lock()
+	p.lastErr = err
+	p.lastCheck = time.Now()
+	p.running = nil
+	p.mu.Unlock()
+	close(running)
+	return err
+}
+
+// runChecks runs the dependency checks without holding p.mu
+func (p *ReadinessProbe) runChecks(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, p.timeout)
+	defer cancel()
+
+	for _, c := range p.checks {
+		if err := c.Check(ctx); err != nil {
+			return fmt.Errorf("readiness check %s: %w", c.Name, err)
+		}
+	}
+	return nil
+}
+
+// Ready reports whether all dependency checks passed
+func (p *ReadinessProbe) Ready() bool {
+	return p.Check(context.Background()) == nil
+}
+
+// LastError returns the error from the most recent probe run, if any
+func (p *ReadinessProbe) LastError() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastErr
+}
+
+// Invalidate discards the cached result so the next call re-runs the checks
+func (p *ReadinessProbe) Invalidate() {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.lastCheck = time.Time{}
+}
+
+// ProviderReadinessCheck reports an LLM provider as reachable when it can list models
+func ProviderReadinessCheck(prov provider.Provider) ReadinessCheck {
+	return ReadinessCheck{
+		Name: "provider",
+		Check: func(ctx context.Context) error {
+			if prov == nil {
+				return fmt.Errorf("provider not configured")
+			}
+			if _, err := prov.ListModels(ctx); err != nil {
+				return fmt.Errorf("%s unreachable: %w", prov.Name(), err)
+			}
+			return nil
+		},
+	}
+}
+
+// VectorStoreReadinessCheck reports a vector store as connected when it can return stats
+func VectorStoreReadinessCheck(store vectorstore.VectorStore) ReadinessCheck {
+	return ReadinessCheck{
+		Name: "vectorstore",
+		Check: func(ctx context.Context) error {
+			if store == nil {
+				return fmt.Errorf("vector store not configured")
+			}
+			if _, err := store.Stats(ctx); err != nil {
+				return fmt.Errorf("vector store unreachable: %w", err)
+			}
+			return nil
+		},
+	}
+}
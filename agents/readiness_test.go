@@ -0,0 +1,173 @@
+package agents
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+func TestReadinessProbe_CachesResult(t *testing.T) {
+	var calls atomic.Int32
+	var healthy atomic.Bool
+	healthy.Store(true)
+
+	probe := NewReadinessProbe([]ReadinessCheck{{
+		Name: "dep",
+		Check: func(ctx context.Context) error {
+			calls.Add(1)
+			if !healthy.Load() {
+				return errors.New("down")
+			}
+			return nil
+		},
+	}}, WithReadinessTTL(time.Hour))
+
+	if !probe.Ready() {
+		t.Fatal("Ready() = false, want true")
+	}
+	healthy.Store(false)
+	if !probe.Ready() {
+		t.Error("Ready() should return cached result within TTL")
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("check calls = %d, want 1", got)
+	}
+
+	probe.Invalidate()
+	if probe.Ready() {
+		t.Error("Ready() = true after invalidation with failing dependency, want false")
+	}
+	if probe.LastError() == nil {
+		t.Error("LastError() = nil, want dependency error")
+	}
+}
+
+func TestReadinessProbe_Timeout(t *testing.T) {
+	probe := NewReadinessProbe([]ReadinessCheck{{
+		Name: "slow",
+		Check: func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	}}, WithReadinessTimeout(10*time.Millisecond))
+
+	if err := probe.Check(context.Background()); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Check() error = %v, want deadline exceeded", err)
+	}
+}
+
+func TestBaseAgent_ReadyWithProbe(t *testing.T) {
+	base := NewBaseAgent(agent.AgentDef{Name: "a", Role: "react"})
+	if !base.Ready() {
+		t.Fatal("Ready() = false without probe, want true")
+	}
+
+	base.SetReadinessProbe(NewReadinessProbe([]ReadinessCheck{ProviderReadinessCheck(nil)}))
+	if base.Ready() {
+		t.Error("Ready() = true with unconfigured provider, want false")
+	}
+
+	base.SetReadinessProbe(NewReadinessProbe([]ReadinessCheck{
+		ProviderReadinessCheck(provider.NewMockProvider("mock")),
+	}))
+	if !base.Ready() {
+		t.Errorf("Ready() = false with reachable provider: %v", base.ReadinessProbe().LastError())
+	}
+
+	base.SetReady(false)
+	if base.Ready() {
+		t.Error("Ready() = true after SetReady(false), want false")
+	}
+}
+
+func TestReadinessProbe_ChecksRunWithoutLock(t *testing.T) {
+	release := make(chan struct{})
+	var calls atomic.Int32
+	probe := NewReadinessProbe([]ReadinessCheck{{
+		Name: "slow",
+		Check: func(ctx context.Context) error {
+			calls.Add(1)
+			<-release
+			return nil
+		},
+	}}, WithReadinessTTL(time.Hour))
+
+	results := make(chan error, 2)
+	for range 2 {
+		go func() { results <- probe.Check(context.Background()) }()
+	}
+
+	// The probe stays usable while the check is blocked
+	deadline := time.After(time.Second)
+	for calls.Load() == 0 {
+		select {
+		case <-deadline:
+			t.Fatal("check did not start")
+		default:
+			time.Sleep(time.Millisecond)
+		}
+	}
+	_ = probe.LastError()
+	probe.Invalidate()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := probe.Check(ctx); !errors.Is(err, context.Canceled) {
+		t.Errorf("Check() while running error = %v, want the caller's cancellation", err)
+	}
+
+	close(release)
+	for range 2 {
+		if err := <-results; err != nil {
+			t.Errorf("Check() error = %v", err)
+		}
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("check calls = %d, want concurrent callers to share one run", got)
+	}
+}
+
+// unlistableProvider is a provider that cannot list its models
+type unlistableProvider struct {
+	*provider.MockProvider
+	probes atomic.Int32
+}
+
+func (p *unlistableProvider) ListModels(context.Context) ([]provider.ModelInfo, error) {
+	p.probes.Add(1)
+	return nil, errors.New("connection refused")
+}
+
+func TestBuiltinAgentReadinessConfig(t *testing.T) {
+	t.Setenv("XAI_API_KEY", "test-key")
+	prov := &unlistableProvider{MockProvider: provider.NewMockProvider("xai")}
+	provider.Register("xai", prov)
+
+	plain, err := NewAggregatorAgent(agent.AgentDef{Name: "plain", Model: "grok-beta"}, nil)
+	if err != nil {
+		t.Fatalf("NewAggregatorAgent() error = %v", err)
+	}
+	if !plain.Ready() || prov.probes.Load() != 0 {
+		t.Errorf("Ready() = %v after %d probes, want ready without probing", plain.Ready(), prov.probes.Load())
+	}
+
+	probed, err := NewAggregatorAgent(agent.AgentDef{
+		Name:  "probed",
+		Model: "grok-beta",
+		Extra: map[string]any{"readiness": map[string]any{"ttl": "1h", "timeout": "1s"}},
+	}, nil)
+	if err != nil {
+		t.Fatalf("NewAggregatorAgent() error = %v", err)
+	}
+	if probed.Ready() {
+		t.Error("Ready() = true with an unreachable provider, want false")
+	}
+	if probed.Ready(); prov.probes.Load() != 1 {
+		t.Errorf("provider probes = %d, want 1 cached probe", prov.probes.Load())
+	}
+}
@@ -0,0 +1,186 @@
+package aixgo
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// CircuitState is the state of an agent's circuit breaker
+type CircuitState string
+
+const (
+	// CircuitClosed lets calls through while counting failures
+	CircuitClosed CircuitState = "closed"
+	// CircuitOpen fails calls immediately until the cooldown elapses
+	CircuitOpen CircuitState = "open"
+	// CircuitHalfOpen lets a single trial call through after the cooldown
+	CircuitHalfOpen CircuitState = "half_open"
+)
+
+// CircuitBreakerConfig configures an agent circuit breaker
+type CircuitBreakerConfig struct {
+	// FailureThreshold is how many consecutive failures open the circuit
+	// Default: 5
+	FailureThreshold int
+
+	// Window limits how far apart the consecutive failures may be; a failure
+	// after a longer gap starts a new count (0 = no limit)
+	Window time.Duration
+
+	// Cooldown is how long the circuit stays open before a half-open trial
+	// Default: 30 seconds
+	Cooldown time.Duration
+}
+
+// CircuitBreakerState is a snapshot of an agent's circuit breaker
+type CircuitBreakerState struct {
+	State               CircuitState `json:"state"`
+	ConsecutiveFailures int          `json:"consecutive_failures"`
+	OpenedAt            time.Time    `json:"opened_at,omitempty"`
+	LastFailure         time.Time    `json:"last_failure,omitempty"`
+}
+
+// circuitBreaker tracks consecutive Call failures for one agent
+type circuitBreaker struct {
+	cfg CircuitBreakerConfig
+
+	mu          sync.Mutex
+	state       CircuitState
+	failures    int
+	lastFailure time.Time
+	openedAt    time.Time
+	trialActive bool
+}
+
+func newCircuitBreaker(cfg CircuitBreakerConfig) *circuitBreaker {
+	if cfg.FailureThreshold <= 0 {
+		cfg.FailureThreshold = 5
+	}
+	if cfg.Cooldown <= 0 {
+		cfg.Cooldown = 30 * time.Second
+	}
+	return &circuitBreaker{cfg: cfg, state: CircuitClosed}
+}
+
+// allow reports whether a call may proceed. Once the cooldown of an open
+// circuit has elapsed, exactly one trial call is let through.
+func (cb *circuitBreaker) allow(now time.Time) bool {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	switch cb.state {
+	case CircuitOpen:
+		if now.Sub(cb.openedAt) < cb.cfg.Cooldown {
+			return false
+		}
+		cb.state = CircuitHalfOpen
+		cb.trialActive = true
+		return true
+	case CircuitHalfOpen:
+		if cb.trialActive {
+			return false
+		}
+		cb.trialActive = true
+		return true
+	default:
+		return true
+	}
+}
+
+// record updates the breaker with a call's outcome. A caller cancelling its
+// own context says nothing about the agent and is not counted.
+func (cb *circuitBreaker) record(err error, now time.Time) {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+
+	if cb.state == CircuitHalfOpen {
+		cb.trialActive = false
+	}
+	if errors.Is(err, context.Canceled) {
+		return
+	}
+	if err == nil {
+		cb.state = CircuitClosed
+		cb.failures = 0
+		return
+	}
+
+	if cb.cfg.Window > 0 && !cb.lastFailure.IsZero() && now.Sub(cb.lastFailure) > cb.cfg.Window {
+		cb.failures = 0
+	}
+	cb.failures++
+	cb.lastFailure = now
+	if cb.state == CircuitHalfOpen || cb.failures >= cb.cfg.FailureThreshold {
+		cb.state = CircuitOpen
+		cb.openedAt = now
+	}
+}
+
+func (cb *circuitBreaker) snapshot() CircuitBreakerState {
+	cb.mu.Lock()
+	defer cb.mu.Unlock()
+	return CircuitBreakerState{
+		State:               cb.state,
+		ConsecutiveFailures: cb.failures,
+		OpenedAt:            cb.openedAt,
+		LastFailure:         cb.lastFailure,
+	}
+}
+
+// WithCircuitBreaker sets the default circuit breaker applied to every agent
+// called through the runtime. Use SetCircuitBreaker to override it per agent.
+func WithCircuitBreaker(cfg CircuitBreakerConfig) RuntimeOption {
+	return func(rc *RuntimeConfig) {
+		rc.CircuitBreaker = &cfg
+	}
+}
+
+// SetCircuitBreaker configures the circuit breaker for an agent, replacing
+// any existing breaker and its state.
+func (r *Runtime) SetCircuitBreaker(agentName string, cfg CircuitBreakerConfig) {
+	r.breakersMu.Lock()
+	defer r.breakersMu.Unlock()
+	r.breakers[agentName] = newCircuitBreaker(cfg)
+}
+
+// CircuitBreakerState returns the state of an agent's circuit breaker, or
+// false if no breaker applies to the agent.
+func (r *Runtime) CircuitBreakerState(agentName string) (CircuitBreakerState, bool) {
+	cb := r.breakerFor(agentName)
+	if cb == nil {
+		return CircuitBreakerState{}, false
+	}
+	return cb.snapshot(), true
+}
+
+// CircuitBreakerStates returns the state of every agent's circuit breaker
+// that has been configured or used.
+func (r *Runtime) CircuitBreakerStates() map[string]CircuitBreakerState {
+	r.breakersMu.Lock()
+	defer r.breakersMu.Unlock()
+
+	states := make(map[string]CircuitBreakerState, len(r.breakers))
+	for name, cb := range r.breakers {
+		states[name] = cb.snapshot()
+	}
+	return states
+}
+
+// breakerFor returns the agent's breaker, creating it from the default
+// configuration on first use. It returns nil if no breaker applies.
+func (r *Runtime) breakerFor(agentName string) *circuitBreaker {
+	r.breakersMu.Lock()
+	defer r.breakersMu.Unlock()
+
+	if cb, ok := r.breakers[agentName]; ok {
+		return cb
+	}
+	if r.config.CircuitBreaker == nil {
+		return nil
+	}
+	cb := newCircuitBreaker(*r.config.CircuitBreaker)
+	r.breakers[agentName] = cb
+	return cb
+}
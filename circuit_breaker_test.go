@@ -0,0 +1,128 @@
+package aixgo
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// flakyAgent fails while failing is set and counts its executions
+type flakyAgent struct {
+	failing atomic.Bool
+	calls   atomic.Int32
+}
+
+func (a *flakyAgent) Name() string                    { return "flaky" }
+func (a *flakyAgent) Role() string                    { return "test" }
+func (a *flakyAgent) Start(ctx context.Context) error { return nil }
+func (a *flakyAgent) Stop(ctx context.Context) error  { return nil }
+func (a *flakyAgent) Ready() bool                     { return true }
+func (a *flakyAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	a.calls.Add(1)
+	if a.failing.Load() {
+		return nil, errors.New("upstream unavailable")
+	}
+	return &agent.Message{Message: &pb.Message{Payload: "ok"}}, nil
+}
+
+func TestRuntime_CircuitBreaker(t *testing.T) {
+	ctx := context.Background()
+	flaky := &flakyAgent{}
+	flaky.failing.Store(true)
+
+	rt := NewRuntime()
+	if err := rt.Register(flaky); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = rt.Stop(ctx) }()
+
+	if _, ok := rt.CircuitBreakerState("flaky"); ok {
+		t.Fatal("CircuitBreakerState() reported a breaker before one was configured")
+	}
+	rt.SetCircuitBreaker("flaky", CircuitBreakerConfig{FailureThreshold: 3, Cooldown: 50 * time.Millisecond})
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+
+	// Three failures trip the breaker
+	for range 3 {
+		if _, err := rt.Call(ctx, "flaky", input); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("Call() error = %v, want the agent's failure", err)
+		}
+	}
+	state, _ := rt.CircuitBreakerState("flaky")
+	if state.State != CircuitOpen || state.ConsecutiveFailures != 3 {
+		t.Fatalf("state = %+v, want open after 3 failures", state)
+	}
+
+	// Open: calls fail fast without reaching the agent
+	if _, err := rt.Call(ctx, "flaky", input); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Call() error = %v, want ErrCircuitOpen", err)
+	}
+	if n := flaky.calls.Load(); n != 3 {
+		t.Errorf("agent executed %d times, want 3", n)
+	}
+
+	// A failed half-open trial reopens the circuit
+	time.Sleep(60 * time.Millisecond)
+	if _, err := rt.Call(ctx, "flaky", input); err == nil || errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("trial Call() error = %v, want the agent's failure", err)
+	}
+	if _, err := rt.Call(ctx, "flaky", input); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("Call() after failed trial error = %v, want ErrCircuitOpen", err)
+	}
+
+	// After the cooldown a successful trial closes it again
+	flaky.failing.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if _, err := rt.Call(ctx, "flaky", input); err != nil {
+		t.Fatalf("trial Call() error = %v", err)
+	}
+	state, _ = rt.CircuitBreakerState("flaky")
+	if state.State != CircuitClosed || state.ConsecutiveFailures != 0 {
+		t.Errorf("state = %+v, want closed after a successful trial", state)
+	}
+	if states := rt.CircuitBreakerStates(); len(states) != 1 {
+		t.Errorf("CircuitBreakerStates() = %v, want one breaker", states)
+	}
+}
+
+func TestCircuitBreaker_Window(t *testing.T) {
+	cb := newCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 2, Window: time.Minute})
+	start := time.Now()
+	failure := errors.New("boom")
+
+	cb.record(failure, start)
+	cb.record(failure, start.Add(2*time.Minute)) // Too far apart to count together
+	if cb.snapshot().State != CircuitClosed {
+		t.Fatalf("state = %v, want closed when failures fall outside the window", cb.snapshot().State)
+	}
+	cb.record(failure, start.Add(2*time.Minute+time.Second))
+	if cb.snapshot().State != CircuitOpen {
+		t.Fatalf("state = %v, want open", cb.snapshot().State)
+	}
+
+	// Only one half-open trial runs at a time, and cancellation is neutral
+	cooled := start.Add(time.Hour)
+	if !cb.allow(cooled) || cb.allow(cooled) {
+		t.Fatal("want exactly one half-open trial")
+	}
+	cb.record(context.Canceled, cooled)
+	if cb.snapshot().State != CircuitHalfOpen || !cb.allow(cooled) {
+		t.Error("a canceled trial should leave the circuit half-open for another trial")
+	}
+}
+
+func TestRuntime_CircuitBreaker_Default(t *testing.T) {
+	rt := NewRuntime(WithCircuitBreaker(CircuitBreakerConfig{FailureThreshold: 1}))
+	state, ok := rt.CircuitBreakerState("any")
+	if !ok || state.State != CircuitClosed {
+		t.Errorf("CircuitBreakerState() = %+v, %v; want closed default breaker", state, ok)
+	}
+}
@@ -6,18 +6,21 @@ import (
 	"log"
 	"os"
 	"os/signal"
+	"strings"
 	"syscall"
 	"time"
 
 	"github.com/aixgo-dev/aixgo"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 	"github.com/aixgo-dev/aixgo/pkg/observability"
 	"github.com/spf13/cobra"
 )
 
 var (
-	configFile string
-	httpPort   int
-	logLevel   string
+	configFile     string
+	httpPort       int
+	logLevel       string
+	checkProviders []string
 )
 
 // runCmd represents the run command for orchestrating agents.
@@ -41,6 +44,7 @@ func init() {
 	runCmd.Flags().StringVarP(&configFile, "config", "c", getEnv("CONFIG_FILE", "config/agents.yaml"), "Agent configuration file")
 	runCmd.Flags().IntVar(&httpPort, "http-port", getEnvInt("PORT", 8080), "HTTP server port for observability")
 	runCmd.Flags().StringVar(&logLevel, "log-level", getEnv("LOG_LEVEL", "info"), "Log level (debug, info, warn, error)")
+	runCmd.Flags().StringSliceVar(&checkProviders, "check-providers", splitEnv("CHECK_PROVIDERS"), "LLM providers to probe in health checks, as name or name:model (e.g. openai,ollama:llama3.2)")
 
 	_ = runCmd.RegisterFlagCompletionFunc("config", func(_ *cobra.Command, _ []string, _ string) ([]string, cobra.ShellCompDirective) {
 		return []string{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
@@ -60,6 +64,17 @@ func runOrchestrator(_ *cobra.Command, _ []string) error {
 
 	// Register health checks
 	healthChecker.RegisterCheck(observability.PingCheck())
+	for _, entry := range checkProviders {
+		name, model, _ := strings.Cut(entry, ":")
+		if model == "" && name == "ollama" {
+			return fmt.Errorf("health check for ollama needs a model, e.g. ollama:llama3.2")
+		}
+		prov, err := provider.CreateProvider(name, map[string]any{"model": model})
+		if err != nil {
+			return fmt.Errorf("failed to create provider %s for health check: %w", name, err)
+		}
+		healthChecker.RegisterCheck(observability.ProviderCheck(prov, model))
+	}
 
 	// Start observability server
 	obsServer := observability.NewServer(httpPort)
@@ -119,3 +134,10 @@ func getEnvInt(key string, defaultValue int) int {
 	}
 	return defaultValue
 }
+
+func splitEnv(key string) []string {
+	if value := os.Getenv(key); value != "" {
+		return strings.Split(value, ",")
+	}
+	return nil
+}
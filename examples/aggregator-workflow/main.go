@@ -156,6 +156,9 @@ func loadConfig(path string) (*WorkflowConfig, error) {
 	return &config, nil
 }
 
+// memoryRetention is how long semantic memories are kept between runs
+const memoryRetention = 24 * time.Hour
+
 // NewResearchSynthesisSystem creates a new research synthesis system
 func NewResearchSynthesisSystem(config *WorkflowConfig) (*ResearchSynthesisSystem, error) {
 	// Initialize LLM provider
@@ -185,30 +188,16 @@ func NewResearchSynthesisSystem(config *WorkflowConfig) (*ResearchSynthesisSyste
 
 // RunResearchWorkflow executes the multi-agent research synthesis
 func (s *ResearchSynthesisSystem) RunResearchWorkflow(ctx context.Context) error {
-	// Phase 1: Deploy Expert Agents
-	log.Println("Phase 1: Deploying Expert Agents...")
-	expertOutputs := make(chan *ExpertAnalysis, len(s.config.ExpertAgents))
-
-	for _, expertConfig := range s.config.ExpertAgents {
-		go s.runExpertAgent(ctx, expertConfig, expertOutputs)
+	// Drop memories from earlier runs so long-lived processes stay bounded
+	if pruned := s.memory.PruneOlderThan(memoryRetention); pruned > 0 {
+		log.Printf("Pruned %d memories older than %s", pruned, memoryRetention)
 	}
 
-	// Collect expert analyses
-	var analyses []*ExpertAnalysis
-	timeout := time.After(time.Duration(s.config.AggregatorAgent.TimeoutMs) * time.Millisecond)
-
-CollectLoop:
-	for i := 0; i < len(s.config.ExpertAgents); i++ {
-		select {
-		case analysis := <-expertOutputs:
-			analyses = append(analyses, analysis)
-			log.Printf("Received analysis from %s agent", analysis.AgentRole)
-		case <-timeout:
-			log.Printf("Timeout waiting for expert agents")
-			break CollectLoop
-		case <-ctx.Done():
-			return ctx.Err()
-		}
+	// Phase 1: Deploy Expert Agents
+	log.Println("Phase 1: Deploying Expert Agents...")
+	analyses, err := s.collectExpertAnalyses(ctx)
+	if err != nil {
+		return err
 	}
 
 	// Phase 2: Demonstrate Different Aggregation Strategies
@@ -259,6 +248,37 @@ CollectLoop:
 	return nil
 }
 
+// collectExpertAnalyses runs the expert agents in parallel and gathers the
+// analyses that arrive before the aggregator timeout. Experts still running
+// when collection stops are canceled so their goroutines exit.
+func (s *ResearchSynthesisSystem) collectExpertAnalyses(ctx context.Context) ([]*ExpertAnalysis, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	expertOutputs := make(chan *ExpertAnalysis, len(s.config.ExpertAgents))
+	for _, expertConfig := range s.config.ExpertAgents {
+		go s.runExpertAgent(ctx, expertConfig, expertOutputs)
+	}
+
+	var analyses []*ExpertAnalysis
+	timeout := time.NewTimer(time.Duration(s.config.AggregatorAgent.TimeoutMs) * time.Millisecond)
+	defer timeout.Stop()
+
+	for i := 0; i < len(s.config.ExpertAgents); i++ {
+		select {
+		case analysis := <-expertOutputs:
+			analyses = append(analyses, analysis)
+			log.Printf("Received analysis from %s agent", analysis.AgentRole)
+		case <-timeout.C:
+			log.Printf("Timeout waiting for expert agents")
+			return analyses, nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return analyses, nil
+}
+
 // runExpertAgent simulates an expert agent analyzing the research topic
 func (s *ResearchSynthesisSystem) runExpertAgent(ctx context.Context, config ExpertAgentConfig, output chan<- *ExpertAnalysis) {
 	// Create expert-specific prompt
@@ -302,7 +322,9 @@ Specific aspects to consider: %v`,
 
 	resp, err := s.provider.CreateCompletion(ctx, req)
 	if err != nil {
-		log.Printf("Expert agent %s failed: %v", config.Name, err)
+		if ctx.Err() == nil {
+			log.Printf("Expert agent %s failed: %v", config.Name, err)
+		}
 		return
 	}
 
@@ -322,7 +344,11 @@ Specific aspects to consider: %v`,
 	// Extract key findings (simplified - in production, use structured output)
 	analysis.KeyFindings = s.extractKeyFindings(resp.Content)
 
-	output <- analysis
+	// Don't block if the collector has already stopped listening
+	select {
+	case output <- analysis:
+	case <-ctx.Done():
+	}
 }
 
 // performConsensusAggregation demonstrates consensus-based aggregation
@@ -672,6 +698,8 @@ func initializeLLMProvider(config LLMConfig) (provider.Provider, error) {
 		return provider.NewOpenAIProvider(config.APIKey, "https://api.openai.com/v1"), nil
 	case "anthropic":
 		return provider.NewAnthropicProvider(config.APIKey, "https://api.anthropic.com/v1"), nil
+	case "gemini":
+		return provider.NewGeminiProvider(config.APIKey, "https://generativelanguage.googleapis.com/v1beta"), nil
 	default:
 		return provider.NewMockProvider("mock-model"), nil
 	}
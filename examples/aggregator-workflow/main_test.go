@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"go.uber.org/goleak"
+)
+
+// stallingProvider answers the expert whose role is fast and blocks every
+// other completion until its context is canceled
+type stallingProvider struct {
+	*provider.MockProvider
+	fast string
+}
+
+func (p *stallingProvider) CreateCompletion(ctx context.Context, req provider.CompletionRequest) (*provider.CompletionResponse, error) {
+	if strings.Contains(req.Messages[0].Content, p.fast) {
+		return &provider.CompletionResponse{Content: p.fast + " analysis"}, nil
+	}
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+func newTestSystem(fast string, roles ...string) *ResearchSynthesisSystem {
+	config := &WorkflowConfig{
+		Topic:           ResearchTopic{Title: "Test topic"},
+		AggregatorAgent: AggregatorWorkflowConfig{TimeoutMs: 50},
+	}
+	for _, role := range roles {
+		config.ExpertAgents = append(config.ExpertAgents, ExpertAgentConfig{Name: role, Role: role, Weight: 0.5})
+	}
+	return &ResearchSynthesisSystem{
+		config:   config,
+		topic:    config.Topic,
+		provider: &stallingProvider{MockProvider: provider.NewMockProvider("test"), fast: fast},
+	}
+}
+
+func TestCollectExpertAnalyses_TimeoutCancelsExperts(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	s := newTestSystem("Technical", "Technical", "Business", "Ethics")
+	analyses, err := s.collectExpertAnalyses(context.Background())
+	if err != nil {
+		t.Fatalf("collectExpertAnalyses() error = %v", err)
+	}
+	if len(analyses) != 1 || analyses[0].AgentRole != "Technical" {
+		t.Errorf("analyses = %+v, want only the Technical expert", analyses)
+	}
+}
+
+func TestCollectExpertAnalyses_ParentCanceled(t *testing.T) {
+	defer goleak.VerifyNone(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	s := newTestSystem("none", "Technical", "Business")
+	if _, err := s.collectExpertAnalyses(ctx); err != context.Canceled {
+		t.Errorf("collectExpertAnalyses() error = %v, want context.Canceled", err)
+	}
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"sync"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+// ProcessTickets classifies tickets with up to concurrency workers. Results
+// and errors are in input order: for each ticket either results[i] is set or
+// errs[i] is non-nil.
+func (w *WorkflowOrchestrator) ProcessTickets(ctx context.Context, tickets []TicketData, concurrency int) ([]ClassificationOutput, []error) {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+
+	results := make([]ClassificationOutput, len(tickets))
+	errs := make([]error, len(tickets))
+
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for range min(concurrency, len(tickets)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				result, err := w.ProcessTicket(ctx, tickets[i])
+				if err != nil {
+					errs[i] = err
+					continue
+				}
+				results[i] = *result
+			}
+		}()
+	}
+
+	for i := range tickets {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	return results, errs
+}
+
+// awaitClassification registers a waiter for the classification of ticketID,
+// starting the dispatcher on first use
+func (w *WorkflowOrchestrator) awaitClassification(ticketID string) (<-chan *agent.Message, error) {
+	var dispatchErr error
+	w.dispatch.Do(func() {
+		outputs, err := w.runtime.Recv("classification_output")
+		if err != nil {
+			dispatchErr = fmt.Errorf("failed to setup result channel: %w", err)
+			return
+		}
+		go w.dispatchClassifications(outputs)
+	})
+	if dispatchErr != nil {
+		return nil, dispatchErr
+	}
+
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.pending == nil {
+		w.pending = make(map[string]chan *agent.Message)
+	}
+	if _, exists := w.pending[ticketID]; exists {
+		return nil, fmt.Errorf("ticket %s is already being processed", ticketID)
+	}
+	ch := make(chan *agent.Message, 1)
+	w.pending[ticketID] = ch
+	return ch, nil
+}
+
+// cancelClassification removes the waiter for ticketID
+func (w *WorkflowOrchestrator) cancelClassification(ticketID string) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	delete(w.pending, ticketID)
+}
+
+// dispatchClassifications delivers each classifier output to the waiter for
+// its ticket ID
+func (w *WorkflowOrchestrator) dispatchClassifications(outputs <-chan *agent.Message) {
+	for msg := range outputs {
+		if msg == nil || msg.Message == nil {
+			continue
+		}
+		w.mu.Lock()
+		ch, ok := w.pending[msg.Id]
+		delete(w.pending, msg.Id)
+		w.mu.Unlock()
+
+		if !ok {
+			log.Printf("Dropping classification for unknown ticket %s", msg.Id)
+			continue
+		}
+		ch <- msg
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo"
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// fakeClassifier answers every ticket on the runtime's input channel,
+// echoing the ticket ID like the classifier agent does
+func fakeClassifier(t *testing.T, rt agent.Runtime) {
+	t.Helper()
+	inputs, err := rt.Recv("ticket_input")
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	payload, _ := json.Marshal(ClassificationResult{Category: "technical_issue", Confidence: 0.9})
+	go func() {
+		for msg := range inputs {
+			out := &agent.Message{Message: &pb.Message{Id: msg.Id, Payload: string(payload)}}
+			if err := rt.Send("classification_output", out); err != nil {
+				return
+			}
+		}
+	}()
+}
+
+func TestProcessTickets_Concurrent(t *testing.T) {
+	rt := aixgo.NewRuntime()
+	fakeClassifier(t, rt)
+	w := &WorkflowOrchestrator{runtime: rt}
+
+	tickets := make([]TicketData, 100)
+	for i := range tickets {
+		tickets[i] = TicketData{ID: fmt.Sprintf("TICKET-%03d", i), Subject: "API errors"}
+	}
+
+	results, errs := w.ProcessTickets(context.Background(), tickets, 8)
+	if len(results) != len(tickets) || len(errs) != len(tickets) {
+		t.Fatalf("got %d results and %d errors, want %d each", len(results), len(errs), len(tickets))
+	}
+	for i, result := range results {
+		if errs[i] != nil {
+			t.Fatalf("ticket %d error = %v", i, errs[i])
+		}
+		if result.TicketID != tickets[i].ID {
+			t.Fatalf("results[%d] is %s, want input order", i, result.TicketID)
+		}
+	}
+	if len(w.results) != len(tickets) {
+		t.Errorf("recorded %d results, want %d", len(w.results), len(tickets))
+	}
+}
+
+func TestProcessTickets_ContextCanceled(t *testing.T) {
+	// No classifier is running, so every ticket waits until cancellation
+	w := &WorkflowOrchestrator{runtime: aixgo.NewRuntime()}
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tickets := []TicketData{{ID: "TICKET-001"}, {ID: "TICKET-002"}}
+	_, errs := w.ProcessTickets(ctx, tickets, 4)
+	for i, err := range errs {
+		if err == nil {
+			t.Errorf("ticket %d error = nil, want the context error", i)
+		}
+	}
+}
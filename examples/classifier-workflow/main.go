@@ -2,16 +2,18 @@ package main
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
 	"log"
 	"os"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/aixgo-dev/aixgo"
+	"github.com/aixgo-dev/aixgo/agents"
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/pkg/config"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 	pb "github.com/aixgo-dev/aixgo/proto"
 )
 
@@ -26,35 +28,25 @@ type TicketData struct {
 
 // ClassificationOutput represents the enhanced classification result
 type ClassificationOutput struct {
-	TicketID       string                `json:"ticket_id"`
-	Classification ClassificationResult  `json:"classification"`
-	Routing        RoutingRecommendation `json:"routing"`
-	Priority       PriorityAssessment    `json:"priority"`
-	SentimentScore float64               `json:"sentiment_score"`
-	ProcessedAt    time.Time             `json:"processed_at"`
+	TicketID           string                `json:"ticket_id"`
+	Classification     ClassificationResult  `json:"classification"`
+	Routing            RoutingRecommendation `json:"routing"`
+	Priority           PriorityAssessment    `json:"priority"`
+	SentimentScore     float64               `json:"sentiment_score"` // 0 (negative) to 1 (positive), 0.5 is neutral
+	SentimentRationale string                `json:"sentiment_rationale,omitempty"`
+	ProcessedAt        time.Time             `json:"processed_at"`
 }
 
-// ClassificationResult from the Classifier agent
-type ClassificationResult struct {
-	Category       string             `json:"category"`
-	Confidence     float64            `json:"confidence"`
-	Reasoning      string             `json:"reasoning"`
-	Alternatives   []AlternativeClass `json:"alternatives,omitempty"`
-	TokensUsed     int                `json:"tokens_used"`
-	PromptStrategy string             `json:"prompt_strategy"`
-}
-
-// AlternativeClass represents alternative classifications
-type AlternativeClass struct {
-	Category   string  `json:"category"`
-	Confidence float64 `json:"confidence"`
-}
+// ClassificationResult is the schema-validated output of the built-in
+// classifier agent
+type ClassificationResult = agents.ClassificationResult
 
 // RoutingRecommendation suggests which team should handle the ticket
 type RoutingRecommendation struct {
 	Team       string   `json:"team"`
 	Expertise  []string `json:"expertise_required"`
 	Escalation bool     `json:"escalation_needed"`
+	SLAHours   int      `json:"sla_hours,omitempty"`
 }
 
 // PriorityAssessment determines ticket urgency
@@ -126,10 +118,19 @@ var SampleTickets = []TicketData{
 
 // WorkflowOrchestrator manages the classification workflow
 type WorkflowOrchestrator struct {
-	config  *config.Config
-	runtime agent.Runtime
-	agents  map[string]agent.Agent
-	results []ClassificationOutput
+	config   *config.Config
+	settings WorkflowSettings
+	output   OutputConfig
+	runtime  agent.Runtime
+	agents   map[string]agent.Agent
+	provider provider.Provider // scores sentiment in llm mode
+	routing  RoutingConfig
+	rules    []priorityRule
+
+	mu       sync.Mutex
+	pending  map[string]chan *agent.Message // classification waiters by ticket ID
+	dispatch sync.Once
+	results  []ClassificationOutput
 }
 
 // NewWorkflowOrchestrator creates a new workflow orchestrator
@@ -139,6 +140,17 @@ func NewWorkflowOrchestrator(configPath string) (*WorkflowOrchestrator, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load config: %w", err)
 	}
+	settings, err := loadSettings(configPath)
+	if err != nil {
+		return nil, err
+	}
+
+	var sentimentProvider provider.Provider
+	if settings.Workflow.SentimentMode == SentimentModeLLM {
+		if sentimentProvider, err = newSentimentProvider(settings.Workflow.SentimentModel); err != nil {
+			return nil, fmt.Errorf("failed to create sentiment provider: %w", err)
+		}
+	}
 
 	// Initialize runtime
 	rt := aixgo.NewRuntime()
@@ -163,10 +175,15 @@ func NewWorkflowOrchestrator(configPath string) (*WorkflowOrchestrator, error) {
 	}
 
 	return &WorkflowOrchestrator{
-		config:  cfg,
-		runtime: rt,
-		agents:  agents,
-		results: []ClassificationOutput{},
+		config:   cfg,
+		settings: settings.Workflow,
+		output:   settings.Output,
+		runtime:  rt,
+		agents:   agents,
+		provider: sentimentProvider,
+		routing:  settings.Routing,
+		rules:    defaultPriorityRules(),
+		results:  []ClassificationOutput{},
 	}, nil
 }
 
@@ -186,32 +203,25 @@ func (w *WorkflowOrchestrator) ProcessTicket(ctx context.Context, ticket TicketD
 		},
 	}
 
-	// Send through the input channel
-	if err := w.runtime.Send("ticket_input", inputMsg); err != nil {
-		return nil, fmt.Errorf("failed to send ticket: %w", err)
-	}
-
-	// Receive classification result
-	resultChan, err := w.runtime.Recv("classification_output")
+	// Wait for the result by ticket ID so concurrent tickets get their own
+	resultChan, err := w.awaitClassification(ticket.ID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to setup result channel: %w", err)
+		return nil, err
 	}
+	defer w.cancelClassification(ticket.ID)
 
-	// Wait for result with timeout
-	select {
-	case result := <-resultChan:
-		return w.processClassificationResult(ticket, result)
-	case <-time.After(30 * time.Second):
-		return nil, fmt.Errorf("classification timeout for ticket %s", ticket.ID)
-	case <-ctx.Done():
-		return nil, ctx.Err()
+	// Send and wait for the result, retrying transient failures
+	result, err := w.classifyWithRetry(ctx, inputMsg, resultChan)
+	if err != nil {
+		return nil, fmt.Errorf("ticket %s: %w", ticket.ID, err)
 	}
+	return w.processClassificationResult(ctx, ticket, result)
 }
 
 // processClassificationResult enriches the classification with routing and priority
-func (w *WorkflowOrchestrator) processClassificationResult(ticket TicketData, msg *agent.Message) (*ClassificationOutput, error) {
+func (w *WorkflowOrchestrator) processClassificationResult(ctx context.Context, ticket TicketData, msg *agent.Message) (*ClassificationOutput, error) {
 	var classification ClassificationResult
-	if err := json.Unmarshal([]byte(msg.Payload), &classification); err != nil {
+	if err := msg.DecodeJSON(&classification); err != nil {
 		return nil, fmt.Errorf("failed to parse classification: %w", err)
 	}
 
@@ -221,161 +231,25 @@ func (w *WorkflowOrchestrator) processClassificationResult(ticket TicketData, ms
 	// Assess priority based on content and classification
 	priority := w.assessPriority(ticket, classification)
 
-	// Calculate sentiment (simplified for demo)
-	sentiment := w.analyzeSentiment(ticket.Description)
+	// Score sentiment by keyword or with the LLM, per sentiment_mode
+	sentiment, rationale := w.scoreSentiment(ctx, ticket.Description)
 
 	output := &ClassificationOutput{
-		TicketID:       ticket.ID,
-		Classification: classification,
-		Routing:        routing,
-		Priority:       priority,
-		SentimentScore: sentiment,
-		ProcessedAt:    time.Now(),
+		TicketID:           ticket.ID,
+		Classification:     classification,
+		Routing:            routing,
+		Priority:           priority,
+		SentimentScore:     sentiment,
+		SentimentRationale: rationale,
+		ProcessedAt:        time.Now(),
 	}
 
+	w.mu.Lock()
 	w.results = append(w.results, *output)
+	w.mu.Unlock()
 	return output, nil
 }
 
-// determineRouting suggests the appropriate team based on classification
-func (w *WorkflowOrchestrator) determineRouting(classification ClassificationResult) RoutingRecommendation {
-	routingMap := map[string]RoutingRecommendation{
-		"technical_issue": {
-			Team:       "Technical Support L2",
-			Expertise:  []string{"API", "Integration", "Performance"},
-			Escalation: classification.Confidence < 0.8,
-		},
-		"billing_inquiry": {
-			Team:       "Billing Department",
-			Expertise:  []string{"Invoicing", "Payments", "Subscriptions"},
-			Escalation: false,
-		},
-		"account_access": {
-			Team:       "Account Security Team",
-			Expertise:  []string{"Authentication", "Password Reset", "Security"},
-			Escalation: true, // Always escalate security issues
-		},
-		"feature_request": {
-			Team:       "Product Management",
-			Expertise:  []string{"Product Roadmap", "Feature Analysis"},
-			Escalation: false,
-		},
-		"bug_report": {
-			Team:       "Engineering Team",
-			Expertise:  []string{"Bug Triage", "QA", "Development"},
-			Escalation: classification.Confidence > 0.9,
-		},
-		"positive_feedback": {
-			Team:       "Customer Success",
-			Expertise:  []string{"Customer Relations", "Feedback Management"},
-			Escalation: false,
-		},
-	}
-
-	if routing, exists := routingMap[classification.Category]; exists {
-		return routing
-	}
-
-	// Default routing for unknown categories
-	return RoutingRecommendation{
-		Team:       "General Support",
-		Expertise:  []string{"Customer Service"},
-		Escalation: classification.Confidence < 0.7,
-	}
-}
-
-// assessPriority determines ticket priority based on multiple factors
-func (w *WorkflowOrchestrator) assessPriority(ticket TicketData, classification ClassificationResult) PriorityAssessment {
-	factors := []string{}
-	score := 0.5 // Base score
-
-	// Check for urgent keywords
-	urgentKeywords := []string{"urgent", "asap", "immediately", "critical", "production", "down", "blocked"}
-	for _, keyword := range urgentKeywords {
-		if containsIgnoreCase(ticket.Description, keyword) || containsIgnoreCase(ticket.Subject, keyword) {
-			score += 0.2
-			factors = append(factors, fmt.Sprintf("Contains urgent keyword: %s", keyword))
-			break
-		}
-	}
-
-	// Category-based priority adjustments
-	categoryPriority := map[string]float64{
-		"account_access":    0.3, // Security issues are high priority
-		"technical_issue":   0.2, // Technical issues need quick resolution
-		"billing_inquiry":   0.2, // Billing issues affect revenue
-		"bug_report":        0.1,
-		"feature_request":   -0.1, // Feature requests are lower priority
-		"positive_feedback": -0.2, // Feedback is lowest priority
-	}
-
-	if adjustment, exists := categoryPriority[classification.Category]; exists {
-		score += adjustment
-		factors = append(factors, fmt.Sprintf("Category priority adjustment: %s", classification.Category))
-	}
-
-	// Confidence-based adjustment
-	if classification.Confidence < 0.7 {
-		score += 0.1
-		factors = append(factors, "Low classification confidence requires review")
-	}
-
-	// Normalize score
-	if score > 1.0 {
-		score = 1.0
-	} else if score < 0.0 {
-		score = 0.0
-	}
-
-	// Determine level based on score
-	level := "low"
-	if score >= 0.8 {
-		level = "critical"
-	} else if score >= 0.6 {
-		level = "high"
-	} else if score >= 0.4 {
-		level = "medium"
-	}
-
-	return PriorityAssessment{
-		Level:   level,
-		Score:   score,
-		Factors: factors,
-	}
-}
-
-// analyzeSentiment performs basic sentiment analysis (simplified for demo)
-func (w *WorkflowOrchestrator) analyzeSentiment(text string) float64 {
-	positiveWords := []string{"thank", "great", "excellent", "happy", "wonderful", "appreciate", "love"}
-	negativeWords := []string{"angry", "frustrated", "terrible", "awful", "hate", "disappointed", "unacceptable"}
-
-	score := 0.5 // Neutral baseline
-	wordCount := 0
-
-	for _, word := range positiveWords {
-		if containsIgnoreCase(text, word) {
-			score += 0.1
-			wordCount++
-		}
-	}
-
-	for _, word := range negativeWords {
-		if containsIgnoreCase(text, word) {
-			score -= 0.1
-			wordCount++
-		}
-	}
-
-	// Normalize score
-	if score > 1.0 {
-		score = 1.0
-	} else if score < 0.0 {
-		score = 0.0
-	}
-
-	return score
-}
-
 // containsIgnoreCase checks if text contains substring (case-insensitive)
 func containsIgnoreCase(text, substr string) bool {
 	return strings.Contains(strings.ToLower(text), strings.ToLower(substr))
@@ -427,6 +301,9 @@ func (w *WorkflowOrchestrator) GenerateReport() string {
 			result.Priority.Level, result.Priority.Score)
 		report += fmt.Sprintf("  Routing: %s\n", result.Routing.Team)
 		report += fmt.Sprintf("  Sentiment Score: %.2f\n", result.SentimentScore)
+		if result.SentimentRationale != "" {
+			report += fmt.Sprintf("  Sentiment Rationale: %s\n", result.SentimentRationale)
+		}
 
 		if result.Classification.Reasoning != "" {
 			report += fmt.Sprintf("  AI Reasoning: %s\n", result.Classification.Reasoning)
@@ -480,15 +357,16 @@ func main() {
 	// Process sample tickets
 	fmt.Println("Processing sample customer support tickets...")
 
+	results, errs := workflow.ProcessTickets(ctx, SampleTickets, workflow.settings.Concurrency)
 	for i, ticket := range SampleTickets {
-		fmt.Printf("[%d/%d] Processing ticket %s: %s\n",
+		fmt.Printf("[%d/%d] Processed ticket %s: %s\n",
 			i+1, len(SampleTickets), ticket.ID, ticket.Subject)
 
-		result, err := workflow.ProcessTicket(ctx, ticket)
-		if err != nil {
-			log.Printf("Error processing ticket %s: %v", ticket.ID, err)
+		if errs[i] != nil {
+			log.Printf("Error processing ticket %s: %v", ticket.ID, errs[i])
 			continue
 		}
+		result := results[i]
 
 		// Display immediate result
 		fmt.Printf("  ✓ Classified as: %s (Confidence: %.2f)\n",
@@ -509,16 +387,10 @@ func main() {
 	fmt.Println(report)
 
 	// Save results to file
-	resultsFile := "classification_results.json"
-	resultsJSON, err := json.MarshalIndent(workflow.results, "", "  ")
-	if err != nil {
-		log.Printf("Failed to marshal results: %v", err)
+	if err := workflow.SaveResults(); err != nil {
+		log.Printf("Failed to save results: %v", err)
 	} else {
-		if err := os.WriteFile(resultsFile, resultsJSON, 0600); err != nil {
-			log.Printf("Failed to save results: %v", err)
-		} else {
-			fmt.Printf("\nResults saved to %s\n", resultsFile)
-		}
+		fmt.Printf("\nResults saved to %s\n", workflow.output.FilePath)
 	}
 
 	fmt.Println("\nWorkflow completed successfully!")
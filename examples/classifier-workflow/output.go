@@ -0,0 +1,115 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+)
+
+// OutputFormat selects how classification results are written
+type OutputFormat string
+
+const (
+	// OutputFormatText writes the human-readable report
+	OutputFormatText OutputFormat = "text"
+	// OutputFormatJSON writes the full results as indented JSON
+	OutputFormatJSON OutputFormat = "json"
+	// OutputFormatCSV writes one RFC 4180 row per ticket, for spreadsheets
+	OutputFormatCSV OutputFormat = "csv"
+)
+
+// csvHeader lists the columns written by OutputFormatCSV
+var csvHeader = []string{"ticket_id", "category", "confidence", "priority", "routing_team", "sentiment"}
+
+// OutputConfig defines where and how results are saved
+type OutputConfig struct {
+	Format   OutputFormat `yaml:"format"`    // text, json (default), or csv
+	FilePath string       `yaml:"file_path"` // defaults to classification_results.<ext>
+}
+
+// validate checks the format and fills in defaults
+func (c *OutputConfig) validate() error {
+	if c.Format == "" {
+		c.Format = OutputFormatJSON
+	}
+	switch c.Format {
+	case OutputFormatText, OutputFormatJSON, OutputFormatCSV:
+	default:
+		return fmt.Errorf("unknown output format %q (want text, json, or csv)", c.Format)
+	}
+	if c.FilePath == "" {
+		c.FilePath = "classification_results." + c.Format.extension()
+	}
+	return nil
+}
+
+// extension returns the file extension conventionally used for f
+func (f OutputFormat) extension() string {
+	if f == OutputFormatText {
+		return "txt"
+	}
+	return string(f)
+}
+
+// WriteResults writes the processed results to out in the given format
+func (w *WorkflowOrchestrator) WriteResults(out io.Writer, format OutputFormat) error {
+	switch format {
+	case OutputFormatText:
+		_, err := io.WriteString(out, w.GenerateReport())
+		return err
+	case OutputFormatJSON:
+		data, err := json.MarshalIndent(w.results, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to marshal results: %w", err)
+		}
+		_, err = out.Write(append(data, '\n'))
+		return err
+	case OutputFormatCSV:
+		return writeCSV(out, w.results)
+	default:
+		return fmt.Errorf("unknown output format %q", format)
+	}
+}
+
+// writeCSV writes a header row and one row per result. encoding/csv quotes
+// fields containing commas, quotes, or newlines as RFC 4180 requires.
+func writeCSV(out io.Writer, results []ClassificationOutput) error {
+	cw := csv.NewWriter(out)
+	cw.UseCRLF = true // RFC 4180 line endings
+
+	if err := cw.Write(csvHeader); err != nil {
+		return fmt.Errorf("failed to write csv header: %w", err)
+	}
+	for _, result := range results {
+		record := []string{
+			result.TicketID,
+			result.Classification.Category,
+			strconv.FormatFloat(result.Classification.Confidence, 'f', 2, 64),
+			result.Priority.Level,
+			result.Routing.Team,
+			strconv.FormatFloat(result.SentimentScore, 'f', 2, 64),
+		}
+		if err := cw.Write(record); err != nil {
+			return fmt.Errorf("failed to write csv row for %s: %w", result.TicketID, err)
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+// SaveResults writes the results to the configured file
+func (w *WorkflowOrchestrator) SaveResults() error {
+	f, err := os.OpenFile(w.output.FilePath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create results file: %w", err)
+	}
+	if err := w.WriteResults(f, w.output.Format); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
@@ -0,0 +1,77 @@
+package main
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+func sampleResults() []ClassificationOutput {
+	return []ClassificationOutput{
+		{
+			TicketID:       "TICKET-001",
+			Classification: ClassificationResult{Category: "account_access", Confidence: 0.92},
+			Routing:        RoutingRecommendation{Team: "Security, Identity"},
+			Priority:       PriorityAssessment{Level: "high"},
+			SentimentScore: 0.3,
+		},
+		{
+			TicketID:       "TICKET-002",
+			Classification: ClassificationResult{Category: "billing", Confidence: 0.875},
+			Routing:        RoutingRecommendation{Team: "Billing"},
+			Priority:       PriorityAssessment{Level: "medium"},
+			SentimentScore: 0.55,
+		},
+	}
+}
+
+func TestWriteResults_CSV(t *testing.T) {
+	w := &WorkflowOrchestrator{results: sampleResults()}
+
+	var buf bytes.Buffer
+	if err := w.WriteResults(&buf, OutputFormatCSV); err != nil {
+		t.Fatalf("WriteResults() error = %v", err)
+	}
+
+	raw := buf.String()
+	if !strings.HasPrefix(raw, "ticket_id,category,confidence,priority,routing_team,sentiment\r\n") {
+		t.Errorf("header row = %q", strings.SplitN(raw, "\n", 2)[0])
+	}
+	if !strings.Contains(raw, `"Security, Identity"`) {
+		t.Errorf("field containing a comma is not quoted: %q", raw)
+	}
+
+	records, err := csv.NewReader(&buf).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v", err)
+	}
+	if len(records) != len(w.results)+1 {
+		t.Fatalf("got %d rows, want header plus %d", len(records), len(w.results))
+	}
+	want := []string{"TICKET-001", "account_access", "0.92", "high", "Security, Identity", "0.30"}
+	if strings.Join(records[1], "|") != strings.Join(want, "|") {
+		t.Errorf("first row = %v, want %v", records[1], want)
+	}
+}
+
+func TestWriteResults_Formats(t *testing.T) {
+	w := &WorkflowOrchestrator{results: sampleResults()}
+
+	var text, js bytes.Buffer
+	if err := w.WriteResults(&text, OutputFormatText); err != nil {
+		t.Fatalf("WriteResults(text) error = %v", err)
+	}
+	if !strings.Contains(text.String(), "Total Tickets Processed: 2") {
+		t.Errorf("text output is not the report: %q", text.String())
+	}
+	if err := w.WriteResults(&js, OutputFormatJSON); err != nil {
+		t.Fatalf("WriteResults(json) error = %v", err)
+	}
+	if !strings.Contains(js.String(), `"ticket_id": "TICKET-002"`) {
+		t.Errorf("json output is missing results: %q", js.String())
+	}
+	if err := w.WriteResults(&js, "xlsx"); err == nil {
+		t.Error("WriteResults(xlsx) error = nil, want unknown format")
+	}
+}
@@ -0,0 +1,126 @@
+package main
+
+import "fmt"
+
+// PriorityRule scores one aspect of a ticket's urgency. It returns a delta
+// added to the priority score and a factor describing why; an empty factor
+// is left out of the assessment.
+type PriorityRule func(ticket TicketData, classification ClassificationResult) (float64, string)
+
+type priorityRule struct {
+	name string
+	fn   PriorityRule
+}
+
+// AddPriorityRule registers a rule evaluated by assessPriority after the
+// rules already registered. A rule with the name of an existing one, such as
+// one of the built-in "urgent_keywords", "category", and "low_confidence"
+// rules, replaces it.
+//
+// Example:
+//
+//	w.AddPriorityRule("enterprise", func(t TicketData, _ ClassificationResult) (float64, string) {
+//	    if strings.HasSuffix(t.Customer, "@enterprise.com") {
+//	        return 0.2, "Enterprise customer"
+//	    }
+//	    return 0, ""
+//	})
+func (w *WorkflowOrchestrator) AddPriorityRule(name string, fn PriorityRule) {
+	if w.rules == nil {
+		w.rules = defaultPriorityRules()
+	}
+	for i, rule := range w.rules {
+		if rule.name == name {
+			w.rules[i].fn = fn
+			return
+		}
+	}
+	w.rules = append(w.rules, priorityRule{name: name, fn: fn})
+}
+
+// defaultPriorityRules returns the built-in rules
+func defaultPriorityRules() []priorityRule {
+	return []priorityRule{
+		{name: "urgent_keywords", fn: urgentKeywordRule},
+		{name: "category", fn: categoryRule},
+		{name: "low_confidence", fn: lowConfidenceRule},
+	}
+}
+
+// urgentKeywordRule raises tickets that mention an urgent keyword
+func urgentKeywordRule(ticket TicketData, _ ClassificationResult) (float64, string) {
+	urgentKeywords := []string{"urgent", "asap", "immediately", "critical", "production", "down", "blocked"}
+	for _, keyword := range urgentKeywords {
+		if containsIgnoreCase(ticket.Description, keyword) || containsIgnoreCase(ticket.Subject, keyword) {
+			return 0.2, fmt.Sprintf("Contains urgent keyword: %s", keyword)
+		}
+	}
+	return 0, ""
+}
+
+// categoryRule adjusts priority by category
+func categoryRule(_ TicketData, classification ClassificationResult) (float64, string) {
+	categoryPriority := map[string]float64{
+		"account_access":    0.3, // Security issues are high priority
+		"technical_issue":   0.2, // Technical issues need quick resolution
+		"billing_inquiry":   0.2, // Billing issues affect revenue
+		"bug_report":        0.1,
+		"feature_request":   -0.1, // Feature requests are lower priority
+		"positive_feedback": -0.2, // Feedback is lowest priority
+	}
+
+	if adjustment, exists := categoryPriority[classification.Category]; exists {
+		return adjustment, fmt.Sprintf("Category priority adjustment: %s", classification.Category)
+	}
+	return 0, ""
+}
+
+// lowConfidenceRule raises uncertain classifications for review
+func lowConfidenceRule(_ TicketData, classification ClassificationResult) (float64, string) {
+	if classification.Confidence < 0.7 {
+		return 0.1, "Low classification confidence requires review"
+	}
+	return 0, ""
+}
+
+// assessPriority determines ticket priority by accumulating the registered
+// rules
+func (w *WorkflowOrchestrator) assessPriority(ticket TicketData, classification ClassificationResult) PriorityAssessment {
+	rules := w.rules
+	if rules == nil {
+		rules = defaultPriorityRules()
+	}
+
+	factors := []string{}
+	score := 0.5 // Base score
+	for _, rule := range rules {
+		delta, factor := rule.fn(ticket, classification)
+		score += delta
+		if factor != "" {
+			factors = append(factors, factor)
+		}
+	}
+
+	// Normalize score
+	if score > 1.0 {
+		score = 1.0
+	} else if score < 0.0 {
+		score = 0.0
+	}
+
+	// Determine level based on score
+	level := "low"
+	if score >= 0.8 {
+		level = "critical"
+	} else if score >= 0.6 {
+		level = "high"
+	} else if score >= 0.4 {
+		level = "medium"
+	}
+
+	return PriorityAssessment{
+		Level:   level,
+		Score:   score,
+		Factors: factors,
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func enterpriseRule(ticket TicketData, _ ClassificationResult) (float64, string) {
+	if strings.HasSuffix(ticket.Customer, "@enterprise.com") {
+		return 0.3, "Enterprise customer"
+	}
+	return 0, ""
+}
+
+func TestAssessPriority_DefaultRules(t *testing.T) {
+	w := &WorkflowOrchestrator{rules: defaultPriorityRules()}
+	ticket := TicketData{Subject: "API down in production", Description: "Requests fail"}
+
+	got := w.assessPriority(ticket, ClassificationResult{Category: "technical_issue", Confidence: 0.6})
+	// 0.5 base + 0.2 urgent + 0.2 category + 0.1 low confidence
+	if got.Level != "critical" || math.Abs(got.Score-1) > 1e-9 {
+		t.Errorf("priority = %s %.2f, want critical 1.00", got.Level, got.Score)
+	}
+	if len(got.Factors) != 3 {
+		t.Errorf("factors = %v, want one per built-in rule", got.Factors)
+	}
+}
+
+func TestAddPriorityRule(t *testing.T) {
+	w := &WorkflowOrchestrator{}
+	w.AddPriorityRule("enterprise", enterpriseRule)
+
+	classification := ClassificationResult{Category: "feature_request", Confidence: 0.9}
+	enterprise := w.assessPriority(TicketData{Customer: "feedback@enterprise.com"}, classification)
+	other := w.assessPriority(TicketData{Customer: "someone@example.com"}, classification)
+
+	if !slices.Contains(enterprise.Factors, "Enterprise customer") {
+		t.Errorf("factors = %v, want the enterprise rule's factor", enterprise.Factors)
+	}
+	if !slices.Contains(enterprise.Factors, "Category priority adjustment: feature_request") {
+		t.Errorf("factors = %v, want the built-in rules kept", enterprise.Factors)
+	}
+	if math.Abs(enterprise.Score-other.Score-0.3) > 1e-9 || enterprise.Level != "high" {
+		t.Errorf("enterprise priority = %s %.2f, other %.2f, want a 0.3 bump to high", enterprise.Level, enterprise.Score, other.Score)
+	}
+	if slices.Contains(other.Factors, "Enterprise customer") {
+		t.Errorf("factors = %v, want no enterprise factor", other.Factors)
+	}
+
+	// Replacing a built-in rule by name
+	w.AddPriorityRule("category", func(TicketData, ClassificationResult) (float64, string) { return 0, "" })
+	replaced := w.assessPriority(TicketData{}, classification)
+	if len(w.rules) != 4 || len(replaced.Factors) != 0 {
+		t.Errorf("rules = %d with factors %v, want the category rule replaced", len(w.rules), replaced.Factors)
+	}
+}
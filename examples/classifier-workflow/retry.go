@@ -0,0 +1,76 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+var (
+	// ErrClassificationTimeout is returned when no classification arrives
+	// within the timeout on any attempt
+	ErrClassificationTimeout = errors.New("classification timeout")
+
+	// ErrTicketSend is returned when the ticket cannot be sent to the
+	// classifier on any attempt
+	ErrTicketSend = errors.New("failed to send ticket")
+)
+
+const (
+	defaultClassificationTimeout = 30 * time.Second
+	maxRetryDelay                = 30 * time.Second
+	retryJitterFactor            = 0.3
+)
+
+// classifyWithRetry sends msg to the classifier and waits for its result on
+// resultChan. A failed send or a timeout is retried up to MaxRetries times;
+// a result from an earlier attempt that arrives late is still accepted.
+func (w *WorkflowOrchestrator) classifyWithRetry(ctx context.Context, msg *agent.Message, resultChan <-chan *agent.Message) (*agent.Message, error) {
+	timeout := defaultClassificationTimeout
+	if w.settings.TimeoutSeconds > 0 {
+		timeout = time.Duration(w.settings.TimeoutSeconds * float64(time.Second))
+	}
+
+	var lastErr error
+	for attempt := 0; attempt <= w.settings.MaxRetries; attempt++ {
+		if attempt > 0 {
+			select {
+			case result := <-resultChan:
+				return result, nil
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			case <-time.After(w.retryBackoff(attempt)):
+			}
+		}
+
+		if err := w.runtime.Send("ticket_input", msg); err != nil {
+			lastErr = fmt.Errorf("%w: %w", ErrTicketSend, err)
+			continue
+		}
+
+		select {
+		case result := <-resultChan:
+			return result, nil
+		case <-time.After(timeout):
+			lastErr = fmt.Errorf("%w after %v", ErrClassificationTimeout, timeout)
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return nil, fmt.Errorf("%w (%d attempts)", lastErr, w.settings.MaxRetries+1)
+}
+
+// retryBackoff returns the delay before the given retry: the configured
+// delay doubled per attempt, capped, ± 30% jitter
+func (w *WorkflowOrchestrator) retryBackoff(attempt int) time.Duration {
+	base := time.Duration(w.settings.RetryDelaySeconds * float64(time.Second))
+	shift := uint(max(0, min(attempt-1, 16))) // #nosec G115 -- clamped to [0,16]
+	delay := min(base<<shift, maxRetryDelay)
+	// Jitter spreads out retries of concurrent tickets; it is not security sensitive
+	jitter := time.Duration(float64(delay) * retryJitterFactor * (rand.Float64()*2 - 1)) // #nosec G404
+	return delay + jitter
+}
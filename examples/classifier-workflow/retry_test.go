@@ -0,0 +1,80 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo"
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// retrySettings retries quickly so timeouts resolve within the test
+var retrySettings = WorkflowSettings{MaxRetries: 2, RetryDelaySeconds: 0.01, TimeoutSeconds: 0.05}
+
+// sendFailingRuntime fails every Send and never delivers a classification
+type sendFailingRuntime struct {
+	agent.Runtime
+	outputs chan *agent.Message
+}
+
+func (r *sendFailingRuntime) Send(string, *agent.Message) error {
+	return errors.New("channel full")
+}
+
+func (r *sendFailingRuntime) Recv(string) (<-chan *agent.Message, error) {
+	return r.outputs, nil
+}
+
+func TestProcessTicket_RetriesAfterTimeout(t *testing.T) {
+	rt := aixgo.NewRuntime()
+	inputs, err := rt.Recv("ticket_input")
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+
+	// The classifier is still starting up and drops the first attempt
+	var received atomic.Int32
+	payload, _ := json.Marshal(ClassificationResult{Category: "billing_inquiry", Confidence: 0.8})
+	go func() {
+		for msg := range inputs {
+			if received.Add(1) == 1 {
+				continue
+			}
+			_ = rt.Send("classification_output", &agent.Message{Message: &pb.Message{Id: msg.Id, Payload: string(payload)}})
+		}
+	}()
+
+	w := &WorkflowOrchestrator{runtime: rt, settings: retrySettings}
+	result, err := w.ProcessTicket(context.Background(), TicketData{ID: "TICKET-001"})
+	if err != nil {
+		t.Fatalf("ProcessTicket() error = %v", err)
+	}
+	if result.Classification.Category != "billing_inquiry" {
+		t.Errorf("category = %q, want billing_inquiry", result.Classification.Category)
+	}
+	if n := received.Load(); n != 2 {
+		t.Errorf("classifier received %d attempts, want 2", n)
+	}
+}
+
+func TestProcessTicket_TypedErrors(t *testing.T) {
+	// Nothing answers, so every attempt times out
+	w := &WorkflowOrchestrator{runtime: aixgo.NewRuntime(), settings: retrySettings}
+	_, err := w.ProcessTicket(context.Background(), TicketData{ID: "TICKET-001"})
+	if !errors.Is(err, ErrClassificationTimeout) || errors.Is(err, ErrTicketSend) {
+		t.Errorf("ProcessTicket() error = %v, want ErrClassificationTimeout", err)
+	}
+
+	w = &WorkflowOrchestrator{
+		runtime:  &sendFailingRuntime{outputs: make(chan *agent.Message)},
+		settings: retrySettings,
+	}
+	_, err = w.ProcessTicket(context.Background(), TicketData{ID: "TICKET-002"})
+	if !errors.Is(err, ErrTicketSend) || errors.Is(err, ErrClassificationTimeout) {
+		t.Errorf("ProcessTicket() error = %v, want ErrTicketSend", err)
+	}
+}
@@ -0,0 +1,116 @@
+package main
+
+import "fmt"
+
+// RoutingRule assigns a category to a team. A ticket is escalated if
+// auto_escalate is set, or if its classification confidence is below
+// escalate_below or above escalate_above (0 disables each threshold).
+type RoutingRule struct {
+	Category      string   `yaml:"category"`
+	Team          string   `yaml:"team"`
+	Expertise     []string `yaml:"expertise"`
+	SLAHours      int      `yaml:"sla_hours"`
+	AutoEscalate  bool     `yaml:"auto_escalate"`
+	EscalateBelow float64  `yaml:"escalate_below"`
+	EscalateAbove float64  `yaml:"escalate_above"`
+}
+
+// RoutingConfig is the routing section of the config file. Categories
+// without a rule are routed by Default.
+type RoutingConfig struct {
+	Rules   []RoutingRule `yaml:"rules"`
+	Default RoutingRule   `yaml:"default"`
+
+	byCategory map[string]RoutingRule
+}
+
+// defaultRoutingRule routes categories without a rule when the config has
+// no default
+var defaultRoutingRule = RoutingRule{
+	Team:          "General Support",
+	Expertise:     []string{"Customer Service"},
+	EscalateBelow: 0.7,
+}
+
+// defaultRouting returns the routing used when the config has no rules
+func defaultRouting() RoutingConfig {
+	cfg := RoutingConfig{Rules: []RoutingRule{
+		{Category: "technical_issue", Team: "Technical Support L2", Expertise: []string{"API", "Integration", "Performance"}, EscalateBelow: 0.8},
+		{Category: "billing_inquiry", Team: "Billing Department", Expertise: []string{"Invoicing", "Payments", "Subscriptions"}},
+		{Category: "account_access", Team: "Account Security Team", Expertise: []string{"Authentication", "Password Reset", "Security"}, AutoEscalate: true},
+		{Category: "feature_request", Team: "Product Management", Expertise: []string{"Product Roadmap", "Feature Analysis"}},
+		{Category: "bug_report", Team: "Engineering Team", Expertise: []string{"Bug Triage", "QA", "Development"}, EscalateAbove: 0.9},
+		{Category: "positive_feedback", Team: "Customer Success", Expertise: []string{"Customer Relations", "Feedback Management"}},
+	}}
+	_ = cfg.validate()
+	return cfg
+}
+
+// validate checks the rules, indexes them by category, and fills in
+// defaults
+func (c *RoutingConfig) validate() error {
+	if len(c.Rules) == 0 {
+		defaults := defaultRouting()
+		c.Rules = defaults.Rules
+	}
+	if c.Default.Team == "" {
+		c.Default = defaultRoutingRule
+	}
+
+	if err := checkThresholds("default", c.Default); err != nil {
+		return err
+	}
+
+	c.byCategory = make(map[string]RoutingRule, len(c.Rules))
+	for _, rule := range c.Rules {
+		if rule.Category == "" {
+			return fmt.Errorf("routing rule for team %q has no category", rule.Team)
+		}
+		if rule.Team == "" {
+			return fmt.Errorf("routing rule for %q has no team", rule.Category)
+		}
+		if err := checkThresholds(rule.Category, rule); err != nil {
+			return err
+		}
+		if _, exists := c.byCategory[rule.Category]; exists {
+			return fmt.Errorf("duplicate routing rule for category %q", rule.Category)
+		}
+		c.byCategory[rule.Category] = rule
+	}
+	return nil
+}
+
+func checkThresholds(name string, rule RoutingRule) error {
+	if rule.EscalateBelow < 0 || rule.EscalateBelow > 1 || rule.EscalateAbove < 0 || rule.EscalateAbove > 1 {
+		return fmt.Errorf("routing rule for %q: escalation thresholds must be in [0, 1]", name)
+	}
+	return nil
+}
+
+// route returns the recommendation of the rule for classification's
+// category, or of the default rule
+func (c *RoutingConfig) route(classification ClassificationResult) RoutingRecommendation {
+	rule, exists := c.byCategory[classification.Category]
+	if !exists {
+		rule = c.Default
+	}
+
+	escalate := rule.AutoEscalate ||
+		(rule.EscalateBelow > 0 && classification.Confidence < rule.EscalateBelow) ||
+		(rule.EscalateAbove > 0 && classification.Confidence > rule.EscalateAbove)
+	return RoutingRecommendation{
+		Team:       rule.Team,
+		Expertise:  rule.Expertise,
+		Escalation: escalate,
+		SLAHours:   rule.SLAHours,
+	}
+}
+
+// determineRouting suggests the appropriate team based on classification
+func (w *WorkflowOrchestrator) determineRouting(classification ClassificationResult) RoutingRecommendation {
+	routing := w.routing
+	if routing.byCategory == nil {
+		routing = defaultRouting()
+	}
+	return routing.route(classification)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const routingConfig = `routing:
+  rules:
+    - category: outage
+      team: "Site Reliability"
+      expertise: ["Incident Response"]
+      sla_hours: 1
+      auto_escalate: true
+    - category: billing_inquiry
+      team: "Billing"
+      escalate_below: 0.6
+  default:
+    team: "Triage"
+    escalate_above: 0.95
+`
+
+func TestLoadSettings_Routing(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(routingConfig), 0600); err != nil {
+		t.Fatal(err)
+	}
+	s, err := loadSettings(path)
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	w := &WorkflowOrchestrator{routing: s.Routing}
+
+	tests := []struct {
+		name       string
+		category   string
+		confidence float64
+		team       string
+		escalate   bool
+	}{
+		{"custom category", "outage", 0.99, "Site Reliability", true},
+		{"confident billing", "billing_inquiry", 0.9, "Billing", false},
+		{"uncertain billing", "billing_inquiry", 0.5, "Billing", true},
+		{"unknown category", "technical_issue", 0.5, "Triage", false},
+		{"confident unknown category", "technical_issue", 0.99, "Triage", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := w.determineRouting(ClassificationResult{Category: tt.category, Confidence: tt.confidence})
+			if got.Team != tt.team || got.Escalation != tt.escalate {
+				t.Errorf("routing = %s escalate=%v, want %s escalate=%v", got.Team, got.Escalation, tt.team, tt.escalate)
+			}
+		})
+	}
+
+	outage := w.determineRouting(ClassificationResult{Category: "outage"})
+	if outage.SLAHours != 1 || len(outage.Expertise) != 1 {
+		t.Errorf("outage routing = %+v, want the configured SLA and expertise", outage)
+	}
+}
+
+func TestRoutingConfig_Defaults(t *testing.T) {
+	w := &WorkflowOrchestrator{}
+	got := w.determineRouting(ClassificationResult{Category: "account_access", Confidence: 0.99})
+	if got.Team != "Account Security Team" || !got.Escalation {
+		t.Errorf("default account_access routing = %+v", got)
+	}
+	got = w.determineRouting(ClassificationResult{Category: "unknown", Confidence: 0.5})
+	if got.Team != "General Support" || !got.Escalation {
+		t.Errorf("default fallback routing = %+v", got)
+	}
+}
+
+func TestRoutingConfig_Invalid(t *testing.T) {
+	invalid := map[string]RoutingConfig{
+		"no team":       {Rules: []RoutingRule{{Category: "outage"}}},
+		"no category":   {Rules: []RoutingRule{{Team: "SRE"}}},
+		"duplicate":     {Rules: []RoutingRule{{Category: "outage", Team: "SRE"}, {Category: "outage", Team: "Ops"}}},
+		"bad threshold": {Rules: []RoutingRule{{Category: "outage", Team: "SRE", EscalateBelow: 2}}},
+	}
+	for name, cfg := range invalid {
+		if err := cfg.validate(); err == nil {
+			t.Errorf("%s: validate() error = nil, want invalid routing", name)
+		}
+	}
+}
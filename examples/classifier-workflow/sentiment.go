@@ -0,0 +1,137 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"reflect"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/llm/schema"
+)
+
+// SentimentMode selects how ticket sentiment is scored
+type SentimentMode string
+
+const (
+	// SentimentModeKeyword counts positive and negative words. It costs
+	// nothing but ignores negation, so "not happy" scores as positive.
+	SentimentModeKeyword SentimentMode = "keyword"
+	// SentimentModeLLM asks the LLM provider for a score and a rationale
+	SentimentModeLLM SentimentMode = "llm"
+)
+
+// SentimentResult is the structured sentiment returned in llm mode
+type SentimentResult struct {
+	Score     float64 `json:"score" validate:"required,min=0,max=1" description:"Sentiment from 0 (very negative) through 0.5 (neutral) to 1 (very positive)"`
+	Rationale string  `json:"rationale" validate:"required,max=200" description:"One sentence explaining the score"`
+}
+
+const sentimentPrompt = `You analyze the sentiment of customer support tickets.
+Score how the customer feels, from 0 (very negative) through 0.5 (neutral) to 1 (very positive).
+Account for negation and sarcasm: "not happy" is negative.
+Respond with the score and a one-sentence rationale.`
+
+// sentimentSchema is the response schema for llm mode
+var sentimentSchema = schema.FromType(reflect.TypeOf(SentimentResult{}))
+
+// scoreSentiment returns the ticket's sentiment in [0, 1] and, in llm mode,
+// the model's rationale. If the LLM call fails the keyword score is used.
+func (w *WorkflowOrchestrator) scoreSentiment(ctx context.Context, text string) (float64, string) {
+	if w.settings.SentimentMode != SentimentModeLLM || w.provider == nil {
+		return analyzeSentiment(text), ""
+	}
+
+	result, err := w.llmSentiment(ctx, text)
+	if err != nil {
+		log.Printf("LLM sentiment failed, using keyword score: %v", err)
+		return analyzeSentiment(text), ""
+	}
+	return result.Score, result.Rationale
+}
+
+// llmSentiment asks the provider for a structured sentiment score
+func (w *WorkflowOrchestrator) llmSentiment(ctx context.Context, text string) (SentimentResult, error) {
+	req := provider.StructuredRequest{
+		CompletionRequest: provider.CompletionRequest{
+			Messages: []provider.Message{
+				{Role: "system", Content: sentimentPrompt},
+				{Role: "user", Content: text},
+			},
+			Model:       w.settings.SentimentModel,
+			Temperature: 0,
+			MaxTokens:   150,
+		},
+		ResponseSchema: sentimentSchema,
+		StrictSchema:   true,
+	}
+
+	resp, err := w.provider.CreateStructured(ctx, req)
+	if err != nil {
+		return SentimentResult{}, fmt.Errorf("sentiment request failed: %w", err)
+	}
+
+	var result SentimentResult
+	if err := json.Unmarshal(resp.Data, &result); err != nil {
+		return SentimentResult{}, fmt.Errorf("failed to parse sentiment: %w", err)
+	}
+	if result.Score < 0 || result.Score > 1 {
+		return SentimentResult{}, fmt.Errorf("sentiment score %.2f outside [0, 1]", result.Score)
+	}
+	if result.Rationale == "" {
+		return SentimentResult{}, fmt.Errorf("sentiment response has no rationale")
+	}
+	return result, nil
+}
+
+// analyzeSentiment performs keyword sentiment analysis, returning a score
+// in [0, 1] where 0.5 is neutral
+func analyzeSentiment(text string) float64 {
+	positiveWords := []string{"thank", "great", "excellent", "happy", "wonderful", "appreciate", "love"}
+	negativeWords := []string{"angry", "frustrated", "terrible", "awful", "hate", "disappointed", "unacceptable"}
+
+	score := 0.5 // Neutral baseline
+
+	for _, word := range positiveWords {
+		if containsIgnoreCase(text, word) {
+			score += 0.1
+		}
+	}
+
+	for _, word := range negativeWords {
+		if containsIgnoreCase(text, word) {
+			score -= 0.1
+		}
+	}
+
+	// Normalize score
+	if score > 1.0 {
+		score = 1.0
+	} else if score < 0.0 {
+		score = 0.0
+	}
+
+	return score
+}
+
+// newSentimentProvider returns the provider for model, preferring one
+// registered with the provider package
+func newSentimentProvider(model string) (provider.Provider, error) {
+	name := provider.DetectProvider(model)
+	if provider.Has(name) {
+		return provider.Get(name)
+	}
+
+	switch name {
+	case "openai":
+		return provider.NewOpenAIProvider(os.Getenv("OPENAI_API_KEY"), "https://api.openai.com/v1"), nil
+	case "anthropic":
+		return provider.NewAnthropicProvider(os.Getenv("ANTHROPIC_API_KEY"), "https://api.anthropic.com/v1"), nil
+	case "gemini":
+		return provider.NewGeminiProvider(os.Getenv("GEMINI_API_KEY"), "https://generativelanguage.googleapis.com/v1beta"), nil
+	default:
+		return nil, fmt.Errorf("no sentiment provider for model %s", model)
+	}
+}
@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+const negatedTicket = "I am not happy with the outage and this is not great."
+
+func TestScoreSentiment_Keyword(t *testing.T) {
+	w := &WorkflowOrchestrator{settings: WorkflowSettings{SentimentMode: SentimentModeKeyword}}
+
+	score, rationale := w.scoreSentiment(context.Background(), negatedTicket)
+	// Keyword matching sees "happy" and "great" and misses the negation
+	if score <= 0.5 {
+		t.Errorf("keyword score = %.2f, want the naive positive score", score)
+	}
+	if rationale != "" {
+		t.Errorf("keyword rationale = %q, want none", rationale)
+	}
+
+	if score := analyzeSentiment("terrible, awful, unacceptable, frustrated, angry, hate"); score != 0 {
+		t.Errorf("keyword score = %.2f, want clamped to 0", score)
+	}
+}
+
+func TestScoreSentiment_LLM(t *testing.T) {
+	mock := provider.NewMockProvider("mock")
+	mock.AddStructuredResponse(&provider.StructuredResponse{
+		Data: []byte(`{"score": 0.15, "rationale": "The customer says they are not happy about the outage."}`),
+	})
+	w := &WorkflowOrchestrator{
+		settings: WorkflowSettings{SentimentMode: SentimentModeLLM, SentimentModel: "gpt-4o-mini"},
+		provider: mock,
+	}
+
+	score, rationale := w.scoreSentiment(context.Background(), negatedTicket)
+	if score != 0.15 || !strings.Contains(rationale, "not happy") {
+		t.Errorf("llm sentiment = %.2f %q, want the model's negative score", score, rationale)
+	}
+
+	req := mock.StructuredCalls[0]
+	if req.Model != "gpt-4o-mini" || len(req.ResponseSchema) == 0 {
+		t.Errorf("request model %q with schema %s, want the sentiment model and schema", req.Model, req.ResponseSchema)
+	}
+	if got := req.Messages[len(req.Messages)-1].Content; got != negatedTicket {
+		t.Errorf("user message = %q, want the ticket text", got)
+	}
+}
+
+func TestScoreSentiment_LLMFallback(t *testing.T) {
+	mock := provider.NewMockProvider("mock")
+	// Errors and responses share an index, so the first response is skipped
+	mock.Errors = []error{errors.New("rate limited")}
+	mock.AddStructuredResponse(nil)
+	mock.AddStructuredResponse(&provider.StructuredResponse{Data: []byte(`{"score": 3, "rationale": "x"}`)})
+	w := &WorkflowOrchestrator{
+		settings: WorkflowSettings{SentimentMode: SentimentModeLLM, SentimentModel: "gpt-4o-mini"},
+		provider: mock,
+	}
+
+	// A failed call and an out-of-range score both fall back to keywords
+	for range 2 {
+		score, rationale := w.scoreSentiment(context.Background(), negatedTicket)
+		if score != analyzeSentiment(negatedTicket) || rationale != "" {
+			t.Errorf("fallback sentiment = %.2f %q, want the keyword score", score, rationale)
+		}
+	}
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// WorkflowSettings holds the workflow section of the config file used by
+// the orchestrator
+type WorkflowSettings struct {
+	SentimentMode  SentimentMode `yaml:"sentiment_mode"`  // keyword (default) or llm
+	SentimentModel string        `yaml:"sentiment_model"` // model used by llm mode
+	Concurrency    int           `yaml:"concurrency"`     // tickets classified at once (default 1)
+
+	// Classification retries: each attempt resends the ticket and waits up
+	// to the timeout, backing off with jitter between attempts
+	MaxRetries        int     `yaml:"max_retries"`
+	RetryDelaySeconds float64 `yaml:"retry_delay_seconds"`
+	TimeoutSeconds    float64 `yaml:"classification_timeout_seconds"` // default 30
+}
+
+// settings holds the example-specific sections of the config file, which
+// config.LoadConfig does not parse
+type settings struct {
+	Workflow WorkflowSettings `yaml:"workflow"`
+	Routing  RoutingConfig    `yaml:"routing"`
+	Output   OutputConfig     `yaml:"output"`
+}
+
+// loadSettings reads the workflow, routing, and output sections of the
+// config file and fills in defaults
+func loadSettings(path string) (settings, error) {
+	// G304: Validate file path to prevent path traversal
+	cleanPath := filepath.Clean(path)
+	if strings.Contains(cleanPath, "..") {
+		return settings{}, fmt.Errorf("path traversal detected in config file path")
+	}
+
+	data, err := os.ReadFile(cleanPath) //nolint:gosec // Path validated above
+	if err != nil {
+		return settings{}, fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	var s settings
+	if err := yaml.Unmarshal(data, &s); err != nil {
+		return settings{}, fmt.Errorf("failed to parse config file: %w", err)
+	}
+	if err := s.Workflow.validate(); err != nil {
+		return settings{}, err
+	}
+	if err := s.Routing.validate(); err != nil {
+		return settings{}, err
+	}
+	if err := s.Output.validate(); err != nil {
+		return settings{}, err
+	}
+	return s, nil
+}
+
+// validate checks the concurrency, retry, and sentiment settings and fills
+// in defaults
+func (s *WorkflowSettings) validate() error {
+	if s.Concurrency < 0 {
+		return fmt.Errorf("concurrency must be positive, got %d", s.Concurrency)
+	}
+	if s.Concurrency == 0 {
+		s.Concurrency = 1
+	}
+	if s.MaxRetries < 0 || s.RetryDelaySeconds < 0 || s.TimeoutSeconds < 0 {
+		return fmt.Errorf("max_retries, retry_delay_seconds, and classification_timeout_seconds must not be negative")
+	}
+
+	switch s.SentimentMode {
+	case "":
+		s.SentimentMode = SentimentModeKeyword
+	case SentimentModeKeyword:
+	case SentimentModeLLM:
+		if s.SentimentModel == "" {
+			return fmt.Errorf("sentiment_mode %q requires sentiment_model", s.SentimentMode)
+		}
+	default:
+		return fmt.Errorf("unknown sentiment_mode %q (want keyword or llm)", s.SentimentMode)
+	}
+	return nil
+}
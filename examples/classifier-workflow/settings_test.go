@@ -0,0 +1,51 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadSettings(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+
+	s, err := loadSettings(write("default.yaml", "agents: []\n"))
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	if s.Output.Format != OutputFormatJSON || s.Output.FilePath != "classification_results.json" {
+		t.Errorf("output defaults = %+v, want json to classification_results.json", s.Output)
+	}
+	if s.Workflow.SentimentMode != SentimentModeKeyword {
+		t.Errorf("sentiment mode = %q, want keyword by default", s.Workflow.SentimentMode)
+	}
+
+	s, err = loadSettings(write("custom.yaml", "workflow:\n  sentiment_mode: llm\n  sentiment_model: gpt-4o-mini\noutput:\n  format: csv\n"))
+	if err != nil {
+		t.Fatalf("loadSettings() error = %v", err)
+	}
+	if s.Output.Format != OutputFormatCSV || s.Output.FilePath != "classification_results.csv" {
+		t.Errorf("csv output = %+v", s.Output)
+	}
+	if s.Workflow.SentimentMode != SentimentModeLLM || s.Workflow.SentimentModel != "gpt-4o-mini" {
+		t.Errorf("workflow = %+v", s.Workflow)
+	}
+
+	invalid := map[string]string{
+		"format.yaml":   "output:\n  format: xlsx\n",
+		"mode.yaml":     "workflow:\n  sentiment_mode: vibes\n",
+		"no-model.yaml": "workflow:\n  sentiment_mode: llm\n",
+	}
+	for name, content := range invalid {
+		if _, err := loadSettings(write(name, content)); err == nil {
+			t.Errorf("loadSettings(%s) error = nil, want invalid config", name)
+		}
+	}
+}
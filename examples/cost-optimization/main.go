@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -13,6 +14,7 @@ import (
 	"github.com/aixgo-dev/aixgo"
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/internal/orchestration"
+	"github.com/aixgo-dev/aixgo/pkg/cache"
 	pb "github.com/aixgo-dev/aixgo/proto"
 )
 
@@ -84,47 +86,48 @@ func (m *CostMetrics) Report() {
 	}
 }
 
-// Simple in-memory cache (in production, use Redis)
-type Cache struct {
-	data map[string]CacheEntry
-	mu   sync.RWMutex
-}
+// maxCachedResponses bounds the response cache; the least recently used
+// answers are evicted beyond it
+const maxCachedResponses = 10000
 
-type CacheEntry struct {
-	Value     string
-	ExpiresAt time.Time
+// Cache is a bounded in-memory response cache with tag and prefix
+// invalidation (in production, use a Redis-backed cache.Cache)
+type Cache struct {
+	data *cache.LRU[string, string]
 }
 
 func NewCache() *Cache {
 	return &Cache{
-		data: make(map[string]CacheEntry),
+		data: cache.New[string, string](maxCachedResponses, 0),
 	}
 }
 
 func (c *Cache) Get(key string) (string, bool) {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	entry, exists := c.data[key]
-	if !exists {
-		return "", false
-	}
+	value, exists, _ := c.data.Get(context.Background(), key)
+	return value, exists
+}
 
-	if time.Now().After(entry.ExpiresAt) {
-		return "", false // Expired
+// Set caches value for ttl, tagged e.g. with the knowledge-base version an
+// answer was built from. A ttl of 0 or less stores nothing, since the entry
+// would already be expired; cache.LRU itself treats 0 as never.
+func (c *Cache) Set(key, value string, ttl time.Duration, tags ...string) {
+	if ttl <= 0 {
+		return
 	}
-
-	return entry.Value, true
+	_ = c.data.SetWithTags(context.Background(), key, value, ttl, tags...)
 }
 
-func (c *Cache) Set(key, value string, ttl time.Duration) {
-	c.mu.Lock()
-	defer c.mu.Unlock()
+// InvalidateByPrefix removes all entries whose key starts with prefix and
+// returns the number removed
+func (c *Cache) InvalidateByPrefix(ctx context.Context, prefix string) (int, error) {
+	return cache.InvalidateByPrefix(ctx, c.data, prefix)
+}
 
-	c.data[key] = CacheEntry{
-		Value:     value,
-		ExpiresAt: time.Now().Add(ttl),
-	}
+// InvalidateByTag removes all entries tagged with tag and returns the number
+// removed. Use it to drop answers derived from a knowledge-base version that
+// has since been re-indexed.
+func (c *Cache) InvalidateByTag(ctx context.Context, tag string) (int, error) {
+	return c.data.InvalidateByTag(ctx, tag)
 }
 
 // CachedAgent wraps an agent with caching functionality
@@ -133,6 +136,8 @@ type CachedAgent struct {
 	cache   *Cache
 	metrics *CostMetrics
 	ttl     time.Duration
+	tags    []string
+	mu      sync.RWMutex
 }
 
 func NewCachedAgent(baseAgent agent.Agent, cache *Cache, metrics *CostMetrics, ttl time.Duration) *CachedAgent {
@@ -144,9 +149,21 @@ func NewCachedAgent(baseAgent agent.Agent, cache *Cache, metrics *CostMetrics, t
 	}
 }
 
+// SetTags sets the tags attached to entries cached from now on
+func (c *CachedAgent) SetTags(tags ...string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.tags = tags
+}
+
+// KeyPrefix returns the prefix of every cache key written by this agent
+func (c *CachedAgent) KeyPrefix() string {
+	return c.Name() + ":"
+}
+
 func (c *CachedAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
-	// Generate cache key from input
-	cacheKey := hashInput(input.Payload)
+	// Generate cache key from input, namespaced by agent for prefix invalidation
+	cacheKey := c.KeyPrefix() + hashInput(input.Payload)
 
 	// Check cache first
 	if cached, found := c.cache.Get(cacheKey); found {
@@ -171,7 +188,10 @@ func (c *CachedAgent) Execute(ctx context.Context, input *agent.Message) (*agent
 	}
 
 	// Cache the result
-	c.cache.Set(cacheKey, result.Payload, c.ttl)
+	c.mu.RLock()
+	tags := c.tags
+	c.mu.RUnlock()
+	c.cache.Set(cacheKey, result.Payload, c.ttl, tags...)
 
 	// Record cost (would extract from result metadata in production)
 	cost := estimateCost(c.Name(), result)
@@ -283,8 +303,8 @@ func demoCaching() {
 	input1 := &agent.Message{Message: &pb.Message{Payload: "What is Golang?"}}
 	fmt.Println("  Query 1: 'What is Golang?' (first time)")
 	result1, _ := cachedAgent.Execute(ctx, input1)
-	fmt.Printf("  Response: %s\n", result1.Payload[:50]+"...")
-	if cacheHit, ok := result1.Metadata["cache_hit"].(bool); ok && cacheHit {
+	fmt.Printf("  Response: %s\n", result1.Payload[:min(50, len(result1.Payload))]+"...")
+	if cacheHit, ok := result1.GetBool("cache_hit"); ok && cacheHit {
 		fmt.Println("  Cache: HIT (no LLM cost)")
 	} else {
 		fmt.Println("  Cache: MISS (LLM called, cost incurred)")
@@ -294,8 +314,8 @@ func demoCaching() {
 	input2 := &agent.Message{Message: &pb.Message{Payload: "What is Golang?"}}
 	fmt.Println("\n  Query 2: 'What is Golang?' (repeated)")
 	result2, _ := cachedAgent.Execute(ctx, input2)
-	fmt.Printf("  Response: %s\n", result2.Payload[:50]+"...")
-	if cacheHit, ok := result2.Metadata["cache_hit"].(bool); ok && cacheHit {
+	fmt.Printf("  Response: %s\n", result2.Payload[:min(50, len(result2.Payload))]+"...")
+	if cacheHit, ok := result2.GetBool("cache_hit"); ok && cacheHit {
 		fmt.Println("  Cache: HIT (no LLM cost)")
 	} else {
 		fmt.Println("  Cache: MISS (LLM called)")
@@ -305,8 +325,8 @@ func demoCaching() {
 	input3 := &agent.Message{Message: &pb.Message{Payload: "What is Python?"}}
 	fmt.Println("\n  Query 3: 'What is Python?' (new question)")
 	result3, _ := cachedAgent.Execute(ctx, input3)
-	fmt.Printf("  Response: %s\n", result3.Payload[:50]+"...")
-	if cacheHit, ok := result3.Metadata["cache_hit"].(bool); ok && cacheHit {
+	fmt.Printf("  Response: %s\n", result3.Payload[:min(50, len(result3.Payload))]+"...")
+	if cacheHit, ok := result3.GetBool("cache_hit"); ok && cacheHit {
 		fmt.Println("  Cache: HIT (no LLM cost)")
 	} else {
 		fmt.Println("  Cache: MISS (LLM called, cost incurred)")
@@ -316,13 +336,26 @@ func demoCaching() {
 	input4 := &agent.Message{Message: &pb.Message{Payload: "What is Python?"}}
 	fmt.Println("\n  Query 4: 'What is Python?' (repeated)")
 	result4, _ := cachedAgent.Execute(ctx, input4)
-	fmt.Printf("  Response: %s\n", result4.Payload[:50]+"...")
-	if cacheHit, ok := result4.Metadata["cache_hit"].(bool); ok && cacheHit {
+	fmt.Printf("  Response: %s\n", result4.Payload[:min(50, len(result4.Payload))]+"...")
+	if cacheHit, ok := result4.GetBool("cache_hit"); ok && cacheHit {
 		fmt.Println("  Cache: HIT (no LLM cost)")
 	} else {
 		fmt.Println("  Cache: MISS (LLM called)")
 	}
 
+	// Tag new answers with the knowledge-base version they were built from
+	cachedAgent.SetTags("kb:v1")
+	input5 := &agent.Message{Message: &pb.Message{Payload: "What is Rust?"}}
+	_, _ = cachedAgent.Execute(ctx, input5)
+
+	// Knowledge base re-indexed: drop answers built from the old version
+	removed, _ := cache.InvalidateByTag(ctx, "kb:v1")
+	fmt.Printf("\n  Re-indexed knowledge base: invalidated %d entries tagged kb:v1\n", removed)
+
+	// Or drop everything this agent cached
+	removed, _ = cache.InvalidateByPrefix(ctx, cachedAgent.KeyPrefix())
+	fmt.Printf("  Invalidated %d remaining entries with prefix %q\n", removed, cachedAgent.KeyPrefix())
+
 	metrics.Report()
 
 	fmt.Println("\n  Integration with Redis:")
@@ -416,6 +449,9 @@ func demoRouterOptimization() {
 			"complex": "expensive-model",
 		},
 		orchestration.WithDefaultRoute("cheap-model"),
+		// Enforce a spend ceiling in the orchestrator itself; agents report
+		// their cost via the cost_usd metadata key
+		orchestration.WithBudget(1.00, nil),
 	)
 
 	// Test queries
@@ -444,13 +480,17 @@ func demoRouterOptimization() {
 		}
 
 		result, err := router.Execute(ctx, input)
+		if errors.Is(err, orchestration.ErrBudgetExceeded) {
+			fmt.Printf("  Budget exhausted: %v\n", err)
+			break
+		}
 		if err != nil {
 			log.Printf("  Router error: %v\n", err)
 			continue
 		}
 
 		var response map[string]interface{}
-		_ = json.Unmarshal([]byte(result.Payload), &response)
+		_ = result.DecodeJSON(&response)
 
 		model := response["model"].(string)
 		cost := response["cost"].(float64)
@@ -469,6 +509,7 @@ func demoRouterOptimization() {
 	fmt.Printf("  Total cost with router: $%.4f\n", totalCostWithRouter)
 	fmt.Printf("  Total cost without router: $%.4f (always GPT-4)\n", totalCostWithoutRouter)
 	fmt.Printf("  Savings: %.1f%%\n", savings)
+	fmt.Printf("  Router-tracked spend: $%.4f of $1.00 budget\n", router.SpentUSD())
 
 	fmt.Println("\n  Router Pattern Benefits:")
 	fmt.Println("  - Automatic complexity classification")
@@ -529,6 +570,10 @@ func (m *MockLLMAgentWithMetrics) Execute(ctx context.Context, input *agent.Mess
 		}
 		resultJSON, _ := json.Marshal(response)
 		result.Payload = string(resultJSON)
+		result.Metadata = map[string]interface{}{
+			orchestration.MetadataKeyModel:   m.model,
+			orchestration.MetadataKeyCostUSD: m.cost,
+		}
 	}
 	return result, err
 }
@@ -122,7 +122,7 @@ func testVotingStrategy(ctx context.Context, rt agent.Runtime, strategy string,
 
 	// Parse result
 	var aggResult agents.AggregationResult
-	if err := json.Unmarshal([]byte(result.Payload), &aggResult); err != nil {
+	if err := result.DecodeJSON(&aggResult); err != nil {
 		log.Printf("Failed to parse result: %v\n\n", err)
 		return
 	}
@@ -141,30 +141,26 @@ func runPolicyAnalysisWorkflow() {
 	_ = rt.Register(NewMockRiskAgent("risk-analyzer"))
 	_ = rt.Register(NewMockRiskAgent("recommendation-agent"))
 
-	// Execute sequentially: analyze risks, then generate recommendations
-	riskInput := phase2Results
+	// Hand the risk agents only the combined data, not the ensemble envelope
+	_ = rt.Register(orchestration.NewTransform("unwrap-combined", orchestration.Unwrap("combined_data")))
 
-	// Step 1: Analyze risks
-	riskAgent, _ := rt.Get("risk-analyzer")
-	riskAnalysis, err := riskAgent.Execute(ctx, riskInput)
-	if err != nil {
-		log.Printf("  Risk analysis error: %v\n", err)
-		return
-	}
-	fmt.Println("  ✓ Step 3.1: Risk analysis complete")
+	// Unwrap, analyze risks, then generate recommendations
+	phase3 := orchestration.NewSequential(
+		"risk-assessment",
+		rt,
+		[]string{"unwrap-combined", "risk-analyzer", "recommendation-agent"},
+	)
 
-	// Step 2: Generate recommendations
-	recAgent, _ := rt.Get("recommendation-agent")
-	finalResult, err := recAgent.Execute(ctx, riskAnalysis)
+	finalResult, err := phase3.Execute(ctx, phase2Results)
 	if err != nil {
-		log.Printf("  Recommendation error: %v\n", err)
+		log.Printf("  Phase 3 error: %v\n", err)
 		return
 	}
-	fmt.Println("  ✓ Step 3.2: Recommendations generated")
+	fmt.Println("  ✓ Phase 3 complete: Risks analyzed and recommendations generated")
 
 	// Display final result
 	var assessment RiskAssessment
-	if err := json.Unmarshal([]byte(finalResult.Payload), &assessment); err == nil {
+	if err := finalResult.DecodeJSON(&assessment); err == nil {
 		fmt.Printf("\n  Final Assessment:\n")
 		fmt.Printf("    Overall Risk: %s\n", assessment.OverallRisk)
 		fmt.Printf("    Severity: %d/10\n", assessment.Severity)
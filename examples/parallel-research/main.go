@@ -19,6 +19,7 @@ import (
 // - 3-4× speedup vs sequential execution
 // - Independent research tasks run concurrently
 // - Automatic result aggregation
+// - Incremental results as each agent completes
 //
 // Use case: Market research requiring data from multiple sources
 
@@ -101,10 +102,26 @@ func main() {
 		fmt.Println("(no payload returned)")
 	}
 	fmt.Println()
+
+	// Stream results as each agent finishes instead of waiting for the slowest
+	fmt.Println("📡 Streaming results as they arrive:")
+	results, errc := parallel.ExecuteStream(ctx, input)
+	for res := range results {
+		if res.Err != nil {
+			fmt.Printf("  ✗ %s: %v\n", res.Agent, res.Err)
+			continue
+		}
+		fmt.Printf("  ✓ %s: %s\n", res.Agent, res.Message.Payload)
+	}
+	if err := <-errc; err != nil {
+		log.Fatalf("Parallel streaming failed: %v", err)
+	}
+	fmt.Println()
 	fmt.Println("💡 Benefits demonstrated:")
 	fmt.Println("  ✓ 4 research tasks completed concurrently")
 	fmt.Println("  ✓ 3-4× faster than sequential execution")
-	fmt.Println("  ✓ Automatic result aggregation")
+	fmt.Println("  ✓ Automatic result aggregation, labeled by agent in input order")
+	fmt.Println("  ✓ Incremental results via ExecuteStream")
 	fmt.Println("  ✓ Continues even if some agents fail")
 }
 
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
 	"fmt"
 	"log"
@@ -9,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aixgo-dev/aixgo/pkg/chunking"
 	"github.com/aixgo-dev/aixgo/pkg/embeddings"
 	"github.com/aixgo-dev/aixgo/pkg/llm"
 	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
@@ -31,6 +33,7 @@ func main() {
 	// Parse command-line flags
 	configFile := flag.String("config", "config.yaml", "Configuration file path")
 	mode := flag.String("mode", "interactive", "Run mode: interactive, index, or query")
+	chunkSize := flag.Int("chunk-size", 0, "Split documents into chunks of this many characters when indexing (0 = index whole documents)")
 	flag.Parse()
 
 	// Load configuration
@@ -50,7 +53,7 @@ func main() {
 	// Run based on mode
 	switch *mode {
 	case "index":
-		if err := runIndexMode(ctx, embSvc, store); err != nil {
+		if err := runIndexMode(ctx, embSvc, store, *chunkSize); err != nil {
 			log.Fatalf("Index mode failed: %v", err)
 		}
 	case "query":
@@ -152,8 +155,10 @@ func initializeServices(ctx context.Context, cfg *Config) (embeddings.EmbeddingS
 	return embSvc, store, llmClient, nil
 }
 
-// runIndexMode indexes sample documents into the vector store
-func runIndexMode(ctx context.Context, embSvc embeddings.EmbeddingService, store vectorstore.VectorStore) error {
+// runIndexMode indexes sample documents into the vector store. With a
+// positive chunkSize, each document is split into overlapping chunks stored
+// as separate documents that share the original's ID as parent_id.
+func runIndexMode(ctx context.Context, embSvc embeddings.EmbeddingService, store vectorstore.VectorStore, chunkSize int) error {
 	fmt.Println("=== Indexing Documents ===")
 
 	// Get collection
@@ -182,7 +187,7 @@ func runIndexMode(ctx context.Context, embSvc embeddings.EmbeddingService, store
 		},
 		{
 			id:       "doc-4",
-			content:  "Aixgo provides in-memory and Firestore vector store implementations. Additional providers like Qdrant and pgvector are planned.",
+			content:  "Aixgo provides in-memory, Firestore, Qdrant, and pgvector vector store implementations. Additional providers like Pinecone are planned.",
 			category: "vectorstore",
 		},
 		{
@@ -207,42 +212,68 @@ func runIndexMode(ctx context.Context, embSvc embeddings.EmbeddingService, store
 		},
 	}
 
-	// Index each document
-	for i, doc := range documents {
-		fmt.Printf("Indexing %d/%d: %s...\n", i+1, len(documents), doc.id)
-
-		// Generate embedding
-		embedding, err := embSvc.Embed(ctx, doc.content)
-		if err != nil {
-			return fmt.Errorf("failed to generate embedding for %s: %w", doc.id, err)
-		}
-
-		// Create document
+	// Build the documents to store, chunked if requested
+	var vDocs []*vectorstore.Document
+	for _, doc := range documents {
 		vDoc := &vectorstore.Document{
 			ID:      doc.id,
 			Content: vectorstore.NewTextContent(doc.content),
-			Embedding: vectorstore.NewEmbedding(
-				embedding,
-				embSvc.ModelName(),
-			),
-			Tags: []string{"documentation", doc.category},
+			Tags:    []string{"documentation", doc.category},
 			Metadata: map[string]any{
 				"source":     "aixgo-docs",
 				"category":   doc.category,
 				"indexed_at": time.Now().Format(time.RFC3339),
 			},
 		}
+		if chunkSize <= 0 {
+			vDocs = append(vDocs, vDoc)
+			continue
+		}
+		chunks, err := chunking.ChunkDocument(vDoc, chunking.ChunkOptions{
+			Strategy: chunking.StrategyWindow,
+			Size:     chunkSize,
+			Overlap:  chunkSize / 5,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to chunk %s: %w", doc.id, err)
+		}
+		vDocs = append(vDocs, chunks...)
+	}
+
+	// Generate embeddings in batches; vectors[i] corresponds to vDocs[i]
+	texts := make([]string, len(vDocs))
+	for i, vDoc := range vDocs {
+		texts[i] = vDoc.Content.Text
+	}
+	vectors, err := embeddings.EmbedAll(ctx, embSvc, texts,
+		embeddings.WithEmbedBatchSize(32),
+		embeddings.WithEmbedConcurrency(4),
+	)
+	if err != nil {
+		var indexErr *embeddings.IndexError
+		if errors.As(err, &indexErr) {
+			for i, docErr := range indexErr.Failed {
+				fmt.Printf("  Failed to embed %s: %v\n", vDocs[i].ID, docErr)
+			}
+		}
+		return fmt.Errorf("failed to generate embeddings: %w", err)
+	}
+
+	// Index each document
+	for i, vDoc := range vDocs {
+		fmt.Printf("Indexing %d/%d: %s...\n", i+1, len(vDocs), vDoc.ID)
+		vDoc.Embedding = vectorstore.NewEmbedding(vectors[i], embSvc.ModelName())
 
 		// Store in vector database
 		result, err := coll.Upsert(ctx, vDoc)
 		if err != nil {
-			return fmt.Errorf("failed to upsert %s: %w", doc.id, err)
+			return fmt.Errorf("failed to upsert %s: %w", vDoc.ID, err)
 		}
 
 		fmt.Printf("  Stored: %d inserted, %d updated\n", result.Inserted, result.Updated)
 	}
 
-	fmt.Printf("\nSuccessfully indexed %d documents!\n", len(documents))
+	fmt.Printf("\nSuccessfully indexed %d documents as %d entries!\n", len(documents), len(vDocs))
 	return nil
 }
 
@@ -268,6 +299,9 @@ func runQueryMode(ctx context.Context, embSvc embeddings.EmbeddingService, store
 		if category, ok := match.Document.Metadata["category"].(string); ok {
 			fmt.Printf("   Category: %s\n", category)
 		}
+		if parent, ok := match.Document.Metadata[chunking.MetadataKeyParentID].(string); ok {
+			fmt.Printf("   Chunk of: %s\n", parent)
+		}
 	}
 
 	// Generate answer with LLM if available
@@ -18,6 +18,7 @@ import (
 //
 // Benefits:
 // - Grounded answers from knowledge base
+// - Answers verified against the retrieved context
 // - 70% token reduction vs full context
 // - Most common enterprise pattern
 //
@@ -45,6 +46,9 @@ func main() {
 	if err := rt.Register(generator); err != nil {
 		log.Fatalf("Failed to register generator: %v", err)
 	}
+	if err := rt.Register(NewMockVerifierAgent()); err != nil {
+		log.Fatalf("Failed to register verifier: %v", err)
+	}
 
 	// Create RAG orchestrator
 	rag := orchestration.NewRAG(
@@ -53,6 +57,8 @@ func main() {
 		"doc-retriever",
 		"answer-generator",
 		orchestration.WithTopK(3),
+		orchestration.WithVerifier("answer-verifier"),
+		orchestration.WithMinGroundedness(0.8),
 	)
 
 	// Example questions
@@ -81,16 +87,21 @@ func main() {
 		}
 
 		var response map[string]interface{}
-		_ = json.Unmarshal([]byte(result.Payload), &response)
+		_ = result.DecodeJSON(&response)
 
 		fmt.Printf("Answer: %s\n", response["answer"])
 		fmt.Printf("Sources: %v\n", response["sources"])
+		fmt.Printf("Groundedness: %.2f\n", result.Metadata[orchestration.MetadataKeyGroundedness])
+		if warning, ok := result.Metadata[orchestration.MetadataKeyVerificationWarning]; ok {
+			fmt.Printf("⚠️  Low confidence: %s\n", warning)
+		}
 		fmt.Println()
 	}
 
 	fmt.Println("💡 Benefits demonstrated:")
 	fmt.Println("  ✓ Retrieve relevant docs from knowledge base")
 	fmt.Println("  ✓ Generate grounded answers with citations")
+	fmt.Println("  ✓ Verify answers against retrieved context")
 	fmt.Println("  ✓ 70% token reduction vs full context")
 	fmt.Println("  ✓ Scalable to large knowledge bases")
 }
@@ -116,7 +127,8 @@ func NewMockRetrieverAgent() *MockRetrieverAgent {
 		"cost": {
 			"Cost tracking is automatic via InstrumentedProvider wrapper.",
 			"All LLM calls are tracked with token counts and cost calculation.",
-			"Pricing is maintained in internal/llm/cost/calculator.go for 25+ models.",
+			"Pricing is maintained in pkg/llm/cost/calculator.go for 25+ models.",
+			"pkg/cost exposes Calculate and RegisterPricing so agents can price custom or self-hosted models.",
 		},
 	}
 
@@ -180,10 +192,13 @@ func (m *MockGeneratorAgent) Execute(ctx context.Context, input *agent.Message)
 	// In production, this would call an LLM with the retrieved context
 	// For this example, we'll just return the retrieved documents as the answer
 
+	// The RAG pattern sends "Context:\n{retrieved}\n\nQuery:\n{question}"
+	retrieved, _, _ := strings.Cut(strings.TrimPrefix(input.Payload, "Context:\n"), "\n\nQuery:\n")
+
 	var docs map[string]interface{}
-	_ = json.Unmarshal([]byte(input.Payload), &docs)
+	_ = json.Unmarshal([]byte(retrieved), &docs)
 
-	documents := docs["documents"].([]interface{})
+	documents, _ := docs["documents"].([]interface{})
 	answer := "Based on the documentation: "
 	if len(documents) > 0 {
 		answer += documents[0].(string)
@@ -203,3 +218,44 @@ func (m *MockGeneratorAgent) Execute(ctx context.Context, input *agent.Message)
 		},
 	}, nil
 }
+
+// MockVerifierAgent scores how well an answer is supported by the context
+type MockVerifierAgent struct{}
+
+func NewMockVerifierAgent() *MockVerifierAgent {
+	return &MockVerifierAgent{}
+}
+
+func (m *MockVerifierAgent) Name() string                    { return "answer-verifier" }
+func (m *MockVerifierAgent) Role() string                    { return "verifier" }
+func (m *MockVerifierAgent) Start(ctx context.Context) error { return nil }
+func (m *MockVerifierAgent) Stop(ctx context.Context) error  { return nil }
+func (m *MockVerifierAgent) Ready() bool                     { return true }
+
+func (m *MockVerifierAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	// In production, this would ask an LLM to check each claim against the context
+	var req struct {
+		Context string `json:"context"`
+		Answer  string `json:"answer"`
+	}
+	_ = input.DecodeJSON(&req)
+
+	var response map[string]interface{}
+	_ = json.Unmarshal([]byte(req.Answer), &response)
+	claim, _ := response["answer"].(string)
+	claim = strings.TrimPrefix(claim, "Based on the documentation: ")
+
+	verdict := map[string]interface{}{"groundedness": 1.0}
+	if claim == "" || !strings.Contains(req.Context, claim) {
+		verdict = map[string]interface{}{"groundedness": 0.2, "unsupported_claims": []string{claim}}
+	}
+
+	resultJSON, _ := json.Marshal(verdict)
+
+	return &agent.Message{
+		Message: &pb.Message{
+			Type:    "verdict",
+			Payload: string(resultJSON),
+		},
+	}, nil
+}
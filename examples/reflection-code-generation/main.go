@@ -53,6 +53,7 @@ func main() {
 		"code-generator",
 		"code-critic",
 		orchestration.WithMaxIterations(3),
+		orchestration.WithQualityThreshold(0.95),
 	)
 
 	// Code generation request
@@ -76,13 +77,13 @@ func main() {
 	}
 
 	var response map[string]interface{}
-	_ = json.Unmarshal([]byte(result.Payload), &response)
+	_ = result.DecodeJSON(&response)
 
 	fmt.Println("✅ Final Code:")
 	fmt.Println(response["code"])
 	fmt.Println()
 	fmt.Printf("Quality Score: %.2f/1.00\n", response["quality"])
-	fmt.Printf("Iterations: %d\n", int(response["iteration"].(float64)))
+	fmt.Printf("Iterations: %v\n", result.Metadata[orchestration.MetadataKeyReflectionIterations])
 	fmt.Println()
 	fmt.Println("💡 Benefits demonstrated:")
 	fmt.Println("  ✓ Iterative refinement with self-critique")
@@ -200,7 +201,7 @@ func (m *MockCodeCriticAgent) Ready() bool                     { return true }
 
 func (m *MockCodeCriticAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
 	var codeData map[string]interface{}
-	_ = json.Unmarshal([]byte(input.Payload), &codeData)
+	_ = input.DecodeJSON(&codeData)
 
 	code := codeData["code"].(string)
 	iteration := int(codeData["iteration"].(float64))
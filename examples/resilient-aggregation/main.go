@@ -10,6 +10,7 @@ import (
 	"github.com/aixgo-dev/aixgo"
 	"github.com/aixgo-dev/aixgo/agents"
 	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/orchestration"
 	pb "github.com/aixgo-dev/aixgo/proto"
 )
 
@@ -177,7 +178,7 @@ func demoAllStrategies() {
 		}
 
 		var aggResult agents.AggregationResult
-		if err := json.Unmarshal([]byte(result.Payload), &aggResult); err == nil {
+		if err := result.DecodeJSON(&aggResult); err == nil {
 			fmt.Printf("   Result: %s\n", aggResult.AggregatedContent[:50]+"...")
 			fmt.Printf("   Consensus: %.0f%%\n", aggResult.ConsensusLevel*100)
 			fmt.Printf("   Tokens: %d\n", aggResult.TokensUsed)
@@ -269,7 +270,7 @@ func demoMissingInputs(ctx context.Context, rt agent.Runtime) {
 	}
 
 	var aggResult agents.AggregationResult
-	if err := json.Unmarshal([]byte(result.Payload), &aggResult); err == nil {
+	if err := result.DecodeJSON(&aggResult); err == nil {
 		fmt.Printf("   ✓ Aggregated %d inputs (60%% response rate)\n", len(inputs))
 		fmt.Printf("   Selected: %s\n", aggResult.AggregatedContent)
 		fmt.Printf("   Agreement: %.0f%%\n", aggResult.ConsensusLevel*100)
@@ -319,7 +320,7 @@ func demoPartialResults(ctx context.Context, rt agent.Runtime) {
 	}
 
 	var aggResult agents.AggregationResult
-	if err := json.Unmarshal([]byte(result.Payload), &aggResult); err == nil {
+	if err := result.DecodeJSON(&aggResult); err == nil {
 		fmt.Printf("   ✓ Processed %d inputs including partial results\n", len(inputs))
 		fmt.Printf("   Selected: %s\n", aggResult.AggregatedContent)
 		fmt.Printf("   Weighted agreement: %.0f%%\n", aggResult.ConsensusLevel*100)
@@ -369,7 +370,7 @@ func demoConfidenceSelection(ctx context.Context, rt agent.Runtime) {
 	}
 
 	var aggResult agents.AggregationResult
-	if err := json.Unmarshal([]byte(result.Payload), &aggResult); err == nil {
+	if err := result.DecodeJSON(&aggResult); err == nil {
 		fmt.Printf("   ✓ Selected input from most confident agent\n")
 		fmt.Printf("   Selected: %s\n", aggResult.AggregatedContent)
 		fmt.Printf("   Strategy: Trust the expert (confidence: 0.95)\n")
@@ -382,48 +383,43 @@ func demoFallbackStrategy(ctx context.Context, rt agent.Runtime) {
 	fmt.Println("   Fallback: Voting Majority (deterministic)")
 	fmt.Println()
 
-	// Try LLM strategy first
-	agentDef := &agent.AgentDef{
-		Name:  "consensus-aggregator",
-		Role:  "aggregator",
-		Model: "mock", // Mock provider might "fail" for consensus
-		Extra: map[string]any{
-			"aggregator_config": map[string]any{
-				"aggregation_strategy": agents.StrategyConsensus,
-				"timeout_ms":          5000,
-			},
-		},
-	}
-
-	inputs := []*agents.AgentInput{
-		{AgentName: "agent-1", Content: "Solution A", Confidence: 0.8},
-		{AgentName: "agent-2", Content: "Solution A", Confidence: 0.7},
-		{AgentName: "agent-3", Content: "Solution B", Confidence: 0.6},
+	// Register both aggregators; the chain skips any that cannot run
+	chain := []struct {
+		name     string
+		strategy string
+	}{
+		{"consensus-aggregator", agents.StrategyConsensus},
+		{"fallback-aggregator", agents.StrategyVotingMajority},
 	}
-
-	// If primary strategy fails, use fallback
-	aggregator, err := agents.NewAggregatorAgent(*agentDef, rt)
-	if err != nil || aggregator == nil {
-		fmt.Println("   Primary strategy unavailable, using fallback...")
-
-		// Fallback to deterministic voting
-		fallbackDef := &agent.AgentDef{
-			Name:  "fallback-aggregator",
+	for _, c := range chain {
+		aggregator, err := agents.NewAggregatorAgent(agent.AgentDef{
+			Name:  c.name,
 			Role:  "aggregator",
 			Model: "mock",
 			Extra: map[string]any{
 				"aggregator_config": map[string]any{
-					"aggregation_strategy": agents.StrategyVotingMajority,
-					"timeout_ms":          5000,
+					"aggregation_strategy": c.strategy,
+					"timeout_ms":           5000,
 				},
 			},
-		}
-
-		aggregator, err = agents.NewAggregatorAgent(*fallbackDef, rt)
+		}, rt)
 		if err != nil {
-			log.Printf("   ❌ Fallback error: %v\n", err)
-			return
+			fmt.Printf("   %s unavailable: %v\n", c.name, err)
+			continue
 		}
+		_ = rt.Register(aggregator)
+	}
+
+	// Try consensus first, then degrade to deterministic voting
+	fallback := orchestration.NewFallback("resilient-aggregation", rt, []orchestration.Orchestrator{
+		orchestration.NewSequential("consensus", rt, []string{"consensus-aggregator"}),
+		orchestration.NewSequential("voting", rt, []string{"fallback-aggregator"}),
+	})
+
+	inputs := []*agents.AgentInput{
+		{AgentName: "agent-1", Content: "Solution A", Confidence: 0.8},
+		{AgentName: "agent-2", Content: "Solution A", Confidence: 0.7},
+		{AgentName: "agent-3", Content: "Solution B", Confidence: 0.6},
 	}
 
 	inputsJSON, _ := json.Marshal(inputs)
@@ -434,17 +430,18 @@ func demoFallbackStrategy(ctx context.Context, rt agent.Runtime) {
 		},
 	}
 
-	result, err := aggregator.Execute(ctx, msg)
+	result, err := fallback.Execute(ctx, msg)
 	if err != nil {
 		fmt.Printf("   ❌ Error: %v\n", err)
 		return
 	}
 
 	var aggResult agents.AggregationResult
-	if err := json.Unmarshal([]byte(result.Payload), &aggResult); err == nil {
-		fmt.Printf("   ✓ Aggregation successful via fallback\n")
+	if err := result.DecodeJSON(&aggResult); err == nil {
+		fmt.Printf("   ✓ Aggregation successful via %v (chain index %v)\n",
+			result.Metadata[orchestration.MetadataKeyFallbackOrchestrator],
+			result.Metadata[orchestration.MetadataKeyFallbackIndex])
 		fmt.Printf("   Selected: %s\n", aggResult.AggregatedContent)
-		fmt.Printf("   Cost: $0 (deterministic fallback)\n")
 		fmt.Println("   Resilience: Graceful degradation to deterministic strategy")
 	}
 }
@@ -94,7 +94,7 @@ func main() {
 		}
 
 		var response map[string]interface{}
-		if err := json.Unmarshal([]byte(result.Payload), &response); err != nil {
+		if err := result.DecodeJSON(&response); err != nil {
 			log.Fatalf("Failed to unmarshal result: %v", err)
 		}
 
@@ -0,0 +1,86 @@
+package agent
+
+import (
+	"encoding/json"
+	"time"
+
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"github.com/google/uuid"
+)
+
+// Error message envelope. An agent or orchestrator can report a failure as a
+// regular message instead of a Go error so downstream agents and aggregators
+// can skip it, substitute a default, or escalate it.
+const (
+	// MessageTypeError is the type of error messages
+	MessageTypeError = "error"
+	// MetadataKeyStatus is the metadata key holding a message's status
+	MetadataKeyStatus = "status"
+	// StatusError marks a message as reporting a failure
+	StatusError = "error"
+)
+
+// ErrorDetails is the payload of an error message
+type ErrorDetails struct {
+	// Agent is the agent that failed
+	Agent string `json:"agent"`
+	// Error is the failure's error text
+	Error string `json:"error"`
+}
+
+// NewErrorDetails describes err, raised by agentName
+func NewErrorDetails(agentName string, err error) ErrorDetails {
+	details := ErrorDetails{Agent: agentName}
+	if err != nil {
+		details.Error = err.Error()
+	}
+	return details
+}
+
+// ParseErrorDetails decodes an error message payload. Payloads that are not
+// an ErrorDetails object are reported as the error text.
+func ParseErrorDetails(payload string) ErrorDetails {
+	var details ErrorDetails
+	if err := json.Unmarshal([]byte(payload), &details); err != nil || details.Error == "" {
+		details = ErrorDetails{Error: payload}
+	}
+	return details
+}
+
+// IsErrorStatus reports whether md marks a message as an error message
+func IsErrorStatus(md map[string]any) bool {
+	status, _ := md[MetadataKeyStatus].(string)
+	return status == StatusError
+}
+
+// NewErrorMessage wraps err, raised by agentName, in an error message
+func NewErrorMessage(agentName string, err error) *Message {
+	payload, _ := json.Marshal(NewErrorDetails(agentName, err))
+
+	return &Message{Message: &pb.Message{
+		Id:        uuid.New().String(),
+		Type:      MessageTypeError,
+		Payload:   string(payload),
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Metadata:  map[string]any{MetadataKeyStatus: StatusError},
+	}}
+}
+
+// IsError reports whether m is an error message
+func (m *Message) IsError() bool {
+	if m == nil || m.Message == nil {
+		return false
+	}
+	return IsErrorStatus(m.Metadata)
+}
+
+// ErrorDetails returns the details of an error message. It returns false if
+// m is not an error message. Error messages whose payload is not an
+// ErrorDetails object report the raw payload as the error text.
+func (m *Message) ErrorDetails() (*ErrorDetails, bool) {
+	if !m.IsError() {
+		return nil, false
+	}
+	details := ParseErrorDetails(m.Payload)
+	return &details, true
+}
@@ -0,0 +1,103 @@
+package agent
+
+import (
+	"encoding/json"
+	"math"
+)
+
+// Metadata keys agents use to report the usage of a call on their response
+// messages. Runtime metrics, orchestration budgets and session usage read them.
+const (
+	MetadataKeyModel        = "model"
+	MetadataKeyInputTokens  = "input_tokens"
+	MetadataKeyOutputTokens = "output_tokens"
+	MetadataKeyTotalTokens  = "total_tokens"
+	MetadataKeyCostUSD      = "cost_usd"
+)
+
+// Typed metadata accessors. Metadata that went through JSON holds every
+// number as a float64, so the numeric accessors accept any numeric type.
+
+// GetString returns the string metadata value under key
+func (m *Message) GetString(key string) (string, bool) {
+	s, ok := m.metadataValue(key).(string)
+	return s, ok
+}
+
+// GetBool returns the bool metadata value under key
+func (m *Message) GetBool(key string) (bool, bool) {
+	b, ok := m.metadataValue(key).(bool)
+	return b, ok
+}
+
+// GetFloat returns the numeric metadata value under key as a float64
+func (m *Message) GetFloat(key string) (float64, bool) {
+	return toFloat64(m.metadataValue(key))
+}
+
+// GetInt returns the numeric metadata value under key as an int. Floats
+// with a fractional part are rejected rather than truncated.
+func (m *Message) GetInt(key string) (int, bool) {
+	return toInt(m.metadataValue(key))
+}
+
+// MetadataFloat returns the numeric value under key in md as a float64
+func MetadataFloat(md map[string]any, key string) (float64, bool) {
+	return toFloat64(md[key])
+}
+
+// MetadataInt returns the whole-number value under key in md as an int
+func MetadataInt(md map[string]any, key string) (int, bool) {
+	return toInt(md[key])
+}
+
+func (m *Message) metadataValue(key string) any {
+	if m == nil || m.Message == nil {
+		return nil
+	}
+	return m.Metadata[key]
+}
+
+// toInt rejects fractions and values outside the int range. float64(MaxInt)
+// rounds up to 2^63, so the upper bound is exclusive.
+func toInt(v any) (int, bool) {
+	f, ok := toFloat64(v)
+	if !ok || f != math.Trunc(f) || f < math.MinInt || f >= math.MaxInt {
+		return 0, false
+	}
+	return int(f), true
+}
+
+func toFloat64(v any) (float64, bool) {
+	switch n := v.(type) {
+	case float64:
+		return n, true
+	case float32:
+		return float64(n), true
+	case int:
+		return float64(n), true
+	case int8:
+		return float64(n), true
+	case int16:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	case int64:
+		return float64(n), true
+	case uint:
+		return float64(n), true
+	case uint8:
+		return float64(n), true
+	case uint16:
+		return float64(n), true
+	case uint32:
+		return float64(n), true
+	case uint64:
+		return float64(n), true
+	case json.Number:
+		f, err := n.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
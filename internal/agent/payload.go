@@ -0,0 +1,65 @@
+package agent
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// Payload content types, recorded under MetadataKeyContentType
+const (
+	// MetadataKeyContentType is the metadata key hinting at the payload encoding
+	MetadataKeyContentType = "content-type"
+	// ContentTypeJSON marks a JSON payload
+	ContentTypeJSON = "application/json"
+)
+
+// ErrNilMessage is returned when encoding a payload into a nil message
+var ErrNilMessage = errors.New("encode JSON payload: message is nil")
+
+// DecodeJSON deserializes the JSON payload into v, which should be a pointer
+func (m *Message) DecodeJSON(v any) error {
+	if m == nil || m.Message == nil {
+		return DecodeJSONPayload("", v)
+	}
+	return DecodeJSONPayload(m.Payload, v)
+}
+
+// SetJSON serializes v into the payload and sets the content-type metadata
+// hint to application/json
+func (m *Message) SetJSON(v any) error {
+	if m == nil || m.Message == nil {
+		return ErrNilMessage
+	}
+	payload, err := EncodeJSONPayload(v)
+	if err != nil {
+		return err
+	}
+	m.Payload = payload
+	if m.Metadata == nil {
+		m.Metadata = make(map[string]any)
+	}
+	m.Metadata[MetadataKeyContentType] = ContentTypeJSON
+	return nil
+}
+
+// DecodeJSONPayload deserializes a message's JSON payload into v. Both
+// message types decode through it so they report errors the same way.
+func DecodeJSONPayload(payload string, v any) error {
+	if payload == "" {
+		return errors.New("decode JSON payload: message payload is empty")
+	}
+	if err := json.Unmarshal([]byte(payload), v); err != nil {
+		return fmt.Errorf("decode JSON payload: %w", err)
+	}
+	return nil
+}
+
+// EncodeJSONPayload serializes v as a message payload
+func EncodeJSONPayload(v any) (string, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return "", fmt.Errorf("encode JSON payload: %w", err)
+	}
+	return string(data), nil
+}
@@ -0,0 +1,55 @@
+package agent
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// MetadataKeyRequestID holds the correlation ID shared by every message of
+// one multi-agent request
+const MetadataKeyRequestID = "request_id"
+
+type requestIDKey struct{}
+
+// ContextWithRequestID returns a copy of ctx carrying request ID id
+func ContextWithRequestID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestIDFromContext returns the request ID carried by ctx, or "" if none
+func RequestIDFromContext(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// RequestID returns the message's request ID, or "" if it has none
+func (m *Message) RequestID() string {
+	id, _ := m.GetString(MetadataKeyRequestID)
+	return id
+}
+
+// WithRequestID resolves the request ID for msg: its own, else the one in
+// ctx, else a new one. It returns ctx and msg both carrying that ID; msg is
+// cloned rather than modified when the ID has to be added.
+func WithRequestID(ctx context.Context, msg *Message) (context.Context, *Message) {
+	id := msg.RequestID()
+	if id == "" {
+		id = RequestIDFromContext(ctx)
+	}
+	if id == "" {
+		id = uuid.New().String()
+	}
+
+	if msg != nil && msg.Message != nil && msg.RequestID() != id {
+		msg = msg.Clone()
+		if msg.Metadata == nil {
+			msg.Metadata = make(map[string]any)
+		}
+		msg.Metadata[MetadataKeyRequestID] = id
+	}
+	if RequestIDFromContext(ctx) != id {
+		ctx = ContextWithRequestID(ctx, id)
+	}
+	return ctx, msg
+}
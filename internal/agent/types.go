@@ -117,6 +117,57 @@ type Tool struct {
 
 type Message struct{ *pb.Message }
 
+// Clone returns a deep copy of the message, including the underlying
+// pb.Message and any nested maps or slices in Metadata. Orchestrators that
+// fan the same input out to several agents clone it per target so an agent
+// mutating its input cannot affect its siblings.
+func (m *Message) Clone() *Message {
+	if m == nil {
+		return nil
+	}
+	if m.Message == nil {
+		return &Message{}
+	}
+	clone := &pb.Message{
+		Id:        m.Id,
+		Type:      m.Type,
+		Payload:   m.Payload,
+		Timestamp: m.Timestamp,
+	}
+	if m.Metadata != nil {
+		clone.Metadata = make(map[string]interface{}, len(m.Metadata))
+		for k, v := range m.Metadata {
+			clone.Metadata[k] = cloneValue(v)
+		}
+	}
+	return &Message{Message: clone}
+}
+
+// cloneValue deep-copies the container types that can appear in message
+// metadata. Other values are returned as-is.
+func cloneValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			out[k] = cloneValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = cloneValue(item)
+		}
+		return out
+	case []string:
+		return append([]string(nil), val...)
+	case []byte:
+		return append([]byte(nil), val...)
+	default:
+		return v
+	}
+}
+
 // Registry for agent factory functions
 type FactoryFunc func(AgentDef, Runtime) (Agent, error)
 
@@ -1,9 +1,14 @@
 package agent
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
+	"math"
 	"testing"
 	"time"
 
+	pb "github.com/aixgo-dev/aixgo/proto"
 	"gopkg.in/yaml.v3"
 )
 
@@ -185,3 +190,185 @@ func TestRegistry(t *testing.T) {
 		t.Error("GetFactory() should not find unregistered factory")
 	}
 }
+
+func TestMessage_Clone(t *testing.T) {
+	original := &Message{Message: &pb.Message{
+		Id:      "msg-1",
+		Type:    "request",
+		Payload: `{"q":"hello"}`,
+		Metadata: map[string]interface{}{
+			"tenant": "acme",
+			"nested": map[string]any{"k": "v"},
+			"list":   []any{"a"},
+		},
+	}}
+
+	clone := original.Clone()
+	if clone.Message == original.Message {
+		t.Fatal("Clone() shares the underlying pb.Message")
+	}
+	if clone.Id != "msg-1" || clone.Type != "request" || clone.Payload != `{"q":"hello"}` {
+		t.Errorf("Clone() fields = %+v, want copy of original", clone.Message)
+	}
+
+	clone.Metadata["tenant"] = "other"
+	clone.Metadata["nested"].(map[string]any)["k"] = "changed"
+	clone.Metadata["list"].([]any)[0] = "b"
+	clone.Payload = "changed"
+
+	if original.Metadata["tenant"] != "acme" {
+		t.Errorf("original tenant = %v, want acme", original.Metadata["tenant"])
+	}
+	if original.Metadata["nested"].(map[string]any)["k"] != "v" {
+		t.Error("Clone() did not deep-copy nested metadata map")
+	}
+	if original.Metadata["list"].([]any)[0] != "a" {
+		t.Error("Clone() did not deep-copy metadata slice")
+	}
+	if original.Payload != `{"q":"hello"}` {
+		t.Error("Clone() shares payload with original")
+	}
+
+	var nilMsg *Message
+	if nilMsg.Clone() != nil {
+		t.Error("Clone() of nil message should be nil")
+	}
+}
+
+func TestErrorMessage(t *testing.T) {
+	msg := NewErrorMessage("fetcher", errors.New("timeout"))
+	if !msg.IsError() || msg.Type != MessageTypeError {
+		t.Fatalf("NewErrorMessage() = %+v, want an error message", msg.Message)
+	}
+
+	details, ok := msg.ErrorDetails()
+	if !ok || details.Agent != "fetcher" || details.Error != "timeout" {
+		t.Errorf("ErrorDetails() = %+v, %v", details, ok)
+	}
+
+	// Metadata survives cloning, so the envelope survives fan-out
+	if !msg.Clone().IsError() {
+		t.Error("Clone() lost the error status")
+	}
+
+	// Any message marked with the error status is treated as an error
+	raw := &Message{Message: &pb.Message{Payload: "boom", Metadata: map[string]any{MetadataKeyStatus: StatusError}}}
+	if details, ok := raw.ErrorDetails(); !ok || details.Error != "boom" {
+		t.Errorf("ErrorDetails() of raw payload = %+v, %v", details, ok)
+	}
+
+	plain := &Message{Message: &pb.Message{Payload: "ok"}}
+	if plain.IsError() {
+		t.Error("IsError() = true for a plain message")
+	}
+	if _, ok := plain.ErrorDetails(); ok {
+		t.Error("ErrorDetails() ok for a plain message")
+	}
+	var nilMsg *Message
+	if nilMsg.IsError() {
+		t.Error("IsError() = true for nil message")
+	}
+}
+
+func TestMessage_JSONPayload(t *testing.T) {
+	type request struct {
+		Query string `json:"query"`
+		Limit int    `json:"limit"`
+	}
+
+	msg := &Message{Message: &pb.Message{Type: "request"}}
+	if err := msg.SetJSON(request{Query: "hello", Limit: 3}); err != nil {
+		t.Fatalf("SetJSON() error = %v", err)
+	}
+	if msg.Metadata[MetadataKeyContentType] != ContentTypeJSON {
+		t.Errorf("content-type = %v, want %s", msg.Metadata[MetadataKeyContentType], ContentTypeJSON)
+	}
+
+	var got request
+	if err := msg.DecodeJSON(&got); err != nil {
+		t.Fatalf("DecodeJSON() error = %v", err)
+	}
+	if got != (request{Query: "hello", Limit: 3}) {
+		t.Errorf("DecodeJSON() = %+v, want round-tripped request", got)
+	}
+
+	invalid := &Message{Message: &pb.Message{Payload: "{not json"}}
+	if err := invalid.DecodeJSON(&got); err == nil {
+		t.Error("DecodeJSON() of invalid JSON should fail")
+	}
+	if err := (&Message{}).DecodeJSON(&got); err == nil {
+		t.Error("DecodeJSON() of empty message should fail")
+	}
+}
+
+func TestMessage_TypedMetadata(t *testing.T) {
+	msg := &Message{Message: &pb.Message{Metadata: map[string]any{
+		"name":   "a",
+		"cached": true,
+		"count":  3,
+		"score":  0.5,
+	}}}
+
+	// JSON decoding turns every number into a float64
+	data, err := json.Marshal(msg.Message)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	decoded := &Message{Message: &pb.Message{}}
+	if err := json.Unmarshal(data, decoded.Message); err != nil {
+		t.Fatalf("Unmarshal() error = %v", err)
+	}
+
+	for _, m := range []*Message{msg, decoded} {
+		if s, ok := m.GetString("name"); !ok || s != "a" {
+			t.Errorf("GetString() = %q, %v; want a", s, ok)
+		}
+		if b, ok := m.GetBool("cached"); !ok || !b {
+			t.Errorf("GetBool() = %v, %v; want true", b, ok)
+		}
+		if n, ok := m.GetInt("count"); !ok || n != 3 {
+			t.Errorf("GetInt() = %d, %v; want 3", n, ok)
+		}
+		if f, ok := m.GetFloat("score"); !ok || f != 0.5 {
+			t.Errorf("GetFloat() = %v, %v; want 0.5", f, ok)
+		}
+		if _, ok := m.GetInt("score"); ok {
+			t.Error("GetInt() of a fractional value should fail")
+		}
+	}
+
+	if _, ok := MetadataInt(map[string]any{"big": math.Pow(2, 63)}, "big"); ok {
+		t.Error("MetadataInt() of 2^63 should fail, it overflows int")
+	}
+
+	if _, ok := (&Message{}).GetBool("cached"); ok {
+		t.Error("GetBool() on an empty message should fail")
+	}
+}
+
+func TestWithRequestID(t *testing.T) {
+	ctx := context.Background()
+	msg := &Message{Message: &pb.Message{Payload: "q"}}
+
+	// A new ID is added to a clone and the context
+	ctx2, tagged := WithRequestID(ctx, msg)
+	id := tagged.RequestID()
+	if id == "" || RequestIDFromContext(ctx2) != id {
+		t.Fatalf("request ID = %q in message, %q in context, want the same new ID", id, RequestIDFromContext(ctx2))
+	}
+	if msg.RequestID() != "" {
+		t.Error("input message was modified")
+	}
+
+	// The context's ID is used for messages without one
+	_, child := WithRequestID(ctx2, &Message{Message: &pb.Message{Payload: "child"}})
+	if child.RequestID() != id {
+		t.Errorf("child request ID = %q, want %q", child.RequestID(), id)
+	}
+
+	// A message that already has an ID is returned as-is and keeps it
+	ctx3, same := WithRequestID(ContextWithRequestID(ctx, "other"), tagged)
+	if same != tagged || RequestIDFromContext(ctx3) != id {
+		t.Errorf("tagged message = %p (want %p), context ID %q (want %q)", same, tagged, RequestIDFromContext(ctx3), id)
+	}
+}
@@ -0,0 +1,50 @@
+package aggregation
+
+import "sort"
+
+// ConflictGroup is one of the competing options in a conflict
+type ConflictGroup struct {
+	Content string   // Original content of the option
+	Sources []string // Agents that produced it, in input order
+}
+
+// DetectConflict groups inputs by normalized content and reports a conflict
+// when at least two groups have minSupport or more sources (minimum 1).
+// It returns those groups, largest first with ties ordered by content, or
+// nil if the inputs do not conflict.
+func DetectConflict(inputs []VotingInput, minSupport int) []ConflictGroup {
+	if minSupport < 1 {
+		minSupport = 1
+	}
+
+	var order []string
+	groups := make(map[string]*ConflictGroup)
+	for _, input := range inputs {
+		normalized := normalizeContent(input.Content)
+		group, ok := groups[normalized]
+		if !ok {
+			group = &ConflictGroup{Content: input.Content}
+			groups[normalized] = group
+			order = append(order, normalized)
+		}
+		group.Sources = append(group.Sources, input.Source)
+	}
+
+	var competing []ConflictGroup
+	for _, normalized := range order {
+		if group := groups[normalized]; len(group.Sources) >= minSupport {
+			competing = append(competing, *group)
+		}
+	}
+	if len(competing) < 2 {
+		return nil
+	}
+
+	sort.SliceStable(competing, func(i, j int) bool {
+		if len(competing[i].Sources) != len(competing[j].Sources) {
+			return len(competing[i].Sources) > len(competing[j].Sources)
+		}
+		return normalizeContent(competing[i].Content) < normalizeContent(competing[j].Content)
+	})
+	return competing
+}
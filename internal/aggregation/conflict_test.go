@@ -0,0 +1,44 @@
+package aggregation
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDetectConflict(t *testing.T) {
+	t.Run("even_split", func(t *testing.T) {
+		inputs := []VoteInput{
+			{Content: "Option B", Source: "agent1"},
+			{Content: "Option A", Source: "agent2"},
+			{Content: "option b ", Source: "agent3"},
+			{Content: "Option A", Source: "agent4"},
+		}
+
+		groups := DetectConflict(inputs, 2)
+		assert.Equal(t, []ConflictGroup{
+			{Content: "Option A", Sources: []string{"agent2", "agent4"}},
+			{Content: "Option B", Sources: []string{"agent1", "agent3"}},
+		}, groups)
+	})
+
+	t.Run("below_threshold", func(t *testing.T) {
+		inputs := []VoteInput{
+			{Content: "Option A", Source: "agent1"},
+			{Content: "Option A", Source: "agent2"},
+			{Content: "Option B", Source: "agent3"},
+		}
+
+		assert.Nil(t, DetectConflict(inputs, 2), "a lone dissenter is below the threshold")
+		assert.Len(t, DetectConflict(inputs, 0), 2, "any split conflicts by default")
+	})
+
+	t.Run("agreement", func(t *testing.T) {
+		inputs := []VoteInput{
+			{Content: "Same", Source: "agent1"},
+			{Content: "same", Source: "agent2"},
+		}
+
+		assert.Nil(t, DetectConflict(inputs, 1))
+	})
+}
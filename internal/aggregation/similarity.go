@@ -0,0 +1,122 @@
+package aggregation
+
+import "strings"
+
+// TextSimilarity computes similarity between two text strings
+// using Levenshtein distance normalized to 0-1 range
+func TextSimilarity(text1, text2 string) float64 {
+	if text1 == text2 {
+		return 1.0
+	}
+	if text1 == "" || text2 == "" {
+		return 0.0
+	}
+
+	// Calculate Levenshtein distance
+	distance := levenshteinDistance(text1, text2)
+	maxLen := maxOf2(len(text1), len(text2))
+	if maxLen == 0 {
+		return 1.0
+	}
+
+	// Normalize to 0-1 range (1 = identical, 0 = completely different)
+	similarity := 1.0 - float64(distance)/float64(maxLen)
+	if similarity < 0 {
+		similarity = 0
+	}
+	return similarity
+}
+
+// MaxEditDistanceLen is the longest text, in bytes, BoundedTextSimilarity
+// compares by edit distance
+const MaxEditDistanceLen = 4096
+
+// BoundedTextSimilarity is TextSimilarity for texts up to MaxEditDistanceLen
+// bytes. Longer texts are scored by word overlap instead, which is linear in
+// their size where edit distance is quadratic.
+func BoundedTextSimilarity(text1, text2 string) float64 {
+	if len(text1) <= MaxEditDistanceLen && len(text2) <= MaxEditDistanceLen {
+		return TextSimilarity(text1, text2)
+	}
+	if text1 == text2 {
+		return 1.0
+	}
+	return wordOverlap(text1, text2)
+}
+
+// wordOverlap returns the Dice coefficient of the two texts' word multisets
+func wordOverlap(text1, text2 string) float64 {
+	words1, words2 := strings.Fields(text1), strings.Fields(text2)
+	if len(words1) == 0 || len(words2) == 0 {
+		return 0.0
+	}
+	counts := make(map[string]int, len(words1))
+	for _, w := range words1 {
+		counts[w]++
+	}
+	shared := 0
+	for _, w := range words2 {
+		if counts[w] > 0 {
+			counts[w]--
+			shared++
+		}
+	}
+	return 2 * float64(shared) / float64(len(words1)+len(words2))
+}
+
+// levenshteinDistance calculates the edit distance between two strings
+func levenshteinDistance(s1, s2 string) int {
+	if len(s1) == 0 {
+		return len(s2)
+	}
+	if len(s2) == 0 {
+		return len(s1)
+	}
+
+	// Keep only the previous and current rows of the distance matrix
+	prev := make([]int, len(s2)+1)
+	curr := make([]int, len(s2)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(s1); i++ {
+		curr[0] = i
+		for j := 1; j <= len(s2); j++ {
+			cost := 1
+			if s1[i-1] == s2[j-1] {
+				cost = 0
+			}
+			curr[j] = minOf3(
+				prev[j]+1,      // deletion
+				curr[j-1]+1,    // insertion
+				prev[j-1]+cost, // substitution
+			)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(s2)]
+}
+
+// minOf3 returns the minimum of three integers
+func minOf3(a, b, c int) int {
+	if a < b {
+		if a < c {
+			return a
+		}
+		return c
+	}
+	if b < c {
+		return b
+	}
+	return c
+}
+
+// maxOf2 returns the maximum of two integers
+func maxOf2(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,40 @@
+package aggregation
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestTextSimilarity(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want float64
+	}{
+		{"same", "same", 1},
+		{"", "text", 0},
+		{"kitten", "sitting", 1 - 3.0/7},
+		{"flaw", "lawn", 0.5},
+		{"abc", "xyz", 0},
+	}
+
+	for _, tt := range tests {
+		if got := TextSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+			t.Errorf("TextSimilarity(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
+
+func TestBoundedTextSimilarity(t *testing.T) {
+	if got := BoundedTextSimilarity("kitten", "sitting"); math.Abs(got-(1-3.0/7)) > 1e-9 {
+		t.Errorf("BoundedTextSimilarity() of short texts = %v, want the edit-distance score", got)
+	}
+
+	long := strings.Repeat("solution a is optimal ", 1000)
+	if got := BoundedTextSimilarity(long, long+"indeed"); got < 0.99 {
+		t.Errorf("BoundedTextSimilarity() of near-identical long texts = %v, want about 1", got)
+	}
+	if got := BoundedTextSimilarity(long, strings.Repeat("needs more data ", 1500)); got != 0 {
+		t.Errorf("BoundedTextSimilarity() of unrelated long texts = %v, want 0", got)
+	}
+}
@@ -3,7 +3,10 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"reflect"
+	"strings"
 
 	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 	"github.com/aixgo-dev/aixgo/internal/llm/validator"
@@ -32,6 +35,14 @@ type ClientConfig struct {
 
 	// StrictValidation enables strict mode (no type coercion)
 	StrictValidation bool
+
+	// SchemaCache caches response schemas generated from result types when
+	// CreateOptions.Schema is empty (default: provider.DefaultSchemaCache)
+	SchemaCache *provider.SchemaCache
+
+	// StructuredMode is the default structured output mechanism
+	// (default: StructuredModeAuto)
+	StructuredMode StructuredMode
 }
 
 // NewClient creates a new LLM client
@@ -42,6 +53,9 @@ func NewClient(prov provider.Provider, config ClientConfig) *Client {
 	if config.DefaultTemperature == 0 {
 		config.DefaultTemperature = 0.7
 	}
+	if config.SchemaCache == nil {
+		config.SchemaCache = provider.DefaultSchemaCache
+	}
 
 	return &Client{
 		provider: prov,
@@ -60,6 +74,13 @@ type CreateOptions struct {
 	// MaxTokens limits response length
 	MaxTokens int
 
+	// TopP enables nucleus sampling (0.0-1.0); ignored by providers that
+	// don't support it
+	TopP float64
+
+	// Stop lists sequences that end generation
+	Stop []string
+
 	// SystemPrompt sets the system message
 	SystemPrompt string
 
@@ -68,6 +89,57 @@ type CreateOptions struct {
 
 	// ValidationMode can be "strict" or "lax"
 	ValidationMode string
+
+	// RepairHints adds a plain description of each violated constraint to
+	// validation retry prompts, e.g. "field 'status' must be one of:
+	// pending, shipped". Defaults to true when nil.
+	RepairHints *bool
+
+	// MaxRetries overrides ClientConfig.MaxRetries for this call when set
+	MaxRetries *int
+
+	// DisableRetry overrides ClientConfig.DisableValidationRetry for this
+	// call when set. True limits the call to a single attempt; false retries
+	// even if the client disables retry.
+	DisableRetry *bool
+
+	// StructuredMode overrides ClientConfig.StructuredMode for this call
+	StructuredMode StructuredMode
+}
+
+// responseSchema returns the explicit schema, or the cached schema generated
+// from the result type
+func (o *CreateOptions) responseSchema(client *Client, t reflect.Type) (json.RawMessage, error) {
+	if len(o.Schema) > 0 {
+		return o.Schema, nil
+	}
+	schema, err := client.config.SchemaCache.Get(t)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate schema: %w", err)
+	}
+	return schema, nil
+}
+
+// repairHints reports whether retry prompts include constraint descriptions
+func (o *CreateOptions) repairHints() bool {
+	return o.RepairHints == nil || *o.RepairHints
+}
+
+// maxAttempts returns the number of attempts for the call, applying the
+// per-call overrides on top of the client defaults
+func (o *CreateOptions) maxAttempts(client *Client) int {
+	maxRetries := client.config.MaxRetries
+	if o.MaxRetries != nil {
+		maxRetries = *o.MaxRetries
+	}
+	disabled := client.config.DisableValidationRetry
+	if o.DisableRetry != nil {
+		disabled = *o.DisableRetry
+	}
+	if disabled || maxRetries < 1 {
+		return 1 // At least one attempt
+	}
+	return maxRetries
 }
 
 // CreateStructured creates a structured response of type T with automatic validation retry
@@ -104,12 +176,15 @@ func CreateStructured[T any](ctx context.Context, client *Client, prompt string,
 	}
 
 	// Determine max retries (default: 3 for Pydantic AI-style behavior)
-	maxRetries := client.config.MaxRetries
-	if client.config.DisableValidationRetry {
-		maxRetries = 1 // Single attempt if retry is disabled
+	maxRetries := options.maxAttempts(client)
+
+	schema, err := options.responseSchema(client, reflect.TypeFor[T]())
+	if err != nil {
+		return nil, err
 	}
-	if maxRetries == 0 {
-		maxRetries = 1 // At least one attempt
+	format, err := options.responseFormat(client)
+	if err != nil {
+		return nil, err
 	}
 
 	// Retry loop for validation failures
@@ -121,8 +196,11 @@ func CreateStructured[T any](ctx context.Context, client *Client, prompt string,
 				Model:       model,
 				Temperature: temperature,
 				MaxTokens:   options.MaxTokens,
+				TopP:        options.TopP,
+				Stop:        options.Stop,
 			},
-			ResponseSchema: options.Schema,
+			ResponseSchema: schema,
+			ResponseFormat: format,
 			StrictSchema:   client.config.StrictValidation || options.ValidationMode == "strict",
 		}
 
@@ -132,10 +210,17 @@ func CreateStructured[T any](ctx context.Context, client *Client, prompt string,
 			return nil, fmt.Errorf("provider error: %w", err)
 		}
 
-		// Parse response data
-		var data map[string]any
-		if err := json.Unmarshal(response.Data, &data); err != nil {
-			return nil, fmt.Errorf("failed to parse response: %w", err)
+		// Parse response data, retrying with feedback if it is not JSON
+		data, err := parseObject(structuredData(response))
+		if err != nil {
+			if attempt == maxRetries-1 {
+				return nil, fmt.Errorf("failed to parse response after %d attempts: %w", maxRetries, err)
+			}
+			messages = append(messages,
+				provider.Message{Role: "assistant", Content: response.Content},
+				provider.Message{Role: "user", Content: formatValidationFeedback(err, response.Content, options.repairHints())},
+			)
+			continue
 		}
 
 		// Validate and convert to target type
@@ -158,7 +243,7 @@ func CreateStructured[T any](ctx context.Context, client *Client, prompt string,
 		}
 
 		// Retry with validation feedback - append assistant's response and user's feedback
-		feedbackMsg := formatValidationFeedback(validationErr, response.Content)
+		feedbackMsg := formatValidationFeedback(validationErr, response.Content, options.repairHints())
 		messages = append(messages,
 			provider.Message{Role: "assistant", Content: response.Content},
 			provider.Message{Role: "user", Content: feedbackMsg},
@@ -175,58 +260,16 @@ func CreateList[T any](ctx context.Context, client *Client, prompt string, optio
 		options = &CreateOptions{}
 	}
 
-	// Build initial messages
-	messages := []provider.Message{}
-
-	if options.SystemPrompt != "" {
-		messages = append(messages, provider.Message{
-			Role:    "system",
-			Content: options.SystemPrompt,
-		})
-	}
-
-	// Add instruction to return a list
-	userPrompt := prompt + "\n\nReturn your response as a JSON array of objects."
-	messages = append(messages, provider.Message{
-		Role:    "user",
-		Content: userPrompt,
-	})
-
-	// Determine model
-	model := options.Model
-	if model == "" {
-		model = client.config.DefaultModel
-	}
-
-	// Determine temperature
-	temperature := options.Temperature
-	if temperature == 0 {
-		temperature = client.config.DefaultTemperature
+	request, err := options.listRequest(client, reflect.TypeFor[T](), prompt)
+	if err != nil {
+		return nil, err
 	}
 
 	// Determine max retries (default: 3 for Pydantic AI-style behavior)
-	maxRetries := client.config.MaxRetries
-	if client.config.DisableValidationRetry {
-		maxRetries = 1 // Single attempt if retry is disabled
-	}
-	if maxRetries == 0 {
-		maxRetries = 1 // At least one attempt
-	}
+	maxRetries := options.maxAttempts(client)
 
 	// Retry loop for validation failures
 	for attempt := 0; attempt < maxRetries; attempt++ {
-		// Create request with current messages (includes retry feedback if retrying)
-		request := provider.StructuredRequest{
-			CompletionRequest: provider.CompletionRequest{
-				Messages:    messages,
-				Model:       model,
-				Temperature: temperature,
-				MaxTokens:   options.MaxTokens,
-			},
-			ResponseSchema: options.Schema,
-			StrictSchema:   client.config.StrictValidation || options.ValidationMode == "strict",
-		}
-
 		// Make request
 		response, err := client.provider.CreateStructured(ctx, request)
 		if err != nil {
@@ -234,12 +277,12 @@ func CreateList[T any](ctx context.Context, client *Client, prompt string, optio
 		}
 
 		// Parse response data
-		var dataList []any
-		if err := json.Unmarshal(response.Data, &dataList); err != nil {
+		dataList, err := parseListItems(structuredData(response))
+		if err != nil {
 			// Retry with parsing error feedback
 			if attempt < maxRetries-1 {
-				feedbackMsg := formatValidationFeedback(err, response.Content)
-				messages = append(messages,
+				feedbackMsg := formatValidationFeedback(err, response.Content, options.repairHints())
+				request.Messages = append(request.Messages,
 					provider.Message{Role: "assistant", Content: response.Content},
 					provider.Message{Role: "user", Content: feedbackMsg},
 				)
@@ -260,7 +303,7 @@ func CreateList[T any](ctx context.Context, client *Client, prompt string, optio
 			}
 
 			var result *T
-			if client.config.StrictValidation || options.ValidationMode == "strict" {
+			if request.StrictSchema {
 				result, err = validator.ValidateStrict[T](mapData)
 			} else {
 				result, err = validator.Validate[T](mapData)
@@ -285,8 +328,8 @@ func CreateList[T any](ctx context.Context, client *Client, prompt string, optio
 		}
 
 		// Retry with validation feedback
-		feedbackMsg := formatValidationFeedback(validationErr, response.Content)
-		messages = append(messages,
+		feedbackMsg := formatValidationFeedback(validationErr, response.Content, options.repairHints())
+		request.Messages = append(request.Messages,
 			provider.Message{Role: "assistant", Content: response.Content},
 			provider.Message{Role: "user", Content: feedbackMsg},
 		)
@@ -335,6 +378,8 @@ func CreateCompletion(ctx context.Context, client *Client, prompt string, option
 		Model:       model,
 		Temperature: temperature,
 		MaxTokens:   options.MaxTokens,
+		TopP:        options.TopP,
+		Stop:        options.Stop,
 	}
 
 	// Make request
@@ -346,13 +391,91 @@ func CreateCompletion(ctx context.Context, client *Client, prompt string, option
 	return response.Content, nil
 }
 
+// parseObject decodes a JSON object response. Output wrapped in markdown
+// code fences or surrounded by prose is repaired by decoding the outermost
+// {...} span.
+func parseObject(raw []byte) (map[string]any, error) {
+	var data map[string]any
+	err := json.Unmarshal(raw, &data)
+	if err == nil {
+		return data, nil
+	}
+
+	text := string(raw)
+	start, end := strings.Index(text, "{"), strings.LastIndex(text, "}")
+	if start >= 0 && end > start {
+		if json.Unmarshal([]byte(text[start:end+1]), &data) == nil {
+			return data, nil
+		}
+	}
+	return nil, fmt.Errorf("response is not a valid JSON object: %w", err)
+}
+
 // formatValidationFeedback formats validation errors into a user-friendly retry prompt
-func formatValidationFeedback(validationErr error, previousOutput string) string {
+func formatValidationFeedback(validationErr error, previousOutput string, hints bool) string {
+	var repair string
+	if hints {
+		if lines := repairHints(validationErr); len(lines) > 0 {
+			repair = "\n\nTo fix it:\n- " + strings.Join(lines, "\n- ")
+		}
+	}
+
 	return fmt.Sprintf(`Your previous response did not pass validation:
 
-%s
+%s%s
 
-Please correct the issues and provide a valid response that matches all requirements.`, validationErr.Error())
+Please correct the issues and provide a valid response that matches all requirements.`, validationErr.Error(), repair)
+}
+
+// repairHints describes the constraint behind each validation error, such as
+// "field 'status' must be one of: pending, shipped". Errors without a known
+// constraint are skipped.
+func repairHints(err error) []string {
+	var verrs *validator.ValidationErrors
+	if !errors.As(err, &verrs) {
+		return nil
+	}
+
+	hints := make([]string, 0, len(verrs.Errors))
+	for _, e := range verrs.Errors {
+		if hint := describeConstraint(e); hint != "" {
+			field := strings.Join(e.Field, ".")
+			if field == "" {
+				field = "value"
+			}
+			hints = append(hints, fmt.Sprintf("field '%s' %s", field, hint))
+		}
+	}
+	return hints
+}
+
+// describeConstraint returns what a valid value looks like for e
+func describeConstraint(e validator.ValidationError) string {
+	switch e.Type {
+	case validator.ErrorTypeRequired:
+		return "is required"
+	case validator.ErrorTypeEnum:
+		if options, ok := e.Constraint.([]string); ok {
+			return "must be one of: " + strings.Join(options, ", ")
+		}
+	case validator.ErrorTypeMinLength:
+		return fmt.Sprintf("must be at least %v characters long", e.Constraint)
+	case validator.ErrorTypeMaxLength:
+		return fmt.Sprintf("must be at most %v characters long", e.Constraint)
+	case validator.ErrorTypeMin:
+		return fmt.Sprintf("must be at least %v", e.Constraint)
+	case validator.ErrorTypeMax:
+		return fmt.Sprintf("must be at most %v", e.Constraint)
+	case validator.ErrorTypeEmail:
+		return "must be a valid email address, e.g. user@example.com"
+	case validator.ErrorTypeURL:
+		return "must be a valid URL, e.g. https://example.com"
+	case validator.ErrorTypeUUID:
+		return "must be a valid UUID, e.g. 123e4567-e89b-12d3-a456-426614174000"
+	case validator.ErrorTypePattern:
+		return fmt.Sprintf("must match the pattern %v", e.Constraint)
+	}
+	return ""
 }
 
 // Helper function to create a client with a provider name
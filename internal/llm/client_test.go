@@ -3,9 +3,11 @@ package llm
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"strings"
 	"testing"
 
+	"github.com/aixgo-dev/aixgo/internal/llm/validator"
 	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 )
 
@@ -87,6 +89,53 @@ func TestCreateStructured_ValidationError(t *testing.T) {
 	}
 }
 
+func TestCreateStructured_MalformedJSON(t *testing.T) {
+	type Label struct {
+		Category string `json:"category" validate:"required"`
+	}
+	raw := func(text string) *provider.StructuredResponse {
+		return &provider.StructuredResponse{
+			Data:               json.RawMessage(text),
+			CompletionResponse: provider.CompletionResponse{Content: text},
+		}
+	}
+
+	tests := []struct {
+		name      string
+		responses []string
+		wantCalls int
+		wantErr   string
+	}{
+		{"code fenced", []string{"```json\n{\"category\": \"billing\"}\n```"}, 1, ""},
+		{"retried", []string{"Sure! The category is billing.", `{"category": "billing"}`}, 2, ""},
+		{"never valid", []string{"no", "still no", "{\"category\": "}, 3, "failed to parse response after 3 attempts"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := provider.NewMockProvider("test")
+			for _, r := range tt.responses {
+				mock.AddStructuredResponse(raw(r))
+			}
+			client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+			label, err := CreateStructured[Label](context.Background(), client, "Classify", nil)
+			if len(mock.StructuredCalls) != tt.wantCalls {
+				t.Errorf("calls = %d, want %d", len(mock.StructuredCalls), tt.wantCalls)
+			}
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("CreateStructured() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || label.Category != "billing" {
+				t.Fatalf("CreateStructured() = %+v, %v", label, err)
+			}
+		})
+	}
+}
+
 func TestCreateStructured_WithOptions(t *testing.T) {
 	type Product struct {
 		Name  string  `json:"name" validate:"required"`
@@ -112,6 +161,8 @@ func TestCreateStructured_WithOptions(t *testing.T) {
 		Model:        "custom-model",
 		Temperature:  0.5,
 		MaxTokens:    1000,
+		TopP:         0.9,
+		Stop:         []string{"END"},
 		SystemPrompt: "You are a helpful assistant.",
 	})
 
@@ -141,6 +192,10 @@ func TestCreateStructured_WithOptions(t *testing.T) {
 		t.Errorf("Request.MaxTokens = %d, want 1000", call.MaxTokens)
 	}
 
+	if call.TopP != 0.9 || len(call.Stop) != 1 || call.Stop[0] != "END" {
+		t.Errorf("Request.TopP = %f, Stop = %v; want 0.9 and [END]", call.TopP, call.Stop)
+	}
+
 	// Check system prompt was added
 	if len(call.Messages) < 2 {
 		t.Fatal("Messages length < 2")
@@ -189,6 +244,44 @@ func TestCreateList(t *testing.T) {
 	if items[1].ID != 2 {
 		t.Errorf("items[1].ID = %d, want 2", items[1].ID)
 	}
+
+	// The array is requested under an object root, which providers require
+	var schema struct {
+		Type       string `json:"type"`
+		Properties struct {
+			Items struct {
+				Type  string         `json:"type"`
+				Items map[string]any `json:"items"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(mock.StructuredCalls[0].ResponseSchema, &schema); err != nil {
+		t.Fatalf("request schema is not JSON: %v", err)
+	}
+	if schema.Type != "object" || schema.Properties.Items.Type != "array" || schema.Properties.Items.Items["properties"] == nil {
+		t.Errorf("request schema = %s, want the item schema wrapped in an items array", mock.StructuredCalls[0].ResponseSchema)
+	}
+
+	// The wrapped form is unwrapped on decode
+	mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"items": listData[:1]}))
+	items, err = CreateList[Item](ctx, client, "Create a list of items", nil)
+	if err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	if len(items) != 1 || items[0].Name != "Item1" {
+		t.Errorf("CreateList() = %+v, want [Item1]", items)
+	}
+
+	// An explicit array schema is wrapped the same way
+	explicit := json.RawMessage(`{"type":"array","items":{"type":"object"}}`)
+	mock.AddStructuredResponse(provider.MockStructuredResponse(listData))
+	if _, err := CreateList[Item](ctx, client, "Create a list of items", &CreateOptions{Schema: explicit}); err != nil {
+		t.Fatalf("CreateList() error = %v", err)
+	}
+	want := `{"additionalProperties":false,"properties":{"items":{"type":"array","items":{"type":"object"}}},"required":["items"],"type":"object"}`
+	if got := string(mock.StructuredCalls[2].ResponseSchema); got != want {
+		t.Errorf("ResponseSchema = %s, want %s", got, want)
+	}
 }
 
 func TestCreateCompletion(t *testing.T) {
@@ -219,6 +312,15 @@ func TestCreateCompletion(t *testing.T) {
 	if len(mock.CompletionCalls) != 1 {
 		t.Errorf("Provider calls = %d, want 1", len(mock.CompletionCalls))
 	}
+
+	// Sampling options reach the request
+	if _, err := CreateCompletion(ctx, client, "Say hello", &CreateOptions{TopP: 0.5, Stop: []string{"\n\n", "###"}}); err != nil {
+		t.Fatalf("CreateCompletion() error = %v", err)
+	}
+	call := mock.CompletionCalls[1]
+	if call.TopP != 0.5 || len(call.Stop) != 2 {
+		t.Errorf("Request.TopP = %f, Stop = %q; want 0.5 and two stop sequences", call.TopP, call.Stop)
+	}
 }
 
 func TestCreateStructured_TypeCoercion(t *testing.T) {
@@ -589,6 +691,80 @@ func TestCreateStructured_MaxRetriesSetToOne(t *testing.T) {
 	}
 }
 
+func TestCreateStructured_PerCallRetryOverride(t *testing.T) {
+	type User struct {
+		Name  string `json:"name" validate:"required"`
+		Email string `json:"email" validate:"required,email"`
+	}
+
+	invalid := provider.MockStructuredResponse(map[string]any{"name": "Frank"})
+	valid := provider.MockStructuredResponse(map[string]any{"name": "Frank", "email": "frank@example.com"})
+	disable, enable := true, false
+	one, three := 1, 3
+
+	tests := []struct {
+		name      string
+		config    ClientConfig
+		options   *CreateOptions
+		wantCalls int
+		wantErr   bool
+	}{
+		{
+			name:      "DisableRetry overrides retrying client",
+			config:    ClientConfig{DefaultModel: "test-model", MaxRetries: 3},
+			options:   &CreateOptions{DisableRetry: &disable},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "DisableRetry false overrides non-retrying client",
+			config:    ClientConfig{DefaultModel: "test-model", MaxRetries: 3, DisableValidationRetry: true},
+			options:   &CreateOptions{DisableRetry: &enable},
+			wantCalls: 2,
+		},
+		{
+			name:      "MaxRetries overrides client retries",
+			config:    ClientConfig{DefaultModel: "test-model", MaxRetries: 3},
+			options:   &CreateOptions{MaxRetries: &one},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+		{
+			name:      "MaxRetries enables retry on single-attempt client",
+			config:    ClientConfig{DefaultModel: "test-model", MaxRetries: 1},
+			options:   &CreateOptions{MaxRetries: &three},
+			wantCalls: 2,
+		},
+		{
+			name:      "nil overrides keep client default",
+			config:    ClientConfig{DefaultModel: "test-model", DisableValidationRetry: true},
+			options:   &CreateOptions{},
+			wantCalls: 1,
+			wantErr:   true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := provider.NewMockProvider("test")
+			mock.AddStructuredResponse(invalid)
+			mock.AddStructuredResponse(valid)
+			client := NewClient(mock, tt.config)
+
+			user, err := CreateStructured[User](context.Background(), client, "Create a user", tt.options)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("CreateStructured() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !tt.wantErr && user.Email != "frank@example.com" {
+				t.Errorf("Email = %q, want the retried response", user.Email)
+			}
+			if len(mock.StructuredCalls) != tt.wantCalls {
+				t.Errorf("Provider calls = %d, want %d", len(mock.StructuredCalls), tt.wantCalls)
+			}
+		})
+	}
+}
+
 func TestCreateList_ValidationRetry_Success(t *testing.T) {
 	type Item struct {
 		Name string `json:"name" validate:"required"`
@@ -668,3 +844,127 @@ func TestCreateStructured_DefaultMaxRetries(t *testing.T) {
 		t.Fatalf("CreateStructured() error = %v", err)
 	}
 }
+
+func TestCreateStructured_RepairHints(t *testing.T) {
+	type Order struct {
+		ID     string `json:"id" validate:"required"`
+		Status string `json:"status" validate:"required,oneof=pending processing shipped"`
+	}
+
+	newMock := func() *provider.MockProvider {
+		mock := provider.NewMockProvider("test")
+		mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"id": "o-1", "status": "sent"}))
+		mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"id": "o-1", "status": "shipped"}))
+		return mock
+	}
+	hint := "field 'status' must be one of: pending, processing, shipped"
+
+	t.Run("enabled by default", func(t *testing.T) {
+		mock := newMock()
+		client := NewClient(mock, ClientConfig{DefaultModel: "test-model", MaxRetries: 3})
+
+		order, err := CreateStructured[Order](context.Background(), client, "Create an order", nil)
+		if err != nil {
+			t.Fatalf("CreateStructured() error = %v, want success after retry", err)
+		}
+		if order.Status != "shipped" {
+			t.Errorf("Order.Status = %s, want 'shipped'", order.Status)
+		}
+		if len(mock.StructuredCalls) != 2 {
+			t.Fatalf("Provider calls = %d, want 2", len(mock.StructuredCalls))
+		}
+
+		msgs := mock.StructuredCalls[1].Messages
+		if feedback := msgs[len(msgs)-1].Content; !strings.Contains(feedback, hint) {
+			t.Errorf("retry prompt missing repair hint %q:\n%s", hint, feedback)
+		}
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		mock := newMock()
+		client := NewClient(mock, ClientConfig{DefaultModel: "test-model", MaxRetries: 3})
+		disabled := false
+
+		if _, err := CreateStructured[Order](context.Background(), client, "Create an order", &CreateOptions{RepairHints: &disabled}); err != nil {
+			t.Fatalf("CreateStructured() error = %v", err)
+		}
+
+		msgs := mock.StructuredCalls[1].Messages
+		if feedback := msgs[len(msgs)-1].Content; strings.Contains(feedback, hint) {
+			t.Errorf("retry prompt contains repair hint with RepairHints disabled:\n%s", feedback)
+		}
+	})
+}
+
+func TestRepairHints(t *testing.T) {
+	type Account struct {
+		Name  string `json:"name" validate:"required,min=3"`
+		Email string `json:"email" validate:"email"`
+		Age   int    `json:"age" validate:"max=120"`
+	}
+
+	_, err := validator.Validate[Account](map[string]any{"name": "Al", "email": "nope", "age": 200})
+	if err == nil {
+		t.Fatal("Validate() error = nil, want validation errors")
+	}
+
+	want := []string{
+		"field 'name' must be at least 3 characters long",
+		"field 'email' must be a valid email address",
+		"field 'age' must be at most 120",
+	}
+	hints := strings.Join(repairHints(fmt.Errorf("item 0: %w", err)), "\n")
+	for _, w := range want {
+		if !strings.Contains(hints, w) {
+			t.Errorf("repairHints() missing %q, got:\n%s", w, hints)
+		}
+	}
+}
+
+func TestCreateStructured_SchemaCache(t *testing.T) {
+	type Ticket struct {
+		Title    string `json:"title" validate:"required"`
+		Priority int    `json:"priority"`
+	}
+
+	ctx := context.Background()
+	cache := provider.NewSchemaCache()
+
+	mock := provider.NewMockProvider("test")
+	for range 3 {
+		mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"title": "Login broken", "priority": 1}))
+	}
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model", SchemaCache: cache})
+
+	for range 3 {
+		if _, err := CreateStructured[Ticket](ctx, client, "Extract the ticket", nil); err != nil {
+			t.Fatalf("CreateStructured() error = %v", err)
+		}
+	}
+
+	// The schema is generated from the type once and reused
+	if got, want := cache.Stats(), (provider.SchemaCacheStats{Hits: 2, Misses: 1, Size: 1}); got != want {
+		t.Errorf("cache.Stats() = %+v, want %+v", got, want)
+	}
+
+	var schema map[string]any
+	if err := json.Unmarshal(mock.StructuredCalls[0].ResponseSchema, &schema); err != nil {
+		t.Fatalf("request schema is not JSON: %v", err)
+	}
+	if props, _ := schema["properties"].(map[string]any); props["title"] == nil {
+		t.Errorf("request schema missing title property: %v", schema)
+	}
+
+	// An explicit schema bypasses the cache
+	explicit := json.RawMessage(`{"type":"object"}`)
+	mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"title": "Slow page"}))
+	if _, err := CreateStructured[Ticket](ctx, client, "Extract the ticket", &CreateOptions{Schema: explicit}); err != nil {
+		t.Fatalf("CreateStructured() error = %v", err)
+	}
+	if got := string(mock.StructuredCalls[3].ResponseSchema); got != string(explicit) {
+		t.Errorf("ResponseSchema = %s, want %s", got, explicit)
+	}
+	if got := cache.Stats().Hits + cache.Stats().Misses; got != 3 {
+		t.Errorf("cache lookups = %d, want 3", got)
+	}
+}
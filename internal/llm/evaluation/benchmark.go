@@ -23,6 +23,43 @@ type TestCase struct {
 	Category     string         `json:"category"`
 	Difficulty   string         `json:"difficulty"` // easy, medium, hard
 	Tags         []string       `json:"tags"`
+
+	// Budgets fail a case that is too slow or uses too many tokens, even if
+	// its output is correct (0 = no budget). Suite files give MaxLatency as
+	// a duration string such as "2s" or as nanoseconds.
+	MaxLatency time.Duration `json:"max_latency,omitempty"`
+	MaxTokens  int           `json:"max_tokens,omitempty"`
+}
+
+// UnmarshalJSON accepts max_latency as a duration string or nanoseconds
+func (tc *TestCase) UnmarshalJSON(data []byte) error {
+	type plain TestCase
+	aux := struct {
+		*plain
+		MaxLatency json.RawMessage `json:"max_latency,omitempty"`
+	}{plain: (*plain)(tc)}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+	if len(aux.MaxLatency) == 0 || string(aux.MaxLatency) == "null" {
+		return nil
+	}
+
+	var text string
+	if err := json.Unmarshal(aux.MaxLatency, &text); err != nil {
+		var nanos int64
+		if err := json.Unmarshal(aux.MaxLatency, &nanos); err != nil {
+			return fmt.Errorf("max_latency: want a duration string or nanoseconds, got %s", aux.MaxLatency)
+		}
+		tc.MaxLatency = time.Duration(nanos)
+		return nil
+	}
+	latency, err := time.ParseDuration(text)
+	if err != nil {
+		return fmt.Errorf("max_latency: %w", err)
+	}
+	tc.MaxLatency = latency
+	return nil
 }
 
 // TestSuite represents a collection of test cases
@@ -320,9 +357,27 @@ func (e *Evaluator) runSingleTest(ctx context.Context, testCase TestCase) Benchm
 	// Evaluate success
 	result.Success = e.evaluateResult(testCase, parseResult)
 
+	if reason := checkBudgets(testCase, result); reason != "" {
+		result.Success = false
+		result.ErrorMessage = reason
+	}
+
 	return result
 }
 
+// checkBudgets returns why result exceeds the case's latency or token
+// budget, or "" if it is within both
+func checkBudgets(testCase TestCase, result BenchmarkResult) string {
+	var exceeded []string
+	if testCase.MaxLatency > 0 && result.Latency > testCase.MaxLatency {
+		exceeded = append(exceeded, fmt.Sprintf("latency %v exceeds budget %v", result.Latency, testCase.MaxLatency))
+	}
+	if testCase.MaxTokens > 0 && result.TokensUsed > testCase.MaxTokens {
+		exceeded = append(exceeded, fmt.Sprintf("used %d tokens, budget %d", result.TokensUsed, testCase.MaxTokens))
+	}
+	return strings.Join(exceeded, "; ")
+}
+
 // evaluateResult checks if the result matches expectations
 func (e *Evaluator) evaluateResult(testCase TestCase, result *parser.ParseResult) bool {
 	switch testCase.ExpectedType {
@@ -1,11 +1,13 @@
 package evaluation
 
 import (
+	"context"
 	"encoding/json"
 	"testing"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/llm/parser"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 )
 
 func TestGetDefaultTestSuite(t *testing.T) {
@@ -308,3 +310,31 @@ func TestGetTestTools(t *testing.T) {
 		}
 	}
 }
+
+func TestRunBenchmark_Budgets(t *testing.T) {
+	answer := provider.CompletionResponse{
+		Content: "Final Answer: 42",
+		Usage:   provider.Usage{PromptTokens: 400, CompletionTokens: 100, TotalTokens: 500},
+	}
+	suite := &TestSuite{Name: "budgets", TestCases: []TestCase{
+		{ID: "within", Input: "q1", ExpectedType: "direct_answer", MaxTokens: 1000, MaxLatency: time.Minute},
+		{ID: "over_tokens", Input: "q2", ExpectedType: "direct_answer", MaxTokens: 200},
+	}}
+
+	mock := provider.NewMockProvider("mock").AddResponse("q", answer)
+	benchmark, err := NewEvaluator(mock, "mock").RunBenchmark(context.Background(), suite, "mock")
+	if err != nil {
+		t.Fatalf("RunBenchmark() error = %v", err)
+	}
+
+	within, over := benchmark.Results[0], benchmark.Results[1]
+	if !within.Success {
+		t.Errorf("case within budget failed: %s", within.ErrorMessage)
+	}
+	if over.Success || over.ParsedResult == nil || over.ParsedResult.FinalAnswer != "42" {
+		t.Errorf("correct case over the token budget: success = %v, parsed = %+v; want a failure", over.Success, over.ParsedResult)
+	}
+	if over.ErrorMessage != "used 500 tokens, budget 200" {
+		t.Errorf("ErrorMessage = %q, want the token budget reason", over.ErrorMessage)
+	}
+}
@@ -0,0 +1,114 @@
+package evaluation
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+const extractionSuiteYAML = `name: extraction
+version: "1.0"
+test_cases:
+  - id: weather_city
+    input: "Weather for Tokyo please"
+    expected_type: tool_call
+    max_latency: 2s
+    expected_tool: get_weather
+    expected_args:
+      location: Tokyo
+    category: weather
+    difficulty: easy
+  - id: greeting
+    input: "Say hello"
+    expected_type: direct_answer
+    category: chat
+    difficulty: easy
+`
+
+func TestLoadSuiteAndRun(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "extraction.yaml")
+	if err := os.WriteFile(path, []byte(extractionSuiteYAML), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	resolved, err := ResolveSuites([]string{DefaultSuiteName, path})
+	if err != nil {
+		t.Fatalf("ResolveSuites() error = %v", err)
+	}
+	if len(resolved) != 2 || resolved[0] != suites[DefaultSuiteName] {
+		t.Fatalf("ResolveSuites() = %v, want the default suite then the file", resolved)
+	}
+	suite := resolved[1]
+	if suite.Name != "extraction" || len(suite.TestCases) != 2 || suite.TestCases[0].ExpectedArgs["location"] != "Tokyo" ||
+		suite.TestCases[0].MaxLatency != 2*time.Second {
+		t.Fatalf("LoadSuite() = %+v", suite)
+	}
+
+	mock := provider.NewMockProvider("mock").
+		AddResponse("Tokyo", provider.CompletionResponse{Content: "Thought: look it up\nAction: get_weather\nAction Input: {\"location\": \"Tokyo\"}"}).
+		AddResponse("hello", provider.CompletionResponse{Content: "Final Answer: Hello!"})
+	benchmark, err := NewEvaluator(mock, "mock").RunBenchmark(context.Background(), suite, "mock")
+	if err != nil {
+		t.Fatalf("RunBenchmark() error = %v", err)
+	}
+	if benchmark.TestSuite != "extraction" || benchmark.Summary.PassedTests != 2 {
+		t.Errorf("benchmark = %s with %d/%d passed, want extraction 2/2",
+			benchmark.TestSuite, benchmark.Summary.PassedTests, benchmark.Summary.TotalTests)
+	}
+}
+
+func TestRegisterSuite(t *testing.T) {
+	custom := &TestSuite{Name: "classification", TestCases: []TestCase{{ID: "c1", Input: "spam?"}}}
+	RegisterSuite("classification", custom)
+	t.Cleanup(func() {
+		suitesMu.Lock()
+		delete(suites, "classification")
+		suitesMu.Unlock()
+	})
+
+	resolved, err := ResolveSuites([]string{"classification"})
+	if err != nil || resolved[0] != custom {
+		t.Fatalf("ResolveSuites() = %v, %v; want the registered suite", resolved, err)
+	}
+
+	if _, err := ResolveSuites([]string{"missing"}); !errors.Is(err, ErrSuiteNotFound) {
+		t.Errorf("ResolveSuites(missing) error = %v, want ErrSuiteNotFound", err)
+	}
+}
+
+func TestLoadSuiteJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "qa.json")
+	data := `{"version": "1", "test_cases": [
+		{"id": "q1", "input": "hi", "expected_type": "direct_answer", "max_latency": "1.5s"},
+		{"id": "q2", "input": "hey", "expected_type": "direct_answer", "max_latency": 1000000000}
+	]}`
+	if err := os.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	suite, err := LoadSuite(path)
+	if err != nil {
+		t.Fatalf("LoadSuite() error = %v", err)
+	}
+	if suite.Name != "qa" || suite.TestCases[0].ID != "q1" {
+		t.Errorf("LoadSuite() = %+v, want name from the file and two cases", suite)
+	}
+	if got := suite.TestCases[0].MaxLatency; got != 1500*time.Millisecond {
+		t.Errorf("MaxLatency from a string = %v, want 1.5s", got)
+	}
+	if got := suite.TestCases[1].MaxLatency; got != time.Second {
+		t.Errorf("MaxLatency from nanoseconds = %v, want 1s", got)
+	}
+
+	if err := os.WriteFile(path, []byte(`{"test_cases": [{"id": "q1", "max_latency": "soon"}]}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadSuite(path); err == nil {
+		t.Error("LoadSuite() accepted an invalid max_latency")
+	}
+}
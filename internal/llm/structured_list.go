@@ -0,0 +1,234 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/llm/validator"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+// ElementError reports why one element of a structured list was rejected
+type ElementError struct {
+	Index int
+	Err   error
+}
+
+func (e *ElementError) Error() string {
+	return fmt.Sprintf("element %d: %v", e.Index, e.Err)
+}
+
+func (e *ElementError) Unwrap() error {
+	return e.Err
+}
+
+// ListValidationError is returned by CreateStructuredList alongside the
+// elements that passed validation, listing the ones that did not
+type ListValidationError struct {
+	Total  int
+	Errors []*ElementError
+}
+
+func (e *ListValidationError) Error() string {
+	msgs := make([]string, len(e.Errors))
+	for i, err := range e.Errors {
+		msgs[i] = err.Error()
+	}
+	return fmt.Sprintf("%d of %d elements failed validation: %s", len(e.Errors), e.Total, strings.Join(msgs, "; "))
+}
+
+func (e *ListValidationError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, err := range e.Errors {
+		errs[i] = err
+	}
+	return errs
+}
+
+// CreateStructuredList extracts every T found for prompt in a single call,
+// e.g. all people mentioned in a document. The response schema is T's schema
+// (or options.Schema) wrapped in an array under an "items" object, as for
+// CreateList.
+//
+// Each element is validated against T's rules on its own. When some fail,
+// the valid elements are returned together with a *ListValidationError
+// describing the rest; responses that are not a list at all are retried
+// with feedback like CreateStructured.
+func CreateStructuredList[T any](ctx context.Context, client *Client, prompt string, options *CreateOptions) ([]T, error) {
+	if options == nil {
+		options = &CreateOptions{}
+	}
+
+	request, err := options.listRequest(client, reflect.TypeFor[T](), prompt)
+	if err != nil {
+		return nil, err
+	}
+	maxRetries := options.maxAttempts(client)
+
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		response, err := client.provider.CreateStructured(ctx, request)
+		if err != nil {
+			return nil, fmt.Errorf("provider error: %w", err)
+		}
+
+		items, err := parseListItems(structuredData(response))
+		if err != nil {
+			if attempt == maxRetries-1 {
+				return nil, fmt.Errorf("failed to parse response as list after %d attempts: %w", maxRetries, err)
+			}
+			request.Messages = append(request.Messages,
+				provider.Message{Role: "assistant", Content: response.Content},
+				provider.Message{Role: "user", Content: formatValidationFeedback(err, response.Content, options.repairHints())},
+			)
+			continue
+		}
+
+		return validateListItems[T](items, request.StrictSchema)
+	}
+
+	// Unreachable (loop always returns)
+	return nil, fmt.Errorf("unreachable")
+}
+
+// listRequest builds the request CreateList and CreateStructuredList send
+// for a list of t: the prompt with the list instruction, the wrapped list
+// schema, and the model settings resolved against the client defaults
+func (o *CreateOptions) listRequest(client *Client, t reflect.Type, prompt string) (provider.StructuredRequest, error) {
+	messages := []provider.Message{}
+	if o.SystemPrompt != "" {
+		messages = append(messages, provider.Message{Role: "system", Content: o.SystemPrompt})
+	}
+	messages = append(messages, provider.Message{
+		Role:    "user",
+		Content: prompt + "\n\nReturn your response as a JSON object whose \"items\" array holds every matching object.",
+	})
+
+	model := o.Model
+	if model == "" {
+		model = client.config.DefaultModel
+	}
+	temperature := o.Temperature
+	if temperature == 0 {
+		temperature = client.config.DefaultTemperature
+	}
+
+	schema, err := o.listResponseSchema(client, t)
+	if err != nil {
+		return provider.StructuredRequest{}, err
+	}
+	format, err := o.responseFormat(client)
+	if err != nil {
+		return provider.StructuredRequest{}, err
+	}
+
+	return provider.StructuredRequest{
+		CompletionRequest: provider.CompletionRequest{
+			Messages:    messages,
+			Model:       model,
+			Temperature: temperature,
+			MaxTokens:   o.MaxTokens,
+			TopP:        o.TopP,
+			Stop:        o.Stop,
+		},
+		ResponseSchema: schema,
+		ResponseFormat: format,
+		StrictSchema:   client.config.StrictValidation || o.ValidationMode == "strict",
+	}, nil
+}
+
+// listResponseSchema returns the schema for a list of t wrapped as
+// {"items": [...]}, since many providers require an object at the schema
+// root. An explicit options.Schema is the element schema, or the schema of
+// the whole array when its root type is "array".
+func (o *CreateOptions) listResponseSchema(client *Client, t reflect.Type) (json.RawMessage, error) {
+	if len(o.Schema) > 0 {
+		var root struct {
+			Type any `json:"type"`
+		}
+		if err := json.Unmarshal(o.Schema, &root); err == nil && root.Type == "array" {
+			return wrapItems(o.Schema)
+		}
+	}
+
+	element, err := o.responseSchema(client, t)
+	if err != nil {
+		return nil, err
+	}
+	array, err := json.Marshal(map[string]any{"type": "array", "items": element})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build list schema: %w", err)
+	}
+	return wrapItems(array)
+}
+
+// wrapItems wraps an array schema as the "items" property of an object
+func wrapItems(array json.RawMessage) (json.RawMessage, error) {
+	schema, err := json.Marshal(map[string]any{
+		"type": "object",
+		"properties": map[string]any{
+			"items": array,
+		},
+		"required":             []string{"items"},
+		"additionalProperties": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to build list schema: %w", err)
+	}
+	return schema, nil
+}
+
+// parseListItems accepts the wrapped {"items": [...]} form as well as a
+// bare array, which some providers return regardless of the schema
+func parseListItems(data []byte) ([]any, error) {
+	var items []any
+	if err := json.Unmarshal(data, &items); err == nil {
+		return items, nil
+	}
+
+	var wrapped struct {
+		Items *[]any `json:"items"`
+	}
+	if err := json.Unmarshal(data, &wrapped); err != nil {
+		return nil, err
+	}
+	if wrapped.Items == nil {
+		return nil, errors.New(`response has no "items" array`)
+	}
+	return *wrapped.Items, nil
+}
+
+// validateListItems converts and validates each element independently
+func validateListItems[T any](items []any, strict bool) ([]T, error) {
+	results := make([]T, 0, len(items))
+	var failed []*ElementError
+
+	for i, item := range items {
+		data, ok := item.(map[string]any)
+		if !ok {
+			failed = append(failed, &ElementError{Index: i, Err: errors.New("not an object")})
+			continue
+		}
+
+		var result *T
+		var err error
+		if strict {
+			result, err = validator.ValidateStrict[T](data)
+		} else {
+			result, err = validator.Validate[T](data)
+		}
+		if err != nil {
+			failed = append(failed, &ElementError{Index: i, Err: err})
+			continue
+		}
+		results = append(results, *result)
+	}
+
+	if len(failed) > 0 {
+		return results, &ListValidationError{Total: len(items), Errors: failed}
+	}
+	return results, nil
+}
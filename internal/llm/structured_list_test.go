@@ -0,0 +1,95 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+type listPerson struct {
+	Name  string `json:"name" validate:"required"`
+	Email string `json:"email" validate:"email"`
+}
+
+func TestCreateStructuredList(t *testing.T) {
+	mock := provider.NewMockProvider("test")
+	mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{
+		"items": []map[string]any{
+			{"name": "Ada", "email": "ada@example.com"},
+			{"name": "Grace", "email": "grace@example.com"},
+		},
+	}))
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+	people, err := CreateStructuredList[listPerson](context.Background(), client, "Extract all people", nil)
+	if err != nil {
+		t.Fatalf("CreateStructuredList() error = %v", err)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[1].Name != "Grace" {
+		t.Errorf("CreateStructuredList() = %+v", people)
+	}
+
+	var schema struct {
+		Type       string `json:"type"`
+		Properties struct {
+			Items struct {
+				Type  string          `json:"type"`
+				Items json.RawMessage `json:"items"`
+			} `json:"items"`
+		} `json:"properties"`
+	}
+	if err := json.Unmarshal(mock.StructuredCalls[0].ResponseSchema, &schema); err != nil {
+		t.Fatal(err)
+	}
+	if schema.Type != "object" || schema.Properties.Items.Type != "array" || len(schema.Properties.Items.Items) == 0 {
+		t.Errorf("response schema should wrap the element schema in an items array: %s", mock.StructuredCalls[0].ResponseSchema)
+	}
+}
+
+func TestCreateStructuredList_PartialResults(t *testing.T) {
+	mock := provider.NewMockProvider("test")
+	// A bare array is accepted too
+	mock.AddStructuredResponse(provider.MockStructuredResponse([]any{
+		map[string]any{"name": "Ada", "email": "ada@example.com"},
+		map[string]any{"name": "Nobody", "email": "not-an-email"},
+		"not a person",
+		map[string]any{"name": "Grace", "email": "grace@example.com"},
+	}))
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+	people, err := CreateStructuredList[listPerson](context.Background(), client, "Extract all people", nil)
+
+	var listErr *ListValidationError
+	if !errors.As(err, &listErr) {
+		t.Fatalf("CreateStructuredList() error = %v, want *ListValidationError", err)
+	}
+	if listErr.Total != 4 || len(listErr.Errors) != 2 || listErr.Errors[0].Index != 1 || listErr.Errors[1].Index != 2 {
+		t.Errorf("ListValidationError = %v", listErr)
+	}
+	if len(people) != 2 || people[0].Name != "Ada" || people[1].Name != "Grace" {
+		t.Errorf("valid elements = %+v, want Ada and Grace", people)
+	}
+	if len(mock.StructuredCalls) != 1 {
+		t.Errorf("element failures should not be retried, calls = %d", len(mock.StructuredCalls))
+	}
+}
+
+func TestCreateStructuredList_RetriesNonList(t *testing.T) {
+	mock := provider.NewMockProvider("test")
+	mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"name": "Ada"}))
+	mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{
+		"items": []map[string]any{{"name": "Ada", "email": "ada@example.com"}},
+	}))
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+	people, err := CreateStructuredList[listPerson](context.Background(), client, "Extract all people", nil)
+	if err != nil || len(people) != 1 {
+		t.Fatalf("CreateStructuredList() = %+v, %v", people, err)
+	}
+	if len(mock.StructuredCalls) != 2 {
+		t.Errorf("calls = %d, want a retry after the non-list response", len(mock.StructuredCalls))
+	}
+}
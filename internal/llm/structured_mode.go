@@ -0,0 +1,96 @@
+package llm
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+// StructuredMode selects how structured output is requested from the provider
+type StructuredMode string
+
+const (
+	// StructuredModeAuto picks the mechanism the provider handles best
+	StructuredModeAuto StructuredMode = "auto"
+	// StructuredModeJSONSchema uses the provider's JSON schema response format
+	StructuredModeJSONSchema StructuredMode = "json_schema"
+	// StructuredModeFunctionCall asks for the response as a forced tool call
+	StructuredModeFunctionCall StructuredMode = "function_call"
+	// StructuredModeGrammar constrains decoding with a grammar built from
+	// the schema. No built-in provider supports it; it is passed through to
+	// custom providers.
+	StructuredModeGrammar StructuredMode = "grammar"
+)
+
+// ErrStructuredModeUnsupported is returned when an explicit structured mode
+// is requested from a built-in provider that cannot honour it
+var ErrStructuredModeUnsupported = errors.New("structured mode not supported by provider")
+
+// providerStructuredModes lists the modes each built-in provider honours.
+// The first is the one StructuredModeAuto selects; an empty list leaves the
+// provider on its own default mechanism. Providers not listed are assumed
+// to handle any mode.
+var providerStructuredModes = map[string][]StructuredMode{
+	"openai":                {StructuredModeJSONSchema, StructuredModeFunctionCall},
+	"xai":                   {StructuredModeJSONSchema},
+	"gemini":                {StructuredModeJSONSchema},
+	"vertexai":              {StructuredModeJSONSchema},
+	"ollama":                {StructuredModeJSONSchema},
+	"anthropic":             {StructuredModeFunctionCall},
+	"bedrock":               {StructuredModeFunctionCall},
+	"huggingface":           {},
+	"huggingface-optimized": {},
+}
+
+// responseFormat resolves the structured mode for the call, preferring the
+// per-call option over the client default, and returns the matching
+// provider.StructuredRequest.ResponseFormat
+func (o *CreateOptions) responseFormat(client *Client) (string, error) {
+	mode := o.StructuredMode
+	if mode == "" {
+		mode = client.config.StructuredMode
+	}
+
+	name := client.provider.Name()
+	supported, builtin := providerStructuredModes[name]
+	if mode == "" || mode == StructuredModeAuto {
+		if len(supported) == 0 {
+			return "", nil
+		}
+		mode = supported[0]
+	}
+
+	var format string
+	switch mode {
+	case StructuredModeJSONSchema:
+		format = provider.ResponseFormatJSONSchema
+	case StructuredModeFunctionCall:
+		format = provider.ResponseFormatTool
+	case StructuredModeGrammar:
+		format = provider.ResponseFormatGrammar
+	default:
+		return "", fmt.Errorf("unknown structured mode %q", mode)
+	}
+
+	if builtin && !slices.Contains(supported, mode) {
+		return "", fmt.Errorf("%w: %s does not support %q", ErrStructuredModeUnsupported, name, mode)
+	}
+	return format, nil
+}
+
+// structuredData returns the response data, taking it from the structured
+// output tool call when a provider returned the call without extracting it
+func structuredData(response *provider.StructuredResponse) json.RawMessage {
+	if len(response.Data) > 0 {
+		return response.Data
+	}
+	for _, tc := range response.ToolCalls {
+		if tc.Function.Name == provider.StructuredOutputTool {
+			return tc.Function.Arguments
+		}
+	}
+	return response.Data
+}
@@ -0,0 +1,139 @@
+package llm
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+func TestCreateStructured_StructuredMode(t *testing.T) {
+	type User struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	tests := []struct {
+		name       string
+		provider   string
+		clientMode StructuredMode
+		callMode   StructuredMode
+		wantFormat string
+	}{
+		{name: "auto openai uses JSON schema", provider: "openai", wantFormat: provider.ResponseFormatJSONSchema},
+		{name: "auto anthropic uses function call", provider: "anthropic", wantFormat: provider.ResponseFormatTool},
+		{name: "auto ollama uses JSON schema", provider: "ollama", wantFormat: provider.ResponseFormatJSONSchema},
+		{name: "auto huggingface keeps its default", provider: "huggingface", wantFormat: ""},
+		{name: "auto unknown provider keeps its default", provider: "custom", wantFormat: ""},
+		{name: "explicit auto", provider: "bedrock", callMode: StructuredModeAuto, wantFormat: provider.ResponseFormatTool},
+		{name: "client default", provider: "openai", clientMode: StructuredModeFunctionCall, wantFormat: provider.ResponseFormatTool},
+		{name: "call overrides client", provider: "openai", clientMode: StructuredModeFunctionCall, callMode: StructuredModeJSONSchema, wantFormat: provider.ResponseFormatJSONSchema},
+		{name: "grammar passes through to custom providers", provider: "custom", callMode: StructuredModeGrammar, wantFormat: provider.ResponseFormatGrammar},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mock := provider.NewMockProvider(tt.provider)
+			mock.AddStructuredResponse(provider.MockStructuredResponse(map[string]any{"name": "Ada"}))
+			client := NewClient(mock, ClientConfig{DefaultModel: "test-model", StructuredMode: tt.clientMode})
+
+			_, err := CreateStructured[User](context.Background(), client, "Name a scientist", &CreateOptions{StructuredMode: tt.callMode})
+			if err != nil {
+				t.Fatalf("CreateStructured() error = %v", err)
+			}
+			if len(mock.StructuredCalls) != 1 {
+				t.Fatalf("Provider calls = %d, want 1", len(mock.StructuredCalls))
+			}
+			req := mock.StructuredCalls[0]
+			if req.ResponseFormat != tt.wantFormat {
+				t.Errorf("ResponseFormat = %q, want %q", req.ResponseFormat, tt.wantFormat)
+			}
+			if len(req.ResponseSchema) == 0 {
+				t.Error("ResponseSchema is empty, want the generated schema in every mode")
+			}
+		})
+	}
+}
+
+func TestCreateStructured_UnknownStructuredMode(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	mock := provider.NewMockProvider("openai")
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+	_, err := CreateStructured[User](context.Background(), client, "Name a scientist", &CreateOptions{StructuredMode: "xml"})
+	if err == nil {
+		t.Fatal("CreateStructured() error = nil, want unknown structured mode")
+	}
+	if len(mock.StructuredCalls) != 0 {
+		t.Errorf("Provider calls = %d, want 0", len(mock.StructuredCalls))
+	}
+}
+
+func TestCreateStructured_UnsupportedStructuredMode(t *testing.T) {
+	type User struct {
+		Name string `json:"name"`
+	}
+
+	tests := []struct {
+		provider string
+		mode     StructuredMode
+	}{
+		{provider: "anthropic", mode: StructuredModeJSONSchema},
+		{provider: "bedrock", mode: StructuredModeJSONSchema},
+		{provider: "gemini", mode: StructuredModeFunctionCall},
+		{provider: "xai", mode: StructuredModeFunctionCall},
+		{provider: "openai", mode: StructuredModeGrammar},
+		{provider: "ollama", mode: StructuredModeGrammar},
+		{provider: "huggingface", mode: StructuredModeJSONSchema},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.provider+"/"+string(tt.mode), func(t *testing.T) {
+			mock := provider.NewMockProvider(tt.provider)
+			client := NewClient(mock, ClientConfig{DefaultModel: "test-model", StructuredMode: tt.mode})
+
+			_, err := CreateStructured[User](context.Background(), client, "Name a scientist", nil)
+			if !errors.Is(err, ErrStructuredModeUnsupported) {
+				t.Fatalf("CreateStructured() error = %v, want ErrStructuredModeUnsupported", err)
+			}
+			if len(mock.StructuredCalls) != 0 {
+				t.Errorf("Provider calls = %d, want 0", len(mock.StructuredCalls))
+			}
+		})
+	}
+}
+
+func TestCreateStructured_FunctionCallData(t *testing.T) {
+	type User struct {
+		Name string `json:"name" validate:"required"`
+	}
+
+	// A provider that returns the tool call without extracting its arguments
+	mock := provider.NewMockProvider("custom")
+	mock.AddStructuredResponse(&provider.StructuredResponse{
+		CompletionResponse: provider.CompletionResponse{
+			FinishReason: "tool_calls",
+			ToolCalls: []provider.ToolCall{{
+				ID:   "call_1",
+				Type: "function",
+				Function: provider.FunctionCall{
+					Name:      provider.StructuredOutputTool,
+					Arguments: json.RawMessage(`{"name":"Ada"}`),
+				},
+			}},
+		},
+	})
+	client := NewClient(mock, ClientConfig{DefaultModel: "test-model"})
+
+	user, err := CreateStructured[User](context.Background(), client, "Name a scientist", &CreateOptions{StructuredMode: StructuredModeFunctionCall})
+	if err != nil {
+		t.Fatalf("CreateStructured() error = %v", err)
+	}
+	if user.Name != "Ada" {
+		t.Errorf("Name = %q, want the tool call arguments", user.Name)
+	}
+}
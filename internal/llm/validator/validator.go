@@ -242,12 +242,7 @@ func (v *Validator) validateField(ctx *ValidationContext, field reflect.StructFi
 // coerceType converts a value to the target type
 func (v *Validator) coerceType(value any, targetType reflect.Type) (any, error) {
 	if !v.coerce {
-		// Strict mode - no coercion
-		valueType := reflect.TypeOf(value)
-		if valueType != targetType {
-			return nil, fmt.Errorf("type mismatch: expected %s, got %s", targetType, valueType)
-		}
-		return value, nil
+		return v.strictType(value, targetType)
 	}
 
 	// Handle nil
@@ -298,6 +293,59 @@ func (v *Validator) coerceType(value any, targetType reflect.Type) (any, error)
 	return nil, fmt.Errorf("cannot convert %T to %s", value, targetType)
 }
 
+// strictType accepts value only if it already has the target's JSON type:
+// numbers for numeric fields (whole numbers for integers), arrays for
+// slices and objects for structs. Strings are never parsed into other types.
+func (v *Validator) strictType(value any, targetType reflect.Type) (any, error) {
+	if value == nil {
+		return reflect.Zero(targetType).Interface(), nil
+	}
+
+	valueType := reflect.TypeOf(value)
+	if valueType == targetType {
+		return value, nil
+	}
+	mismatch := fmt.Errorf("type mismatch: expected %s, got %s", targetType, valueType)
+
+	switch targetType.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if f, ok := value.(float64); !ok || f != math.Trunc(f) {
+			return nil, mismatch
+		}
+		return v.toInt(value, targetType)
+
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		if f, ok := value.(float64); !ok || f != math.Trunc(f) {
+			return nil, mismatch
+		}
+		return v.toUint(value, targetType)
+
+	case reflect.Float32, reflect.Float64:
+		if _, ok := value.(float64); !ok {
+			return nil, mismatch
+		}
+		return v.toFloat(value, targetType)
+
+	case reflect.Slice:
+		if _, ok := value.([]any); !ok {
+			return nil, mismatch
+		}
+		return v.toSlice(value, targetType)
+
+	case reflect.Struct:
+		if valueMap, ok := value.(map[string]any); ok {
+			return v.structFromMap(valueMap, targetType)
+		}
+		return nil, mismatch
+	}
+
+	// Named types over the same kind, such as a string-based enum
+	if valueType.Kind() == targetType.Kind() && valueType.ConvertibleTo(targetType) {
+		return reflect.ValueOf(value).Convert(targetType).Interface(), nil
+	}
+	return nil, mismatch
+}
+
 // Type conversion helpers
 
 func (v *Validator) toInt(value any, targetType reflect.Type) (any, error) {
@@ -539,37 +587,38 @@ func (v *Validator) applyRule(ctx *ValidationContext, rule validationRule, value
 		return nil
 
 	case "min":
-		return v.validateMin(value, rule.param)
+		return ruleError(v.validateMin(value, rule.param), lengthOr(value, ErrorTypeMinLength, ErrorTypeMin), rule.param)
 
 	case "max":
-		return v.validateMax(value, rule.param)
+		return ruleError(v.validateMax(value, rule.param), lengthOr(value, ErrorTypeMaxLength, ErrorTypeMax), rule.param)
 
 	case "gte":
-		return v.validateGTE(value, rule.param)
+		return ruleError(v.validateGTE(value, rule.param), ErrorTypeMin, rule.param)
 
 	case "gt":
 		return v.validateGT(value, rule.param)
 
 	case "lte":
-		return v.validateLTE(value, rule.param)
+		return ruleError(v.validateLTE(value, rule.param), ErrorTypeMax, rule.param)
 
 	case "lt":
 		return v.validateLT(value, rule.param)
 
 	case "oneof":
-		return v.validateOneOf(value, strings.Split(rule.param, " "))
+		options := strings.Split(rule.param, " ")
+		return ruleError(v.validateOneOf(value, options), ErrorTypeEnum, options)
 
 	case "email":
-		return v.validateEmail(value)
+		return ruleError(v.validateEmail(value), ErrorTypeEmail, nil)
 
 	case "url":
-		return v.validateURL(value)
+		return ruleError(v.validateURL(value), ErrorTypeURL, nil)
 
 	case "uuid":
-		return v.validateUUID(value)
+		return ruleError(v.validateUUID(value), ErrorTypeUUID, nil)
 
 	case "pattern":
-		return v.validatePattern(value, rule.param)
+		return ruleError(v.validatePattern(value, rule.param), ErrorTypePattern, rule.param)
 
 	case "alpha":
 		return v.validateAlpha(value)
@@ -586,6 +635,27 @@ func (v *Validator) applyRule(ctx *ValidationContext, rule validationRule, value
 	}
 }
 
+// ruleError records the type and constraint of a failed rule so callers
+// can describe what a valid value looks like. The caller adds the field path.
+func ruleError(err error, errorType string, constraint any) error {
+	if err == nil {
+		return nil
+	}
+	return &ValidationErrors{Errors: []ValidationError{{
+		Message:    err.Error(),
+		Type:       errorType,
+		Constraint: constraint,
+	}}}
+}
+
+// lengthOr returns lengthType for string values and valueType otherwise
+func lengthOr(value any, lengthType, valueType string) string {
+	if _, ok := value.(string); ok {
+		return lengthType
+	}
+	return valueType
+}
+
 // Validation rule implementations
 
 func (v *Validator) validateMin(value any, param string) error {
@@ -179,6 +179,33 @@ func TestStrictMode(t *testing.T) {
 	}
 }
 
+func TestStrictModeAcceptsJSONTypes(t *testing.T) {
+	type Tag struct {
+		Label string  `json:"label"`
+		Score float64 `json:"score"`
+	}
+	type Doc struct {
+		Count int   `json:"count"`
+		Tags  []Tag `json:"tags"`
+	}
+
+	// Values as decoded by encoding/json: numbers are float64
+	doc, err := ValidateStrict[Doc](map[string]any{
+		"count": float64(3),
+		"tags":  []any{map[string]any{"label": "a", "score": 0.5}},
+	})
+	if err != nil {
+		t.Fatalf("ValidateStrict() error = %v", err)
+	}
+	if doc.Count != 3 || len(doc.Tags) != 1 || doc.Tags[0].Label != "a" {
+		t.Errorf("ValidateStrict() = %+v", doc)
+	}
+
+	if _, err := ValidateStrict[Doc](map[string]any{"count": 2.5}); err == nil {
+		t.Error("ValidateStrict() accepted a fractional number for an int field")
+	}
+}
+
 // Test constrained types
 type Product struct {
 	ID    schema.UUID          `json:"id" validate:"required"`
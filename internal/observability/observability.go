@@ -5,11 +5,13 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"strconv"
 	"time"
 
+	"github.com/aixgo-dev/aixgo/internal/agent"
 	"go.opentelemetry.io/otel"
 	"go.opentelemetry.io/otel/attribute"
-	"go.opentelemetry.io/otel/exporters/otlp/otlptrace"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
 	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
 	"go.opentelemetry.io/otel/exporters/stdout/stdouttrace"
 	"go.opentelemetry.io/otel/sdk/resource"
@@ -26,6 +28,12 @@ const (
 	LangfuseEndpoint = "https://cloud.langfuse.com/api/public/otel"
 )
 
+// OTLP transport protocols for Config.Protocol
+const (
+	OTLPProtocolHTTP = "http"
+	OTLPProtocolGRPC = "grpc"
+)
+
 var (
 	// Global tracer provider
 	tracerProvider *sdktrace.TracerProvider
@@ -50,6 +58,21 @@ type Config struct {
 
 	// OTLPHeaders are additional headers for OTLP requests (e.g., authorization)
 	OTLPHeaders map[string]string
+
+	// Protocol is the OTLP transport: OTLPProtocolHTTP (default) or
+	// OTLPProtocolGRPC
+	Protocol string
+
+	// OTLPInsecure disables TLS to the OTLP endpoint
+	OTLPInsecure bool
+
+	// SampleRatio is the fraction of new traces recorded, in [0, 1].
+	// Zero means 1 (sample everything). Child spans follow their parent.
+	SampleRatio float64
+
+	// Exporter replaces the exporter selected by ExporterType when set,
+	// e.g. an in-memory exporter in tests
+	Exporter sdktrace.SpanExporter
 }
 
 // InitFromEnv initializes observability from environment variables
@@ -58,6 +81,8 @@ type Config struct {
 // - OTEL_TRACES_EXPORTER: Exporter type - "otlp", "stdout", or "none" (default: "otlp")
 // - OTEL_EXPORTER_OTLP_ENDPOINT: OTLP endpoint (default: Langfuse endpoint)
 // - OTEL_EXPORTER_OTLP_HEADERS: Headers in format "key1=value1,key2=value2"
+// - OTEL_EXPORTER_OTLP_PROTOCOL: "grpc" or "http/protobuf" (default: "http/protobuf")
+// - OTEL_TRACES_SAMPLER_ARG: Fraction of traces sampled (default: 1)
 // - LANGFUSE_PUBLIC_KEY: Langfuse public key (for Basic Auth)
 // - LANGFUSE_SECRET_KEY: Langfuse secret key (for Basic Auth)
 func InitFromEnv() error {
@@ -68,6 +93,16 @@ func InitFromEnv() error {
 		OTLPEndpoint: getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", LangfuseEndpoint),
 		OTLPHeaders:  parseHeaders(getEnv("OTEL_EXPORTER_OTLP_HEADERS", "")),
 	}
+	if getEnv("OTEL_EXPORTER_OTLP_PROTOCOL", "") == OTLPProtocolGRPC {
+		config.Protocol = OTLPProtocolGRPC
+	}
+	if arg := os.Getenv("OTEL_TRACES_SAMPLER_ARG"); arg != "" {
+		ratio, err := strconv.ParseFloat(arg, 64)
+		if err != nil {
+			return fmt.Errorf("invalid OTEL_TRACES_SAMPLER_ARG: %w", err)
+		}
+		config.SampleRatio = ratio
+	}
 
 	// Add Langfuse credentials if provided
 	publicKey := os.Getenv("LANGFUSE_PUBLIC_KEY")
@@ -91,6 +126,17 @@ func Init(config Config) error {
 		return nil
 	}
 
+	if config.SampleRatio < 0 || config.SampleRatio > 1 {
+		return fmt.Errorf("sample ratio must be between 0 and 1, got %v", config.SampleRatio)
+	}
+	sampleRatio := config.SampleRatio
+	if sampleRatio == 0 {
+		sampleRatio = 1
+	}
+	if config.ServiceName == "" {
+		config.ServiceName = DefaultServiceName
+	}
+
 	// Create resource
 	res, err := resource.New(
 		context.Background(),
@@ -103,16 +149,17 @@ func Init(config Config) error {
 	}
 
 	// Create exporter based on type
-	var exporter sdktrace.SpanExporter
-	switch config.ExporterType {
-	case "otlp":
+	exporter := config.Exporter
+	switch {
+	case exporter != nil:
+	case config.ExporterType == "otlp":
 		exporter, err = createOTLPExporter(config)
 		if err != nil {
 			return fmt.Errorf("failed to create OTLP exporter: %w", err)
 		}
 		log.Printf("Observability initialized with OTLP exporter (endpoint: %s)", config.OTLPEndpoint)
 
-	case "stdout":
+	case config.ExporterType == "stdout":
 		exporter, err = stdouttrace.New(stdouttrace.WithPrettyPrint())
 		if err != nil {
 			return fmt.Errorf("failed to create stdout exporter: %w", err)
@@ -127,6 +174,7 @@ func Init(config Config) error {
 	tracerProvider = sdktrace.NewTracerProvider(
 		sdktrace.WithBatcher(exporter),
 		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(sampleRatio))),
 	)
 
 	// Set global tracer provider
@@ -154,7 +202,8 @@ func Shutdown(ctx context.Context) error {
 	return tracerProvider.Shutdown(ctx)
 }
 
-// StartSpan creates a new span with the given name and attributes (legacy version with map)
+// StartSpan creates a new span with the given name and attributes (legacy version with map).
+// It has no context to take a request ID from; pass one in data as "request_id".
 // Deprecated: Use StartSpanWithContext for context-aware tracing
 func StartSpan(name string, data map[string]any) *Span {
 	// If tracer is not initialized, use a noop tracer
@@ -186,6 +235,7 @@ func StartSpan(name string, data map[string]any) *Span {
 // StartSpanWithOtel creates a new span with the given name and OpenTelemetry options.
 // This is the preferred method for context-aware tracing.
 // Returns a context with the span and the raw OpenTelemetry span.
+// The request ID in ctx, if any, is recorded as the request_id attribute.
 func StartSpanWithOtel(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
 	// Get the tracer (will use global if not initialized)
 	tr := tracer
@@ -193,10 +243,14 @@ func StartSpanWithOtel(ctx context.Context, name string, opts ...trace.SpanStart
 		tr = otel.GetTracerProvider().Tracer(DefaultServiceName)
 	}
 
+	if id := agent.RequestIDFromContext(ctx); id != "" {
+		opts = append(opts, trace.WithAttributes(attribute.String(agent.MetadataKeyRequestID, id)))
+	}
 	return tr.Start(ctx, name, opts...)
 }
 
-// StartSpanWithContext creates a new span from a parent context
+// StartSpanWithContext creates a new span from a parent context. The request
+// ID in ctx, if any, is recorded as the request_id attribute.
 func StartSpanWithContext(ctx context.Context, name string, data map[string]any) (context.Context, *Span) {
 	// If tracer is not initialized, use a noop tracer
 	if tracer == nil {
@@ -204,6 +258,9 @@ func StartSpanWithContext(ctx context.Context, name string, data map[string]any)
 	}
 
 	spanCtx, span := tracer.Start(ctx, name)
+	if id := agent.RequestIDFromContext(ctx); id != "" {
+		span.SetAttributes(attribute.String(agent.MetadataKeyRequestID, id))
+	}
 
 	// Convert data to attributes
 	if data != nil {
@@ -279,17 +336,37 @@ func (s *Span) SetError(err error) {
 // Helper functions
 
 func createOTLPExporter(config Config) (sdktrace.SpanExporter, error) {
-	opts := []otlptracehttp.Option{
-		otlptracehttp.WithEndpoint(config.OTLPEndpoint),
-	}
+	ctx := context.Background()
 
-	// Add headers if provided
-	if len(config.OTLPHeaders) > 0 {
-		opts = append(opts, otlptracehttp.WithHeaders(config.OTLPHeaders))
-	}
+	switch config.Protocol {
+	case "", OTLPProtocolHTTP:
+		opts := []otlptracehttp.Option{
+			otlptracehttp.WithEndpoint(config.OTLPEndpoint),
+		}
+		if config.OTLPInsecure {
+			opts = append(opts, otlptracehttp.WithInsecure())
+		}
+		// Add headers if provided
+		if len(config.OTLPHeaders) > 0 {
+			opts = append(opts, otlptracehttp.WithHeaders(config.OTLPHeaders))
+		}
+		return otlptracehttp.New(ctx, opts...)
 
-	client := otlptracehttp.NewClient(opts...)
-	return otlptrace.New(context.Background(), client)
+	case OTLPProtocolGRPC:
+		opts := []otlptracegrpc.Option{
+			otlptracegrpc.WithEndpoint(config.OTLPEndpoint),
+		}
+		if config.OTLPInsecure {
+			opts = append(opts, otlptracegrpc.WithInsecure())
+		}
+		if len(config.OTLPHeaders) > 0 {
+			opts = append(opts, otlptracegrpc.WithHeaders(config.OTLPHeaders))
+		}
+		return otlptracegrpc.New(ctx, opts...)
+
+	default:
+		return nil, fmt.Errorf("unknown OTLP protocol: %s", config.Protocol)
+	}
 }
 
 func convertToAttribute(key string, value any) attribute.KeyValue {
@@ -1,7 +1,11 @@
 package observability
 
 import (
+	"context"
 	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 func TestStartSpan(t *testing.T) {
@@ -282,3 +286,92 @@ func intToString(n int) string {
 
 	return string(result)
 }
+
+func TestInitOTLPConfig(t *testing.T) {
+	otlp := func(cfg Config) Config {
+		cfg.Enabled = true
+		cfg.ExporterType = "otlp"
+		return cfg
+	}
+	tests := []struct {
+		name    string
+		cfg     Config
+		wantErr bool
+	}{
+		{"grpc", otlp(Config{Protocol: OTLPProtocolGRPC, OTLPEndpoint: "localhost:4317", OTLPInsecure: true}), false},
+		{"http", otlp(Config{OTLPEndpoint: "localhost:4318", OTLPInsecure: true}), false},
+		{"unknown protocol", otlp(Config{Protocol: "thrift", OTLPEndpoint: "localhost:4318"}), true},
+		{"sample ratio above 1", otlp(Config{SampleRatio: 1.5}), true},
+		{"negative sample ratio", otlp(Config{SampleRatio: -0.1}), true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Init(tt.cfg)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Init() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			_ = Shutdown(context.Background())
+		})
+	}
+}
+
+func TestInitSampling(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	if err := Init(Config{Enabled: true, Exporter: exporter, SampleRatio: 1}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer func() { _ = Shutdown(context.Background()) }()
+
+	ctx, parent := StartSpanWithContext(context.Background(), "parent", nil)
+	_, child := StartSpanWithContext(ctx, "child", map[string]any{"step": 1})
+	child.End()
+	parent.End()
+	StartSpan("legacy", nil).End()
+
+	if err := tracerProvider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	spans := exporter.GetSpans()
+	if len(spans) != 3 {
+		t.Fatalf("exported %d spans, want 3", len(spans))
+	}
+	if spans[0].Name != "child" || spans[0].Parent.SpanID() != spans[1].SpanContext.SpanID() {
+		t.Errorf("child span should be parented to %s, got %+v", spans[1].Name, spans[0].Parent)
+	}
+}
+
+func TestSpansRecordRequestID(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	if err := Init(Config{Enabled: true, Exporter: exporter, SampleRatio: 1}); err != nil {
+		t.Fatalf("Init() error = %v", err)
+	}
+	defer func() { _ = Shutdown(context.Background()) }()
+
+	ctx := agent.ContextWithRequestID(context.Background(), "req-123")
+	_, span := StartSpanWithContext(ctx, "wrapped", nil)
+	span.End()
+	_, otelSpan := StartSpanWithOtel(ctx, "otel")
+	otelSpan.End()
+	_, untagged := StartSpanWithOtel(context.Background(), "untagged")
+	untagged.End()
+
+	if err := tracerProvider.ForceFlush(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	for _, s := range exporter.GetSpans() {
+		var got string
+		for _, attr := range s.Attributes {
+			if attr.Key == agent.MetadataKeyRequestID {
+				got = attr.Value.AsString()
+			}
+		}
+		want := "req-123"
+		if s.Name == "untagged" {
+			want = ""
+		}
+		if got != want {
+			t.Errorf("span %s request_id = %q, want %q", s.Name, got, want)
+		}
+	}
+}
@@ -0,0 +1,247 @@
+package orchestration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
+)
+
+// ErrBudgetExceeded is returned when an agent call would push accumulated
+// spend past the orchestrator's cost ceiling.
+var ErrBudgetExceeded = errors.New("budget exceeded")
+
+// Metadata keys agents use to report usage on their response messages.
+// Budget tracking reads these to account for the actual cost of each call.
+const (
+	MetadataKeyModel        = agent.MetadataKeyModel
+	MetadataKeyInputTokens  = agent.MetadataKeyInputTokens
+	MetadataKeyOutputTokens = agent.MetadataKeyOutputTokens
+	MetadataKeyCostUSD      = agent.MetadataKeyCostUSD
+)
+
+// defaultEstimatedOutputTokens is the output size assumed when estimating the
+// cost of a call before it is made.
+const defaultEstimatedOutputTokens = 256
+
+// BudgetExceededError describes a call that was refused by the cost ceiling.
+// It matches ErrBudgetExceeded with errors.Is.
+type BudgetExceededError struct {
+	Agent        string
+	SpentUSD     float64
+	EstimatedUSD float64
+	MaxUSD       float64
+}
+
+func (e *BudgetExceededError) Error() string {
+	return fmt.Sprintf("budget exceeded: calling %s would cost ~$%.4f with $%.4f of $%.4f already spent",
+		e.Agent, e.EstimatedUSD, e.SpentUSD, e.MaxUSD)
+}
+
+// Is reports whether target is ErrBudgetExceeded
+func (e *BudgetExceededError) Is(target error) bool {
+	return target == ErrBudgetExceeded
+}
+
+// BudgetOption enforces a cost ceiling on an orchestrator. It is accepted by
+// NewRouter and NewSequential.
+type BudgetOption struct {
+	maxUSD       float64
+	calc         *cost.Calculator
+	agentModels  map[string]string
+	outputTokens int
+}
+
+// WithBudget aborts orchestration with ErrBudgetExceeded once the estimated
+// cost of the next agent call would push accumulated spend past maxUSD.
+// If calc is nil, cost.DefaultCalculator is used.
+func WithBudget(maxUSD float64, calc *cost.Calculator) BudgetOption {
+	if calc == nil {
+		calc = cost.DefaultCalculator
+	}
+	return BudgetOption{
+		maxUSD:       maxUSD,
+		calc:         calc,
+		outputTokens: defaultEstimatedOutputTokens,
+	}
+}
+
+// WithAgentModels tells the budget which model each agent uses so calls can be
+// priced before they happen. Agents not listed are priced from the model they
+// last reported in response metadata.
+func (o BudgetOption) WithAgentModels(models map[string]string) BudgetOption {
+	o.agentModels = models
+	return o
+}
+
+// WithEstimatedOutputTokens sets the output size assumed for pre-call estimates
+func (o BudgetOption) WithEstimatedOutputTokens(n int) BudgetOption {
+	if n > 0 {
+		o.outputTokens = n
+	}
+	return o
+}
+
+// BudgetStatus reports the spend an orchestrator has tracked
+type BudgetStatus struct {
+	SpentUSD float64               // Spend tracked by WithBudget
+	MaxUSD   float64               // Ceiling set by WithBudget, or 0 if none
+	Routes   map[string]RouteSpend // Per-route spend when route caps are set
+}
+
+// BudgetReporter is implemented by orchestrators that track spend. The
+// runtime collects it from registered orchestrators, see Runtime.BudgetStatus.
+type BudgetReporter interface {
+	BudgetStatus() BudgetStatus
+}
+
+var (
+	_ BudgetReporter = (*Router)(nil)
+	_ BudgetReporter = (*Sequential)(nil)
+)
+
+func (o BudgetOption) applyRouter(r *Router)         { r.budget = newBudget(o) }
+func (o BudgetOption) applySequential(s *Sequential) { s.budget = newBudget(o) }
+
+// budget tracks spend against a ceiling for one orchestrator
+type budget struct {
+	maxUSD       float64
+	calc         *cost.Calculator
+	agentModels  map[string]string
+	lastModels   map[string]string
+	outputTokens int
+	spent        float64
+	mu           sync.Mutex
+}
+
+func newBudget(o BudgetOption) *budget {
+	return &budget{
+		maxUSD:       o.maxUSD,
+		calc:         o.calc,
+		agentModels:  o.agentModels,
+		lastModels:   make(map[string]string),
+		outputTokens: o.outputTokens,
+	}
+}
+
+// reserve estimates the cost of calling target with input and returns an
+// error if it would exceed the ceiling. It returns the estimate so it can be
+// charged if the agent does not report its own usage.
+func (b *budget) reserve(target string, input *agent.Message) (float64, error) {
+	if b == nil {
+		return 0, nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	estimate := b.estimateLocked(target, input)
+	if b.spent+estimate > b.maxUSD {
+		return estimate, &BudgetExceededError{
+			Agent:        target,
+			SpentUSD:     b.spent,
+			EstimatedUSD: estimate,
+			MaxUSD:       b.maxUSD,
+		}
+	}
+	return estimate, nil
+}
+
+// charge records the cost of a completed call, preferring usage reported in
+// the response metadata over the pre-call estimate. It returns the amount
+// charged.
+func (b *budget) charge(target string, result *agent.Message, estimate float64) float64 {
+	if b == nil {
+		return 0
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	amount := estimate
+	if result != nil && result.Message != nil && result.Metadata != nil {
+		md := result.Metadata
+		if model, ok := md[MetadataKeyModel].(string); ok && model != "" {
+			b.lastModels[target] = model
+		}
+		if usd, ok := reportedCost(b.calc, b.lastModels[target], md); ok {
+			amount = usd
+		}
+	}
+
+	b.spent += amount
+	return amount
+}
+
+// reportedCost prices a call from the usage reported in its response
+// metadata. An explicit cost wins; otherwise tokens are priced for model.
+func reportedCost(calc *cost.Calculator, model string, md map[string]interface{}) (float64, bool) {
+	if usd, ok := agent.MetadataFloat(md, MetadataKeyCostUSD); ok {
+		return usd, true
+	}
+
+	in, hasIn := agent.MetadataFloat(md, MetadataKeyInputTokens)
+	out, hasOut := agent.MetadataFloat(md, MetadataKeyOutputTokens)
+	if model == "" || (!hasIn && !hasOut) {
+		return 0, false
+	}
+	c, err := calc.EstimateCost(model, int(in), int(out))
+	if err != nil {
+		return 0, false
+	}
+	return c.TotalCost, true
+}
+
+func (b *budget) estimateLocked(target string, input *agent.Message) float64 {
+	model := b.agentModels[target]
+	if model == "" {
+		model = b.lastModels[target]
+	}
+	if model == "" {
+		return 0
+	}
+
+	inputTokens := 0
+	if input != nil && input.Message != nil {
+		inputTokens = estimateTokens(input.Payload)
+	}
+
+	c, err := b.calc.EstimateCost(model, inputTokens, b.outputTokens)
+	if err != nil {
+		return 0
+	}
+	return c.TotalCost
+}
+
+// estimate returns the pre-call cost estimate of calling target with input
+func (b *budget) estimate(target string, input *agent.Message) float64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.estimateLocked(target, input)
+}
+
+func (b *budget) maxSpendUSD() float64 {
+	if b == nil {
+		return 0
+	}
+	return b.maxUSD
+}
+
+func (b *budget) spentUSD() float64 {
+	if b == nil {
+		return 0
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.spent
+}
+
+// estimateTokens approximates token count at ~4 characters per token
+func estimateTokens(text string) int {
+	return (len(text) + 3) / 4
+}
@@ -0,0 +1,139 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// usageAgent returns a fixed payload and reports token usage in metadata
+type usageAgent struct {
+	*MockAgent
+	model        string
+	inputTokens  int
+	outputTokens int
+}
+
+func (u *usageAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	_, _ = u.MockAgent.Execute(ctx, input)
+	return &agent.Message{Message: &pb.Message{
+		Payload: u.response,
+		Metadata: map[string]interface{}{
+			MetadataKeyModel:        u.model,
+			MetadataKeyInputTokens:  u.inputTokens,
+			MetadataKeyOutputTokens: u.outputTokens,
+		},
+	}}, nil
+}
+
+func newUsageAgent(name, response string, inputTokens, outputTokens int) *usageAgent {
+	return &usageAgent{
+		MockAgent:    NewMockAgent(name, "test", 0, response),
+		model:        "test-model",
+		inputTokens:  inputTokens,
+		outputTokens: outputTokens,
+	}
+}
+
+func budgetCalculator() *cost.Calculator {
+	calc := cost.NewCalculator()
+	// $1 per 1M tokens in either direction keeps the arithmetic obvious
+	calc.AddPricing(&cost.ModelPricing{Model: "test-model", InputPer1M: 1, OutputPer1M: 1})
+	return calc
+}
+
+func TestSequentialBudgetTracksSpend(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(newUsageAgent("step1", "a", 500_000, 500_000))
+	_ = rt.Register(newUsageAgent("step2", "b", 250_000, 250_000))
+
+	seq := NewSequential("pipeline", rt, []string{"step1", "step2"},
+		WithBudget(10, budgetCalculator()),
+	)
+
+	result, err := seq.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "in"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "b" {
+		t.Errorf("Execute() payload = %q, want %q", result.Payload, "b")
+	}
+	if got := seq.SpentUSD(); math.Abs(got-1.5) > 1e-9 {
+		t.Errorf("SpentUSD() = %v, want 1.5", got)
+	}
+}
+
+func TestSequentialBudgetExceeded(t *testing.T) {
+	rt := NewMockRuntime()
+	step1 := newUsageAgent("step1", "a", 600_000, 600_000)
+	step2 := newUsageAgent("step2", "b", 0, 0)
+	_ = rt.Register(step1)
+	_ = rt.Register(step2)
+
+	budget := WithBudget(1.0, budgetCalculator()).
+		WithAgentModels(map[string]string{"step2": "test-model"}).
+		WithEstimatedOutputTokens(1000)
+	seq := NewSequential("pipeline", rt, []string{"step1", "step2"}, budget)
+
+	_, err := seq.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "in"}})
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("Execute() error = %v, want ErrBudgetExceeded", err)
+	}
+
+	var budgetErr *BudgetExceededError
+	if !errors.As(err, &budgetErr) {
+		t.Fatalf("Execute() error = %T, want *BudgetExceededError", err)
+	}
+	if budgetErr.Agent != "step2" {
+		t.Errorf("BudgetExceededError.Agent = %q, want step2", budgetErr.Agent)
+	}
+	if step2.CallCount() != 0 {
+		t.Errorf("step2 called %d times after budget exhausted, want 0", step2.CallCount())
+	}
+}
+
+func TestRouterBudget(t *testing.T) {
+	rt := NewMockRuntime()
+	classifier := newUsageAgent("classifier", "simple", 100_000, 100_000)
+	cheap := newUsageAgent("cheap", "answer", 200_000, 200_000)
+	_ = rt.Register(classifier)
+	_ = rt.Register(cheap)
+
+	router := NewRouter("router", rt, "classifier", map[string]string{"simple": "cheap"},
+		WithDefaultRoute("cheap"),
+		WithBudget(0.7, budgetCalculator()),
+	)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	if _, err := router.Execute(context.Background(), input); err != nil {
+		t.Fatalf("first Execute() error = %v", err)
+	}
+	if got := router.SpentUSD(); math.Abs(got-0.6) > 1e-9 {
+		t.Errorf("SpentUSD() = %v, want 0.6", got)
+	}
+
+	// The second classifier call spends $0.20, leaving no room for the routed call
+	_, err := router.Execute(context.Background(), input)
+	if !errors.Is(err, ErrBudgetExceeded) {
+		t.Fatalf("second Execute() error = %v, want ErrBudgetExceeded", err)
+	}
+}
+
+func TestRouterWithoutBudget(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("classifier", "test", 0, "simple"))
+	_ = rt.Register(NewMockAgent("cheap", "test", 0, "answer"))
+
+	router := NewRouter("router", rt, "classifier", map[string]string{"simple": "cheap"})
+	if _, err := router.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "hi"}}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if router.SpentUSD() != 0 {
+		t.Errorf("SpentUSD() = %v without budget, want 0", router.SpentUSD())
+	}
+}
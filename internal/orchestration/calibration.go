@@ -0,0 +1,194 @@
+package orchestration
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+// ErrNoCalibrationData is returned when a calibration method is fitted on
+// an empty dataset
+var ErrNoCalibrationData = errors.New("no calibration data")
+
+// minCalibratedConfidence keeps calibrated confidences positive, since the
+// voting functions treat a zero confidence as unset
+const minCalibratedConfidence = 0.01
+
+// CalibrationSample is one labelled prediction used to fit a calibration
+type CalibrationSample struct {
+	Confidence float64 // Confidence the agent reported (0-1)
+	Correct    bool    // Whether the prediction turned out to be right
+}
+
+// CalibrationPoint is one point of a calibration curve
+type CalibrationPoint struct {
+	Reported   float64 // Reported confidence
+	Calibrated float64 // Confidence after calibration
+	Count      int     // Calibration samples behind the point (0 if not data-driven)
+}
+
+// CalibrationMethod maps agent-reported confidences to calibrated ones that
+// track observed accuracy
+type CalibrationMethod interface {
+	// Calibrate returns the calibrated confidence for a reported one
+	Calibrate(confidence float64) float64
+
+	// Curve returns the calibration mapping for inspection
+	Curve() []CalibrationPoint
+}
+
+// WithConfidenceCalibration calibrates agent-reported confidences before
+// voting, so the weighted vote and the reported agreement use calibrated
+// values
+func WithConfidenceCalibration(method CalibrationMethod) EnsembleOption {
+	return func(e *Ensemble) {
+		e.calibration = method
+	}
+}
+
+// CalibrationCurve returns the configured calibration curve, or nil if
+// confidences are not calibrated
+func (e *Ensemble) CalibrationCurve() []CalibrationPoint {
+	if e.calibration == nil {
+		return nil
+	}
+	return e.calibration.Curve()
+}
+
+// TemperatureScaling divides the log-odds of a confidence by Temperature.
+// Temperatures above 1 soften overconfident agents; below 1 sharpen
+// underconfident ones.
+type TemperatureScaling struct {
+	Temperature float64
+}
+
+// FitTemperatureScaling returns the temperature that minimizes the negative
+// log-likelihood of the samples
+func FitTemperatureScaling(samples []CalibrationSample) (*TemperatureScaling, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoCalibrationData
+	}
+
+	nll := func(logT float64) float64 {
+		ts := TemperatureScaling{Temperature: math.Exp(logT)}
+		var loss float64
+		for _, s := range samples {
+			p := ts.scale(s.Confidence)
+			if !s.Correct {
+				p = 1 - p
+			}
+			loss -= math.Log(math.Max(p, 1e-12))
+		}
+		return loss
+	}
+
+	// The loss is unimodal in log T, so a golden-section search suffices
+	lo, hi := math.Log(0.05), math.Log(20.0)
+	ratio := (math.Sqrt(5) - 1) / 2
+	for range 100 {
+		a := hi - ratio*(hi-lo)
+		b := lo + ratio*(hi-lo)
+		if nll(a) < nll(b) {
+			hi = b
+		} else {
+			lo = a
+		}
+	}
+	return &TemperatureScaling{Temperature: math.Exp((lo + hi) / 2)}, nil
+}
+
+// Calibrate applies the temperature to the confidence's log-odds
+func (t *TemperatureScaling) Calibrate(confidence float64) float64 {
+	return math.Max(t.scale(confidence), minCalibratedConfidence)
+}
+
+func (t *TemperatureScaling) scale(confidence float64) float64 {
+	temp := t.Temperature
+	if temp <= 0 {
+		temp = 1
+	}
+	p := math.Min(math.Max(confidence, 1e-6), 1-1e-6)
+	logit := math.Log(p / (1 - p))
+	return 1 / (1 + math.Exp(-logit/temp))
+}
+
+// Curve samples the mapping at 0.05, 0.15, ..., 0.95
+func (t *TemperatureScaling) Curve() []CalibrationPoint {
+	points := make([]CalibrationPoint, 10)
+	for i := range points {
+		reported := 0.05 + 0.1*float64(i)
+		points[i] = CalibrationPoint{Reported: reported, Calibrated: t.Calibrate(reported)}
+	}
+	return points
+}
+
+// HistogramBinning splits [0, 1] into equal-width bins and maps each
+// confidence to the observed accuracy of the calibration samples in its
+// bin. Confidences falling in bins without samples are left unchanged.
+type HistogramBinning struct {
+	accuracy []float64
+	counts   []int
+	reported []float64 // Mean reported confidence per bin
+}
+
+// NewHistogramBinning fits bins equal-width bins on the samples
+func NewHistogramBinning(samples []CalibrationSample, bins int) (*HistogramBinning, error) {
+	if len(samples) == 0 {
+		return nil, ErrNoCalibrationData
+	}
+	if bins <= 0 {
+		return nil, fmt.Errorf("histogram binning needs at least one bin, got %d", bins)
+	}
+
+	h := &HistogramBinning{
+		accuracy: make([]float64, bins),
+		counts:   make([]int, bins),
+		reported: make([]float64, bins),
+	}
+	for _, s := range samples {
+		i := h.bin(s.Confidence)
+		h.counts[i]++
+		h.reported[i] += s.Confidence
+		if s.Correct {
+			h.accuracy[i]++
+		}
+	}
+	for i, n := range h.counts {
+		if n > 0 {
+			h.accuracy[i] /= float64(n)
+			h.reported[i] /= float64(n)
+		}
+	}
+	return h, nil
+}
+
+func (h *HistogramBinning) bin(confidence float64) int {
+	i := int(confidence * float64(len(h.counts)))
+	return min(max(i, 0), len(h.counts)-1)
+}
+
+// Calibrate returns the observed accuracy of the confidence's bin
+func (h *HistogramBinning) Calibrate(confidence float64) float64 {
+	i := h.bin(confidence)
+	if h.counts[i] == 0 {
+		return confidence
+	}
+	return math.Max(h.accuracy[i], minCalibratedConfidence)
+}
+
+// Curve returns one point per non-empty bin, at the bin's mean reported
+// confidence
+func (h *HistogramBinning) Curve() []CalibrationPoint {
+	points := make([]CalibrationPoint, 0, len(h.counts))
+	for i, n := range h.counts {
+		if n == 0 {
+			continue
+		}
+		points = append(points, CalibrationPoint{
+			Reported:   h.reported[i],
+			Calibrated: h.Calibrate(h.reported[i]),
+			Count:      n,
+		})
+	}
+	return points
+}
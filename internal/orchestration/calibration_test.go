@@ -0,0 +1,129 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// overconfidentSamples reports 0.95 confidence but is right 60% of the time,
+// and 0.65 confidence while right 50% of the time
+func overconfidentSamples() []CalibrationSample {
+	var samples []CalibrationSample
+	for i := range 100 {
+		samples = append(samples, CalibrationSample{Confidence: 0.95, Correct: i%10 < 6})
+		samples = append(samples, CalibrationSample{Confidence: 0.65, Correct: i%2 == 0})
+	}
+	return samples
+}
+
+func TestCalibrationPullsTowardAccuracy(t *testing.T) {
+	samples := overconfidentSamples()
+
+	temp, err := FitTemperatureScaling(samples)
+	if err != nil {
+		t.Fatalf("FitTemperatureScaling() error = %v", err)
+	}
+	hist, err := NewHistogramBinning(samples, 10)
+	if err != nil {
+		t.Fatalf("NewHistogramBinning() error = %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		method CalibrationMethod
+		maxErr float64 // Allowed distance from observed accuracy
+	}{
+		{"temperature scaling", temp, 0.15},
+		{"histogram binning", hist, 1e-9},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			for _, c := range []struct{ reported, accuracy float64 }{{0.95, 0.6}, {0.65, 0.5}} {
+				got := tt.method.Calibrate(c.reported)
+				if got >= c.reported {
+					t.Errorf("Calibrate(%v) = %v, want it lowered", c.reported, got)
+				}
+				if math.Abs(got-c.accuracy) > tt.maxErr {
+					t.Errorf("Calibrate(%v) = %v, want within %v of accuracy %v", c.reported, got, tt.maxErr, c.accuracy)
+				}
+			}
+			if curve := tt.method.Curve(); len(curve) == 0 {
+				t.Error("Curve() is empty")
+			}
+		})
+	}
+
+	if temp.Temperature <= 1 {
+		t.Errorf("Temperature = %v, want > 1 for overconfident samples", temp.Temperature)
+	}
+	curve := hist.Curve()
+	if len(curve) != 2 || curve[1].Count != 100 || curve[1].Calibrated != 0.6 {
+		t.Errorf("histogram Curve() = %+v, want two bins of 100 samples", curve)
+	}
+
+	if _, err := FitTemperatureScaling(nil); !errors.Is(err, ErrNoCalibrationData) {
+		t.Errorf("FitTemperatureScaling(nil) error = %v, want ErrNoCalibrationData", err)
+	}
+}
+
+// confidentAgent answers with a fixed payload and reported confidence
+type confidentAgent struct {
+	*MockAgent
+	confidence float64
+}
+
+func (a *confidentAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return &agent.Message{Message: &pb.Message{
+		Payload:  a.response,
+		Metadata: map[string]any{"confidence": a.confidence},
+	}}, nil
+}
+
+func TestEnsembleConfidenceCalibration(t *testing.T) {
+	rt := NewMockRuntime()
+	for _, m := range []struct {
+		name, vote string
+		confidence float64
+	}{
+		{"a", "approve", 0.95},
+		{"b", "approve", 0.95},
+		{"c", "reject", 0.65},
+	} {
+		_ = rt.Register(&confidentAgent{MockAgent: NewMockAgent(m.name, "test", 0, m.vote), confidence: m.confidence})
+	}
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	models := []string{"a", "b", "c"}
+
+	// Raw confidences: 1.9 of 2.55 total weight
+	raw, err := NewEnsemble("ensemble", rt, models, WithVotingStrategy(VotingWeighted)).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	rawAgreement := raw.Metadata[MetadataKeyAgreement].(float64)
+
+	// Calibrated: 1.2 of 1.7, so the overconfident majority counts for less
+	hist, err := NewHistogramBinning(overconfidentSamples(), 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	calibrated := NewEnsemble("ensemble", rt, models,
+		WithVotingStrategy(VotingWeighted),
+		WithConfidenceCalibration(hist),
+		WithAgreementThreshold(0.72),
+	)
+	_, err = calibrated.Execute(context.Background(), input)
+	if !errors.Is(err, ErrInsufficientAgreement) {
+		t.Fatalf("Execute() error = %v, want ErrInsufficientAgreement once calibrated (raw agreement %.3f)", err, rawAgreement)
+	}
+	if rawAgreement < 0.72 {
+		t.Errorf("raw agreement = %v, want above the threshold", rawAgreement)
+	}
+	if len(calibrated.CalibrationCurve()) != 2 {
+		t.Errorf("CalibrationCurve() = %+v", calibrated.CalibrationCurve())
+	}
+}
@@ -0,0 +1,154 @@
+package orchestration
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// Metadata keys set on a RAG answer when citations are enabled
+const (
+	// MetadataKeyCitations holds the []Citation that check out against the
+	// retrieved documents
+	MetadataKeyCitations = "citations"
+
+	// MetadataKeyHallucinatedCitations holds the []Citation that name an
+	// unknown document or quote text the document does not contain
+	MetadataKeyHallucinatedCitations = "hallucinated_citations"
+)
+
+// Citation is a generator's reference to a retrieved document
+type Citation struct {
+	DocID string  `json:"doc_id"`
+	Quote string  `json:"quote,omitempty"`
+	Score float64 `json:"score,omitempty"`
+}
+
+// RAGResult is the structured reply a generator gives when citations are
+// enabled: the answer text and the retrieved documents it relies on
+type RAGResult struct {
+	Answer    string     `json:"answer"`
+	Citations []Citation `json:"citations,omitempty"`
+}
+
+// citationInstructions is appended to the generator input when citations
+// are enabled
+const citationInstructions = "Reply with JSON only, in the form " +
+	`{"answer": "...", "citations": [{"doc_id": "0", "quote": "...", "score": 0.9}]}` +
+	", citing each context document you rely on by its [id] and quoting it verbatim."
+
+// WithCitations asks the generator for a RAGResult citing the retrieved
+// documents, which are labelled [0], [1], ... in its context. The answer
+// becomes the result payload; each citation is checked against the cited
+// document, and those naming an unknown document or quoting text it does
+// not contain are flagged under hallucinated_citations.
+func WithCitations() RAGOption {
+	return func(r *RAG) {
+		r.citations = true
+	}
+}
+
+// augmentWithCitations builds the generator input with labelled documents
+// and the structured reply instructions
+func augmentWithCitations(query, documents *agent.Message) *agent.Message {
+	docs := splitDocuments(documents)
+	if query == nil || query.Message == nil || len(docs) == 0 {
+		return augmentInput(query, documents)
+	}
+
+	labelled := make([]string, len(docs))
+	for i, doc := range docs {
+		labelled[i] = fmt.Sprintf("[%d] %s", i, doc)
+	}
+	msg := augmentInput(query, &agent.Message{Message: &pb.Message{
+		Payload:  strings.Join(labelled, documentSeparator),
+		Metadata: documents.Metadata,
+	}})
+	msg.Payload += "\n\n" + citationInstructions
+	return msg
+}
+
+// parseAnswer turns a generator reply into the answer returned to the
+// caller, extracting its citations when they are enabled. The verifier
+// checks the parsed answer, not the raw reply.
+func (r *RAG) parseAnswer(answer, documents *agent.Message) *agent.Message {
+	if !r.citations {
+		return answer
+	}
+	return applyCitations(answer, documents)
+}
+
+// applyCitations parses the generator's reply into a RAGResult, checks its
+// citations against documents, and records them in the answer's metadata.
+// A reply that is not a RAGResult is returned unchanged.
+func applyCitations(answer, documents *agent.Message) *agent.Message {
+	result, ok := parseRAGResult(payloadOf(answer))
+	if !ok {
+		return answer
+	}
+
+	docs := splitDocuments(documents)
+	var valid, hallucinated []Citation
+	for _, c := range result.Citations {
+		if citationSupported(c, docs) {
+			valid = append(valid, c)
+		} else {
+			hallucinated = append(hallucinated, c)
+		}
+	}
+
+	out := answer.Clone()
+	out.Payload = result.Answer
+	if out.Metadata == nil {
+		out.Metadata = make(map[string]any)
+	}
+	out.Metadata[MetadataKeyCitations] = valid
+	if len(hallucinated) > 0 {
+		out.Metadata[MetadataKeyHallucinatedCitations] = hallucinated
+	}
+	return out
+}
+
+// parseRAGResult decodes a generator reply, allowing a markdown code fence
+func parseRAGResult(payload string) (*RAGResult, bool) {
+	payload = strings.TrimSpace(payload)
+	if strings.HasPrefix(payload, "```") {
+		payload = strings.TrimPrefix(payload, "```json")
+		payload = strings.TrimPrefix(payload, "```")
+		payload = strings.TrimSuffix(strings.TrimSpace(payload), "```")
+	}
+
+	var result RAGResult
+	if err := json.Unmarshal([]byte(payload), &result); err != nil || result.Answer == "" {
+		return nil, false
+	}
+	return &result, true
+}
+
+// citationSupported reports whether c names one of docs and, if it has a
+// quote, whether that document contains it (ignoring case and spacing)
+func citationSupported(c Citation, docs []string) bool {
+	i, err := strconv.Atoi(strings.Trim(strings.TrimSpace(c.DocID), "[]"))
+	if err != nil || i < 0 || i >= len(docs) {
+		return false
+	}
+	quote := normalizeQuote(c.Quote)
+	return quote == "" || strings.Contains(normalizeQuote(docs[i]), quote)
+}
+
+func normalizeQuote(s string) string {
+	return strings.ToLower(strings.Join(strings.Fields(s), " "))
+}
+
+// splitDocuments returns the individual documents of a retrieval payload
+func splitDocuments(documents *agent.Message) []string {
+	payload := payloadOf(documents)
+	if payload == "" {
+		return nil
+	}
+	return strings.Split(payload, documentSeparator)
+}
@@ -0,0 +1,102 @@
+package orchestration
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+func TestRAGCitations(t *testing.T) {
+	rt := NewMockRuntime()
+	generator := newScriptedAgent("generator", "```json\n"+`{
+		"answer": "Aixgo supports NATS and gRPC.",
+		"citations": [
+			{"doc_id": "0", "quote": "supports  NATS", "score": 0.9},
+			{"doc_id": "1", "quote": "gRPC transport is built in"},
+			{"doc_id": "1", "quote": "Kafka is supported"},
+			{"doc_id": "7", "quote": "anything"}
+		]
+	}`+"\n```")
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Aixgo supports NATS.\n---\nThe gRPC transport is built in."))
+	_ = rt.Register(generator)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "Which transports?"}}
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithCitations()).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if !strings.Contains(generator.inputs[0], "[0] Aixgo supports NATS.\n---\n[1] The gRPC transport") {
+		t.Errorf("generator context should label documents, got %q", generator.inputs[0])
+	}
+	if result.Payload != "Aixgo supports NATS and gRPC." {
+		t.Errorf("Execute() payload = %q, want the parsed answer", result.Payload)
+	}
+
+	wantValid := []Citation{
+		{DocID: "0", Quote: "supports  NATS", Score: 0.9},
+		{DocID: "1", Quote: "gRPC transport is built in"},
+	}
+	if got := result.Metadata[MetadataKeyCitations]; !reflect.DeepEqual(got, wantValid) {
+		t.Errorf("citations = %v, want %v", got, wantValid)
+	}
+	wantFlagged := []Citation{
+		{DocID: "1", Quote: "Kafka is supported"},
+		{DocID: "7", Quote: "anything"},
+	}
+	if got := result.Metadata[MetadataKeyHallucinatedCitations]; !reflect.DeepEqual(got, wantFlagged) {
+		t.Errorf("hallucinated citations = %v, want %v", got, wantFlagged)
+	}
+}
+
+func TestRAGCitationsUnstructuredReply(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Doc A"))
+	_ = rt.Register(newScriptedAgent("generator", "plain answer"))
+
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithCitations()).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "plain answer" || result.Metadata[MetadataKeyCitations] != nil {
+		t.Errorf("a reply that is not a RAGResult should pass through, got %q %v", result.Payload, result.Metadata)
+	}
+}
+
+func TestRAGCitationsVerifiesParsedAnswer(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Doc A"))
+	_ = rt.Register(newScriptedAgent("generator",
+		`{"answer": "first answer", "citations": [{"doc_id": "0"}]}`,
+		`{"answer": "second answer", "citations": [{"doc_id": "0"}]}`,
+	))
+	verifier := newScriptedAgent("verifier", `{"groundedness": 0.2}`, `{"groundedness": 0.9}`)
+	_ = rt.Register(verifier)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithCitations(), WithVerifier("verifier")).
+		Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	if len(verifier.inputs) != 2 {
+		t.Fatalf("verifier called %d times, want 2", len(verifier.inputs))
+	}
+	for i, want := range []string{"first answer", "second answer"} {
+		if !strings.Contains(verifier.inputs[i], `"answer":"`+want+`"`) || strings.Contains(verifier.inputs[i], "citations") {
+			t.Errorf("verifier input %d = %q, want the parsed answer %q", i, verifier.inputs[i], want)
+		}
+	}
+	if result.Payload != "second answer" || result.Metadata[MetadataKeyGroundedness] != 0.9 {
+		t.Errorf("Execute() = %q %v, want the verified second answer", result.Payload, result.Metadata)
+	}
+	if got := result.Metadata[MetadataKeyCitations]; !reflect.DeepEqual(got, []Citation{{DocID: "0"}}) {
+		t.Errorf("citations = %v, want doc 0", got)
+	}
+}
@@ -2,6 +2,7 @@ package orchestration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
@@ -26,8 +27,26 @@ type Ensemble struct {
 	models         []string
 	votingStrategy VotingStrategy
 	threshold      float64 // Minimum agreement threshold
+	belowThreshold EnsembleBehavior
+	calibration    CalibrationMethod // Optional; applied to reported confidences
 }
 
+// EnsembleBehavior defines what an Ensemble does when agreement falls below
+// its threshold
+type EnsembleBehavior string
+
+const (
+	EnsembleFail       EnsembleBehavior = "fail"        // Return ErrInsufficientAgreement
+	EnsembleBestEffort EnsembleBehavior = "best_effort" // Return the winning answer flagged low_confidence
+)
+
+// MetadataKeyAgreement holds the agreement level (0-1) of an Ensemble result
+const MetadataKeyAgreement = "agreement"
+
+// ErrInsufficientAgreement is returned when models agree less than the
+// threshold and the Ensemble is configured to fail
+var ErrInsufficientAgreement = errors.New("insufficient agreement")
+
 // VotingStrategy defines how ensemble votes are aggregated
 type VotingStrategy string
 
@@ -55,6 +74,14 @@ func WithAgreementThreshold(threshold float64) EnsembleOption {
 	}
 }
 
+// WithBelowThresholdBehavior sets what happens when agreement is below the
+// threshold (default: EnsembleFail)
+func WithBelowThresholdBehavior(behavior EnsembleBehavior) EnsembleOption {
+	return func(e *Ensemble) {
+		e.belowThreshold = behavior
+	}
+}
+
 // NewEnsemble creates a new Ensemble orchestrator
 func NewEnsemble(name string, runtime agent.Runtime, models []string, opts ...EnsembleOption) *Ensemble {
 	e := &Ensemble{
@@ -62,6 +89,7 @@ func NewEnsemble(name string, runtime agent.Runtime, models []string, opts ...En
 		models:           models,
 		votingStrategy:   VotingMajority,
 		threshold:        0.5, // 50% agreement required
+		belowThreshold:   EnsembleFail,
 	}
 
 	for _, opt := range opts {
@@ -73,6 +101,7 @@ func NewEnsemble(name string, runtime agent.Runtime, models []string, opts ...En
 
 // Execute runs all models and aggregates via voting
 func (e *Ensemble) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.ensemble.%s", e.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "ensemble"),
@@ -86,7 +115,7 @@ func (e *Ensemble) Execute(ctx context.Context, input *agent.Message) (*agent.Me
 	startTime := time.Now()
 
 	// Execute all models in parallel
-	results, errors := e.runtime.CallParallel(ctx, e.models, input)
+	results, errors := callParallelCloned(ctx, e.runtime, e.models, input, 0)
 
 	duration := time.Since(startTime)
 
@@ -115,9 +144,17 @@ func (e *Ensemble) Execute(ctx context.Context, input *agent.Message) (*agent.Me
 		attribute.Bool("orchestration.success", true),
 	)
 
+	finalResult.Metadata[MetadataKeyAgreement] = agreement
+
 	// Check agreement threshold
 	if agreement < e.threshold {
-		return nil, fmt.Errorf("insufficient agreement: %.2f < %.2f", agreement, e.threshold)
+		if e.belowThreshold != EnsembleBestEffort {
+			err := fmt.Errorf("%w: %.2f < %.2f", ErrInsufficientAgreement, agreement, e.threshold)
+			span.RecordError(err)
+			return nil, err
+		}
+		span.SetAttributes(attribute.Bool("orchestration.low_confidence", true))
+		finalResult.Metadata[MetadataKeyLowConfidence] = true
 	}
 
 	return finalResult, nil
@@ -142,6 +179,9 @@ func (e *Ensemble) vote(results map[string]*agent.Message) (*agent.Message, floa
 			if confVal, ok := msg.Metadata["confidence"]; ok {
 				if confFloat, ok := confVal.(float64); ok {
 					confidence = confFloat
+					if e.calibration != nil {
+						confidence = e.calibration.Calibrate(confidence)
+					}
 				}
 			}
 		}
@@ -178,17 +218,20 @@ func (e *Ensemble) vote(results map[string]*agent.Message) (*agent.Message, floa
 	}
 
 	// Convert back to agent.Message
-	// Use the first result's message structure as template
+	// Use a copy of the first valid result's message structure as template
 	var template *agent.Message
 	for _, msg := range results {
-		template = msg
-		break
+		if msg != nil && msg.Message != nil {
+			template = msg
+			break
+		}
 	}
 
-	finalMsg := &agent.Message{
-		Message: template.Message,
-	}
+	finalMsg := template.Clone()
 	finalMsg.Payload = result.SelectedContent
+	if finalMsg.Metadata == nil {
+		finalMsg.Metadata = make(map[string]any)
+	}
 
 	return finalMsg, result.Agreement, nil
 }
@@ -0,0 +1,84 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// newSplitVoteRuntime registers five models voting 2-2-1, so the plurality
+// answer has 40% agreement
+func newSplitVoteRuntime() (*MockRuntime, []string) {
+	rt := NewMockRuntime()
+	votes := map[string]string{
+		"model-1": "approve",
+		"model-2": "approve",
+		"model-3": "reject",
+		"model-4": "reject",
+		"model-5": "escalate",
+	}
+	models := make([]string, 0, len(votes))
+	for name, vote := range votes {
+		_ = rt.Register(NewMockAgent(name, "test", 0, vote))
+		models = append(models, name)
+	}
+	return rt, models
+}
+
+func TestEnsembleBelowThreshold(t *testing.T) {
+	input := &agent.Message{Message: &pb.Message{Payload: "should we ship?"}}
+
+	t.Run("fail", func(t *testing.T) {
+		rt, models := newSplitVoteRuntime()
+		_, err := NewEnsemble("ensemble", rt, models, WithAgreementThreshold(0.6)).Execute(context.Background(), input)
+		if !errors.Is(err, ErrInsufficientAgreement) {
+			t.Errorf("Execute() error = %v, want ErrInsufficientAgreement", err)
+		}
+	})
+
+	t.Run("best effort", func(t *testing.T) {
+		rt, models := newSplitVoteRuntime()
+		result, err := NewEnsemble("ensemble", rt, models,
+			WithAgreementThreshold(0.6),
+			WithBelowThresholdBehavior(EnsembleBestEffort),
+		).Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if result.Payload != "approve" && result.Payload != "reject" {
+			t.Errorf("Execute() payload = %q, want a plurality answer", result.Payload)
+		}
+		if result.Metadata[MetadataKeyLowConfidence] != true {
+			t.Error("best-effort result should be flagged low_confidence")
+		}
+		if result.Metadata[MetadataKeyAgreement] != 0.4 {
+			t.Errorf("agreement = %v, want 0.4", result.Metadata[MetadataKeyAgreement])
+		}
+	})
+}
+
+func TestEnsembleAboveThreshold(t *testing.T) {
+	rt := NewMockRuntime()
+	for i, vote := range []string{"approve", "approve", "reject"} {
+		_ = rt.Register(NewMockAgent(string(rune('a'+i)), "test", 0, vote))
+	}
+
+	result, err := NewEnsemble("ensemble", rt, []string{"a", "b", "c"},
+		WithBelowThresholdBehavior(EnsembleBestEffort),
+	).Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "q"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "approve" {
+		t.Errorf("Execute() payload = %q, want approve", result.Payload)
+	}
+	if _, flagged := result.Metadata[MetadataKeyLowConfidence]; flagged {
+		t.Error("result above threshold should not be flagged low_confidence")
+	}
+	if agreement, _ := result.Metadata[MetadataKeyAgreement].(float64); agreement < 0.66 || agreement > 0.67 {
+		t.Errorf("agreement = %v, want 2/3", result.Metadata[MetadataKeyAgreement])
+	}
+}
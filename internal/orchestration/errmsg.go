@@ -0,0 +1,34 @@
+package orchestration
+
+import (
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+// ErrorMessagesOption makes an orchestrator report agent failures as error
+// messages (see agent.NewErrorMessage) instead of aborting. It is accepted
+// by NewSequential and NewParallel.
+type ErrorMessagesOption struct {
+	enabled bool
+}
+
+// WithErrorMessages enables error messages. Sequential passes a failed
+// step's error message to the next step as its input; Parallel hands failed
+// agents' error messages to the aggregate function alongside the successful
+// results. Downstream agents detect them with Message.IsError.
+func WithErrorMessages(enabled bool) ErrorMessagesOption {
+	return ErrorMessagesOption{enabled: enabled}
+}
+
+func (o ErrorMessagesOption) applySequential(s *Sequential) { s.errorMessages = o.enabled }
+func (o ErrorMessagesOption) applyParallel(p *Parallel)     { p.errorMessages = o.enabled }
+
+// withoutErrors returns the results that are not error messages
+func withoutErrors(results map[string]*agent.Message) map[string]*agent.Message {
+	out := make(map[string]*agent.Message, len(results))
+	for name, msg := range results {
+		if msg != nil && !msg.IsError() {
+			out[name] = msg
+		}
+	}
+	return out
+}
@@ -0,0 +1,103 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// recoveringAgent substitutes a default for error message inputs and echoes
+// anything else
+type recoveringAgent struct {
+	*MockAgent
+	fallback string
+}
+
+func (r *recoveringAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	if input.IsError() {
+		return &agent.Message{Message: &pb.Message{Payload: r.fallback}}, nil
+	}
+	return input, nil
+}
+
+func newRecoveringAgent(name, fallback string) *recoveringAgent {
+	return &recoveringAgent{MockAgent: NewMockAgent(name, "test", 0, ""), fallback: fallback}
+}
+
+func TestSequentialErrorMessages(t *testing.T) {
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "input"}}
+
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("draft", "test", 0, "draft"))
+	_ = rt.Register(newRecoveringAgent("recover", "default"))
+
+	// Without the option a failed step aborts the pipeline
+	_, err := NewSequential("seq", rt, []string{"missing", "recover"}).Execute(ctx, input)
+	if !errors.Is(err, agent.ErrAgentNotFound) {
+		t.Fatalf("Execute() error = %v, want ErrAgentNotFound", err)
+	}
+
+	// Downstream agents can substitute a default for the failure
+	result, err := NewSequential("seq", rt, []string{"missing", "recover"}, WithErrorMessages(true)).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "default" {
+		t.Errorf("Execute() payload = %q, want %q", result.Payload, "default")
+	}
+
+	// A failed last step is returned as an error message
+	result, err = NewSequential("seq", rt, []string{"draft", "missing"}, WithErrorMessages(true)).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	details, ok := result.ErrorDetails()
+	if !ok {
+		t.Fatalf("Execute() returned %q, want an error message", result.Payload)
+	}
+	if details.Agent != "missing" || details.Error != agent.ErrAgentNotFound.Error() {
+		t.Errorf("ErrorDetails() = %+v", details)
+	}
+}
+
+func TestParallelErrorMessages(t *testing.T) {
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "input"}}
+
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("a", "test", 0, "result-a"))
+
+	var seen map[string]*agent.Message
+	p := NewParallel("par", rt, []string{"a", "missing"},
+		WithErrorMessages(true),
+		WithAggregateFunc(func(results map[string]*agent.Message) (*agent.Message, error) {
+			seen = results
+			return ConcatAggregator("")(results)
+		}),
+	)
+
+	result, err := p.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if !seen["missing"].IsError() {
+		t.Errorf("aggregate func got %v for the failed agent, want an error message", seen["missing"])
+	}
+	if result.Payload != "result-a" {
+		t.Errorf("ConcatAggregator payload = %q, want only the successful result", result.Payload)
+	}
+
+	// All agents failing is reported as an error message
+	result, err = NewParallel("par", rt, []string{"missing", "gone"}, WithErrorMessages(true)).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	details, ok := result.ErrorDetails()
+	if !ok || details.Agent != "par" {
+		t.Errorf("Execute() = %q, want an error message from par", result.Payload)
+	}
+}
@@ -0,0 +1,117 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/observability"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metadata keys set on a Fallback result
+const (
+	// MetadataKeyFallbackIndex holds the chain index of the orchestrator
+	// that produced the result
+	MetadataKeyFallbackIndex = "fallback_index"
+
+	// MetadataKeyFallbackOrchestrator holds that orchestrator's name
+	MetadataKeyFallbackOrchestrator = "fallback_orchestrator"
+)
+
+// Fallback tries a chain of orchestrators in order and returns the first
+// successful result, degrading gracefully from e.g. an LLM-powered Ensemble
+// to a deterministic Router.
+//
+// Use cases:
+// - Primary/backup model or provider chains
+// - LLM strategy with a deterministic fallback
+// - Degrading from RAG to plain generation when retrieval is down
+type Fallback struct {
+	*BaseOrchestrator
+	chain []Orchestrator
+}
+
+// NewFallback creates a Fallback orchestrator over chain
+func NewFallback(name string, runtime agent.Runtime, chain []Orchestrator) *Fallback {
+	f := &Fallback{
+		BaseOrchestrator: NewBaseOrchestrator(name, "fallback", runtime),
+		chain:            chain,
+	}
+
+	f.SetReady(true)
+	return f
+}
+
+// Execute runs each orchestrator in turn until one succeeds. Results that
+// are error messages count as failures. If every orchestrator fails, the
+// returned error joins each failure.
+func (f *Fallback) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
+	names := make([]string, len(f.chain))
+	for i, o := range f.chain {
+		names[i] = o.Name()
+	}
+
+	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.fallback.%s", f.name),
+		trace.WithAttributes(
+			attribute.String("orchestration.pattern", "fallback"),
+			attribute.StringSlice("orchestration.chain", names),
+		),
+	)
+	defer span.End()
+
+	if len(f.chain) == 0 {
+		err := fmt.Errorf("fallback %s has no orchestrators", f.name)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	startTime := time.Now()
+	var failures []error
+
+	for i, o := range f.chain {
+		if err := ctx.Err(); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+
+		// Each attempt gets a fresh copy in case a failed one mutated it
+		result, err := o.Execute(ctx, input.Clone())
+		if err == nil {
+			if result == nil {
+				err = errors.New("returned no message")
+			} else if details, ok := result.ErrorDetails(); ok {
+				err = errors.New(details.Error)
+			}
+		}
+		if err != nil {
+			failures = append(failures, fmt.Errorf("%d (%s): %w", i, o.Name(), err))
+			span.SetAttributes(attribute.String(fmt.Sprintf("error.%d", i), err.Error()))
+			continue
+		}
+
+		span.SetAttributes(
+			attribute.Int64("orchestration.duration_ms", time.Since(startTime).Milliseconds()),
+			attribute.Int("orchestration.fallback_index", i),
+			attribute.Bool("orchestration.success", true),
+		)
+		if result.Message == nil {
+			result.Message = &pb.Message{}
+		}
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]any)
+		}
+		result.Metadata[MetadataKeyFallbackIndex] = i
+		result.Metadata[MetadataKeyFallbackOrchestrator] = o.Name()
+		return result, nil
+	}
+
+	err := fmt.Errorf("all %d orchestrators failed: %w", len(f.chain), errors.Join(failures...))
+	span.RecordError(err)
+	return nil, err
+}
@@ -0,0 +1,107 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// failingOrchestrator always fails with err
+type failingOrchestrator struct {
+	*BaseOrchestrator
+	err   error
+	calls int
+}
+
+func newFailingOrchestrator(name string, err error) *failingOrchestrator {
+	return &failingOrchestrator{BaseOrchestrator: NewBaseOrchestrator(name, "test", NewMockRuntime()), err: err}
+}
+
+func (f *failingOrchestrator) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	f.calls++
+	input.Metadata["touched"] = true
+	return nil, f.err
+}
+
+func TestFallbackUsesFirstSuccess(t *testing.T) {
+	ctx := context.Background()
+	rt := NewMockRuntime()
+	voter := NewMockAgent("voter", "test", 0, "majority: A")
+	_ = rt.Register(voter)
+
+	primary := newFailingOrchestrator("consensus", errors.New("llm unavailable"))
+	deterministic := NewSequential("voting", rt, []string{"voter"})
+
+	fallback := NewFallback("aggregate", rt, []Orchestrator{primary, deterministic})
+	input := &agent.Message{Message: &pb.Message{Payload: "inputs", Metadata: map[string]any{}}}
+
+	result, err := fallback.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "majority: A" {
+		t.Errorf("Execute() payload = %q", result.Payload)
+	}
+	if result.Metadata[MetadataKeyFallbackIndex] != 1 || result.Metadata[MetadataKeyFallbackOrchestrator] != "voting" {
+		t.Errorf("unexpected fallback metadata %v", result.Metadata)
+	}
+	if primary.calls != 1 || voter.CallCount() != 1 {
+		t.Errorf("calls = %d primary, %d voter, want 1 each", primary.calls, voter.CallCount())
+	}
+	if _, touched := input.Metadata["touched"]; touched {
+		t.Error("a failed attempt mutated the caller's input")
+	}
+
+	// A healthy primary short-circuits the chain
+	result, err = NewFallback("aggregate", rt, []Orchestrator{deterministic, primary}).Execute(ctx, input)
+	if err != nil || result.Metadata[MetadataKeyFallbackIndex] != 0 {
+		t.Errorf("Execute() = %v, %v, want index 0", result, err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("primary called %d times after a successful first orchestrator", primary.calls)
+	}
+}
+
+func TestFallbackAllFail(t *testing.T) {
+	rt := NewMockRuntime()
+	errLLM := errors.New("llm unavailable")
+	errAgent := NewSequential("missing-agent", rt, []string{"nonexistent"})
+
+	_, err := NewFallback("aggregate", rt, []Orchestrator{newFailingOrchestrator("consensus", errLLM), errAgent}).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{Metadata: map[string]any{}}})
+	if err == nil {
+		t.Fatal("expected error when every orchestrator fails")
+	}
+	if !errors.Is(err, errLLM) || !errors.Is(err, agent.ErrAgentNotFound) {
+		t.Errorf("error %v should wrap every failure", err)
+	}
+	for _, want := range []string{"all 2 orchestrators failed", "0 (consensus)", "1 (missing-agent)"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("error %q missing %q", err, want)
+		}
+	}
+
+	if _, err := NewFallback("empty", rt, nil).Execute(context.Background(), &agent.Message{Message: &pb.Message{}}); err == nil {
+		t.Error("expected error for empty chain")
+	}
+}
+
+func TestFallbackSkipsErrorMessages(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("backup", "test", 0, "ok"))
+
+	// With error messages enabled Sequential reports failure as a message, not an error
+	soft := NewSequential("soft", rt, []string{"nonexistent"}, WithErrorMessages(true))
+	result, err := NewFallback("f", rt, []Orchestrator{soft, NewSequential("backup", rt, []string{"backup"})}).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "ok" || result.Metadata[MetadataKeyFallbackIndex] != 1 {
+		t.Errorf("Execute() = %q %v, want backup result", result.Payload, result.Metadata)
+	}
+}
@@ -58,6 +58,7 @@ func NewHierarchical(name string, runtime agent.Runtime, manager string, teams m
 
 // Execute delegates task through hierarchical structure
 func (h *Hierarchical) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.hierarchical.%s", h.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "hierarchical"),
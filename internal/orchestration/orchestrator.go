@@ -6,6 +6,7 @@ import (
 	"sync"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
 )
 
 // Orchestrator defines the interface for agent orchestration patterns.
@@ -62,6 +63,12 @@ func (b *BaseOrchestrator) Pattern() string {
 	return b.pattern
 }
 
+// Role returns the pattern type, so an orchestrator can be registered with a
+// runtime as an agent
+func (b *BaseOrchestrator) Role() string {
+	return b.pattern
+}
+
 // Runtime returns the runtime
 func (b *BaseOrchestrator) Runtime() agent.Runtime {
 	return b.runtime
@@ -95,3 +102,85 @@ func (b *BaseOrchestrator) Start(ctx context.Context) error {
 func (b *BaseOrchestrator) Stop(ctx context.Context) error {
 	return nil
 }
+
+// annotatable returns a clone of msg with non-nil Message and Metadata, so an
+// orchestrator can annotate an agent's result without mutating the message
+// the agent returned, which the agent may still hold or share
+func annotatable(msg *agent.Message) *agent.Message {
+	clone := msg.Clone()
+	if clone == nil || clone.Message == nil {
+		return &agent.Message{Message: &pb.Message{Metadata: make(map[string]any)}}
+	}
+	if clone.Metadata == nil {
+		clone.Metadata = make(map[string]any)
+	}
+	return clone
+}
+
+// callParallelCloned invokes targets concurrently like Runtime.CallParallel,
+// but hands each target its own clone of input so an agent that mutates the
+// message (e.g. its Metadata) cannot corrupt what its siblings observe.
+// When limit is positive at most limit targets are called at once.
+func callParallelCloned(ctx context.Context, rt agent.Runtime, targets []string, input *agent.Message, limit int) (map[string]*agent.Message, map[string]error) {
+	results := make(map[string]*agent.Message)
+	errors := make(map[string]error)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := newSemaphore(limit)
+
+	for _, target := range targets {
+		wg.Add(1)
+		go func(t string, msg *agent.Message) {
+			defer wg.Done()
+
+			var result *agent.Message
+			err := sem.acquire(ctx)
+			if err == nil {
+				result, err = rt.Call(ctx, t, msg)
+				sem.release()
+			}
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errors[t] = err
+			} else {
+				results[t] = result
+			}
+		}(target, input.Clone())
+	}
+
+	wg.Wait()
+	return results, errors
+}
+
+// semaphore bounds concurrent agent calls; a nil semaphore is unbounded
+type semaphore chan struct{}
+
+// newSemaphore returns a semaphore admitting n holders, or nil if n <= 0
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+// acquire blocks until a slot is free or ctx is done
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// release frees a slot taken by acquire
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
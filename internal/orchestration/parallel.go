@@ -1,12 +1,18 @@
 package orchestration
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"maps"
+	"slices"
+	"strings"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/aggregation"
 	"github.com/aixgo-dev/aixgo/internal/observability"
 	pb "github.com/aixgo-dev/aixgo/proto"
 	"go.opentelemetry.io/otel/attribute"
@@ -23,26 +29,120 @@ import (
 // - Independent data gathering
 type Parallel struct {
 	*BaseOrchestrator
-	agents        []string
-	aggregateFunc func(results map[string]*agent.Message) (*agent.Message, error)
-	failFast      bool // If true, return error on first failure; otherwise collect all results
+	agents         []string
+	aggregateFunc  func(results map[string]*agent.Message) (*agent.Message, error)
+	failFast       bool    // If true, return error on first failure; otherwise collect all results
+	collectErrors  bool    // If true, return the failures as an AgentsFailedError with the results
+	errorMessages  bool    // If true, failures are passed to the aggregate function as error messages
+	dedupThreshold float64 // Similarity above which results are collapsed; 0 disables
+	maxConcurrency int     // Agents running at once; 0 runs all together
+}
+
+// Metadata keys set on results collapsed by WithDeduplication
+const (
+	// MetadataKeyAgreedBy lists the agents whose results were collapsed
+	// into this one
+	MetadataKeyAgreedBy = "agreed_by"
+
+	// MetadataKeyAgreementCount holds the number of agents in agreed_by
+	MetadataKeyAgreementCount = "agreement_count"
+)
+
+// MetadataKeyFailedAgents lists, in input order, the agents that failed in a
+// Parallel execution. It is set on the aggregated result whenever any failed.
+const MetadataKeyFailedAgents = "failed_agents"
+
+// ErrAgentsFailed is returned by a Parallel orchestrator in collect-errors
+// mode when at least one agent failed.
+var ErrAgentsFailed = errors.New("agents failed")
+
+// AgentsFailedError lists the failed agents of a collect-errors execution.
+// It matches ErrAgentsFailed with errors.Is, and each agent's error through
+// Unwrap.
+type AgentsFailedError struct {
+	Failed []string         // failed agents in input order
+	Errors map[string]error // each failed agent's error
+	Total  int              // agents executed
+}
+
+func (e *AgentsFailedError) Error() string {
+	parts := make([]string, len(e.Failed))
+	for i, name := range e.Failed {
+		parts[i] = fmt.Sprintf("%s: %v", name, e.Errors[name])
+	}
+	return fmt.Sprintf("%d of %d agents failed: %s", len(e.Failed), e.Total, strings.Join(parts, "; "))
+}
+
+// Is reports whether target is ErrAgentsFailed
+func (e *AgentsFailedError) Is(target error) bool {
+	return target == ErrAgentsFailed
+}
+
+// Unwrap returns the agents' errors in input order
+func (e *AgentsFailedError) Unwrap() []error {
+	errs := make([]error, len(e.Failed))
+	for i, name := range e.Failed {
+		errs[i] = e.Errors[name]
+	}
+	return errs
 }
 
 // ParallelOption configures a Parallel orchestrator
-type ParallelOption func(*Parallel)
+type ParallelOption interface {
+	applyParallel(*Parallel)
+}
+
+// parallelOptionFunc adapts a function to a ParallelOption
+type parallelOptionFunc func(*Parallel)
 
-// WithAggregateFunc sets a custom aggregation function
+func (f parallelOptionFunc) applyParallel(p *Parallel) { f(p) }
+
+// WithAggregateFunc replaces the default aggregation, which labels each
+// result with its agent in input order
 func WithAggregateFunc(fn func(results map[string]*agent.Message) (*agent.Message, error)) ParallelOption {
-	return func(p *Parallel) {
+	return parallelOptionFunc(func(p *Parallel) {
 		p.aggregateFunc = fn
-	}
+	})
 }
 
-// WithFailFast enables fail-fast mode (stop on first error)
+// WithFailFast enables fail-fast mode: the first agent to fail, by error or
+// error message, cancels the agents still running and Execute returns its
+// error without aggregating. By default failures are recorded in the
+// result's failed_agents metadata and the successful results are aggregated.
 func WithFailFast(enabled bool) ParallelOption {
-	return func(p *Parallel) {
+	return parallelOptionFunc(func(p *Parallel) {
 		p.failFast = enabled
-	}
+	})
+}
+
+// WithCollectErrors enables collect-errors mode: every agent runs to
+// completion and, if any failed, Execute returns the aggregated results
+// together with an *AgentsFailedError listing the failures. Fail-fast takes
+// precedence when both are enabled.
+func WithCollectErrors(enabled bool) ParallelOption {
+	return parallelOptionFunc(func(p *Parallel) {
+		p.collectErrors = enabled
+	})
+}
+
+// WithDeduplication collapses results whose text similarity (0-1) is at
+// least threshold into one before aggregation, so redundant sources reporting
+// the same fact are not repeated. Payloads over aggregation.MaxEditDistanceLen
+// bytes are compared by word overlap rather than edit distance. The kept result lists the agents that agreed
+// in its agreed_by and agreement_count metadata. ExecuteStream is unaffected.
+func WithDeduplication(threshold float64) ParallelOption {
+	return parallelOptionFunc(func(p *Parallel) {
+		p.dedupThreshold = threshold
+	})
+}
+
+// WithMaxConcurrency bounds how many agents run at once, so a large fan-out
+// does not overwhelm a rate-limited provider. Every agent still runs and its
+// result is aggregated. n <= 0 (the default) runs all agents together.
+func WithMaxConcurrency(n int) ParallelOption {
+	return parallelOptionFunc(func(p *Parallel) {
+		p.maxConcurrency = n
+	})
 }
 
 // NewParallel creates a new Parallel orchestrator
@@ -50,12 +150,11 @@ func NewParallel(name string, runtime agent.Runtime, agents []string, opts ...Pa
 	p := &Parallel{
 		BaseOrchestrator: NewBaseOrchestrator(name, "parallel", runtime),
 		agents:           agents,
-		aggregateFunc:    defaultAggregateFunc,
 		failFast:         false,
 	}
 
 	for _, opt := range opts {
-		opt(p)
+		opt.applyParallel(p)
 	}
 
 	p.SetReady(true)
@@ -64,12 +163,15 @@ func NewParallel(name string, runtime agent.Runtime, agents []string, opts ...Pa
 
 // Execute runs all agents in parallel and aggregates results
 func (p *Parallel) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.parallel.%s", p.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "parallel"),
 			attribute.StringSlice("orchestration.agents", p.agents),
 			attribute.Int("orchestration.agent_count", len(p.agents)),
 			attribute.Bool("orchestration.fail_fast", p.failFast),
+			attribute.Bool("orchestration.collect_errors", p.collectErrors),
+			attribute.Int("orchestration.max_concurrency", p.maxConcurrency),
 		),
 	)
 	defer span.End()
@@ -77,69 +179,341 @@ func (p *Parallel) Execute(ctx context.Context, input *agent.Message) (*agent.Me
 	startTime := time.Now()
 
 	// Execute all agents in parallel
-	results, errors := p.runtime.CallParallel(ctx, p.agents, input)
+	var results map[string]*agent.Message
+	var errors map[string]error
+	if p.failFast {
+		var err error
+		if results, err = p.callFailFast(ctx, input); err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	} else {
+		results, errors = callParallelCloned(ctx, p.runtime, p.agents, input, p.maxConcurrency)
+	}
 
 	duration := time.Since(startTime)
 
+	// Agents may also report failures as error messages
+	successes := withoutErrors(results)
+	failed, failures := p.failures(results, errors)
+
 	// Record metrics
 	span.SetAttributes(
 		attribute.Int64("orchestration.duration_ms", duration.Milliseconds()),
-		attribute.Int("orchestration.success_count", len(results)),
-		attribute.Int("orchestration.error_count", len(errors)),
+		attribute.Int("orchestration.success_count", len(successes)),
+		attribute.Int("orchestration.error_count", len(failed)),
 	)
+	for _, agentName := range failed {
+		span.SetAttributes(attribute.String(fmt.Sprintf("error.%s", agentName), failures[agentName].Error()))
+	}
 
-	// Handle errors based on fail-fast mode
-	if len(errors) > 0 {
-		if p.failFast {
-			// Return first error
-			for agent, err := range errors {
-				span.RecordError(err)
-				return nil, fmt.Errorf("agent %s failed: %w", agent, err)
-			}
+	// If all agents failed, return error
+	if len(successes) == 0 {
+		var err error = fmt.Errorf("all %d agents failed", len(p.agents))
+		if p.collectErrors && len(failed) > 0 {
+			err = &AgentsFailedError{Failed: failed, Errors: failures, Total: len(p.agents)}
 		}
+		span.RecordError(err)
+		if p.errorMessages {
+			return agent.NewErrorMessage(p.name, err), nil
+		}
+		return nil, err
+	}
 
-		// Log errors but continue with partial results
+	// Hand failures to the aggregate function as error messages
+	if p.errorMessages {
 		for agentName, err := range errors {
-			span.SetAttributes(attribute.String(fmt.Sprintf("error.%s", agentName), err.Error()))
+			results[agentName] = agent.NewErrorMessage(agentName, err)
 		}
 	}
 
-	// If all agents failed, return error
-	if len(results) == 0 {
-		err := fmt.Errorf("all %d agents failed", len(errors))
-		span.RecordError(err)
-		return nil, err
+	if p.dedupThreshold > 0 {
+		before := len(results)
+		results = deduplicateResults(results, p.dedupThreshold)
+		span.SetAttributes(attribute.Int("orchestration.duplicates_removed", before-len(results)))
 	}
 
 	// Aggregate results
-	aggregated, err := p.aggregateFunc(results)
+	var aggregated *agent.Message
+	var err error
+	if p.aggregateFunc != nil {
+		aggregated, err = p.aggregateFunc(results)
+	} else {
+		aggregated, err = p.labeledResults(results, errors)
+	}
 	if err != nil {
 		span.RecordError(err)
 		return nil, fmt.Errorf("aggregation failed: %w", err)
 	}
 
+	if len(failed) > 0 {
+		aggregated = aggregated.Clone()
+		if aggregated.Metadata == nil {
+			aggregated.Metadata = make(map[string]any)
+		}
+		aggregated.Metadata[MetadataKeyFailedAgents] = failed
+		if p.collectErrors {
+			err := &AgentsFailedError{Failed: failed, Errors: failures, Total: len(p.agents)}
+			span.RecordError(err)
+			return aggregated, err
+		}
+	}
+
 	span.SetAttributes(attribute.Bool("orchestration.success", true))
 	return aggregated, nil
 }
 
-// defaultAggregateFunc combines all results into a JSON array
-func defaultAggregateFunc(results map[string]*agent.Message) (*agent.Message, error) {
-	// Collect all results
-	aggregated := make(map[string]any)
-	for name, msg := range results {
-		aggregated[name] = msg.Message
+// callFailFast calls every agent with its own clone of input and returns
+// their results. The first failure cancels the agents still running and is
+// returned immediately.
+func (p *Parallel) callFailFast(ctx context.Context, input *agent.Message) (map[string]*agent.Message, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Buffered so cancelled agents never block after we return
+	done := make(chan AgentResult, len(p.agents))
+	sem := newSemaphore(p.maxConcurrency)
+	for _, target := range p.agents {
+		go func(t string, msg *agent.Message) {
+			var result *agent.Message
+			err := sem.acquire(ctx)
+			if err == nil {
+				result, err = p.runtime.Call(ctx, t, msg)
+				sem.release()
+			}
+			done <- AgentResult{Agent: t, Message: result, Err: err}
+		}(target, input.Clone())
 	}
 
-	// Marshal to JSON
-	resultJSON, err := json.Marshal(aggregated)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal aggregated results: %w", err)
+	results := make(map[string]*agent.Message, len(p.agents))
+	for range p.agents {
+		res := <-done
+		if details, ok := res.Message.ErrorDetails(); ok && res.Err == nil {
+			res.Err = errors.New(details.Error)
+		}
+		if res.Err != nil {
+			return nil, fmt.Errorf("agent %s failed: %w", res.Agent, res.Err)
+		}
+		results[res.Agent] = res.Message
+	}
+	return results, nil
+}
+
+// failures returns the failed agents in input order and their errors,
+// counting error messages as failures
+func (p *Parallel) failures(results map[string]*agent.Message, errs map[string]error) ([]string, map[string]error) {
+	var failed []string
+	failures := make(map[string]error)
+	for _, name := range p.agents {
+		if _, dup := failures[name]; dup {
+			continue
+		}
+		err := errs[name]
+		if details, ok := results[name].ErrorDetails(); ok && err == nil {
+			err = errors.New(details.Error)
+		}
+		if err != nil {
+			failed = append(failed, name)
+			failures[name] = err
+		}
+	}
+	return failed, failures
+}
+
+// AgentResult is the outcome of one agent in a streamed parallel execution.
+// Err is set when the agent failed; Message then holds its error message if
+// WithErrorMessages is enabled.
+type AgentResult struct {
+	Agent   string
+	Message *agent.Message
+	Err     error
+}
+
+// ExecuteStream runs all agents in parallel like Execute, but yields each
+// agent's result as soon as it completes instead of aggregating them. The
+// result channel is closed once every agent has finished.
+//
+// The error channel receives at most one error before it is closed: the
+// first failure in fail-fast mode (remaining agents are cancelled and their
+// results dropped), an *AgentsFailedError in collect-errors mode if any agent
+// failed, or an error if every agent failed. Agents that report failure via
+// an error message count as failed.
+func (p *Parallel) ExecuteStream(ctx context.Context, input *agent.Message) (<-chan AgentResult, <-chan error) {
+	ctx, input = agent.WithRequestID(ctx, input)
+	results := make(chan AgentResult, len(p.agents))
+	errc := make(chan error, 1)
+
+	ctx, cancel := context.WithCancel(ctx)
+	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.parallel.%s.stream", p.name),
+		trace.WithAttributes(
+			attribute.String("orchestration.pattern", "parallel"),
+			attribute.StringSlice("orchestration.agents", p.agents),
+			attribute.Int("orchestration.agent_count", len(p.agents)),
+			attribute.Bool("orchestration.fail_fast", p.failFast),
+			attribute.Bool("orchestration.collect_errors", p.collectErrors),
+			attribute.Int("orchestration.max_concurrency", p.maxConcurrency),
+		),
+	)
+
+	// Agents report here; buffered so none block once streaming stops
+	done := make(chan AgentResult, len(p.agents))
+	sem := newSemaphore(p.maxConcurrency)
+	for _, target := range p.agents {
+		go func(t string, msg *agent.Message) {
+			var result *agent.Message
+			err := sem.acquire(ctx)
+			if err == nil {
+				result, err = p.runtime.Call(ctx, t, msg)
+				sem.release()
+			}
+			done <- AgentResult{Agent: t, Message: result, Err: err}
+		}(target, input.Clone())
+	}
+
+	go func() {
+		defer span.End()
+		defer cancel()
+		defer close(errc)
+		defer close(results)
+
+		startTime := time.Now()
+		successes := 0
+		failures := make(map[string]error)
+		for range p.agents {
+			res := <-done
+			if details, ok := res.Message.ErrorDetails(); ok && res.Err == nil {
+				res.Err = errors.New(details.Error)
+			}
+
+			if res.Err == nil {
+				successes++
+			} else {
+				failures[res.Agent] = res.Err
+				span.SetAttributes(attribute.String(fmt.Sprintf("error.%s", res.Agent), res.Err.Error()))
+				if p.failFast {
+					err := fmt.Errorf("agent %s failed: %w", res.Agent, res.Err)
+					span.RecordError(err)
+					errc <- err
+					return
+				}
+				if p.errorMessages && res.Message == nil {
+					res.Message = agent.NewErrorMessage(res.Agent, res.Err)
+				}
+			}
+			results <- res
+		}
+
+		span.SetAttributes(
+			attribute.Int64("orchestration.duration_ms", time.Since(startTime).Milliseconds()),
+			attribute.Int("orchestration.success_count", successes),
+			attribute.Int("orchestration.error_count", len(p.agents)-successes),
+		)
+		if p.collectErrors && len(failures) > 0 {
+			failed, _ := p.failures(nil, failures)
+			err := &AgentsFailedError{Failed: failed, Errors: failures, Total: len(p.agents)}
+			span.RecordError(err)
+			errc <- err
+		} else if successes == 0 && len(p.agents) > 0 {
+			err := fmt.Errorf("all %d agents failed", len(p.agents))
+			span.RecordError(err)
+			errc <- err
+		}
+	}()
+
+	return results, errc
+}
+
+// deduplicateResults groups results whose payloads are at least threshold
+// similar, keeping the first agent's result (by name) of each group. Error
+// messages are never collapsed.
+func deduplicateResults(results map[string]*agent.Message, threshold float64) map[string]*agent.Message {
+	type group struct {
+		keeper  string
+		payload string
+		members []string
+	}
+
+	var groups []*group
+	deduped := make(map[string]*agent.Message, len(results))
+	for _, name := range slices.Sorted(maps.Keys(results)) {
+		msg := results[name]
+		if msg == nil || msg.Message == nil || msg.IsError() {
+			deduped[name] = msg
+			continue
+		}
+
+		payload := strings.TrimSpace(msg.Payload)
+		var match *group
+		for _, g := range groups {
+			if aggregation.BoundedTextSimilarity(payload, g.payload) >= threshold {
+				match = g
+				break
+			}
+		}
+		if match == nil {
+			groups = append(groups, &group{keeper: name, payload: payload, members: []string{name}})
+			deduped[name] = msg
+			continue
+		}
+		match.members = append(match.members, name)
+	}
+
+	for _, g := range groups {
+		if len(g.members) == 1 {
+			continue
+		}
+		kept := deduped[g.keeper].Clone()
+		if kept.Metadata == nil {
+			kept.Metadata = make(map[string]any)
+		}
+		kept.Metadata[MetadataKeyAgreedBy] = g.members
+		kept.Metadata[MetadataKeyAgreementCount] = len(g.members)
+		deduped[g.keeper] = kept
+	}
+	return deduped
+}
+
+// labeledResults is the default aggregation: a JSON object mapping each
+// agent to its result message, or to {"error": "..."} if it failed. Keys
+// follow the order of the configured agents so the payload is deterministic.
+// Results collapsed by WithDeduplication are omitted.
+func (p *Parallel) labeledResults(results map[string]*agent.Message, errs map[string]error) (*agent.Message, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	seen := make(map[string]bool, len(p.agents))
+	for _, name := range p.agents {
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		var value any
+		if msg, ok := results[name]; ok {
+			value = msg.Message
+		} else if err, ok := errs[name]; ok {
+			value = map[string]string{"error": err.Error()}
+		} else {
+			continue
+		}
+
+		data, err := json.Marshal(value)
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal result of agent %s: %w", name, err)
+		}
+		key, _ := json.Marshal(name)
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(data)
 	}
+	buf.WriteByte('}')
 
 	return &agent.Message{
 		Message: &pb.Message{
 			Type:    "aggregated",
-			Payload: string(resultJSON),
+			Payload: buf.String(),
 		},
 	}, nil
 }
@@ -150,7 +524,7 @@ func defaultAggregateFunc(results map[string]*agent.Message) (*agent.Message, er
 func ConcatAggregator(separator string) func(results map[string]*agent.Message) (*agent.Message, error) {
 	return func(results map[string]*agent.Message) (*agent.Message, error) {
 		var combined string
-		for _, msg := range results {
+		for _, msg := range withoutErrors(results) {
 			if msg.Message != nil {
 				// Extract text content from message
 				combined += fmt.Sprintf("%v%s", msg.Payload, separator)
@@ -169,7 +543,7 @@ func ConcatAggregator(separator string) func(results map[string]*agent.Message)
 // FirstSuccessAggregator returns the first successful result
 func FirstSuccessAggregator() func(results map[string]*agent.Message) (*agent.Message, error) {
 	return func(results map[string]*agent.Message) (*agent.Message, error) {
-		for _, msg := range results {
+		for _, msg := range withoutErrors(results) {
 			if msg != nil {
 				return msg, nil
 			}
@@ -185,7 +559,7 @@ func MajorityVoteAggregator() func(results map[string]*agent.Message) (*agent.Me
 		counts := make(map[string]int)
 		messages := make(map[string]*agent.Message)
 
-		for _, msg := range results {
+		for _, msg := range withoutErrors(results) {
 			key := fmt.Sprintf("%v", msg.Message)
 			counts[key]++
 			messages[key] = msg
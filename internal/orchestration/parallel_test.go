@@ -2,7 +2,13 @@ package orchestration
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
@@ -342,3 +348,613 @@ func TestParallelReady(t *testing.T) {
 		t.Error("Ready() = false, want true")
 	}
 }
+
+// metadataAgent mutates its input metadata and records what it observed
+type metadataAgent struct {
+	*MockAgent
+	mutate   bool
+	observed string
+}
+
+func (m *metadataAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	if m.mutate {
+		input.Metadata["tenant"] = "corrupted"
+		input.Metadata["tags"].([]any)[0] = "corrupted"
+		time.Sleep(10 * time.Millisecond)
+	} else {
+		// Give the mutating agent time to run first
+		time.Sleep(30 * time.Millisecond)
+	}
+
+	m.mu.Lock()
+	tag, _ := input.Metadata["tags"].([]any)[0].(string)
+	m.observed = input.Metadata["tenant"].(string) + "/" + tag
+	m.mu.Unlock()
+
+	return m.MockAgent.Execute(ctx, input)
+}
+
+func (m *metadataAgent) Observed() string {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.observed
+}
+
+func TestParallelClonesInputPerAgent(t *testing.T) {
+	ctx := context.Background()
+	rt := NewMockRuntime()
+
+	mutator := &metadataAgent{MockAgent: NewMockAgent("mutator", "test", 0, "m"), mutate: true}
+	reader1 := &metadataAgent{MockAgent: NewMockAgent("reader1", "test", 0, "r1")}
+	reader2 := &metadataAgent{MockAgent: NewMockAgent("reader2", "test", 0, "r2")}
+
+	_ = rt.Register(mutator)
+	_ = rt.Register(reader1)
+	_ = rt.Register(reader2)
+
+	parallel := NewParallel("test-parallel", rt, []string{"mutator", "reader1", "reader2"})
+
+	input := &agent.Message{
+		Message: &pb.Message{
+			Payload: "test input",
+			Metadata: map[string]interface{}{
+				"tenant": "acme",
+				"tags":   []any{"original"},
+			},
+		},
+	}
+
+	if _, err := parallel.Execute(ctx, input); err != nil {
+		t.Fatalf("Parallel execution failed: %v", err)
+	}
+
+	for _, r := range []*metadataAgent{reader1, reader2} {
+		if got := r.Observed(); got != "acme/original" {
+			t.Errorf("%s observed metadata %q, want %q", r.Name(), got, "acme/original")
+		}
+	}
+	if got := input.Metadata["tenant"]; got != "acme" {
+		t.Errorf("caller input tenant = %v, want acme", got)
+	}
+}
+
+// requestAgent records the request IDs of its input and context
+type requestAgent struct {
+	*MockAgent
+	messageID, contextID string
+}
+
+func (r *requestAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	r.mu.Lock()
+	r.messageID, r.contextID = input.RequestID(), agent.RequestIDFromContext(ctx)
+	r.mu.Unlock()
+	return r.MockAgent.Execute(ctx, input)
+}
+
+func TestParallelPropagatesRequestID(t *testing.T) {
+	rt := NewMockRuntime()
+	var children []*requestAgent
+	var names []string
+	for _, name := range []string{"a", "b", "c"} {
+		child := &requestAgent{MockAgent: NewMockAgent(name, "test", 0, name)}
+		_ = rt.Register(child)
+		children = append(children, child)
+		names = append(names, name)
+	}
+	parallel := NewParallel("fanout", rt, names)
+
+	tests := []struct {
+		name  string
+		ctx   context.Context
+		input *agent.Message
+		want  string
+	}{
+		{
+			name: "from message",
+			ctx:  context.Background(),
+			input: &agent.Message{Message: &pb.Message{
+				Payload:  "q",
+				Metadata: map[string]any{agent.MetadataKeyRequestID: "req-parent"},
+			}},
+			want: "req-parent",
+		},
+		{
+			name:  "from context",
+			ctx:   agent.ContextWithRequestID(context.Background(), "req-ctx"),
+			input: &agent.Message{Message: &pb.Message{Payload: "q"}},
+			want:  "req-ctx",
+		},
+		{
+			name:  "generated",
+			ctx:   context.Background(),
+			input: &agent.Message{Message: &pb.Message{Payload: "q"}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := parallel.Execute(tt.ctx, tt.input); err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			want := tt.want
+			if want == "" {
+				want = children[0].messageID
+			}
+			for _, child := range children {
+				if child.messageID == "" || child.messageID != want || child.contextID != want {
+					t.Errorf("%s saw message %q and context %q, want %q", child.Name(), child.messageID, child.contextID, want)
+				}
+			}
+			if tt.want == "" && tt.input.RequestID() != "" {
+				t.Error("caller input was modified, want the generated ID on a clone")
+			}
+		})
+	}
+}
+
+// blockingAgent waits until its context is cancelled
+type blockingAgent struct {
+	*MockAgent
+}
+
+func (b *blockingAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	<-ctx.Done()
+	return nil, ctx.Err()
+}
+
+// drainStream collects every streamed result and the final error
+func drainStream(results <-chan AgentResult, errc <-chan error) ([]AgentResult, error) {
+	var got []AgentResult
+	for res := range results {
+		got = append(got, res)
+	}
+	return got, <-errc
+}
+
+func TestParallelExecuteStream(t *testing.T) {
+	ctx := context.Background()
+	rt := NewMockRuntime()
+
+	_ = rt.Register(NewMockAgent("competitors", "test", 10*time.Millisecond, "fast"))
+	_ = rt.Register(NewMockAgent("regulations", "test", 150*time.Millisecond, "slow"))
+
+	parallel := NewParallel("research", rt, []string{"regulations", "competitors", "missing"})
+	input := &agent.Message{Message: &pb.Message{Payload: "test input"}}
+
+	start := time.Now()
+	results, errc := parallel.ExecuteStream(ctx, input)
+
+	// Results arrive in completion order, the fast agent well before the slow one
+	first := <-results
+	if first.Agent != "missing" && first.Agent != "competitors" {
+		t.Errorf("first result from %s, want a fast agent", first.Agent)
+	}
+	if elapsed := time.Since(start); elapsed > 100*time.Millisecond {
+		t.Errorf("first result took %v, should not wait for the slowest agent", elapsed)
+	}
+
+	rest, err := drainStream(results, errc)
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	got := append([]AgentResult{first}, rest...)
+	if len(got) != 3 {
+		t.Fatalf("got %d results, want 3", len(got))
+	}
+	if last := got[2]; last.Agent != "regulations" || last.Message.Payload != "slow" {
+		t.Errorf("last result = %+v, want the slow regulations agent", last)
+	}
+	for _, res := range got {
+		if res.Agent == "missing" && !errors.Is(res.Err, agent.ErrAgentNotFound) {
+			t.Errorf("missing agent Err = %v, want ErrAgentNotFound", res.Err)
+		}
+	}
+}
+
+func TestParallelExecuteStreamErrorMessages(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("ok", "test", 0, "fine"))
+
+	results, errc := NewParallel("p", rt, []string{"ok", "missing"}, WithErrorMessages(true)).
+		ExecuteStream(context.Background(), &agent.Message{Message: &pb.Message{}})
+	got, err := drainStream(results, errc)
+	if err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	for _, res := range got {
+		if res.Agent == "missing" && (res.Err == nil || !res.Message.IsError()) {
+			t.Errorf("missing agent result = %+v, want an error message", res)
+		}
+	}
+}
+
+func TestParallelExecuteStreamFailFast(t *testing.T) {
+	rt := NewMockRuntime()
+	blocker := &blockingAgent{MockAgent: NewMockAgent("blocker", "test", 0, "")}
+	_ = rt.Register(blocker)
+
+	results, errc := NewParallel("p", rt, []string{"blocker", "missing"}, WithFailFast(true)).
+		ExecuteStream(context.Background(), &agent.Message{Message: &pb.Message{}})
+
+	done := make(chan struct{})
+	var got []AgentResult
+	var err error
+	go func() {
+		got, err = drainStream(results, errc)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("fail-fast stream did not cancel the remaining agents")
+	}
+	if !errors.Is(err, agent.ErrAgentNotFound) {
+		t.Errorf("ExecuteStream() error = %v, want ErrAgentNotFound", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %d results after fail-fast, want 0", len(got))
+	}
+}
+
+func TestParallelExecuteStreamAllFailed(t *testing.T) {
+	results, errc := NewParallel("p", NewMockRuntime(), []string{"a", "b"}).
+		ExecuteStream(context.Background(), &agent.Message{Message: &pb.Message{}})
+	got, err := drainStream(results, errc)
+	if err == nil {
+		t.Fatal("expected error when every agent fails")
+	}
+	if len(got) != 2 {
+		t.Errorf("got %d results, want 2 failures", len(got))
+	}
+}
+
+func TestParallelDeduplication(t *testing.T) {
+	ctx := context.Background()
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("source-a", "research", 0, "The market grew 12% in 2025."))
+	_ = rt.Register(NewMockAgent("source-b", "research", 0, "The market grew 12% in 2025"))
+	_ = rt.Register(NewMockAgent("source-c", "research", 0, "  The market grew 12% in 2025.\n"))
+	_ = rt.Register(NewMockAgent("source-d", "research", 0, "Three new competitors entered the market."))
+	agents := []string{"source-a", "source-b", "source-c", "source-d"}
+
+	var got map[string]*agent.Message
+	capture := WithAggregateFunc(func(results map[string]*agent.Message) (*agent.Message, error) {
+		got = results
+		return ConcatAggregator("\n")(results)
+	})
+
+	if _, err := NewParallel("research", rt, agents, capture, WithDeduplication(0.9)).Execute(ctx, &agent.Message{Message: &pb.Message{}}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("aggregated %d results, want 2 after deduplication", len(got))
+	}
+	kept := got["source-a"]
+	if kept == nil || got["source-d"] == nil {
+		t.Fatalf("unexpected kept results %v", got)
+	}
+	if !reflect.DeepEqual(kept.Metadata[MetadataKeyAgreedBy], []string{"source-a", "source-b", "source-c"}) || kept.Metadata[MetadataKeyAgreementCount] != 3 {
+		t.Errorf("unexpected agreement metadata %v", kept.Metadata)
+	}
+	if _, ok := got["source-d"].Metadata[MetadataKeyAgreedBy]; ok {
+		t.Error("unique result should not carry agreement metadata")
+	}
+
+	// Without the option every result reaches the aggregate function
+	if _, err := NewParallel("research", rt, agents, capture).Execute(ctx, &agent.Message{Message: &pb.Message{}}); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(got) != 4 {
+		t.Errorf("aggregated %d results, want 4 without deduplication", len(got))
+	}
+}
+
+// barrier releases its waiters once n of them have arrived
+type barrier struct {
+	n       int32
+	arrived atomic.Int32
+	release chan struct{}
+}
+
+func newBarrier(n int) *barrier {
+	return &barrier{n: int32(n), release: make(chan struct{})}
+}
+
+func (b *barrier) wait() error {
+	if b.arrived.Add(1) == b.n {
+		close(b.release)
+	}
+	select {
+	case <-b.release:
+		return nil
+	case <-time.After(5 * time.Second):
+		return fmt.Errorf("only %d of %d agents ran at once", b.arrived.Load(), b.n)
+	}
+}
+
+// peakAgent tracks the most executions of any peakAgent in flight at once.
+// With a barrier, it waits inside Execute until every agent sharing the
+// barrier is running.
+type peakAgent struct {
+	*MockAgent
+	active, peak *atomic.Int32
+	barrier      *barrier
+}
+
+func (a *peakAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	n := a.active.Add(1)
+	defer a.active.Add(-1)
+	for {
+		peak := a.peak.Load()
+		if n <= peak || a.peak.CompareAndSwap(peak, n) {
+			break
+		}
+	}
+	if a.barrier != nil {
+		if err := a.barrier.wait(); err != nil {
+			return nil, err
+		}
+	}
+	return a.MockAgent.Execute(ctx, input)
+}
+
+func TestParallelMaxConcurrency(t *testing.T) {
+	for _, tt := range []struct {
+		name        string
+		concurrency int
+		bounded     bool // peak must stay at or below 3
+	}{
+		{name: "bounded", concurrency: 3, bounded: true},
+		{name: "unbounded", concurrency: 0, bounded: false},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewMockRuntime()
+			var active, peak atomic.Int32
+			// Unbounded, every agent must be running at once to pass the barrier
+			var all *barrier
+			if !tt.bounded {
+				all = newBarrier(10)
+			}
+			var names []string
+			for i := range 10 {
+				name := fmt.Sprintf("agent%d", i)
+				names = append(names, name)
+				_ = rt.Register(&peakAgent{MockAgent: NewMockAgent(name, "test", 20*time.Millisecond, name), active: &active, peak: &peak, barrier: all})
+			}
+
+			parallel := NewParallel("bounded", rt, names, WithMaxConcurrency(tt.concurrency))
+			result, err := parallel.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "go"}})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+
+			var aggregated map[string]any
+			if err := json.Unmarshal([]byte(result.Payload), &aggregated); err != nil {
+				t.Fatalf("aggregated payload is not JSON: %v", err)
+			}
+			if len(aggregated) != 10 {
+				t.Errorf("aggregated %d results, want 10", len(aggregated))
+			}
+			if got := peak.Load(); tt.bounded && got > 3 || !tt.bounded && got != 10 {
+				t.Errorf("peak concurrency = %d with WithMaxConcurrency(%d)", got, tt.concurrency)
+			}
+		})
+	}
+}
+
+func TestParallelExecuteStreamMaxConcurrency(t *testing.T) {
+	rt := NewMockRuntime()
+	var active, peak atomic.Int32
+	var names []string
+	for i := range 6 {
+		name := fmt.Sprintf("agent%d", i)
+		names = append(names, name)
+		_ = rt.Register(&peakAgent{MockAgent: NewMockAgent(name, "test", 10*time.Millisecond, name), active: &active, peak: &peak})
+	}
+
+	parallel := NewParallel("bounded", rt, names, WithMaxConcurrency(2))
+	results, errc := parallel.ExecuteStream(context.Background(), &agent.Message{Message: &pb.Message{Payload: "go"}})
+
+	count := 0
+	for range results {
+		count++
+	}
+	if err := <-errc; err != nil {
+		t.Fatalf("ExecuteStream() error = %v", err)
+	}
+	if count != 6 {
+		t.Errorf("streamed %d results, want 6", count)
+	}
+	if got := peak.Load(); got > 2 {
+		t.Errorf("peak concurrency = %d, want at most 2", got)
+	}
+}
+
+func TestParallelLabeledResults(t *testing.T) {
+	rt := NewMockRuntime()
+	// Fastest agents finish first, so completion order differs from input order
+	_ = rt.Register(NewMockAgent("zeta", "test", 30*time.Millisecond, "z"))
+	_ = rt.Register(NewMockAgent("alpha", "test", 20*time.Millisecond, "a"))
+	_ = rt.Register(NewMockAgent("mid", "test", 0, "m"))
+	agents := []string{"zeta", "alpha", "missing", "mid"}
+
+	result, err := NewParallel("labeled", rt, agents).Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "go"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Type != "aggregated" {
+		t.Errorf("Type = %q, want aggregated", result.Type)
+	}
+
+	// Read the keys in payload order
+	dec := json.NewDecoder(strings.NewReader(result.Payload))
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		t.Fatalf("payload is not a JSON object: %s", result.Payload)
+	}
+	var keys []string
+	values := make(map[string]map[string]any)
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			t.Fatalf("decode key: %v", err)
+		}
+		key := tok.(string)
+		var value map[string]any
+		if err := dec.Decode(&value); err != nil {
+			t.Fatalf("decode value of %s: %v", key, err)
+		}
+		keys = append(keys, key)
+		values[key] = value
+	}
+
+	if !reflect.DeepEqual(keys, agents) {
+		t.Errorf("payload keys = %v, want input order %v", keys, agents)
+	}
+	if values["zeta"]["Payload"] != "z" || values["mid"]["Payload"] != "m" {
+		t.Errorf("results not labeled with their agents: %s", result.Payload)
+	}
+	if msg, _ := values["missing"]["error"].(string); msg == "" {
+		t.Errorf("failed agent has no error entry: %v", values["missing"])
+	}
+}
+
+var errQuotaExceeded = errors.New("quota exceeded")
+
+// outcomeAgent fails with err, replies with an error message, or blocks
+// until cancelled, recording whether it saw the cancellation
+type outcomeAgent struct {
+	*MockAgent
+	err       error
+	errMsg    bool
+	block     bool
+	cancelled atomic.Bool
+}
+
+func (o *outcomeAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	switch {
+	case o.err != nil:
+		return nil, o.err
+	case o.errMsg:
+		return agent.NewErrorMessage(o.name, errors.New("bad response")), nil
+	case o.block:
+		select {
+		case <-ctx.Done():
+			o.cancelled.Store(true)
+			return nil, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
+	}
+	return o.MockAgent.Execute(ctx, input)
+}
+
+func newOutcomeRuntime() (*MockRuntime, []string) {
+	rt := NewMockRuntime()
+	_ = rt.Register(&outcomeAgent{MockAgent: NewMockAgent("ok1", "test", 0, "one")})
+	_ = rt.Register(&outcomeAgent{MockAgent: NewMockAgent("quota", "test", 0, ""), err: errQuotaExceeded})
+	_ = rt.Register(&outcomeAgent{MockAgent: NewMockAgent("ok2", "test", 0, "two")})
+	_ = rt.Register(&outcomeAgent{MockAgent: NewMockAgent("garbled", "test", 0, ""), errMsg: true})
+	return rt, []string{"ok1", "quota", "ok2", "garbled"}
+}
+
+func TestParallelFailureModes(t *testing.T) {
+	input := &agent.Message{Message: &pb.Message{Payload: "go"}}
+
+	t.Run("default returns partial results", func(t *testing.T) {
+		rt, agents := newOutcomeRuntime()
+		result, err := NewParallel("modes", rt, agents).Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute() error = %v, want partial results", err)
+		}
+		if got := result.Metadata[MetadataKeyFailedAgents]; !reflect.DeepEqual(got, []string{"quota", "garbled"}) {
+			t.Errorf("failed_agents = %v, want [quota garbled]", got)
+		}
+	})
+
+	t.Run("collect errors returns results and combined error", func(t *testing.T) {
+		rt, agents := newOutcomeRuntime()
+		result, err := NewParallel("modes", rt, agents, WithCollectErrors(true)).Execute(context.Background(), input)
+
+		var failed *AgentsFailedError
+		if !errors.As(err, &failed) {
+			t.Fatalf("Execute() error = %v, want *AgentsFailedError", err)
+		}
+		if !errors.Is(err, ErrAgentsFailed) || !errors.Is(err, errQuotaExceeded) {
+			t.Errorf("error %v does not match ErrAgentsFailed and the agent's error", err)
+		}
+		if !reflect.DeepEqual(failed.Failed, []string{"quota", "garbled"}) || failed.Total != 4 {
+			t.Errorf("AgentsFailedError = %+v", failed)
+		}
+		if want := "2 of 4 agents failed: quota: quota exceeded; garbled: bad response"; err.Error() != want {
+			t.Errorf("Error() = %q, want %q", err.Error(), want)
+		}
+		if result == nil || !strings.Contains(result.Payload, `"ok1"`) || !strings.Contains(result.Payload, `"ok2"`) {
+			t.Fatalf("result = %v, want the successful results aggregated", result)
+		}
+		if got := result.Metadata[MetadataKeyFailedAgents]; !reflect.DeepEqual(got, []string{"quota", "garbled"}) {
+			t.Errorf("failed_agents = %v, want [quota garbled]", got)
+		}
+	})
+
+	t.Run("collect errors without failures", func(t *testing.T) {
+		rt, _ := newOutcomeRuntime()
+		result, err := NewParallel("modes", rt, []string{"ok1", "ok2"}, WithCollectErrors(true)).Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute() error = %v", err)
+		}
+		if _, ok := result.Metadata[MetadataKeyFailedAgents]; ok {
+			t.Error("failed_agents set although every agent succeeded")
+		}
+	})
+
+	t.Run("fail fast cancels siblings", func(t *testing.T) {
+		rt, _ := newOutcomeRuntime()
+		slow := &outcomeAgent{MockAgent: NewMockAgent("slow", "test", 0, ""), block: true}
+		_ = rt.Register(slow)
+
+		start := time.Now()
+		result, err := NewParallel("modes", rt, []string{"slow", "ok1", "quota"}, WithFailFast(true)).Execute(context.Background(), input)
+		if !errors.Is(err, errQuotaExceeded) || result != nil {
+			t.Fatalf("Execute() = %v, %v, want the quota error and no result", result, err)
+		}
+		if !strings.Contains(err.Error(), "agent quota failed") {
+			t.Errorf("error %q does not name the failed agent", err)
+		}
+		if elapsed := time.Since(start); elapsed > time.Second {
+			t.Errorf("Execute() took %v, want it to return on the first failure", elapsed)
+		}
+
+		deadline := time.Now().Add(time.Second)
+		for !slow.cancelled.Load() && time.Now().Before(deadline) {
+			time.Sleep(5 * time.Millisecond)
+		}
+		if !slow.cancelled.Load() {
+			t.Error("running sibling was not cancelled")
+		}
+	})
+
+	t.Run("fail fast counts error messages", func(t *testing.T) {
+		rt, _ := newOutcomeRuntime()
+		_, err := NewParallel("modes", rt, []string{"ok1", "garbled"}, WithFailFast(true)).Execute(context.Background(), input)
+		if err == nil || !strings.Contains(err.Error(), "agent garbled failed: bad response") {
+			t.Errorf("Execute() error = %v, want the error message's failure", err)
+		}
+	})
+}
+
+func TestParallelExecuteStreamCollectErrors(t *testing.T) {
+	rt, agents := newOutcomeRuntime()
+	results, errc := NewParallel("modes", rt, agents, WithCollectErrors(true)).ExecuteStream(context.Background(), &agent.Message{Message: &pb.Message{Payload: "go"}})
+
+	count := 0
+	for range results {
+		count++
+	}
+	if count != 4 {
+		t.Errorf("streamed %d results, want all 4", count)
+	}
+	var failed *AgentsFailedError
+	if err := <-errc; !errors.As(err, &failed) || !reflect.DeepEqual(failed.Failed, []string{"quota", "garbled"}) {
+		t.Errorf("ExecuteStream() error = %v, want AgentsFailedError for quota and garbled", err)
+	}
+}
@@ -35,8 +35,17 @@ type RAG struct {
 	historyAgent     string              // Agent for managing history
 	queryExpander    string              // For multi-query RAG
 	keywordRetriever string              // For hybrid RAG
+	keywordFallback  string              // Keyword retriever used when semantic retrieval fails
+	verifier         string              // Optional answer verifier agent
+	minGroundedness  float64             // Groundedness an answer needs to pass verification
+	verifyRetries    int                 // Regenerations after a failed verification
+	citations        bool                // Whether the generator cites documents
 }
 
+// MetadataKeyDegradedRetrieval is true on results answered from keyword
+// retrieval alone because semantic retrieval (e.g. query embedding) failed
+const MetadataKeyDegradedRetrieval = "degraded_retrieval"
+
 // ConversationTurn represents a single turn in conversation history
 type ConversationTurn struct {
 	Query    string
@@ -54,7 +63,9 @@ func WithTopK(k int) RAGOption {
 	}
 }
 
-// WithReranker enables reranking with the specified agent
+// WithReranker reorders the retrieved documents with the specified agent
+// before generation, keeping the top-K it scores highest (see
+// rerankDocuments for the request and reply format)
 func WithReranker(reranker string) RAGOption {
 	return func(r *RAG) {
 		r.rerank = true
@@ -62,6 +73,15 @@ func WithReranker(reranker string) RAGOption {
 	}
 }
 
+// WithKeywordFallback retrieves with the named keyword/lexical retriever
+// when the semantic retriever fails, e.g. during an embedding provider
+// outage. Such results are flagged degraded_retrieval.
+func WithKeywordFallback(retriever string) RAGOption {
+	return func(r *RAG) {
+		r.keywordFallback = retriever
+	}
+}
+
 // NewRAG creates a new RAG orchestrator
 func NewRAG(name string, runtime agent.Runtime, retriever, generator string, opts ...RAGOption) *RAG {
 	r := &RAG{
@@ -70,6 +90,8 @@ func NewRAG(name string, runtime agent.Runtime, retriever, generator string, opt
 		generator:        generator,
 		topK:             5, // Default top-5
 		rerank:           false,
+		minGroundedness:  0.7,
+		verifyRetries:    1,
 	}
 
 	for _, opt := range opts {
@@ -79,8 +101,9 @@ func NewRAG(name string, runtime agent.Runtime, retriever, generator string, opt
 	return r
 }
 
-// Execute performs RAG: retrieve → (optional rerank) → generate
+// Execute performs RAG: retrieve → (optional rerank) → generate → (optional cite) → (optional verify)
 func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.rag.%s", r.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "rag"),
@@ -91,6 +114,7 @@ func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message
 			attribute.Bool("orchestration.conversational", len(r.conversationHist) > 0 || r.historyAgent != ""),
 			attribute.Bool("orchestration.multi_query", r.queryExpander != ""),
 			attribute.Bool("orchestration.hybrid", r.keywordRetriever != ""),
+			attribute.Bool("orchestration.verify", r.verifier != ""),
 		),
 	)
 	defer span.End()
@@ -106,17 +130,27 @@ func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message
 	// Step 1: Retrieve relevant documents
 	var documents *agent.Message
 	var err error
+	var degraded bool
 
 	if r.queryExpander != "" {
 		// Multi-query RAG: expand query into multiple variants
 		documents, err = r.multiQueryRetrieve(ctx, queryInput)
 	} else if r.keywordRetriever != "" {
 		// Hybrid RAG: combine semantic and keyword retrieval
-		documents, err = r.hybridRetrieve(ctx, queryInput)
+		documents, degraded, err = r.hybridRetrieve(ctx, queryInput)
 	} else {
 		// Standard retrieval
 		retrieveStart := time.Now()
 		retrieved, retrieveErr := r.runtime.Call(ctx, r.retriever, queryInput)
+		if retrieveErr != nil && r.keywordFallback != "" && ctx.Err() == nil {
+			span.SetAttributes(attribute.String("orchestration.semantic_error", retrieveErr.Error()))
+			fallback, fallbackErr := r.runtime.Call(ctx, r.keywordFallback, queryInput)
+			if fallbackErr != nil {
+				retrieveErr = fmt.Errorf("%w; keyword fallback: %v", retrieveErr, fallbackErr)
+			} else {
+				retrieved, retrieveErr, degraded = fallback, nil, true
+			}
+		}
 		retrieveDuration := time.Since(retrieveStart)
 
 		if retrieveErr != nil {
@@ -127,24 +161,7 @@ func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message
 		span.SetAttributes(
 			attribute.Int64("orchestration.retrieve_duration_ms", retrieveDuration.Milliseconds()),
 		)
-
-		// Step 2: Optional reranking
-		if r.rerank && r.reranker != "" {
-			rerankStart := time.Now()
-			documents, err = r.runtime.Call(ctx, r.reranker, retrieved)
-			rerankDuration := time.Since(rerankStart)
-
-			if err != nil {
-				span.RecordError(err)
-				return nil, fmt.Errorf("reranking failed: %w", err)
-			}
-
-			span.SetAttributes(
-				attribute.Int64("orchestration.rerank_duration_ms", rerankDuration.Milliseconds()),
-			)
-		} else {
-			documents = retrieved
-		}
+		documents = retrieved
 	}
 
 	if err != nil {
@@ -152,8 +169,27 @@ func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message
 		return nil, err
 	}
 
+	// Step 2: Optional reranking of the retrieved candidates
+	if r.rerank && r.reranker != "" {
+		rerankStart := time.Now()
+		documents, err = r.rerankDocuments(ctx, input, documents)
+		rerankDuration := time.Since(rerankStart)
+
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("reranking failed: %w", err)
+		}
+
+		span.SetAttributes(
+			attribute.Int64("orchestration.rerank_duration_ms", rerankDuration.Milliseconds()),
+		)
+	}
+
 	// Step 3: Generate answer with retrieved context
 	augmentedInput := augmentInput(input, documents)
+	if r.citations {
+		augmentedInput = augmentWithCitations(input, documents)
+	}
 
 	generateStart := time.Now()
 	result, err := r.runtime.Call(ctx, r.generator, augmentedInput)
@@ -172,6 +208,28 @@ func (r *RAG) Execute(ctx context.Context, input *agent.Message) (*agent.Message
 		return nil, fmt.Errorf("generation failed: %w", err)
 	}
 
+	// Step 4: Optional citation extraction and checking
+	result = r.parseAnswer(result, documents)
+
+	// Step 5: Optional answer verification against the retrieved context
+	if r.verifier != "" {
+		result, err = r.verify(ctx, span, input, documents, augmentedInput, result)
+		if err != nil {
+			span.RecordError(err)
+			return nil, err
+		}
+	}
+
+	if hallucinated, ok := result.Metadata[MetadataKeyHallucinatedCitations].([]Citation); ok {
+		span.SetAttributes(attribute.Int("orchestration.hallucinated_citations", len(hallucinated)))
+	}
+
+	if degraded {
+		span.SetAttributes(attribute.Bool("orchestration.degraded_retrieval", true))
+		result = annotatable(result)
+		result.Metadata[MetadataKeyDegradedRetrieval] = true
+	}
+
 	// Store conversation turn if conversational
 	if len(r.conversationHist) > 0 || r.historyAgent != "" {
 		r.storeConversationTurn(input.Payload, result.Payload, documents.Payload)
@@ -260,7 +318,7 @@ func (r *RAG) multiQueryRetrieve(ctx context.Context, input *agent.Message) (*ag
 
 		// Apply reciprocal rank fusion scoring
 		// Score = sum(1 / (rank + 60)) for each query
-		docList := strings.Split(docs.Payload, "\n---\n")
+		docList := strings.Split(docs.Payload, documentSeparator)
 		for rank, doc := range docList {
 			score := 1.0 / float64(rank+60)
 			docScores[doc] += score
@@ -303,13 +361,14 @@ func (r *RAG) multiQueryRetrieve(ctx context.Context, input *agent.Message) (*ag
 
 	return &agent.Message{
 		Message: &pb.Message{
-			Payload: strings.Join(mergedDocs, "\n---\n"),
+			Payload: strings.Join(mergedDocs, documentSeparator),
 		},
 	}, nil
 }
 
-// hybridRetrieve combines semantic and keyword retrieval
-func (r *RAG) hybridRetrieve(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+// hybridRetrieve combines semantic and keyword retrieval. degraded reports
+// that only the keyword retrieval succeeded.
+func (r *RAG) hybridRetrieve(ctx context.Context, input *agent.Message) (documents *agent.Message, degraded bool, err error) {
 	// Retrieve from both semantic and keyword retrievers in parallel
 	type result struct {
 		docs *agent.Message
@@ -336,22 +395,23 @@ func (r *RAG) hybridRetrieve(ctx context.Context, input *agent.Message) (*agent.
 	keywordResult := <-keywordCh
 
 	if semanticResult.err != nil && keywordResult.err != nil {
-		return nil, fmt.Errorf("both retrievals failed: semantic=%v, keyword=%v",
+		return nil, false, fmt.Errorf("both retrievals failed: semantic=%v, keyword=%v",
 			semanticResult.err, keywordResult.err)
 	}
+	degraded = semanticResult.err != nil
 
 	// Merge results with reciprocal rank fusion
 	allDocs := make(map[string]float64)
 
 	if semanticResult.err == nil && semanticResult.docs != nil {
-		docs := strings.Split(semanticResult.docs.Payload, "\n---\n")
+		docs := strings.Split(semanticResult.docs.Payload, documentSeparator)
 		for rank, doc := range docs {
 			allDocs[doc] = 1.0 / float64(rank+60)
 		}
 	}
 
 	if keywordResult.err == nil && keywordResult.docs != nil {
-		docs := strings.Split(keywordResult.docs.Payload, "\n---\n")
+		docs := strings.Split(keywordResult.docs.Payload, documentSeparator)
 		for rank, doc := range docs {
 			score := 1.0 / float64(rank+60)
 			allDocs[doc] += score
@@ -390,9 +450,9 @@ func (r *RAG) hybridRetrieve(ctx context.Context, input *agent.Message) (*agent.
 
 	return &agent.Message{
 		Message: &pb.Message{
-			Payload: strings.Join(mergedDocs, "\n---\n"),
+			Payload: strings.Join(mergedDocs, documentSeparator),
 		},
-	}, nil
+	}, degraded, nil
 }
 
 // augmentInput combines the original query with retrieved documents
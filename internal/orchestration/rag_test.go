@@ -2,6 +2,7 @@ package orchestration
 
 import (
 	"context"
+	"errors"
 	"strings"
 	"testing"
 	"time"
@@ -404,3 +405,89 @@ func TestRAGContextCancellation(t *testing.T) {
 		t.Fatal("Result is nil")
 	}
 }
+
+func TestRAGKeywordFallback(t *testing.T) {
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "query"}}
+
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("keyword-retriever", "retriever", 0, "keyword docs"))
+	_ = rt.Register(NewMockAgent("semantic-retriever", "retriever", 0, "semantic docs"))
+	generator := newScriptedAgent("generator", "answer", "answer", "answer")
+	_ = rt.Register(generator)
+
+	// The semantic retriever is unavailable, e.g. its embedding provider is down
+	rag := NewRAG("test-rag", rt, "embedding-retriever", "generator", WithKeywordFallback("keyword-retriever"))
+	result, err := rag.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyDegradedRetrieval] != true {
+		t.Errorf("expected degraded_retrieval flag, got %v", result.Metadata)
+	}
+	if !strings.Contains(generator.inputs[0], "keyword docs") {
+		t.Errorf("generator input = %q, want keyword documents", generator.inputs[0])
+	}
+
+	// A healthy semantic retriever is not flagged
+	rag = NewRAG("test-rag", rt, "semantic-retriever", "generator", WithKeywordFallback("keyword-retriever"))
+	result, err = rag.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, flagged := result.Metadata[MetadataKeyDegradedRetrieval]; flagged {
+		t.Error("healthy retrieval should not be flagged degraded")
+	}
+
+	// Hybrid RAG answering from keyword results alone is degraded too
+	result, err = NewHybridRAG("test-rag", rt, "embedding-retriever", "keyword-retriever", "generator").Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyDegradedRetrieval] != true {
+		t.Errorf("expected degraded_retrieval flag for hybrid RAG, got %v", result.Metadata)
+	}
+}
+
+func TestRAGKeywordFallbackLeavesAnswerUntouched(t *testing.T) {
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "query"}}
+
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("keyword-retriever", "retriever", 0, "keyword docs"))
+	_ = rt.Register(NewMockAgent("semantic-retriever", "retriever", 0, "semantic docs"))
+	generator := newCachedAgent("generator", "answer")
+	_ = rt.Register(generator)
+
+	result, err := NewRAG("test-rag", rt, "embedding-retriever", "generator", WithKeywordFallback("keyword-retriever")).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyDegradedRetrieval] != true {
+		t.Errorf("expected degraded_retrieval flag, got %v", result.Metadata)
+	}
+
+	// The flag must not stick to the generator's reply and leak into later,
+	// healthy answers
+	result, err = NewRAG("test-rag", rt, "semantic-retriever", "generator", WithKeywordFallback("keyword-retriever")).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if _, flagged := result.Metadata[MetadataKeyDegradedRetrieval]; flagged {
+		t.Error("healthy retrieval was flagged degraded by an earlier answer")
+	}
+}
+
+func TestRAGKeywordFallbackFailure(t *testing.T) {
+	rt := NewMockRuntime()
+	_ = rt.Register(NewMockAgent("generator", "generator", 0, "answer"))
+
+	rag := NewRAG("test-rag", rt, "embedding-retriever", "generator", WithKeywordFallback("keyword-retriever"))
+	_, err := rag.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "query"}})
+	if err == nil {
+		t.Fatal("expected error when both retrievers fail")
+	}
+	if !errors.Is(err, agent.ErrAgentNotFound) || !strings.Contains(err.Error(), "keyword fallback") {
+		t.Errorf("Execute() error = %v, want both failures reported", err)
+	}
+}
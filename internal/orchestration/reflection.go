@@ -32,8 +32,21 @@ type Reflection struct {
 	critics              []string // For multi-critic reflection
 	maxIterations        int
 	improvementThreshold float64 // Minimum improvement required to continue
+	qualityThreshold     float64 // Critic score at which refinement stops
+	minIterations        int     // Iterations to run before any early stop
+	keepBest             bool    // Return the highest-scoring iteration instead of the last
 }
 
+// Metadata keys set on the output returned by Reflection
+const (
+	// MetadataKeyReflectionIterations is the number of generate/critique
+	// iterations that ran
+	MetadataKeyReflectionIterations = "reflection_iterations"
+
+	// MetadataKeyReflectionScore is the critic's score for the returned output
+	MetadataKeyReflectionScore = "reflection_score"
+)
+
 // ReflectionOption configures a Reflection orchestrator
 type ReflectionOption func(*Reflection)
 
@@ -51,6 +64,31 @@ func WithImprovementThreshold(threshold float64) ReflectionOption {
 	}
 }
 
+// WithQualityThreshold stops refinement as soon as the critic's score meets
+// or exceeds threshold (default 0.95)
+func WithQualityThreshold(threshold float64) ReflectionOption {
+	return func(r *Reflection) {
+		r.qualityThreshold = threshold
+	}
+}
+
+// WithMinIterations runs at least min iterations before stopping early on
+// quality or insufficient improvement, e.g. 2 forces one refinement pass.
+// It raises the maximum iterations if needed.
+func WithMinIterations(min int) ReflectionOption {
+	return func(r *Reflection) {
+		r.minIterations = min
+	}
+}
+
+// WithKeepBest returns the iteration the critic scored highest instead of the
+// last one, so a refinement that regresses does not replace a better answer
+func WithKeepBest(keep bool) ReflectionOption {
+	return func(r *Reflection) {
+		r.keepBest = keep
+	}
+}
+
 // NewReflection creates a new Reflection orchestrator
 func NewReflection(name string, runtime agent.Runtime, generator, critic string, opts ...ReflectionOption) *Reflection {
 	r := &Reflection{
@@ -59,17 +97,21 @@ func NewReflection(name string, runtime agent.Runtime, generator, critic string,
 		critic:               critic,
 		maxIterations:        3,   // Default 3 iterations
 		improvementThreshold: 0.1, // 10% improvement required
+		qualityThreshold:     0.95,
+		minIterations:        1,
 	}
 
 	for _, opt := range opts {
 		opt(r)
 	}
+	r.maxIterations = max(r.maxIterations, r.minIterations)
 
 	return r
 }
 
 // Execute performs iterative refinement: generate → critique → refine
 func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.reflection.%s", r.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "reflection"),
@@ -84,6 +126,11 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 	var currentOutput *agent.Message
 	var previousScore float64
 	var lastCritique *agent.Message
+	var bestOutput *agent.Message
+	bestScore := -1.0
+	bestIteration := 0
+	var score float64
+	iterations := 0
 
 	for iteration := 0; iteration < r.maxIterations; iteration++ {
 		iterationStart := time.Now()
@@ -104,9 +151,9 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 		}
 
 		currentOutput = generated
+		iterations++
 
 		// Get critique (possibly from multiple critics)
-		var score float64
 
 		if len(r.critics) > 1 {
 			// Multi-critic: aggregate feedback from all critics
@@ -127,6 +174,10 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 			score = extractQualityScore(lastCritique)
 		}
 
+		if score > bestScore {
+			bestOutput, bestScore, bestIteration = generated, score, iteration
+		}
+
 		iterationDuration := time.Since(iterationStart)
 
 		span.SetAttributes(
@@ -135,6 +186,20 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 			attribute.Int64(fmt.Sprintf("iteration.%d.duration_ms", iteration), iterationDuration.Milliseconds()),
 		)
 
+		// Early stopping only applies once the minimum iterations have run
+		if iterations < r.minIterations {
+			previousScore = score
+			continue
+		}
+
+		// Check if score is good enough (assume 1.0 is perfect)
+		if score >= r.qualityThreshold {
+			span.SetAttributes(
+				attribute.String("orchestration.stop_reason", "quality_threshold_met"),
+			)
+			break
+		}
+
 		// Check if we should continue iterating
 		if iteration > 0 {
 			improvement := score - previousScore
@@ -148,14 +213,6 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 			}
 		}
 
-		// Check if score is good enough (assume 1.0 is perfect)
-		if score >= 0.95 {
-			span.SetAttributes(
-				attribute.String("orchestration.stop_reason", "quality_threshold_met"),
-			)
-			break
-		}
-
 		previousScore = score
 	}
 
@@ -163,10 +220,32 @@ func (r *Reflection) Execute(ctx context.Context, input *agent.Message) (*agent.
 
 	span.SetAttributes(
 		attribute.Int64("orchestration.total_duration_ms", totalDuration.Milliseconds()),
+		attribute.Int("orchestration.iterations", iterations),
 		attribute.Bool("orchestration.success", true),
 	)
 
-	return currentOutput, nil
+	result := currentOutput
+	if r.keepBest && bestOutput != nil {
+		span.SetAttributes(
+			attribute.Int("orchestration.best_iteration", bestIteration),
+			attribute.Float64("orchestration.best_score", bestScore),
+		)
+		result, score = bestOutput, bestScore
+	}
+
+	if result != nil {
+		// Annotate a copy: the generator may still hold the message it returned
+		result = result.Clone()
+		if result.Message == nil {
+			result.Message = &pb.Message{}
+		}
+		if result.Metadata == nil {
+			result.Metadata = make(map[string]any)
+		}
+		result.Metadata[MetadataKeyReflectionIterations] = iterations
+		result.Metadata[MetadataKeyReflectionScore] = score
+	}
+	return result, nil
 }
 
 // combineWithCritique combines original input with critique for refinement
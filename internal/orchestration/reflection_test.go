@@ -0,0 +1,154 @@
+package orchestration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// scriptedAgent returns its responses in order, repeating the last one, and
+// records the payloads it received
+type scriptedAgent struct {
+	*MockAgent
+	responses []string
+	calls     int
+	inputs    []string
+}
+
+func (s *scriptedAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	resp := s.responses[min(s.calls, len(s.responses)-1)]
+	s.calls++
+	s.inputs = append(s.inputs, input.Payload)
+	return &agent.Message{Message: &pb.Message{Payload: resp}}, nil
+}
+
+func newScriptedAgent(name string, responses ...string) *scriptedAgent {
+	return &scriptedAgent{
+		MockAgent: NewMockAgent(name, "test", 0, ""),
+		responses: responses,
+	}
+}
+
+func TestReflectionKeepBest(t *testing.T) {
+	tests := []struct {
+		name     string
+		keepBest bool
+		want     string
+	}{
+		{"returns last iteration by default", false, "v3"},
+		{"keeps highest scoring iteration", true, "v2"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewMockRuntime()
+			_ = rt.Register(newScriptedAgent("generator", "v1", "v2", "v3"))
+			// v3 regresses below v2, which stops the loop with v3 as the latest output
+			_ = rt.Register(newScriptedAgent("critic", "Score: 5/10", "Score: 8/10", "Score: 4/10"))
+
+			r := NewReflection("reflect", rt, "generator", "critic",
+				WithMaxIterations(5),
+				WithKeepBest(tt.keepBest),
+			)
+
+			result, err := r.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "task"}})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Payload != tt.want {
+				t.Errorf("Execute() payload = %q, want %q", result.Payload, tt.want)
+			}
+		})
+	}
+}
+
+func TestReflectionQualityThreshold(t *testing.T) {
+	tests := []struct {
+		name           string
+		opts           []ReflectionOption
+		want           string
+		wantIterations int
+	}{
+		{"stops once the threshold is met", []ReflectionOption{WithQualityThreshold(0.7)}, "v2", 2},
+		{"default threshold keeps refining", nil, "v4", 4},
+		{"min iterations overrides an early pass", []ReflectionOption{WithQualityThreshold(0.5), WithMinIterations(3)}, "v3", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewMockRuntime()
+			generator := newScriptedAgent("generator", "v1", "v2", "v3", "v4", "v5")
+			_ = rt.Register(generator)
+			_ = rt.Register(newScriptedAgent("critic", "Score: 6/10", "Score: 7.5/10", "Score: 8.7/10", "Score: 9.8/10"))
+
+			opts := append([]ReflectionOption{WithMaxIterations(5), WithImprovementThreshold(0.05)}, tt.opts...)
+			r := NewReflection("reflect", rt, "generator", "critic", opts...)
+
+			result, err := r.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "task"}})
+			if err != nil {
+				t.Fatalf("Execute() error = %v", err)
+			}
+			if result.Payload != tt.want {
+				t.Errorf("Execute() payload = %q, want %q", result.Payload, tt.want)
+			}
+			if got := result.Metadata[MetadataKeyReflectionIterations]; got != tt.wantIterations {
+				t.Errorf("iterations = %v, want %d", got, tt.wantIterations)
+			}
+			if generator.calls != tt.wantIterations {
+				t.Errorf("generator called %d times, want %d", generator.calls, tt.wantIterations)
+			}
+		})
+	}
+}
+
+func TestReflectionMinIterationsForcesRefinement(t *testing.T) {
+	rt := NewMockRuntime()
+	generator := newScriptedAgent("generator", "v1", "v2")
+	_ = rt.Register(generator)
+	// The first draft already passes the default threshold
+	_ = rt.Register(newScriptedAgent("critic", "Score: 10/10"))
+
+	r := NewReflection("reflect", rt, "generator", "critic", WithMaxIterations(1), WithMinIterations(2))
+	result, err := r.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "task"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "v2" || generator.calls != 2 {
+		t.Errorf("Execute() = %q after %d generator calls, want v2 after 2", result.Payload, generator.calls)
+	}
+	if score := result.Metadata[MetadataKeyReflectionScore]; score != 1.0 {
+		t.Errorf("score = %v, want 1.0", score)
+	}
+}
+
+// sharedAgent returns the same message on every call, like an agent that
+// caches its response
+type sharedAgent struct {
+	*MockAgent
+	msg *agent.Message
+}
+
+func (s *sharedAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return s.msg, nil
+}
+
+func TestReflectionDoesNotMutateGeneratorOutput(t *testing.T) {
+	shared := &agent.Message{Message: &pb.Message{Payload: "answer"}}
+	rt := NewMockRuntime()
+	_ = rt.Register(&sharedAgent{MockAgent: NewMockAgent("generator", "test", 0, ""), msg: shared})
+	_ = rt.Register(newScriptedAgent("critic", "Score: 10/10"))
+
+	r := NewReflection("reflect", rt, "generator", "critic")
+	result, err := r.Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "task"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyReflectionIterations] != 1 {
+		t.Errorf("result iterations = %v, want 1", result.Metadata[MetadataKeyReflectionIterations])
+	}
+	if result == shared || shared.Metadata != nil {
+		t.Errorf("generator's message was annotated in place: %v", shared.Metadata)
+	}
+}
@@ -0,0 +1,108 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// documentSeparator separates documents in a retrieval payload
+const documentSeparator = "\n---\n"
+
+// RerankCandidate is a retrieved document sent to the reranker agent
+type RerankCandidate struct {
+	ID      string `json:"id"`
+	Content string `json:"content"`
+}
+
+// RerankScore is one entry of a reranker agent's reply
+type RerankScore struct {
+	ID    string  `json:"id"`
+	Score float64 `json:"score"`
+}
+
+// rerankRequest is the payload sent to the reranker agent
+type rerankRequest struct {
+	Query     string            `json:"query"`
+	Documents []RerankCandidate `json:"documents"`
+}
+
+// rerankDocuments asks the reranker agent to score the retrieved documents
+// against the query and returns them reordered by score and trimmed to
+// topK. The reranker receives {"query", "documents": [{"id", "content"}]}
+// JSON, where ids are the documents' positions in retrieval order, and
+// replies with [{"id", "score"}] (or {"results": [...]}). Documents it omits
+// are dropped. A reply that is not a ranking is used as the documents
+// themselves, for rerankers that return reordered text.
+func (r *RAG) rerankDocuments(ctx context.Context, query, documents *agent.Message) (*agent.Message, error) {
+	docs := strings.Split(payloadOf(documents), documentSeparator)
+	req := rerankRequest{Query: payloadOf(query), Documents: make([]RerankCandidate, len(docs))}
+	for i, doc := range docs {
+		req.Documents[i] = RerankCandidate{ID: strconv.Itoa(i), Content: doc}
+	}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal rerank request: %w", err)
+	}
+
+	result, err := r.runtime.Call(ctx, r.reranker, &agent.Message{
+		Message: &pb.Message{Type: "rag_rerank", Payload: string(payload)},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	scores, ok := parseRerankScores(payloadOf(result))
+	if !ok {
+		return result, nil
+	}
+	// Highest score first; the reranker's order breaks ties
+	sort.SliceStable(scores, func(i, j int) bool { return scores[i].Score > scores[j].Score })
+
+	reranked := make([]string, 0, len(scores))
+	used := make(map[int]bool, len(scores))
+	for _, s := range scores {
+		i, err := strconv.Atoi(s.ID)
+		if err != nil || i < 0 || i >= len(docs) || used[i] {
+			continue
+		}
+		used[i] = true
+		reranked = append(reranked, docs[i])
+		if r.topK > 0 && len(reranked) == r.topK {
+			break
+		}
+	}
+	if len(reranked) == 0 {
+		return nil, fmt.Errorf("reranker returned no known document ids")
+	}
+
+	out := documents.Clone()
+	if out == nil || out.Message == nil {
+		out = &agent.Message{Message: &pb.Message{}}
+	}
+	out.Payload = strings.Join(reranked, documentSeparator)
+	return out, nil
+}
+
+// parseRerankScores decodes a reranker's JSON reply, reporting false if the
+// payload is not a ranking
+func parseRerankScores(payload string) ([]RerankScore, bool) {
+	payload = strings.TrimSpace(payload)
+	var scores []RerankScore
+	if err := json.Unmarshal([]byte(payload), &scores); err == nil {
+		return scores, true
+	}
+	var wrapped struct {
+		Results []RerankScore `json:"results"`
+	}
+	if err := json.Unmarshal([]byte(payload), &wrapped); err == nil && wrapped.Results != nil {
+		return wrapped.Results, true
+	}
+	return nil, false
+}
@@ -0,0 +1,102 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// reversingReranker scores candidates in reverse retrieval order
+type reversingReranker struct {
+	*MockAgent
+	request rerankRequest
+}
+
+func (a *reversingReranker) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	if _, err := a.MockAgent.Execute(ctx, input); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(input.Payload), &a.request); err != nil {
+		return nil, err
+	}
+	scores := make([]RerankScore, len(a.request.Documents))
+	for i, doc := range a.request.Documents {
+		scores[i] = RerankScore{ID: doc.ID, Score: float64(i + 1)}
+	}
+	payload, _ := json.Marshal(scores)
+	return &agent.Message{Message: &pb.Message{Payload: string(payload)}}, nil
+}
+
+func TestRAGRerankerReordersDocuments(t *testing.T) {
+	rt := NewMockRuntime()
+	reranker := &reversingReranker{MockAgent: NewMockAgent("reranker", "test", 0, "")}
+	generator := newScriptedAgent("generator", "answer")
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Doc A\n---\nDoc B\n---\nDoc C"))
+	_ = rt.Register(reranker)
+	_ = rt.Register(generator)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "Which doc?"}}
+	rag := NewRAG("rag", rt, "retriever", "generator", WithReranker("reranker"), WithTopK(2))
+	if _, err := rag.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// The reranker sees the query and every candidate
+	if reranker.request.Query != "Which doc?" || len(reranker.request.Documents) != 3 {
+		t.Fatalf("reranker request = %+v", reranker.request)
+	}
+	if got := reranker.request.Documents[2]; got.ID != "2" || got.Content != "Doc C" {
+		t.Errorf("third candidate = %+v, want {2 Doc C}", got)
+	}
+
+	// The generator gets the reranked top-K only
+	if len(generator.inputs) != 1 {
+		t.Fatalf("generator called %d times, want 1", len(generator.inputs))
+	}
+	if want := "Context:\nDoc C\n---\nDoc B\n\nQuery:\nWhich doc?"; generator.inputs[0] != want {
+		t.Errorf("generator input = %q, want %q", generator.inputs[0], want)
+	}
+}
+
+func TestRAGRerankerReply(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    string
+		wantErr bool
+	}{
+		{"orders by score", `[{"id":"0","score":0.2},{"id":"2","score":0.9}]`, "Doc C\n---\nDoc A", false},
+		{"wrapped results", `{"results":[{"id":"1","score":1}]}`, "Doc B", false},
+		{"ignores unknown and repeated ids", `[{"id":"7","score":5},{"id":"1","score":1},{"id":"1","score":1}]`, "Doc B", false},
+		{"text reply used as documents", "Doc B\n---\nDoc A", "Doc B\n---\nDoc A", false},
+		{"no known ids", `[{"id":"x","score":1}]`, "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rt := NewMockRuntime()
+			_ = rt.Register(newScriptedAgent("reranker", tt.reply))
+			rag := NewRAG("rag", rt, "retriever", "generator", WithReranker("reranker"))
+
+			docs := &agent.Message{Message: &pb.Message{Payload: "Doc A\n---\nDoc B\n---\nDoc C"}}
+			query := &agent.Message{Message: &pb.Message{Payload: "q"}}
+			got, err := rag.rerankDocuments(context.Background(), query, docs)
+			if tt.wantErr {
+				if err == nil || !strings.Contains(err.Error(), "no known document ids") {
+					t.Fatalf("rerankDocuments() error = %v, want no known ids error", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("rerankDocuments() error = %v", err)
+			}
+			if got.Payload != tt.want {
+				t.Errorf("rerankDocuments() = %q, want %q", got.Payload, tt.want)
+			}
+		})
+	}
+}
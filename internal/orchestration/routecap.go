@@ -0,0 +1,218 @@
+package orchestration
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
+)
+
+// ErrRouteCapExceeded is returned when a route has reached its cost cap and
+// has no fallback route with remaining capacity.
+var ErrRouteCapExceeded = errors.New("route cost cap exceeded")
+
+// DefaultRouteName names the Router's default route in WithRouteCap,
+// RouteCap.Fallback and RouteSpend. It cannot collide with a classification.
+const DefaultRouteName = "*"
+
+// RouteCap limits how much a single Router route may spend. Windows are
+// fixed UTC calendar hours and days. A zero limit is unlimited. Calls in
+// flight count toward the cap at their estimated cost until they complete,
+// so concurrent requests cannot all slip past it.
+type RouteCap struct {
+	// MaxUSDPerHour caps spend within the current hour
+	MaxUSDPerHour float64
+	// MaxUSDPerDay caps spend within the current day
+	MaxUSDPerDay float64
+	// Fallback is the route used once this route is capped. If empty, capped
+	// requests are rejected with ErrRouteCapExceeded.
+	Fallback string
+}
+
+// RouteCapExceededError describes a request refused by a route cost cap.
+// It matches ErrRouteCapExceeded with errors.Is.
+type RouteCapExceededError struct {
+	Route    string
+	Window   string // "hour" or "day"
+	SpentUSD float64
+	MaxUSD   float64
+}
+
+func (e *RouteCapExceededError) Error() string {
+	return fmt.Sprintf("route cost cap exceeded: route %s spent $%.4f of $%.4f this %s",
+		e.Route, e.SpentUSD, e.MaxUSD, e.Window)
+}
+
+// Is reports whether target is ErrRouteCapExceeded
+func (e *RouteCapExceededError) Is(target error) bool {
+	return target == ErrRouteCapExceeded
+}
+
+// RouteSpend reports the spend recorded for one route
+type RouteSpend struct {
+	HourUSD  float64 // Spend in the current hour
+	DayUSD   float64 // Spend in the current day
+	TotalUSD float64 // Spend since the router was created
+	Capped   bool    // Whether the route has reached a cap
+}
+
+// WithRouteCap caps the spend of the route for a classification, or of the
+// default route if route is DefaultRouteName. Spend is taken from usage the
+// routed agent reports in its response metadata, priced with the budget's
+// calculator or cost.DefaultCalculator. A call is estimated at the budget's
+// pre-call estimate, or else the cost of the route's previous call.
+func WithRouteCap(route string, routeCap RouteCap) RouterOption {
+	return routerOptionFunc(func(r *Router) {
+		if r.routeCaps == nil {
+			r.routeCaps = newRouteCaps()
+		}
+		r.routeCaps.caps[route] = routeCap
+	})
+}
+
+// routeCaps tracks per-route spend against optional caps
+type routeCaps struct {
+	caps  map[string]RouteCap
+	spend map[string]*routeSpend
+	now   func() time.Time
+	mu    sync.Mutex
+}
+
+type routeSpend struct {
+	hourStart time.Time
+	dayStart  time.Time
+	hour      float64
+	day       float64
+	total     float64
+	pending   float64 // Reserved for calls in flight
+	last      float64 // Cost of the last completed call
+}
+
+func newRouteCaps() *routeCaps {
+	return &routeCaps{
+		caps:  make(map[string]RouteCap),
+		spend: make(map[string]*routeSpend),
+		now:   time.Now,
+	}
+}
+
+// reserve returns the route to use for route, following fallbacks past
+// capped routes, and reserves the call's estimated cost against it. The
+// reservation must be released with settle.
+func (c *routeCaps) reserve(route string, estimate func(route string) float64) (string, float64, error) {
+	if c == nil {
+		return route, 0, nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	visited := make(map[string]bool)
+	for {
+		err := c.checkLocked(route)
+		if err == nil {
+			s := c.rollLocked(route)
+			reserved := max(estimate(route), s.last)
+			s.pending += reserved
+			return route, reserved, nil
+		}
+		visited[route] = true
+
+		next := c.caps[route].Fallback
+		if next == "" || visited[next] {
+			return "", 0, err
+		}
+		route = next
+	}
+}
+
+// checkLocked returns an error if route has reached one of its caps
+func (c *routeCaps) checkLocked(route string) error {
+	rc, ok := c.caps[route]
+	if !ok {
+		return nil
+	}
+
+	s := c.rollLocked(route)
+	if rc.MaxUSDPerHour > 0 && s.hour+s.pending >= rc.MaxUSDPerHour {
+		return &RouteCapExceededError{Route: route, Window: "hour", SpentUSD: s.hour, MaxUSD: rc.MaxUSDPerHour}
+	}
+	if rc.MaxUSDPerDay > 0 && s.day+s.pending >= rc.MaxUSDPerDay {
+		return &RouteCapExceededError{Route: route, Window: "day", SpentUSD: s.day, MaxUSD: rc.MaxUSDPerDay}
+	}
+	return nil
+}
+
+// settle releases a reservation made by reserve and adds usd, the call's
+// actual cost, to route's spend
+func (c *routeCaps) settle(route string, reserved, usd float64) {
+	if c == nil {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	s := c.rollLocked(route)
+	s.pending = max(0, s.pending-reserved)
+	s.hour += usd
+	s.day += usd
+	s.total += usd
+	if usd > 0 {
+		s.last = usd
+	}
+}
+
+// rollLocked returns route's spend, resetting windows that have ended
+func (c *routeCaps) rollLocked(route string) *routeSpend {
+	now := c.now().UTC()
+	hourStart := now.Truncate(time.Hour)
+	dayStart := now.Truncate(24 * time.Hour)
+
+	s, ok := c.spend[route]
+	if !ok {
+		s = &routeSpend{hourStart: hourStart, dayStart: dayStart}
+		c.spend[route] = s
+	}
+	if !s.hourStart.Equal(hourStart) {
+		s.hourStart, s.hour = hourStart, 0
+	}
+	if !s.dayStart.Equal(dayStart) {
+		s.dayStart, s.day = dayStart, 0
+	}
+	return s
+}
+
+func (c *routeCaps) status() map[string]RouteSpend {
+	if c == nil {
+		return nil
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	out := make(map[string]RouteSpend, len(c.spend))
+	for route := range c.spend {
+		s := c.rollLocked(route)
+		out[route] = RouteSpend{
+			HourUSD:  s.hour,
+			DayUSD:   s.day,
+			TotalUSD: s.total,
+			Capped:   c.checkLocked(route) != nil,
+		}
+	}
+	return out
+}
+
+// routeCost prices a routed response when no budget is configured
+func routeCost(result *agent.Message) float64 {
+	if result == nil || result.Message == nil || result.Metadata == nil {
+		return 0
+	}
+	model, _ := result.Metadata[MetadataKeyModel].(string)
+	usd, _ := reportedCost(cost.DefaultCalculator, model, result.Metadata)
+	return usd
+}
@@ -0,0 +1,185 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// costAgent returns a fixed payload and reports its cost in metadata. If
+// started and release are set, it signals started and waits for release.
+type costAgent struct {
+	*MockAgent
+	usd     float64
+	started chan struct{}
+	release chan struct{}
+}
+
+func (c *costAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	_, _ = c.MockAgent.Execute(ctx, input)
+	if c.started != nil {
+		c.started <- struct{}{}
+		<-c.release
+	}
+	return &agent.Message{Message: &pb.Message{
+		Payload:  c.response,
+		Metadata: map[string]interface{}{MetadataKeyCostUSD: c.usd},
+	}}, nil
+}
+
+func newCostAgent(name, response string, usd float64) *costAgent {
+	return &costAgent{MockAgent: NewMockAgent(name, "test", 0, response), usd: usd}
+}
+
+func TestRouterRouteCapFallback(t *testing.T) {
+	rt := NewMockRuntime()
+	expensive := newUsageAgent("expensive", "deep answer", 500_000, 500_000)
+	cheap := newUsageAgent("cheap", "quick answer", 1000, 1000)
+	_ = rt.Register(NewMockAgent("classifier", "test", 0, "complex"))
+	_ = rt.Register(expensive)
+	_ = rt.Register(cheap)
+
+	router := NewRouter("router", rt, "classifier",
+		map[string]string{"simple": "cheap", "complex": "expensive"},
+		WithBudget(100, budgetCalculator()),
+		WithRouteCap("complex", RouteCap{MaxUSDPerHour: 1.5, Fallback: "simple"}),
+	)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	want := []string{"deep answer", "deep answer", "quick answer"}
+	for i, w := range want {
+		result, err := router.Execute(context.Background(), input)
+		if err != nil {
+			t.Fatalf("Execute() #%d error = %v", i+1, err)
+		}
+		if result.Payload != w {
+			t.Errorf("Execute() #%d payload = %q, want %q", i+1, result.Payload, w)
+		}
+	}
+
+	if expensive.CallCount() != 2 {
+		t.Errorf("expensive called %d times, want 2", expensive.CallCount())
+	}
+
+	spend := router.RouteSpend()
+	if got := spend["complex"]; math.Abs(got.HourUSD-2.0) > 1e-9 || !got.Capped {
+		t.Errorf("RouteSpend()[complex] = %+v, want $2.00 this hour and capped", got)
+	}
+	if got := spend["simple"]; math.Abs(got.TotalUSD-0.002) > 1e-9 || got.Capped {
+		t.Errorf("RouteSpend()[simple] = %+v, want $0.002 and not capped", got)
+	}
+}
+
+func TestRouterRouteCapRejects(t *testing.T) {
+	rt := NewMockRuntime()
+	expensive := newCostAgent("expensive", "deep answer", 3)
+	_ = rt.Register(NewMockAgent("classifier", "test", 0, "complex"))
+	_ = rt.Register(expensive)
+
+	router := NewRouter("router", rt, "classifier",
+		map[string]string{"complex": "expensive"},
+		WithRouteCap("complex", RouteCap{MaxUSDPerHour: 10, MaxUSDPerDay: 5}),
+	)
+
+	now := time.Date(2025, 1, 1, 10, 30, 0, 0, time.UTC)
+	router.routeCaps.now = func() time.Time { return now }
+
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	for i := 0; i < 2; i++ {
+		if _, err := router.Execute(context.Background(), input); err != nil {
+			t.Fatalf("Execute() #%d error = %v", i+1, err)
+		}
+	}
+
+	_, err := router.Execute(context.Background(), input)
+	if !errors.Is(err, ErrRouteCapExceeded) {
+		t.Fatalf("Execute() error = %v, want ErrRouteCapExceeded", err)
+	}
+	var capErr *RouteCapExceededError
+	if !errors.As(err, &capErr) || capErr.Route != "complex" || capErr.Window != "day" {
+		t.Errorf("Execute() error = %#v, want day cap on complex", err)
+	}
+	if expensive.CallCount() != 2 {
+		t.Errorf("expensive called %d times after cap, want 2", expensive.CallCount())
+	}
+
+	// A new day resets the window
+	now = now.Add(24 * time.Hour)
+	if _, err := router.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute() on the next day error = %v", err)
+	}
+	if got := router.RouteSpend()["complex"]; got.DayUSD != 3 || got.TotalUSD != 9 {
+		t.Errorf("RouteSpend()[complex] = %+v, want $3 today and $9 total", got)
+	}
+}
+
+func TestRouterRouteCapDefaultRoute(t *testing.T) {
+	rt := NewMockRuntime()
+	general := newCostAgent("general", "answer", 3)
+	_ = rt.Register(NewMockAgent("classifier", "test", 0, "unknown"))
+	_ = rt.Register(general)
+
+	router := NewRouter("router", rt, "classifier",
+		map[string]string{"complex": "expensive"},
+		WithDefaultRoute("general"),
+		WithRouteCap(DefaultRouteName, RouteCap{MaxUSDPerHour: 5}),
+	)
+
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	for i := 0; i < 2; i++ {
+		if _, err := router.Execute(context.Background(), input); err != nil {
+			t.Fatalf("Execute() #%d error = %v", i+1, err)
+		}
+	}
+	if _, err := router.Execute(context.Background(), input); !errors.Is(err, ErrRouteCapExceeded) {
+		t.Fatalf("Execute() error = %v, want ErrRouteCapExceeded", err)
+	}
+	if general.CallCount() != 2 {
+		t.Errorf("general called %d times, want 2", general.CallCount())
+	}
+	if got := router.RouteSpend()[DefaultRouteName]; got.HourUSD != 6 || !got.Capped {
+		t.Errorf("RouteSpend()[%s] = %+v, want $6 this hour and capped", DefaultRouteName, got)
+	}
+}
+
+func TestRouterRouteCapReservesInFlightCalls(t *testing.T) {
+	rt := NewMockRuntime()
+	expensive := newCostAgent("expensive", "deep answer", 1)
+	_ = rt.Register(NewMockAgent("classifier", "test", 0, "complex"))
+	_ = rt.Register(expensive)
+
+	router := NewRouter("router", rt, "classifier",
+		map[string]string{"complex": "expensive"},
+		WithRouteCap("complex", RouteCap{MaxUSDPerHour: 1.5}),
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	if _, err := router.Execute(context.Background(), input); err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+
+	// The second call is under the cap, but holds a reservation while in flight
+	expensive.started = make(chan struct{})
+	expensive.release = make(chan struct{})
+	done := make(chan error, 1)
+	go func() {
+		_, err := router.Execute(context.Background(), input)
+		done <- err
+	}()
+	<-expensive.started
+
+	if _, err := router.Execute(context.Background(), input); !errors.Is(err, ErrRouteCapExceeded) {
+		t.Errorf("concurrent Execute() error = %v, want ErrRouteCapExceeded", err)
+	}
+	close(expensive.release)
+	if err := <-done; err != nil {
+		t.Fatalf("in-flight Execute() error = %v", err)
+	}
+	if expensive.CallCount() != 2 {
+		t.Errorf("expensive called %d times, want 2", expensive.CallCount())
+	}
+}
@@ -25,16 +25,27 @@ type Router struct {
 	classifier   string            // Agent that classifies the input
 	routes       map[string]string // Map of classification → agent name
 	defaultRoute string            // Fallback agent if classification not found
+	budget       *budget           // Optional cost ceiling
+	routeCaps    *routeCaps        // Optional per-route cost caps
 }
 
+var _ agent.Agent = (*Router)(nil)
+
 // RouterOption configures a Router orchestrator
-type RouterOption func(*Router)
+type RouterOption interface {
+	applyRouter(*Router)
+}
+
+// routerOptionFunc adapts a function to a RouterOption
+type routerOptionFunc func(*Router)
+
+func (f routerOptionFunc) applyRouter(r *Router) { f(r) }
 
 // WithDefaultRoute sets the fallback agent
 func WithDefaultRoute(agent string) RouterOption {
-	return func(r *Router) {
+	return routerOptionFunc(func(r *Router) {
 		r.defaultRoute = agent
-	}
+	})
 }
 
 // NewRouter creates a new Router orchestrator
@@ -46,14 +57,61 @@ func NewRouter(name string, runtime agent.Runtime, classifier string, routes map
 	}
 
 	for _, opt := range opts {
-		opt(r)
+		opt.applyRouter(r)
 	}
 
 	return r
 }
 
+// SpentUSD returns the accumulated spend tracked by WithBudget, or 0 if no budget is set
+func (r *Router) SpentUSD() float64 {
+	return r.budget.spentUSD()
+}
+
+// RouteSpend returns the spend recorded for each route, or nil if no route
+// caps are set
+func (r *Router) RouteSpend() map[string]RouteSpend {
+	return r.routeCaps.status()
+}
+
+// BudgetStatus returns the spend tracked by WithBudget and WithRouteCap
+func (r *Router) BudgetStatus() BudgetStatus {
+	return BudgetStatus{
+		SpentUSD: r.budget.spentUSD(),
+		MaxUSD:   r.budget.maxSpendUSD(),
+		Routes:   r.routeCaps.status(),
+	}
+}
+
+// routeAgent returns the agent serving route, which may be DefaultRouteName
+func (r *Router) routeAgent(route string) (string, bool) {
+	if route == DefaultRouteName {
+		return r.defaultRoute, r.defaultRoute != ""
+	}
+	target, ok := r.routes[route]
+	return target, ok
+}
+
+// call invokes target through the runtime, enforcing the budget if one is set.
+// It returns the cost of the call.
+func (r *Router) call(ctx context.Context, target string, input *agent.Message) (*agent.Message, float64, error) {
+	estimate, err := r.budget.reserve(target, input)
+	if err != nil {
+		return nil, 0, err
+	}
+	result, err := r.runtime.Call(ctx, target, input)
+	if err != nil {
+		return nil, 0, err
+	}
+	if r.budget != nil {
+		return result, r.budget.charge(target, result, estimate), nil
+	}
+	return result, routeCost(result), nil
+}
+
 // Execute classifies the input and routes to the appropriate agent
 func (r *Router) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.router.%s", r.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "router"),
@@ -67,7 +125,7 @@ func (r *Router) Execute(ctx context.Context, input *agent.Message) (*agent.Mess
 
 	// Step 1: Classify the input
 	classifyStart := time.Now()
-	classification, err := r.runtime.Call(ctx, r.classifier, input)
+	classification, _, err := r.call(ctx, r.classifier, input)
 	classifyDuration := time.Since(classifyStart)
 
 	if err != nil {
@@ -86,9 +144,11 @@ func (r *Router) Execute(ctx context.Context, input *agent.Message) (*agent.Mess
 	span.SetAttributes(attribute.String("orchestration.classification", classResult))
 
 	// Step 3: Route to appropriate agent
-	targetAgent, ok := r.routes[classResult]
+	route := classResult
+	targetAgent, ok := r.routes[route]
 	if !ok {
 		if r.defaultRoute != "" {
+			route = DefaultRouteName
 			targetAgent = r.defaultRoute
 			span.SetAttributes(attribute.Bool("orchestration.used_default_route", true))
 		} else {
@@ -98,12 +158,35 @@ func (r *Router) Execute(ctx context.Context, input *agent.Message) (*agent.Mess
 		}
 	}
 
+	// Capped routes fall back to a cheaper route or are rejected. The
+	// call's cost is reserved until it completes.
+	resolved, reserved, err := r.routeCaps.reserve(route, func(route string) float64 {
+		target, _ := r.routeAgent(route)
+		return r.budget.estimate(target, input)
+	})
+	if err != nil {
+		span.RecordError(err)
+		return nil, err
+	}
+	if resolved != route {
+		fallback, ok := r.routeAgent(resolved)
+		if !ok {
+			r.routeCaps.settle(resolved, reserved, 0)
+			err := fmt.Errorf("fallback route %s for %s has no agent", resolved, route)
+			span.RecordError(err)
+			return nil, err
+		}
+		span.SetAttributes(attribute.String("orchestration.route_cap_fallback", resolved))
+		route, targetAgent = resolved, fallback
+	}
+
 	span.SetAttributes(attribute.String("orchestration.target_agent", targetAgent))
 
 	// Step 4: Execute target agent
 	executeStart := time.Now()
-	result, err := r.runtime.Call(ctx, targetAgent, input)
+	result, usd, err := r.call(ctx, targetAgent, input)
 	executeDuration := time.Since(executeStart)
+	r.routeCaps.settle(route, reserved, usd)
 
 	totalDuration := time.Since(startTime)
 
@@ -112,6 +195,9 @@ func (r *Router) Execute(ctx context.Context, input *agent.Message) (*agent.Mess
 		attribute.Int64("orchestration.total_duration_ms", totalDuration.Milliseconds()),
 		attribute.Bool("orchestration.success", err == nil),
 	)
+	if r.budget != nil {
+		span.SetAttributes(attribute.Float64("orchestration.spent_usd", r.SpentUSD()))
+	}
 
 	if err != nil {
 		span.RecordError(err)
@@ -0,0 +1,114 @@
+package orchestration
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Sequential executes agents in order, passing each agent's output as the
+// next agent's input.
+//
+// Use cases:
+// - ETL pipelines
+// - Multi-step content generation (draft → edit → format)
+// - Staged analysis
+type Sequential struct {
+	*BaseOrchestrator
+	agents        []string
+	budget        *budget // Optional cost ceiling
+	errorMessages bool    // If true, failed steps pass an error message downstream
+}
+
+// SequentialOption configures a Sequential orchestrator
+type SequentialOption interface {
+	applySequential(*Sequential)
+}
+
+// NewSequential creates a new Sequential orchestrator
+func NewSequential(name string, runtime agent.Runtime, agents []string, opts ...SequentialOption) *Sequential {
+	s := &Sequential{
+		BaseOrchestrator: NewBaseOrchestrator(name, "sequential", runtime),
+		agents:           agents,
+	}
+
+	for _, opt := range opts {
+		opt.applySequential(s)
+	}
+
+	s.SetReady(true)
+	return s
+}
+
+// SpentUSD returns the accumulated spend tracked by WithBudget, or 0 if no budget is set
+func (s *Sequential) SpentUSD() float64 {
+	return s.budget.spentUSD()
+}
+
+// BudgetStatus returns the spend tracked by WithBudget
+func (s *Sequential) BudgetStatus() BudgetStatus {
+	return BudgetStatus{SpentUSD: s.budget.spentUSD(), MaxUSD: s.budget.maxSpendUSD()}
+}
+
+// Execute runs each agent in turn and returns the last agent's output
+func (s *Sequential) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
+	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.sequential.%s", s.name),
+		trace.WithAttributes(
+			attribute.String("orchestration.pattern", "sequential"),
+			attribute.StringSlice("orchestration.agents", s.agents),
+			attribute.Int("orchestration.agent_count", len(s.agents)),
+		),
+	)
+	defer span.End()
+
+	if len(s.agents) == 0 {
+		err := fmt.Errorf("sequential %s has no agents", s.name)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	startTime := time.Now()
+	current := input
+
+	for i, target := range s.agents {
+		estimate, err := s.budget.reserve(target, current)
+		if err != nil {
+			span.RecordError(err)
+			return nil, fmt.Errorf("step %d (%s): %w", i+1, target, err)
+		}
+
+		stepStart := time.Now()
+		result, err := s.runtime.Call(ctx, target, current)
+		span.SetAttributes(
+			attribute.Int64(fmt.Sprintf("orchestration.step_%d_duration_ms", i+1), time.Since(stepStart).Milliseconds()),
+		)
+		if err != nil {
+			span.RecordError(err)
+			if !s.errorMessages {
+				return nil, fmt.Errorf("step %d (%s) failed: %w", i+1, target, err)
+			}
+			span.SetAttributes(attribute.String(fmt.Sprintf("error.%s", target), err.Error()))
+			current = agent.NewErrorMessage(target, err)
+			continue
+		}
+
+		s.budget.charge(target, result, estimate)
+		current = result
+	}
+
+	span.SetAttributes(
+		attribute.Int64("orchestration.duration_ms", time.Since(startTime).Milliseconds()),
+		attribute.Bool("orchestration.success", !current.IsError()),
+	)
+	if s.budget != nil {
+		span.SetAttributes(attribute.Float64("orchestration.spent_usd", s.SpentUSD()))
+	}
+
+	return current, nil
+}
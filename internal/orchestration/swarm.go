@@ -2,6 +2,7 @@ package orchestration
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strings"
@@ -23,63 +24,105 @@ import (
 // - Collaborative problem-solving
 type Swarm struct {
 	*BaseOrchestrator
-	agents      []string
-	entryAgent  string // Starting agent
-	maxHandoffs int    // Maximum number of handoffs to prevent loops
+	agents     []string
+	entryAgent string // Starting agent
+	maxHops    int    // Maximum number of handoffs to prevent loops
 }
 
+const (
+	// MetadataKeyHandoffTo is the metadata key an agent sets to hand off
+	// to the named agent
+	MetadataKeyHandoffTo = "handoff_to"
+
+	// MetadataKeyHopPath is the metadata key holding the agents a swarm
+	// visited, in order, on its final message
+	MetadataKeyHopPath = "hop_path"
+)
+
+// ErrMaxHopsExceeded is returned when a swarm is still handing off after
+// its maximum number of hops.
+var ErrMaxHopsExceeded = errors.New("max handoff hops exceeded")
+
+// ErrHandoffCycle is returned when a handoff would send an agent the same
+// input it has already handled, which would repeat forever.
+var ErrHandoffCycle = errors.New("handoff cycle detected")
+
 // SwarmOption configures a Swarm orchestrator
 type SwarmOption func(*Swarm)
 
-// WithMaxHandoffs sets the maximum number of handoffs
-func WithMaxHandoffs(max int) SwarmOption {
+// WithMaxHops sets the maximum number of handoffs (default 10)
+func WithMaxHops(max int) SwarmOption {
 	return func(s *Swarm) {
-		s.maxHandoffs = max
+		s.maxHops = max
 	}
 }
 
-// NewSwarm creates a new Swarm orchestrator
-func NewSwarm(name string, runtime agent.Runtime, entryAgent string, agents []string, opts ...SwarmOption) *Swarm {
+// WithInitialAgent sets the agent that receives the input (default: the
+// first agent)
+func WithInitialAgent(name string) SwarmOption {
+	return func(s *Swarm) {
+		s.entryAgent = name
+	}
+}
+
+// NewSwarm creates a new Swarm orchestrator. Each agent may hand off to
+// another agent of the swarm by setting handoff_to in its output metadata.
+func NewSwarm(name string, runtime agent.Runtime, agents []string, opts ...SwarmOption) *Swarm {
 	s := &Swarm{
 		BaseOrchestrator: NewBaseOrchestrator(name, "swarm", runtime),
 		agents:           agents,
-		entryAgent:       entryAgent,
-		maxHandoffs:      10, // Default max handoffs
+		maxHops:          10, // Default max handoffs
+	}
+	if len(agents) > 0 {
+		s.entryAgent = agents[0]
 	}
 
 	for _, opt := range opts {
 		opt(s)
 	}
 
+	s.SetReady(true)
 	return s
 }
 
-// Execute runs the swarm starting from the entry agent
+// Execute runs the swarm starting from the initial agent and follows
+// handoffs until an agent produces none. The final message carries the
+// visited agents under hop_path in its metadata.
 func (s *Swarm) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	ctx, input = agent.WithRequestID(ctx, input)
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.swarm.%s", s.name),
 		trace.WithAttributes(
 			attribute.String("orchestration.pattern", "swarm"),
 			attribute.String("orchestration.entry_agent", s.entryAgent),
-			attribute.Int("orchestration.max_handoffs", s.maxHandoffs),
+			attribute.Int("orchestration.max_hops", s.maxHops),
 		),
 	)
 	defer span.End()
 
+	if s.entryAgent == "" {
+		err := fmt.Errorf("swarm %s has no agents", s.name)
+		span.RecordError(err)
+		return nil, err
+	}
+
 	startTime := time.Now()
 	currentAgent := s.entryAgent
 	currentInput := input
-	handoffCount := 0
+	hopPath := []string{currentAgent}
+	seen := make(map[string]struct{})
 
 	for {
-		// Check handoff limit
-		if handoffCount >= s.maxHandoffs {
-			err := fmt.Errorf("max handoffs (%d) exceeded", s.maxHandoffs)
+		// An agent receiving an input it already handled would loop forever
+		key := currentAgent + "\x00" + payloadOf(currentInput)
+		if _, ok := seen[key]; ok {
+			err := fmt.Errorf("%w: %s", ErrHandoffCycle, strings.Join(hopPath, " -> "))
 			span.RecordError(err)
 			return nil, err
 		}
+		seen[key] = struct{}{}
 
 		span.SetAttributes(
-			attribute.String(fmt.Sprintf("handoff.%d.agent", handoffCount), currentAgent),
+			attribute.String(fmt.Sprintf("handoff.%d.agent", len(hopPath)-1), currentAgent),
 		)
 
 		// Execute current agent
@@ -97,9 +140,12 @@ func (s *Swarm) Execute(ctx context.Context, input *agent.Message) (*agent.Messa
 			duration := time.Since(startTime)
 			span.SetAttributes(
 				attribute.Int64("orchestration.duration_ms", duration.Milliseconds()),
-				attribute.Int("orchestration.handoff_count", handoffCount),
+				attribute.Int("orchestration.handoff_count", len(hopPath)-1),
+				attribute.StringSlice("orchestration.hop_path", hopPath),
 				attribute.Bool("orchestration.success", true),
 			)
+			result = annotatable(result)
+			result.Metadata[MetadataKeyHopPath] = hopPath
 			return result, nil
 		}
 
@@ -110,11 +156,27 @@ func (s *Swarm) Execute(ctx context.Context, input *agent.Message) (*agent.Messa
 			return nil, err
 		}
 
-		// Perform handoff
+		// Check handoff limit
+		if len(hopPath) > s.maxHops {
+			err := fmt.Errorf("%w: %d hops (%s)", ErrMaxHopsExceeded, s.maxHops, strings.Join(hopPath, " -> "))
+			span.RecordError(err)
+			return nil, err
+		}
+
+		// Pass the result to the next agent without the consumed handoff
+		currentInput = result.Clone()
+		delete(currentInput.Metadata, MetadataKeyHandoffTo)
 		currentAgent = nextAgent
-		currentInput = result // Pass previous result as input to next agent
-		handoffCount++
+		hopPath = append(hopPath, nextAgent)
+	}
+}
+
+// payloadOf returns msg's payload, or "" for an empty message
+func payloadOf(msg *agent.Message) string {
+	if msg == nil || msg.Message == nil {
+		return ""
 	}
+	return msg.Payload
 }
 
 // isValidAgent checks if an agent is in the swarm
@@ -135,7 +197,7 @@ func extractHandoff(msg *agent.Message) (string, bool) {
 
 	// Check metadata for handoff instruction
 	if msg.Metadata != nil {
-		if nextAgent, exists := msg.Metadata["handoff_to"]; exists {
+		if nextAgent, exists := msg.Metadata[MetadataKeyHandoffTo]; exists {
 			if nextStr, ok := nextAgent.(string); ok && nextStr != "" {
 				// Validate agent name format
 				if isValidAgentName(nextStr) {
@@ -1,6 +1,9 @@
 package orchestration
 
 import (
+	"context"
+	"errors"
+	"reflect"
 	"testing"
 
 	"github.com/aixgo-dev/aixgo/internal/agent"
@@ -144,3 +147,158 @@ func TestExtractHandoff(t *testing.T) {
 		})
 	}
 }
+
+// handoffAgent hands off to next, tagging the payload with its name, or
+// answers when next is empty
+type handoffAgent struct {
+	*MockAgent
+	next string
+	tag  bool
+}
+
+func newHandoffAgent(name, next string) *handoffAgent {
+	return &handoffAgent{MockAgent: NewMockAgent(name, "test", 0, ""), next: next, tag: true}
+}
+
+func (h *handoffAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	h.mu.Lock()
+	h.callCount++
+	h.mu.Unlock()
+
+	if _, stale := input.Metadata[MetadataKeyHandoffTo]; stale {
+		return nil, errors.New("received a consumed handoff")
+	}
+	payload := input.Payload
+	if h.tag {
+		payload += ">" + h.name
+	}
+	out := &agent.Message{Message: &pb.Message{Payload: payload, Metadata: map[string]any{}}}
+	if h.next != "" {
+		out.Metadata[MetadataKeyHandoffTo] = h.next
+	}
+	return out, nil
+}
+
+func newSwarmRuntime(agents ...agent.Agent) *MockRuntime {
+	rt := NewMockRuntime()
+	for _, a := range agents {
+		_ = rt.Register(a)
+	}
+	return rt
+}
+
+func TestSwarmExecute(t *testing.T) {
+	ctx := context.Background()
+	rt := newSwarmRuntime(
+		newHandoffAgent("general", "billing"),
+		newHandoffAgent("billing", "tech"),
+		newHandoffAgent("tech", ""),
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "refund"}}
+
+	swarm := NewSwarm("support", rt, []string{"general", "billing", "tech"})
+	if !swarm.Ready() {
+		t.Error("Ready() = false, want true")
+	}
+	result, err := swarm.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "refund>general>billing>tech" {
+		t.Errorf("Execute() payload = %q", result.Payload)
+	}
+	if got, want := result.Metadata[MetadataKeyHopPath], []string{"general", "billing", "tech"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hop_path = %v, want %v", got, want)
+	}
+
+	// Starting elsewhere follows the remaining handoffs
+	result, err = NewSwarm("support", rt, []string{"general", "billing", "tech"}, WithInitialAgent("billing")).Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := result.Metadata[MetadataKeyHopPath], []string{"billing", "tech"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hop_path = %v, want %v", got, want)
+	}
+}
+
+func TestSwarmMaxHops(t *testing.T) {
+	rt := newSwarmRuntime(
+		newHandoffAgent("general", "billing"),
+		newHandoffAgent("billing", "general"),
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "ping"}}
+
+	// Each hop changes the payload, so only the hop limit stops the ping-pong
+	_, err := NewSwarm("s", rt, []string{"general", "billing"}, WithMaxHops(3)).Execute(context.Background(), input)
+	if !errors.Is(err, ErrMaxHopsExceeded) {
+		t.Fatalf("Execute() error = %v, want ErrMaxHopsExceeded", err)
+	}
+	// The initial call plus three handoffs
+	calls := rt.agents["general"].(*handoffAgent).CallCount() + rt.agents["billing"].(*handoffAgent).CallCount()
+	if calls != 4 {
+		t.Errorf("agents called %d times, want 4", calls)
+	}
+}
+
+func TestSwarmHandoffCycle(t *testing.T) {
+	echoA := newHandoffAgent("triage", "billing")
+	echoB := newHandoffAgent("billing", "triage")
+	echoA.tag, echoB.tag = false, false
+	rt := newSwarmRuntime(echoA, echoB)
+
+	_, err := NewSwarm("s", rt, []string{"triage", "billing"}, WithMaxHops(100)).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "same"}})
+	if !errors.Is(err, ErrHandoffCycle) {
+		t.Fatalf("Execute() error = %v, want ErrHandoffCycle", err)
+	}
+}
+
+func TestSwarmInvalidHandoff(t *testing.T) {
+	rt := newSwarmRuntime(newHandoffAgent("general", "outsider"), newHandoffAgent("outsider", ""))
+
+	_, err := NewSwarm("s", rt, []string{"general"}).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "x"}})
+	if err == nil {
+		t.Fatal("expected error for handoff outside the swarm")
+	}
+
+	if _, err := NewSwarm("empty", rt, nil).Execute(context.Background(), &agent.Message{Message: &pb.Message{}}); err == nil {
+		t.Error("expected error for swarm without agents")
+	}
+}
+
+// cachedAgent returns the same reply message on every call, as an agent
+// serving responses from a cache would
+type cachedAgent struct {
+	*MockAgent
+	reply *agent.Message
+}
+
+func newCachedAgent(name, payload string) *cachedAgent {
+	return &cachedAgent{
+		MockAgent: NewMockAgent(name, "test", 0, payload),
+		reply:     &agent.Message{Message: &pb.Message{Payload: payload, Metadata: map[string]any{"source": "cache"}}},
+	}
+}
+
+func (c *cachedAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	_, _ = c.MockAgent.Execute(ctx, input)
+	return c.reply, nil
+}
+
+func TestSwarmLeavesAgentResultUntouched(t *testing.T) {
+	cached := newCachedAgent("faq", "see the FAQ")
+	rt := newSwarmRuntime(cached)
+
+	result, err := NewSwarm("s", rt, []string{"faq"}).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "help"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if got, want := result.Metadata[MetadataKeyHopPath], []string{"faq"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("hop_path = %v, want %v", got, want)
+	}
+	if _, ok := cached.reply.Metadata[MetadataKeyHopPath]; ok {
+		t.Error("Execute() annotated the agent's own reply, want a copy annotated")
+	}
+}
@@ -0,0 +1,185 @@
+package orchestration
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/observability"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// TransformFunc reshapes a message between pipeline stages
+type TransformFunc func(*agent.Message) (*agent.Message, error)
+
+// Transform is a pipeline stage that reshapes messages without calling an
+// LLM, e.g. extracting a field or wrapping a payload for the next agent.
+// It implements both Orchestrator and agent.Agent, so it can run on its own
+// or be registered with the runtime and named as a step of a Sequential or
+// any other pattern.
+type Transform struct {
+	name string
+	fn   TransformFunc
+}
+
+var _ Orchestrator = (*Transform)(nil)
+var _ agent.Agent = (*Transform)(nil)
+
+// NewTransform creates a Transform stage applying fn. fn receives a clone
+// of the input, which it may modify and return.
+func NewTransform(name string, fn TransformFunc) *Transform {
+	return &Transform{name: name, fn: fn}
+}
+
+// Name returns the stage identifier
+func (t *Transform) Name() string { return t.name }
+
+// Pattern returns the pattern type
+func (t *Transform) Pattern() string { return "transform" }
+
+// Role returns the agent role when the stage is registered with a runtime
+func (t *Transform) Role() string { return "transform" }
+
+// Start is a no-op; transforms only run synchronously
+func (t *Transform) Start(ctx context.Context) error { return nil }
+
+// Stop is a no-op
+func (t *Transform) Stop(ctx context.Context) error { return nil }
+
+// Ready returns true once a transformation function is set
+func (t *Transform) Ready() bool { return t.fn != nil }
+
+// Execute applies the transformation to input
+func (t *Transform) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	_, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("orchestration.transform.%s", t.name),
+		trace.WithAttributes(
+			attribute.String("orchestration.pattern", "transform"),
+		),
+	)
+	defer span.End()
+
+	if t.fn == nil {
+		err := fmt.Errorf("transform %s has no function", t.name)
+		span.RecordError(err)
+		return nil, err
+	}
+	if input == nil || input.Message == nil {
+		err := fmt.Errorf("transform %s: empty input message", t.name)
+		span.RecordError(err)
+		return nil, err
+	}
+
+	result, err := t.fn(input.Clone())
+	if err != nil {
+		span.RecordError(err)
+		return nil, fmt.Errorf("transform %s: %w", t.name, err)
+	}
+	if result == nil || result.Message == nil {
+		err := fmt.Errorf("transform %s returned no message", t.name)
+		span.RecordError(err)
+		return nil, err
+	}
+	return result, nil
+}
+
+// Common transformations
+
+// ExtractJSONPath replaces a JSON payload with the value at path, a
+// dot-separated list of object keys and array indexes (e.g.
+// "results.0.title"). String values become the raw payload; other values
+// stay JSON.
+func ExtractJSONPath(path string) TransformFunc {
+	segments := strings.Split(path, ".")
+	return func(msg *agent.Message) (*agent.Message, error) {
+		value, err := decodePayload(msg.Payload)
+		if err != nil {
+			return nil, err
+		}
+		for i, seg := range segments {
+			switch node := value.(type) {
+			case map[string]any:
+				v, ok := node[seg]
+				if !ok {
+					return nil, fmt.Errorf("path %q: key %q not found", path, strings.Join(segments[:i+1], "."))
+				}
+				value = v
+			case []any:
+				idx, err := strconv.Atoi(seg)
+				if err != nil || idx < 0 || idx >= len(node) {
+					return nil, fmt.Errorf("path %q: index %q out of range", path, strings.Join(segments[:i+1], "."))
+				}
+				value = node[idx]
+			default:
+				return nil, fmt.Errorf("path %q: %q is not an object or array", path, strings.Join(segments[:i], "."))
+			}
+		}
+		return withPayloadValue(msg, value)
+	}
+}
+
+// Wrap nests the payload under key in a JSON object. JSON payloads are
+// embedded as-is; anything else becomes a string value.
+func Wrap(key string) TransformFunc {
+	return func(msg *agent.Message) (*agent.Message, error) {
+		var value any = msg.Payload
+		if trimmed := bytes.TrimSpace([]byte(msg.Payload)); json.Valid(trimmed) {
+			value = json.RawMessage(trimmed)
+		}
+		data, err := json.Marshal(map[string]any{key: value})
+		if err != nil {
+			return nil, fmt.Errorf("failed to wrap payload: %w", err)
+		}
+		msg.Payload = string(data)
+		return msg, nil
+	}
+}
+
+// Unwrap replaces a JSON object payload with its value at key, reversing
+// Wrap. Unlike ExtractJSONPath, key is matched literally.
+func Unwrap(key string) TransformFunc {
+	return func(msg *agent.Message) (*agent.Message, error) {
+		value, err := decodePayload(msg.Payload)
+		if err != nil {
+			return nil, err
+		}
+		obj, ok := value.(map[string]any)
+		if !ok {
+			return nil, fmt.Errorf("payload is not a JSON object")
+		}
+		v, ok := obj[key]
+		if !ok {
+			return nil, fmt.Errorf("key %q not found", key)
+		}
+		return withPayloadValue(msg, v)
+	}
+}
+
+// decodePayload decodes a JSON payload, keeping numbers exact
+func decodePayload(payload string) (any, error) {
+	dec := json.NewDecoder(strings.NewReader(payload))
+	dec.UseNumber()
+	var value any
+	if err := dec.Decode(&value); err != nil {
+		return nil, fmt.Errorf("payload is not valid JSON: %w", err)
+	}
+	return value, nil
+}
+
+// withPayloadValue sets value as msg's payload, unquoting strings
+func withPayloadValue(msg *agent.Message, value any) (*agent.Message, error) {
+	if s, ok := value.(string); ok {
+		msg.Payload = s
+		return msg, nil
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal extracted value: %w", err)
+	}
+	msg.Payload = string(data)
+	return msg, nil
+}
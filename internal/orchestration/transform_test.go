@@ -0,0 +1,108 @@
+package orchestration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+func TestBuiltinTransforms(t *testing.T) {
+	payload := `{"order":{"id":"A-1","items":[{"sku":"kb-01","qty":2}],"total":12.50},"note":"rush"}`
+
+	tests := []struct {
+		name    string
+		fn      TransformFunc
+		payload string
+		want    string
+		wantErr string
+	}{
+		{"extract string", ExtractJSONPath("order.id"), payload, "A-1", ""},
+		{"extract array element", ExtractJSONPath("order.items.0"), payload, `{"qty":2,"sku":"kb-01"}`, ""},
+		{"extract number keeps precision", ExtractJSONPath("order.total"), payload, "12.50", ""},
+		{"extract missing key", ExtractJSONPath("order.customer"), payload, "", `key "order.customer" not found`},
+		{"extract index out of range", ExtractJSONPath("order.items.3"), payload, "", "out of range"},
+		{"extract through scalar", ExtractJSONPath("note.text"), payload, "", "not an object or array"},
+		{"extract from non-JSON", ExtractJSONPath("a"), "plain text", "", "not valid JSON"},
+		{"wrap JSON", Wrap("data"), `{"a": 1}`, `{"data":{"a":1}}`, ""},
+		{"wrap text", Wrap("text"), `hello "world"`, `{"text":"hello \"world\""}`, ""},
+		{"unwrap", Unwrap("order.id"), `{"order.id":"literal"}`, "literal", ""},
+		{"unwrap non-object", Unwrap("a"), `[1,2]`, "", "not a JSON object"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			msg := &agent.Message{Message: &pb.Message{Payload: tt.payload}}
+			got, err := tt.fn(msg)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got.Payload != tt.want {
+				t.Errorf("payload = %s, want %s", got.Payload, tt.want)
+			}
+		})
+	}
+}
+
+func TestTransformInSequential(t *testing.T) {
+	ctx := context.Background()
+	rt := NewMockRuntime()
+
+	// The extractor replies with JSON; the transform pulls out the field the echo agent needs
+	_ = rt.Register(NewMockAgent("extractor", "test", 0, `{"summary":{"title":"Quarterly report"}}`))
+	_ = rt.Register(NewTransform("pick-title", ExtractJSONPath("summary.title")))
+	_ = rt.Register(NewTransform("wrap-title", Wrap("title")))
+
+	seq := NewSequential("pipeline", rt, []string{"extractor", "pick-title", "wrap-title"})
+	result, err := seq.Execute(ctx, &agent.Message{Message: &pb.Message{Payload: "doc"}})
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != `{"title":"Quarterly report"}` {
+		t.Errorf("Execute() payload = %s", result.Payload)
+	}
+}
+
+func TestTransformExecute(t *testing.T) {
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: `{"a":"b"}`, Metadata: map[string]any{"tenant": "acme"}}}
+
+	// Functions get a clone, so mutating it leaves the caller's input intact
+	mutate := NewTransform("mutate", func(msg *agent.Message) (*agent.Message, error) {
+		msg.Metadata["tenant"] = "changed"
+		return Unwrap("a")(msg)
+	})
+	if mutate.Pattern() != "transform" || !mutate.Ready() {
+		t.Errorf("unexpected Pattern() %q or Ready() %v", mutate.Pattern(), mutate.Ready())
+	}
+	result, err := mutate.Execute(ctx, input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "b" || input.Metadata["tenant"] != "acme" {
+		t.Errorf("result %q, input tenant %v", result.Payload, input.Metadata["tenant"])
+	}
+
+	boom := errors.New("boom")
+	failing := NewTransform("failing", func(*agent.Message) (*agent.Message, error) { return nil, boom })
+	if _, err := failing.Execute(ctx, input); !errors.Is(err, boom) {
+		t.Errorf("Execute() error = %v, want boom", err)
+	}
+
+	empty := NewTransform("empty", func(*agent.Message) (*agent.Message, error) { return nil, nil })
+	if _, err := empty.Execute(ctx, input); err == nil {
+		t.Error("expected error when the function returns no message")
+	}
+	if NewTransform("unset", nil).Ready() {
+		t.Error("Ready() = true for transform without function")
+	}
+}
@@ -0,0 +1,182 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Metadata keys set on a verified RAG answer
+const (
+	// MetadataKeyGroundedness holds the verifier's groundedness score (0-1)
+	MetadataKeyGroundedness = "groundedness"
+
+	// MetadataKeyUnsupportedClaims holds the claims the verifier could not
+	// find support for in the retrieved context
+	MetadataKeyUnsupportedClaims = "unsupported_claims"
+
+	// MetadataKeyLowConfidence is true when no answer reached the minimum
+	// groundedness, or an Ensemble returned a best-effort answer below its
+	// agreement threshold
+	MetadataKeyLowConfidence = "low_confidence"
+
+	// MetadataKeyVerificationWarning explains a low-confidence answer
+	MetadataKeyVerificationWarning = "verification_warning"
+)
+
+// GroundednessVerdict is a verifier agent's assessment of an answer against
+// the retrieved context. Verifiers reply with it as a JSON payload; a
+// "score" field is accepted in place of "groundedness", and scores on a
+// 0-10 scale are normalized.
+type GroundednessVerdict struct {
+	Groundedness      float64  `json:"groundedness"`
+	UnsupportedClaims []string `json:"unsupported_claims,omitempty"`
+}
+
+// verificationRequest is the payload sent to the verifier agent
+type verificationRequest struct {
+	Query   string `json:"query"`
+	Context string `json:"context"`
+	Answer  string `json:"answer"`
+}
+
+// WithVerifier checks each generated answer against the retrieved context
+// with the named verifier agent
+func WithVerifier(verifier string) RAGOption {
+	return func(r *RAG) {
+		r.verifier = verifier
+	}
+}
+
+// WithMinGroundedness sets the groundedness score (0-1) an answer needs to
+// pass verification (default 0.7)
+func WithMinGroundedness(min float64) RAGOption {
+	return func(r *RAG) {
+		r.minGroundedness = min
+	}
+}
+
+// WithVerificationRetries sets how many times generation is retried, with
+// the unsupported claims as feedback, when an answer fails verification
+// (default 1). Once retries are exhausted the best answer is returned
+// flagged as low confidence.
+func WithVerificationRetries(n int) RAGOption {
+	return func(r *RAG) {
+		r.verifyRetries = n
+	}
+}
+
+// verify checks answer with the verifier agent, regenerating from
+// augmentedInput while it falls short of the minimum groundedness
+func (r *RAG) verify(ctx context.Context, span trace.Span, query, documents, augmentedInput, answer *agent.Message) (*agent.Message, error) {
+	best, bestVerdict := answer, (*GroundednessVerdict)(nil)
+
+	for attempt := 0; ; attempt++ {
+		verdict, err := r.callVerifier(ctx, query, documents, answer)
+		if err != nil {
+			return nil, fmt.Errorf("verification failed: %w", err)
+		}
+		span.SetAttributes(attribute.Float64(fmt.Sprintf("verification.%d.groundedness", attempt), verdict.Groundedness))
+
+		if bestVerdict == nil || verdict.Groundedness > bestVerdict.Groundedness {
+			best, bestVerdict = answer, verdict
+		}
+		if verdict.Groundedness >= r.minGroundedness {
+			span.SetAttributes(attribute.Float64("orchestration.groundedness", verdict.Groundedness))
+			return annotateVerdict(answer, verdict, ""), nil
+		}
+		if attempt >= r.verifyRetries {
+			break
+		}
+
+		answer, err = r.runtime.Call(ctx, r.generator, withVerificationFeedback(augmentedInput, verdict))
+		if err != nil {
+			return nil, fmt.Errorf("generation failed: %w", err)
+		}
+		answer = r.parseAnswer(answer, documents)
+	}
+
+	span.SetAttributes(
+		attribute.Float64("orchestration.groundedness", bestVerdict.Groundedness),
+		attribute.Bool("orchestration.low_confidence", true),
+	)
+	warning := fmt.Sprintf("answer groundedness %.2f is below the minimum %.2f", bestVerdict.Groundedness, r.minGroundedness)
+	return annotateVerdict(best, bestVerdict, warning), nil
+}
+
+// callVerifier asks the verifier agent to score answer against documents
+func (r *RAG) callVerifier(ctx context.Context, query, documents, answer *agent.Message) (*GroundednessVerdict, error) {
+	req := verificationRequest{Query: payloadOf(query), Context: payloadOf(documents), Answer: payloadOf(answer)}
+	payload, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal verification request: %w", err)
+	}
+
+	result, err := r.runtime.Call(ctx, r.verifier, &agent.Message{
+		Message: &pb.Message{Type: "rag_verification", Payload: string(payload)},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return parseVerdict(payloadOf(result))
+}
+
+// parseVerdict decodes a verifier's JSON reply
+func parseVerdict(payload string) (*GroundednessVerdict, error) {
+	var raw struct {
+		GroundednessVerdict
+		Score *float64 `json:"score"`
+	}
+	if err := json.Unmarshal([]byte(payload), &raw); err != nil {
+		return nil, fmt.Errorf("invalid verifier verdict %q: %w", payload, err)
+	}
+	verdict := raw.GroundednessVerdict
+	if verdict.Groundedness == 0 && raw.Score != nil {
+		verdict.Groundedness = *raw.Score
+	}
+	verdict.Groundedness = normalizeScore(verdict.Groundedness)
+	return &verdict, nil
+}
+
+// withVerificationFeedback asks the generator to answer again without the
+// unsupported claims
+func withVerificationFeedback(augmentedInput *agent.Message, verdict *GroundednessVerdict) *agent.Message {
+	msg := augmentedInput.Clone()
+	if msg == nil || msg.Message == nil {
+		return msg
+	}
+
+	var b strings.Builder
+	b.WriteString(msg.Payload)
+	b.WriteString("\n\nYour previous answer was not supported by the context.")
+	if len(verdict.UnsupportedClaims) > 0 {
+		b.WriteString(" Unsupported claims:\n")
+		for _, claim := range verdict.UnsupportedClaims {
+			b.WriteString("- " + claim + "\n")
+		}
+	}
+	b.WriteString("\nAnswer again using only the context.")
+	msg.Payload = b.String()
+	return msg
+}
+
+// annotateVerdict returns a copy of answer recording verdict, and warning if
+// set, in its metadata
+func annotateVerdict(answer *agent.Message, verdict *GroundednessVerdict, warning string) *agent.Message {
+	answer = annotatable(answer)
+	answer.Metadata[MetadataKeyGroundedness] = verdict.Groundedness
+	if len(verdict.UnsupportedClaims) > 0 {
+		answer.Metadata[MetadataKeyUnsupportedClaims] = verdict.UnsupportedClaims
+	}
+	if warning != "" {
+		answer.Metadata[MetadataKeyLowConfidence] = true
+		answer.Metadata[MetadataKeyVerificationWarning] = warning
+	}
+	return answer
+}
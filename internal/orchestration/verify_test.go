@@ -0,0 +1,135 @@
+package orchestration
+
+import (
+	"context"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+func newVerifiedRAGRuntime(answers, verdicts []string) (*MockRuntime, *scriptedAgent, *scriptedAgent) {
+	rt := NewMockRuntime()
+	generator := newScriptedAgent("generator", answers...)
+	verifier := newScriptedAgent("verifier", verdicts...)
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Aixgo supports NATS and gRPC transports."))
+	_ = rt.Register(generator)
+	_ = rt.Register(verifier)
+	return rt, generator, verifier
+}
+
+func TestRAGVerifierPasses(t *testing.T) {
+	rt, generator, verifier := newVerifiedRAGRuntime(
+		[]string{"Aixgo supports NATS and gRPC."},
+		[]string{`{"groundedness": 0.95}`},
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "Which transports?"}}
+
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithVerifier("verifier")).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyGroundedness] != 0.95 || result.Metadata[MetadataKeyLowConfidence] != nil {
+		t.Errorf("unexpected metadata %v", result.Metadata)
+	}
+	if len(generator.inputs) != 1 {
+		t.Errorf("generator called %d times, want 1", len(generator.inputs))
+	}
+
+	var req verificationRequest
+	if err := json.Unmarshal([]byte(verifier.inputs[0]), &req); err != nil {
+		t.Fatalf("verifier input is not JSON: %v", err)
+	}
+	want := verificationRequest{Query: "Which transports?", Context: "Aixgo supports NATS and gRPC transports.", Answer: "Aixgo supports NATS and gRPC."}
+	if req != want {
+		t.Errorf("verifier input = %+v, want %+v", req, want)
+	}
+}
+
+func TestRAGVerifierRetriesGeneration(t *testing.T) {
+	rt, generator, _ := newVerifiedRAGRuntime(
+		[]string{"Aixgo supports NATS, gRPC and Kafka.", "Aixgo supports NATS and gRPC."},
+		[]string{`{"groundedness": 0.4, "unsupported_claims": ["supports Kafka"]}`, `{"score": 9}`},
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "Which transports?"}}
+
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithVerifier("verifier"), WithMinGroundedness(0.8)).
+		Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Payload != "Aixgo supports NATS and gRPC." {
+		t.Errorf("Execute() payload = %q, want the regenerated answer", result.Payload)
+	}
+	if result.Metadata[MetadataKeyGroundedness] != 0.9 {
+		t.Errorf("groundedness = %v, want 0.9", result.Metadata[MetadataKeyGroundedness])
+	}
+	if len(generator.inputs) != 2 || !strings.Contains(generator.inputs[1], "- supports Kafka") {
+		t.Errorf("regeneration should include the unsupported claims, got %q", generator.inputs)
+	}
+}
+
+func TestRAGVerifierLowConfidence(t *testing.T) {
+	rt, generator, _ := newVerifiedRAGRuntime(
+		[]string{"first", "second", "third"},
+		[]string{
+			`{"groundedness": 0.5, "unsupported_claims": ["a"]}`,
+			`{"groundedness": 0.3, "unsupported_claims": ["b"]}`,
+			`{"groundedness": 0.2}`,
+		},
+	)
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithVerifier("verifier"), WithVerificationRetries(2)).
+		Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if len(generator.inputs) != 3 {
+		t.Errorf("generator called %d times, want 3", len(generator.inputs))
+	}
+
+	// The best-scoring answer is returned with a warning
+	if result.Payload != "first" {
+		t.Errorf("Execute() payload = %q, want the best answer", result.Payload)
+	}
+	if result.Metadata[MetadataKeyLowConfidence] != true || result.Metadata[MetadataKeyVerificationWarning] == "" {
+		t.Errorf("expected low-confidence warning, got %v", result.Metadata)
+	}
+	if got := result.Metadata[MetadataKeyUnsupportedClaims]; !reflect.DeepEqual(got, []string{"a"}) {
+		t.Errorf("unsupported_claims = %v, want [a]", got)
+	}
+}
+
+func TestRAGVerifierInvalidVerdict(t *testing.T) {
+	rt, _, _ := newVerifiedRAGRuntime([]string{"answer"}, []string{"looks fine to me"})
+
+	_, err := NewRAG("rag", rt, "retriever", "generator", WithVerifier("verifier")).
+		Execute(context.Background(), &agent.Message{Message: &pb.Message{Payload: "q"}})
+	if err == nil || !strings.Contains(err.Error(), "verification failed") {
+		t.Errorf("Execute() error = %v, want verification failure", err)
+	}
+}
+
+func TestRAGVerifierLeavesAnswerUntouched(t *testing.T) {
+	rt := NewMockRuntime()
+	generator := newCachedAgent("generator", "Aixgo supports NATS and gRPC.")
+	_ = rt.Register(NewMockAgent("retriever", "test", 0, "Aixgo supports NATS and gRPC transports."))
+	_ = rt.Register(generator)
+	_ = rt.Register(newScriptedAgent("verifier", `{"groundedness": 0.95}`))
+	input := &agent.Message{Message: &pb.Message{Payload: "Which transports?"}}
+
+	result, err := NewRAG("rag", rt, "retriever", "generator", WithVerifier("verifier")).Execute(context.Background(), input)
+	if err != nil {
+		t.Fatalf("Execute() error = %v", err)
+	}
+	if result.Metadata[MetadataKeyGroundedness] != 0.95 {
+		t.Errorf("groundedness = %v, want 0.95", result.Metadata[MetadataKeyGroundedness])
+	}
+	if _, ok := generator.reply.Metadata[MetadataKeyGroundedness]; ok {
+		t.Error("Execute() annotated the generator's own reply, want a copy annotated")
+	}
+}
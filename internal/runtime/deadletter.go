@@ -0,0 +1,75 @@
+package runtime
+
+import (
+	"log"
+	"sync/atomic"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// Metadata keys set on messages delivered to the dead-letter channel
+const (
+	MetadataKeyDeadLetterTarget = "dead_letter_target"
+	MetadataKeyDeadLetterReason = "dead_letter_reason"
+)
+
+// DefaultDeadLetterBufferSize is the dead-letter buffer used when none is set
+const DefaultDeadLetterBufferSize = 100
+
+// DeadLetterQueue collects messages that Send could not deliver. It never
+// blocks the sender: when the buffer is full, new dead letters are dropped
+// and counted.
+type DeadLetterQueue struct {
+	ch      chan *agent.Message
+	dropped uint64 // Atomic counter of dead letters lost to a full buffer
+}
+
+// NewDeadLetterQueue creates a queue buffering size dead letters
+// (<= 0 uses DefaultDeadLetterBufferSize)
+func NewDeadLetterQueue(size int) *DeadLetterQueue {
+	if size <= 0 {
+		size = DefaultDeadLetterBufferSize
+	}
+	return &DeadLetterQueue{ch: make(chan *agent.Message, size)}
+}
+
+// Add queues a copy of msg tagged with the intended target and failure reason
+func (q *DeadLetterQueue) Add(target string, msg *agent.Message, reason error) {
+	dl := &agent.Message{Message: &pb.Message{}}
+	if msg != nil && msg.Message != nil {
+		dl = msg.Clone()
+	}
+	if dl.Metadata == nil {
+		dl.Metadata = make(map[string]any)
+	}
+	dl.Metadata[MetadataKeyDeadLetterTarget] = target
+	dl.Metadata[MetadataKeyDeadLetterReason] = reason.Error()
+
+	select {
+	case q.ch <- dl:
+	default:
+		if atomic.AddUint64(&q.dropped, 1) == 1 {
+			log.Printf("WARNING: dead-letter buffer full (%d messages), dropping undeliverable messages", cap(q.ch))
+		}
+	}
+}
+
+// Messages returns the channel dead letters are delivered on
+func (q *DeadLetterQueue) Messages() <-chan *agent.Message {
+	return q.ch
+}
+
+// DeadLetters returns a channel receiving copies of messages Send could not
+// deliver, tagged with MetadataKeyDeadLetterTarget and
+// MetadataKeyDeadLetterReason
+func (r *LocalRuntime) DeadLetters() <-chan *agent.Message {
+	return r.deadLetters.ch
+}
+
+// DeadLetters returns a channel receiving copies of messages Send could not
+// deliver, tagged with MetadataKeyDeadLetterTarget and
+// MetadataKeyDeadLetterReason
+func (r *DistributedRuntime) DeadLetters() <-chan *agent.Message {
+	return r.deadLetters.ch
+}
@@ -2,15 +2,9 @@ package runtime
 
 import (
 	"context"
-	"crypto/tls"
-	"crypto/x509"
 	"errors"
 	"fmt"
 	"log"
-	"net"
-	"os"
-	"regexp"
-	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -19,37 +13,36 @@ import (
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/internal/graph"
 	"github.com/aixgo-dev/aixgo/internal/observability"
-	"github.com/aixgo-dev/aixgo/pkg/security"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
 	"github.com/aixgo-dev/aixgo/pkg/session"
 	pb "github.com/aixgo-dev/aixgo/proto"
 	"go.opentelemetry.io/otel/attribute"
 	"go.opentelemetry.io/otel/trace"
 	"golang.org/x/sync/errgroup"
-	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/credentials"
-	"google.golang.org/grpc/credentials/insecure"
-	"google.golang.org/grpc/status"
 )
 
-// DistributedRuntime provides distributed agent execution using gRPC.
-// Agents can run in separate processes or on different machines.
+// DistributedRuntime provides distributed agent execution over a pluggable
+// Transport (gRPC by default, or NATS). Agents can run in separate processes
+// or on different machines.
 type DistributedRuntime struct {
 	localAgents    map[string]agent.Agent         // Agents running in this process
-	remoteAgents   map[string]*remoteAgentClient  // Remote agent connections
+	remoteAgents   map[string]string              // Remote agent name to address
+	resolved       map[string]bool                // Remote agents found through the registry
 	channels       map[string]chan *agent.Message // Local message channels
 	config         *RuntimeConfig
 	tlsConfig      *TLSConfig      // TLS configuration for secure connections
+	transport      Transport       // Carries calls to and from remote nodes
 	sessionManager session.Manager // Session manager for persistence
 	mu             sync.RWMutex
 	started        bool
 	ctx            context.Context
 	cancel         context.CancelFunc
-	server         *grpc.Server
-	listener       net.Listener  // gRPC listener
 	listenAddr     string
-	semaphore      chan struct{} // For limiting concurrent calls
-	messagesSent   uint64        // Atomic counter for metrics
+	advertiseAddr  string          // Address published to the registry
+	registry       ServiceRegistry // Resolves remote agents by name
+	semaphore      chan struct{}   // For limiting concurrent calls
+	messagesSent   uint64          // Atomic counter for metrics
+	deadLetters    *DeadLetterQueue
 }
 
 // TLSConfig holds TLS configuration for gRPC connections.
@@ -73,14 +66,6 @@ type TLSConfig struct {
 	ExternalTLS bool
 }
 
-// remoteAgentClient represents a connection to a remote agent
-type remoteAgentClient struct {
-	name   string
-	addr   string
-	conn   *grpc.ClientConn
-	client pb.AgentServiceClient
-}
-
 // DistributedRuntimeConfig extends RuntimeConfig with distributed-specific options
 type DistributedRuntimeConfig struct {
 	*RuntimeConfig
@@ -107,7 +92,7 @@ func WithDistributedSessionManager(sm session.Manager) DistributedOption {
 
 // NewDistributedRuntime creates a new DistributedRuntime.
 // The listenAddr is the address to listen for incoming gRPC connections (e.g., ":50051").
-// Use DistributedOption to configure TLS and session management.
+// Use DistributedOption to configure TLS, the transport, and session management.
 func NewDistributedRuntime(listenAddr string, opts ...any) *DistributedRuntime {
 	cfg := DefaultConfig()
 
@@ -118,7 +103,8 @@ func NewDistributedRuntime(listenAddr string, opts ...any) *DistributedRuntime {
 
 	r := &DistributedRuntime{
 		localAgents:  make(map[string]agent.Agent),
-		remoteAgents: make(map[string]*remoteAgentClient),
+		remoteAgents: make(map[string]string),
+		resolved:     make(map[string]bool),
 		channels:     make(map[string]chan *agent.Message),
 		config:       cfg,
 		listenAddr:   listenAddr,
@@ -135,30 +121,57 @@ func NewDistributedRuntime(listenAddr string, opts ...any) *DistributedRuntime {
 		}
 	}
 
+	r.deadLetters = NewDeadLetterQueue(r.config.DeadLetterBufferSize)
+
+	if r.transport == nil {
+		r.transport = NewGRPCTransport(listenAddr, r.tlsConfig)
+	}
+
 	return r
 }
 
-// Register registers a local agent with the runtime
+// Register registers a local agent with the runtime. Once the runtime has
+// started, the agent is also published to the service registry; if that
+// fails, the agent is not registered.
 func (r *DistributedRuntime) Register(a agent.Agent) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	name := a.Name()
 	if _, exists := r.localAgents[name]; exists {
+		r.mu.Unlock()
 		return fmt.Errorf("%w: %s", ErrAgentAlreadyRegistered, name)
 	}
 	if _, exists := r.remoteAgents[name]; exists {
+		r.mu.Unlock()
 		return fmt.Errorf("%w: %s (registered as remote)", ErrAgentAlreadyRegistered, name)
 	}
 
+	ch := make(chan *agent.Message, r.config.ChannelBufferSize)
 	r.localAgents[name] = a
-	r.channels[name] = make(chan *agent.Message, r.config.ChannelBufferSize)
+	r.channels[name] = ch
+	started, ctx := r.started, r.ctx
+	r.mu.Unlock()
 
+	if !started {
+		return nil
+	}
+	if err := r.publish(ctx, name); err != nil {
+		r.mu.Lock()
+		// Unless it was unregistered meanwhile
+		if r.channels[name] == ch {
+			close(ch)
+			delete(r.channels, name)
+			delete(r.localAgents, name)
+		}
+		r.mu.Unlock()
+		return err
+	}
 	return nil
 }
 
-// Connect establishes a connection to a remote agent.
-// Uses TLS if configured, otherwise falls back to insecure connection.
+// Connect registers a remote agent reachable over the transport. addr is
+// the agent's node address for transports that implement Dialer, such as
+// gRPC; transports that route by name ignore it.
 func (r *DistributedRuntime) Connect(name, addr string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -170,130 +183,41 @@ func (r *DistributedRuntime) Connect(name, addr string) error {
 		return fmt.Errorf("%w: %s", ErrAgentAlreadyRegistered, name)
 	}
 
-	// Build dial options
-	dialOpts, err := r.buildDialOptions()
-	if err != nil {
-		return fmt.Errorf("failed to build dial options: %w", err)
-	}
-
-	// Create gRPC connection
-	conn, err := grpc.NewClient(addr, dialOpts...)
-	if err != nil {
-		return fmt.Errorf("failed to connect to remote agent %s at %s: %w", name, addr, err)
-	}
-
-	r.remoteAgents[name] = &remoteAgentClient{
-		name:   name,
-		addr:   addr,
-		conn:   conn,
-		client: pb.NewAgentServiceClient(conn),
-	}
-
-	return nil
-}
-
-// buildDialOptions creates gRPC dial options based on TLS configuration.
-func (r *DistributedRuntime) buildDialOptions() ([]grpc.DialOption, error) {
-	var opts []grpc.DialOption
-
-	// ExternalTLS means TLS is handled by service mesh (Istio, Linkerd, etc.)
-	// Use plaintext transport since the sidecar handles encryption
-	if r.tlsConfig != nil && r.tlsConfig.ExternalTLS {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
-		return opts, nil
-	}
-
-	if r.tlsConfig != nil && r.tlsConfig.Enabled {
-		// SECURITY: Prevent InsecureSkipVerify in production environments
-		// This enforces certificate verification when running in production
-		// Empty/unset ENVIRONMENT is treated as production (fail-safe)
-		if r.tlsConfig.InsecureSkipVerify {
-			env := strings.ToLower(os.Getenv("ENVIRONMENT"))
-			// Only allow InsecureSkipVerify in explicit non-production environments
-			allowedNonProdEnvs := map[string]bool{
-				"development": true,
-				"dev":         true,
-				"staging":     true,
-				"local":       true,
-				"test":        true,
-			}
-			if !allowedNonProdEnvs[env] {
-				return nil, fmt.Errorf("SECURITY: InsecureSkipVerify cannot be enabled in production environment (ENVIRONMENT=%q). "+
-					"Set ENVIRONMENT to 'development', 'dev', 'staging', 'local', or 'test' to allow insecure TLS", env)
-			}
-
-			// Log warning for non-production environments.
-			// #nosec G706 -- env is sanitised via security.SanitizeLogField before formatting.
-			log.Printf("[DistributedRuntime] WARNING: TLS certificate verification is disabled (InsecureSkipVerify=true). "+
-				"This is a security risk and should NEVER be used in production. "+
-				"Connections are vulnerable to man-in-the-middle attacks. "+
-				"Current ENVIRONMENT=%s", security.SanitizeLogField(env))
+	if d, ok := r.transport.(Dialer); ok {
+		if err := d.Connect(name, addr); err != nil {
+			return fmt.Errorf("failed to connect to remote agent %s at %s: %w", name, addr, err)
 		}
-
-		tlsCfg := &tls.Config{
-			MinVersion:         tls.VersionTLS12,
-			InsecureSkipVerify: r.tlsConfig.InsecureSkipVerify, // #nosec G402 -- intentionally configurable for dev/test; blocked in production by env check above
-		}
-
-		// Set server name for SNI
-		if r.tlsConfig.ServerName != "" {
-			tlsCfg.ServerName = r.tlsConfig.ServerName
-		}
-
-		// Load CA certificate if provided
-		if r.tlsConfig.CAFile != "" {
-			caPath, err := security.ResolveTLSCertPath(r.tlsConfig.CAFile)
-			if err != nil {
-				return nil, fmt.Errorf("invalid CA file path: %w", err)
-			}
-			caData, err := os.ReadFile(caPath) // #nosec G304 -- path confined to AIXGO_TLS_CERT_DIR allowlist by security.ResolveTLSCertPath
-			if err != nil {
-				return nil, fmt.Errorf("failed to read CA file: %w", err)
-			}
-			caPool := x509.NewCertPool()
-			if !caPool.AppendCertsFromPEM(caData) {
-				return nil, fmt.Errorf("failed to parse CA certificate")
-			}
-			tlsCfg.RootCAs = caPool
-		}
-
-		// Load client certificate for mTLS if provided
-		if r.tlsConfig.CertFile != "" && r.tlsConfig.KeyFile != "" {
-			cert, err := tls.LoadX509KeyPair(r.tlsConfig.CertFile, r.tlsConfig.KeyFile)
-			if err != nil {
-				return nil, fmt.Errorf("failed to load client certificate: %w", err)
-			}
-			tlsCfg.Certificates = []tls.Certificate{cert}
-		}
-
-		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
-	} else {
-		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
 	}
 
-	return opts, nil
+	r.remoteAgents[name] = addr
+	return nil
 }
 
 // Unregister removes an agent from the runtime
 func (r *DistributedRuntime) Unregister(name string) error {
 	r.mu.Lock()
-	defer r.mu.Unlock()
 
 	// Check local agents
 	if _, exists := r.localAgents[name]; exists {
 		close(r.channels[name])
 		delete(r.channels, name)
 		delete(r.localAgents, name)
+		r.mu.Unlock()
+
+		r.withdraw(context.Background(), name)
 		return nil
 	}
 
 	// Check remote agents
-	if remote, exists := r.remoteAgents[name]; exists {
-		_ = remote.conn.Close()
+	if _, exists := r.remoteAgents[name]; exists {
+		r.disconnect(name)
 		delete(r.remoteAgents, name)
+		delete(r.resolved, name)
+		r.mu.Unlock()
 		return nil
 	}
 
+	r.mu.Unlock()
 	return fmt.Errorf("%w: %s", ErrAgentNotFound, name)
 }
 
@@ -326,8 +250,17 @@ func (r *DistributedRuntime) List() []string {
 	return names
 }
 
-// Send sends a message to a target agent asynchronously
+// Send sends a message to a target agent asynchronously. Messages that
+// cannot be delivered are also copied to DeadLetters.
 func (r *DistributedRuntime) Send(target string, msg *agent.Message) error {
+	err := r.send(target, msg)
+	if err != nil {
+		r.deadLetters.Add(target, msg, err)
+	}
+	return err
+}
+
+func (r *DistributedRuntime) send(target string, msg *agent.Message) error {
 	r.mu.RLock()
 
 	// Check local agents
@@ -357,15 +290,9 @@ func (r *DistributedRuntime) Send(target string, msg *agent.Message) error {
 		}
 	}
 
-	// Check remote agents
-	remote, exists := r.remoteAgents[target]
 	r.mu.RUnlock()
 
-	if !exists {
-		return fmt.Errorf("%w: %s", ErrAgentNotFound, target)
-	}
-
-	// Send to remote agent via gRPC
+	// Send to remote agent over the transport
 	timeout := r.config.SendTimeout
 	if timeout == 0 {
 		timeout = 5 * time.Second
@@ -373,12 +300,16 @@ func (r *DistributedRuntime) Send(target string, msg *agent.Message) error {
 	ctx, cancel := context.WithTimeout(context.Background(), timeout)
 	defer cancel()
 
-	_, err := remote.client.Send(ctx, &pb.SendRequest{
-		Message: msg.Message,
-	})
+	addr, err := r.remoteAddr(ctx, target)
+	if err != nil {
+		return err
+	}
+	err = r.transport.Send(ctx, target, msg)
 
 	if err == nil {
 		atomic.AddUint64(&r.messagesSent, 1)
+	} else {
+		r.forgetResolved(target, addr)
 	}
 
 	return err
@@ -386,7 +317,7 @@ func (r *DistributedRuntime) Send(target string, msg *agent.Message) error {
 
 // Recv returns a channel to receive messages from a source agent.
 // For local agents, returns the local channel directly.
-// For remote agents, establishes a gRPC streaming connection.
+// For remote agents, subscribes to the agent over the transport.
 func (r *DistributedRuntime) Recv(source string) (<-chan *agent.Message, error) {
 	r.mu.RLock()
 
@@ -396,50 +327,22 @@ func (r *DistributedRuntime) Recv(source string) (<-chan *agent.Message, error)
 		return ch, nil
 	}
 
-	// Check remote agents
-	remote, exists := r.remoteAgents[source]
+	ctx := r.ctx
 	r.mu.RUnlock()
 
-	if !exists {
-		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, source)
+	// Check remote agents
+	lookupCtx := ctx
+	if lookupCtx == nil {
+		lookupCtx = context.Background()
 	}
-
-	// Create streaming connection to remote agent
-	return r.remoteRecv(remote, source)
-}
-
-// remoteRecv establishes a streaming connection to a remote agent.
-func (r *DistributedRuntime) remoteRecv(remote *remoteAgentClient, source string) (<-chan *agent.Message, error) {
-	if r.ctx == nil {
-		return nil, errors.New("runtime not started: context is nil")
+	if _, err := r.remoteAddr(lookupCtx, source); err != nil {
+		return nil, err
 	}
-
-	stream, err := remote.client.Listen(r.ctx, &pb.ListenRequest{AgentName: source})
-	if err != nil {
-		return nil, fmt.Errorf("failed to connect to remote agent stream: %w", err)
+	if ctx == nil {
+		return nil, errors.New("runtime not started: context is nil")
 	}
 
-	ch := make(chan *agent.Message, r.config.ChannelBufferSize)
-
-	go func() {
-		defer close(ch)
-		for {
-			resp, err := stream.Recv()
-			if err != nil {
-				// Stream closed or error
-				log.Printf("[DistributedRuntime] Stream from %s closed: %v", source, err)
-				return
-			}
-
-			select {
-			case ch <- &agent.Message{Message: resp.Message}:
-			case <-r.ctx.Done():
-				return
-			}
-		}
-	}()
-
-	return ch, nil
+	return r.transport.Subscribe(ctx, source)
 }
 
 // Call invokes an agent synchronously and waits for response
@@ -448,6 +351,9 @@ func (r *DistributedRuntime) Call(ctx context.Context, target string, input *age
 		return nil, ErrRuntimeNotStarted
 	}
 
+	// Correlate everything this call triggers under one request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	// Acquire semaphore if concurrency limiting is enabled
 	if r.semaphore != nil {
 		select {
@@ -486,33 +392,32 @@ func (r *DistributedRuntime) Call(ctx context.Context, target string, input *age
 				attribute.Int64("execution.duration_ms", duration.Milliseconds()),
 				attribute.Bool("execution.success", err == nil),
 			)
+			recordAgentCall(target, duration, result, err)
 		}
 
 		return result, err
 	}
 
-	// Try remote agent
-	remote, exists := r.remoteAgents[target]
 	r.mu.RUnlock()
 
-	if !exists {
-		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, target)
+	// Try remote agent
+	addr, err := r.remoteAddr(ctx, target)
+	if err != nil {
+		return nil, err
 	}
 
-	// Call remote agent via gRPC
+	// Call remote agent over the transport
 	ctx, span := observability.StartSpanWithOtel(ctx, fmt.Sprintf("runtime.call.%s", target),
 		trace.WithAttributes(
 			attribute.String("agent.name", target),
 			attribute.String("runtime.type", "remote"),
-			attribute.String("remote.addr", remote.addr),
+			attribute.String("remote.addr", addr),
 		),
 	)
 	defer span.End()
 
 	startTime := time.Now()
-	resp, err := remote.client.Execute(ctx, &pb.ExecuteRequest{
-		Input: input.Message,
-	})
+	result, err := r.transport.Call(ctx, target, input)
 	duration := time.Since(startTime)
 
 	if r.config.EnableMetrics {
@@ -523,14 +428,18 @@ func (r *DistributedRuntime) Call(ctx context.Context, target string, input *age
 	}
 
 	if err != nil {
+		r.forgetResolved(target, addr)
 		return nil, err
 	}
 
-	return &agent.Message{Message: resp.Output}, nil
+	return result, nil
 }
 
 // CallParallel invokes multiple agents concurrently and returns all results
 func (r *DistributedRuntime) CallParallel(ctx context.Context, targets []string, input *agent.Message) (map[string]*agent.Message, map[string]error) {
+	// Every target shares the caller's request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	results := make(map[string]*agent.Message)
 	errors := make(map[string]error)
 	var mu sync.Mutex
@@ -599,8 +508,8 @@ func (r *DistributedRuntime) Broadcast(msg *agent.Message) error {
 	return firstErr
 }
 
-// Start starts the runtime and gRPC server.
-// The gRPC server will listen on the address provided during construction.
+// Start starts the runtime and begins serving local agents to other nodes
+// over the transport.
 func (r *DistributedRuntime) Start(ctx context.Context) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -611,81 +520,23 @@ func (r *DistributedRuntime) Start(ctx context.Context) error {
 
 	r.ctx, r.cancel = context.WithCancel(ctx)
 
-	// Start gRPC server if listen address is configured
-	if r.listenAddr != "" {
-		// Create listener
-		lis, err := net.Listen("tcp", r.listenAddr)
-		if err != nil {
-			return fmt.Errorf("failed to listen on %s: %w", r.listenAddr, err)
-		}
-		r.listener = lis
-
-		// Configure server options (TLS if enabled)
-		serverOpts, err := r.buildServerOptions()
-		if err != nil {
-			_ = lis.Close()
-			return fmt.Errorf("failed to configure server: %w", err)
-		}
-
-		r.server = grpc.NewServer(serverOpts...)
-		pb.RegisterAgentServiceServer(r.server, &agentServiceServer{runtime: r})
-
-		// Start gRPC server in goroutine
-		go func() {
-			log.Printf("[DistributedRuntime] gRPC server listening on %s", r.listenAddr)
-			if err := r.server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
-				log.Printf("[DistributedRuntime] gRPC server error: %v", err)
-			}
-		}()
+	if err := r.transport.Serve(r.ctx, localHandler{r}); err != nil {
+		r.cancel()
+		return fmt.Errorf("failed to start transport: %w", err)
 	}
 
-	r.started = true
-	return nil
-}
-
-// buildServerOptions creates gRPC server options based on TLS configuration.
-func (r *DistributedRuntime) buildServerOptions() ([]grpc.ServerOption, error) {
-	var opts []grpc.ServerOption
-
-	// ExternalTLS means TLS is handled by service mesh - no server-side TLS needed
-	if r.tlsConfig != nil && r.tlsConfig.ExternalTLS {
-		return opts, nil
+	names := make([]string, 0, len(r.localAgents))
+	for name := range r.localAgents {
+		names = append(names, name)
 	}
-
-	if r.tlsConfig != nil && r.tlsConfig.Enabled {
-		// Load server certificate
-		cert, err := tls.LoadX509KeyPair(r.tlsConfig.CertFile, r.tlsConfig.KeyFile)
-		if err != nil {
-			return nil, fmt.Errorf("failed to load server certificate: %w", err)
-		}
-
-		tlsCfg := &tls.Config{
-			Certificates: []tls.Certificate{cert},
-			MinVersion:   tls.VersionTLS12,
-		}
-
-		// Load CA for mTLS if provided
-		if r.tlsConfig.CAFile != "" {
-			caPath, err := security.ResolveTLSCertPath(r.tlsConfig.CAFile)
-			if err != nil {
-				return nil, fmt.Errorf("invalid CA file path: %w", err)
-			}
-			caData, err := os.ReadFile(caPath) // #nosec G304 -- path confined to AIXGO_TLS_CERT_DIR allowlist by security.ResolveTLSCertPath
-			if err != nil {
-				return nil, fmt.Errorf("failed to read CA file: %w", err)
-			}
-			caPool := x509.NewCertPool()
-			if !caPool.AppendCertsFromPEM(caData) {
-				return nil, fmt.Errorf("failed to parse CA certificate")
-			}
-			tlsCfg.ClientCAs = caPool
-			tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
-		}
-
-		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	if err := r.publish(r.ctx, names...); err != nil {
+		_ = r.transport.Close()
+		r.cancel()
+		return err
 	}
 
-	return opts, nil
+	r.started = true
+	return nil
 }
 
 // Stop gracefully shuts down the runtime
@@ -698,23 +549,20 @@ func (r *DistributedRuntime) Stop(ctx context.Context) error {
 
 	r.cancel()
 
-	// Stop gRPC server
-	if r.server != nil {
-		r.server.GracefulStop()
-	}
-
-	// Close remote connections
-	for _, remote := range r.remoteAgents {
-		_ = remote.conn.Close()
-	}
+	// Stop serving and close remote connections
+	_ = r.transport.Close()
 
-	// Stop local agents
+	// Withdraw and stop local agents
 	agents := make([]agent.Agent, 0, len(r.localAgents))
-	for _, a := range r.localAgents {
+	names := make([]string, 0, len(r.localAgents))
+	for name, a := range r.localAgents {
 		agents = append(agents, a)
+		names = append(names, name)
 	}
 	r.mu.Unlock()
 
+	r.withdraw(ctx, names...)
+
 	var wg sync.WaitGroup
 	for _, a := range agents {
 		wg.Add(1)
@@ -759,8 +607,9 @@ func (r *DistributedRuntime) SessionManager() session.Manager {
 // The input message is appended to the session before execution,
 // and the result is appended after execution.
 //
-// If the agent implements sessionAwareAgent, it will receive
-// the full conversation history during execution.
+// The agent receives the prior turns under the input's
+// session.MetadataKeyHistory metadata, including remote agents. Local
+// agents implementing sessionAwareAgent also get the session itself.
 func (r *DistributedRuntime) CallWithSession(
 	ctx context.Context,
 	target string,
@@ -777,13 +626,24 @@ func (r *DistributedRuntime) CallWithSession(
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
+	// Load prior turns before the input joins the session
+	history, err := sess.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get history: %w", err)
+	}
+
 	// Append input to session
 	if err := sess.AppendMessage(ctx, input); err != nil {
 		return nil, fmt.Errorf("append input: %w", err)
 	}
 
-	// Add session to context
+	// The agent sees the input with the history attached
+	input = session.WithHistory(input, history)
+
+	// Add session to context and track the LLM usage of this turn
 	ctx = session.ContextWithSession(ctx, sess)
+	tracker := cost.NewTracker()
+	ctx = cost.ContextWithTracker(ctx, tracker)
 
 	// Get the agent (local only for session-aware execution)
 	r.mu.RLock()
@@ -823,7 +683,8 @@ func (r *DistributedRuntime) CallWithSession(
 		result = internalToPublicMessage(internalResult)
 	}
 
-	// Append result to session
+	// Append result to session with the turn's usage
+	session.StampUsage(result, tracker)
 	if err := sess.AppendMessage(ctx, result); err != nil {
 		return nil, fmt.Errorf("append result: %w", err)
 	}
@@ -874,13 +735,13 @@ func publicToInternalMessage(msg *publicAgent.Message) *agent.Message {
 	}
 }
 
-// ListenAddr returns the address the gRPC server is listening on.
-// Returns empty string if server is not started.
+// ListenAddr returns the address the transport is listening on, or the
+// configured listen address if it is not serving.
 func (r *DistributedRuntime) ListenAddr() string {
-	r.mu.RLock()
-	defer r.mu.RUnlock()
-	if r.listener != nil {
-		return r.listener.Addr().String()
+	if a, ok := r.transport.(interface{ Addr() string }); ok {
+		if addr := a.Addr(); addr != "" {
+			return addr
+		}
 	}
 	return r.listenAddr
 }
@@ -911,115 +772,6 @@ func (r *DistributedRuntime) MessagesSent() uint64 {
 	return atomic.LoadUint64(&r.messagesSent)
 }
 
-// agentServiceServer implements the gRPC AgentService
-type agentServiceServer struct {
-	pb.UnimplementedAgentServiceServer
-	runtime *DistributedRuntime
-}
-
-func (s *agentServiceServer) Execute(ctx context.Context, req *pb.ExecuteRequest) (*pb.ExecuteResponse, error) {
-	// 1. Validate request
-	if req.AgentName == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "agent_name is required")
-	}
-	if req.Input == nil {
-		return nil, status.Errorf(codes.InvalidArgument, "input is required")
-	}
-
-	// 2. Validate agent name format
-	if !isValidAgentNameGRPC(req.AgentName) {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid agent name format")
-	}
-
-	// 3. Execute with timeout
-	ctx, cancel := context.WithTimeout(ctx, 30*time.Second)
-	defer cancel()
-
-	result, err := s.runtime.Call(ctx, req.AgentName, &agent.Message{Message: req.Input})
-	if err != nil {
-		if errors.Is(err, ErrAgentNotFound) {
-			return nil, status.Errorf(codes.NotFound, "agent not found: %s", req.AgentName)
-		}
-		if errors.Is(err, context.DeadlineExceeded) {
-			return nil, status.Errorf(codes.DeadlineExceeded, "execution timeout")
-		}
-		return nil, status.Errorf(codes.Internal, "execution failed: %v", err)
-	}
-
-	return &pb.ExecuteResponse{Output: result.Message}, nil
-}
-
-func (s *agentServiceServer) Send(ctx context.Context, req *pb.SendRequest) (*pb.SendResponse, error) {
-	// Validate
-	if req.Target == "" {
-		return nil, status.Errorf(codes.InvalidArgument, "target is required")
-	}
-	if req.Message == nil {
-		return nil, status.Errorf(codes.InvalidArgument, "message is required")
-	}
-
-	if !isValidAgentNameGRPC(req.Target) {
-		return nil, status.Errorf(codes.InvalidArgument, "invalid target name")
-	}
-
-	// Send message
-	err := s.runtime.Send(req.Target, &agent.Message{Message: req.Message})
-	if err != nil {
-		if errors.Is(err, ErrAgentNotFound) {
-			return nil, status.Errorf(codes.NotFound, "agent not found: %s", req.Target)
-		}
-		return nil, status.Errorf(codes.Internal, "send failed: %v", err)
-	}
-
-	return &pb.SendResponse{Success: true}, nil
-}
-
-// Listen implements server-side streaming for receiving messages from an agent.
-// This allows remote clients to subscribe to messages from a local agent.
-func (s *agentServiceServer) Listen(req *pb.ListenRequest, stream pb.AgentService_ListenServer) error {
-	// Validate
-	if req.AgentName == "" {
-		return status.Errorf(codes.InvalidArgument, "agent_name is required")
-	}
-
-	if !isValidAgentNameGRPC(req.AgentName) {
-		return status.Errorf(codes.InvalidArgument, "invalid agent name format")
-	}
-
-	// Get the channel for this agent
-	ch, err := s.runtime.Recv(req.AgentName)
-	if err != nil {
-		if errors.Is(err, ErrAgentNotFound) {
-			return status.Errorf(codes.NotFound, "agent not found: %s", req.AgentName)
-		}
-		return status.Errorf(codes.Internal, "failed to get channel: %v", err)
-	}
-
-	// Stream messages until context is cancelled
-	ctx := stream.Context()
-	for {
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		case msg, ok := <-ch:
-			if !ok {
-				// Channel closed
-				return nil
-			}
-			if err := stream.Send(&pb.ListenResponse{Message: msg.Message}); err != nil {
-				return err
-			}
-		}
-	}
-}
-
-// isValidAgentNameGRPC validates agent name for gRPC requests
-func isValidAgentNameGRPC(name string) bool {
-	// Only allow lowercase alphanumeric, hyphens, underscores, max 64 chars
-	matched, _ := regexp.MatchString(`^[a-z][a-z0-9_-]{0,63}$`, name)
-	return matched
-}
-
 // StartAgentsPhased starts all registered LOCAL agents in dependency order.
 // Remote agents are assumed to be already running on their respective nodes.
 //
@@ -33,6 +33,7 @@ type LocalRuntime struct {
 	cancel       context.CancelFunc
 	semaphore    chan struct{} // For limiting concurrent calls
 	messagesSent uint64        // Atomic counter for metrics
+	deadLetters  *DeadLetterQueue
 }
 
 // NewLocalRuntime creates a new LocalRuntime with the given options.
@@ -51,10 +52,11 @@ func NewLocalRuntime(opts ...Option) *LocalRuntime {
 	}
 
 	return &LocalRuntime{
-		agents:    make(map[string]agent.Agent),
-		channels:  make(map[string]chan *agent.Message),
-		config:    cfg,
-		semaphore: sem,
+		agents:      make(map[string]agent.Agent),
+		channels:    make(map[string]chan *agent.Message),
+		config:      cfg,
+		semaphore:   sem,
+		deadLetters: NewDeadLetterQueue(cfg.DeadLetterBufferSize),
 	}
 }
 
@@ -117,8 +119,17 @@ func (r *LocalRuntime) List() []string {
 	return names
 }
 
-// Send sends a message to a target agent asynchronously
+// Send sends a message to a target agent asynchronously. Messages that
+// cannot be delivered are also copied to DeadLetters.
 func (r *LocalRuntime) Send(target string, msg *agent.Message) error {
+	err := r.send(target, msg)
+	if err != nil {
+		r.deadLetters.Add(target, msg, err)
+	}
+	return err
+}
+
+func (r *LocalRuntime) send(target string, msg *agent.Message) error {
 	r.mu.RLock()
 	ch, exists := r.channels[target]
 	r.mu.RUnlock()
@@ -175,6 +186,9 @@ func (r *LocalRuntime) Call(ctx context.Context, target string, input *agent.Mes
 		return nil, ErrRuntimeNotStarted
 	}
 
+	// Correlate everything this call triggers under one request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	// Acquire semaphore if concurrency limiting is enabled
 	if r.semaphore != nil {
 		select {
@@ -216,6 +230,7 @@ func (r *LocalRuntime) Call(ctx context.Context, target string, input *agent.Mes
 			attribute.Int64("execution.duration_ms", duration.Milliseconds()),
 			attribute.Bool("execution.success", err == nil),
 		)
+		recordAgentCall(target, duration, result, err)
 	}
 
 	return result, err
@@ -223,6 +238,9 @@ func (r *LocalRuntime) Call(ctx context.Context, target string, input *agent.Mes
 
 // CallParallel invokes multiple agents concurrently and returns all results
 func (r *LocalRuntime) CallParallel(ctx context.Context, targets []string, input *agent.Message) (map[string]*agent.Message, map[string]error) {
+	// Every target shares the caller's request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	results := make(map[string]*agent.Message)
 	errors := make(map[string]error)
 	var mu sync.Mutex
@@ -378,7 +396,11 @@ func (r *LocalRuntime) Stop(ctx context.Context) error {
 // This method should be called after all agents are registered and after
 // Start() has been called to initialize the runtime.
 func (r *LocalRuntime) StartAgentsPhased(ctx context.Context, agentDefs map[string]agent.AgentDef) error {
-	if !r.started {
+	r.mu.RLock()
+	started := r.started
+	r.mu.RUnlock()
+
+	if !started {
 		return ErrRuntimeNotStarted
 	}
 
@@ -410,9 +432,10 @@ func (r *LocalRuntime) StartAgentsPhased(ctx context.Context, agentDefs map[stri
 				}
 
 				// Start agent in goroutine (non-blocking)
-				// The Start() method runs the agent's main loop
+				// The Start() method runs the agent's main loop, so it gets
+				// ctx rather than gctx, which is canceled once the phase ends
 				go func() {
-					if err := a.Start(gctx); err != nil {
+					if err := a.Start(ctx); err != nil {
 						log.Printf("[Runtime] Agent %s error: %v", name, err)
 					}
 				}()
@@ -0,0 +1,191 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/graph"
+)
+
+// startupLog records the order agents start in
+type startupLog struct {
+	mu    sync.Mutex
+	order []string
+}
+
+func (l *startupLog) add(name string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.order = append(l.order, name)
+}
+
+// phasedAgent becomes ready shortly after Start and runs until its context
+// is canceled. Start fails if any dependency is not yet ready.
+type phasedAgent struct {
+	echoAgent
+	deps     []*phasedAgent
+	log      *startupLog
+	ready    atomic.Bool
+	canceled atomic.Bool
+	startErr atomic.Value
+}
+
+func (a *phasedAgent) Ready() bool { return a.ready.Load() }
+
+func (a *phasedAgent) Start(ctx context.Context) error {
+	for _, dep := range a.deps {
+		if !dep.Ready() {
+			err := errors.New(a.name + " started before " + dep.name + " was ready")
+			a.startErr.Store(err)
+			return err
+		}
+	}
+	a.log.add(a.name)
+	time.Sleep(20 * time.Millisecond)
+	a.ready.Store(true)
+	<-ctx.Done()
+	a.canceled.Store(true)
+	return nil
+}
+
+func TestLocalRuntime_StartAgentsPhased(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	log := &startupLog{}
+	extractor := &phasedAgent{echoAgent: echoAgent{name: "extractor"}, log: log}
+	enricher := &phasedAgent{echoAgent: echoAgent{name: "enricher"}, log: log, deps: []*phasedAgent{extractor}}
+	aggregator := &phasedAgent{echoAgent: echoAgent{name: "aggregator"}, log: log, deps: []*phasedAgent{enricher}}
+
+	rt := NewLocalRuntime()
+	for _, a := range []*phasedAgent{aggregator, enricher, extractor} {
+		if err := rt.Register(a); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	defs := map[string]agent.AgentDef{
+		"extractor":  {Name: "extractor"},
+		"enricher":   {Name: "enricher", DependsOn: []string{"extractor"}},
+		"aggregator": {Name: "aggregator", DependsOn: []string{"enricher"}},
+	}
+	if err := rt.StartAgentsPhased(ctx, defs); err != nil {
+		t.Fatalf("StartAgentsPhased() error = %v", err)
+	}
+
+	want := []string{"extractor", "enricher", "aggregator"}
+	log.mu.Lock()
+	got := append([]string(nil), log.order...)
+	log.mu.Unlock()
+	if len(got) != len(want) {
+		t.Fatalf("start order = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("start order = %v, want %v", got, want)
+		}
+	}
+
+	// Agents keep running after their phase completes
+	time.Sleep(20 * time.Millisecond)
+	for _, a := range []*phasedAgent{extractor, enricher, aggregator} {
+		if err, _ := a.startErr.Load().(error); err != nil {
+			t.Error(err)
+		}
+		if a.canceled.Load() {
+			t.Errorf("agent %s was canceled after startup", a.name)
+		}
+	}
+}
+
+func TestLocalRuntime_StartAgentsPhased_Cycle(t *testing.T) {
+	ctx := context.Background()
+	log := &startupLog{}
+
+	rt := NewLocalRuntime()
+	for _, name := range []string{"a", "b", "c"} {
+		if err := rt.Register(&phasedAgent{echoAgent: echoAgent{name: name}, log: log}); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+
+	defs := map[string]agent.AgentDef{
+		"a": {Name: "a", DependsOn: []string{"c"}},
+		"b": {Name: "b", DependsOn: []string{"a"}},
+		"c": {Name: "c", DependsOn: []string{"b"}},
+	}
+	err := rt.StartAgentsPhased(ctx, defs)
+	if !errors.Is(err, graph.ErrCycleDetected) {
+		t.Fatalf("StartAgentsPhased() error = %v, want ErrCycleDetected", err)
+	}
+	var cycleErr *graph.CycleError
+	if !errors.As(err, &cycleErr) || len(cycleErr.Path) != 4 {
+		t.Errorf("cycle path = %v, want a -> b -> c loop", err)
+	}
+	if len(log.order) != 0 {
+		t.Errorf("agents %v started despite the cycle", log.order)
+	}
+}
+
+func TestLocalRuntime_DeadLetters(t *testing.T) {
+	rt := NewLocalRuntime(WithDeadLetterBufferSize(1))
+
+	msg := textMessage("lost")
+	msg.Metadata = map[string]any{"trace": "t1"}
+	if err := rt.Send("ticket_input", msg); !errors.Is(err, ErrAgentNotFound) {
+		t.Fatalf("Send() error = %v, want ErrAgentNotFound", err)
+	}
+
+	select {
+	case dl := <-rt.DeadLetters():
+		if dl.Payload != "lost" || dl.Metadata["trace"] != "t1" {
+			t.Errorf("dead letter = %+v, want the original message", dl.Message)
+		}
+		if dl.Metadata[MetadataKeyDeadLetterTarget] != "ticket_input" {
+			t.Errorf("dead letter target = %v, want ticket_input", dl.Metadata[MetadataKeyDeadLetterTarget])
+		}
+		reason, _ := dl.Metadata[MetadataKeyDeadLetterReason].(string)
+		if !strings.Contains(reason, ErrAgentNotFound.Error()) {
+			t.Errorf("dead letter reason = %q", reason)
+		}
+	default:
+		t.Fatal("undeliverable message not on the dead-letter channel")
+	}
+	if _, tagged := msg.Metadata[MetadataKeyDeadLetterTarget]; tagged {
+		t.Error("sender's message should not be modified")
+	}
+
+	// Delivered messages do not produce dead letters, and a full buffer
+	// never blocks Send
+	if err := rt.Register(&echoAgent{name: "echo"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := rt.Send("echo", textMessage("ok")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	for range 3 {
+		_ = rt.Send("missing", textMessage("lost"))
+	}
+	if n := len(rt.DeadLetters()); n != 1 {
+		t.Errorf("dead letters = %d, want 1 (buffer size)", n)
+	}
+
+	// A zero size keeps the default buffer rather than dropping everything
+	rt = NewLocalRuntime(WithDeadLetterBufferSize(0))
+	_ = rt.Send("missing", textMessage("lost"))
+	if n := len(rt.DeadLetters()); n != 1 {
+		t.Errorf("dead letters with size 0 = %d, want 1", n)
+	}
+}
@@ -0,0 +1,22 @@
+package runtime
+
+import (
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pkgobs "github.com/aixgo-dev/aixgo/pkg/observability"
+)
+
+// recordAgentCall exports a completed call to the Prometheus agent metrics,
+// taking token and cost usage from the response metadata
+func recordAgentCall(target string, duration time.Duration, result *agent.Message, err error) {
+	var tokens int
+	var costUSD float64
+	if result != nil && result.Message != nil {
+		in, _ := agent.MetadataFloat(result.Metadata, agent.MetadataKeyInputTokens)
+		out, _ := agent.MetadataFloat(result.Metadata, agent.MetadataKeyOutputTokens)
+		tokens = int(in + out)
+		costUSD, _ = agent.MetadataFloat(result.Metadata, agent.MetadataKeyCostUSD)
+	}
+	pkgobs.RecordAgentCall(target, duration, tokens, costUSD, err)
+}
@@ -0,0 +1,172 @@
+package runtime
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// ServiceRegistry maps agent names to the addresses of the nodes serving
+// them. A DistributedRuntime with a registry publishes its local agents
+// when it starts and resolves unknown agents through it on first use, so
+// remote agents need no explicit Connect. A resolved address is dropped
+// when a call or send to it fails, so the next use looks the agent up
+// again and follows it to a new node.
+type ServiceRegistry interface {
+	// Register announces that the agent name is served at addr
+	Register(ctx context.Context, name, addr string) error
+
+	// Deregister withdraws the agent name
+	Deregister(ctx context.Context, name string) error
+
+	// Lookup returns the address serving name, or an error wrapping
+	// ErrAgentNotFound
+	Lookup(ctx context.Context, name string) (string, error)
+}
+
+// WithServiceRegistry sets the registry used to publish local agents and
+// resolve remote ones
+func WithServiceRegistry(reg ServiceRegistry) DistributedOption {
+	return func(r *DistributedRuntime) {
+		r.registry = reg
+	}
+}
+
+// WithAdvertiseAddr sets the address published to the service registry
+// for local agents. Defaults to the transport's listen address, which may
+// not be reachable from other hosts (e.g. ":50051").
+func WithAdvertiseAddr(addr string) DistributedOption {
+	return func(r *DistributedRuntime) {
+		r.advertiseAddr = addr
+	}
+}
+
+// MemoryRegistry is an in-process ServiceRegistry for tests and
+// single-host deployments
+type MemoryRegistry struct {
+	mu     sync.RWMutex
+	agents map[string]string
+}
+
+// NewMemoryRegistry creates an empty MemoryRegistry
+func NewMemoryRegistry() *MemoryRegistry {
+	return &MemoryRegistry{agents: make(map[string]string)}
+}
+
+// Register records addr for name, replacing any previous address
+func (m *MemoryRegistry) Register(ctx context.Context, name, addr string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.agents[name] = addr
+	return nil
+}
+
+// Deregister removes name
+func (m *MemoryRegistry) Deregister(ctx context.Context, name string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.agents, name)
+	return nil
+}
+
+// Lookup returns the address registered for name
+func (m *MemoryRegistry) Lookup(ctx context.Context, name string) (string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+	addr, ok := m.agents[name]
+	if !ok {
+		return "", fmt.Errorf("%w: %s (not in registry)", ErrAgentNotFound, name)
+	}
+	return addr, nil
+}
+
+// publishedAddr is the address local agents are registered under
+func (r *DistributedRuntime) publishedAddr() string {
+	if r.advertiseAddr != "" {
+		return r.advertiseAddr
+	}
+	return r.ListenAddr()
+}
+
+// publish registers local agents with the service registry, if any. On
+// failure it withdraws the agents it already registered.
+func (r *DistributedRuntime) publish(ctx context.Context, names ...string) error {
+	if r.registry == nil {
+		return nil
+	}
+	addr := r.publishedAddr()
+	for i, name := range names {
+		if err := r.registry.Register(ctx, name, addr); err != nil {
+			r.withdraw(ctx, names[:i]...)
+			return fmt.Errorf("failed to register agent %s with service registry: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// withdraw deregisters local agents from the service registry, if any
+func (r *DistributedRuntime) withdraw(ctx context.Context, names ...string) {
+	if r.registry == nil {
+		return
+	}
+	for _, name := range names {
+		_ = r.registry.Deregister(ctx, name)
+	}
+}
+
+// remoteAddr returns the address of a connected remote agent, resolving
+// and connecting it through the service registry on first use
+func (r *DistributedRuntime) remoteAddr(ctx context.Context, name string) (string, error) {
+	r.mu.RLock()
+	addr, exists := r.remoteAgents[name]
+	r.mu.RUnlock()
+	if exists {
+		return addr, nil
+	}
+	if r.registry == nil {
+		return "", fmt.Errorf("%w: %s", ErrAgentNotFound, name)
+	}
+
+	addr, err := r.registry.Lookup(ctx, name)
+	if err != nil {
+		return "", err
+	}
+	if err := r.Connect(name, addr); err != nil {
+		// Another caller may have connected it concurrently
+		r.mu.RLock()
+		addr, exists = r.remoteAgents[name]
+		r.mu.RUnlock()
+		if !exists {
+			return "", err
+		}
+		return addr, nil
+	}
+
+	r.mu.Lock()
+	if r.remoteAgents[name] == addr {
+		r.resolved[name] = true
+	}
+	r.mu.Unlock()
+	return addr, nil
+}
+
+// forgetResolved drops a remote agent resolved through the registry at addr
+// so its next use resolves it again. Explicitly connected agents are kept.
+func (r *DistributedRuntime) forgetResolved(name, addr string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !r.resolved[name] || r.remoteAgents[name] != addr {
+		return
+	}
+	r.disconnect(name)
+	delete(r.remoteAgents, name)
+	delete(r.resolved, name)
+}
+
+// disconnect closes the transport's connection to a remote agent, if it
+// keeps one. Caller must hold mu so a concurrent Connect is not undone.
+func (r *DistributedRuntime) disconnect(name string) {
+	if d, ok := r.transport.(Dialer); ok {
+		_ = d.Disconnect(name)
+	}
+}
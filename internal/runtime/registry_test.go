@@ -0,0 +1,160 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+// failingAgent returns err from every Execute
+type failingAgent struct {
+	echoAgent
+	err error
+}
+
+func (a *failingAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return nil, a.err
+}
+
+func TestServiceRegistry(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	registry := NewMemoryRegistry()
+
+	server := NewDistributedRuntime("",
+		WithTransport(NewGRPCTransport("127.0.0.1:0", nil)),
+		WithServiceRegistry(registry),
+	)
+	if err := server.Register(&echoAgent{name: "echo"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("server Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = server.Stop(context.Background()) })
+
+	// Agents registered after Start are published too
+	quota := &failingAgent{echoAgent: echoAgent{name: "quota"}, err: errors.New("quota exhausted")}
+	if err := server.Register(quota); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if addr, err := registry.Lookup(ctx, "quota"); err != nil || addr != server.ListenAddr() {
+		t.Fatalf("Lookup() = %q, %v, want %q", addr, err, server.ListenAddr())
+	}
+
+	client := NewDistributedRuntime("",
+		WithTransport(NewGRPCTransport("", nil)),
+		WithServiceRegistry(registry),
+	)
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("client Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Stop(context.Background()) })
+
+	// Remote agents resolve by name without an explicit Connect
+	result, err := client.Call(ctx, "echo", textMessage("hello"))
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result.Payload != "echo: hello" {
+		t.Errorf("Call() payload = %q", result.Payload)
+	}
+
+	// Agent errors propagate across the wire
+	if _, err := client.Call(ctx, "quota", textMessage("hi")); err == nil || !strings.Contains(err.Error(), "quota exhausted") {
+		t.Errorf("Call() error = %v, want the remote agent's error", err)
+	}
+
+	if _, err := client.Call(ctx, "missing", textMessage("hi")); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Call() error = %v, want ErrAgentNotFound", err)
+	}
+
+	// Stopping the server withdraws its agents
+	if err := server.Stop(ctx); err != nil {
+		t.Fatalf("server Stop() error = %v", err)
+	}
+	if _, err := registry.Lookup(ctx, "echo"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Lookup() after Stop error = %v, want ErrAgentNotFound", err)
+	}
+}
+
+// failingRegistry rejects every Register
+type failingRegistry struct {
+	*MemoryRegistry
+}
+
+func (f failingRegistry) Register(ctx context.Context, name, addr string) error {
+	return errors.New("registry unavailable")
+}
+
+func TestServiceRegistry_PublishFailureRollsBack(t *testing.T) {
+	rt := NewDistributedRuntime("",
+		WithTransport(NewGRPCTransport("127.0.0.1:0", nil)),
+		WithServiceRegistry(failingRegistry{NewMemoryRegistry()}),
+	)
+	if err := rt.Start(context.Background()); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = rt.Stop(context.Background()) })
+
+	if err := rt.Register(&echoAgent{name: "echo"}); err == nil {
+		t.Fatal("Register() succeeded although publishing failed")
+	}
+	if _, err := rt.Get("echo"); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Get() error = %v, want the agent rolled back", err)
+	}
+}
+
+func TestServiceRegistry_ReResolvesMovedAgent(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	registry := NewMemoryRegistry()
+
+	startServer := func() *DistributedRuntime {
+		server := NewDistributedRuntime("",
+			WithTransport(NewGRPCTransport("127.0.0.1:0", nil)),
+			WithServiceRegistry(registry),
+		)
+		if err := server.Register(&echoAgent{name: "echo"}); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+		if err := server.Start(ctx); err != nil {
+			t.Fatalf("server Start() error = %v", err)
+		}
+		t.Cleanup(func() { _ = server.Stop(context.Background()) })
+		return server
+	}
+
+	first := startServer()
+	client := NewDistributedRuntime("",
+		WithTransport(NewGRPCTransport("", nil)),
+		WithServiceRegistry(registry),
+	)
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("client Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Stop(context.Background()) })
+
+	if _, err := client.Call(ctx, "echo", textMessage("hello")); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	// The agent moves to a new node
+	if err := first.Stop(ctx); err != nil {
+		t.Fatalf("Stop() error = %v", err)
+	}
+	second := startServer()
+
+	callCtx, callCancel := context.WithTimeout(ctx, time.Second)
+	defer callCancel()
+	if _, err := client.Call(callCtx, "echo", textMessage("hello")); err == nil {
+		t.Fatal("Call() to the stopped node succeeded")
+	}
+	if _, err := client.Call(ctx, "echo", textMessage("hello")); err != nil {
+		t.Fatalf("Call() after the move error = %v, want it resolved to %s", err, second.ListenAddr())
+	}
+}
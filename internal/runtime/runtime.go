@@ -51,6 +51,11 @@ type RuntimeConfig struct {
 	// ChannelFullWarningThreshold triggers a warning when channel utilization exceeds this percentage
 	// Default: 80
 	ChannelFullWarningThreshold int
+
+	// DeadLetterBufferSize sets how many undeliverable messages DeadLetters
+	// holds before further ones are dropped (<= 0 uses the default)
+	// Default: 100
+	DeadLetterBufferSize int
 }
 
 // DefaultConfig returns a RuntimeConfig with sensible defaults
@@ -63,6 +68,7 @@ func DefaultConfig() *RuntimeConfig {
 		AgentStartTimeout:           30 * time.Second,
 		SendTimeout:                 5 * time.Second,
 		ChannelFullWarningThreshold: 80,
+		DeadLetterBufferSize:        DefaultDeadLetterBufferSize,
 	}
 }
 
@@ -104,6 +110,16 @@ func WithSendTimeout(timeout time.Duration) Option {
 	}
 }
 
+// WithDeadLetterBufferSize sets the dead-letter channel buffer size. Sizes
+// <= 0 are ignored, since an unbuffered channel would drop every dead letter.
+func WithDeadLetterBufferSize(size int) Option {
+	return func(cfg *RuntimeConfig) {
+		if size > 0 {
+			cfg.DeadLetterBufferSize = size
+		}
+	}
+}
+
 // WithTracing enables or disables OpenTelemetry tracing
 func WithTracing(enabled bool) Option {
 	return func(cfg *RuntimeConfig) {
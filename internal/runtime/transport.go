@@ -0,0 +1,219 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+// remoteExecuteTimeout bounds agent calls served for other nodes
+const remoteExecuteTimeout = 30 * time.Second
+
+// Transport carries agent calls and messages between DistributedRuntime
+// nodes. Agent code and orchestration patterns only see the Runtime
+// interface, so the same agents run over any transport.
+//
+// Implementations: GRPCTransport (default), NATSTransport, and
+// MemoryTransport for tests.
+type Transport interface {
+	// Call invokes a remote agent and waits for its response. It returns an
+	// error wrapping ErrAgentNotFound if no node serves target.
+	Call(ctx context.Context, target string, input *agent.Message) (*agent.Message, error)
+
+	// Send delivers a message to a remote agent's channel
+	Send(ctx context.Context, target string, msg *agent.Message) error
+
+	// Subscribe streams messages from a remote agent's channel until ctx is
+	// done or the remote channel closes
+	Subscribe(ctx context.Context, source string) (<-chan *agent.Message, error)
+
+	// Serve starts accepting requests from other nodes and dispatches them
+	// to h. It returns once the transport is ready; serving stops when ctx
+	// is done or the transport is closed.
+	Serve(ctx context.Context, h TransportHandler) error
+
+	// Close stops serving and releases connections
+	Close() error
+}
+
+// Dialer is implemented by transports that address each remote agent
+// directly, such as GRPCTransport. DistributedRuntime.Connect and
+// Unregister forward to it; transports that route by agent name, such as
+// NATSTransport, do not implement it.
+type Dialer interface {
+	// Connect makes the agent at addr reachable by name
+	Connect(name, addr string) error
+
+	// Disconnect closes the connection to a remote agent
+	Disconnect(name string) error
+}
+
+// TransportHandler serves requests a transport receives from other nodes
+type TransportHandler interface {
+	HandleCall(ctx context.Context, target string, input *agent.Message) (*agent.Message, error)
+	HandleSend(target string, msg *agent.Message) error
+	HandleSubscribe(source string) (<-chan *agent.Message, error)
+}
+
+// WithTransport sets the transport used to reach remote agents and serve
+// local ones. Defaults to a GRPCTransport on the runtime's listen address.
+func WithTransport(t Transport) DistributedOption {
+	return func(r *DistributedRuntime) {
+		r.transport = t
+	}
+}
+
+// localHandler serves transport requests from the runtime's local agents
+// only, so a request is never forwarded back out over the transport
+type localHandler struct {
+	r *DistributedRuntime
+}
+
+func (h localHandler) isLocal(name string) bool {
+	h.r.mu.RLock()
+	defer h.r.mu.RUnlock()
+	_, ok := h.r.localAgents[name]
+	return ok
+}
+
+func (h localHandler) HandleCall(ctx context.Context, target string, input *agent.Message) (*agent.Message, error) {
+	if !h.isLocal(target) {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, target)
+	}
+	return h.r.Call(ctx, target, input)
+}
+
+func (h localHandler) HandleSend(target string, msg *agent.Message) error {
+	if !h.isLocal(target) {
+		return fmt.Errorf("%w: %s", ErrAgentNotFound, target)
+	}
+	return h.r.Send(target, msg)
+}
+
+func (h localHandler) HandleSubscribe(source string) (<-chan *agent.Message, error) {
+	if !h.isLocal(source) {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, source)
+	}
+	return h.r.Recv(source)
+}
+
+// MemoryNetwork connects MemoryTransports within one process. It lets
+// tests run several DistributedRuntime nodes without a network.
+type MemoryNetwork struct {
+	mu    sync.RWMutex
+	nodes map[*MemoryTransport]TransportHandler
+}
+
+// NewMemoryNetwork creates an empty in-memory network
+func NewMemoryNetwork() *MemoryNetwork {
+	return &MemoryNetwork{nodes: make(map[*MemoryTransport]TransportHandler)}
+}
+
+// MemoryTransport is a Transport between nodes of a MemoryNetwork.
+// Messages are cloned on the way in and out, as if serialized.
+type MemoryTransport struct {
+	network *MemoryNetwork
+}
+
+// NewMemoryTransport creates a transport attached to network
+func NewMemoryTransport(network *MemoryNetwork) *MemoryTransport {
+	return &MemoryTransport{network: network}
+}
+
+// peers returns the handlers of every other serving node
+func (t *MemoryTransport) peers() []TransportHandler {
+	t.network.mu.RLock()
+	defer t.network.mu.RUnlock()
+
+	peers := make([]TransportHandler, 0, len(t.network.nodes))
+	for node, h := range t.network.nodes {
+		if node != t {
+			peers = append(peers, h)
+		}
+	}
+	return peers
+}
+
+// Call invokes target on the first peer that serves it
+func (t *MemoryTransport) Call(ctx context.Context, target string, input *agent.Message) (*agent.Message, error) {
+	for _, h := range t.peers() {
+		result, err := h.HandleCall(ctx, target, input.Clone())
+		if errors.Is(err, ErrAgentNotFound) {
+			continue
+		}
+		return result.Clone(), err
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, target)
+}
+
+// Send delivers msg to the first peer that serves target
+func (t *MemoryTransport) Send(ctx context.Context, target string, msg *agent.Message) error {
+	for _, h := range t.peers() {
+		err := h.HandleSend(target, msg.Clone())
+		if errors.Is(err, ErrAgentNotFound) {
+			continue
+		}
+		return err
+	}
+	return fmt.Errorf("%w: %s", ErrAgentNotFound, target)
+}
+
+// Subscribe streams messages from the first peer that serves source
+func (t *MemoryTransport) Subscribe(ctx context.Context, source string) (<-chan *agent.Message, error) {
+	for _, h := range t.peers() {
+		remote, err := h.HandleSubscribe(source)
+		if errors.Is(err, ErrAgentNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		ch := make(chan *agent.Message)
+		go func() {
+			defer close(ch)
+			for {
+				select {
+				case msg, ok := <-remote:
+					if !ok {
+						return
+					}
+					select {
+					case ch <- msg.Clone():
+					case <-ctx.Done():
+						return
+					}
+				case <-ctx.Done():
+					return
+				}
+			}
+		}()
+		return ch, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, source)
+}
+
+// Serve joins the network, serving requests with h until ctx is done
+func (t *MemoryTransport) Serve(ctx context.Context, h TransportHandler) error {
+	t.network.mu.Lock()
+	t.network.nodes[t] = h
+	t.network.mu.Unlock()
+
+	go func() {
+		<-ctx.Done()
+		_ = t.Close()
+	}()
+	return nil
+}
+
+// Close leaves the network
+func (t *MemoryTransport) Close() error {
+	t.network.mu.Lock()
+	defer t.network.mu.Unlock()
+	delete(t.network.nodes, t)
+	return nil
+}
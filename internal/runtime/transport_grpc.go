@@ -0,0 +1,485 @@
+package runtime
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/security"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+)
+
+// subscribeBufferSize is the buffer size of channels returned by Subscribe
+const subscribeBufferSize = 100
+
+// GRPCTransport is a Transport using the gRPC AgentService. Each remote
+// agent is reached through its own connection, opened with Connect.
+type GRPCTransport struct {
+	listenAddr string
+	tlsConfig  *TLSConfig
+	remotes    map[string]*remoteAgentClient
+	server     *grpc.Server
+	listener   net.Listener
+	mu         sync.RWMutex
+}
+
+// remoteAgentClient represents a connection to a remote agent
+type remoteAgentClient struct {
+	name   string
+	addr   string
+	conn   *grpc.ClientConn
+	client pb.AgentServiceClient
+}
+
+// NewGRPCTransport creates a gRPC transport. If listenAddr is empty the
+// transport only makes outgoing calls. tlsConfig may be nil.
+func NewGRPCTransport(listenAddr string, tlsConfig *TLSConfig) *GRPCTransport {
+	return &GRPCTransport{
+		listenAddr: listenAddr,
+		tlsConfig:  tlsConfig,
+		remotes:    make(map[string]*remoteAgentClient),
+	}
+}
+
+// Connect opens a connection to the remote agent at addr.
+// Uses TLS if configured, otherwise falls back to insecure connection.
+func (t *GRPCTransport) Connect(name, addr string) error {
+	dialOpts, err := t.buildDialOptions()
+	if err != nil {
+		return fmt.Errorf("failed to build dial options: %w", err)
+	}
+	dialOpts = append(dialOpts, grpc.WithDefaultCallOptions(grpc.CallContentSubtype(pb.CodecName)))
+
+	conn, err := grpc.NewClient(addr, dialOpts...)
+	if err != nil {
+		return err
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if old, ok := t.remotes[name]; ok {
+		_ = old.conn.Close()
+	}
+	t.remotes[name] = &remoteAgentClient{
+		name:   name,
+		addr:   addr,
+		conn:   conn,
+		client: pb.NewAgentServiceClient(conn),
+	}
+	return nil
+}
+
+// Disconnect closes the connection to a remote agent
+func (t *GRPCTransport) Disconnect(name string) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	remote, ok := t.remotes[name]
+	if !ok {
+		return nil
+	}
+	delete(t.remotes, name)
+	return remote.conn.Close()
+}
+
+func (t *GRPCTransport) remote(name string) (*remoteAgentClient, error) {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	remote, ok := t.remotes[name]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s (not connected)", ErrAgentNotFound, name)
+	}
+	return remote, nil
+}
+
+// Call invokes a remote agent via the Execute RPC
+func (t *GRPCTransport) Call(ctx context.Context, target string, input *agent.Message) (*agent.Message, error) {
+	remote, err := t.remote(target)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := remote.client.Execute(ctx, &pb.ExecuteRequest{
+		AgentName: target,
+		Input:     input.Message,
+	})
+	if err != nil {
+		return nil, fromStatus(target, err)
+	}
+	return &agent.Message{Message: resp.Output}, nil
+}
+
+// Send delivers a message via the Send RPC
+func (t *GRPCTransport) Send(ctx context.Context, target string, msg *agent.Message) error {
+	remote, err := t.remote(target)
+	if err != nil {
+		return err
+	}
+
+	_, err = remote.client.Send(ctx, &pb.SendRequest{
+		Target:  target,
+		Message: msg.Message,
+	})
+	return fromStatus(target, err)
+}
+
+// Subscribe streams messages via the Listen RPC
+func (t *GRPCTransport) Subscribe(ctx context.Context, source string) (<-chan *agent.Message, error) {
+	remote, err := t.remote(source)
+	if err != nil {
+		return nil, err
+	}
+
+	stream, err := remote.client.Listen(ctx, &pb.ListenRequest{AgentName: source})
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to remote agent stream: %w", fromStatus(source, err))
+	}
+
+	ch := make(chan *agent.Message, subscribeBufferSize)
+
+	go func() {
+		defer close(ch)
+		for {
+			resp, err := stream.Recv()
+			if err != nil {
+				// Stream closed or error
+				log.Printf("[DistributedRuntime] Stream from %s closed: %v", source, err)
+				return
+			}
+
+			select {
+			case ch <- &agent.Message{Message: resp.Message}:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// fromStatus maps a NotFound status back to ErrAgentNotFound
+func fromStatus(name string, err error) error {
+	if status.Code(err) == codes.NotFound {
+		return fmt.Errorf("%w: %s", ErrAgentNotFound, name)
+	}
+	return err
+}
+
+// Serve starts the gRPC server on the listen address. It does nothing if
+// no listen address is configured.
+func (t *GRPCTransport) Serve(ctx context.Context, h TransportHandler) error {
+	if t.listenAddr == "" {
+		return nil
+	}
+
+	lis, err := net.Listen("tcp", t.listenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", t.listenAddr, err)
+	}
+
+	// Configure server options (TLS if enabled)
+	serverOpts, err := t.buildServerOptions()
+	if err != nil {
+		_ = lis.Close()
+		return fmt.Errorf("failed to configure server: %w", err)
+	}
+
+	server := grpc.NewServer(serverOpts...)
+	pb.RegisterAgentServiceServer(server, &agentServiceServer{handler: h})
+
+	t.mu.Lock()
+	t.server = server
+	t.listener = lis
+	t.mu.Unlock()
+
+	go func() {
+		log.Printf("[DistributedRuntime] gRPC server listening on %s", lis.Addr())
+		if err := server.Serve(lis); err != nil && !errors.Is(err, grpc.ErrServerStopped) {
+			log.Printf("[DistributedRuntime] gRPC server error: %v", err)
+		}
+	}()
+
+	return nil
+}
+
+// Addr returns the address the server is listening on, or empty if it is
+// not serving
+func (t *GRPCTransport) Addr() string {
+	t.mu.RLock()
+	defer t.mu.RUnlock()
+	if t.listener != nil {
+		return t.listener.Addr().String()
+	}
+	return ""
+}
+
+// Close stops the server and closes remote connections
+func (t *GRPCTransport) Close() error {
+	t.mu.Lock()
+	server := t.server
+	t.server = nil
+	t.listener = nil
+	remotes := t.remotes
+	t.remotes = make(map[string]*remoteAgentClient)
+	t.mu.Unlock()
+
+	if server != nil {
+		server.GracefulStop()
+	}
+	for _, remote := range remotes {
+		_ = remote.conn.Close()
+	}
+	return nil
+}
+
+// buildDialOptions creates gRPC dial options based on TLS configuration.
+func (t *GRPCTransport) buildDialOptions() ([]grpc.DialOption, error) {
+	var opts []grpc.DialOption
+
+	// ExternalTLS means TLS is handled by service mesh (Istio, Linkerd, etc.)
+	// Use plaintext transport since the sidecar handles encryption
+	if t.tlsConfig != nil && t.tlsConfig.ExternalTLS {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		return opts, nil
+	}
+
+	if t.tlsConfig != nil && t.tlsConfig.Enabled {
+		// SECURITY: Prevent InsecureSkipVerify in production environments
+		// This enforces certificate verification when running in production
+		// Empty/unset ENVIRONMENT is treated as production (fail-safe)
+		if t.tlsConfig.InsecureSkipVerify {
+			env := strings.ToLower(os.Getenv("ENVIRONMENT"))
+			// Only allow InsecureSkipVerify in explicit non-production environments
+			allowedNonProdEnvs := map[string]bool{
+				"development": true,
+				"dev":         true,
+				"staging":     true,
+				"local":       true,
+				"test":        true,
+			}
+			if !allowedNonProdEnvs[env] {
+				return nil, fmt.Errorf("SECURITY: InsecureSkipVerify cannot be enabled in production environment (ENVIRONMENT=%q). "+
+					"Set ENVIRONMENT to 'development', 'dev', 'staging', 'local', or 'test' to allow insecure TLS", env)
+			}
+
+			// Log warning for non-production environments.
+			// #nosec G706 -- env is sanitised via security.SanitizeLogField before formatting.
+			log.Printf("[DistributedRuntime] WARNING: TLS certificate verification is disabled (InsecureSkipVerify=true). "+
+				"This is a security risk and should NEVER be used in production. "+
+				"Connections are vulnerable to man-in-the-middle attacks. "+
+				"Current ENVIRONMENT=%s", security.SanitizeLogField(env))
+		}
+
+		tlsCfg := &tls.Config{
+			MinVersion:         tls.VersionTLS12,
+			InsecureSkipVerify: t.tlsConfig.InsecureSkipVerify, // #nosec G402 -- intentionally configurable for dev/test; blocked in production by env check above
+		}
+
+		// Set server name for SNI
+		if t.tlsConfig.ServerName != "" {
+			tlsCfg.ServerName = t.tlsConfig.ServerName
+		}
+
+		// Load CA certificate if provided
+		if t.tlsConfig.CAFile != "" {
+			caPath, err := security.ResolveTLSCertPath(t.tlsConfig.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CA file path: %w", err)
+			}
+			caData, err := os.ReadFile(caPath) // #nosec G304 -- path confined to AIXGO_TLS_CERT_DIR allowlist by security.ResolveTLSCertPath
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			caPool := x509.NewCertPool()
+			if !caPool.AppendCertsFromPEM(caData) {
+				return nil, fmt.Errorf("failed to parse CA certificate")
+			}
+			tlsCfg.RootCAs = caPool
+		}
+
+		// Load client certificate for mTLS if provided
+		if t.tlsConfig.CertFile != "" && t.tlsConfig.KeyFile != "" {
+			cert, err := tls.LoadX509KeyPair(t.tlsConfig.CertFile, t.tlsConfig.KeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to load client certificate: %w", err)
+			}
+			tlsCfg.Certificates = []tls.Certificate{cert}
+		}
+
+		opts = append(opts, grpc.WithTransportCredentials(credentials.NewTLS(tlsCfg)))
+	} else {
+		opts = append(opts, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	}
+
+	return opts, nil
+}
+
+// buildServerOptions creates gRPC server options based on TLS configuration.
+func (t *GRPCTransport) buildServerOptions() ([]grpc.ServerOption, error) {
+	var opts []grpc.ServerOption
+
+	// ExternalTLS means TLS is handled by service mesh - no server-side TLS needed
+	if t.tlsConfig != nil && t.tlsConfig.ExternalTLS {
+		return opts, nil
+	}
+
+	if t.tlsConfig != nil && t.tlsConfig.Enabled {
+		// Load server certificate
+		cert, err := tls.LoadX509KeyPair(t.tlsConfig.CertFile, t.tlsConfig.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load server certificate: %w", err)
+		}
+
+		tlsCfg := &tls.Config{
+			Certificates: []tls.Certificate{cert},
+			MinVersion:   tls.VersionTLS12,
+		}
+
+		// Load CA for mTLS if provided
+		if t.tlsConfig.CAFile != "" {
+			caPath, err := security.ResolveTLSCertPath(t.tlsConfig.CAFile)
+			if err != nil {
+				return nil, fmt.Errorf("invalid CA file path: %w", err)
+			}
+			caData, err := os.ReadFile(caPath) // #nosec G304 -- path confined to AIXGO_TLS_CERT_DIR allowlist by security.ResolveTLSCertPath
+			if err != nil {
+				return nil, fmt.Errorf("failed to read CA file: %w", err)
+			}
+			caPool := x509.NewCertPool()
+			if !caPool.AppendCertsFromPEM(caData) {
+				return nil, fmt.Errorf("failed to parse CA certificate")
+			}
+			tlsCfg.ClientCAs = caPool
+			tlsCfg.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		opts = append(opts, grpc.Creds(credentials.NewTLS(tlsCfg)))
+	}
+
+	return opts, nil
+}
+
+// agentServiceServer implements the gRPC AgentService
+type agentServiceServer struct {
+	pb.UnimplementedAgentServiceServer
+	handler TransportHandler
+}
+
+func (s *agentServiceServer) Execute(ctx context.Context, req *pb.ExecuteRequest) (*pb.ExecuteResponse, error) {
+	// 1. Validate request
+	if req.AgentName == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "agent_name is required")
+	}
+	if req.Input == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "input is required")
+	}
+
+	// 2. Validate agent name format
+	if !isValidAgentName(req.AgentName) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid agent name format")
+	}
+
+	// 3. Execute with timeout
+	ctx, cancel := context.WithTimeout(ctx, remoteExecuteTimeout)
+	defer cancel()
+
+	result, err := s.handler.HandleCall(ctx, req.AgentName, &agent.Message{Message: req.Input})
+	if err != nil {
+		if errors.Is(err, ErrAgentNotFound) {
+			return nil, status.Errorf(codes.NotFound, "agent not found: %s", req.AgentName)
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			return nil, status.Errorf(codes.DeadlineExceeded, "execution timeout")
+		}
+		return nil, status.Errorf(codes.Internal, "execution failed: %v", err)
+	}
+
+	return &pb.ExecuteResponse{Output: result.Message}, nil
+}
+
+func (s *agentServiceServer) Send(ctx context.Context, req *pb.SendRequest) (*pb.SendResponse, error) {
+	// Validate
+	if req.Target == "" {
+		return nil, status.Errorf(codes.InvalidArgument, "target is required")
+	}
+	if req.Message == nil {
+		return nil, status.Errorf(codes.InvalidArgument, "message is required")
+	}
+
+	if !isValidAgentName(req.Target) {
+		return nil, status.Errorf(codes.InvalidArgument, "invalid target name")
+	}
+
+	// Send message
+	err := s.handler.HandleSend(req.Target, &agent.Message{Message: req.Message})
+	if err != nil {
+		if errors.Is(err, ErrAgentNotFound) {
+			return nil, status.Errorf(codes.NotFound, "agent not found: %s", req.Target)
+		}
+		return nil, status.Errorf(codes.Internal, "send failed: %v", err)
+	}
+
+	return &pb.SendResponse{Success: true}, nil
+}
+
+// Listen implements server-side streaming for receiving messages from an agent.
+// This allows remote clients to subscribe to messages from a local agent.
+func (s *agentServiceServer) Listen(req *pb.ListenRequest, stream pb.AgentService_ListenServer) error {
+	// Validate
+	if req.AgentName == "" {
+		return status.Errorf(codes.InvalidArgument, "agent_name is required")
+	}
+
+	if !isValidAgentName(req.AgentName) {
+		return status.Errorf(codes.InvalidArgument, "invalid agent name format")
+	}
+
+	// Get the channel for this agent
+	ch, err := s.handler.HandleSubscribe(req.AgentName)
+	if err != nil {
+		if errors.Is(err, ErrAgentNotFound) {
+			return status.Errorf(codes.NotFound, "agent not found: %s", req.AgentName)
+		}
+		return status.Errorf(codes.Internal, "failed to get channel: %v", err)
+	}
+
+	// Stream messages until context is cancelled
+	ctx := stream.Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case msg, ok := <-ch:
+			if !ok {
+				// Channel closed
+				return nil
+			}
+			if err := stream.Send(&pb.ListenResponse{Message: msg.Message}); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// agentNamePattern matches names accepted from remote nodes: lowercase
+// alphanumeric, hyphens, underscores, max 64 chars
+var agentNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]{0,63}$`)
+
+// isValidAgentName validates an agent name received from a remote node
+func isValidAgentName(name string) bool {
+	return agentNamePattern.MatchString(name)
+}
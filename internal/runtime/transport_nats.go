@@ -0,0 +1,339 @@
+package runtime
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+	"github.com/nats-io/nats.go"
+)
+
+// DefaultNATSSubjectPrefix is the subject prefix NATSTransport uses unless
+// WithNATSSubjectPrefix is set
+const DefaultNATSSubjectPrefix = "aixgo.agents"
+
+// defaultNATSRequestTimeout bounds requests whose context has no deadline
+const defaultNATSRequestTimeout = 30 * time.Second
+
+// NATSTransport is a Transport over a NATS connection. Agents are addressed
+// by name on subjects of the form <prefix>.<call|send|listen>.<agent>, so
+// remote agents need no address and any node serving an agent answers.
+//
+// Nodes that do not serve an agent ignore requests for it, so a call to an
+// agent no node serves fails when its request times out.
+type NATSTransport struct {
+	nc      *nats.Conn
+	prefix  string
+	timeout time.Duration
+	subs    []*nats.Subscription
+	cancel  context.CancelFunc
+	mu      sync.Mutex
+}
+
+// NATSOption configures a NATSTransport
+type NATSOption func(*NATSTransport)
+
+// WithNATSSubjectPrefix sets the subject prefix, letting several
+// deployments share one NATS cluster
+func WithNATSSubjectPrefix(prefix string) NATSOption {
+	return func(t *NATSTransport) {
+		if prefix != "" {
+			t.prefix = prefix
+		}
+	}
+}
+
+// WithNATSRequestTimeout sets the timeout for requests whose context has no
+// deadline. Defaults to 30 seconds.
+func WithNATSRequestTimeout(d time.Duration) NATSOption {
+	return func(t *NATSTransport) {
+		if d > 0 {
+			t.timeout = d
+		}
+	}
+}
+
+// NewNATSTransport creates a transport using nc. The caller owns nc;
+// Close does not close it.
+func NewNATSTransport(nc *nats.Conn, opts ...NATSOption) *NATSTransport {
+	t := &NATSTransport{
+		nc:      nc,
+		prefix:  DefaultNATSSubjectPrefix,
+		timeout: defaultNATSRequestTimeout,
+	}
+	for _, opt := range opts {
+		opt(t)
+	}
+	return t
+}
+
+// natsEnvelope is the JSON body of NATS requests, replies, and streamed
+// messages
+type natsEnvelope struct {
+	Message *pb.Message `json:"message,omitempty"`
+	Error   string      `json:"error,omitempty"`
+	Inbox   string      `json:"inbox,omitempty"`  // Listen: subject to stream messages to
+	Closed  bool        `json:"closed,omitempty"` // Listen: the remote channel closed
+}
+
+func (t *NATSTransport) subject(kind, name string) string {
+	return t.prefix + "." + kind + "." + name
+}
+
+// request sends env to subject and decodes the reply
+func (t *NATSTransport) request(ctx context.Context, subject, name string, env natsEnvelope) (*natsEnvelope, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.timeout)
+		defer cancel()
+	}
+
+	data, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("encode request: %w", err)
+	}
+
+	msg, err := t.nc.RequestWithContext(ctx, subject, data)
+	if errors.Is(err, nats.ErrNoResponders) {
+		return nil, fmt.Errorf("%w: %s", ErrAgentNotFound, name)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("nats request to %s: %w", name, err)
+	}
+
+	var reply natsEnvelope
+	if err := json.Unmarshal(msg.Data, &reply); err != nil {
+		return nil, fmt.Errorf("decode reply: %w", err)
+	}
+	if reply.Error != "" {
+		return nil, errors.New(reply.Error)
+	}
+	return &reply, nil
+}
+
+// Call invokes a remote agent with a NATS request
+func (t *NATSTransport) Call(ctx context.Context, target string, input *agent.Message) (*agent.Message, error) {
+	reply, err := t.request(ctx, t.subject("call", target), target, natsEnvelope{Message: input.Message})
+	if err != nil {
+		return nil, err
+	}
+	return &agent.Message{Message: reply.Message}, nil
+}
+
+// Send delivers a message with a NATS request, so delivery errors are
+// reported
+func (t *NATSTransport) Send(ctx context.Context, target string, msg *agent.Message) error {
+	_, err := t.request(ctx, t.subject("send", target), target, natsEnvelope{Message: msg.Message})
+	return err
+}
+
+// Subscribe asks the node serving source to stream its messages to a
+// private inbox
+func (t *NATSTransport) Subscribe(ctx context.Context, source string) (<-chan *agent.Message, error) {
+	inbox := t.nc.NewInbox()
+	raw := make(chan *nats.Msg, subscribeBufferSize)
+	sub, err := t.nc.ChanSubscribe(inbox, raw)
+	if err != nil {
+		return nil, fmt.Errorf("subscribe to %s: %w", source, err)
+	}
+
+	if _, err := t.request(ctx, t.subject("listen", source), source, natsEnvelope{Inbox: inbox}); err != nil {
+		_ = sub.Unsubscribe()
+		return nil, err
+	}
+
+	ch := make(chan *agent.Message, subscribeBufferSize)
+	go func() {
+		defer close(ch)
+		defer func() {
+			_ = sub.Unsubscribe()
+			_ = t.nc.Publish(inbox+".stop", nil)
+		}()
+
+		for {
+			select {
+			case m := <-raw:
+				var env natsEnvelope
+				if err := json.Unmarshal(m.Data, &env); err != nil {
+					log.Printf("[NATSTransport] Dropping malformed message from %s: %v", source, err)
+					continue
+				}
+				if env.Closed {
+					return
+				}
+				select {
+				case ch <- &agent.Message{Message: env.Message}:
+				case <-ctx.Done():
+					return
+				}
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return ch, nil
+}
+
+// Serve subscribes to requests for every agent and dispatches them to h.
+// Requests for agents h does not serve are left for other nodes.
+func (t *NATSTransport) Serve(ctx context.Context, h TransportHandler) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	t.mu.Lock()
+	t.cancel = cancel
+	t.mu.Unlock()
+
+	handlers := map[string]func(context.Context, TransportHandler, string, *nats.Msg, *natsEnvelope){
+		"call":   t.serveCall,
+		"send":   t.serveSend,
+		"listen": t.serveListen,
+	}
+	for kind, serve := range handlers {
+		sub, err := t.nc.Subscribe(t.subject(kind, "*"), func(m *nats.Msg) {
+			name := m.Subject[strings.LastIndex(m.Subject, ".")+1:]
+			if !isValidAgentName(name) {
+				return
+			}
+			var env natsEnvelope
+			if err := json.Unmarshal(m.Data, &env); err != nil {
+				t.respond(m, natsEnvelope{Error: "malformed request"})
+				return
+			}
+			go serve(ctx, h, name, m, &env)
+		})
+		if err != nil {
+			_ = t.Close()
+			return fmt.Errorf("subscribe to %s requests: %w", kind, err)
+		}
+
+		t.mu.Lock()
+		t.subs = append(t.subs, sub)
+		t.mu.Unlock()
+	}
+
+	go func() {
+		<-ctx.Done()
+		_ = t.Close()
+	}()
+	return nil
+}
+
+func (t *NATSTransport) serveCall(ctx context.Context, h TransportHandler, name string, m *nats.Msg, env *natsEnvelope) {
+	if env.Message == nil {
+		t.respond(m, natsEnvelope{Error: "message is required"})
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, remoteExecuteTimeout)
+	defer cancel()
+
+	result, err := h.HandleCall(ctx, name, &agent.Message{Message: env.Message})
+	switch {
+	case errors.Is(err, ErrAgentNotFound):
+		return // Another node may serve it
+	case err != nil:
+		t.respond(m, natsEnvelope{Error: fmt.Sprintf("execution failed: %v", err)})
+	default:
+		t.respond(m, natsEnvelope{Message: result.Message})
+	}
+}
+
+func (t *NATSTransport) serveSend(ctx context.Context, h TransportHandler, name string, m *nats.Msg, env *natsEnvelope) {
+	if env.Message == nil {
+		t.respond(m, natsEnvelope{Error: "message is required"})
+		return
+	}
+
+	err := h.HandleSend(name, &agent.Message{Message: env.Message})
+	switch {
+	case errors.Is(err, ErrAgentNotFound):
+		return
+	case err != nil:
+		t.respond(m, natsEnvelope{Error: fmt.Sprintf("send failed: %v", err)})
+	default:
+		t.respond(m, natsEnvelope{})
+	}
+}
+
+// serveListen streams source's messages to the requester's inbox until the
+// requester stops, the channel closes, or serving stops
+func (t *NATSTransport) serveListen(ctx context.Context, h TransportHandler, name string, m *nats.Msg, env *natsEnvelope) {
+	if env.Inbox == "" {
+		t.respond(m, natsEnvelope{Error: "inbox is required"})
+		return
+	}
+
+	ch, err := h.HandleSubscribe(name)
+	switch {
+	case errors.Is(err, ErrAgentNotFound):
+		return
+	case err != nil:
+		t.respond(m, natsEnvelope{Error: fmt.Sprintf("failed to get channel: %v", err)})
+		return
+	}
+
+	stop := make(chan *nats.Msg, 1)
+	stopSub, err := t.nc.ChanSubscribe(env.Inbox+".stop", stop)
+	if err != nil {
+		t.respond(m, natsEnvelope{Error: fmt.Sprintf("failed to subscribe: %v", err)})
+		return
+	}
+	defer func() { _ = stopSub.Unsubscribe() }()
+
+	t.respond(m, natsEnvelope{})
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-stop:
+			return
+		case msg, ok := <-ch:
+			if !ok {
+				t.publish(env.Inbox, natsEnvelope{Closed: true})
+				return
+			}
+			t.publish(env.Inbox, natsEnvelope{Message: msg.Message})
+		}
+	}
+}
+
+func (t *NATSTransport) respond(m *nats.Msg, env natsEnvelope) {
+	data, _ := json.Marshal(env)
+	if err := m.Respond(data); err != nil {
+		log.Printf("[NATSTransport] Failed to respond on %s: %v", m.Subject, err)
+	}
+}
+
+func (t *NATSTransport) publish(subject string, env natsEnvelope) {
+	data, _ := json.Marshal(env)
+	if err := t.nc.Publish(subject, data); err != nil {
+		log.Printf("[NATSTransport] Failed to publish to %s: %v", subject, err)
+	}
+}
+
+// Close stops serving. The NATS connection is left open.
+func (t *NATSTransport) Close() error {
+	t.mu.Lock()
+	subs := t.subs
+	t.subs = nil
+	cancel := t.cancel
+	t.cancel = nil
+	t.mu.Unlock()
+
+	if cancel != nil {
+		cancel()
+	}
+	for _, sub := range subs {
+		_ = sub.Unsubscribe()
+	}
+	return nil
+}
@@ -0,0 +1,35 @@
+//go:build integration
+
+package runtime
+
+import (
+	"os"
+	"testing"
+
+	"github.com/nats-io/nats.go"
+)
+
+// TestNATSTransport runs the transport checks against a NATS server. Set
+// NATS_TEST_URL to a running server, e.g.
+//
+//	docker run -d -p 4222:4222 nats:2
+//	export NATS_TEST_URL=nats://localhost:4222
+func TestNATSTransport(t *testing.T) {
+	url := os.Getenv("NATS_TEST_URL")
+	if url == "" {
+		t.Skip("NATS_TEST_URL not set")
+	}
+
+	nc, err := nats.Connect(url)
+	if err != nil {
+		t.Fatalf("nats.Connect() error = %v", err)
+	}
+	t.Cleanup(nc.Close)
+
+	prefix := "aixgo-test." + nats.NewInbox()[len("_INBOX."):]
+	server, client := startNodes(t,
+		func(bool) Transport { return NewNATSTransport(nc, WithNATSSubjectPrefix(prefix)) },
+		func(*DistributedRuntime) string { return "" },
+	)
+	testTransport(t, server, client)
+}
@@ -0,0 +1,147 @@
+package runtime
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// echoAgent replies with its input payload prefixed by its name
+type echoAgent struct {
+	name string
+}
+
+func (a *echoAgent) Name() string                    { return a.name }
+func (a *echoAgent) Role() string                    { return "echo" }
+func (a *echoAgent) Start(ctx context.Context) error { return nil }
+func (a *echoAgent) Stop(ctx context.Context) error  { return nil }
+func (a *echoAgent) Ready() bool                     { return true }
+
+func (a *echoAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return &agent.Message{Message: &pb.Message{
+		Type:     "echo",
+		Payload:  a.name + ": " + input.Payload,
+		Metadata: map[string]any{"node": a.name},
+	}}, nil
+}
+
+func textMessage(payload string) *agent.Message {
+	return &agent.Message{Message: &pb.Message{Type: "text", Payload: payload}}
+}
+
+// startNodes starts a server node hosting the "echo" agent and a client
+// node that reaches it over transports built by newTransport
+func startNodes(t *testing.T, newTransport func(server bool) Transport, serverAddr func(*DistributedRuntime) string) (server, client *DistributedRuntime) {
+	t.Helper()
+	ctx := context.Background()
+
+	server = NewDistributedRuntime("", WithTransport(newTransport(true)))
+	if err := server.Register(&echoAgent{name: "echo"}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := server.Start(ctx); err != nil {
+		t.Fatalf("server Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = server.Stop(ctx) })
+
+	client = NewDistributedRuntime("", WithTransport(newTransport(false)))
+	if err := client.Start(ctx); err != nil {
+		t.Fatalf("client Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = client.Stop(ctx) })
+
+	if err := client.Connect("echo", serverAddr(server)); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	return server, client
+}
+
+// testTransport checks Call, Send, and Subscribe between two nodes
+func testTransport(t *testing.T, server, client *DistributedRuntime) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	result, err := client.Call(ctx, "echo", textMessage("hello"))
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if result.Payload != "echo: hello" || result.Metadata["node"] != "echo" {
+		t.Errorf("Call() = %+v", result.Message)
+	}
+
+	// Messages sent through the client land on the server's local channel
+	if err := client.Send("echo", textMessage("queued")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	local, err := server.Recv("echo")
+	if err != nil {
+		t.Fatalf("Recv() error = %v", err)
+	}
+	select {
+	case msg := <-local:
+		if msg.Payload != "queued" {
+			t.Errorf("server received %q, want %q", msg.Payload, "queued")
+		}
+	case <-ctx.Done():
+		t.Fatal("server did not receive sent message")
+	}
+
+	// Subscribing from the client streams the server's channel
+	remote, err := client.Recv("echo")
+	if err != nil {
+		t.Fatalf("remote Recv() error = %v", err)
+	}
+	if err := server.Send("echo", textMessage("streamed")); err != nil {
+		t.Fatalf("Send() error = %v", err)
+	}
+	select {
+	case msg := <-remote:
+		if msg.Payload != "streamed" {
+			t.Errorf("client received %q, want %q", msg.Payload, "streamed")
+		}
+	case <-ctx.Done():
+		t.Fatal("client did not receive streamed message")
+	}
+}
+
+func TestMemoryTransport(t *testing.T) {
+	network := NewMemoryNetwork()
+	server, client := startNodes(t,
+		func(bool) Transport { return NewMemoryTransport(network) },
+		func(*DistributedRuntime) string { return "" },
+	)
+	testTransport(t, server, client)
+
+	// A remote name no node serves is reported as not found
+	if err := client.Connect("missing", ""); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := client.Call(context.Background(), "missing", textMessage("hi")); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Call() error = %v, want ErrAgentNotFound", err)
+	}
+}
+
+func TestGRPCTransport(t *testing.T) {
+	server, client := startNodes(t,
+		func(server bool) Transport {
+			if server {
+				return NewGRPCTransport("127.0.0.1:0", nil)
+			}
+			return NewGRPCTransport("", nil)
+		},
+		func(r *DistributedRuntime) string { return r.ListenAddr() },
+	)
+	testTransport(t, server, client)
+
+	// The server only serves its local agents
+	if err := client.Connect("missing", server.ListenAddr()); err != nil {
+		t.Fatalf("Connect() error = %v", err)
+	}
+	if _, err := client.Call(context.Background(), "missing", textMessage("hi")); !errors.Is(err, ErrAgentNotFound) {
+		t.Errorf("Call() error = %v, want ErrAgentNotFound", err)
+	}
+}
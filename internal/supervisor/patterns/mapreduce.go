@@ -2,6 +2,7 @@ package patterns
 
 import (
 	"context"
+	"fmt"
 	"sync"
 	"time"
 )
@@ -18,6 +19,22 @@ type MapReduceConfig struct {
 	Reducer          Reducer       // Function to reduce results
 	ConcurrencyLimit int           // Max concurrent map operations
 	Timeout          time.Duration // Timeout per map operation
+
+	// ReduceFanIn is the most results a single Reducer call receives. When
+	// there are more, results are reduced in tiers: consecutive groups of
+	// ReduceFanIn are reduced, then the group outputs, until one remains.
+	// Zero or one reduces all results in a single pass.
+	ReduceFanIn int
+	// ReduceConcurrency is the max concurrent Reducer calls within a tier.
+	// Zero is unlimited.
+	ReduceConcurrency int
+}
+
+// MapReduceResult is the outcome of a map-reduce run
+type MapReduceResult struct {
+	Output      string            // Final reduced output
+	Results     []ExecutionResult // Map results in chunk order
+	ReduceDepth int               // Number of reduce tiers, 0 if nothing was reduced
 }
 
 // MapReducePattern implements map-reduce execution
@@ -26,16 +43,41 @@ type MapReducePattern struct {
 	executor AgentExecutor
 }
 
+// MapReduceOption adjusts a MapReducePattern's config
+type MapReduceOption func(*MapReducePattern)
+
+// WithReduceFanIn sets ReduceFanIn, the most results a single Reducer call
+// receives. Values <= 0 are ignored.
+func WithReduceFanIn(n int) MapReduceOption {
+	return func(m *MapReducePattern) {
+		if n > 0 {
+			m.config.ReduceFanIn = n
+		}
+	}
+}
+
 // NewMapReducePattern creates a new map-reduce pattern
-func NewMapReducePattern(executor AgentExecutor, config MapReduceConfig) *MapReducePattern {
-	return &MapReducePattern{
+func NewMapReducePattern(executor AgentExecutor, config MapReduceConfig, opts ...MapReduceOption) *MapReducePattern {
+	m := &MapReducePattern{
 		config:   config,
 		executor: executor,
 	}
+	for _, opt := range opts {
+		opt(m)
+	}
+	return m
 }
 
 // Execute runs the map-reduce pattern
 func (m *MapReducePattern) Execute(ctx context.Context, agentName, input string) (string, []ExecutionResult, error) {
+	res, err := m.Run(ctx, agentName, input)
+	return res.Output, res.Results, err
+}
+
+// Run runs the map-reduce pattern and reports the reduce tree depth. Map
+// results are reduced in chunk order, so output is reproducible for an
+// order-sensitive Reducer.
+func (m *MapReducePattern) Run(ctx context.Context, agentName, input string) (*MapReduceResult, error) {
 	// Split phase
 	var chunks []string
 	if m.config.Splitter != nil {
@@ -45,26 +87,97 @@ func (m *MapReducePattern) Execute(ctx context.Context, agentName, input string)
 	}
 
 	if len(chunks) == 0 {
-		return "", nil, nil
+		return &MapReduceResult{}, nil
 	}
 
 	// Map phase - parallel execution
 	results := m.mapPhase(ctx, agentName, chunks)
 
 	// Reduce phase
-	var output string
-	var err error
-	if m.config.Reducer != nil {
-		output, err = m.config.Reducer(results)
-	} else {
-		output = defaultReduce(results)
+	output, depth, err := m.reducePhase(ctx, agentName, results)
+	return &MapReduceResult{Output: output, Results: results, ReduceDepth: depth}, err
+}
+
+// reducePhase reduces results in tiers of at most ReduceFanIn and returns
+// the output and the number of tiers
+func (m *MapReducePattern) reducePhase(ctx context.Context, agentName string, results []ExecutionResult) (string, int, error) {
+	fanIn := m.config.ReduceFanIn
+	if fanIn < 2 {
+		fanIn = len(results)
+	}
+
+	tier := results
+	for depth := 1; ; depth++ {
+		if len(tier) <= fanIn {
+			output, err := m.reduce(tier)
+			return output, depth, err
+		}
+
+		if err := ctx.Err(); err != nil {
+			return "", depth - 1, err
+		}
+
+		next, err := m.reduceTier(agentName, tier, fanIn)
+		if err != nil {
+			return "", depth, fmt.Errorf("reduce tier %d: %w", depth, err)
+		}
+		tier = next
+	}
+}
+
+// reduceTier reduces consecutive groups of fanIn results, keeping group order
+func (m *MapReducePattern) reduceTier(agentName string, tier []ExecutionResult, fanIn int) ([]ExecutionResult, error) {
+	groups := (len(tier) + fanIn - 1) / fanIn
+	next := make([]ExecutionResult, groups)
+	errs := make([]error, groups)
+
+	var sem chan struct{}
+	if m.config.ReduceConcurrency > 0 {
+		sem = make(chan struct{}, m.config.ReduceConcurrency)
 	}
 
-	return output, results, err
+	var wg sync.WaitGroup
+	for g := 0; g < groups; g++ {
+		group := tier[g*fanIn : min((g+1)*fanIn, len(tier))]
+
+		wg.Add(1)
+		go func(idx int, group []ExecutionResult) {
+			defer wg.Done()
+			if sem != nil {
+				sem <- struct{}{}
+				defer func() { <-sem }()
+			}
+
+			start := time.Now()
+			output, err := m.reduce(group)
+			next[idx] = ExecutionResult{
+				AgentName: agentName,
+				Output:    output,
+				Duration:  time.Since(start).Milliseconds(),
+			}
+			errs[idx] = err
+		}(g, group)
+	}
+	wg.Wait()
+
+	for g, err := range errs {
+		if err != nil {
+			return nil, fmt.Errorf("group %d: %w", g, err)
+		}
+	}
+	return next, nil
 }
 
+func (m *MapReducePattern) reduce(results []ExecutionResult) (string, error) {
+	if m.config.Reducer != nil {
+		return m.config.Reducer(results)
+	}
+	return defaultReduce(results), nil
+}
+
+// mapPhase executes every chunk and returns the results in chunk order
 func (m *MapReducePattern) mapPhase(ctx context.Context, agentName string, chunks []string) []ExecutionResult {
-	resultsCh := make(chan ExecutionResult, len(chunks))
+	results := make([]ExecutionResult, len(chunks))
 	var wg sync.WaitGroup
 
 	// Create semaphore for concurrency limit
@@ -84,7 +197,7 @@ func (m *MapReducePattern) mapPhase(ctx context.Context, agentName string, chunk
 				case sem <- struct{}{}:
 					defer func() { <-sem }()
 				case <-ctx.Done():
-					resultsCh <- ExecutionResult{
+					results[idx] = ExecutionResult{
 						AgentName: agentName,
 						Error:     ctx.Err(),
 					}
@@ -102,7 +215,7 @@ func (m *MapReducePattern) mapPhase(ctx context.Context, agentName string, chunk
 
 			start := time.Now()
 			output, err := m.executor(execCtx, agentName, data)
-			resultsCh <- ExecutionResult{
+			results[idx] = ExecutionResult{
 				AgentName: agentName,
 				Output:    output,
 				Error:     err,
@@ -112,12 +225,6 @@ func (m *MapReducePattern) mapPhase(ctx context.Context, agentName string, chunk
 	}
 
 	wg.Wait()
-	close(resultsCh)
-
-	var results []ExecutionResult
-	for r := range resultsCh {
-		results = append(results, r)
-	}
 	return results
 }
 
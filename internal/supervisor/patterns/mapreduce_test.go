@@ -3,6 +3,7 @@ package patterns
 import (
 	"context"
 	"errors"
+	"math/rand/v2"
 	"strings"
 	"sync/atomic"
 	"testing"
@@ -185,3 +186,112 @@ func TestMapReducePattern_EmptyInput(t *testing.T) {
 		t.Errorf("expected empty output, got %s", output)
 	}
 }
+
+func TestMapReducePattern_TreeReduce(t *testing.T) {
+	// Random map latency must not change the reduce order
+	executor := func(ctx context.Context, name, input string) (string, error) {
+		time.Sleep(time.Duration(rand.IntN(5)) * time.Millisecond)
+		return input, nil
+	}
+
+	var maxGroup int32
+	reducer := func(results []ExecutionResult) (string, error) {
+		if n := int32(len(results)); n > atomic.LoadInt32(&maxGroup) {
+			atomic.StoreInt32(&maxGroup, n)
+		}
+		outputs := make([]string, len(results))
+		for i, r := range results {
+			outputs[i] = r.Output
+		}
+		return "(" + strings.Join(outputs, " ") + ")", nil
+	}
+
+	config := MapReduceConfig{
+		Splitter: func(input string) []string { return strings.Split(input, ",") },
+		Reducer:  reducer,
+	}
+	m := NewMapReducePattern(executor, config, WithReduceFanIn(3))
+
+	// 10 results reduce to 4, then 2, then the final output
+	want := "(((0 1 2) (3 4 5) (6 7 8)) ((9)))"
+	for range 5 {
+		res, err := m.Run(context.Background(), "agent1", "0,1,2,3,4,5,6,7,8,9")
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if res.Output != want {
+			t.Fatalf("expected %s, got %s", want, res.Output)
+		}
+		if res.ReduceDepth != 3 {
+			t.Errorf("expected reduce depth 3, got %d", res.ReduceDepth)
+		}
+		if len(res.Results) != 10 || res.Results[9].Output != "9" {
+			t.Errorf("expected map results in chunk order, got %v", res.Results)
+		}
+	}
+	if maxGroup > 3 {
+		t.Errorf("reducer received %d results, exceeding fan-in 3", maxGroup)
+	}
+
+	// Without a fan-in everything reduces in one pass
+	m = NewMapReducePattern(executor, config)
+	res, err := m.Run(context.Background(), "agent1", "0,1,2,3")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if res.Output != "(0 1 2 3)" || res.ReduceDepth != 1 {
+		t.Errorf("expected single pass, got %s at depth %d", res.Output, res.ReduceDepth)
+	}
+}
+
+func TestMapReducePattern_ReduceConcurrency(t *testing.T) {
+	executor := func(ctx context.Context, name, input string) (string, error) {
+		return input, nil
+	}
+
+	var current, maxConcurrent int32
+	reducer := func(results []ExecutionResult) (string, error) {
+		c := atomic.AddInt32(&current, 1)
+		if c > atomic.LoadInt32(&maxConcurrent) {
+			atomic.StoreInt32(&maxConcurrent, c)
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return results[0].Output, nil
+	}
+
+	m := NewMapReducePattern(executor, MapReduceConfig{
+		Splitter:          func(input string) []string { return strings.Split(input, ",") },
+		Reducer:           reducer,
+		ReduceConcurrency: 2,
+	}, WithReduceFanIn(2))
+
+	if _, err := m.Run(context.Background(), "agent1", "a,b,c,d,e,f,g,h"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if maxConcurrent > 2 {
+		t.Errorf("expected at most 2 concurrent reduces, got %d", maxConcurrent)
+	}
+}
+
+func TestMapReducePattern_TreeReduceError(t *testing.T) {
+	executor := func(ctx context.Context, name, input string) (string, error) {
+		return input, nil
+	}
+
+	reduceErr := errors.New("context overflow")
+	m := NewMapReducePattern(executor, MapReduceConfig{
+		Splitter: func(input string) []string { return strings.Split(input, ",") },
+		Reducer: func(results []ExecutionResult) (string, error) {
+			if results[0].Output == "c" {
+				return "", reduceErr
+			}
+			return results[0].Output, nil
+		},
+	}, WithReduceFanIn(2))
+
+	_, _, err := m.Execute(context.Background(), "agent1", "a,b,c,d")
+	if !errors.Is(err, reduceErr) {
+		t.Errorf("expected reducer error, got %v", err)
+	}
+}
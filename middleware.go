@@ -0,0 +1,168 @@
+package aixgo
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"log"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/cache"
+	"github.com/aixgo-dev/aixgo/pkg/observability"
+	"github.com/aixgo-dev/aixgo/pkg/session"
+)
+
+// ExecuteFunc executes an agent with input, like agent.Agent's Execute
+type ExecuteFunc func(ctx context.Context, input *agent.Message) (*agent.Message, error)
+
+// AgentMiddleware wraps agent execution with a cross-cutting concern such as
+// logging, metrics, auth, or caching. It may call next, or return without
+// calling it to short-circuit the agent.
+type AgentMiddleware func(next ExecuteFunc) ExecuteFunc
+
+// MetadataKeyCacheHit is set to true on responses served by CacheMiddleware
+const MetadataKeyCacheHit = "cache_hit"
+
+type agentNameKey struct{}
+
+// AgentNameFromContext returns the name of the agent being executed, as set
+// by the runtime for its middleware.
+func AgentNameFromContext(ctx context.Context) string {
+	name, _ := ctx.Value(agentNameKey{}).(string)
+	return name
+}
+
+// Use appends middleware that wraps the execution of every registered agent
+// called through the runtime. Middleware runs in the order it was added: the
+// first is outermost and sees each call first.
+//
+// Example:
+//
+//	rt.Use(aixgo.LoggingMiddleware(nil), aixgo.MetricsMiddleware())
+func (r *Runtime) Use(mw ...AgentMiddleware) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.middleware = append(r.middleware, mw...)
+}
+
+// execute runs a through the middleware chain
+func (r *Runtime) execute(ctx context.Context, a agent.Agent, input *agent.Message) (*agent.Message, error) {
+	r.mu.RLock()
+	chain := r.middleware
+	r.mu.RUnlock()
+
+	exec := ExecuteFunc(a.Execute)
+	for i := len(chain) - 1; i >= 0; i-- {
+		exec = chain[i](exec)
+	}
+	return exec(context.WithValue(ctx, agentNameKey{}, a.Name()), input)
+}
+
+// LoggingMiddleware logs each agent call with its duration and outcome to
+// logger, or to the standard logger if nil.
+func LoggingMiddleware(logger *log.Logger) AgentMiddleware {
+	if logger == nil {
+		logger = log.Default()
+	}
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+			start := time.Now()
+			result, err := next(ctx, input)
+			if err != nil {
+				logger.Printf("[Runtime] Agent %s failed after %v: %v", AgentNameFromContext(ctx), time.Since(start), err)
+			} else {
+				logger.Printf("[Runtime] Agent %s completed in %v", AgentNameFromContext(ctx), time.Since(start))
+			}
+			return result, err
+		}
+	}
+}
+
+// MetricsMiddleware records each agent call's outcome and latency in the
+// Prometheus agent metrics (see observability.RecordAgentCall).
+func MetricsMiddleware() AgentMiddleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+			start := time.Now()
+			result, err := next(ctx, input)
+			observability.RecordAgentCall(AgentNameFromContext(ctx), time.Since(start), 0, 0, err)
+			return result, err
+		}
+	}
+}
+
+// ResponseCache stores agent responses for CacheMiddleware. Implementations
+// must be safe for concurrent use.
+type ResponseCache interface {
+	// Get returns the response stored under key, or false if there is none
+	Get(key string) (*agent.Message, bool)
+
+	// Set stores response under key
+	Set(key string, response *agent.Message)
+}
+
+// CacheMiddleware serves repeated inputs from cache instead of executing the
+// agent. Responses are keyed by agent name, session (under CallWithSession)
+// and input payload; failed calls are not cached. Cached responses carry
+// MetadataKeyCacheHit.
+func CacheMiddleware(cache ResponseCache) AgentMiddleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+			key := responseCacheKey(ctx, input)
+			if cached, ok := cache.Get(key); ok {
+				hit := cached.Clone()
+				if hit.Metadata == nil {
+					hit.Metadata = make(map[string]any)
+				}
+				hit.Metadata[MetadataKeyCacheHit] = true
+				return hit, nil
+			}
+
+			result, err := next(ctx, input)
+			if err == nil && result != nil {
+				cache.Set(key, result.Clone())
+			}
+			return result, err
+		}
+	}
+}
+
+// responseCacheKey returns the agent name and the hex SHA-256 of the
+// session ID and payload, so one session's answers are never served to
+// another
+func responseCacheKey(ctx context.Context, input *agent.Message) string {
+	h := sha256.New()
+	if sess, ok := session.SessionFromContext(ctx); ok {
+		h.Write([]byte(sess.ID()))
+	}
+	h.Write([]byte{0})
+	if input != nil && input.Message != nil {
+		h.Write([]byte(input.Payload))
+	}
+	return AgentNameFromContext(ctx) + ":" + hex.EncodeToString(h.Sum(nil))
+}
+
+// MemoryResponseCache is an in-process ResponseCache that holds a bounded
+// number of responses, evicting the least recently used, and expires them
+// after a TTL.
+type MemoryResponseCache struct {
+	entries *cache.LRU[string, *agent.Message]
+}
+
+// NewMemoryResponseCache creates a MemoryResponseCache holding up to
+// maxEntries responses (0 = unlimited) that expire after ttl (0 = never).
+func NewMemoryResponseCache(maxEntries int, ttl time.Duration) *MemoryResponseCache {
+	return &MemoryResponseCache{entries: cache.New[string, *agent.Message](maxEntries, ttl)}
+}
+
+// Get returns the unexpired response stored under key.
+func (c *MemoryResponseCache) Get(key string) (*agent.Message, bool) {
+	response, ok, _ := c.entries.Get(context.Background(), key)
+	return response, ok
+}
+
+// Set stores response under key.
+func (c *MemoryResponseCache) Set(key string, response *agent.Message) {
+	_ = c.entries.Set(context.Background(), key, response)
+}
@@ -0,0 +1,159 @@
+package aixgo
+
+import (
+	"bytes"
+	"context"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/pkg/session"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// recordingMiddleware appends name to calls before and after the agent runs
+func recordingMiddleware(name string, calls *[]string) AgentMiddleware {
+	return func(next ExecuteFunc) ExecuteFunc {
+		return func(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+			*calls = append(*calls, name+":"+AgentNameFromContext(ctx))
+			result, err := next(ctx, input)
+			*calls = append(*calls, name+":done")
+			return result, err
+		}
+	}
+}
+
+func startedRuntime(t *testing.T, a agent.Agent) *Runtime {
+	t.Helper()
+	ctx := context.Background()
+	rt := NewRuntime()
+	if err := rt.Register(a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	t.Cleanup(func() { _ = rt.Stop(ctx) })
+	return rt
+}
+
+func TestRuntime_MiddlewareOrder(t *testing.T) {
+	rt := startedRuntime(t, &flakyAgent{})
+	var calls []string
+	rt.Use(recordingMiddleware("first", &calls))
+	rt.Use(recordingMiddleware("second", &calls))
+
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	if _, err := rt.Call(context.Background(), "flaky", input); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+
+	want := []string{"first:flaky", "second:flaky", "second:done", "first:done"}
+	if strings.Join(calls, ",") != strings.Join(want, ",") {
+		t.Errorf("middleware calls = %v, want %v", calls, want)
+	}
+}
+
+func TestRuntime_CacheMiddleware(t *testing.T) {
+	flaky := &flakyAgent{}
+	rt := startedRuntime(t, flaky)
+	var calls []string
+	rt.Use(CacheMiddleware(NewMemoryResponseCache(100, 0)), recordingMiddleware("inner", &calls))
+
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	first, err := rt.Call(ctx, "flaky", input)
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if first.Metadata[MetadataKeyCacheHit] != nil {
+		t.Error("first call should not be a cache hit")
+	}
+
+	// A hit short-circuits the rest of the chain and the agent
+	second, err := rt.Call(ctx, "flaky", input)
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if second.Payload != "ok" || second.Metadata[MetadataKeyCacheHit] != true {
+		t.Errorf("second call = %q %v, want cached ok", second.Payload, second.Metadata)
+	}
+	if n := flaky.calls.Load(); n != 1 || len(calls) != 2 {
+		t.Errorf("agent executed %d times and inner middleware ran %d times, want 1 each", n, len(calls)/2)
+	}
+
+	// Failures are not cached
+	flaky.failing.Store(true)
+	other := &agent.Message{Message: &pb.Message{Payload: "other"}}
+	for range 2 {
+		if _, err := rt.Call(ctx, "flaky", other); err == nil {
+			t.Fatal("Call() error = nil, want the agent's failure")
+		}
+	}
+	if n := flaky.calls.Load(); n != 3 {
+		t.Errorf("agent executed %d times, want failed calls to reach it", n)
+	}
+}
+
+func TestCacheMiddlewarePerSession(t *testing.T) {
+	backend, err := session.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr := session.NewManager(backend)
+
+	var calls int
+	exec := CacheMiddleware(NewMemoryResponseCache(100, 0))(func(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+		calls++
+		sess, _ := session.SessionFromContext(ctx)
+		return &agent.Message{Message: &pb.Message{Payload: "answer for " + sess.ID()}}, nil
+	})
+
+	ctx := context.Background()
+	input := &agent.Message{Message: &pb.Message{Payload: "what did I say earlier?"}}
+	var answers []string
+	for _, user := range []string{"alice", "bob", "alice"} {
+		sess, err := mgr.GetOrCreate(ctx, "agent", user)
+		if err != nil {
+			t.Fatalf("GetOrCreate() error = %v", err)
+		}
+		result, err := exec(session.ContextWithSession(ctx, sess), input)
+		if err != nil {
+			t.Fatalf("exec() error = %v", err)
+		}
+		answers = append(answers, result.Payload)
+	}
+
+	if calls != 2 || answers[1] == answers[0] || answers[2] != answers[0] {
+		t.Errorf("agent ran %d times with answers %v, want each session cached separately", calls, answers)
+	}
+}
+
+func TestMemoryResponseCacheBounded(t *testing.T) {
+	c := NewMemoryResponseCache(2, 0)
+	for _, key := range []string{"a", "b", "c"} {
+		c.Set(key, &agent.Message{Message: &pb.Message{Payload: key}})
+	}
+	if _, ok := c.Get("a"); ok {
+		t.Error("oldest response was not evicted")
+	}
+	if got, ok := c.Get("c"); !ok || got.Payload != "c" {
+		t.Errorf("Get(c) = %v, %v, want c", got, ok)
+	}
+}
+
+func TestLoggingMiddleware(t *testing.T) {
+	rt := startedRuntime(t, &flakyAgent{})
+	var buf bytes.Buffer
+	rt.Use(LoggingMiddleware(log.New(&buf, "", 0)), MetricsMiddleware())
+
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	if _, err := rt.Call(context.Background(), "flaky", input); err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if !strings.Contains(buf.String(), "Agent flaky completed") {
+		t.Errorf("log = %q, want the completed call", buf.String())
+	}
+}
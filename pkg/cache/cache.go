@@ -0,0 +1,213 @@
+// Package cache provides a generic key-value cache interface and a
+// thread-safe in-memory implementation with LRU eviction and per-entry TTLs.
+//
+// Cache takes a context and returns errors so a shared store such as Redis
+// can implement it; the in-memory LRU never returns an error.
+package cache
+
+import (
+	"container/list"
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Cache stores values by key. Implementations must be safe for concurrent use.
+type Cache[K comparable, V any] interface {
+	// Get returns the value stored under key, or false if there is none or
+	// it has expired
+	Get(ctx context.Context, key K) (V, bool, error)
+
+	// Set stores value under key with the cache's default TTL
+	Set(ctx context.Context, key K, value V) error
+
+	// SetWithTTL stores value under key, expiring after ttl (0 = never)
+	SetWithTTL(ctx context.Context, key K, value V, ttl time.Duration) error
+
+	// Delete removes key. Deleting a missing key is not an error.
+	Delete(ctx context.Context, key K) error
+}
+
+// LRU is an in-memory Cache that evicts the least recently used entry once
+// it holds maxEntries values. Expired entries are removed when read or
+// evicted.
+type LRU[K comparable, V any] struct {
+	mu         sync.Mutex
+	maxEntries int
+	defaultTTL time.Duration
+	order      *list.List // Front is most recently used
+	entries    map[K]*list.Element
+
+	// now is replaced in tests
+	now func() time.Time
+}
+
+var _ Cache[string, string] = (*LRU[string, string])(nil)
+
+type lruEntry[K comparable, V any] struct {
+	key       K
+	value     V
+	expiresAt time.Time // zero = never
+	tags      []string
+}
+
+// New creates an LRU holding up to maxEntries values (0 = unlimited) that
+// expire after defaultTTL (0 = never) unless set with SetWithTTL.
+//
+// Example:
+//
+//	responses := cache.New[string, string](10000, 5*time.Minute)
+func New[K comparable, V any](maxEntries int, defaultTTL time.Duration) *LRU[K, V] {
+	return &LRU[K, V]{
+		maxEntries: maxEntries,
+		defaultTTL: defaultTTL,
+		order:      list.New(),
+		entries:    make(map[K]*list.Element),
+		now:        time.Now,
+	}
+}
+
+// Get returns the value stored under key and marks it most recently used.
+func (c *LRU[K, V]) Get(ctx context.Context, key K) (V, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var zero V
+	el, ok := c.entries[key]
+	if !ok {
+		return zero, false, nil
+	}
+	entry := el.Value.(*lruEntry[K, V])
+	if c.expired(entry) {
+		c.removeElement(el)
+		return zero, false, nil
+	}
+	c.order.MoveToFront(el)
+	return entry.value, true, nil
+}
+
+// Set stores value under key with the default TTL.
+func (c *LRU[K, V]) Set(ctx context.Context, key K, value V) error {
+	return c.SetWithTTL(ctx, key, value, c.defaultTTL)
+}
+
+// SetWithTTL stores value under key, expiring after ttl (0 = never), and
+// evicts the least recently used entry if the cache is full.
+func (c *LRU[K, V]) SetWithTTL(ctx context.Context, key K, value V, ttl time.Duration) error {
+	return c.SetWithTags(ctx, key, value, ttl)
+}
+
+// SetWithTags is SetWithTTL that also labels the entry with tags, replacing
+// any it had, so it can be removed with InvalidateByTag.
+func (c *LRU[K, V]) SetWithTags(ctx context.Context, key K, value V, ttl time.Duration, tags ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expiresAt time.Time
+	if ttl > 0 {
+		expiresAt = c.now().Add(ttl)
+	}
+
+	if el, ok := c.entries[key]; ok {
+		entry := el.Value.(*lruEntry[K, V])
+		entry.value = value
+		entry.expiresAt = expiresAt
+		entry.tags = tags
+		c.order.MoveToFront(el)
+		return nil
+	}
+
+	c.entries[key] = c.order.PushFront(&lruEntry[K, V]{key: key, value: value, expiresAt: expiresAt, tags: tags})
+	if c.maxEntries > 0 && c.order.Len() > c.maxEntries {
+		c.removeElement(c.order.Back())
+	}
+	return nil
+}
+
+// Delete removes key.
+func (c *LRU[K, V]) Delete(ctx context.Context, key K) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.entries[key]; ok {
+		c.removeElement(el)
+	}
+	return nil
+}
+
+// DeleteFunc removes every unexpired entry for which match returns true and
+// returns the number removed. Expired entries are dropped without calling
+// match. match must not call methods on c.
+func (c *LRU[K, V]) DeleteFunc(match func(key K, value V) bool) int {
+	return c.deleteWhere(func(entry *lruEntry[K, V]) bool {
+		return match(entry.key, entry.value)
+	})
+}
+
+// InvalidateByTag removes every entry stored with tag by SetWithTags and
+// returns the number removed, e.g. to drop answers derived from a
+// knowledge-base version that has since been re-indexed. The context and
+// error leave room for a shared store, which would scan remotely.
+func (c *LRU[K, V]) InvalidateByTag(ctx context.Context, tag string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.deleteWhere(func(entry *lruEntry[K, V]) bool {
+		return slices.Contains(entry.tags, tag)
+	}), nil
+}
+
+// InvalidateByPrefix removes every entry of c whose key starts with prefix
+// and returns the number removed. It is a function rather than a method
+// because it needs string keys.
+func InvalidateByPrefix[V any](ctx context.Context, c *LRU[string, V], prefix string) (int, error) {
+	if err := ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.DeleteFunc(func(key string, _ V) bool {
+		return strings.HasPrefix(key, prefix)
+	}), nil
+}
+
+// deleteWhere removes every unexpired entry matching match, dropping expired
+// entries along the way, and returns the number of matches removed
+func (c *LRU[K, V]) deleteWhere(match func(entry *lruEntry[K, V]) bool) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	removed := 0
+	for el := c.order.Front(); el != nil; {
+		next := el.Next()
+		entry := el.Value.(*lruEntry[K, V])
+		switch {
+		case c.expired(entry):
+			c.removeElement(el)
+		case match(entry):
+			c.removeElement(el)
+			removed++
+		}
+		el = next
+	}
+	return removed
+}
+
+// Len returns the number of cached entries, including expired entries that
+// have not been removed yet.
+func (c *LRU[K, V]) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// expired reports whether entry's TTL has passed. Caller must hold mu.
+func (c *LRU[K, V]) expired(entry *lruEntry[K, V]) bool {
+	return !entry.expiresAt.IsZero() && !c.now().Before(entry.expiresAt)
+}
+
+// removeElement removes el from the list and index. Caller must hold mu.
+func (c *LRU[K, V]) removeElement(el *list.Element) {
+	c.order.Remove(el)
+	delete(c.entries, el.Value.(*lruEntry[K, V]).key)
+}
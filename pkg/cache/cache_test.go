@@ -0,0 +1,217 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock returns a controllable time source for TTL tests
+type fakeClock struct {
+	mu  sync.Mutex
+	now time.Time
+}
+
+func (f *fakeClock) Now() time.Time {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.now
+}
+
+func (f *fakeClock) Advance(d time.Duration) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.now = f.now.Add(d)
+}
+
+func newTestLRU(maxEntries int, defaultTTL time.Duration) (*LRU[string, int], *fakeClock) {
+	clock := &fakeClock{now: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)}
+	c := New[string, int](maxEntries, defaultTTL)
+	c.now = clock.Now
+	return c, clock
+}
+
+func mustGet(t *testing.T, c *LRU[string, int], key string) (int, bool) {
+	t.Helper()
+	v, ok, err := c.Get(context.Background(), key)
+	if err != nil {
+		t.Fatalf("Get(%q) error = %v", key, err)
+	}
+	return v, ok
+}
+
+func TestLRUEvictionOrder(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestLRU(3, 0)
+
+	for i, key := range []string{"a", "b", "c"} {
+		_ = c.Set(ctx, key, i)
+	}
+
+	// Reading "a" makes "b" the least recently used
+	if v, ok := mustGet(t, c, "a"); !ok || v != 0 {
+		t.Fatalf("Get(a) = %d, %v, want 0, true", v, ok)
+	}
+	_ = c.Set(ctx, "d", 3)
+	if _, ok := mustGet(t, c, "b"); ok {
+		t.Error("b was not evicted")
+	}
+
+	// Overwriting "c" refreshes it, so "a" goes next
+	_ = c.Set(ctx, "c", 20)
+	_ = c.Set(ctx, "e", 4)
+	if _, ok := mustGet(t, c, "a"); ok {
+		t.Error("a was not evicted")
+	}
+
+	for key, want := range map[string]int{"c": 20, "d": 3, "e": 4} {
+		if v, ok := mustGet(t, c, key); !ok || v != want {
+			t.Errorf("Get(%q) = %d, %v, want %d, true", key, v, ok, want)
+		}
+	}
+	if c.Len() != 3 {
+		t.Errorf("Len() = %d, want 3", c.Len())
+	}
+}
+
+func TestLRUTTL(t *testing.T) {
+	ctx := context.Background()
+	c, clock := newTestLRU(0, time.Minute)
+
+	_ = c.Set(ctx, "default", 1)
+	_ = c.SetWithTTL(ctx, "short", 2, time.Second)
+	_ = c.SetWithTTL(ctx, "forever", 3, 0)
+
+	clock.Advance(time.Second)
+	if _, ok := mustGet(t, c, "short"); ok {
+		t.Error("short did not expire after its TTL")
+	}
+	if _, ok := mustGet(t, c, "default"); !ok {
+		t.Error("default expired before the default TTL")
+	}
+
+	clock.Advance(time.Minute)
+	if _, ok := mustGet(t, c, "default"); ok {
+		t.Error("default did not expire after the default TTL")
+	}
+	if v, ok := mustGet(t, c, "forever"); !ok || v != 3 {
+		t.Errorf("Get(forever) = %d, %v, want 3, true", v, ok)
+	}
+
+	// Expired entries are removed once read
+	if c.Len() != 1 {
+		t.Errorf("Len() = %d, want 1", c.Len())
+	}
+
+	// Setting again resets the TTL
+	_ = c.SetWithTTL(ctx, "forever", 4, time.Second)
+	clock.Advance(time.Second)
+	if _, ok := mustGet(t, c, "forever"); ok {
+		t.Error("forever kept its old TTL after SetWithTTL")
+	}
+}
+
+func TestLRUDelete(t *testing.T) {
+	ctx := context.Background()
+	c, clock := newTestLRU(0, 0)
+
+	for i, key := range []string{"kb:a", "kb:b", "other", "stale"} {
+		_ = c.Set(ctx, key, i)
+	}
+	if err := c.Delete(ctx, "other"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := c.Delete(ctx, "missing"); err != nil {
+		t.Fatalf("Delete(missing) error = %v", err)
+	}
+	if _, ok := mustGet(t, c, "other"); ok {
+		t.Error("other was not deleted")
+	}
+
+	_ = c.SetWithTTL(ctx, "stale", 9, time.Second)
+	clock.Advance(time.Second)
+	removed := c.DeleteFunc(func(key string, _ int) bool {
+		return strings.HasPrefix(key, "kb:")
+	})
+	if removed != 2 {
+		t.Errorf("DeleteFunc() = %d, want 2", removed)
+	}
+	if c.Len() != 0 {
+		t.Errorf("Len() = %d, want 0", c.Len())
+	}
+}
+
+func TestLRUInvalidate(t *testing.T) {
+	ctx := context.Background()
+	c, _ := newTestLRU(0, 0)
+
+	_ = c.SetWithTags(ctx, "agent-a:q1", 1, 0, "kb:v1")
+	_ = c.SetWithTags(ctx, "agent-a:q2", 2, 0, "kb:v2")
+	_ = c.SetWithTags(ctx, "agent-b:q1", 3, 0, "kb:v1", "priority")
+	_ = c.Set(ctx, "agent-b:q2", 4)
+
+	removed, err := c.InvalidateByTag(ctx, "kb:v1")
+	if err != nil || removed != 2 {
+		t.Fatalf("InvalidateByTag() = %d, %v, want 2 removed", removed, err)
+	}
+	if _, ok := mustGet(t, c, "agent-a:q2"); !ok {
+		t.Error("agent-a:q2 was removed without the tag")
+	}
+
+	// Overwriting an entry replaces its tags
+	_ = c.SetWithTags(ctx, "agent-a:q2", 5, 0)
+	if removed, _ := c.InvalidateByTag(ctx, "kb:v2"); removed != 0 {
+		t.Errorf("InvalidateByTag() removed %d entries by a replaced tag", removed)
+	}
+
+	removed, err = InvalidateByPrefix(ctx, c, "agent-a:")
+	if err != nil || removed != 1 {
+		t.Fatalf("InvalidateByPrefix() = %d, %v, want 1 removed", removed, err)
+	}
+	if _, ok := mustGet(t, c, "agent-b:q2"); !ok || c.Len() != 1 {
+		t.Errorf("Len() = %d, want only agent-b:q2 left", c.Len())
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := c.InvalidateByTag(cancelled, "kb:v1"); err != context.Canceled {
+		t.Errorf("InvalidateByTag() error = %v, want context.Canceled", err)
+	}
+	if _, err := InvalidateByPrefix(cancelled, c, ""); err != context.Canceled || c.Len() != 1 {
+		t.Errorf("InvalidateByPrefix() error = %v, want context.Canceled and nothing removed", err)
+	}
+}
+
+func TestLRUConcurrentAccess(t *testing.T) {
+	ctx := context.Background()
+	c := New[string, int](50, time.Minute)
+
+	var wg sync.WaitGroup
+	for g := 0; g < 8; g++ {
+		wg.Add(1)
+		go func(g int) {
+			defer wg.Done()
+			for i := 0; i < 500; i++ {
+				key := fmt.Sprintf("key-%d", (g*31+i)%100)
+				switch i % 4 {
+				case 0:
+					_ = c.Set(ctx, key, i)
+				case 1:
+					_ = c.SetWithTTL(ctx, key, i, time.Millisecond)
+				case 2:
+					_, _, _ = c.Get(ctx, key)
+				case 3:
+					_ = c.Delete(ctx, key)
+				}
+			}
+		}(g)
+	}
+	wg.Wait()
+
+	if n := c.Len(); n > 50 {
+		t.Errorf("Len() = %d, want at most 50", n)
+	}
+}
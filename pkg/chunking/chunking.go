@@ -0,0 +1,228 @@
+// Package chunking splits text into chunks small enough for embedding models,
+// so long documents can be indexed for retrieval piece by piece.
+//
+// Example:
+//
+//	chunks := chunking.ChunkText(text, chunking.ChunkOptions{
+//	    Strategy: chunking.StrategyWindow,
+//	    Size:     800,
+//	    Overlap:  200,
+//	})
+//	for _, c := range chunks {
+//	    fmt.Println(c.Index, c.Start, c.End, c.Text)
+//	}
+package chunking
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+// Strategy selects how text is split into chunks
+type Strategy string
+
+const (
+	// StrategyFixed cuts text into consecutive chunks of Size characters
+	StrategyFixed Strategy = "fixed"
+
+	// StrategySentence packs whole sentences into chunks of at most Size
+	// characters. A sentence longer than Size is cut into fixed-size pieces.
+	StrategySentence Strategy = "sentence"
+
+	// StrategyWindow slides a window of Size characters over the text,
+	// advancing by Size-Overlap so consecutive chunks share Overlap characters
+	StrategyWindow Strategy = "window"
+)
+
+// DefaultChunkSize is the chunk size used when ChunkOptions.Size is not set
+const DefaultChunkSize = 1000
+
+// Metadata keys set on chunk documents by ChunkDocument
+const (
+	MetadataKeyParentID   = "parent_id"
+	MetadataKeyChunkIndex = "chunk_index"
+	MetadataKeyChunkStart = "chunk_start"
+	MetadataKeyChunkEnd   = "chunk_end"
+)
+
+// ChunkOptions configures ChunkText
+type ChunkOptions struct {
+	// Strategy selects the splitting strategy (default: StrategyFixed)
+	Strategy Strategy
+
+	// Size is the maximum chunk length in characters (default: DefaultChunkSize)
+	Size int
+
+	// Overlap is the number of characters shared by consecutive chunks with
+	// StrategyWindow (default: Size/5). It is clamped to Size-1.
+	Overlap int
+}
+
+// Chunk is a piece of text together with its position in the source.
+// Start and End are byte offsets, so text[Start:End] == Text.
+type Chunk struct {
+	Index int
+	Text  string
+	Start int
+	End   int
+}
+
+// ChunkText splits text according to opts. Text no longer than Size is
+// returned as a single chunk; empty or whitespace-only text yields none.
+func ChunkText(text string, opts ChunkOptions) []Chunk {
+	if strings.TrimSpace(text) == "" {
+		return nil
+	}
+	size := opts.Size
+	if size <= 0 {
+		size = DefaultChunkSize
+	}
+
+	var chunks []Chunk
+	switch opts.Strategy {
+	case StrategySentence:
+		chunks = sentenceChunks(text, size)
+	case StrategyWindow:
+		overlap := opts.Overlap
+		if overlap <= 0 {
+			overlap = size / 5
+		}
+		chunks = windowChunks(text, 0, size, min(overlap, size-1))
+	default:
+		chunks = windowChunks(text, 0, size, 0)
+	}
+
+	for i := range chunks {
+		chunks[i].Index = i
+	}
+	return chunks
+}
+
+// windowChunks cuts text into windows of size runes stepping by size-overlap.
+// Offsets are relative to the start of the enclosing text at base.
+func windowChunks(text string, base, size, overlap int) []Chunk {
+	// Byte offset of every rune boundary, including the end of text
+	bounds := make([]int, 0, len(text)+1)
+	for i := range text {
+		bounds = append(bounds, i)
+	}
+	bounds = append(bounds, len(text))
+	runes := len(bounds) - 1
+
+	var chunks []Chunk
+	for start := 0; ; start += size - overlap {
+		end := min(start+size, runes)
+		chunks = append(chunks, Chunk{
+			Text:  text[bounds[start]:bounds[end]],
+			Start: base + bounds[start],
+			End:   base + bounds[end],
+		})
+		if end == runes {
+			return chunks
+		}
+	}
+}
+
+// sentenceChunks greedily packs sentences into chunks of at most size runes
+func sentenceChunks(text string, size int) []Chunk {
+	var chunks []Chunk
+	start, end := -1, -1
+	flush := func() {
+		if start >= 0 {
+			chunks = append(chunks, Chunk{Text: text[start:end], Start: start, End: end})
+		}
+		start, end = -1, -1
+	}
+
+	for _, s := range sentences(text) {
+		if utf8.RuneCountInString(text[s[0]:s[1]]) > size {
+			flush()
+			chunks = append(chunks, windowChunks(text[s[0]:s[1]], s[0], size, 0)...)
+			continue
+		}
+		if start >= 0 && utf8.RuneCountInString(text[start:s[1]]) > size {
+			flush()
+		}
+		if start < 0 {
+			start = s[0]
+		}
+		end = s[1]
+	}
+	flush()
+	return chunks
+}
+
+// sentences returns the byte ranges of the sentences in text, without
+// surrounding whitespace. A sentence ends at '.', '!' or '?' followed by
+// whitespace, at a blank line, or at the end of text.
+func sentences(text string) [][2]int {
+	var spans [][2]int
+	add := func(start, end int) {
+		segment := text[start:end]
+		trimmed := strings.TrimLeftFunc(segment, unicode.IsSpace)
+		start += len(segment) - len(trimmed)
+		trimmed = strings.TrimRightFunc(trimmed, unicode.IsSpace)
+		if trimmed != "" {
+			spans = append(spans, [2]int{start, start + len(trimmed)})
+		}
+	}
+
+	start := 0
+	for i, r := range text {
+		next := i + utf8.RuneLen(r)
+		switch {
+		case r == '.' || r == '!' || r == '?':
+			if next == len(text) || startsWithSpace(text[next:]) {
+				add(start, next)
+				start = next
+			}
+		case r == '\n' && strings.HasPrefix(text[next:], "\n"):
+			add(start, next)
+			start = next
+		}
+	}
+	add(start, len(text))
+	return spans
+}
+
+func startsWithSpace(s string) bool {
+	r, _ := utf8.DecodeRuneInString(s)
+	return unicode.IsSpace(r)
+}
+
+// ChunkDocument splits a text document into one document per chunk for
+// indexing. Chunk documents are named "<id>-chunk-<n>", copy the parent's
+// tags, scope and metadata, and record the parent ID and chunk position under
+// the MetadataKey* keys. Embeddings are left for the caller to set.
+func ChunkDocument(doc *vectorstore.Document, opts ChunkOptions) ([]*vectorstore.Document, error) {
+	if doc == nil || doc.Content == nil || doc.Content.Type != vectorstore.ContentTypeText {
+		return nil, fmt.Errorf("chunking requires a text document")
+	}
+
+	chunks := ChunkText(doc.Content.Text, opts)
+	docs := make([]*vectorstore.Document, len(chunks))
+	for i, c := range chunks {
+		metadata := make(map[string]any, len(doc.Metadata)+4)
+		for k, v := range doc.Metadata {
+			metadata[k] = v
+		}
+		metadata[MetadataKeyParentID] = doc.ID
+		metadata[MetadataKeyChunkIndex] = c.Index
+		metadata[MetadataKeyChunkStart] = c.Start
+		metadata[MetadataKeyChunkEnd] = c.End
+
+		docs[i] = &vectorstore.Document{
+			ID:       fmt.Sprintf("%s-chunk-%d", doc.ID, c.Index),
+			Content:  vectorstore.NewTextContent(c.Text),
+			Scope:    doc.Scope,
+			Temporal: doc.Temporal,
+			Tags:     append([]string(nil), doc.Tags...),
+			Metadata: metadata,
+		}
+	}
+	return docs, nil
+}
@@ -0,0 +1,160 @@
+package chunking
+
+import (
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+// checkOffsets verifies every chunk's text matches its offsets into text
+func checkOffsets(t *testing.T, text string, chunks []Chunk) {
+	t.Helper()
+	for i, c := range chunks {
+		if c.Index != i {
+			t.Errorf("chunk %d has Index %d", i, c.Index)
+		}
+		if text[c.Start:c.End] != c.Text {
+			t.Errorf("chunk %d: text[%d:%d] = %q, want %q", i, c.Start, c.End, text[c.Start:c.End], c.Text)
+		}
+	}
+}
+
+func TestChunkTextFixed(t *testing.T) {
+	text := strings.Repeat("abcdefghij", 3) // 30 characters
+	chunks := ChunkText(text, ChunkOptions{Size: 12})
+	checkOffsets(t, text, chunks)
+
+	want := []string{"abcdefghijab", "cdefghijabcd", "efghij"}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks, want %d", len(chunks), len(want))
+	}
+	for i, c := range chunks {
+		if c.Text != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, c.Text, want[i])
+		}
+	}
+}
+
+func TestChunkTextWindowOverlap(t *testing.T) {
+	text := "0123456789abcdefghijklmnopqrstuvwxyz"
+	chunks := ChunkText(text, ChunkOptions{Strategy: StrategyWindow, Size: 10, Overlap: 4})
+	checkOffsets(t, text, chunks)
+
+	if len(chunks) != 6 {
+		t.Fatalf("got %d chunks, want 6", len(chunks))
+	}
+	for i := 1; i < len(chunks); i++ {
+		prev, cur := chunks[i-1], chunks[i]
+		if cur.Start != prev.Start+6 {
+			t.Errorf("chunk %d starts at %d, want %d", i, cur.Start, prev.Start+6)
+		}
+		if shared := prev.Text[len(prev.Text)-4:]; !strings.HasPrefix(cur.Text, shared) {
+			t.Errorf("chunk %d = %q does not start with the previous chunk's last 4 characters %q", i, cur.Text, shared)
+		}
+	}
+	if last := chunks[len(chunks)-1]; last.End != len(text) {
+		t.Errorf("last chunk ends at %d, want %d", last.End, len(text))
+	}
+
+	// Overlap is clamped so windows always advance
+	clamped := ChunkText("abcdef", ChunkOptions{Strategy: StrategyWindow, Size: 3, Overlap: 5})
+	checkOffsets(t, "abcdef", clamped)
+	if len(clamped) != 4 {
+		t.Errorf("got %d chunks with clamped overlap, want 4", len(clamped))
+	}
+}
+
+func TestChunkTextSentence(t *testing.T) {
+	text := "First sentence here. Second one! Third? " +
+		"A much longer fourth sentence that exceeds the size limit.\n\nTail"
+	chunks := ChunkText(text, ChunkOptions{Strategy: StrategySentence, Size: 35})
+	checkOffsets(t, text, chunks)
+
+	want := []string{
+		"First sentence here. Second one!",
+		"Third?",
+		"A much longer fourth sentence that ",
+		"exceeds the size limit.",
+		"Tail",
+	}
+	if len(chunks) != len(want) {
+		t.Fatalf("got %d chunks %+v, want %d", len(chunks), chunks, len(want))
+	}
+	for i, c := range chunks {
+		if c.Text != want[i] {
+			t.Errorf("chunk %d = %q, want %q", i, c.Text, want[i])
+		}
+	}
+}
+
+func TestChunkTextShortInputs(t *testing.T) {
+	for _, strategy := range []Strategy{StrategyFixed, StrategySentence, StrategyWindow} {
+		if chunks := ChunkText("", ChunkOptions{Strategy: strategy}); chunks != nil {
+			t.Errorf("%s: empty text gave %d chunks", strategy, len(chunks))
+		}
+		if chunks := ChunkText(" \n\t ", ChunkOptions{Strategy: strategy}); chunks != nil {
+			t.Errorf("%s: whitespace gave %d chunks", strategy, len(chunks))
+		}
+
+		chunks := ChunkText("Short.", ChunkOptions{Strategy: strategy, Size: 100})
+		if len(chunks) != 1 || chunks[0].Text != "Short." || chunks[0].Start != 0 || chunks[0].End != 6 {
+			t.Errorf("%s: short text gave %+v, want one whole chunk", strategy, chunks)
+		}
+
+		// A text exactly Size long is not split
+		if chunks := ChunkText("abcde", ChunkOptions{Strategy: strategy, Size: 5}); len(chunks) != 1 {
+			t.Errorf("%s: text of exactly Size gave %d chunks", strategy, len(chunks))
+		}
+	}
+}
+
+func TestChunkTextMultibyte(t *testing.T) {
+	text := "héllo wörld ünïcode"
+	chunks := ChunkText(text, ChunkOptions{Strategy: StrategyWindow, Size: 5, Overlap: 2})
+	checkOffsets(t, text, chunks)
+	for i, c := range chunks {
+		if !utf8.ValidString(c.Text) {
+			t.Errorf("chunk %d = %q splits a character", i, c.Text)
+		}
+		if n := utf8.RuneCountInString(c.Text); n > 5 {
+			t.Errorf("chunk %d has %d characters, want at most 5", i, n)
+		}
+	}
+}
+
+func TestChunkDocument(t *testing.T) {
+	doc := &vectorstore.Document{
+		ID:       "doc-1",
+		Content:  vectorstore.NewTextContent("One. Two. Three."),
+		Tags:     []string{"documentation"},
+		Metadata: map[string]any{"source": "docs"},
+	}
+
+	docs, err := ChunkDocument(doc, ChunkOptions{Strategy: StrategySentence, Size: 9})
+	if err != nil {
+		t.Fatalf("ChunkDocument() error = %v", err)
+	}
+	if len(docs) != 2 {
+		t.Fatalf("got %d documents, want 2", len(docs))
+	}
+	for i, d := range docs {
+		if err := vectorstore.Validate(d); err != nil {
+			t.Errorf("chunk document %d is invalid: %v", i, err)
+		}
+		if d.Metadata[MetadataKeyParentID] != "doc-1" || d.Metadata["source"] != "docs" {
+			t.Errorf("chunk document %d metadata = %v", i, d.Metadata)
+		}
+	}
+	if docs[1].ID != "doc-1-chunk-1" || docs[1].Content.Text != "Three." {
+		t.Errorf("second chunk = %s %q", docs[1].ID, docs[1].Content.Text)
+	}
+	if _, ok := doc.Metadata[MetadataKeyParentID]; ok {
+		t.Error("ChunkDocument() modified the parent's metadata")
+	}
+
+	if _, err := ChunkDocument(&vectorstore.Document{ID: "img", Content: vectorstore.NewImageURL("https://example.com/a.png")}, ChunkOptions{}); err == nil {
+		t.Error("ChunkDocument() should reject non-text documents")
+	}
+}
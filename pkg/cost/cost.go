@@ -0,0 +1,40 @@
+// Package cost computes the USD cost of LLM calls from token counts.
+//
+// It is a small facade over the shared pricing table in pkg/llm/cost, so
+// pricing registered here is also used by instrumented providers and cost
+// trackers.
+package cost
+
+import (
+	"fmt"
+
+	llmcost "github.com/aixgo-dev/aixgo/pkg/llm/cost"
+)
+
+// ErrUnknownModel is returned by Calculate for models without pricing
+var ErrUnknownModel = llmcost.ErrNoPricing
+
+// Calculate returns the USD cost of a call to model with the given prompt
+// and completion token counts. Model names match registered pricing exactly
+// or by prefix (e.g. "gpt-4o-2024-08-06" uses "gpt-4o").
+func Calculate(model string, promptTokens, completionTokens int) (float64, error) {
+	if promptTokens < 0 || completionTokens < 0 {
+		return 0, fmt.Errorf("token counts must be non-negative, got %d prompt and %d completion", promptTokens, completionTokens)
+	}
+	c, err := llmcost.DefaultCalculator.EstimateCost(model, promptTokens, completionTokens)
+	if err != nil {
+		return 0, err
+	}
+	return c.TotalCost, nil
+}
+
+// RegisterPricing adds or replaces the pricing for model, given in USD per
+// 1K input and output tokens. Use it for custom, fine-tuned or self-hosted
+// models.
+func RegisterPricing(model string, inputPer1K, outputPer1K float64) {
+	llmcost.DefaultCalculator.AddPricing(&llmcost.ModelPricing{
+		Model:       model,
+		InputPer1M:  inputPer1K * 1000,
+		OutputPer1M: outputPer1K * 1000,
+	})
+}
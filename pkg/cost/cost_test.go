@@ -0,0 +1,50 @@
+package cost
+
+import (
+	"errors"
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestCalculate(t *testing.T) {
+	// gpt-4o: $2.50 per 1M input, $10.00 per 1M output
+	got, err := Calculate("gpt-4o", 1000, 500)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if want := 0.0025 + 0.005; math.Abs(got-want) > 1e-12 {
+		t.Errorf("Calculate() = %v, want %v", got, want)
+	}
+
+	// Dated snapshots fall back to the base model's pricing
+	if dated, err := Calculate("gpt-4o-2024-08-06", 1000, 500); err != nil || dated != got {
+		t.Errorf("Calculate(dated) = %v, %v, want %v", dated, err, got)
+	}
+
+	if _, err := Calculate("gpt-4o", -1, 0); err == nil {
+		t.Error("expected error for negative token counts")
+	}
+}
+
+func TestCalculate_UnknownModel(t *testing.T) {
+	_, err := Calculate("acme-unreleased-7b", 100, 100)
+	if !errors.Is(err, ErrUnknownModel) {
+		t.Fatalf("Calculate() error = %v, want ErrUnknownModel", err)
+	}
+	if !strings.Contains(err.Error(), "acme-unreleased-7b") {
+		t.Errorf("error %q should name the model", err)
+	}
+}
+
+func TestRegisterPricing(t *testing.T) {
+	RegisterPricing("selfhosted/mixtral-8x7b", 0.0006, 0.0012)
+
+	got, err := Calculate("selfhosted/mixtral-8x7b", 2000, 1000)
+	if err != nil {
+		t.Fatalf("Calculate() error = %v", err)
+	}
+	if want := 0.0012 + 0.0012; math.Abs(got-want) > 1e-12 {
+		t.Errorf("Calculate() = %v, want %v", got, want)
+	}
+}
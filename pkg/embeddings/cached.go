@@ -0,0 +1,151 @@
+package embeddings
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"slices"
+
+	"github.com/aixgo-dev/aixgo/pkg/cache"
+)
+
+// Cache stores embeddings by key for CachedService. Implementations must be
+// safe for concurrent use; a shared cache such as Redis can implement it by
+// storing each vector as an encoded value under the key.
+type Cache interface {
+	// Get returns the embedding stored under key, or false if there is none
+	Get(ctx context.Context, key string) ([]float32, bool, error)
+
+	// Set stores embedding under key
+	Set(ctx context.Context, key string, embedding []float32) error
+}
+
+// CachedService wraps an EmbeddingService and serves repeated texts from a
+// Cache, so re-indexing unchanged content does not call the provider again.
+// Entries are keyed by model and text, so services for different models can
+// share a cache. Cache errors are treated as misses.
+type CachedService struct {
+	svc   EmbeddingService
+	cache Cache
+}
+
+// NewCached returns svc with embeddings cached in cache.
+//
+// Example:
+//
+//	svc = embeddings.NewCached(svc, embeddings.NewMemoryCache(10000))
+func NewCached(svc EmbeddingService, cache Cache) *CachedService {
+	return &CachedService{svc: svc, cache: cache}
+}
+
+// CacheKey returns the cache key for text embedded with model: the hex
+// SHA-256 of the model name and text.
+func CacheKey(model, text string) string {
+	sum := sha256.Sum256([]byte(model + "\x00" + text))
+	return hex.EncodeToString(sum[:])
+}
+
+// Embed returns the cached embedding of text, embedding it on a miss.
+func (c *CachedService) Embed(ctx context.Context, text string) ([]float32, error) {
+	key := CacheKey(c.svc.ModelName(), text)
+	if embedding, ok, err := c.cache.Get(ctx, key); err == nil && ok {
+		return embedding, nil
+	}
+
+	embedding, err := c.svc.Embed(ctx, text)
+	if err != nil {
+		return nil, err
+	}
+	_ = c.cache.Set(ctx, key, embedding)
+	return embedding, nil
+}
+
+// EmbedBatch returns cached embeddings and embeds only the texts that miss,
+// in a single EmbedBatch call.
+func (c *CachedService) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+	model := c.svc.ModelName()
+	results := make([][]float32, len(texts))
+	keys := make([]string, len(texts))
+
+	// Texts to embed, each with the result positions it fills
+	var missing []string
+	positions := make(map[string][]int)
+	for i, text := range texts {
+		keys[i] = CacheKey(model, text)
+		if embedding, ok, err := c.cache.Get(ctx, keys[i]); err == nil && ok {
+			results[i] = embedding
+			continue
+		}
+		if _, seen := positions[text]; !seen {
+			missing = append(missing, text)
+		}
+		positions[text] = append(positions[text], i)
+	}
+	if len(missing) == 0 {
+		return results, nil
+	}
+
+	embedded, err := c.svc.EmbedBatch(ctx, missing)
+	if err != nil {
+		return nil, err
+	}
+	if len(embedded) != len(missing) {
+		return nil, fmt.Errorf("EmbedBatch returned %d embeddings for %d texts", len(embedded), len(missing))
+	}
+	for j, text := range missing {
+		idx := positions[text]
+		_ = c.cache.Set(ctx, keys[idx[0]], embedded[j])
+		for _, i := range idx {
+			results[i] = embedded[j]
+		}
+	}
+	return results, nil
+}
+
+// Dimensions returns the dimensions of the wrapped service.
+func (c *CachedService) Dimensions() int {
+	return c.svc.Dimensions()
+}
+
+// ModelName returns the model name of the wrapped service.
+func (c *CachedService) ModelName() string {
+	return c.svc.ModelName()
+}
+
+// Close closes the wrapped service. The cache is left open, since it may be
+// shared.
+func (c *CachedService) Close() error {
+	return c.svc.Close()
+}
+
+// MemoryCache is an in-process Cache that evicts the least recently used
+// entry once it holds maxEntries embeddings.
+type MemoryCache struct {
+	entries *cache.LRU[string, []float32]
+}
+
+// NewMemoryCache creates a MemoryCache holding up to maxEntries embeddings
+// (0 = unlimited).
+func NewMemoryCache(maxEntries int) *MemoryCache {
+	return &MemoryCache{entries: cache.New[string, []float32](maxEntries, 0)}
+}
+
+// Get returns a copy of the embedding stored under key.
+func (m *MemoryCache) Get(ctx context.Context, key string) ([]float32, bool, error) {
+	embedding, ok, err := m.entries.Get(ctx, key)
+	if !ok || err != nil {
+		return nil, false, err
+	}
+	return slices.Clone(embedding), true, nil
+}
+
+// Set stores a copy of embedding under key.
+func (m *MemoryCache) Set(ctx context.Context, key string, embedding []float32) error {
+	return m.entries.Set(ctx, key, slices.Clone(embedding))
+}
+
+// Len returns the number of cached embeddings.
+func (m *MemoryCache) Len() int {
+	return m.entries.Len()
+}
@@ -0,0 +1,144 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync/atomic"
+	"testing"
+)
+
+// countingService embeds a text as its length and counts provider calls
+func countingService(embeds, batches *atomic.Int32) *mockEmbeddingService {
+	return &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			embeds.Add(1)
+			return []float32{float32(len(text))}, nil
+		},
+		embedBatchFunc: func(ctx context.Context, texts []string) ([][]float32, error) {
+			batches.Add(1)
+			result := make([][]float32, len(texts))
+			for i, text := range texts {
+				result[i] = []float32{float32(len(text))}
+			}
+			return result, nil
+		},
+	}
+}
+
+func TestCachedEmbed(t *testing.T) {
+	ctx := context.Background()
+	var embeds, batches atomic.Int32
+	cached := NewCached(countingService(&embeds, &batches), NewMemoryCache(0))
+
+	first, err := cached.Embed(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	second, err := cached.Embed(ctx, "hello")
+	if err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	if n := embeds.Load(); n != 1 {
+		t.Errorf("underlying Embed called %d times, want 1", n)
+	}
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("cached embedding = %v, want %v", second, first)
+	}
+
+	// Mutating a returned vector does not corrupt the cache
+	second[0] = -1
+	third, _ := cached.Embed(ctx, "hello")
+	if third[0] != 5 {
+		t.Errorf("cached embedding = %v after caller mutation, want [5]", third)
+	}
+
+	if cached.ModelName() != "mock-model" || cached.Dimensions() != 3 {
+		t.Errorf("ModelName() = %q, Dimensions() = %d; want the wrapped service's", cached.ModelName(), cached.Dimensions())
+	}
+	if err := cached.Close(); err != nil {
+		t.Errorf("Close() error = %v", err)
+	}
+}
+
+func TestCachedEmbedBatch(t *testing.T) {
+	ctx := context.Background()
+	var embeds, batches atomic.Int32
+	cached := NewCached(countingService(&embeds, &batches), NewMemoryCache(0))
+
+	if _, err := cached.Embed(ctx, "a"); err != nil {
+		t.Fatalf("Embed() error = %v", err)
+	}
+	got, err := cached.EmbedBatch(ctx, []string{"a", "bb", "ccc", "bb"})
+	if err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	want := [][]float32{{1}, {2}, {3}, {2}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("EmbedBatch() = %v, want %v", got, want)
+	}
+
+	// Everything is cached now, so the provider is not called again
+	if _, err := cached.EmbedBatch(ctx, []string{"ccc", "a"}); err != nil {
+		t.Fatalf("EmbedBatch() error = %v", err)
+	}
+	if embeds.Load() != 1 || batches.Load() != 1 {
+		t.Errorf("provider called %d Embed and %d EmbedBatch times, want 1 each", embeds.Load(), batches.Load())
+	}
+}
+
+func TestCachedKeysOnModel(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(0)
+	var embeds, batches atomic.Int32
+	small := countingService(&embeds, &batches)
+	large := countingService(&embeds, &batches)
+	large.modelName = "large-model"
+
+	_, _ = NewCached(small, cache).Embed(ctx, "text")
+	_, _ = NewCached(large, cache).Embed(ctx, "text")
+	if n := embeds.Load(); n != 2 {
+		t.Errorf("underlying Embed called %d times, want 2 for different models", n)
+	}
+	if CacheKey("m", "ab") == CacheKey("ma", "b") {
+		t.Error("CacheKey() should separate model and text")
+	}
+}
+
+// failingCache fails every operation
+type failingCache struct{}
+
+func (failingCache) Get(ctx context.Context, key string) ([]float32, bool, error) {
+	return nil, false, errors.New("cache down")
+}
+
+func (failingCache) Set(ctx context.Context, key string, embedding []float32) error {
+	return errors.New("cache down")
+}
+
+func TestCachedCacheErrorsAreMisses(t *testing.T) {
+	var embeds, batches atomic.Int32
+	cached := NewCached(countingService(&embeds, &batches), failingCache{})
+	if _, err := cached.Embed(context.Background(), "x"); err != nil {
+		t.Fatalf("Embed() error = %v, want cache errors to be ignored", err)
+	}
+}
+
+func TestMemoryCacheEviction(t *testing.T) {
+	ctx := context.Background()
+	cache := NewMemoryCache(2)
+	_ = cache.Set(ctx, "a", []float32{1})
+	_ = cache.Set(ctx, "b", []float32{2})
+	_, _, _ = cache.Get(ctx, "a") // a is now the most recently used
+	_ = cache.Set(ctx, "c", []float32{3})
+
+	if _, ok, _ := cache.Get(ctx, "b"); ok {
+		t.Error("least recently used entry b should have been evicted")
+	}
+	if _, ok, _ := cache.Get(ctx, "a"); !ok {
+		t.Error("recently used entry a should be kept")
+	}
+	if cache.Len() != 2 {
+		t.Errorf("Len() = %d, want 2", cache.Len())
+	}
+}
@@ -111,7 +111,14 @@ func (h *HuggingFaceEmbeddings) EmbedBatch(ctx context.Context, texts []string)
 		},
 	}
 
-	return h.makeRequest(ctx, reqBody)
+	embeddings, err := h.makeRequest(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+	return embeddings, nil
 }
 
 // Dimensions returns the dimension size of the embeddings.
@@ -131,6 +131,9 @@ func (t *HuggingFaceTEIEmbeddings) EmbedBatch(ctx context.Context, texts []strin
 	if err != nil {
 		return nil, err
 	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
 
 	// Update dimensions if not set (using atomic operation to prevent race condition)
 	if atomic.LoadInt32(&t.dimensions) == 0 && len(embeddings) > 0 && len(embeddings[0]) > 0 {
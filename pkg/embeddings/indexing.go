@@ -0,0 +1,225 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+
+	"golang.org/x/time/rate"
+)
+
+// DefaultEmbedConcurrency is the number of concurrent Embed calls used by EmbedAll
+// when no concurrency is configured.
+const DefaultEmbedConcurrency = 4
+
+// IndexOption configures EmbedAll.
+type IndexOption func(*indexConfig)
+
+type indexConfig struct {
+	concurrency int
+	batchSize   int
+	limiter     *rate.Limiter
+}
+
+// WithEmbedConcurrency sets the maximum number of concurrent Embed calls.
+// Values below 1 are treated as 1 (sequential embedding).
+func WithEmbedConcurrency(n int) IndexOption {
+	return func(c *indexConfig) {
+		if n < 1 {
+			n = 1
+		}
+		c.concurrency = n
+	}
+}
+
+// WithEmbedBatchSize sends texts to EmbedBatch n at a time instead of calling
+// Embed once per text. Concurrency and rate limits then apply per batch request.
+// A size of 1, the default, disables batching; values below 1 are raised to 1.
+func WithEmbedBatchSize(n int) IndexOption {
+	return func(c *indexConfig) {
+		if n < 1 {
+			n = 1
+		}
+		c.batchSize = n
+	}
+}
+
+// WithEmbedRateLimit caps Embed calls at requestsPerSecond with the given burst,
+// so raising concurrency does not exceed the provider's rate limit.
+func WithEmbedRateLimit(requestsPerSecond float64, burst int) IndexOption {
+	return func(c *indexConfig) {
+		if requestsPerSecond <= 0 {
+			c.limiter = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.limiter = rate.NewLimiter(rate.Limit(requestsPerSecond), burst)
+	}
+}
+
+// IndexError aggregates per-text embedding failures from EmbedAll.
+// Failed maps the index of each failed text to its error.
+type IndexError struct {
+	Failed map[int]error
+	Total  int
+}
+
+// Error implements the error interface.
+func (e *IndexError) Error() string {
+	idx := e.indexes()
+	return fmt.Sprintf("failed to embed %d/%d texts (first failure at index %d: %v)",
+		len(e.Failed), e.Total, idx[0], e.Failed[idx[0]])
+}
+
+// Unwrap returns the individual errors in index order so errors.Is and
+// errors.As can match any of them.
+func (e *IndexError) Unwrap() []error {
+	idx := e.indexes()
+	errs := make([]error, len(idx))
+	for i, j := range idx {
+		errs[i] = e.Failed[j]
+	}
+	return errs
+}
+
+func (e *IndexError) indexes() []int {
+	idx := make([]int, 0, len(e.Failed))
+	for i := range e.Failed {
+		idx = append(idx, i)
+	}
+	sort.Ints(idx)
+	return idx
+}
+
+// EmbedAll embeds texts using up to WithEmbedConcurrency concurrent Embed calls,
+// or EmbedBatch calls when WithEmbedBatchSize is set.
+// The returned slice preserves input order: result[i] is the embedding of texts[i].
+//
+// Failures do not stop the remaining texts from being embedded. If any text fails,
+// EmbedAll returns the partial results (nil for failed entries) together with an
+// *IndexError describing every failure. A failed batch marks each of its texts failed.
+//
+// Example:
+//
+//	vectors, err := embeddings.EmbedAll(ctx, svc, texts,
+//	    embeddings.WithEmbedConcurrency(8),
+//	    embeddings.WithEmbedBatchSize(32),
+//	    embeddings.WithEmbedRateLimit(50, 10),
+//	)
+func EmbedAll(ctx context.Context, svc EmbeddingService, texts []string, opts ...IndexOption) ([][]float32, error) {
+	if svc == nil {
+		return nil, errors.New("embedding service is nil")
+	}
+
+	cfg := &indexConfig{concurrency: DefaultEmbedConcurrency, batchSize: 1}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	results := make([][]float32, len(texts))
+	failed := make(map[int]error)
+	var mu sync.Mutex
+
+	// A batch takes its slot before its goroutine is spawned, so at most
+	// cfg.concurrency goroutines exist and no batch starts once ctx ends
+	sem := make(chan struct{}, cfg.concurrency)
+	var wg sync.WaitGroup
+
+	for start := 0; start < len(texts); start += cfg.batchSize {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if err := ctx.Err(); err != nil {
+			// This batch and every later one is never started
+			mu.Lock()
+			for i := start; i < len(texts); i++ {
+				failed[i] = err
+			}
+			mu.Unlock()
+			break
+		}
+
+		end := min(start+cfg.batchSize, len(texts))
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			fail := func(err error) {
+				mu.Lock()
+				for i := start; i < end; i++ {
+					failed[i] = err
+				}
+				mu.Unlock()
+			}
+
+			if cfg.limiter != nil {
+				if err := cfg.limiter.Wait(ctx); err != nil {
+					fail(fmt.Errorf("rate limit: %w", err))
+					return
+				}
+			}
+
+			if end-start == 1 {
+				embedding, err := svc.Embed(ctx, texts[start])
+				if err != nil {
+					fail(err)
+					return
+				}
+				results[start] = embedding
+				return
+			}
+
+			batch, err := svc.EmbedBatch(ctx, texts[start:end])
+			if err != nil {
+				fail(err)
+				return
+			}
+			if len(batch) != end-start {
+				fail(fmt.Errorf("EmbedBatch returned %d embeddings for %d texts", len(batch), end-start))
+				return
+			}
+			copy(results[start:end], batch)
+		}()
+	}
+
+	wg.Wait()
+
+	if len(failed) > 0 {
+		return results, &IndexError{Failed: failed, Total: len(texts)}
+	}
+	return results, nil
+}
+
+// EmbedEach implements EmbedBatch by calling embed once per text, for
+// embedding services whose backend has no batch endpoint. It stops at the
+// first error.
+//
+// Example:
+//
+//	func (s *MyEmbeddings) EmbedBatch(ctx context.Context, texts []string) ([][]float32, error) {
+//	    return embeddings.EmbedEach(ctx, s.Embed, texts)
+//	}
+func EmbedEach(ctx context.Context, embed func(ctx context.Context, text string) ([]float32, error), texts []string) ([][]float32, error) {
+	if len(texts) == 0 {
+		return nil, fmt.Errorf("texts cannot be empty")
+	}
+
+	results := make([][]float32, len(texts))
+	for i, text := range texts {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		embedding, err := embed(ctx, text)
+		if err != nil {
+			return nil, fmt.Errorf("text %d: %w", i, err)
+		}
+		results[i] = embedding
+	}
+	return results, nil
+}
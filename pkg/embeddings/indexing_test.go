@@ -0,0 +1,200 @@
+package embeddings
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEmbedAllPreservesOrder tests that results correspond to input positions.
+func TestEmbedAllPreservesOrder(t *testing.T) {
+	svc := &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			var n float32
+			_, _ = fmt.Sscanf(text, "doc-%f", &n)
+			// Finish out of order to exercise index bookkeeping
+			time.Sleep(time.Duration(10-int(n)) * time.Millisecond)
+			return []float32{n}, nil
+		},
+	}
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("doc-%d", i)
+	}
+
+	results, err := EmbedAll(context.Background(), svc, texts, WithEmbedConcurrency(5))
+	require.NoError(t, err)
+	require.Len(t, results, len(texts))
+	for i, vec := range results {
+		assert.Equal(t, []float32{float32(i)}, vec)
+	}
+}
+
+// TestEmbedAllBoundsConcurrency tests that no more than n Embed calls run at once.
+func TestEmbedAllBoundsConcurrency(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	svc := &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			cur := inFlight.Add(1)
+			for {
+				p := peak.Load()
+				if cur <= p || peak.CompareAndSwap(p, cur) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			inFlight.Add(-1)
+			return []float32{1}, nil
+		},
+	}
+
+	texts := make([]string, 20)
+	_, err := EmbedAll(context.Background(), svc, texts, WithEmbedConcurrency(3))
+	require.NoError(t, err)
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+// TestEmbedAllStopsOnCancel tests that no batch is started once ctx is cancelled.
+func TestEmbedAllStopsOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var calls atomic.Int32
+	svc := &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			calls.Add(1)
+			cancel()
+			return []float32{1}, nil
+		},
+	}
+
+	results, err := EmbedAll(ctx, svc, make([]string, 5), WithEmbedConcurrency(1))
+	var indexErr *IndexError
+	require.ErrorAs(t, err, &indexErr)
+	assert.Equal(t, int32(1), calls.Load())
+	assert.Len(t, indexErr.Failed, 4)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.NotNil(t, results[0])
+}
+
+// TestEmbedAllAggregatesErrors tests that per-text failures are collected.
+func TestEmbedAllAggregatesErrors(t *testing.T) {
+	errBoom := errors.New("boom")
+	svc := &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			if text == "bad" {
+				return nil, errBoom
+			}
+			return []float32{1}, nil
+		},
+	}
+
+	results, err := EmbedAll(context.Background(), svc, []string{"ok", "bad", "ok", "bad"})
+	require.Error(t, err)
+
+	var indexErr *IndexError
+	require.ErrorAs(t, err, &indexErr)
+	assert.Len(t, indexErr.Failed, 2)
+	assert.Contains(t, indexErr.Failed, 1)
+	assert.Contains(t, indexErr.Failed, 3)
+	assert.ErrorIs(t, err, errBoom)
+
+	assert.NotNil(t, results[0])
+	assert.Nil(t, results[1])
+	assert.NotNil(t, results[2])
+}
+
+// TestEmbedAllRateLimit tests that the rate limiter spaces out calls.
+func TestEmbedAllRateLimit(t *testing.T) {
+	svc := &mockEmbeddingService{}
+
+	start := time.Now()
+	_, err := EmbedAll(context.Background(), svc, make([]string, 3),
+		WithEmbedConcurrency(3),
+		WithEmbedRateLimit(20, 1),
+	)
+	require.NoError(t, err)
+	// Burst of 1 at 20 rps: the third call waits ~100ms
+	assert.GreaterOrEqual(t, time.Since(start), 80*time.Millisecond)
+}
+
+// TestEmbedAllBatches tests that WithEmbedBatchSize uses EmbedBatch and keeps order.
+func TestEmbedAllBatches(t *testing.T) {
+	var batches atomic.Int32
+	svc := &mockEmbeddingService{
+		embedFunc: func(ctx context.Context, text string) ([]float32, error) {
+			return nil, errors.New("Embed should not be called")
+		},
+		embedBatchFunc: func(ctx context.Context, texts []string) ([][]float32, error) {
+			batches.Add(1)
+			out := make([][]float32, len(texts))
+			for i, text := range texts {
+				var n float32
+				_, _ = fmt.Sscanf(text, "doc-%f", &n)
+				out[i] = []float32{n}
+			}
+			return out, nil
+		},
+	}
+
+	texts := make([]string, 10)
+	for i := range texts {
+		texts[i] = fmt.Sprintf("doc-%d", i)
+	}
+
+	results, err := EmbedAll(context.Background(), svc, texts, WithEmbedBatchSize(4), WithEmbedConcurrency(2))
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), batches.Load())
+	for i, vec := range results {
+		assert.Equal(t, []float32{float32(i)}, vec)
+	}
+}
+
+// TestEmbedAllBatchFailure tests that a failed or short batch marks each of its texts failed.
+func TestEmbedAllBatchFailure(t *testing.T) {
+	svc := &mockEmbeddingService{
+		embedBatchFunc: func(ctx context.Context, texts []string) ([][]float32, error) {
+			switch texts[0] {
+			case "bad":
+				return nil, errors.New("boom")
+			case "short":
+				return [][]float32{{1}}, nil
+			}
+			return [][]float32{{1}, {2}}, nil
+		},
+	}
+
+	results, err := EmbedAll(context.Background(), svc, []string{"ok", "ok", "bad", "x", "short", "y"}, WithEmbedBatchSize(2))
+	var indexErr *IndexError
+	require.ErrorAs(t, err, &indexErr)
+	assert.Len(t, indexErr.Failed, 4)
+	assert.Contains(t, indexErr.Failed[4].Error(), "returned 1 embeddings for 2 texts")
+	assert.Equal(t, [][]float32{{1}, {2}, nil, nil, nil, nil}, results)
+}
+
+// TestEmbedEach tests the loop-based EmbedBatch helper.
+func TestEmbedEach(t *testing.T) {
+	embed := func(ctx context.Context, text string) ([]float32, error) {
+		if text == "bad" {
+			return nil, errors.New("boom")
+		}
+		return []float32{float32(len(text))}, nil
+	}
+
+	results, err := EmbedEach(context.Background(), embed, []string{"a", "bb", "ccc"})
+	require.NoError(t, err)
+	assert.Equal(t, [][]float32{{1}, {2}, {3}}, results)
+
+	_, err = EmbedEach(context.Background(), embed, []string{"a", "bad"})
+	assert.ErrorContains(t, err, "text 1: boom")
+
+	_, err = EmbedEach(context.Background(), embed, nil)
+	assert.Error(t, err)
+}
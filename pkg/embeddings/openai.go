@@ -130,7 +130,15 @@ func (o *OpenAIEmbeddings) EmbedBatch(ctx context.Context, texts []string) ([][]
 		reqBody.Dimensions = &o.dimensions
 	}
 
-	return o.makeRequest(ctx, reqBody)
+	// makeRequest orders results by their response index
+	embeddings, err := o.makeRequest(ctx, reqBody)
+	if err != nil {
+		return nil, err
+	}
+	if len(embeddings) != len(texts) {
+		return nil, fmt.Errorf("expected %d embeddings, got %d", len(texts), len(embeddings))
+	}
+	return embeddings, nil
 }
 
 // Dimensions returns the dimension size of the embeddings.
@@ -200,6 +200,7 @@ func TestOpenAIEmbedBatch(t *testing.T) {
 		mockStatusCode int
 		wantErr        bool
 		errMsg         string
+		wantFirst      []float32
 	}{
 		{
 			name:  "successful batch embedding",
@@ -220,6 +221,44 @@ func TestOpenAIEmbedBatch(t *testing.T) {
 			mockStatusCode: http.StatusOK,
 			wantErr:        false,
 		},
+		{
+			name:  "out-of-order response follows input order",
+			texts: []string{"text1", "text2", "text3"},
+			mockResponse: &openAIResponse{
+				Object: "list",
+				Data: []struct {
+					Object    string    `json:"object"`
+					Embedding []float32 `json:"embedding"`
+					Index     int       `json:"index"`
+				}{
+					{Object: "embedding", Embedding: []float32{0.7, 0.8, 0.9}, Index: 2},
+					{Object: "embedding", Embedding: []float32{0.1, 0.2, 0.3}, Index: 0},
+					{Object: "embedding", Embedding: []float32{0.4, 0.5, 0.6}, Index: 1},
+				},
+				Model: "text-embedding-3-small",
+			},
+			mockStatusCode: http.StatusOK,
+			wantFirst:      []float32{0.1, 0.2, 0.3},
+		},
+		{
+			name:  "fewer embeddings than texts",
+			texts: []string{"text1", "text2", "text3"},
+			mockResponse: &openAIResponse{
+				Object: "list",
+				Data: []struct {
+					Object    string    `json:"object"`
+					Embedding []float32 `json:"embedding"`
+					Index     int       `json:"index"`
+				}{
+					{Object: "embedding", Embedding: []float32{0.1, 0.2, 0.3}, Index: 0},
+					{Object: "embedding", Embedding: []float32{0.4, 0.5, 0.6}, Index: 1},
+				},
+				Model: "text-embedding-3-small",
+			},
+			mockStatusCode: http.StatusOK,
+			wantErr:        true,
+			errMsg:         "expected 3 embeddings, got 2",
+		},
 		{
 			name:           "empty texts",
 			texts:          []string{},
@@ -290,6 +329,9 @@ func TestOpenAIEmbedBatch(t *testing.T) {
 			} else {
 				require.NoError(t, err)
 				assert.Len(t, embeddings, len(tt.texts))
+				if tt.wantFirst != nil {
+					assert.Equal(t, tt.wantFirst, embeddings[0])
+				}
 			}
 		})
 	}
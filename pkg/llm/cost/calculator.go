@@ -1,11 +1,15 @@
 package cost
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 	"sync"
 )
 
+// ErrNoPricing is returned when a model has no registered pricing
+var ErrNoPricing = errors.New("no pricing found for model")
+
 // ModelPricing contains pricing information for a specific model
 type ModelPricing struct {
 	Model           string
@@ -156,7 +160,7 @@ func (c *Calculator) GetPricing(model string) (*ModelPricing, bool) {
 func (c *Calculator) Calculate(usage *Usage) (*Cost, error) {
 	pricing, ok := c.GetPricing(usage.Model)
 	if !ok {
-		return nil, fmt.Errorf("no pricing found for model: %s", usage.Model)
+		return nil, fmt.Errorf("%w: %s", ErrNoPricing, usage.Model)
 	}
 
 	cost := &Cost{
@@ -0,0 +1,89 @@
+package cost
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker accumulates usage and cost for the LLM calls made with a context.
+// It is safe for concurrent use.
+type Tracker struct {
+	mu      sync.Mutex
+	usage   Usage
+	costUSD float64
+	calls   int
+	models  map[string]struct{}
+}
+
+// NewTracker creates an empty tracker
+func NewTracker() *Tracker {
+	return &Tracker{models: make(map[string]struct{})}
+}
+
+// Record adds one call's usage. c may be nil when the model has no pricing.
+func (t *Tracker) Record(usage *Usage, c *Cost) {
+	if t == nil || usage == nil {
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.calls++
+	t.usage.InputTokens += usage.InputTokens
+	t.usage.OutputTokens += usage.OutputTokens
+	t.usage.CachedTokens += usage.CachedTokens
+	total := usage.TotalTokens
+	if total == 0 {
+		total = usage.InputTokens + usage.OutputTokens
+	}
+	t.usage.TotalTokens += total
+	if usage.Model != "" {
+		t.models[usage.Model] = struct{}{}
+	}
+	if c != nil {
+		t.costUSD += c.TotalCost
+	}
+}
+
+// Usage returns the summed usage. Model is set only when every recorded call
+// used the same model.
+func (t *Tracker) Usage() Usage {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	u := t.usage
+	if len(t.models) == 1 {
+		for m := range t.models {
+			u.Model = m
+		}
+	}
+	return u
+}
+
+// CostUSD returns the summed cost of calls with known pricing
+func (t *Tracker) CostUSD() float64 {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.costUSD
+}
+
+// Calls returns the number of recorded calls
+func (t *Tracker) Calls() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.calls
+}
+
+type trackerKey struct{}
+
+// ContextWithTracker returns a context whose LLM calls are recorded in t
+func ContextWithTracker(ctx context.Context, t *Tracker) context.Context {
+	return context.WithValue(ctx, trackerKey{}, t)
+}
+
+// TrackerFromContext returns the tracker attached to ctx, if any
+func TrackerFromContext(ctx context.Context) (*Tracker, bool) {
+	t, ok := ctx.Value(trackerKey{}).(*Tracker)
+	return t, ok
+}
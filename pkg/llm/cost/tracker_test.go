@@ -0,0 +1,53 @@
+package cost
+
+import (
+	"context"
+	"sync"
+	"testing"
+)
+
+func TestTracker(t *testing.T) {
+	tracker := NewTracker()
+	ctx := ContextWithTracker(context.Background(), tracker)
+
+	got, ok := TrackerFromContext(ctx)
+	if !ok || got != tracker {
+		t.Fatal("TrackerFromContext did not return the attached tracker")
+	}
+	if _, ok := TrackerFromContext(context.Background()); ok {
+		t.Error("TrackerFromContext found a tracker on an empty context")
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			got.Record(&Usage{Model: "gpt-4o", InputTokens: 100, OutputTokens: 50}, &Cost{TotalCost: 0.01})
+		}()
+	}
+	wg.Wait()
+
+	usage := tracker.Usage()
+	if usage.InputTokens != 1000 || usage.OutputTokens != 500 || usage.TotalTokens != 1500 {
+		t.Errorf("Usage() = %+v, want 1000 in / 500 out / 1500 total", usage)
+	}
+	if usage.Model != "gpt-4o" {
+		t.Errorf("Usage().Model = %q, want gpt-4o", usage.Model)
+	}
+	if tracker.Calls() != 10 {
+		t.Errorf("Calls() = %d, want 10", tracker.Calls())
+	}
+	if diff := tracker.CostUSD() - 0.1; diff > 1e-9 || diff < -1e-9 {
+		t.Errorf("CostUSD() = %f, want 0.1", tracker.CostUSD())
+	}
+
+	// Unpriced calls add tokens but no cost, and mixed models clear Model
+	tracker.Record(&Usage{Model: "unknown", InputTokens: 10, TotalTokens: 10}, nil)
+	if tracker.Usage().Model != "" {
+		t.Errorf("Usage().Model = %q, want empty for mixed models", tracker.Usage().Model)
+	}
+	if tracker.Usage().TotalTokens != 1510 {
+		t.Errorf("Usage().TotalTokens = %d, want 1510", tracker.Usage().TotalTokens)
+	}
+}
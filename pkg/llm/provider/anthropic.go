@@ -62,12 +62,18 @@ func (p *AnthropicProvider) Name() string {
 	return "anthropic"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *AnthropicProvider) retriesTransientErrors() {}
+
 type anthropicRequest struct {
 	Model       string             `json:"model"`
 	Messages    []anthropicMessage `json:"messages"`
 	System      string             `json:"system,omitempty"`
 	MaxTokens   int                `json:"max_tokens"`
 	Temperature float64            `json:"temperature,omitempty"`
+	TopP        float64            `json:"top_p,omitempty"`
+	Stop        []string           `json:"stop_sequences,omitempty"`
 	Tools       []anthropicTool    `json:"tools,omitempty"`
 	Stream      bool               `json:"stream,omitempty"`
 }
@@ -128,6 +134,13 @@ func (p *AnthropicProvider) CreateCompletion(ctx context.Context, req Completion
 	return p.parseResponse(&resp)
 }
 
+// CreateWithTools creates a message offering tools as Anthropic tool
+// definitions. Requested tool_use blocks are returned in ToolCalls.
+func (p *AnthropicProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response
 func (p *AnthropicProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	// Anthropic uses tool_use for structured output
@@ -140,7 +153,7 @@ func (p *AnthropicProvider) CreateStructured(ctx context.Context, req Structured
 	modReq := req.CompletionRequest
 	if len(req.ResponseSchema) > 0 {
 		modReq.Tools = append(modReq.Tools, Tool{
-			Name:        "structured_output",
+			Name:        StructuredOutputTool,
 			Description: "Use this tool to provide your structured response",
 			Parameters:  req.ResponseSchema,
 		})
@@ -166,7 +179,7 @@ func (p *AnthropicProvider) CreateStructured(ctx context.Context, req Structured
 	// Extract structured data from tool_use
 	var data json.RawMessage
 	for _, block := range resp.Content {
-		if block.Type == "tool_use" && block.Name == "structured_output" {
+		if block.Type == "tool_use" && block.Name == StructuredOutputTool {
 			data = block.Input
 			break
 		}
@@ -225,11 +238,36 @@ func (p *AnthropicProvider) buildRequest(req CompletionRequest, model string, st
 	messages := make([]anthropicMessage, 0, len(req.Messages))
 
 	for _, m := range req.Messages {
-		if m.Role == "system" {
+		switch {
+		case m.Role == "system":
 			system = m.Content
-			continue
+		case m.Role == "tool":
+			// Tool results go back as tool_result blocks of a user turn;
+			// consecutive results share one turn
+			block := anthropicContentBlock{Type: "tool_result", ToolUseID: m.ToolCallID, Content: m.Content}
+			if n := len(messages); n > 0 && messages[n-1].Role == "user" {
+				if blocks, ok := messages[n-1].Content.([]anthropicContentBlock); ok {
+					messages[n-1].Content = append(blocks, block)
+					continue
+				}
+			}
+			messages = append(messages, anthropicMessage{Role: "user", Content: []anthropicContentBlock{block}})
+		case len(m.ToolCalls) > 0:
+			var blocks []anthropicContentBlock
+			if m.Content != "" {
+				blocks = append(blocks, anthropicContentBlock{Type: "text", Text: m.Content})
+			}
+			for _, tc := range m.ToolCalls {
+				input := tc.Function.Arguments
+				if len(input) == 0 {
+					input = json.RawMessage("{}")
+				}
+				blocks = append(blocks, anthropicContentBlock{Type: "tool_use", ID: tc.ID, Name: tc.Function.Name, Input: input})
+			}
+			messages = append(messages, anthropicMessage{Role: m.Role, Content: blocks})
+		default:
+			messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
 		}
-		messages = append(messages, anthropicMessage{Role: m.Role, Content: m.Content})
 	}
 
 	maxTokens := req.MaxTokens
@@ -243,6 +281,8 @@ func (p *AnthropicProvider) buildRequest(req CompletionRequest, model string, st
 		System:      system,
 		MaxTokens:   maxTokens,
 		Temperature: req.Temperature,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
 		Stream:      stream,
 	}
 
@@ -270,6 +310,9 @@ func (p *AnthropicProvider) doRequestWithRetry(ctx context.Context, endpoint str
 	for attempt := 0; attempt < anthropicMaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			if after := retryAfterOf(lastErr); after > 0 {
+				delay = min(after, maxRetryAfter)
+			}
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -337,6 +380,7 @@ func (p *AnthropicProvider) handleErrorResponse(resp *http.Response) error {
 			Type:        errResp.Error.Type,
 			StatusCode:  resp.StatusCode,
 			IsRetryable: code == ErrorCodeRateLimit || code == ErrorCodeServerError,
+			RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
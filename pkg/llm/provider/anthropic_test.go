@@ -6,6 +6,7 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
 )
 
@@ -230,3 +231,73 @@ func TestAnthropicProvider_Factory(t *testing.T) {
 		t.Errorf("expected 'anthropic', got %s", p.Name())
 	}
 }
+
+func TestAnthropicProvider_SamplingOptions(t *testing.T) {
+	p := &AnthropicProvider{}
+	req := p.buildRequest(CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		TopP:     0.8,
+		Stop:     []string{"END"},
+	}, "claude-3-5-haiku", false)
+
+	if req.TopP != 0.8 || len(req.Stop) != 1 || req.Stop[0] != "END" {
+		t.Errorf("TopP = %v, Stop = %v; want 0.8 and [END]", req.TopP, req.Stop)
+	}
+	data, _ := json.Marshal(req)
+	if !strings.Contains(string(data), `"stop_sequences":["END"]`) {
+		t.Errorf("request = %s, want stop_sequences", data)
+	}
+}
+
+func TestAnthropicProvider_CreateWithToolsContinuesTurn(t *testing.T) {
+	var req map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, &req)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(anthropicResponse{
+			Role:       "assistant",
+			Content:    []anthropicContentBlock{{Type: "text", Text: "Sunny in both."}},
+			StopReason: "end_turn",
+		})
+	}))
+	defer server.Close()
+
+	p := NewAnthropicProvider("test-key", server.URL)
+	calls := []ToolCall{
+		{ID: "toolu_1", Function: FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"Paris"}`)}},
+		{ID: "toolu_2", Function: FunctionCall{Name: "get_weather", Arguments: json.RawMessage(`{"location":"Rome"}`)}},
+	}
+	resp, err := CreateWithTools(context.Background(), p, CompletionRequest{
+		Messages: []Message{
+			{Role: "user", Content: "Weather in Paris and Rome?"},
+			{Role: "assistant", Content: "Checking.", ToolCalls: calls},
+			{Role: "tool", Content: "sunny", ToolCallID: "toolu_1"},
+			{Role: "tool", Content: "sunny", ToolCallID: "toolu_2"},
+		},
+	}, []ToolSpec{{Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)}})
+	if err != nil {
+		t.Fatalf("CreateWithTools() error = %v", err)
+	}
+	if resp.Content != "Sunny in both." {
+		t.Errorf("Content = %q", resp.Content)
+	}
+
+	if tools, _ := req["tools"].([]any); len(tools) != 1 {
+		t.Errorf("request tools = %v, want one", req["tools"])
+	}
+	messages, _ := req["messages"].([]any)
+	if len(messages) != 3 {
+		t.Fatalf("request messages = %v, want user, assistant, and one user turn of results", req["messages"])
+	}
+
+	assistant, _ := messages[1].(map[string]any)["content"].([]any)
+	if len(assistant) != 3 || assistant[1].(map[string]any)["type"] != "tool_use" || assistant[1].(map[string]any)["id"] != "toolu_1" {
+		t.Errorf("assistant content = %v, want text and two tool_use blocks", assistant)
+	}
+	results := messages[2].(map[string]any)
+	blocks, _ := results["content"].([]any)
+	if results["role"] != "user" || len(blocks) != 2 || blocks[1].(map[string]any)["tool_use_id"] != "toolu_2" {
+		t.Errorf("results message = %v, want two tool_result blocks", results)
+	}
+}
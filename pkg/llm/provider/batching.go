@@ -0,0 +1,181 @@
+package provider
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// BatchProvider is implemented by providers that can complete several
+// requests in a single call, e.g. through a discounted batch API
+type BatchProvider interface {
+	Provider
+
+	// CreateCompletionBatch completes requests together, returning one
+	// response per request in the same order
+	CreateCompletionBatch(ctx context.Context, requests []CompletionRequest) ([]*CompletionResponse, error)
+}
+
+// BatchingProvider coalesces CreateCompletion calls arriving within a short
+// window into one batched request to a wrapped BatchProvider. It suits
+// high-volume, latency-tolerant workloads such as bulk classification.
+// Other methods, and every call to a provider without a batch API, pass
+// straight through.
+type BatchingProvider struct {
+	provider Provider
+	window   time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	pending []*batchCall
+	timer   *time.Timer
+}
+
+type batchCall struct {
+	ctx     context.Context
+	request CompletionRequest
+	done    chan batchOutcome
+}
+
+type batchOutcome struct {
+	response *CompletionResponse
+	err      error
+}
+
+// NewBatchingProvider wraps p so that CreateCompletion calls are held for up
+// to window and sent together, or as soon as maxBatch calls are waiting
+// (maxBatch <= 0 means no limit). Each batch is a single
+// CreateCompletionBatch call. Batching is disabled when window <= 0 or p
+// does not implement BatchProvider, since holding calls would only add
+// latency.
+//
+// A batch runs detached from the callers' cancellation; a caller whose
+// context ends stops waiting, and calls already cancelled when the batch is
+// sent are left out.
+func NewBatchingProvider(p Provider, window time.Duration, maxBatch int) *BatchingProvider {
+	return &BatchingProvider{
+		provider: p,
+		window:   window,
+		maxBatch: maxBatch,
+	}
+}
+
+// Name returns the wrapped provider's name
+func (b *BatchingProvider) Name() string {
+	return b.provider.Name()
+}
+
+// CreateCompletion queues the request for the next batch and waits for its
+// response
+func (b *BatchingProvider) CreateCompletion(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
+	bp, ok := b.provider.(BatchProvider)
+	if !ok || b.window <= 0 {
+		return b.provider.CreateCompletion(ctx, request)
+	}
+
+	call := &batchCall{ctx: ctx, request: request, done: make(chan batchOutcome, 1)}
+
+	b.mu.Lock()
+	b.pending = append(b.pending, call)
+	var full []*batchCall
+	if b.maxBatch > 0 && len(b.pending) >= b.maxBatch {
+		full = b.takePendingLocked()
+	} else if b.timer == nil {
+		b.timer = time.AfterFunc(b.window, b.flushPending)
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		go b.send(bp, full)
+	}
+
+	select {
+	case out := <-call.done:
+		return out.response, out.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// CreateWithTools passes the request to the wrapped provider's native tool
+// calling without batching
+func (b *BatchingProvider) CreateWithTools(ctx context.Context, request CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	return CreateWithTools(ctx, b.provider, request, tools)
+}
+
+// CreateStructured passes the request to the wrapped provider
+func (b *BatchingProvider) CreateStructured(ctx context.Context, request StructuredRequest) (*StructuredResponse, error) {
+	return b.provider.CreateStructured(ctx, request)
+}
+
+// CreateStreaming passes the request to the wrapped provider
+func (b *BatchingProvider) CreateStreaming(ctx context.Context, request CompletionRequest) (Stream, error) {
+	return b.provider.CreateStreaming(ctx, request)
+}
+
+// ListModels returns the wrapped provider's models
+func (b *BatchingProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return b.provider.ListModels(ctx)
+}
+
+// takePendingLocked removes and returns the waiting calls. b.mu must be held.
+func (b *BatchingProvider) takePendingLocked() []*batchCall {
+	batch := b.pending
+	b.pending = nil
+	if b.timer != nil {
+		b.timer.Stop()
+		b.timer = nil
+	}
+	return batch
+}
+
+// flushPending sends the waiting calls once the window closes
+func (b *BatchingProvider) flushPending() {
+	b.mu.Lock()
+	batch := b.takePendingLocked()
+	b.mu.Unlock()
+
+	if len(batch) > 0 {
+		b.send(b.provider.(BatchProvider), batch)
+	}
+}
+
+// send completes a batch and hands each caller its outcome
+func (b *BatchingProvider) send(bp BatchProvider, batch []*batchCall) {
+	live := batch[:0]
+	for _, call := range batch {
+		if call.ctx.Err() == nil {
+			live = append(live, call)
+		}
+	}
+
+	switch len(live) {
+	case 0:
+		return
+	case 1:
+		call := live[0]
+		resp, err := bp.CreateCompletion(call.ctx, call.request)
+		call.done <- batchOutcome{response: resp, err: err}
+		return
+	}
+
+	requests := make([]CompletionRequest, len(live))
+	for i, call := range live {
+		requests[i] = call.request
+	}
+
+	// Keep the first caller's context values (e.g. trace spans) without its
+	// cancellation, since the batch serves every caller
+	responses, err := bp.CreateCompletionBatch(context.WithoutCancel(live[0].ctx), requests)
+	if err == nil && len(responses) != len(live) {
+		err = fmt.Errorf("batch returned %d responses for %d requests", len(responses), len(live))
+	}
+	for i, call := range live {
+		if err != nil {
+			call.done <- batchOutcome{err: fmt.Errorf("batch completion failed: %w", err)}
+			continue
+		}
+		call.done <- batchOutcome{response: responses[i]}
+	}
+}
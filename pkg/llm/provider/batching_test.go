@@ -0,0 +1,135 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+// echoBatchProvider answers each request with its user message and records
+// the size of every batch
+type echoBatchProvider struct {
+	MockProvider
+	mu      sync.Mutex
+	batches []int
+	singles int
+	err     error
+}
+
+func (p *echoBatchProvider) CreateCompletion(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	p.mu.Lock()
+	p.singles++
+	p.mu.Unlock()
+	return &CompletionResponse{Content: req.Messages[0].Content}, nil
+}
+
+func (p *echoBatchProvider) CreateCompletionBatch(ctx context.Context, reqs []CompletionRequest) ([]*CompletionResponse, error) {
+	p.mu.Lock()
+	p.batches = append(p.batches, len(reqs))
+	p.mu.Unlock()
+	if p.err != nil {
+		return nil, p.err
+	}
+	responses := make([]*CompletionResponse, len(reqs))
+	for i, req := range reqs {
+		responses[i] = &CompletionResponse{Content: req.Messages[0].Content}
+	}
+	return responses, nil
+}
+
+// completeConcurrently issues n calls at once and returns their contents
+func completeConcurrently(t *testing.T, p Provider, n int) []string {
+	t.Helper()
+	contents := make([]string, n)
+	errs := make([]error, n)
+	var wg sync.WaitGroup
+	for i := range n {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			req := CompletionRequest{Messages: []Message{{Role: "user", Content: fmt.Sprintf("ticket %d", i)}}}
+			resp, err := p.CreateCompletion(context.Background(), req)
+			if err == nil {
+				contents[i] = resp.Content
+			}
+			errs[i] = err
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		t.Fatalf("CreateCompletion() error = %v", err)
+	}
+	return contents
+}
+
+func TestBatchingProviderCoalesces(t *testing.T) {
+	tests := []struct {
+		name     string
+		window   time.Duration
+		maxBatch int
+		calls    int
+		want     []int
+	}{
+		{"window closes", 50 * time.Millisecond, 0, 4, []int{4}},
+		{"max batch flushes early", time.Hour, 3, 6, []int{3, 3}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := &echoBatchProvider{MockProvider: *NewMockProvider("test")}
+			p := NewBatchingProvider(inner, tt.window, tt.maxBatch)
+
+			contents := completeConcurrently(t, p, tt.calls)
+			for i, content := range contents {
+				if want := fmt.Sprintf("ticket %d", i); content != want {
+					t.Errorf("call %d got %q, want %q", i, content, want)
+				}
+			}
+			if fmt.Sprint(inner.batches) != fmt.Sprint(tt.want) || inner.singles != 0 {
+				t.Errorf("batches = %v, singles = %d, want %v", inner.batches, inner.singles, tt.want)
+			}
+		})
+	}
+}
+
+func TestBatchingProviderWithoutBatchAPI(t *testing.T) {
+	inner := &flakyProvider{MockProvider: *NewMockProvider("test")}
+	p := NewBatchingProvider(&struct{ Provider }{inner}, time.Hour, 0)
+
+	// Without a batch API the call is not held for the window
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+	resp, err := p.CreateCompletion(ctx, CompletionRequest{})
+	if err != nil || resp.Content != "ok" || inner.calls != 1 {
+		t.Errorf("CreateCompletion() = %v, %v after %d calls", resp, err, inner.calls)
+	}
+}
+
+func TestBatchingProviderErrors(t *testing.T) {
+	boom := errors.New("batch rejected")
+	inner := &echoBatchProvider{MockProvider: *NewMockProvider("test"), err: boom}
+	p := NewBatchingProvider(inner, time.Hour, 2)
+
+	var wg sync.WaitGroup
+	for range 2 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := p.CreateCompletion(context.Background(), CompletionRequest{Messages: []Message{{Content: "x"}}})
+			if !errors.Is(err, boom) {
+				t.Errorf("CreateCompletion() error = %v, want %v", err, boom)
+			}
+		}()
+	}
+	wg.Wait()
+
+	// A cancelled caller stops waiting and is left out of the batch
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := NewBatchingProvider(inner, time.Hour, 0).CreateCompletion(ctx, CompletionRequest{}); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("CreateCompletion() error = %v, want deadline exceeded", err)
+	}
+}
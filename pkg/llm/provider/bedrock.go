@@ -116,6 +116,10 @@ func (p *BedrockProvider) Name() string {
 	return "bedrock"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *BedrockProvider) retriesTransientErrors() {}
+
 // CreateCompletion creates a completion using the Converse API
 func (p *BedrockProvider) CreateCompletion(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
 	modelID := p.normalizeModelID(req.Model)
@@ -160,6 +164,13 @@ func (p *BedrockProvider) CreateCompletion(ctx context.Context, req CompletionRe
 	return p.parseConverseResponse(resp)
 }
 
+// CreateWithTools creates a completion offering tools through the Converse
+// API tool configuration. Requested toolUse blocks are returned in ToolCalls.
+func (p *BedrockProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response using tool-based approach
 func (p *BedrockProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	modelID := p.normalizeModelID(req.Model)
@@ -171,7 +182,7 @@ func (p *BedrockProvider) CreateStructured(ctx context.Context, req StructuredRe
 	modReq := req.CompletionRequest
 	if len(req.ResponseSchema) > 0 {
 		modReq.Tools = append(modReq.Tools, Tool{
-			Name:        "structured_output",
+			Name:        StructuredOutputTool,
 			Description: "Use this tool to provide your structured response",
 			Parameters:  req.ResponseSchema,
 		})
@@ -223,7 +234,7 @@ func (p *BedrockProvider) CreateStructured(ctx context.Context, req StructuredRe
 	// Extract structured data from tool_use
 	var data json.RawMessage
 	for _, tc := range compResp.ToolCalls {
-		if tc.Function.Name == "structured_output" {
+		if tc.Function.Name == StructuredOutputTool {
 			data = tc.Function.Arguments
 			break
 		}
@@ -382,6 +393,17 @@ func (p *BedrockProvider) buildConverseInput(req CompletionRequest, modelID stri
 		hasConfig = true
 	}
 
+	if req.TopP > 0 {
+		topP := float32(req.TopP)
+		inferenceConfig.TopP = &topP
+		hasConfig = true
+	}
+
+	if len(req.Stop) > 0 {
+		inferenceConfig.StopSequences = req.Stop
+		hasConfig = true
+	}
+
 	if hasConfig {
 		input.InferenceConfig = inferenceConfig
 	}
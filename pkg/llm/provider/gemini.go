@@ -61,6 +61,10 @@ func (p *GeminiProvider) Name() string {
 	return "gemini"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *GeminiProvider) retriesTransientErrors() {}
+
 type geminiRequest struct {
 	Contents          []geminiContent  `json:"contents"`
 	SystemInstruction *geminiContent   `json:"systemInstruction,omitempty"`
@@ -90,10 +94,12 @@ type geminiFuncResp struct {
 }
 
 type geminiGenConfig struct {
-	Temperature      float64 `json:"temperature,omitempty"`
-	MaxOutputTokens  int     `json:"maxOutputTokens,omitempty"`
-	ResponseMimeType string  `json:"responseMimeType,omitempty"`
-	ResponseSchema   any     `json:"responseSchema,omitempty"`
+	Temperature      float64  `json:"temperature,omitempty"`
+	MaxOutputTokens  int      `json:"maxOutputTokens,omitempty"`
+	TopP             float64  `json:"topP,omitempty"`
+	StopSequences    []string `json:"stopSequences,omitempty"`
+	ResponseMimeType string   `json:"responseMimeType,omitempty"`
+	ResponseSchema   any      `json:"responseSchema,omitempty"`
 }
 
 type geminiTool struct {
@@ -145,6 +151,13 @@ func (p *GeminiProvider) CreateCompletion(ctx context.Context, req CompletionReq
 	return p.parseResponse(&resp)
 }
 
+// CreateWithTools creates a completion offering tools as function
+// declarations. Requested functionCall parts are returned in ToolCalls.
+func (p *GeminiProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response
 func (p *GeminiProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	model := req.Model
@@ -250,10 +263,12 @@ func (p *GeminiProvider) buildRequest(req CompletionRequest) geminiRequest {
 		SystemInstruction: systemContent,
 	}
 
-	if req.Temperature != 0 || req.MaxTokens != 0 {
+	if req.Temperature != 0 || req.MaxTokens != 0 || req.TopP != 0 || len(req.Stop) > 0 {
 		gReq.GenerationConfig = &geminiGenConfig{
 			Temperature:     req.Temperature,
 			MaxOutputTokens: req.MaxTokens,
+			TopP:            req.TopP,
+			StopSequences:   req.Stop,
 		}
 	}
 
@@ -286,6 +301,9 @@ func (p *GeminiProvider) doRequestWithRetry(ctx context.Context, endpoint string
 	for attempt := 0; attempt < geminiMaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			if after := retryAfterOf(lastErr); after > 0 {
+				delay = min(after, maxRetryAfter)
+			}
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -351,6 +369,7 @@ func (p *GeminiProvider) handleErrorResponse(resp *http.Response) error {
 			Type:        errResp.Error.Status,
 			StatusCode:  resp.StatusCode,
 			IsRetryable: code == ErrorCodeRateLimit || code == ErrorCodeServerError,
+			RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
@@ -304,3 +304,85 @@ func TestGeminiProvider_RoleMapping(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// geminiStructuredFixture is a recorded generateContent response for a JSON
+// mode request
+const geminiStructuredFixture = `{
+  "candidates": [
+    {
+      "content": {
+        "parts": [{"text": "{\"city\": \"Paris\", \"country\": \"France\"}"}],
+        "role": "model"
+      },
+      "finishReason": "STOP",
+      "avgLogprobs": -0.0123,
+      "safetyRatings": [
+        {"category": "HARM_CATEGORY_HATE_SPEECH", "probability": "NEGLIGIBLE"}
+      ]
+    }
+  ],
+  "usageMetadata": {
+    "promptTokenCount": 21,
+    "candidatesTokenCount": 12,
+    "totalTokenCount": 33
+  },
+  "modelVersion": "gemini-1.5-flash-002"
+}`
+
+func TestGeminiProvider_CreateStructured(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.HasSuffix(r.URL.Path, "/models/gemini-1.5-pro:generateContent") {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+
+		body, _ := io.ReadAll(r.Body)
+		var req map[string]any
+		_ = json.Unmarshal(body, &req)
+
+		if _, ok := req["systemInstruction"].(map[string]any); !ok {
+			t.Error("system message should be sent as systemInstruction")
+		}
+		config, _ := req["generationConfig"].(map[string]any)
+		if config["responseMimeType"] != "application/json" {
+			t.Errorf("expected responseMimeType application/json, got %v", config["responseMimeType"])
+		}
+		if _, ok := config["responseSchema"].(map[string]any); !ok {
+			t.Error("expected responseSchema in generationConfig")
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = io.WriteString(w, geminiStructuredFixture)
+	}))
+	defer server.Close()
+
+	p := NewGeminiProvider("test-key", server.URL)
+	resp, err := p.CreateStructured(context.Background(), StructuredRequest{
+		CompletionRequest: CompletionRequest{
+			Messages: []Message{
+				{Role: "system", Content: "Answer in JSON."},
+				{Role: "user", Content: "Where is the Eiffel Tower?"},
+			},
+			Model: "gemini-1.5-pro",
+		},
+		ResponseSchema: json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"},"country":{"type":"string"}}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var place struct {
+		City    string `json:"city"`
+		Country string `json:"country"`
+	}
+	if err := json.Unmarshal(resp.Data, &place); err != nil {
+		t.Fatalf("structured data is not JSON: %v", err)
+	}
+	if place.City != "Paris" || place.Country != "France" {
+		t.Errorf("unexpected data %s", resp.Data)
+	}
+
+	want := Usage{PromptTokens: 21, CompletionTokens: 12, TotalTokens: 33}
+	if resp.Usage != want {
+		t.Errorf("expected usage %+v, got %+v", want, resp.Usage)
+	}
+}
@@ -54,8 +54,26 @@ func NewInstrumentedProvider(provider Provider, config *InstrumentedConfig) *Ins
 
 // CreateCompletion creates a completion with automatic instrumentation
 func (p *InstrumentedProvider) CreateCompletion(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
-	if !p.enabled {
+	return p.instrumentCompletion(ctx, request, func(ctx context.Context) (*CompletionResponse, error) {
 		return p.provider.CreateCompletion(ctx, request)
+	})
+}
+
+// CreateWithTools calls the wrapped provider's native tool calling with the
+// same instrumentation as CreateCompletion. It returns ErrToolsNotSupported
+// if the wrapped provider has none.
+func (p *InstrumentedProvider) CreateWithTools(ctx context.Context, request CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	request.Tools = tools
+	return p.instrumentCompletion(ctx, request, func(ctx context.Context) (*CompletionResponse, error) {
+		return CreateWithTools(ctx, p.provider, request, tools)
+	})
+}
+
+// instrumentCompletion runs call inside a completion span, recording its
+// usage and cost
+func (p *InstrumentedProvider) instrumentCompletion(ctx context.Context, request CompletionRequest, call func(ctx context.Context) (*CompletionResponse, error)) (*CompletionResponse, error) {
+	if !p.enabled {
+		return call(ctx)
 	}
 
 	// Create span for this completion
@@ -75,7 +93,7 @@ func (p *InstrumentedProvider) CreateCompletion(ctx context.Context, request Com
 	startTime := time.Now()
 
 	// Call underlying provider
-	response, err := p.provider.CreateCompletion(ctx, request)
+	response, err := call(ctx)
 
 	// Track duration
 	duration := time.Since(startTime)
@@ -109,13 +127,17 @@ func (p *InstrumentedProvider) CreateCompletion(ctx context.Context, request Com
 			TotalTokens:  response.Usage.TotalTokens,
 		}
 
-		if costResult, err := p.calculator.Calculate(usage); err == nil {
+		costResult, costErr := p.calculator.Calculate(usage)
+		if costErr == nil {
 			span.SetAttributes(
 				attribute.Float64("llm.cost.input_usd", costResult.InputCost),
 				attribute.Float64("llm.cost.output_usd", costResult.OutputCost),
 				attribute.Float64("llm.cost.total_usd", costResult.TotalCost),
 			)
 		}
+		if tracker, ok := cost.TrackerFromContext(ctx); ok {
+			tracker.Record(usage, costResult)
+		}
 
 		// Track tool calls
 		if len(response.ToolCalls) > 0 {
@@ -173,13 +195,17 @@ func (p *InstrumentedProvider) CreateStructured(ctx context.Context, request Str
 			TotalTokens:  response.Usage.TotalTokens,
 		}
 
-		if costResult, err := p.calculator.Calculate(usage); err == nil {
+		costResult, costErr := p.calculator.Calculate(usage)
+		if costErr == nil {
 			span.SetAttributes(
 				attribute.Float64("llm.cost.input_usd", costResult.InputCost),
 				attribute.Float64("llm.cost.output_usd", costResult.OutputCost),
 				attribute.Float64("llm.cost.total_usd", costResult.TotalCost),
 			)
 		}
+		if tracker, ok := cost.TrackerFromContext(ctx); ok {
+			tracker.Record(usage, costResult)
+		}
 	}
 
 	return response, nil
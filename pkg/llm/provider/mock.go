@@ -5,6 +5,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/rand"
+	"strings"
 )
 
 // MockProvider is a mock LLM provider for testing
@@ -23,6 +25,19 @@ type MockProvider struct {
 	StreamCalls     []CompletionRequest
 
 	currentIndex int
+
+	// keyed completion responses, see AddResponse
+	keyed []keyedResponse
+
+	// rng varies default responses reproducibly, see NewMockProviderWithSeed
+	rng  *rand.Rand
+	seed int64
+}
+
+// keyedResponse is returned for requests whose last message contains match
+type keyedResponse struct {
+	match    string
+	response CompletionResponse
 }
 
 // NewMockProvider creates a new mock provider
@@ -40,10 +55,26 @@ func NewMockProvider(name string) *MockProvider {
 	}
 }
 
-// CreateCompletion implements Provider
+// NewMockProviderWithSeed creates a mock provider whose default completions
+// vary pseudo-randomly, as real model output does, but repeat exactly for
+// the same seed.
+func NewMockProviderWithSeed(name string, seed int64) *MockProvider {
+	m := NewMockProvider(name)
+	m.seed = seed
+	m.rng = rand.New(rand.NewSource(seed))
+	return m
+}
+
+// CreateCompletion implements Provider. A response added with AddResponse
+// whose match the last message contains takes precedence over the queued
+// responses and the default.
 func (m *MockProvider) CreateCompletion(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
 	m.CompletionCalls = append(m.CompletionCalls, request)
 
+	if response, ok := m.keyedResponse(request); ok {
+		return response, nil
+	}
+
 	// Check for errors first
 	if m.currentIndex < len(m.Errors) && m.Errors[m.currentIndex] != nil {
 		err := m.Errors[m.currentIndex]
@@ -59,6 +90,18 @@ func (m *MockProvider) CreateCompletion(ctx context.Context, request CompletionR
 	}
 
 	// Default response
+	if m.rng != nil {
+		completionTokens := 1 + m.rng.Intn(50)
+		return &CompletionResponse{
+			Content:      fmt.Sprintf("Mock response %d", m.rng.Intn(1000000)),
+			FinishReason: "stop",
+			Usage: Usage{
+				PromptTokens:     10,
+				CompletionTokens: completionTokens,
+				TotalTokens:      10 + completionTokens,
+			},
+		}, nil
+	}
 	return &CompletionResponse{
 		Content:      "Mock response",
 		FinishReason: "stop",
@@ -146,6 +189,28 @@ func (m *MockProvider) AddCompletionResponse(response *CompletionResponse) *Mock
 	return m
 }
 
+// AddResponse returns response for every completion request whose last
+// message contains match. Responses are checked in the order they were added.
+func (m *MockProvider) AddResponse(match string, response CompletionResponse) *MockProvider {
+	m.keyed = append(m.keyed, keyedResponse{match: match, response: response})
+	return m
+}
+
+// keyedResponse returns a copy of the first keyed response matching request
+func (m *MockProvider) keyedResponse(request CompletionRequest) (*CompletionResponse, bool) {
+	if len(m.keyed) == 0 || len(request.Messages) == 0 {
+		return nil, false
+	}
+	prompt := request.Messages[len(request.Messages)-1].Content
+	for _, k := range m.keyed {
+		if strings.Contains(prompt, k.match) {
+			response := k.response
+			return &response, true
+		}
+	}
+	return nil, false
+}
+
 // AddStructuredResponse adds a structured response to return
 func (m *MockProvider) AddStructuredResponse(response *StructuredResponse) *MockProvider {
 	m.StructuredResponses = append(m.StructuredResponses, response)
@@ -174,6 +239,10 @@ func (m *MockProvider) Reset() {
 	m.StructuredCalls = []StructuredRequest{}
 	m.StreamCalls = []CompletionRequest{}
 	m.currentIndex = 0
+	m.keyed = nil
+	if m.rng != nil {
+		m.rng = rand.New(rand.NewSource(m.seed))
+	}
 }
 
 // MockStream is a mock stream implementation
@@ -0,0 +1,73 @@
+package provider
+
+import (
+	"context"
+	"reflect"
+	"testing"
+)
+
+func completionContents(t *testing.T, m *MockProvider, n int) []string {
+	t.Helper()
+	contents := make([]string, n)
+	for i := range contents {
+		resp, err := m.CreateCompletion(context.Background(), CompletionRequest{
+			Messages: []Message{{Role: "user", Content: "hello"}},
+		})
+		if err != nil {
+			t.Fatalf("CreateCompletion() error = %v", err)
+		}
+		contents[i] = resp.Content
+	}
+	return contents
+}
+
+func TestMockProviderWithSeed(t *testing.T) {
+	first := completionContents(t, NewMockProviderWithSeed("mock", 42), 5)
+	second := completionContents(t, NewMockProviderWithSeed("mock", 42), 5)
+	if !reflect.DeepEqual(first, second) {
+		t.Errorf("same seed gave %v and %v, want identical sequences", first, second)
+	}
+
+	other := completionContents(t, NewMockProviderWithSeed("mock", 7), 5)
+	if reflect.DeepEqual(first, other) {
+		t.Errorf("different seeds gave the same sequence %v", first)
+	}
+
+	// Reset restarts the sequence
+	m := NewMockProviderWithSeed("mock", 42)
+	_ = completionContents(t, m, 2)
+	m.Reset()
+	if got := completionContents(t, m, 5); !reflect.DeepEqual(got, first) {
+		t.Errorf("after Reset() got %v, want %v", got, first)
+	}
+}
+
+func TestMockProviderAddResponse(t *testing.T) {
+	ctx := context.Background()
+	m := NewMockProvider("mock").
+		AddCompletionResponse(MockCompletionResponse("queued")).
+		AddResponse("refund", CompletionResponse{Content: "billing"}).
+		AddResponse("refund policy", CompletionResponse{Content: "shadowed"})
+
+	tests := []struct {
+		prompt string
+		want   string
+	}{
+		{"What is the refund policy?", "billing"},
+		{"Tell me a joke", "queued"},
+		{"Another refund please", "billing"},
+		{"Tell me another joke", "Mock response"},
+	}
+
+	for _, tt := range tests {
+		resp, err := m.CreateCompletion(ctx, CompletionRequest{
+			Messages: []Message{{Role: "system", Content: "You handle refund requests."}, {Role: "user", Content: tt.prompt}},
+		})
+		if err != nil {
+			t.Fatalf("CreateCompletion(%q) error = %v", tt.prompt, err)
+		}
+		if resp.Content != tt.want {
+			t.Errorf("CreateCompletion(%q) = %q, want %q", tt.prompt, resp.Content, tt.want)
+		}
+	}
+}
@@ -0,0 +1,473 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/security"
+	"github.com/google/uuid"
+)
+
+const ollamaBaseURL = "http://localhost:11434"
+
+func init() {
+	RegisterFactory("ollama", func(config map[string]any) (Provider, error) {
+		baseURL := os.Getenv("OLLAMA_HOST")
+		if url, ok := config["base_url"].(string); ok && url != "" {
+			baseURL = url
+		}
+
+		model, _ := config["model"].(string)
+		p := NewOllamaProvider(baseURL, model)
+
+		if keepAlive, ok := config["keep_alive"].(string); ok && keepAlive != "" {
+			d, err := time.ParseDuration(keepAlive)
+			if err != nil {
+				return nil, fmt.Errorf("invalid keep_alive: %w", err)
+			}
+			p.SetKeepAlive(d)
+		}
+
+		return p, nil
+	})
+}
+
+// OllamaProvider implements Provider for a local or self-hosted Ollama
+// server using the /api/chat endpoint. Requests are sent through an
+// SSRF-protected transport limited to the Ollama host allowlist.
+type OllamaProvider struct {
+	baseURL   string
+	model     string
+	keepAlive string
+	client    *http.Client
+}
+
+// NewOllamaProvider creates a new Ollama provider. baseURL defaults to
+// http://localhost:11434; model is used when a request does not set one.
+func NewOllamaProvider(baseURL, model string) *OllamaProvider {
+	if baseURL == "" {
+		baseURL = ollamaBaseURL
+	}
+
+	validator := security.NewOllamaSSRFValidator()
+	return &OllamaProvider{
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		model:   model,
+		client: &http.Client{
+			Timeout:   5 * time.Minute,
+			Transport: validator.CreateSecureTransport(),
+			// Disable following redirects to prevent SSRF via redirect
+			CheckRedirect: func(req *http.Request, via []*http.Request) error {
+				return http.ErrUseLastResponse
+			},
+		},
+	}
+}
+
+// SetKeepAlive sets how long Ollama keeps the model loaded after a request.
+// A negative duration keeps it loaded indefinitely and zero unloads it
+// immediately. Requests can override it with Extra["keep_alive"].
+func (p *OllamaProvider) SetKeepAlive(d time.Duration) {
+	p.keepAlive = d.String()
+}
+
+// Name returns the provider name
+func (p *OllamaProvider) Name() string {
+	return "ollama"
+}
+
+// Ollama API types
+type ollamaRequest struct {
+	Model     string          `json:"model"`
+	Messages  []ollamaMessage `json:"messages"`
+	Stream    bool            `json:"stream"`
+	Format    any             `json:"format,omitempty"`
+	Options   map[string]any  `json:"options,omitempty"`
+	Tools     []ollamaTool    `json:"tools,omitempty"`
+	KeepAlive string          `json:"keep_alive,omitempty"`
+}
+
+type ollamaMessage struct {
+	Role      string           `json:"role"`
+	Content   string           `json:"content"`
+	ToolCalls []ollamaToolCall `json:"tool_calls,omitempty"`
+}
+
+type ollamaTool struct {
+	Type     string             `json:"type"`
+	Function ollamaToolFunction `json:"function"`
+}
+
+type ollamaToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"`
+}
+
+type ollamaToolCall struct {
+	Function struct {
+		Name      string          `json:"name"`
+		Arguments json.RawMessage `json:"arguments"`
+	} `json:"function"`
+}
+
+// ollamaChunk is one line of the /api/chat NDJSON stream. A buffered
+// response is a single chunk with Done set.
+type ollamaChunk struct {
+	Model           string        `json:"model"`
+	Message         ollamaMessage `json:"message"`
+	Done            bool          `json:"done"`
+	DoneReason      string        `json:"done_reason,omitempty"`
+	PromptEvalCount int           `json:"prompt_eval_count,omitempty"`
+	EvalCount       int           `json:"eval_count,omitempty"`
+	Error           string        `json:"error,omitempty"`
+}
+
+// CreateCompletion creates a completion
+func (p *OllamaProvider) CreateCompletion(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	stream, err := p.chat(ctx, p.buildRequest(req, false))
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	return stream.collect()
+}
+
+// CreateWithTools creates a chat completion offering tools through the
+// /api/chat tools field. Requested calls are returned in ToolCalls.
+func (p *OllamaProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
+// CreateStructured creates a structured response using Ollama's JSON mode,
+// constrained by the response schema when one is set
+func (p *OllamaProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
+	ollamaReq := p.buildRequest(req.CompletionRequest, false)
+	ollamaReq.Format = "json"
+	if len(req.ResponseSchema) > 0 {
+		ollamaReq.Format = req.ResponseSchema
+	}
+
+	stream, err := p.chat(ctx, ollamaReq)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = stream.Close()
+	}()
+
+	compResp, err := stream.collect()
+	if err != nil {
+		return nil, err
+	}
+
+	return &StructuredResponse{
+		Data:               json.RawMessage(compResp.Content),
+		CompletionResponse: *compResp,
+	}, nil
+}
+
+// CreateStreaming creates a streaming response. The returned stream is an
+// *OllamaStream, whose Usage reports token counts once the stream is done.
+func (p *OllamaProvider) CreateStreaming(ctx context.Context, req CompletionRequest) (Stream, error) {
+	return p.chat(ctx, p.buildRequest(req, true))
+}
+
+func (p *OllamaProvider) buildRequest(req CompletionRequest, stream bool) ollamaRequest {
+	model := req.Model
+	if model == "" {
+		model = p.model
+	}
+
+	messages := make([]ollamaMessage, len(req.Messages))
+	for i, m := range req.Messages {
+		messages[i] = ollamaMessage{Role: m.Role, Content: m.Content}
+		for _, tc := range m.ToolCalls {
+			var call ollamaToolCall
+			call.Function.Name = tc.Function.Name
+			call.Function.Arguments = tc.Function.Arguments
+			messages[i].ToolCalls = append(messages[i].ToolCalls, call)
+		}
+	}
+
+	oReq := ollamaRequest{
+		Model:     model,
+		Messages:  messages,
+		Stream:    stream,
+		KeepAlive: p.keepAlive,
+	}
+	if keepAlive, ok := req.Extra["keep_alive"].(string); ok && keepAlive != "" {
+		oReq.KeepAlive = keepAlive
+	}
+
+	options := make(map[string]any)
+	if req.Temperature != 0 {
+		options["temperature"] = req.Temperature
+	}
+	if req.MaxTokens > 0 {
+		options["num_predict"] = req.MaxTokens
+	}
+	if req.TopP != 0 {
+		options["top_p"] = req.TopP
+	}
+	if len(req.Stop) > 0 {
+		options["stop"] = req.Stop
+	}
+	if len(options) > 0 {
+		oReq.Options = options
+	}
+
+	for _, t := range req.Tools {
+		oReq.Tools = append(oReq.Tools, ollamaTool{
+			Type: "function",
+			Function: ollamaToolFunction{
+				Name:        t.Name,
+				Description: t.Description,
+				Parameters:  t.Parameters,
+			},
+		})
+	}
+
+	return oReq
+}
+
+// chat posts to /api/chat and returns a stream over the response body
+func (p *OllamaProvider) chat(ctx context.Context, req ollamaRequest) (*OllamaStream, error) {
+	if req.Model == "" {
+		return nil, NewProviderError("ollama", ErrorCodeInvalidRequest, "model is required", nil)
+	}
+
+	body, err := json.Marshal(req)
+	if err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/api/chat", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := p.client.Do(httpReq)
+	if err != nil {
+		return nil, NewProviderError("ollama", ErrorCodeTimeout, err.Error(), err)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		defer func() {
+			_ = resp.Body.Close()
+		}()
+		return nil, p.handleErrorResponse(resp)
+	}
+
+	return &OllamaStream{reader: bufio.NewReader(resp.Body), closer: resp.Body}, nil
+}
+
+func (p *OllamaProvider) handleErrorResponse(resp *http.Response) error {
+	body, _ := io.ReadAll(resp.Body)
+
+	message := string(body)
+	var errResp ollamaChunk
+	if err := json.Unmarshal(body, &errResp); err == nil && errResp.Error != "" {
+		message = errResp.Error
+	}
+
+	code := ErrorCodeUnknown
+	switch {
+	case resp.StatusCode == http.StatusBadRequest:
+		code = ErrorCodeInvalidRequest
+	case resp.StatusCode == http.StatusNotFound:
+		code = ErrorCodeModelNotFound
+	case resp.StatusCode == http.StatusTooManyRequests:
+		code = ErrorCodeRateLimit
+	case resp.StatusCode >= 500:
+		code = ErrorCodeServerError
+	}
+
+	return &ProviderError{
+		Provider:    "ollama",
+		Code:        code,
+		Message:     message,
+		StatusCode:  resp.StatusCode,
+		IsRetryable: code == ErrorCodeRateLimit || code == ErrorCodeServerError,
+	}
+}
+
+// OllamaStream implements Stream over Ollama's newline-delimited JSON
+// responses
+type OllamaStream struct {
+	reader    *bufio.Reader
+	closer    io.Closer
+	usage     Usage
+	toolCalls []ToolCall
+	done      bool
+}
+
+// Recv returns the next chunk. The final chunk carries the finish reason;
+// later calls return io.EOF.
+func (s *OllamaStream) Recv() (*StreamChunk, error) {
+	for {
+		if s.done {
+			return nil, io.EOF
+		}
+
+		line, err := s.reader.ReadBytes('\n')
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 {
+			if errors.Is(err, io.EOF) {
+				return nil, NewProviderError("ollama", ErrorCodeServerError, "stream ended before done", nil)
+			}
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		if err != nil && !errors.Is(err, io.EOF) {
+			return nil, err // An unterminated last line is still decoded
+		}
+
+		var chunk ollamaChunk
+		if err := json.Unmarshal(line, &chunk); err != nil {
+			return nil, NewProviderError("ollama", ErrorCodeUnknown, "malformed stream chunk", err)
+		}
+		if chunk.Error != "" {
+			return nil, NewProviderError("ollama", ErrorCodeServerError, chunk.Error, nil)
+		}
+
+		s.usage.PromptTokens += chunk.PromptEvalCount
+		s.usage.CompletionTokens += chunk.EvalCount
+		s.usage.TotalTokens = s.usage.PromptTokens + s.usage.CompletionTokens
+
+		out := &StreamChunk{Delta: chunk.Message.Content}
+		for _, tc := range chunk.Message.ToolCalls {
+			// Ollama does not identify tool calls, so each gets its own ID
+			// for matching tool results to calls of the same function
+			id := "call_" + uuid.NewString()
+			s.toolCalls = append(s.toolCalls, ToolCall{
+				ID:   id,
+				Type: "function",
+				Function: FunctionCall{
+					Name:      tc.Function.Name,
+					Arguments: tc.Function.Arguments,
+				},
+			})
+			out.ToolCallDeltas = append(out.ToolCallDeltas, ToolCallDelta{
+				Index:         len(s.toolCalls) - 1,
+				ID:            id,
+				Type:          "function",
+				FunctionName:  tc.Function.Name,
+				ArgumentDelta: string(tc.Function.Arguments),
+			})
+		}
+
+		if chunk.Done {
+			s.done = true
+			out.FinishReason = chunk.DoneReason
+			if out.FinishReason == "" {
+				out.FinishReason = "stop"
+			}
+		}
+		return out, nil
+	}
+}
+
+// Usage returns the token counts reported so far. Ollama reports them on
+// the final chunk.
+func (s *OllamaStream) Usage() Usage {
+	return s.usage
+}
+
+// Close closes the stream
+func (s *OllamaStream) Close() error {
+	return s.closer.Close()
+}
+
+// collect reads the stream to completion into a single response
+func (s *OllamaStream) collect() (*CompletionResponse, error) {
+	var content strings.Builder
+	var finishReason string
+
+	for {
+		chunk, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		content.WriteString(chunk.Delta)
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+	}
+
+	return &CompletionResponse{
+		Content:      content.String(),
+		FinishReason: finishReason,
+		Usage:        s.usage,
+		ToolCalls:    s.toolCalls,
+	}, nil
+}
+
+// ollamaTagsResponse represents the response from GET /api/tags
+type ollamaTagsResponse struct {
+	Models []struct {
+		Name    string `json:"name"`
+		Model   string `json:"model"`
+		Details struct {
+			Family        string `json:"family"`
+			ParameterSize string `json:"parameter_size"`
+		} `json:"details"`
+	} `json:"models"`
+}
+
+// ListModels returns the models pulled on the Ollama server
+func (p *OllamaProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", p.baseURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, NewProviderError("ollama", ErrorCodeTimeout, err.Error(), err)
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, p.handleErrorResponse(resp)
+	}
+
+	var tags ollamaTagsResponse
+	if err := json.NewDecoder(resp.Body).Decode(&tags); err != nil {
+		return nil, NewProviderError("ollama", ErrorCodeUnknown, "failed to decode models response", err)
+	}
+
+	models := make([]ModelInfo, 0, len(tags.Models))
+	for _, m := range tags.Models {
+		description := strings.TrimSpace(m.Details.Family + " " + m.Details.ParameterSize)
+		models = append(models, ModelInfo{
+			ID:           m.Name,
+			Name:         m.Name,
+			Provider:     "ollama",
+			Description:  description,
+			Capabilities: []string{"chat"},
+		})
+	}
+	return models, nil
+}
@@ -0,0 +1,281 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+// ollamaChatStream is an /api/chat response split across several NDJSON
+// chunks, as Ollama emits it
+const ollamaChatStream = `{"model":"llama3.2","created_at":"2025-01-01T00:00:00Z","message":{"role":"assistant","content":"Hello"},"done":false}
+{"model":"llama3.2","created_at":"2025-01-01T00:00:00Z","message":{"role":"assistant","content":" from"},"done":false}
+
+{"model":"llama3.2","created_at":"2025-01-01T00:00:00Z","message":{"role":"assistant","content":" Ollama!"},"done":false}
+{"model":"llama3.2","created_at":"2025-01-01T00:00:01Z","message":{"role":"assistant","content":""},"done":true,"done_reason":"stop","total_duration":123456,"prompt_eval_count":12,"eval_count":7}
+`
+
+// newOllamaServer serves body from /api/chat and records the decoded request
+func newOllamaServer(t *testing.T, body string, got *map[string]any) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		data, _ := io.ReadAll(r.Body)
+		if got != nil {
+			_ = json.Unmarshal(data, got)
+		}
+
+		w.Header().Set("Content-Type", "application/x-ndjson")
+		flusher, _ := w.(http.Flusher)
+		for _, line := range strings.SplitAfter(body, "\n") {
+			_, _ = io.WriteString(w, line)
+			if flusher != nil {
+				flusher.Flush()
+			}
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOllamaProvider_Name(t *testing.T) {
+	p := NewOllamaProvider("", "llama3.2")
+	if p.Name() != "ollama" {
+		t.Errorf("expected 'ollama', got %s", p.Name())
+	}
+	if p.baseURL != ollamaBaseURL {
+		t.Errorf("expected default base URL %s, got %s", ollamaBaseURL, p.baseURL)
+	}
+}
+
+func TestOllamaProvider_CreateCompletion(t *testing.T) {
+	var req map[string]any
+	server := newOllamaServer(t, ollamaChatStream, &req)
+
+	p := NewOllamaProvider(server.URL, "llama3.2")
+	p.SetKeepAlive(10 * time.Minute)
+
+	resp, err := p.CreateCompletion(context.Background(), CompletionRequest{
+		Messages:    []Message{{Role: "system", Content: "Be brief."}, {Role: "user", Content: "Hi"}},
+		Temperature: 0.2,
+		MaxTokens:   64,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if resp.Content != "Hello from Ollama!" {
+		t.Errorf("expected accumulated content, got %q", resp.Content)
+	}
+	if resp.FinishReason != "stop" {
+		t.Errorf("expected 'stop', got %s", resp.FinishReason)
+	}
+	want := Usage{PromptTokens: 12, CompletionTokens: 7, TotalTokens: 19}
+	if resp.Usage != want {
+		t.Errorf("expected usage %+v, got %+v", want, resp.Usage)
+	}
+
+	if req["model"] != "llama3.2" || req["stream"] != false {
+		t.Errorf("unexpected request %v", req)
+	}
+	if req["keep_alive"] != "10m0s" {
+		t.Errorf("expected keep_alive 10m0s, got %v", req["keep_alive"])
+	}
+	options, _ := req["options"].(map[string]any)
+	if options["temperature"] != 0.2 || options["num_predict"] != float64(64) {
+		t.Errorf("unexpected options %v", options)
+	}
+	messages, _ := req["messages"].([]any)
+	if first, _ := messages[0].(map[string]any); len(messages) != 2 || first["role"] != "system" {
+		t.Errorf("unexpected messages %v", messages)
+	}
+}
+
+func TestOllamaProvider_CreateStreaming(t *testing.T) {
+	var req map[string]any
+	server := newOllamaServer(t, ollamaChatStream, &req)
+
+	p := NewOllamaProvider(server.URL, "")
+	s, err := p.CreateStreaming(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "Hi"}},
+		Model:    "llama3.2",
+		Extra:    map[string]any{"keep_alive": "-1s"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer func() { _ = s.Close() }()
+
+	if req["stream"] != true || req["keep_alive"] != "-1s" {
+		t.Errorf("unexpected request %v", req)
+	}
+
+	var deltas []string
+	var finishReason string
+	for {
+		chunk, err := s.Recv()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			t.Fatalf("Recv() error = %v", err)
+		}
+		deltas = append(deltas, chunk.Delta)
+		if chunk.FinishReason != "" {
+			finishReason = chunk.FinishReason
+		}
+	}
+
+	if got := strings.Join(deltas, ""); got != "Hello from Ollama!" || len(deltas) != 4 {
+		t.Errorf("expected 4 chunks forming the reply, got %q", deltas)
+	}
+	if finishReason != "stop" {
+		t.Errorf("expected 'stop', got %s", finishReason)
+	}
+
+	usage := s.(*OllamaStream).Usage()
+	if usage.PromptTokens != 12 || usage.CompletionTokens != 7 || usage.TotalTokens != 19 {
+		t.Errorf("unexpected usage %+v", usage)
+	}
+}
+
+func TestOllamaProvider_CreateStructured(t *testing.T) {
+	var req map[string]any
+	body := `{"model":"llama3.2","message":{"role":"assistant","content":"{\"city\":"},"done":false}
+{"model":"llama3.2","message":{"role":"assistant","content":"\"Paris\"}"},"done":true,"prompt_eval_count":20,"eval_count":6}
+`
+	server := newOllamaServer(t, body, &req)
+
+	p := NewOllamaProvider(server.URL, "llama3.2")
+	resp, err := p.CreateStructured(context.Background(), StructuredRequest{
+		CompletionRequest: CompletionRequest{Messages: []Message{{Role: "user", Content: "Capital of France?"}}},
+		ResponseSchema:    json.RawMessage(`{"type":"object","properties":{"city":{"type":"string"}}}`),
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if format, _ := req["format"].(map[string]any); format["type"] != "object" {
+		t.Errorf("expected schema as format, got %v", req["format"])
+	}
+	if string(resp.Data) != `{"city":"Paris"}` {
+		t.Errorf("unexpected data %s", resp.Data)
+	}
+	if resp.Usage.TotalTokens != 26 {
+		t.Errorf("expected 26 total tokens, got %d", resp.Usage.TotalTokens)
+	}
+}
+
+func TestOllamaProvider_ToolCalls(t *testing.T) {
+	body := `{"model":"llama3.2","message":{"role":"assistant","content":"","tool_calls":[{"function":{"name":"get_weather","arguments":{"city":"Paris"}}},{"function":{"name":"get_weather","arguments":{"city":"Rome"}}}]},"done":true,"done_reason":"stop"}
+`
+	var req map[string]any
+	server := newOllamaServer(t, body, &req)
+
+	p := NewOllamaProvider(server.URL, "llama3.2")
+	resp, err := p.CreateCompletion(context.Background(), CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "Weather in Paris?"}},
+		Tools:    []Tool{{Name: "get_weather", Description: "Get weather", Parameters: json.RawMessage(`{"type":"object"}`)}},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if tools, _ := req["tools"].([]any); len(tools) != 1 {
+		t.Errorf("expected 1 tool in request, got %v", req["tools"])
+	}
+	if len(resp.ToolCalls) != 2 || resp.ToolCalls[0].Function.Name != "get_weather" {
+		t.Fatalf("unexpected tool calls %+v", resp.ToolCalls)
+	}
+	if string(resp.ToolCalls[0].Function.Arguments) != `{"city":"Paris"}` {
+		t.Errorf("unexpected arguments %s", resp.ToolCalls[0].Function.Arguments)
+	}
+	if first, second := resp.ToolCalls[0].ID, resp.ToolCalls[1].ID; first == "" || first == second {
+		t.Errorf("calls to the same tool need distinct IDs, got %q and %q", first, second)
+	}
+}
+
+func TestOllamaProvider_ErrorHandling(t *testing.T) {
+	tests := []struct {
+		name     string
+		status   int
+		body     string
+		wantCode string
+	}{
+		{"model not found", http.StatusNotFound, `{"error":"model 'nope' not found"}`, ErrorCodeModelNotFound},
+		{"server error", http.StatusInternalServerError, `{"error":"out of memory"}`, ErrorCodeServerError},
+		{"error mid-stream", http.StatusOK, `{"message":{"content":"Hi"},"done":false}` + "\n" + `{"error":"model crashed"}` + "\n", ErrorCodeServerError},
+		{"truncated stream", http.StatusOK, `{"message":{"content":"Hi"},"done":false}` + "\n", ErrorCodeServerError},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tt.status)
+				_, _ = io.WriteString(w, tt.body)
+			}))
+			defer server.Close()
+
+			p := NewOllamaProvider(server.URL, "nope")
+			_, err := p.CreateCompletion(context.Background(), CompletionRequest{
+				Messages: []Message{{Role: "user", Content: "Hi"}},
+			})
+
+			var provErr *ProviderError
+			if !errors.As(err, &provErr) {
+				t.Fatalf("expected ProviderError, got %v", err)
+			}
+			if provErr.Code != tt.wantCode {
+				t.Errorf("expected code %s, got %s", tt.wantCode, provErr.Code)
+			}
+		})
+	}
+}
+
+func TestOllamaProvider_Factory(t *testing.T) {
+	p, err := CreateProvider("ollama", map[string]any{
+		"base_url":   "http://localhost:11434",
+		"model":      "llama3.2",
+		"keep_alive": "30m",
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	op, ok := p.(*OllamaProvider)
+	if !ok {
+		t.Fatalf("expected *OllamaProvider, got %T", p)
+	}
+	if op.model != "llama3.2" || op.keepAlive != "30m0s" {
+		t.Errorf("unexpected provider config %+v", op)
+	}
+
+	if _, err := CreateProvider("ollama", map[string]any{"keep_alive": "forever"}); err == nil {
+		t.Error("expected error for invalid keep_alive")
+	}
+}
+
+func TestOllamaProvider_ListModels(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+		_, _ = io.WriteString(w, `{"models":[{"name":"llama3.2:latest","model":"llama3.2:latest","details":{"family":"llama","parameter_size":"3.2B"}}]}`)
+	}))
+	defer server.Close()
+
+	models, err := NewOllamaProvider(server.URL, "").ListModels(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(models) != 1 || models[0].ID != "llama3.2:latest" || models[0].Provider != "ollama" {
+		t.Errorf("unexpected models %+v", models)
+	}
+}
@@ -7,16 +7,12 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"math"
 	"net/http"
 	"os"
 	"time"
 )
 
-const (
-	openaiBaseURL    = "https://api.openai.com/v1"
-	openaiMaxRetries = 3
-)
+const openaiBaseURL = "https://api.openai.com/v1"
 
 func init() {
 	RegisterFactory("openai", func(config map[string]any) (Provider, error) {
@@ -45,31 +41,60 @@ type OpenAIProvider struct {
 	apiKey  string
 	baseURL string
 	client  *http.Client
+	retry   RetryConfig
+
+	// batchPollInterval overrides openaiBatchPollInterval
+	batchPollInterval time.Duration
 }
 
-// NewOpenAIProvider creates a new OpenAI provider
+// NewOpenAIProvider creates a new OpenAI provider. Rate limits and server
+// errors are retried with DefaultRetryConfig; use SetRetryConfig to change
+// or disable this.
 func NewOpenAIProvider(apiKey, baseURL string) *OpenAIProvider {
 	return &OpenAIProvider{
 		apiKey:  apiKey,
 		baseURL: baseURL,
 		client:  &http.Client{Timeout: 120 * time.Second},
+		retry:   DefaultRetryConfig(),
 	}
 }
 
+// SetRetryConfig replaces the built-in retry configuration. Zero fields take
+// their defaults; a MaxAttempts of 1 disables retries. WithRetry returns the
+// provider unwrapped, so this is the only way to tune its retries.
+func (p *OpenAIProvider) SetRetryConfig(cfg RetryConfig) {
+	p.retry = cfg.withDefaults()
+}
+
 // Name returns the provider name
 func (p *OpenAIProvider) Name() string {
 	return "openai"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *OpenAIProvider) retriesTransientErrors() {}
+
 // openaiRequest represents the OpenAI API request format
 type openaiRequest struct {
-	Model          string          `json:"model"`
-	Messages       []openaiMessage `json:"messages"`
-	Temperature    float64         `json:"temperature,omitempty"`
-	MaxTokens      int             `json:"max_tokens,omitempty"`
-	Tools          []openaiTool    `json:"tools,omitempty"`
-	Stream         bool            `json:"stream,omitempty"`
-	ResponseFormat *openaiRespFmt  `json:"response_format,omitempty"`
+	Model          string            `json:"model"`
+	Messages       []openaiMessage   `json:"messages"`
+	Temperature    float64           `json:"temperature,omitempty"`
+	MaxTokens      int               `json:"max_tokens,omitempty"`
+	TopP           float64           `json:"top_p,omitempty"`
+	Stop           []string          `json:"stop,omitempty"`
+	Tools          []openaiTool      `json:"tools,omitempty"`
+	ToolChoice     *openaiToolChoice `json:"tool_choice,omitempty"`
+	Stream         bool              `json:"stream,omitempty"`
+	ResponseFormat *openaiRespFmt    `json:"response_format,omitempty"`
+}
+
+// openaiToolChoice forces a call to the named function
+type openaiToolChoice struct {
+	Type     string `json:"type"`
+	Function struct {
+		Name string `json:"name"`
+	} `json:"function"`
 }
 
 type openaiMessage struct {
@@ -139,13 +164,21 @@ func (p *OpenAIProvider) CreateCompletion(ctx context.Context, req CompletionReq
 	openaiReq := p.buildRequest(req, model, false)
 
 	var resp openaiResponse
-	if err := p.doRequestWithRetry(ctx, "/chat/completions", openaiReq, &resp); err != nil {
+	if err := p.doRequest(ctx, "/chat/completions", openaiReq, &resp); err != nil {
 		return nil, err
 	}
 
 	return p.parseResponse(&resp)
 }
 
+// CreateWithTools creates a chat completion offering tools through the
+// tools/tool_calls fields. Requested calls are returned in ToolCalls with
+// FinishReason "tool_calls".
+func (p *OpenAIProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response
 func (p *OpenAIProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	model := req.Model
@@ -153,10 +186,24 @@ func (p *OpenAIProvider) CreateStructured(ctx context.Context, req StructuredReq
 		model = "gpt-4"
 	}
 
-	openaiReq := p.buildRequest(req.CompletionRequest, model, false)
+	useTool := req.ResponseFormat == ResponseFormatTool && len(req.ResponseSchema) > 0
+	compReq := req.CompletionRequest
+	if useTool {
+		compReq.Tools = append(compReq.Tools, Tool{
+			Name:        StructuredOutputTool,
+			Description: "Use this tool to provide your structured response",
+			Parameters:  req.ResponseSchema,
+		})
+	}
+
+	openaiReq := p.buildRequest(compReq, model, false)
 
 	// Add response format for structured output
-	if len(req.ResponseSchema) > 0 {
+	switch {
+	case useTool:
+		openaiReq.ToolChoice = &openaiToolChoice{Type: "function"}
+		openaiReq.ToolChoice.Function.Name = StructuredOutputTool
+	case len(req.ResponseSchema) > 0 && req.ResponseFormat != ResponseFormatJSONObject:
 		openaiReq.ResponseFormat = &openaiRespFmt{
 			Type: "json_schema",
 			JSONSchema: &openaiJSONSchema{
@@ -165,12 +212,12 @@ func (p *OpenAIProvider) CreateStructured(ctx context.Context, req StructuredReq
 				Schema: req.ResponseSchema,
 			},
 		}
-	} else {
+	default:
 		openaiReq.ResponseFormat = &openaiRespFmt{Type: "json_object"}
 	}
 
 	var resp openaiResponse
-	if err := p.doRequestWithRetry(ctx, "/chat/completions", openaiReq, &resp); err != nil {
+	if err := p.doRequest(ctx, "/chat/completions", openaiReq, &resp); err != nil {
 		return nil, err
 	}
 
@@ -179,8 +226,16 @@ func (p *OpenAIProvider) CreateStructured(ctx context.Context, req StructuredReq
 		return nil, err
 	}
 
+	data := json.RawMessage(compResp.Content)
+	for _, tc := range compResp.ToolCalls {
+		if tc.Function.Name == StructuredOutputTool {
+			data = tc.Function.Arguments
+			break
+		}
+	}
+
 	return &StructuredResponse{
-		Data:               json.RawMessage(compResp.Content),
+		Data:               data,
 		CompletionResponse: *compResp,
 	}, nil
 }
@@ -199,33 +254,42 @@ func (p *OpenAIProvider) CreateStreaming(ctx context.Context, req CompletionRequ
 		return nil, err
 	}
 
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
-	if err != nil {
-		return nil, err
-	}
+	// Only opening the stream is retried
+	return retryCall(ctx, p.retry, func() (Stream, error) {
+		httpReq, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+"/chat/completions", bytes.NewReader(body))
+		if err != nil {
+			return nil, err
+		}
 
-	httpReq.Header.Set("Content-Type", "application/json")
-	httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
+		httpReq.Header.Set("Content-Type", "application/json")
+		httpReq.Header.Set("Authorization", "Bearer "+p.apiKey)
 
-	resp, err := p.client.Do(httpReq)
-	if err != nil {
-		return nil, NewProviderError("openai", ErrorCodeTimeout, err.Error(), err)
-	}
+		resp, err := p.client.Do(httpReq)
+		if err != nil {
+			return nil, NewProviderError("openai", ErrorCodeTimeout, err.Error(), err)
+		}
 
-	if resp.StatusCode != http.StatusOK {
-		defer func() {
-			_ = resp.Body.Close()
-		}()
-		return nil, p.handleErrorResponse(resp)
-	}
+		if resp.StatusCode != http.StatusOK {
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			return nil, p.handleErrorResponse(resp)
+		}
 
-	return &openaiStream{reader: bufio.NewReader(resp.Body), closer: resp.Body}, nil
+		return &openaiStream{reader: bufio.NewReader(resp.Body), closer: resp.Body}, nil
+	})
 }
 
 func (p *OpenAIProvider) buildRequest(req CompletionRequest, model string, stream bool) openaiRequest {
 	messages := make([]openaiMessage, len(req.Messages))
 	for i, m := range req.Messages {
-		messages[i] = openaiMessage{Role: m.Role, Content: m.Content}
+		messages[i] = openaiMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, tc := range m.ToolCalls {
+			call := openaiToolCall{ID: tc.ID, Type: "function"}
+			call.Function.Name = tc.Function.Name
+			call.Function.Arguments = string(tc.Function.Arguments)
+			messages[i].ToolCalls = append(messages[i].ToolCalls, call)
+		}
 	}
 
 	oReq := openaiRequest{
@@ -233,6 +297,8 @@ func (p *OpenAIProvider) buildRequest(req CompletionRequest, model string, strea
 		Messages:    messages,
 		Temperature: req.Temperature,
 		MaxTokens:   req.MaxTokens,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
 		Stream:      stream,
 	}
 
@@ -249,26 +315,18 @@ func (p *OpenAIProvider) buildRequest(req CompletionRequest, model string, strea
 	return oReq
 }
 
-func (p *OpenAIProvider) doRequestWithRetry(ctx context.Context, endpoint string, reqBody any, result any) error {
+// doRequest posts reqBody to endpoint and decodes the response into result,
+// retrying rate limits and server errors as configured
+func (p *OpenAIProvider) doRequest(ctx context.Context, endpoint string, reqBody any, result any) error {
 	body, err := json.Marshal(reqBody)
 	if err != nil {
 		return err
 	}
 
-	var lastErr error
-	for attempt := 0; attempt < openaiMaxRetries; attempt++ {
-		if attempt > 0 {
-			delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
-			select {
-			case <-ctx.Done():
-				return ctx.Err()
-			case <-time.After(delay):
-			}
-		}
-
+	_, err = retryCall(ctx, p.retry, func() (struct{}, error) {
 		req, err := http.NewRequestWithContext(ctx, "POST", p.baseURL+endpoint, bytes.NewReader(body))
 		if err != nil {
-			return err
+			return struct{}{}, err
 		}
 
 		req.Header.Set("Content-Type", "application/json")
@@ -276,26 +334,19 @@ func (p *OpenAIProvider) doRequestWithRetry(ctx context.Context, endpoint string
 
 		resp, err := p.client.Do(req)
 		if err != nil {
-			lastErr = NewProviderError("openai", ErrorCodeTimeout, err.Error(), err)
-			continue
+			return struct{}{}, NewProviderError("openai", ErrorCodeTimeout, err.Error(), err)
 		}
 		defer func() {
 			_ = resp.Body.Close()
 		}()
 
-		if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500 {
-			lastErr = p.handleErrorResponse(resp)
-			continue
-		}
-
 		if resp.StatusCode != http.StatusOK {
-			return p.handleErrorResponse(resp)
+			return struct{}{}, p.handleErrorResponse(resp)
 		}
 
-		return json.NewDecoder(resp.Body).Decode(result)
-	}
-
-	return lastErr
+		return struct{}{}, json.NewDecoder(resp.Body).Decode(result)
+	})
+	return err
 }
 
 func (p *OpenAIProvider) handleErrorResponse(resp *http.Response) error {
@@ -325,6 +376,7 @@ func (p *OpenAIProvider) handleErrorResponse(resp *http.Response) error {
 			Type:        errResp.Error.Type,
 			StatusCode:  resp.StatusCode,
 			IsRetryable: code == ErrorCodeRateLimit || code == ErrorCodeServerError,
+			RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
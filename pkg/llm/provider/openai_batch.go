@@ -0,0 +1,278 @@
+package provider
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// openaiBatchPollInterval is how often a submitted batch's status is checked
+const openaiBatchPollInterval = 30 * time.Second
+
+// openaiBatchLine is one request in a Batch API input file
+type openaiBatchLine struct {
+	CustomID string        `json:"custom_id"`
+	Method   string        `json:"method"`
+	URL      string        `json:"url"`
+	Body     openaiRequest `json:"body"`
+}
+
+// openaiBatchResult is one line of a Batch API output or error file
+type openaiBatchResult struct {
+	CustomID string `json:"custom_id"`
+	Response *struct {
+		StatusCode int            `json:"status_code"`
+		Body       openaiResponse `json:"body"`
+	} `json:"response"`
+	Error *struct {
+		Code    string `json:"code"`
+		Message string `json:"message"`
+	} `json:"error"`
+}
+
+// openaiBatch is the Batch API's batch object
+type openaiBatch struct {
+	ID           string `json:"id"`
+	Status       string `json:"status"`
+	OutputFileID string `json:"output_file_id"`
+	ErrorFileID  string `json:"error_file_id"`
+	Errors       *struct {
+		Data []struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		} `json:"data"`
+	} `json:"errors"`
+}
+
+// CreateCompletionBatch completes requests through the OpenAI Batch API:
+// the requests are uploaded as one JSONL file, submitted as a batch, and
+// polled until the batch ends. Batches trade latency (up to the 24h
+// completion window) for the Batch API's discount, so they suit
+// NewBatchingProvider's bulk, latency-tolerant workloads. If any request in
+// the batch fails, the whole call fails.
+func (p *OpenAIProvider) CreateCompletionBatch(ctx context.Context, requests []CompletionRequest) ([]*CompletionResponse, error) {
+	var input bytes.Buffer
+	enc := json.NewEncoder(&input)
+	for i, req := range requests {
+		model := req.Model
+		if model == "" {
+			model = "gpt-4"
+		}
+		line := openaiBatchLine{
+			CustomID: strconv.Itoa(i),
+			Method:   "POST",
+			URL:      "/v1/chat/completions",
+			Body:     p.buildRequest(req, model, false),
+		}
+		if err := enc.Encode(line); err != nil {
+			return nil, err
+		}
+	}
+
+	fileID, err := p.uploadBatchFile(ctx, input.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	var batch openaiBatch
+	if err := p.doRequest(ctx, "/batches", map[string]string{
+		"input_file_id":     fileID,
+		"endpoint":          "/v1/chat/completions",
+		"completion_window": "24h",
+	}, &batch); err != nil {
+		return nil, err
+	}
+
+	if err := p.waitForBatch(ctx, &batch); err != nil {
+		return nil, err
+	}
+
+	results, err := p.readBatchResults(ctx, batch.OutputFileID)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ErrorFileID != "" {
+		failed, err := p.readBatchResults(ctx, batch.ErrorFileID)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, failed...)
+	}
+
+	responses := make([]*CompletionResponse, len(requests))
+	for _, result := range results {
+		i, err := strconv.Atoi(result.CustomID)
+		if err != nil || i < 0 || i >= len(requests) {
+			return nil, NewProviderError("openai", ErrorCodeUnknown, "unexpected custom_id in batch output: "+result.CustomID, nil)
+		}
+		switch {
+		case result.Error != nil:
+			return nil, NewProviderError("openai", ErrorCodeUnknown, fmt.Sprintf("batch request %d failed: %s", i, result.Error.Message), nil)
+		case result.Response == nil || result.Response.StatusCode != http.StatusOK:
+			msg := fmt.Sprintf("batch request %d failed", i)
+			if result.Response != nil && result.Response.Body.Error != nil {
+				msg += ": " + result.Response.Body.Error.Message
+			}
+			return nil, NewProviderError("openai", ErrorCodeUnknown, msg, nil)
+		}
+		if responses[i], err = p.parseResponse(&result.Response.Body); err != nil {
+			return nil, err
+		}
+	}
+	for i, resp := range responses {
+		if resp == nil {
+			return nil, NewProviderError("openai", ErrorCodeUnknown, fmt.Sprintf("batch output is missing request %d", i), nil)
+		}
+	}
+
+	return responses, nil
+}
+
+// uploadBatchFile uploads a Batch API input file and returns its ID
+func (p *OpenAIProvider) uploadBatchFile(ctx context.Context, data []byte) (string, error) {
+	var body bytes.Buffer
+	form := multipart.NewWriter(&body)
+	if err := form.WriteField("purpose", "batch"); err != nil {
+		return "", err
+	}
+	part, err := form.CreateFormFile("file", "batch.jsonl")
+	if err != nil {
+		return "", err
+	}
+	if _, err := part.Write(data); err != nil {
+		return "", err
+	}
+	if err := form.Close(); err != nil {
+		return "", err
+	}
+
+	resp, err := p.sendBatchRequest(ctx, "POST", "/files", form.FormDataContentType(), body.Bytes())
+	if err != nil {
+		return "", err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var file struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&file); err != nil {
+		return "", NewProviderError("openai", ErrorCodeUnknown, "failed to decode file upload response", err)
+	}
+	return file.ID, nil
+}
+
+// waitForBatch polls batch until it completes, fails, expires, or is
+// cancelled, or until ctx ends
+func (p *OpenAIProvider) waitForBatch(ctx context.Context, batch *openaiBatch) error {
+	interval := p.batchPollInterval
+	if interval <= 0 {
+		interval = openaiBatchPollInterval
+	}
+
+	for {
+		switch batch.Status {
+		case "completed":
+			return nil
+		case "failed", "expired", "cancelled":
+			msg := "batch " + batch.ID + " " + batch.Status
+			if batch.Errors != nil && len(batch.Errors.Data) > 0 {
+				msg += ": " + batch.Errors.Data[0].Message
+			}
+			return NewProviderError("openai", ErrorCodeUnknown, msg, nil)
+		}
+
+		timer := time.NewTimer(interval)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return ctx.Err()
+		case <-timer.C:
+		}
+
+		resp, err := p.sendBatchRequest(ctx, "GET", "/batches/"+batch.ID, "", nil)
+		if err != nil {
+			return err
+		}
+		err = json.NewDecoder(resp.Body).Decode(batch)
+		_ = resp.Body.Close()
+		if err != nil {
+			return NewProviderError("openai", ErrorCodeUnknown, "failed to decode batch status", err)
+		}
+	}
+}
+
+// readBatchResults downloads and parses a Batch API output or error file
+func (p *OpenAIProvider) readBatchResults(ctx context.Context, fileID string) ([]openaiBatchResult, error) {
+	if fileID == "" {
+		return nil, nil
+	}
+
+	resp, err := p.sendBatchRequest(ctx, "GET", "/files/"+fileID+"/content", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		_ = resp.Body.Close()
+	}()
+
+	var results []openaiBatchResult
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var result openaiBatchResult
+		if err := json.Unmarshal(line, &result); err != nil {
+			return nil, NewProviderError("openai", ErrorCodeUnknown, "failed to decode batch output", err)
+		}
+		results = append(results, result)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, NewProviderError("openai", ErrorCodeUnknown, "failed to read batch output", err)
+	}
+	return results, nil
+}
+
+// sendBatchRequest makes a Batch API call, retrying as configured, and
+// returns the response once it succeeds. The caller closes the body.
+func (p *OpenAIProvider) sendBatchRequest(ctx context.Context, method, endpoint, contentType string, body []byte) (*http.Response, error) {
+	return retryCall(ctx, p.retry, func() (*http.Response, error) {
+		var reader io.Reader
+		if body != nil {
+			reader = bytes.NewReader(body)
+		}
+		req, err := http.NewRequestWithContext(ctx, method, p.baseURL+endpoint, reader)
+		if err != nil {
+			return nil, err
+		}
+
+		if contentType != "" {
+			req.Header.Set("Content-Type", contentType)
+		}
+		req.Header.Set("Authorization", "Bearer "+p.apiKey)
+
+		resp, err := p.client.Do(req)
+		if err != nil {
+			return nil, NewProviderError("openai", ErrorCodeTimeout, err.Error(), err)
+		}
+
+		if resp.StatusCode != http.StatusOK {
+			defer func() {
+				_ = resp.Body.Close()
+			}()
+			return nil, p.handleErrorResponse(resp)
+		}
+		return resp, nil
+	})
+}
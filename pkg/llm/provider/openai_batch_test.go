@@ -0,0 +1,161 @@
+package provider
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// batchAPIServer fakes the OpenAI Files and Batches endpoints, answering
+// each batched request with its last message
+type batchAPIServer struct {
+	t      *testing.T
+	mu     sync.Mutex
+	inputs [][]openaiBatchLine
+	polls  int
+	chats  int
+	output []byte
+}
+
+func (s *batchAPIServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	w.Header().Set("Content-Type", "application/json")
+
+	switch {
+	case r.Method == "POST" && r.URL.Path == "/files":
+		if r.FormValue("purpose") != "batch" {
+			s.t.Errorf("purpose = %q, want batch", r.FormValue("purpose"))
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			s.t.Errorf("upload has no file: %v", err)
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		var lines []openaiBatchLine
+		scanner := bufio.NewScanner(file)
+		for scanner.Scan() {
+			var line openaiBatchLine
+			if err := json.Unmarshal(scanner.Bytes(), &line); err != nil {
+				s.t.Errorf("bad input line %q: %v", scanner.Text(), err)
+			}
+			lines = append(lines, line)
+		}
+		s.inputs = append(s.inputs, lines)
+
+		// Answer out of order so responses must be matched by custom_id
+		var out []byte
+		for _, line := range slices.Backward(lines) {
+			content := line.Body.Messages[len(line.Body.Messages)-1].Content
+			out = fmt.Appendf(out, `{"custom_id":%q,"response":{"status_code":200,"body":{"choices":[{"message":{"role":"assistant","content":%q},"finish_reason":"stop"}]}}}`+"\n", line.CustomID, content)
+		}
+		s.output = out
+		_, _ = fmt.Fprintf(w, `{"id":"file-in-%d"}`, len(s.inputs))
+	case r.Method == "POST" && r.URL.Path == "/batches":
+		_, _ = fmt.Fprint(w, `{"id":"batch-1","status":"validating"}`)
+	case r.Method == "GET" && r.URL.Path == "/batches/batch-1":
+		s.polls++
+		status := "in_progress"
+		if s.polls > 1 {
+			status = "completed"
+		}
+		_, _ = fmt.Fprintf(w, `{"id":"batch-1","status":%q,"output_file_id":"file-out"}`, status)
+	case r.Method == "GET" && r.URL.Path == "/files/file-out/content":
+		_, _ = w.Write(s.output)
+	case r.URL.Path == "/chat/completions":
+		s.chats++
+		_, _ = fmt.Fprint(w, `{"choices":[{"message":{"role":"assistant","content":"direct"}}]}`)
+	default:
+		http.NotFound(w, r)
+	}
+}
+
+func TestOpenAIProvider_BatchesConcurrentCompletions(t *testing.T) {
+	api := &batchAPIServer{t: t}
+	server := httptest.NewServer(api)
+	defer server.Close()
+
+	openai := NewOpenAIProvider("test-key", server.URL)
+	openai.batchPollInterval = time.Millisecond
+	p := NewBatchingProvider(openai, 50*time.Millisecond, 0)
+
+	contents := completeConcurrently(t, p, 5)
+	for i, content := range contents {
+		if want := fmt.Sprintf("ticket %d", i); content != want {
+			t.Errorf("call %d got %q, want %q", i, content, want)
+		}
+	}
+
+	api.mu.Lock()
+	defer api.mu.Unlock()
+	if len(api.inputs) != 1 || len(api.inputs[0]) != 5 {
+		t.Fatalf("uploaded batches = %v, want one batch of 5 requests", api.inputs)
+	}
+	if api.chats != 0 {
+		t.Errorf("chat completions called %d times, want every call batched", api.chats)
+	}
+	line := api.inputs[0][0]
+	if line.Method != "POST" || line.URL != "/v1/chat/completions" || line.Body.Model != "gpt-4" {
+		t.Errorf("batch line = %+v, want a chat completion request", line)
+	}
+}
+
+func TestOpenAIProvider_CreateCompletionBatchFailures(t *testing.T) {
+	tests := []struct {
+		name    string
+		status  string
+		output  string
+		wantErr string
+	}{
+		{
+			name:    "batch failed",
+			status:  `"failed","errors":{"data":[{"message":"invalid input"}]}`,
+			wantErr: "batch batch-1 failed: invalid input",
+		},
+		{
+			name:    "request failed",
+			status:  `"completed","output_file_id":"file-out"`,
+			output:  `{"custom_id":"0","response":{"status_code":400,"body":{"error":{"message":"bad model"}}}}`,
+			wantErr: "batch request 0 failed: bad model",
+		},
+		{
+			name:    "request missing",
+			status:  `"completed","output_file_id":"file-out"`,
+			wantErr: "batch output is missing request 0",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				switch r.URL.Path {
+				case "/files":
+					_, _ = fmt.Fprint(w, `{"id":"file-in"}`)
+				case "/batches":
+					_, _ = fmt.Fprintf(w, `{"id":"batch-1","status":%s}`, tt.status)
+				case "/files/file-out/content":
+					_, _ = fmt.Fprint(w, tt.output)
+				default:
+					http.NotFound(w, r)
+				}
+			}))
+			defer server.Close()
+
+			p := NewOpenAIProvider("test-key", server.URL)
+			_, err := p.CreateCompletionBatch(t.Context(), []CompletionRequest{
+				{Messages: []Message{{Role: "user", Content: "Hi"}}},
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("CreateCompletionBatch() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
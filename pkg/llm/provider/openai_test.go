@@ -3,10 +3,15 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 )
 
 func TestOpenAIProvider_Name(t *testing.T) {
@@ -210,3 +215,211 @@ func TestOpenAIProvider_Factory(t *testing.T) {
 		t.Errorf("expected 'openai', got %s", p.Name())
 	}
 }
+
+// fixtureServer replies with a recorded OpenAI response and captures the
+// request body
+func fixtureServer(t *testing.T, fixture string, captured *map[string]any) *httptest.Server {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("testdata", fixture))
+	if err != nil {
+		t.Fatal(err)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		_ = json.Unmarshal(body, captured)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAIProvider_CreateWithTools(t *testing.T) {
+	tools := []ToolSpec{{
+		Name:        "get_weather",
+		Description: "Get the current weather for a location",
+		Parameters:  json.RawMessage(`{"type":"object","properties":{"location":{"type":"string"}}}`),
+	}}
+
+	t.Run("tool call", func(t *testing.T) {
+		var req map[string]any
+		p := NewOpenAIProvider("test-key", fixtureServer(t, "openai_tool_call.json", &req).URL)
+
+		resp, err := CreateWithTools(context.Background(), p, CompletionRequest{
+			Messages: []Message{{Role: "user", Content: "What's the weather in Paris?"}},
+		}, tools)
+		if err != nil {
+			t.Fatalf("CreateWithTools() error = %v", err)
+		}
+
+		sent, _ := req["tools"].([]any)
+		if len(sent) != 1 || sent[0].(map[string]any)["type"] != "function" {
+			t.Errorf("request tools = %v, want one function tool", req["tools"])
+		}
+		if resp.FinishReason != "tool_calls" || len(resp.ToolCalls) != 1 {
+			t.Fatalf("response = %+v, want one tool call", resp)
+		}
+		call := resp.ToolCalls[0]
+		if call.ID != "call_Wq8dK3" || call.Function.Name != "get_weather" ||
+			string(call.Function.Arguments) != `{"location":"Paris","unit":"celsius"}` {
+			t.Errorf("tool call = %+v", call)
+		}
+	})
+
+	t.Run("answer after tool result", func(t *testing.T) {
+		var req map[string]any
+		p := NewOpenAIProvider("test-key", fixtureServer(t, "openai_no_tool.json", &req).URL)
+
+		call := ToolCall{ID: "call_Wq8dK3", Type: "function", Function: FunctionCall{
+			Name: "get_weather", Arguments: json.RawMessage(`{"location":"Paris"}`),
+		}}
+		resp, err := p.CreateWithTools(context.Background(), CompletionRequest{
+			Messages: []Message{
+				{Role: "user", Content: "What's the weather in Paris?"},
+				{Role: "assistant", ToolCalls: []ToolCall{call}},
+				{Role: "tool", Content: `{"temp_c":18,"sky":"sunny"}`, ToolCallID: "call_Wq8dK3"},
+			},
+		}, tools)
+		if err != nil {
+			t.Fatalf("CreateWithTools() error = %v", err)
+		}
+		if resp.Content != "It is 18°C and sunny in Paris." || len(resp.ToolCalls) != 0 {
+			t.Errorf("response = %+v, want a plain answer", resp)
+		}
+
+		messages, _ := req["messages"].([]any)
+		if len(messages) != 3 {
+			t.Fatalf("request messages = %v, want 3", req["messages"])
+		}
+		assistant := messages[1].(map[string]any)
+		calls, _ := assistant["tool_calls"].([]any)
+		if len(calls) != 1 || calls[0].(map[string]any)["function"].(map[string]any)["arguments"] != `{"location":"Paris"}` {
+			t.Errorf("assistant message = %v, want the tool call with string arguments", assistant)
+		}
+		if tool := messages[2].(map[string]any); tool["role"] != "tool" || tool["tool_call_id"] != "call_Wq8dK3" {
+			t.Errorf("tool message = %v, want the result linked to its call", tool)
+		}
+	})
+}
+
+func TestCreateWithTools_NotSupported(t *testing.T) {
+	_, err := CreateWithTools(context.Background(), NewMockProvider("mock"), CompletionRequest{}, nil)
+	if !errors.Is(err, ErrToolsNotSupported) {
+		t.Errorf("CreateWithTools() error = %v, want ErrToolsNotSupported", err)
+	}
+}
+
+func TestOpenAIProvider_SamplingOptions(t *testing.T) {
+	p := &OpenAIProvider{}
+	req := p.buildRequest(CompletionRequest{
+		Messages: []Message{{Role: "user", Content: "hi"}},
+		TopP:     0.8,
+		Stop:     []string{"END"},
+	}, "gpt-4o", false)
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		t.Fatalf("Marshal() error = %v", err)
+	}
+	if !strings.Contains(string(data), `"top_p":0.8`) || !strings.Contains(string(data), `"stop":["END"]`) {
+		t.Errorf("request = %s, want top_p and stop", data)
+	}
+
+	// Unset options are omitted so provider defaults apply
+	data, _ = json.Marshal(p.buildRequest(CompletionRequest{Messages: []Message{{Role: "user", Content: "hi"}}}, "gpt-4o", false))
+	if strings.Contains(string(data), "top_p") || strings.Contains(string(data), `"stop"`) {
+		t.Errorf("request = %s, want no sampling options", data)
+	}
+}
+
+func TestOpenAIProvider_CreateStructured_ResponseFormats(t *testing.T) {
+	schema := json.RawMessage(`{"type":"object","properties":{"name":{"type":"string"}}}`)
+	toolCallResponse := `{"choices":[{"message":{"role":"assistant","tool_calls":[{"id":"call_1","type":"function",` +
+		`"function":{"name":"structured_output","arguments":"{\"name\":\"Ada\"}"}}]},"finish_reason":"tool_calls"}]}`
+	contentResponse := `{"choices":[{"message":{"role":"assistant","content":"{\"name\":\"Ada\"}"},"finish_reason":"stop"}]}`
+
+	tests := []struct {
+		name       string
+		format     string
+		response   string
+		wantFormat string // response_format.type, "" when omitted
+		wantTool   bool
+	}{
+		{name: "native", response: contentResponse, wantFormat: "json_schema"},
+		{name: "json schema", format: ResponseFormatJSONSchema, response: contentResponse, wantFormat: "json_schema"},
+		{name: "json object", format: ResponseFormatJSONObject, response: contentResponse, wantFormat: "json_object"},
+		{name: "tool", format: ResponseFormatTool, response: toolCallResponse, wantTool: true},
+		{name: "unsupported grammar", format: ResponseFormatGrammar, response: contentResponse, wantFormat: "json_schema"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var req map[string]any
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				body, _ := io.ReadAll(r.Body)
+				_ = json.Unmarshal(body, &req)
+				w.Header().Set("Content-Type", "application/json")
+				_, _ = io.WriteString(w, tt.response)
+			}))
+			defer server.Close()
+
+			p := NewOpenAIProvider("test-key", server.URL)
+			resp, err := p.CreateStructured(context.Background(), StructuredRequest{
+				CompletionRequest: CompletionRequest{Messages: []Message{{Role: "user", Content: "Name a scientist"}}},
+				ResponseSchema:    schema,
+				ResponseFormat:    tt.format,
+			})
+			if err != nil {
+				t.Fatalf("CreateStructured() error = %v", err)
+			}
+
+			gotFormat := ""
+			if rf, ok := req["response_format"].(map[string]any); ok {
+				gotFormat, _ = rf["type"].(string)
+			}
+			if gotFormat != tt.wantFormat {
+				t.Errorf("response_format.type = %q, want %q", gotFormat, tt.wantFormat)
+			}
+			_, hasChoice := req["tool_choice"]
+			if hasChoice != tt.wantTool {
+				t.Errorf("tool_choice present = %v, want %v", hasChoice, tt.wantTool)
+			}
+			if string(resp.Data) != `{"name":"Ada"}` {
+				t.Errorf("Data = %s, want the structured object", resp.Data)
+			}
+		})
+	}
+}
+
+func TestCreateWithTools_Wrappers(t *testing.T) {
+	tools := []ToolSpec{{Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)}}
+	wrappers := map[string]func(Provider) Provider{
+		"instrumented": func(p Provider) Provider { return NewInstrumentedProvider(p, nil) },
+		"retry":        func(p Provider) Provider { return WithRetry(p, RetryConfig{}) },
+		"batching":     func(p Provider) Provider { return NewBatchingProvider(p, time.Millisecond, 0) },
+	}
+
+	for name, wrap := range wrappers {
+		t.Run(name, func(t *testing.T) {
+			var req map[string]any
+			p := wrap(NewOpenAIProvider("test-key", fixtureServer(t, "openai_tool_call.json", &req).URL))
+			if _, ok := p.(ToolProvider); !ok {
+				t.Fatal("wrapper hides ToolProvider")
+			}
+			resp, err := CreateWithTools(context.Background(), p, CompletionRequest{
+				Messages: []Message{{Role: "user", Content: "What's the weather in Paris?"}},
+			}, tools)
+			if err != nil {
+				t.Fatalf("CreateWithTools() error = %v", err)
+			}
+			if len(resp.ToolCalls) != 1 {
+				t.Errorf("ToolCalls = %+v, want the wrapped provider's call", resp.ToolCalls)
+			}
+
+			_, err = CreateWithTools(context.Background(), wrap(NewMockProvider("mock")), CompletionRequest{}, tools)
+			if !errors.Is(err, ErrToolsNotSupported) {
+				t.Errorf("CreateWithTools() over a provider without tools error = %v, want ErrToolsNotSupported", err)
+			}
+		})
+	}
+}
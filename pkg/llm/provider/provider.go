@@ -3,6 +3,7 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"time"
 )
 
 // Provider defines the interface for LLM providers
@@ -55,8 +56,15 @@ type ModelInfo struct {
 
 // Message represents a chat message
 type Message struct {
-	Role    string `json:"role"`    // "system", "user", "assistant"
+	Role    string `json:"role"`    // "system", "user", "assistant", "tool"
 	Content string `json:"content"` // The message content
+
+	// ToolCalls carries the calls of an assistant turn that requested tools,
+	// so the conversation can be continued with their results
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+
+	// ToolCallID links a "tool" message holding a result to its call
+	ToolCallID string `json:"tool_call_id,omitempty"`
 }
 
 // Tool represents a function/tool that can be called by the LLM
@@ -80,6 +88,12 @@ type CompletionRequest struct {
 	// MaxTokens is the maximum number of tokens to generate
 	MaxTokens int `json:"max_tokens,omitempty"`
 
+	// TopP enables nucleus sampling (0.0-1.0); providers without it ignore it
+	TopP float64 `json:"top_p,omitempty"`
+
+	// Stop lists sequences that end generation when produced
+	Stop []string `json:"stop,omitempty"`
+
 	// Tools available for the model to call
 	Tools []Tool `json:"tools,omitempty"`
 
@@ -118,13 +132,33 @@ type StructuredRequest struct {
 	// ResponseSchema is the JSON Schema for the expected response
 	ResponseSchema json.RawMessage `json:"response_schema"`
 
-	// ResponseFormat specifies the format (e.g., "json_object", "json_schema")
+	// ResponseFormat selects the structured output mechanism (one of the
+	// ResponseFormat* constants). Empty uses the provider's native one.
 	ResponseFormat string `json:"response_format,omitempty"`
 
 	// StrictSchema enables strict schema adherence (provider-dependent)
 	StrictSchema bool `json:"strict_schema,omitempty"`
 }
 
+// StructuredRequest.ResponseFormat values. Only OpenAI reads the field; the
+// other built-in providers always use their native mechanism, and
+// internal/llm rejects modes they cannot honour before calling them.
+const (
+	// ResponseFormatJSONSchema constrains the output with the response schema
+	ResponseFormatJSONSchema = "json_schema"
+	// ResponseFormatJSONObject requests a JSON object without a schema
+	ResponseFormatJSONObject = "json_object"
+	// ResponseFormatTool forces a call to the StructuredOutputTool whose
+	// parameters are the response schema
+	ResponseFormatTool = "tool"
+	// ResponseFormatGrammar constrains decoding with a grammar built from
+	// the response schema
+	ResponseFormatGrammar = "grammar"
+)
+
+// StructuredOutputTool is the tool name used for tool-based structured output
+const StructuredOutputTool = "structured_output"
+
 // StructuredResponse represents a structured response
 type StructuredResponse struct {
 	// Data is the parsed structured data
@@ -194,6 +228,10 @@ type ProviderError struct {
 	StatusCode    int    `json:"status_code,omitempty"`
 	IsRetryable   bool   `json:"is_retryable"`
 	OriginalError error  `json:"-"`
+
+	// RetryAfter is the delay the provider asked for before the next
+	// attempt, parsed from a Retry-After header. Zero if not advertised.
+	RetryAfter time.Duration `json:"retry_after,omitempty"`
 }
 
 // Error implements the error interface
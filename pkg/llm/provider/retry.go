@@ -0,0 +1,207 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// RetryConfig configures the retry layer added by WithRetry
+type RetryConfig struct {
+	// MaxAttempts is the total number of attempts, including the first (default 3)
+	MaxAttempts int
+
+	// BaseDelay is the backoff before the second attempt; it doubles on
+	// each further attempt (default 1s)
+	BaseDelay time.Duration
+
+	// MaxDelay caps both the backoff and any advertised Retry-After (default 30s)
+	MaxDelay time.Duration
+}
+
+// maxRetryAfter caps the Retry-After honoured by default and by the
+// providers' built-in retry loops
+const maxRetryAfter = 30 * time.Second
+
+// DefaultRetryConfig returns the default retry configuration
+func DefaultRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseDelay:   time.Second,
+		MaxDelay:    maxRetryAfter,
+	}
+}
+
+// selfRetrying is implemented by providers that already retry transient
+// failures internally
+type selfRetrying interface {
+	retriesTransientErrors()
+}
+
+// retryProvider retries rate-limited and server-side failures of the
+// wrapped provider
+type retryProvider struct {
+	provider Provider
+	config   RetryConfig
+}
+
+// WithRetry wraps p so that calls failing with a rate limit (429) or
+// server error (5xx) are retried with capped exponential backoff. A
+// Retry-After delay advertised by the provider takes precedence over the
+// backoff. Waiting stops as soon as the context is cancelled.
+//
+// Providers with their own retry loop (Anthropic, Bedrock, Gemini, OpenAI,
+// Vertex AI, and xAI) are returned unwrapped so attempts don't multiply.
+//
+// Wrapping with NewInstrumentedProvider outside WithRetry records one span
+// per call; inside, one span per attempt.
+func WithRetry(p Provider, cfg RetryConfig) Provider {
+	if _, ok := p.(selfRetrying); ok {
+		return p
+	}
+	return &retryProvider{provider: p, config: cfg.withDefaults()}
+}
+
+// withDefaults fills unset fields from DefaultRetryConfig
+func (cfg RetryConfig) withDefaults() RetryConfig {
+	defaults := DefaultRetryConfig()
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = defaults.MaxAttempts
+	}
+	if cfg.BaseDelay <= 0 {
+		cfg.BaseDelay = defaults.BaseDelay
+	}
+	if cfg.MaxDelay <= 0 {
+		cfg.MaxDelay = defaults.MaxDelay
+	}
+	return cfg
+}
+
+// Name returns the wrapped provider's name
+func (p *retryProvider) Name() string {
+	return p.provider.Name()
+}
+
+// CreateCompletion creates a completion, retrying transient failures
+func (p *retryProvider) CreateCompletion(ctx context.Context, request CompletionRequest) (*CompletionResponse, error) {
+	return retryCall(ctx, p.config, func() (*CompletionResponse, error) {
+		return p.provider.CreateCompletion(ctx, request)
+	})
+}
+
+// CreateWithTools calls the wrapped provider's native tool calling,
+// retrying transient failures
+func (p *retryProvider) CreateWithTools(ctx context.Context, request CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	return retryCall(ctx, p.config, func() (*CompletionResponse, error) {
+		return CreateWithTools(ctx, p.provider, request, tools)
+	})
+}
+
+// CreateStructured creates a structured response, retrying transient failures
+func (p *retryProvider) CreateStructured(ctx context.Context, request StructuredRequest) (*StructuredResponse, error) {
+	return retryCall(ctx, p.config, func() (*StructuredResponse, error) {
+		return p.provider.CreateStructured(ctx, request)
+	})
+}
+
+// CreateStreaming opens a stream, retrying transient failures. Errors
+// after the stream is open are not retried.
+func (p *retryProvider) CreateStreaming(ctx context.Context, request CompletionRequest) (Stream, error) {
+	return retryCall(ctx, p.config, func() (Stream, error) {
+		return p.provider.CreateStreaming(ctx, request)
+	})
+}
+
+// ListModels lists models, retrying transient failures
+func (p *retryProvider) ListModels(ctx context.Context) ([]ModelInfo, error) {
+	return retryCall(ctx, p.config, func() ([]ModelInfo, error) {
+		return p.provider.ListModels(ctx)
+	})
+}
+
+// retryCall runs call until it succeeds, fails permanently, or runs out
+// of attempts
+func retryCall[T any](ctx context.Context, cfg RetryConfig, call func() (T, error)) (T, error) {
+	var (
+		result T
+		err    error
+	)
+	for attempt := range cfg.MaxAttempts {
+		if attempt > 0 {
+			timer := time.NewTimer(retryDelay(cfg, attempt, err))
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				var zero T
+				return zero, ctx.Err()
+			case <-timer.C:
+			}
+		}
+
+		result, err = call()
+		if err == nil || !shouldRetry(err) || ctx.Err() != nil {
+			return result, err
+		}
+	}
+	return result, err
+}
+
+// retryDelay returns the wait before the given attempt, preferring the
+// Retry-After advertised by the last error
+func retryDelay(cfg RetryConfig, attempt int, lastErr error) time.Duration {
+	if after := retryAfterOf(lastErr); after > 0 {
+		return min(after, cfg.MaxDelay)
+	}
+	delay := cfg.BaseDelay
+	for range attempt - 1 {
+		if delay >= cfg.MaxDelay {
+			break
+		}
+		delay *= 2
+	}
+	return min(delay, cfg.MaxDelay)
+}
+
+// shouldRetry reports whether err is a rate limit or server error
+func shouldRetry(err error) bool {
+	var provErr *ProviderError
+	if !errors.As(err, &provErr) {
+		return false
+	}
+	if provErr.StatusCode == http.StatusTooManyRequests || provErr.StatusCode >= 500 {
+		return true
+	}
+	return provErr.Code == ErrorCodeRateLimit || provErr.Code == ErrorCodeServerError
+}
+
+// retryAfterOf returns the Retry-After carried by a ProviderError, if any
+func retryAfterOf(err error) time.Duration {
+	var provErr *ProviderError
+	if errors.As(err, &provErr) {
+		return provErr.RetryAfter
+	}
+	return 0
+}
+
+// parseRetryAfter parses a Retry-After header given either as a number of
+// seconds or as an HTTP date. It returns zero if the value is missing or
+// invalid.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		if secs < 0 {
+			return 0
+		}
+		return time.Duration(secs) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+	return 0
+}
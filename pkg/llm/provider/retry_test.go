@@ -0,0 +1,196 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// newRateLimitedServer answers the first request with a 429 advertising
+// retryAfter, and every later request with a completion
+func newRateLimitedServer(t *testing.T, retryAfter string, calls *atomic.Int32, times *[]time.Time) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*times = append(*times, time.Now())
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", retryAfter)
+			w.WriteHeader(http.StatusTooManyRequests)
+			_ = json.NewEncoder(w).Encode(map[string]any{
+				"error": map[string]string{"message": "Rate limit reached", "type": "requests"},
+			})
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]any{
+			"id":      "chatcmpl-1",
+			"model":   "gpt-4o",
+			"choices": []map[string]any{{"message": map[string]any{"role": "assistant", "content": "Hello!"}, "finish_reason": "stop"}},
+			"usage":   map[string]int{"prompt_tokens": 5, "completion_tokens": 2, "total_tokens": 7},
+		})
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestOpenAIProvider_HonorsRetryAfter(t *testing.T) {
+	var calls atomic.Int32
+	var times []time.Time
+	server := newRateLimitedServer(t, "1", &calls, &times)
+	req := CompletionRequest{Messages: []Message{{Role: "user", Content: "Hi"}}}
+
+	// With retries disabled, the provider makes a single attempt
+	single := NewOpenAIProvider("test-key", server.URL)
+	single.SetRetryConfig(RetryConfig{MaxAttempts: 1})
+	_, err := single.CreateCompletion(context.Background(), req)
+	var provErr *ProviderError
+	if !errors.As(err, &provErr) || provErr.RetryAfter != time.Second {
+		t.Fatalf("single-attempt error = %v, want the 429 with its Retry-After", err)
+	}
+	calls.Store(0)
+	times = nil
+
+	openai := NewOpenAIProvider("test-key", server.URL)
+	openai.SetRetryConfig(RetryConfig{BaseDelay: 10 * time.Millisecond})
+	p := NewInstrumentedProvider(openai, nil)
+	resp, err := p.CreateCompletion(context.Background(), req)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Content != "Hello!" {
+		t.Errorf("expected 'Hello!', got %q", resp.Content)
+	}
+	if calls.Load() != 2 {
+		t.Fatalf("expected 2 attempts, got %d", calls.Load())
+	}
+
+	// The advertised 1s replaces the 10ms backoff
+	gap := times[1].Sub(times[0])
+	if gap < time.Second || gap >= 2*time.Second {
+		t.Errorf("expected second attempt after ~1s, got %v", gap)
+	}
+}
+
+func TestWithRetry_SkipsSelfRetryingProviders(t *testing.T) {
+	for _, self := range []Provider{NewAnthropicProvider("test-key", ""), NewOpenAIProvider("test-key", "")} {
+		if got := WithRetry(self, RetryConfig{}); got != self {
+			t.Errorf("WithRetry(%s) = %T, want the provider unwrapped", self.Name(), got)
+		}
+	}
+
+	p, err := CreateProvider("openai", map[string]any{"api_key": "test"})
+	if err != nil {
+		t.Fatalf("CreateProvider() error = %v", err)
+	}
+	if _, ok := p.(*OpenAIProvider); !ok {
+		t.Errorf("CreateProvider(openai) = %T, want the self-retrying provider", p)
+	}
+}
+
+// flakyProvider fails with errs in turn, then succeeds
+type flakyProvider struct {
+	MockProvider
+	errs  []error
+	calls int
+}
+
+func (p *flakyProvider) CreateCompletion(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
+	p.calls++
+	if p.calls <= len(p.errs) {
+		return nil, p.errs[p.calls-1]
+	}
+	return &CompletionResponse{Content: "ok"}, nil
+}
+
+func TestWithRetry(t *testing.T) {
+	rateLimited := &ProviderError{Provider: "test", Code: ErrorCodeRateLimit, StatusCode: 429, RetryAfter: 20 * time.Millisecond}
+	unavailable := &ProviderError{Provider: "test", Code: ErrorCodeServerError, StatusCode: 503}
+	badRequest := &ProviderError{Provider: "test", Code: ErrorCodeInvalidRequest, StatusCode: 400}
+
+	tests := []struct {
+		name      string
+		errs      []error
+		wantCalls int
+		wantErr   error
+	}{
+		{"429 then success", []error{rateLimited}, 2, nil},
+		{"5xx then success", []error{unavailable, unavailable}, 3, nil},
+		{"client error is not retried", []error{badRequest}, 1, badRequest},
+		{"plain error is not retried", []error{errors.New("boom")}, 1, nil},
+		{"attempts exhausted", []error{unavailable, unavailable, unavailable}, 3, unavailable},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flaky := &flakyProvider{MockProvider: *NewMockProvider("test"), errs: tt.errs}
+			p := WithRetry(flaky, RetryConfig{MaxAttempts: 3, BaseDelay: time.Millisecond, MaxDelay: 50 * time.Millisecond})
+
+			_, err := p.CreateCompletion(context.Background(), CompletionRequest{})
+			if flaky.calls != tt.wantCalls {
+				t.Errorf("expected %d calls, got %d", tt.wantCalls, flaky.calls)
+			}
+			if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+			if tt.wantErr == nil && tt.wantCalls > len(tt.errs) && err != nil {
+				t.Errorf("unexpected error: %v", err)
+			}
+		})
+	}
+}
+
+func TestWithRetry_ContextCancelled(t *testing.T) {
+	flaky := &flakyProvider{
+		MockProvider: *NewMockProvider("test"),
+		errs:         []error{&ProviderError{Provider: "test", Code: ErrorCodeRateLimit, StatusCode: 429, RetryAfter: time.Minute}},
+	}
+	p := WithRetry(flaky, RetryConfig{MaxDelay: time.Hour})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := p.CreateCompletion(ctx, CompletionRequest{})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected context.DeadlineExceeded, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Errorf("expected cancellation to stop the wait, took %v", elapsed)
+	}
+	if flaky.calls != 1 {
+		t.Errorf("expected 1 call, got %d", flaky.calls)
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := RetryConfig{BaseDelay: 100 * time.Millisecond, MaxDelay: time.Second}
+
+	for attempt, want := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 4: 800 * time.Millisecond, 10: time.Second} {
+		if got := retryDelay(cfg, attempt, nil); got != want {
+			t.Errorf("retryDelay(attempt %d) = %v, want %v", attempt, got, want)
+		}
+	}
+
+	advertised := &ProviderError{RetryAfter: 5 * time.Second}
+	if got := retryDelay(cfg, 1, advertised); got != time.Second {
+		t.Errorf("expected Retry-After capped at MaxDelay, got %v", got)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	if got := parseRetryAfter("3"); got != 3*time.Second {
+		t.Errorf("parseRetryAfter(3) = %v", got)
+	}
+	date := time.Now().Add(10 * time.Second).UTC().Format(http.TimeFormat)
+	if got := parseRetryAfter(date); got < 8*time.Second || got > 10*time.Second {
+		t.Errorf("parseRetryAfter(%q) = %v, want ~10s", date, got)
+	}
+	for _, v := range []string{"", "-1", "soon"} {
+		if got := parseRetryAfter(v); got != 0 {
+			t.Errorf("parseRetryAfter(%q) = %v, want 0", v, got)
+		}
+	}
+}
@@ -0,0 +1,86 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	jsonschema "github.com/aixgo-dev/aixgo/pkg/llm/schema"
+)
+
+// DefaultSchemaCache is the cache used by SchemaFor and by clients that do
+// not configure their own
+var DefaultSchemaCache = NewSchemaCache()
+
+// SchemaCache memoizes the JSON Schemas schema.FromType generates, keyed by
+// Go type, so structured-output calls skip reflection and marshaling after
+// the first call for each type. It is safe for concurrent use.
+type SchemaCache struct {
+	mu      sync.RWMutex
+	schemas map[reflect.Type]json.RawMessage
+	hits    atomic.Int64
+	misses  atomic.Int64
+}
+
+// SchemaCacheStats reports cache effectiveness
+type SchemaCacheStats struct {
+	Hits   int64 // Lookups served from the cache
+	Misses int64 // Lookups that generated a schema
+	Size   int   // Types currently cached
+}
+
+// NewSchemaCache creates an empty schema cache
+func NewSchemaCache() *SchemaCache {
+	return &SchemaCache{schemas: make(map[reflect.Type]json.RawMessage)}
+}
+
+// Get returns the JSON Schema for t, generating it on first use. The
+// returned schema is shared and must not be modified.
+func (c *SchemaCache) Get(t reflect.Type) (json.RawMessage, error) {
+	c.mu.RLock()
+	schema, ok := c.schemas[t]
+	c.mu.RUnlock()
+	if ok {
+		c.hits.Add(1)
+		return schema, nil
+	}
+
+	c.misses.Add(1)
+	schema = jsonschema.FromType(t)
+
+	c.mu.Lock()
+	if cached, ok := c.schemas[t]; ok {
+		schema = cached // Another goroutine generated it first
+	} else {
+		c.schemas[t] = schema
+	}
+	c.mu.Unlock()
+	return schema, nil
+}
+
+// Stats returns the cache's hit, miss, and size counts
+func (c *SchemaCache) Stats() SchemaCacheStats {
+	c.mu.RLock()
+	size := len(c.schemas)
+	c.mu.RUnlock()
+	return SchemaCacheStats{
+		Hits:   c.hits.Load(),
+		Misses: c.misses.Load(),
+		Size:   size,
+	}
+}
+
+// Reset empties the cache and zeroes its stats
+func (c *SchemaCache) Reset() {
+	c.mu.Lock()
+	c.schemas = make(map[reflect.Type]json.RawMessage)
+	c.mu.Unlock()
+	c.hits.Store(0)
+	c.misses.Store(0)
+}
+
+// SchemaFor returns the JSON Schema for T from DefaultSchemaCache
+func SchemaFor[T any]() (json.RawMessage, error) {
+	return DefaultSchemaCache.Get(reflect.TypeFor[T]())
+}
@@ -0,0 +1,71 @@
+package provider
+
+import (
+	"encoding/json"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+func TestSchemaCache(t *testing.T) {
+	type Ticket struct {
+		Title    string `json:"title" validate:"required"`
+		Priority int    `json:"priority"`
+	}
+
+	cache := NewSchemaCache()
+	typ := reflect.TypeFor[Ticket]()
+
+	first, err := cache.Get(typ)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	second, err := cache.Get(typ)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if string(first) != string(second) {
+		t.Errorf("cached schema differs: %s vs %s", first, second)
+	}
+
+	var schema Schema
+	if err := json.Unmarshal(first, &schema); err != nil {
+		t.Fatalf("schema is not valid JSON: %v", err)
+	}
+	if schema.Type != "object" || schema.Properties["title"] == nil {
+		t.Errorf("unexpected schema %s", first)
+	}
+
+	if got, want := cache.Stats(), (SchemaCacheStats{Hits: 1, Misses: 1, Size: 1}); got != want {
+		t.Errorf("Stats() = %+v, want %+v", got, want)
+	}
+
+	cache.Reset()
+	if got := cache.Stats(); got != (SchemaCacheStats{}) {
+		t.Errorf("Stats() after Reset = %+v, want zero", got)
+	}
+}
+
+func TestSchemaCache_Concurrent(t *testing.T) {
+	type Item struct {
+		Name string `json:"name"`
+	}
+
+	cache := NewSchemaCache()
+	typ := reflect.TypeFor[Item]()
+
+	var wg sync.WaitGroup
+	for range 50 {
+		wg.Go(func() {
+			if _, err := cache.Get(typ); err != nil {
+				t.Errorf("Get() error = %v", err)
+			}
+		})
+	}
+	wg.Wait()
+
+	stats := cache.Stats()
+	if stats.Size != 1 || stats.Hits+stats.Misses != 50 {
+		t.Errorf("Stats() = %+v, want 1 entry and 50 lookups", stats)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"strings"
 
 	"github.com/aixgo-dev/aixgo/internal/llm/inference"
+	jsonschema "github.com/aixgo-dev/aixgo/pkg/llm/schema"
 )
 
 // JSONSchemaValidator validates JSON data against a JSON Schema
@@ -24,21 +25,29 @@ func NewJSONSchemaValidator(strict bool) *JSONSchemaValidator {
 
 // Schema represents a JSON Schema
 type Schema struct {
-	Type        string             `json:"type,omitempty"`
-	Properties  map[string]*Schema `json:"properties,omitempty"`
-	Required    []string           `json:"required,omitempty"`
-	Items       *Schema            `json:"items,omitempty"`
-	Enum        []any              `json:"enum,omitempty"`
-	Minimum     *float64           `json:"minimum,omitempty"`
-	Maximum     *float64           `json:"maximum,omitempty"`
-	MinLength   *int               `json:"minLength,omitempty"`
-	MaxLength   *int               `json:"maxLength,omitempty"`
-	Pattern     string             `json:"pattern,omitempty"`
-	Description string             `json:"description,omitempty"`
-	Default     any                `json:"default,omitempty"`
-	OneOf       []*Schema          `json:"oneOf,omitempty"`
-	AnyOf       []*Schema          `json:"anyOf,omitempty"`
-	AllOf       []*Schema          `json:"allOf,omitempty"`
+	Type                 string             `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64           `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64           `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int               `json:"minLength,omitempty"`
+	MaxLength            *int               `json:"maxLength,omitempty"`
+	MinItems             *int               `json:"minItems,omitempty"`
+	MaxItems             *int               `json:"maxItems,omitempty"`
+	MinProperties        *int               `json:"minProperties,omitempty"`
+	MaxProperties        *int               `json:"maxProperties,omitempty"`
+	Pattern              string             `json:"pattern,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	OneOf                []*Schema          `json:"oneOf,omitempty"`
+	AnyOf                []*Schema          `json:"anyOf,omitempty"`
+	AllOf                []*Schema          `json:"allOf,omitempty"`
 }
 
 // ParseSchema parses a JSON Schema from raw JSON
@@ -404,70 +413,62 @@ func extractJSON(text string) string {
 	return ""
 }
 
-// SchemaFromStruct generates a JSON Schema from a Go struct type
+// SchemaFromStruct generates a JSON Schema from a Go struct type (see
+// schema.FromType). Use SchemaFromStructE to reject types that aren't
+// structs.
 func SchemaFromStruct(t reflect.Type) *Schema {
-	return generateSchemaFromType(t)
+	return fromNode(jsonschema.Of(t))
 }
 
-func generateSchemaFromType(t reflect.Type) *Schema {
-	// Handle pointers
-	if t.Kind() == reflect.Pointer {
-		t = t.Elem()
+// SchemaFromStructE is SchemaFromStruct for a struct type, or a pointer to
+// one, returning an error for a nil or non-struct type
+func SchemaFromStructE(t reflect.Type) (*Schema, error) {
+	if t == nil {
+		return nil, fmt.Errorf("schema from struct: nil type")
 	}
+	if base := derefType(t); base.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("schema from struct: %s is not a struct", t)
+	}
+	return SchemaFromStruct(t), nil
+}
 
-	schema := &Schema{}
-
-	switch t.Kind() {
-	case reflect.String:
-		schema.Type = "string"
-	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		schema.Type = "integer"
-	case reflect.Float32, reflect.Float64:
-		schema.Type = "number"
-	case reflect.Bool:
-		schema.Type = "boolean"
-	case reflect.Slice, reflect.Array:
-		schema.Type = "array"
-		schema.Items = generateSchemaFromType(t.Elem())
-	case reflect.Struct:
-		schema.Type = "object"
-		schema.Properties = make(map[string]*Schema)
-		schema.Required = make([]string, 0)
-
-		for i := 0; i < t.NumField(); i++ {
-			field := t.Field(i)
-			if !field.IsExported() {
-				continue
-			}
-
-			// Get JSON field name
-			jsonTag := field.Tag.Get("json")
-			fieldName := field.Name
-			if jsonTag != "" && jsonTag != "-" {
-				parts := strings.Split(jsonTag, ",")
-				if parts[0] != "" {
-					fieldName = parts[0]
-				}
-			}
-
-			propSchema := generateSchemaFromType(field.Type)
-
-			// Add description from tag
-			if desc := field.Tag.Get("description"); desc != "" {
-				propSchema.Description = desc
-			}
-
-			schema.Properties[fieldName] = propSchema
+func derefType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return t
+}
 
-			// Check for required tag
-			validateTag := field.Tag.Get("validate")
-			if strings.Contains(validateTag, "required") {
-				schema.Required = append(schema.Required, fieldName)
-			}
+// fromNode copies a generated schema into a Schema
+func fromNode(n *jsonschema.Node) *Schema {
+	if n == nil {
+		return nil
+	}
+	s := &Schema{
+		Type:                 n.Type,
+		Format:               n.Format,
+		Description:          n.Description,
+		Required:             n.Required,
+		Items:                fromNode(n.Items),
+		AdditionalProperties: fromNode(n.AdditionalProperties),
+		Enum:                 n.Enum,
+		Minimum:              n.Minimum,
+		Maximum:              n.Maximum,
+		ExclusiveMinimum:     n.ExclusiveMinimum,
+		ExclusiveMaximum:     n.ExclusiveMaximum,
+		MinLength:            n.MinLength,
+		MaxLength:            n.MaxLength,
+		MinItems:             n.MinItems,
+		MaxItems:             n.MaxItems,
+		MinProperties:        n.MinProperties,
+		MaxProperties:        n.MaxProperties,
+		Pattern:              n.Pattern,
+	}
+	if n.Properties != nil {
+		s.Properties = make(map[string]*Schema, len(n.Properties))
+		for name, prop := range n.Properties {
+			s.Properties[name] = fromNode(prop)
 		}
-	case reflect.Map:
-		schema.Type = "object"
 	}
-
-	return schema
+	return s
 }
@@ -361,3 +361,48 @@ func TestSchemaFromStruct(t *testing.T) {
 		t.Error("expected 'name' to be in required fields")
 	}
 }
+
+func TestSchemaFromStruct_KeepsGeneratedKeywords(t *testing.T) {
+	type TestStruct struct {
+		Email  string            `json:"email" validate:"email"`
+		Score  float64           `json:"score" validate:"gt=0,lt=1"`
+		Tags   []string          `json:"tags" validate:"min=1,max=3"`
+		Labels map[string]string `json:"labels" validate:"max=4"`
+	}
+
+	schema := SchemaFromStruct(reflect.TypeOf(&TestStruct{}))
+	if got := schema.Properties["email"].Format; got != "email" {
+		t.Errorf("email format = %q, want email", got)
+	}
+	if score := schema.Properties["score"]; score.ExclusiveMinimum == nil || score.ExclusiveMaximum == nil {
+		t.Error("score exclusive bounds were dropped")
+	}
+	if tags := schema.Properties["tags"]; tags.MinItems == nil || *tags.MinItems != 1 || tags.MaxItems == nil || *tags.MaxItems != 3 {
+		t.Error("tags item bounds were dropped")
+	}
+	labels := schema.Properties["labels"]
+	if labels.AdditionalProperties == nil || labels.AdditionalProperties.Type != "string" || labels.MaxProperties == nil {
+		t.Error("labels map keywords were dropped")
+	}
+}
+
+func TestSchemaFromStructE(t *testing.T) {
+	type TestStruct struct {
+		Name string `json:"name"`
+	}
+
+	schema, err := SchemaFromStructE(reflect.TypeOf(&TestStruct{}))
+	if err != nil {
+		t.Fatalf("SchemaFromStructE() error = %v", err)
+	}
+	if schema.Type != "object" || schema.Properties["name"] == nil {
+		t.Errorf("SchemaFromStructE() = %+v, want the struct's object schema", schema)
+	}
+
+	if _, err := SchemaFromStructE(reflect.TypeOf([]string{})); err == nil {
+		t.Error("SchemaFromStructE() of a slice should fail")
+	}
+	if _, err := SchemaFromStructE(nil); err == nil {
+		t.Error("SchemaFromStructE(nil) should fail")
+	}
+}
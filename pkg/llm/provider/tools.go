@@ -0,0 +1,39 @@
+package provider
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrToolsNotSupported is returned by CreateWithTools for providers without
+// native tool calling
+var ErrToolsNotSupported = errors.New("native tool calling not supported")
+
+// ToolSpec describes a function the model may call
+type ToolSpec = Tool
+
+// ToolProvider is implemented by providers with native tool (function)
+// calling. The response carries any tool calls the model requested; the
+// caller executes them and continues the conversation with an assistant
+// message holding the calls and one "tool" message per result.
+//
+// The wrappers (InstrumentedProvider, WithRetry, BatchingProvider) always
+// implement it and return ErrToolsNotSupported when the wrapped provider
+// does not.
+type ToolProvider interface {
+	Provider
+
+	// CreateWithTools creates a completion in which the model may call tools
+	CreateWithTools(ctx context.Context, request CompletionRequest, tools []ToolSpec) (*CompletionResponse, error)
+}
+
+// CreateWithTools calls p's native tool calling, or returns
+// ErrToolsNotSupported if p does not implement ToolProvider.
+func CreateWithTools(ctx context.Context, p Provider, request CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	tp, ok := p.(ToolProvider)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrToolsNotSupported, p.Name())
+	}
+	return tp.CreateWithTools(ctx, request, tools)
+}
@@ -101,6 +101,10 @@ func (p *VertexAIProvider) Name() string {
 	return "vertexai"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *VertexAIProvider) retriesTransientErrors() {}
+
 // CreateCompletion creates a completion using the Gen AI SDK
 func (p *VertexAIProvider) CreateCompletion(ctx context.Context, req CompletionRequest) (*CompletionResponse, error) {
 	model := req.Model
@@ -113,6 +117,10 @@ func (p *VertexAIProvider) CreateCompletion(ctx context.Context, req CompletionR
 	// Always set temperature - 0 is a valid value for deterministic output
 	// Use -1 as sentinel for "not set" if needed, but typically callers set explicit values
 	config.Temperature = genai.Ptr(float32(req.Temperature))
+	if req.TopP != 0 {
+		config.TopP = genai.Ptr(float32(req.TopP))
+	}
+	config.StopSequences = req.Stop
 	// G115: Safe int to int32 conversion with bounds checking
 	if req.MaxTokens > 0 {
 		maxTokens, err := security.SafeIntToInt32(req.MaxTokens)
@@ -167,6 +175,13 @@ func (p *VertexAIProvider) CreateCompletion(ctx context.Context, req CompletionR
 	return p.parseResponse(resp)
 }
 
+// CreateWithTools creates a completion offering tools as Gen AI function
+// declarations. Requested function calls are returned in ToolCalls.
+func (p *VertexAIProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response with JSON schema
 func (p *VertexAIProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	model := req.Model
@@ -180,6 +195,10 @@ func (p *VertexAIProvider) CreateStructured(ctx context.Context, req StructuredR
 	}
 	// Always set temperature - 0 is a valid value for deterministic output
 	config.Temperature = genai.Ptr(float32(req.Temperature))
+	if req.TopP != 0 {
+		config.TopP = genai.Ptr(float32(req.TopP))
+	}
+	config.StopSequences = req.Stop
 	// G115: Safe int to int32 conversion with bounds checking
 	if req.MaxTokens > 0 {
 		maxTokens, err := security.SafeIntToInt32(req.MaxTokens)
@@ -255,6 +274,10 @@ func (p *VertexAIProvider) CreateStreaming(ctx context.Context, req CompletionRe
 	config := &genai.GenerateContentConfig{}
 	// Always set temperature - 0 is a valid value for deterministic output
 	config.Temperature = genai.Ptr(float32(req.Temperature))
+	if req.TopP != 0 {
+		config.TopP = genai.Ptr(float32(req.TopP))
+	}
+	config.StopSequences = req.Stop
 	// G115: Safe int to int32 conversion with bounds checking
 	if req.MaxTokens > 0 {
 		maxTokens, err := security.SafeIntToInt32(req.MaxTokens)
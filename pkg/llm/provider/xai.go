@@ -74,12 +74,18 @@ func (p *XAIProvider) Name() string {
 	return "xai"
 }
 
+// retriesTransientErrors marks the provider's built-in retry loop for
+// WithRetry
+func (p *XAIProvider) retriesTransientErrors() {}
+
 // xaiRequest represents the X.AI API request format (OpenAI-compatible)
 type xaiRequest struct {
 	Model          string       `json:"model"`
 	Messages       []xaiMessage `json:"messages"`
 	Temperature    float64      `json:"temperature,omitempty"`
 	MaxTokens      int          `json:"max_tokens,omitempty"`
+	TopP           float64      `json:"top_p,omitempty"`
+	Stop           []string     `json:"stop,omitempty"`
 	Tools          []xaiTool    `json:"tools,omitempty"`
 	Stream         bool         `json:"stream,omitempty"`
 	ResponseFormat *xaiRespFmt  `json:"response_format,omitempty"`
@@ -159,6 +165,13 @@ func (p *XAIProvider) CreateCompletion(ctx context.Context, req CompletionReques
 	return p.parseResponse(&resp)
 }
 
+// CreateWithTools creates a chat completion offering tools through the
+// OpenAI-compatible tools field. Requested calls are returned in ToolCalls.
+func (p *XAIProvider) CreateWithTools(ctx context.Context, req CompletionRequest, tools []ToolSpec) (*CompletionResponse, error) {
+	req.Tools = tools
+	return p.CreateCompletion(ctx, req)
+}
+
 // CreateStructured creates a structured response
 func (p *XAIProvider) CreateStructured(ctx context.Context, req StructuredRequest) (*StructuredResponse, error) {
 	model := req.Model
@@ -238,7 +251,13 @@ func (p *XAIProvider) CreateStreaming(ctx context.Context, req CompletionRequest
 func (p *XAIProvider) buildRequest(req CompletionRequest, model string, stream bool) xaiRequest {
 	messages := make([]xaiMessage, len(req.Messages))
 	for i, m := range req.Messages {
-		messages[i] = xaiMessage{Role: m.Role, Content: m.Content}
+		messages[i] = xaiMessage{Role: m.Role, Content: m.Content, ToolCallID: m.ToolCallID}
+		for _, tc := range m.ToolCalls {
+			call := xaiToolCall{ID: tc.ID, Type: "function"}
+			call.Function.Name = tc.Function.Name
+			call.Function.Arguments = string(tc.Function.Arguments)
+			messages[i].ToolCalls = append(messages[i].ToolCalls, call)
+		}
 	}
 
 	xReq := xaiRequest{
@@ -246,6 +265,8 @@ func (p *XAIProvider) buildRequest(req CompletionRequest, model string, stream b
 		Messages:    messages,
 		Temperature: req.Temperature,
 		MaxTokens:   req.MaxTokens,
+		TopP:        req.TopP,
+		Stop:        req.Stop,
 		Stream:      stream,
 	}
 
@@ -272,6 +293,9 @@ func (p *XAIProvider) doRequestWithRetry(ctx context.Context, endpoint string, r
 	for attempt := 0; attempt < xaiMaxRetries; attempt++ {
 		if attempt > 0 {
 			delay := time.Duration(math.Pow(2, float64(attempt))) * time.Second
+			if after := retryAfterOf(lastErr); after > 0 {
+				delay = min(after, maxRetryAfter)
+			}
 			select {
 			case <-ctx.Done():
 				return ctx.Err()
@@ -338,6 +362,7 @@ func (p *XAIProvider) handleErrorResponse(resp *http.Response) error {
 			Type:        errResp.Error.Type,
 			StatusCode:  resp.StatusCode,
 			IsRetryable: code == ErrorCodeRateLimit || code == ErrorCodeServerError,
+			RetryAfter:  parseRetryAfter(resp.Header.Get("Retry-After")),
 		}
 	}
 
@@ -370,3 +370,37 @@ func TestXAIProvider_DefaultBaseURL(t *testing.T) {
 		t.Errorf("Default baseURL = %q, want %q", p.baseURL, xaiBaseURL)
 	}
 }
+
+func TestXAIProvider_CreateWithToolsContinuesTurn(t *testing.T) {
+	var req map[string]any
+	p := NewXAIProvider("test-key", "grok-beta", fixtureServer(t, "openai_no_tool.json", &req).URL)
+
+	call := ToolCall{ID: "call_1", Type: "function", Function: FunctionCall{
+		Name: "get_weather", Arguments: json.RawMessage(`{"location":"Paris"}`),
+	}}
+	_, err := CreateWithTools(context.Background(), p, CompletionRequest{
+		Messages: []Message{
+			{Role: "user", Content: "What's the weather in Paris?"},
+			{Role: "assistant", ToolCalls: []ToolCall{call}},
+			{Role: "tool", Content: `{"temp_c":18}`, ToolCallID: "call_1"},
+		},
+	}, []ToolSpec{{Name: "get_weather", Parameters: json.RawMessage(`{"type":"object"}`)}})
+	if err != nil {
+		t.Fatalf("CreateWithTools() error = %v", err)
+	}
+
+	if tools, _ := req["tools"].([]any); len(tools) != 1 {
+		t.Errorf("request tools = %v, want one", req["tools"])
+	}
+	messages, _ := req["messages"].([]any)
+	if len(messages) != 3 {
+		t.Fatalf("request messages = %v, want 3", req["messages"])
+	}
+	calls, _ := messages[1].(map[string]any)["tool_calls"].([]any)
+	if len(calls) != 1 || calls[0].(map[string]any)["function"].(map[string]any)["arguments"] != `{"location":"Paris"}` {
+		t.Errorf("assistant message = %v, want the tool call", messages[1])
+	}
+	if tool := messages[2].(map[string]any); tool["tool_call_id"] != "call_1" {
+		t.Errorf("tool message = %v, want the result linked to its call", tool)
+	}
+}
@@ -0,0 +1,292 @@
+// Package schema generates JSON Schemas for structured LLM output from Go
+// types. Field names come from json tags and constraints from validate tags,
+// using the same rules the structured-output validator enforces:
+//
+//	type Ticket struct {
+//	    Title    string   `json:"title" validate:"required,max=80"`
+//	    Priority string   `json:"priority" validate:"oneof=low medium high"`
+//	    Score    int      `json:"score" validate:"min=1,max=5"`
+//	    Tags     []string `json:"tags,omitempty" validate:"max=3,dive,min=2"`
+//	}
+//
+//	raw := schema.FromType(reflect.TypeOf(Ticket{}))
+package schema
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Node is a JSON Schema, limited to the keywords generated from Go types
+type Node struct {
+	Type                 string           `json:"type,omitempty"`
+	Format               string           `json:"format,omitempty"`
+	Description          string           `json:"description,omitempty"`
+	Properties           map[string]*Node `json:"properties,omitempty"`
+	Required             []string         `json:"required,omitempty"`
+	Items                *Node            `json:"items,omitempty"`
+	AdditionalProperties *Node            `json:"additionalProperties,omitempty"`
+	Enum                 []any            `json:"enum,omitempty"`
+	Minimum              *float64         `json:"minimum,omitempty"`
+	Maximum              *float64         `json:"maximum,omitempty"`
+	ExclusiveMinimum     *float64         `json:"exclusiveMinimum,omitempty"`
+	ExclusiveMaximum     *float64         `json:"exclusiveMaximum,omitempty"`
+	MinLength            *int             `json:"minLength,omitempty"`
+	MaxLength            *int             `json:"maxLength,omitempty"`
+	MinItems             *int             `json:"minItems,omitempty"`
+	MaxItems             *int             `json:"maxItems,omitempty"`
+	MinProperties        *int             `json:"minProperties,omitempty"`
+	MaxProperties        *int             `json:"maxProperties,omitempty"`
+	Pattern              string           `json:"pattern,omitempty"`
+}
+
+var (
+	timeType = reflect.TypeOf(time.Time{})
+	rawType  = reflect.TypeOf(json.RawMessage{})
+)
+
+// FromType returns the JSON Schema for t.
+//
+// Struct fields are named by their json tag; fields tagged "-" and
+// unexported fields are skipped, and embedded structs are flattened as
+// encoding/json does. These validate rules map to schema keywords:
+//
+//   - required: the field is listed in required
+//   - oneof: enum, typed to match the field
+//   - min, max, gte, lte: minimum/maximum for numbers, minLength/maxLength
+//     for strings, minItems/maxItems for slices, and minProperties/
+//     maxProperties for maps
+//   - gt, lt: exclusiveMinimum/exclusiveMaximum for numbers
+//   - pattern, email, url, uuid: pattern or format
+//
+// Rules after "dive" apply to slice elements or map values. A description tag sets the
+// field's description. Recursive types are cut off with a plain object.
+func FromType(t reflect.Type) json.RawMessage {
+	// Bounds are always finite, so a Node always marshals
+	data, _ := json.Marshal(Of(t))
+	return data
+}
+
+// Of returns the JSON Schema for t as a Node, by the same rules as FromType
+func Of(t reflect.Type) *Node {
+	return fromType(t, make(map[reflect.Type]bool))
+}
+
+func fromType(t reflect.Type, visiting map[reflect.Type]bool) *Node {
+	for t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+
+	switch t {
+	case timeType:
+		return &Node{Type: "string", Format: "date-time"}
+	case rawType:
+		return &Node{}
+	}
+
+	switch t.Kind() {
+	case reflect.String:
+		return &Node{Type: "string"}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return &Node{Type: "integer"}
+	case reflect.Float32, reflect.Float64:
+		return &Node{Type: "number"}
+	case reflect.Bool:
+		return &Node{Type: "boolean"}
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Node{Type: "string"} // encoding/json base64-encodes bytes
+		}
+		return &Node{Type: "array", Items: fromType(t.Elem(), visiting)}
+	case reflect.Map:
+		return &Node{Type: "object", AdditionalProperties: fromType(t.Elem(), visiting)}
+	case reflect.Struct:
+		if visiting[t] {
+			return &Node{Type: "object"}
+		}
+		visiting[t] = true
+		defer delete(visiting, t)
+
+		n := &Node{Type: "object", Properties: make(map[string]*Node)}
+		addFields(n, t, visiting)
+		return n
+	default:
+		return &Node{} // interfaces accept any value
+	}
+}
+
+// addFields adds the properties of struct type t to n
+func addFields(n *Node, t reflect.Type, visiting map[reflect.Type]bool) {
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name, skip := fieldName(field)
+		if skip {
+			continue
+		}
+
+		if field.Anonymous && name == "" {
+			embedded := field.Type
+			if embedded.Kind() == reflect.Pointer {
+				embedded = embedded.Elem()
+			}
+			if embedded.Kind() == reflect.Struct {
+				addFields(n, embedded, visiting)
+				continue
+			}
+		}
+		if !field.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = field.Name
+		}
+
+		prop := fromType(field.Type, visiting)
+		if desc := field.Tag.Get("description"); desc != "" {
+			prop.Description = desc
+		}
+		if applyRules(prop, field.Tag.Get("validate")) {
+			n.Required = append(n.Required, name)
+		}
+		n.Properties[name] = prop
+	}
+}
+
+// fieldName returns the field's json name, empty if the tag has none
+func fieldName(field reflect.StructField) (string, bool) {
+	tag := field.Tag.Get("json")
+	if tag == "-" {
+		return "", true
+	}
+	name, _, _ := strings.Cut(tag, ",")
+	return name, false
+}
+
+// applyRules sets the constraints of a validate tag on n and reports whether
+// the field is required
+func applyRules(n *Node, tag string) bool {
+	if tag == "" {
+		return false
+	}
+
+	required := false
+	target := n
+	for _, rule := range strings.Split(tag, ",") {
+		name, param, _ := strings.Cut(strings.TrimSpace(rule), "=")
+		switch name {
+		case "required":
+			if target == n {
+				required = true
+			}
+		case "dive":
+			switch {
+			case target.Items != nil:
+				target = target.Items
+			case target.AdditionalProperties != nil:
+				target = target.AdditionalProperties
+			default:
+				return required
+			}
+		case "min", "gte":
+			setBound(target, param, true)
+		case "max", "lte":
+			setBound(target, param, false)
+		case "gt":
+			if isNumber(target) {
+				target.ExclusiveMinimum = parseFloat(param)
+			}
+		case "lt":
+			if isNumber(target) {
+				target.ExclusiveMaximum = parseFloat(param)
+			}
+		case "oneof":
+			target.Enum = enumValues(target, strings.Fields(param))
+		case "pattern":
+			target.Pattern = param
+		case "email":
+			target.Format = "email"
+		case "url":
+			target.Format = "uri"
+		case "uuid":
+			target.Format = "uuid"
+		}
+	}
+	return required
+}
+
+// setBound sets a lower or upper bound, as a value, length, or item count
+// depending on n's type
+func setBound(n *Node, param string, lower bool) {
+	switch n.Type {
+	case "string":
+		if v, err := strconv.Atoi(param); err == nil {
+			if lower {
+				n.MinLength = &v
+			} else {
+				n.MaxLength = &v
+			}
+		}
+	case "array":
+		if v, err := strconv.Atoi(param); err == nil {
+			if lower {
+				n.MinItems = &v
+			} else {
+				n.MaxItems = &v
+			}
+		}
+	case "object":
+		if v, err := strconv.Atoi(param); err == nil && n.AdditionalProperties != nil {
+			if lower {
+				n.MinProperties = &v
+			} else {
+				n.MaxProperties = &v
+			}
+		}
+	case "integer", "number":
+		if lower {
+			n.Minimum = parseFloat(param)
+		} else {
+			n.Maximum = parseFloat(param)
+		}
+	}
+}
+
+func isNumber(n *Node) bool {
+	return n.Type == "integer" || n.Type == "number"
+}
+
+// parseFloat parses a bound, ignoring values JSON cannot encode
+func parseFloat(param string) *float64 {
+	v, err := strconv.ParseFloat(param, 64)
+	if err != nil || math.IsNaN(v) || math.IsInf(v, 0) {
+		return nil
+	}
+	return &v
+}
+
+// enumValues converts oneof options to n's type so numeric enums match
+// numeric values
+func enumValues(n *Node, options []string) []any {
+	values := make([]any, 0, len(options))
+	for _, option := range options {
+		switch n.Type {
+		case "integer":
+			if v, err := strconv.ParseInt(option, 10, 64); err == nil {
+				values = append(values, v)
+				continue
+			}
+		case "number":
+			if v, err := strconv.ParseFloat(option, 64); err == nil {
+				values = append(values, v)
+				continue
+			}
+		}
+		values = append(values, option)
+	}
+	return values
+}
@@ -0,0 +1,103 @@
+package schema
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type address struct {
+	City    string `json:"city" validate:"required"`
+	Country string `json:"country" validate:"oneof=US CA MX"`
+}
+
+type audit struct {
+	CreatedAt time.Time `json:"created_at"`
+}
+
+type user struct {
+	audit
+	Name     string            `json:"name" validate:"required,min=2,max=50" description:"Full name"`
+	Email    string            `json:"email,omitempty" validate:"email"`
+	Age      int               `json:"age" validate:"gte=0,lt=150"`
+	Level    int               `json:"level" validate:"oneof=1 2 3"`
+	Address  address           `json:"address" validate:"required"`
+	Previous []*address        `json:"previous" validate:"max=5,dive,required"`
+	Tags     []string          `json:"tags" validate:"dive,oneof=admin staff"`
+	Labels   map[string]string `json:"labels"`
+	Manager  *user             `json:"manager,omitempty"`
+	Secret   string            `json:"-"`
+	internal string
+}
+
+func generate(t *testing.T, v any) map[string]any {
+	t.Helper()
+	var s map[string]any
+	if err := json.Unmarshal(FromType(reflect.TypeOf(v)), &s); err != nil {
+		t.Fatalf("FromType() produced invalid JSON: %v", err)
+	}
+	return s
+}
+
+// prop returns the property at path, failing if it is missing
+func prop(t *testing.T, s map[string]any, path ...string) map[string]any {
+	t.Helper()
+	for _, name := range path {
+		props, _ := s["properties"].(map[string]any)
+		next, ok := props[name].(map[string]any)
+		if !ok {
+			t.Fatalf("missing property %v", path)
+		}
+		s = next
+	}
+	return s
+}
+
+// assertJSON compares got, re-encoded with sorted keys, to want
+func assertJSON(t *testing.T, got any, want string) {
+	t.Helper()
+	data, _ := json.Marshal(got)
+	if string(data) != want {
+		t.Errorf("got %s, want %s", data, want)
+	}
+}
+
+func TestFromType(t *testing.T) {
+	s := generate(t, user{})
+
+	assertJSON(t, s["type"], `"object"`)
+	assertJSON(t, s["required"], `["name","address"]`)
+
+	props := s["properties"].(map[string]any)
+	for _, skipped := range []string{"Secret", "-", "internal", "audit"} {
+		if _, ok := props[skipped]; ok {
+			t.Errorf("property %q should be skipped", skipped)
+		}
+	}
+
+	assertJSON(t, prop(t, s, "created_at"), `{"format":"date-time","type":"string"}`)
+	assertJSON(t, prop(t, s, "name"), `{"description":"Full name","maxLength":50,"minLength":2,"type":"string"}`)
+	assertJSON(t, prop(t, s, "email"), `{"format":"email","type":"string"}`)
+	assertJSON(t, prop(t, s, "age"), `{"exclusiveMaximum":150,"minimum":0,"type":"integer"}`)
+	assertJSON(t, prop(t, s, "level")["enum"], `[1,2,3]`)
+	assertJSON(t, prop(t, s, "labels"), `{"additionalProperties":{"type":"string"},"type":"object"}`)
+
+	// Nested structs carry their own required fields and enums
+	assertJSON(t, prop(t, s, "address")["required"], `["city"]`)
+	assertJSON(t, prop(t, s, "address", "country")["enum"], `["US","CA","MX"]`)
+
+	previous := prop(t, s, "previous")
+	assertJSON(t, previous["maxItems"], `5`)
+	assertJSON(t, previous["items"].(map[string]any)["required"], `["city"]`)
+	assertJSON(t, prop(t, s, "tags")["items"], `{"enum":["admin","staff"],"type":"string"}`)
+
+	// Recursion is cut off
+	assertJSON(t, prop(t, s, "manager"), `{"type":"object"}`)
+}
+
+func TestFromType_NonStruct(t *testing.T) {
+	assertJSON(t, generate(t, []int{}), `{"items":{"type":"integer"},"type":"array"}`)
+	assertJSON(t, generate(t, json.RawMessage{}), `{}`)
+	assertJSON(t, generate(t, []byte{}), `{"type":"string"}`)
+}
@@ -0,0 +1,72 @@
+package mcp
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/cache"
+)
+
+// maxCachedToolResults bounds the tool result cache; the least recently used
+// results are evicted first
+const maxCachedToolResults = 10000
+
+// NoCache excludes a tool from the server's result cache. Use it for
+// non-idempotent tools whose results must not be reused.
+func NoCache() ToolOption {
+	return func(o *toolOptions) {
+		o.noCache = true
+	}
+}
+
+// toolCache holds successful tool results keyed by caller, tool name and
+// arguments
+type toolCache struct {
+	entries *cache.LRU[string, *CallToolResult]
+}
+
+func newToolCache(ttl time.Duration) *toolCache {
+	return &toolCache{entries: cache.New[string, *CallToolResult](maxCachedToolResults, ttl)}
+}
+
+// toolCacheKey returns the tool name plus a stable hash of the caller's
+// principal ID ("" when unauthenticated) and the arguments, so one
+// principal's results are never served to another. encoding/json sorts map
+// keys, so equal arguments always hash the same.
+func toolCacheKey(principalID, name string, args map[string]any) (string, error) {
+	data, err := json.Marshal(struct {
+		Principal string         `json:"principal"`
+		Args      map[string]any `json:"args"`
+	}{principalID, args})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash arguments: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return name + ":" + hex.EncodeToString(sum[:]), nil
+}
+
+// get returns a copy of the cached result for key, if present and fresh
+func (c *toolCache) get(key string) (*CallToolResult, bool) {
+	result, ok, _ := c.entries.Get(context.Background(), key)
+	if !ok {
+		return nil, false
+	}
+	return copyToolResult(result), true
+}
+
+// set stores a copy of result under key
+func (c *toolCache) set(key string, result *CallToolResult) {
+	_ = c.entries.Set(context.Background(), key, copyToolResult(result))
+}
+
+func copyToolResult(result *CallToolResult) *CallToolResult {
+	return &CallToolResult{
+		Content: append([]Content(nil), result.Content...),
+		IsError: result.IsError,
+		Error:   result.Error,
+	}
+}
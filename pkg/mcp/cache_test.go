@@ -0,0 +1,126 @@
+package mcp
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/security"
+)
+
+// countingTool returns a tool that reports how often it ran
+func countingTool(name string, calls *int, err error) Tool {
+	return Tool{
+		Name: name,
+		Handler: func(ctx context.Context, args Args) (any, error) {
+			*calls++
+			if err != nil {
+				return nil, err
+			}
+			return fmt.Sprintf("%s call %d for %v", name, *calls, args["city"]), nil
+		},
+	}
+}
+
+func callText(t *testing.T, server *Server, name string, args map[string]any) string {
+	t.Helper()
+	result, err := server.CallTool(context.Background(), CallToolParams{Name: name, Arguments: args})
+	if err != nil {
+		t.Fatalf("CallTool() error = %v", err)
+	}
+	return result.Content[0].Text
+}
+
+func TestServer_ToolCache(t *testing.T) {
+	server := NewServer("weather")
+	server.EnableToolCache(time.Minute)
+
+	var calls int
+	if err := server.RegisterTool(countingTool("get_weather", &calls, nil)); err != nil {
+		t.Fatal(err)
+	}
+
+	first := callText(t, server, "get_weather", map[string]any{"city": "Oslo", "units": "metric"})
+	// Same arguments in a different insertion order hit the cache
+	second := callText(t, server, "get_weather", map[string]any{"units": "metric", "city": "Oslo"})
+	if calls != 1 || second != first {
+		t.Errorf("identical call should be cached: calls = %d, results %q and %q", calls, first, second)
+	}
+
+	// Distinct arguments miss the cache
+	if got := callText(t, server, "get_weather", map[string]any{"city": "Bergen", "units": "metric"}); calls != 2 || got == first {
+		t.Errorf("distinct arguments should miss the cache: calls = %d, result %q", calls, got)
+	}
+}
+
+func TestServer_ToolCachePerPrincipal(t *testing.T) {
+	server := NewServer("weather")
+	server.EnableToolCache(time.Minute)
+
+	var calls int
+	_ = server.RegisterTool(countingTool("get_weather", &calls, nil))
+
+	callAs := func(id string) string {
+		t.Helper()
+		ctx := security.WithAuthContext(context.Background(), &security.AuthContext{
+			Principal: &security.Principal{ID: id},
+		})
+		result, err := server.CallTool(ctx, CallToolParams{Name: "get_weather", Arguments: map[string]any{"city": "Oslo"}})
+		if err != nil {
+			t.Fatalf("CallTool() error = %v", err)
+		}
+		return result.Content[0].Text
+	}
+
+	alice := callAs("alice")
+	if got := callAs("bob"); calls != 2 || got == alice {
+		t.Errorf("another principal should miss the cache: calls = %d, result %q", calls, got)
+	}
+	if got := callAs("alice"); calls != 2 || got != alice {
+		t.Errorf("same principal should hit the cache: calls = %d, result %q", calls, got)
+	}
+	callText(t, server, "get_weather", map[string]any{"city": "Oslo"})
+	if calls != 3 {
+		t.Errorf("unauthenticated call should not share a principal's entry: calls = %d", calls)
+	}
+}
+
+func TestServer_ToolCacheExpiryAndOptOut(t *testing.T) {
+	server := NewServer("test")
+	server.EnableToolCache(200 * time.Millisecond)
+
+	var cached, uncached, failing int
+	_ = server.RegisterTool(countingTool("cached", &cached, nil))
+	_ = server.RegisterToolWithOptions(countingTool("book_flight", &uncached, nil), NoCache())
+	_ = server.RegisterTool(countingTool("failing", &failing, errors.New("backend down")))
+
+	args := map[string]any{"city": "Oslo"}
+	for range 2 {
+		callText(t, server, "cached", args)
+		callText(t, server, "book_flight", args)
+		callText(t, server, "failing", args)
+	}
+	if cached != 1 || uncached != 2 || failing != 2 {
+		t.Errorf("calls = cached %d, no-cache %d, failing %d, want 1, 2, 2", cached, uncached, failing)
+	}
+
+	time.Sleep(250 * time.Millisecond)
+	callText(t, server, "cached", args)
+	if cached != 2 {
+		t.Errorf("expired entry should be re-executed, calls = %d", cached)
+	}
+}
+
+func TestServer_ToolCacheDisabledByDefault(t *testing.T) {
+	server := NewServer("test")
+	var calls int
+	_ = server.RegisterTool(countingTool("tool", &calls, nil))
+
+	callText(t, server, "tool", nil)
+	callText(t, server, "tool", nil)
+	if calls != 2 {
+		t.Errorf("calls = %d, want 2 without EnableToolCache", calls)
+	}
+}
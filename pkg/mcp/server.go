@@ -3,6 +3,7 @@ package mcp
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"reflect"
 	"strings"
@@ -19,6 +20,10 @@ type Server struct {
 	transport Transport
 	mu        sync.RWMutex
 
+	// Result caching (disabled unless EnableToolCache is called)
+	toolCache *toolCache
+	noCache   map[string]bool
+
 	// Security components
 	securityConfig  *security.SecurityConfig
 	authExtractor   security.AuthExtractor
@@ -86,8 +91,9 @@ func WithSecurityConfig(config *security.SecurityConfig) ServerOption {
 // NewServer creates a new MCP server with options
 func NewServer(name string, opts ...ServerOption) *Server {
 	server := &Server{
-		name:  name,
-		tools: make(map[string]Tool),
+		name:    name,
+		tools:   make(map[string]Tool),
+		noCache: make(map[string]bool),
 		// Default security components (no-op/allow-all for backward compatibility)
 		authenticator:   security.NewNoAuthAuthenticator(),
 		authorizer:      security.NewAllowAllAuthorizer(),
@@ -139,8 +145,43 @@ func NewServer(name string, opts ...ServerOption) *Server {
 	return server
 }
 
+// ToolOption configures how a tool is registered
+type ToolOption func(*toolOptions)
+
+type toolOptions struct {
+	noCache     bool
+	inputSchema json.RawMessage
+}
+
+// WithInputSchema declares the tool's arguments as a JSON Schema object,
+// replacing tool.Schema. Calls with arguments that do not match are rejected
+// before the handler runs.
+func WithInputSchema(schema json.RawMessage) ToolOption {
+	return func(o *toolOptions) {
+		o.inputSchema = schema
+	}
+}
+
 // RegisterTool registers a tool with the server
 func (s *Server) RegisterTool(tool Tool) error {
+	return s.RegisterToolWithOptions(tool)
+}
+
+// RegisterToolWithOptions registers a tool with per-tool options such as
+// NoCache or WithInputSchema
+func (s *Server) RegisterToolWithOptions(tool Tool, opts ...ToolOption) error {
+	var options toolOptions
+	for _, opt := range opts {
+		opt(&options)
+	}
+	if options.inputSchema != nil {
+		schema, err := ParseInputSchema(options.inputSchema)
+		if err != nil {
+			return fmt.Errorf("tool %s: %w", tool.Name, err)
+		}
+		tool.Schema = schema
+	}
+
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
@@ -157,9 +198,22 @@ func (s *Server) RegisterTool(tool Tool) error {
 	}
 
 	s.tools[tool.Name] = tool
+	if options.noCache {
+		s.noCache[tool.Name] = true
+	}
 	return nil
 }
 
+// EnableToolCache caches successful tool results for ttl, keyed by the
+// caller's principal, tool name and arguments (0 = until evicted). The cache
+// holds up to 10000 results, evicting the least recently used. Tools
+// registered with NoCache are always executed.
+func (s *Server) EnableToolCache(ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.toolCache = newToolCache(ttl)
+}
+
 // RegisterTypedTool registers a tool with type-safe handler using reflection
 func RegisterTypedTool[TInput any, TOutput any](
 	s *Server,
@@ -360,6 +414,10 @@ func (s *Server) CallTool(ctx context.Context, params CallToolParams) (*CallTool
 	// Get tool
 	s.mu.RLock()
 	tool, exists := s.tools[params.Name]
+	cache := s.toolCache
+	if s.noCache[params.Name] {
+		cache = nil
+	}
 	s.mu.RUnlock()
 
 	if !exists {
@@ -415,11 +473,32 @@ func (s *Server) CallTool(ctx context.Context, params CallToolParams) (*CallTool
 			if s.auditLogger != nil {
 				s.auditLogger.LogToolExecution(ctx, params.Name, params.Arguments, nil, err)
 			}
+			// Schema errors name the offending field, which is safe to return
+			var argErr *ArgumentError
+			if errors.As(err, &argErr) {
+				return argumentErrorResult(argErr), nil
+			}
 			return s.errorResult(security.ErrCodeValidation,
 				"argument validation failed", err)
 		}
 	}
 
+	// Serve repeated calls from the caller's own cache entries; security
+	// checks above still apply
+	var cacheKey string
+	if cache != nil {
+		var principalID string
+		if principal != nil {
+			principalID = principal.ID
+		}
+		if key, err := toolCacheKey(principalID, params.Name, params.Arguments); err == nil {
+			cacheKey = key
+			if cached, ok := cache.get(cacheKey); ok {
+				return cached, nil
+			}
+		}
+	}
+
 	// Set execution timeout
 	toolCtx, cancel := s.timeoutManager.WithTimeout(ctx, params.Name)
 	defer cancel()
@@ -438,10 +517,14 @@ func (s *Server) CallTool(ctx context.Context, params CallToolParams) (*CallTool
 
 	// Convert result to content
 	content := formatResult(result)
-	return &CallToolResult{
+	callResult := &CallToolResult{
 		Content: []Content{content},
 		IsError: false,
-	}, nil
+	}
+	if cacheKey != "" {
+		cache.set(cacheKey, callResult)
+	}
+	return callResult, nil
 }
 
 // errorResult creates an error result with sanitized error messages
@@ -465,9 +548,23 @@ func (s *Server) errorResult(code security.ErrorCode, message string, err error)
 			Text: errorText,
 		}},
 		IsError: true,
+		Error:   &ToolError{Code: code, Message: errorText},
 	}, nil
 }
 
+// argumentErrorResult reports a schema validation failure for one argument
+func argumentErrorResult(err *ArgumentError) *CallToolResult {
+	text := fmt.Sprintf("argument validation failed: %v", err)
+	return &CallToolResult{
+		Content: []Content{{
+			Type: "text",
+			Text: text,
+		}},
+		IsError: true,
+		Error:   &ToolError{Code: security.ErrCodeValidation, Message: text, Field: err.Field},
+	}
+}
+
 // Serve starts the MCP server with the given transport
 func (s *Server) Serve(transport Transport) error {
 	s.mu.Lock()
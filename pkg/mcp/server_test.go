@@ -5,6 +5,8 @@ import (
 	"errors"
 	"sync"
 	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/security"
 )
 
 func TestNewServer(t *testing.T) {
@@ -480,3 +482,85 @@ func (m *mockTransport) Close() error {
 	m.closed = true
 	return m.closeErr
 }
+
+func TestServer_InputSchemaValidation(t *testing.T) {
+	server := NewServer("weather")
+
+	var calls int
+	err := server.RegisterToolWithOptions(Tool{
+		Name: "get_forecast",
+		Handler: func(ctx context.Context, args Args) (any, error) {
+			calls++
+			return "sunny", nil
+		},
+	}, WithInputSchema([]byte(`{
+		"type": "object",
+		"properties": {
+			"city": {"type": "string", "pattern": "^[A-Za-z ]+$"},
+			"days": {"type": "integer", "minimum": 1, "maximum": 14}
+		},
+		"required": ["city", "days"]
+	}`)))
+	if err != nil {
+		t.Fatalf("RegisterToolWithOptions() error = %v", err)
+	}
+
+	tests := []struct {
+		name      string
+		args      map[string]any
+		wantField string
+	}{
+		{"valid", map[string]any{"city": "Oslo", "days": float64(3)}, ""},
+		{"string days", map[string]any{"city": "Oslo", "days": "three"}, "days"},
+		{"fractional days", map[string]any{"city": "Oslo", "days": 2.5}, "days"},
+		{"days above maximum", map[string]any{"city": "Oslo", "days": float64(30)}, "days"},
+		{"missing city", map[string]any{"days": float64(3)}, "city"},
+		{"city fails pattern", map[string]any{"city": "Oslo; rm", "days": float64(3)}, "city"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			before := calls
+			result, err := server.CallTool(context.Background(), CallToolParams{Name: "get_forecast", Arguments: tt.args})
+			if err != nil {
+				t.Fatalf("CallTool() error = %v", err)
+			}
+			if tt.wantField == "" {
+				if result.IsError || calls != before+1 {
+					t.Errorf("valid call rejected: %+v", result)
+				}
+				return
+			}
+			if !result.IsError || result.Error == nil {
+				t.Fatalf("expected structured error, got %+v", result)
+			}
+			if result.Error.Field != tt.wantField || result.Error.Code != security.ErrCodeValidation {
+				t.Errorf("error = %+v, want field %q", result.Error, tt.wantField)
+			}
+			if calls != before {
+				t.Error("handler ran despite invalid arguments")
+			}
+		})
+	}
+}
+
+func TestParseInputSchema(t *testing.T) {
+	schema, err := ParseInputSchema([]byte(`{"type":"object","properties":{"q":{"type":"string","maxLength":10}},"required":["q"]}`))
+	if err != nil {
+		t.Fatalf("ParseInputSchema() error = %v", err)
+	}
+	if field := schema["q"]; field.Type != "string" || !field.Required || field.MaxLength != 10 {
+		t.Errorf("schema[q] = %+v", field)
+	}
+
+	for _, raw := range []string{
+		`{"type":"array"}`,
+		`{"type":"object","required":["missing"]}`,
+		`{"properties":{"q":{"type":"string","pattern":"("}}}`,
+		`not json`,
+	} {
+		if _, err := ParseInputSchema([]byte(raw)); err == nil {
+			t.Errorf("ParseInputSchema(%s) expected error", raw)
+		}
+	}
+}
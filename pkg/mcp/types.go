@@ -4,6 +4,8 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"math"
+	"regexp"
 
 	"github.com/aixgo-dev/aixgo/pkg/security"
 )
@@ -170,6 +172,56 @@ func (a Args) ValidatedFloat(key string, validator security.ArgValidator) (float
 	return 0, fmt.Errorf("arg %s: expected number, got %T", key, val)
 }
 
+// ParseInputSchema converts a JSON Schema object describing a tool's
+// arguments, e.g. {"type": "object", "properties": {...}, "required": [...]},
+// into a Schema
+func ParseInputSchema(raw json.RawMessage) (Schema, error) {
+	var doc struct {
+		Type       string                 `json:"type"`
+		Properties map[string]SchemaField `json:"properties"`
+		Required   []string               `json:"required"`
+	}
+	if err := json.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parse input schema: %w", err)
+	}
+	if doc.Type != "" && doc.Type != "object" {
+		return nil, fmt.Errorf("input schema type must be object, got %s", doc.Type)
+	}
+
+	schema := make(Schema, len(doc.Properties))
+	for name, field := range doc.Properties {
+		if field.Pattern != "" {
+			if _, err := regexp.Compile(field.Pattern); err != nil {
+				return nil, fmt.Errorf("input schema property %s: invalid pattern: %w", name, err)
+			}
+		}
+		schema[name] = field
+	}
+	for _, name := range doc.Required {
+		field, ok := schema[name]
+		if !ok {
+			return nil, fmt.Errorf("input schema requires undeclared property %s", name)
+		}
+		field.Required = true
+		schema[name] = field
+	}
+	return schema, nil
+}
+
+// ArgumentError reports a tool argument that failed schema validation
+type ArgumentError struct {
+	Field   string
+	Message string
+}
+
+func (e *ArgumentError) Error() string {
+	return fmt.Sprintf("field %s: %s", e.Field, e.Message)
+}
+
+func argumentError(field, format string, args ...any) error {
+	return &ArgumentError{Field: field, Message: fmt.Sprintf(format, args...)}
+}
+
 // ValidateSchema validates arguments against the tool's schema
 func (s Schema) ValidateArgs(args Args) error {
 	for fieldName, field := range s {
@@ -177,7 +229,7 @@ func (s Schema) ValidateArgs(args Args) error {
 
 		// Check required fields
 		if field.Required && !exists {
-			return fmt.Errorf("missing required field: %s", fieldName)
+			return &ArgumentError{Field: fieldName, Message: "missing required field"}
 		}
 
 		if !exists {
@@ -199,16 +251,23 @@ func validateFieldType(fieldName string, val any, field SchemaField) error {
 	case "string":
 		str, ok := val.(string)
 		if !ok {
-			return fmt.Errorf("field %s: expected string, got %T", fieldName, val)
+			return argumentError(fieldName, "expected string, got %T", val)
 		}
 
 		// Validate string constraints
 		if field.MinLength > 0 && len(str) < field.MinLength {
-			return fmt.Errorf("field %s: string too short (min %d)", fieldName, field.MinLength)
+			return argumentError(fieldName, "string too short (min %d)", field.MinLength)
 		}
 
 		if field.MaxLength > 0 && len(str) > field.MaxLength {
-			return fmt.Errorf("field %s: string too long (max %d)", fieldName, field.MaxLength)
+			return argumentError(fieldName, "string too long (max %d)", field.MaxLength)
+		}
+
+		if field.Pattern != "" {
+			matched, err := regexp.MatchString(field.Pattern, str)
+			if err != nil || !matched {
+				return &ArgumentError{Field: fieldName, Message: "value does not match pattern"}
+			}
 		}
 
 		// Validate enum
@@ -221,7 +280,7 @@ func validateFieldType(fieldName string, val any, field SchemaField) error {
 				}
 			}
 			if !found {
-				return fmt.Errorf("field %s: value not in allowed list", fieldName)
+				return &ArgumentError{Field: fieldName, Message: "value not in allowed list"}
 			}
 		}
 
@@ -234,27 +293,37 @@ func validateFieldType(fieldName string, val any, field SchemaField) error {
 			numVal = float64(v)
 		case int64:
 			numVal = float64(v)
+		case json.Number:
+			f, err := v.Float64()
+			if err != nil {
+				return argumentError(fieldName, "expected %s, got %q", field.Type, v)
+			}
+			numVal = f
 		default:
-			return fmt.Errorf("field %s: expected number, got %T", fieldName, val)
+			return argumentError(fieldName, "expected %s, got %T", field.Type, val)
+		}
+
+		if field.Type == "integer" && numVal != math.Trunc(numVal) {
+			return argumentError(fieldName, "expected integer, got %v", numVal)
 		}
 
 		// Validate numeric constraints
 		if field.Minimum != nil && numVal < *field.Minimum {
-			return fmt.Errorf("field %s: value %f below minimum %f", fieldName, numVal, *field.Minimum)
+			return argumentError(fieldName, "value %f below minimum %f", numVal, *field.Minimum)
 		}
 
 		if field.Maximum != nil && numVal > *field.Maximum {
-			return fmt.Errorf("field %s: value %f above maximum %f", fieldName, numVal, *field.Maximum)
+			return argumentError(fieldName, "value %f above maximum %f", numVal, *field.Maximum)
 		}
 
 	case "boolean":
 		if _, ok := val.(bool); !ok {
-			return fmt.Errorf("field %s: expected boolean, got %T", fieldName, val)
+			return argumentError(fieldName, "expected boolean, got %T", val)
 		}
 
 	case "object":
 		if _, ok := val.(map[string]any); !ok {
-			return fmt.Errorf("field %s: expected object, got %T", fieldName, val)
+			return argumentError(fieldName, "expected object, got %T", val)
 		}
 
 	case "array":
@@ -263,7 +332,7 @@ func validateFieldType(fieldName string, val any, field SchemaField) error {
 		case []any:
 			// Valid array type
 		default:
-			return fmt.Errorf("field %s: expected array, got %T", fieldName, val)
+			return argumentError(fieldName, "expected array, got %T", val)
 		}
 	}
 
@@ -278,8 +347,16 @@ type CallToolParams struct {
 
 // CallToolResult represents the result of a tool call
 type CallToolResult struct {
-	Content []Content `json:"content"`
-	IsError bool      `json:"isError,omitempty"`
+	Content []Content  `json:"content"`
+	IsError bool       `json:"isError,omitempty"`
+	Error   *ToolError `json:"error,omitempty"` // Set when IsError is true
+}
+
+// ToolError describes why a tool call failed
+type ToolError struct {
+	Code    security.ErrorCode `json:"code"`
+	Message string             `json:"message"`
+	Field   string             `json:"field,omitempty"` // Offending argument for validation errors
 }
 
 // Content represents tool result content
@@ -105,6 +105,42 @@ func (sm *SemanticMemory) Retrieve(embedding []float64, limit int) ([]Memory, er
 	return results, nil
 }
 
+// Remove deletes the memory with the given ID and reports whether it existed
+func (sm *SemanticMemory) Remove(id string) bool {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	for i, mem := range sm.memories {
+		if mem.ID == id {
+			// Keep insertion order so capacity eviction still drops the oldest
+			sm.memories = append(sm.memories[:i], sm.memories[i+1:]...)
+			return true
+		}
+	}
+	return false
+}
+
+// PruneOlderThan removes memories stored more than d ago and returns the
+// number removed
+func (sm *SemanticMemory) PruneOlderThan(d time.Duration) int {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	cutoff := time.Now().Add(-d)
+	kept := sm.memories[:0]
+	for _, mem := range sm.memories {
+		if !mem.Timestamp.Before(cutoff) {
+			kept = append(kept, mem)
+		}
+	}
+
+	pruned := len(sm.memories) - len(kept)
+	// Clear the tail so pruned embeddings can be garbage collected
+	clear(sm.memories[len(kept):])
+	sm.memories = kept
+	return pruned
+}
+
 // Clear removes all memories
 func (sm *SemanticMemory) Clear() {
 	sm.mu.Lock()
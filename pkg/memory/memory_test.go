@@ -0,0 +1,85 @@
+package memory
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSemanticMemoryRemove(t *testing.T) {
+	sm := NewSemanticMemory(Config{SimilarityThreshold: 0.5})
+
+	if err := sm.Store("north", []float64{1, 0}, nil); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+	if err := sm.Store("north-east", []float64{1, 0.5}, nil); err != nil {
+		t.Fatalf("Store() error = %v", err)
+	}
+
+	results, err := sm.Retrieve([]float64{1, 0}, 2)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(results) != 2 || results[0].Content != "north" {
+		t.Fatalf("Retrieve() = %v, want north first", results)
+	}
+
+	// Remove the most similar memory
+	if !sm.Remove(results[0].ID) {
+		t.Fatal("Remove() = false for a stored memory")
+	}
+	if sm.Remove(results[0].ID) {
+		t.Error("Remove() = true for an already removed memory")
+	}
+	if sm.Count() != 1 {
+		t.Errorf("Count() = %d, want 1", sm.Count())
+	}
+
+	results, err = sm.Retrieve([]float64{1, 0}, 2)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Content != "north-east" {
+		t.Errorf("Retrieve() after Remove = %v, want only north-east", results)
+	}
+}
+
+func TestSemanticMemoryPruneOlderThan(t *testing.T) {
+	sm := NewSemanticMemory(Config{MaxMemories: 3})
+
+	for _, content := range []string{"a", "b", "c"} {
+		if err := sm.Store(content, []float64{1, 0}, nil); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+
+	// Age the first two memories
+	sm.mu.Lock()
+	sm.memories[0].Timestamp = time.Now().Add(-2 * time.Hour)
+	sm.memories[1].Timestamp = time.Now().Add(-2 * time.Hour)
+	sm.mu.Unlock()
+
+	if pruned := sm.PruneOlderThan(time.Hour); pruned != 2 {
+		t.Errorf("PruneOlderThan() = %d, want 2", pruned)
+	}
+	if pruned := sm.PruneOlderThan(time.Hour); pruned != 0 {
+		t.Errorf("second PruneOlderThan() = %d, want 0", pruned)
+	}
+
+	results, err := sm.Retrieve([]float64{1, 0}, 10)
+	if err != nil {
+		t.Fatalf("Retrieve() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Content != "c" {
+		t.Errorf("Retrieve() after prune = %v, want only c", results)
+	}
+
+	// Capacity eviction still works after pruning
+	for _, content := range []string{"d", "e", "f"} {
+		if err := sm.Store(content, []float64{1, 0}, nil); err != nil {
+			t.Fatalf("Store() error = %v", err)
+		}
+	}
+	if sm.Count() != 3 {
+		t.Errorf("Count() = %d, want 3", sm.Count())
+	}
+}
@@ -3,10 +3,13 @@ package observability
 import (
 	"context"
 	"encoding/json"
+	"fmt"
 	"net/http"
 	"runtime"
 	"sync"
 	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 )
 
 // HealthStatus represents the health status of the service
@@ -24,6 +27,7 @@ type HealthCheck struct {
 	CheckFunc   func(context.Context) error
 	Timeout     time.Duration
 	Critical    bool
+	CacheTTL    time.Duration // reuse the last result for this long; 0 disables
 	lastChecked time.Time
 	lastStatus  error
 	mu          sync.RWMutex
@@ -134,8 +138,18 @@ func (hc *HealthChecker) Check(ctx context.Context) HealthResponse {
 	}
 }
 
-// performCheck performs a single health check
+// performCheck performs a single health check, serving the last result
+// instead while it is younger than the check's CacheTTL
 func (hc *HealthChecker) performCheck(ctx context.Context, check *HealthCheck) CheckStatus {
+	if check.CacheTTL > 0 {
+		check.mu.RLock()
+		lastChecked, lastErr := check.lastChecked, check.lastStatus
+		check.mu.RUnlock()
+		if !lastChecked.IsZero() && time.Since(lastChecked) < check.CacheTTL {
+			return newCheckStatus(check, lastChecked, lastErr, "")
+		}
+	}
+
 	start := time.Now()
 
 	checkCtx, cancel := context.WithTimeout(ctx, check.Timeout)
@@ -158,11 +172,17 @@ func (hc *HealthChecker) performCheck(ctx context.Context, check *HealthCheck) C
 	check.mu.Lock()
 	check.lastChecked = time.Now()
 	check.lastStatus = err
+	lastChecked := check.lastChecked
 	check.mu.Unlock()
 
+	return newCheckStatus(check, lastChecked, err, duration.String())
+}
+
+// newCheckStatus converts a check outcome into its reported status
+func newCheckStatus(check *HealthCheck, lastChecked time.Time, err error, duration string) CheckStatus {
 	status := CheckStatus{
-		LastChecked: check.lastChecked,
-		Duration:    duration.String(),
+		LastChecked: lastChecked,
+		Duration:    duration,
 	}
 
 	if err != nil {
@@ -282,3 +302,27 @@ func ExternalServiceCheck(name string, checkFunc func(context.Context) error) *H
 		Critical:  false,
 	}
 }
+
+// ProviderCheck creates a health check that probes an LLM provider with a
+// minimal one-token completion against model (empty uses the provider's
+// default). Results are cached briefly so frequent probes don't turn into a
+// stream of billed requests.
+func ProviderCheck(p provider.Provider, model string) *HealthCheck {
+	return &HealthCheck{
+		Name: "provider:" + p.Name(),
+		CheckFunc: func(ctx context.Context) error {
+			_, err := p.CreateCompletion(ctx, provider.CompletionRequest{
+				Model:     model,
+				Messages:  []provider.Message{{Role: "user", Content: "ping"}},
+				MaxTokens: 1,
+			})
+			if err != nil {
+				return fmt.Errorf("provider %s unreachable: %w", p.Name(), err)
+			}
+			return nil
+		},
+		Timeout:  5 * time.Second,
+		Critical: true,
+		CacheTTL: 10 * time.Second,
+	}
+}
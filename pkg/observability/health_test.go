@@ -0,0 +1,40 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+func TestProviderCheck(t *testing.T) {
+	healthy := provider.NewMockProvider("healthy")
+	down := provider.NewMockProvider("down")
+	down.Errors = []error{errors.New("connection refused")}
+
+	hc := &HealthChecker{checks: make(map[string]*HealthCheck)}
+	hc.RegisterCheck(ProviderCheck(healthy, "llama3.2"))
+	hc.RegisterCheck(ProviderCheck(down, ""))
+
+	resp := hc.Check(context.Background())
+	if resp.Status != HealthStatusUnhealthy {
+		t.Errorf("Check() status = %s, want unhealthy", resp.Status)
+	}
+	if got := resp.Checks["provider:healthy"].Status; got != HealthStatusHealthy {
+		t.Errorf("provider:healthy status = %s, want healthy", got)
+	}
+	if got := resp.Checks["provider:down"]; got.Status != HealthStatusUnhealthy || got.Message == "" {
+		t.Errorf("provider:down = %+v, want unhealthy with a message", got)
+	}
+	if req := healthy.CompletionCalls[0]; req.MaxTokens != 1 || req.Model != "llama3.2" {
+		t.Errorf("probe MaxTokens = %d, Model = %q, want 1 token from llama3.2", req.MaxTokens, req.Model)
+	}
+
+	// The down provider would now succeed, but the cached failure is served
+	resp = hc.Check(context.Background())
+	if len(healthy.CompletionCalls) != 1 || resp.Checks["provider:down"].Status != HealthStatusUnhealthy {
+		t.Errorf("cached results should be reused: %d probes, down = %s",
+			len(healthy.CompletionCalls), resp.Checks["provider:down"].Status)
+	}
+}
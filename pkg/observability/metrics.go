@@ -82,6 +82,102 @@ var (
 		[]string{"agent"},
 	)
 
+	agentCallsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_agent_calls_total",
+			Help: "Total number of agent calls",
+		},
+		[]string{"agent", "status"},
+	)
+
+	agentTokensTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_agent_tokens_total",
+			Help: "Total LLM tokens consumed by agent",
+		},
+		[]string{"agent"},
+	)
+
+	agentCostUSDTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_agent_cost_usd_total",
+			Help: "Total LLM cost in USD by agent",
+		},
+		[]string{"agent"},
+	)
+
+	// Token buckets span 16 to 128k tokens so long-context outliers land in
+	// their own buckets instead of the +Inf overflow
+	agentPromptTokens = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aixgo_agent_prompt_tokens",
+			Help:    "Prompt tokens per LLM call by agent",
+			Buckets: prometheus.ExponentialBuckets(16, 2, 14),
+		},
+		[]string{"agent"},
+	)
+
+	agentCompletionTokens = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aixgo_agent_completion_tokens",
+			Help:    "Completion tokens per LLM call by agent",
+			Buckets: prometheus.ExponentialBuckets(16, 2, 14),
+		},
+		[]string{"agent"},
+	)
+
+	// Vector store metrics
+	vectorStoreQueriesTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_vectorstore_queries_total",
+			Help: "Total number of vector store queries",
+		},
+		[]string{"provider", "collection"},
+	)
+
+	vectorStoreQueryHitsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_vectorstore_query_hits_total",
+			Help: "Total number of vector store queries that returned at least one match",
+		},
+		[]string{"provider", "collection"},
+	)
+
+	vectorStoreQueryDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aixgo_vectorstore_query_duration_seconds",
+			Help:    "Vector store query duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"provider", "collection"},
+	)
+
+	vectorStoreQueryResults = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aixgo_vectorstore_query_results",
+			Help:    "Matches returned per vector store query",
+			Buckets: prometheus.ExponentialBuckets(1, 2, 10),
+		},
+		[]string{"provider", "collection"},
+	)
+
+	vectorStoreUpsertedDocumentsTotal = prometheus.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "aixgo_vectorstore_upserted_documents_total",
+			Help: "Total number of documents inserted or updated in the vector store",
+		},
+		[]string{"provider", "collection"},
+	)
+
+	vectorStoreUpsertDuration = prometheus.NewHistogramVec(
+		prometheus.HistogramOpts{
+			Name:    "aixgo_vectorstore_upsert_duration_seconds",
+			Help:    "Vector store upsert duration in seconds",
+			Buckets: prometheus.DefBuckets,
+		},
+		[]string{"provider", "collection"},
+	)
+
 	// System metrics
 	activeConnections = prometheus.NewGauge(
 		prometheus.GaugeOpts{
@@ -119,6 +215,17 @@ func InitMetrics() {
 			grpcRequestDuration,
 			agentMessagesTotal,
 			agentExecutionDuration,
+			agentCallsTotal,
+			agentTokensTotal,
+			agentCostUSDTotal,
+			agentPromptTokens,
+			agentCompletionTokens,
+			vectorStoreQueriesTotal,
+			vectorStoreQueryHitsTotal,
+			vectorStoreQueryDuration,
+			vectorStoreQueryResults,
+			vectorStoreUpsertedDocumentsTotal,
+			vectorStoreUpsertDuration,
 			activeConnections,
 			memoryUsage,
 			goroutines,
@@ -159,6 +266,74 @@ func RecordAgentExecution(agent string, duration time.Duration) {
 	agentExecutionDuration.WithLabelValues(agent).Observe(duration.Seconds())
 }
 
+// RecordAgentCall records one completed agent call: its outcome, latency,
+// and the tokens and cost it consumed. A non-nil err counts as an error.
+func RecordAgentCall(name string, dur time.Duration, tokens int, costUSD float64, err error) {
+	status := "success"
+	if err != nil {
+		status = "error"
+	}
+	agentCallsTotal.WithLabelValues(name, status).Inc()
+	agentExecutionDuration.WithLabelValues(name).Observe(dur.Seconds())
+	if tokens > 0 {
+		agentTokensTotal.WithLabelValues(name).Add(float64(tokens))
+	}
+	if costUSD > 0 {
+		agentCostUSDTotal.WithLabelValues(name).Add(costUSD)
+	}
+}
+
+// RecordAgentTokens records the prompt and completion token counts of one
+// LLM call made by agent
+func RecordAgentTokens(agent string, promptTokens, completionTokens int) {
+	agentPromptTokens.WithLabelValues(agent).Observe(float64(promptTokens))
+	agentCompletionTokens.WithLabelValues(agent).Observe(float64(completionTokens))
+}
+
+// RecordVectorStoreQuery records one vector store query and the number of
+// matches it returned. collection may be empty to aggregate by provider.
+func RecordVectorStoreQuery(provider, collection string, duration time.Duration, results int) {
+	vectorStoreQueriesTotal.WithLabelValues(provider, collection).Inc()
+	if results > 0 {
+		vectorStoreQueryHitsTotal.WithLabelValues(provider, collection).Inc()
+	}
+	vectorStoreQueryDuration.WithLabelValues(provider, collection).Observe(duration.Seconds())
+	vectorStoreQueryResults.WithLabelValues(provider, collection).Observe(float64(results))
+}
+
+// RecordVectorStoreUpsert records one vector store upsert of documents
+// documents
+func RecordVectorStoreUpsert(provider, collection string, documents int, duration time.Duration) {
+	vectorStoreUpsertedDocumentsTotal.WithLabelValues(provider, collection).Add(float64(documents))
+	vectorStoreUpsertDuration.WithLabelValues(provider, collection).Observe(duration.Seconds())
+}
+
+// VectorStoreMetrics exports vector store collection metrics to Prometheus.
+// It implements vectorstore.MetricsObserver; install it with
+// vectorstore.SetMetricsObserver. Series are labelled by provider, with an
+// empty collection label unless PerCollection is set, since collections
+// created per tenant or session would give unbounded cardinality.
+type VectorStoreMetrics struct {
+	PerCollection bool
+}
+
+// ObserveQuery records a query with RecordVectorStoreQuery
+func (m VectorStoreMetrics) ObserveQuery(provider, collection string, latency time.Duration, results int) {
+	RecordVectorStoreQuery(provider, m.collection(collection), latency, results)
+}
+
+// ObserveUpsert records an upsert with RecordVectorStoreUpsert
+func (m VectorStoreMetrics) ObserveUpsert(provider, collection string, documents int, latency time.Duration) {
+	RecordVectorStoreUpsert(provider, m.collection(collection), documents, latency)
+}
+
+func (m VectorStoreMetrics) collection(name string) string {
+	if m.PerCollection {
+		return name
+	}
+	return ""
+}
+
 // SetActiveConnections sets the active connections gauge
 func SetActiveConnections(count int) {
 	activeConnections.Set(float64(count))
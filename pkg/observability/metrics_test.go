@@ -0,0 +1,65 @@
+package observability
+
+import (
+	"errors"
+	"io"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRecordAgentCallScrape(t *testing.T) {
+	InitMetrics()
+
+	RecordAgentCall("scrape-agent", 120*time.Millisecond, 150, 0.0025, nil)
+	RecordAgentCall("scrape-agent", 80*time.Millisecond, 50, 0.0005, nil)
+	RecordAgentCall("scrape-agent", time.Second, 0, 0, errors.New("timeout"))
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`aixgo_agent_calls_total{agent="scrape-agent",status="success"} 2`,
+		`aixgo_agent_calls_total{agent="scrape-agent",status="error"} 1`,
+		`aixgo_agent_tokens_total{agent="scrape-agent"} 200`,
+		`aixgo_agent_cost_usd_total{agent="scrape-agent"} 0.003`,
+		`aixgo_agent_execution_duration_seconds_count{agent="scrape-agent"} 3`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("scraped metrics missing %q", want)
+		}
+	}
+}
+
+func TestVectorStoreMetricsScrape(t *testing.T) {
+	InitMetrics()
+
+	// Collections share one series unless per-collection labels are enabled
+	for _, name := range []string{"tenant-a", "tenant-b"} {
+		VectorStoreMetrics{}.ObserveQuery("scrape", name, time.Millisecond, 3)
+		VectorStoreMetrics{}.ObserveUpsert("scrape", name, 2, time.Millisecond)
+	}
+	perCollection := VectorStoreMetrics{PerCollection: true}
+	perCollection.ObserveQuery("scrape", "shared-kb", time.Millisecond, 0)
+
+	rec := httptest.NewRecorder()
+	MetricsHandler().ServeHTTP(rec, httptest.NewRequest("GET", "/metrics", nil))
+	body, _ := io.ReadAll(rec.Body)
+
+	for _, want := range []string{
+		`aixgo_vectorstore_queries_total{collection="",provider="scrape"} 2`,
+		`aixgo_vectorstore_query_hits_total{collection="",provider="scrape"} 2`,
+		`aixgo_vectorstore_query_duration_seconds_count{collection="",provider="scrape"} 2`,
+		`aixgo_vectorstore_upserted_documents_total{collection="",provider="scrape"} 4`,
+		`aixgo_vectorstore_queries_total{collection="shared-kb",provider="scrape"} 1`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("scraped metrics missing %q", want)
+		}
+	}
+	if strings.Contains(string(body), "tenant-a") {
+		t.Error("collection names were exported without PerCollection")
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -36,7 +37,8 @@ func validatePathComponent(s string) error {
 //	      ├── sessions.json          # Session index
 //	      ├── <session-id>.jsonl     # Session entries
 //	      └── checkpoints/
-//	          └── <checkpoint-id>.json
+//	          ├── <checkpoint-id>.json
+//	          └── <session-id>.index  # IDs of the session's checkpoints
 type FileBackend struct {
 	baseDir string
 	mu      sync.RWMutex
@@ -243,8 +245,8 @@ func (f *FileBackend) ListSessions(ctx context.Context, agentName string, opts L
 	// Filter and collect sessions
 	var sessions []*SessionMetadata
 	for _, meta := range index {
-		// Filter by user ID if specified
-		if opts.UserID != "" && meta.UserID != opts.UserID {
+		// Filter by user ID and update time if specified
+		if !opts.matches(meta) {
 			continue
 		}
 		sessions = append(sessions, meta)
@@ -388,6 +390,17 @@ func (f *FileBackend) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint
 		return fmt.Errorf("write checkpoint: %w", err)
 	}
 
+	ids, err := checkpointIDs(checkpointsDir, checkpoint.SessionID)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(ids, checkpoint.ID) {
+		indexPath := filepath.Join(checkpointsDir, checkpoint.SessionID+".index")
+		if err := writeCheckpointIndex(indexPath, append(ids, checkpoint.ID)); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -436,6 +449,190 @@ func (f *FileBackend) LoadCheckpoint(ctx context.Context, checkpointID string) (
 	return nil, ErrCheckpointNotFound
 }
 
+// ListCheckpoints returns a session's checkpoints, oldest first. Only the
+// checkpoints listed in the session's index are read.
+func (f *FileBackend) ListCheckpoints(ctx context.Context, sessionID string) ([]*Checkpoint, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return nil, ErrStorageClosed
+	}
+
+	// Find the session to get the agent name (validates sessionID)
+	meta, err := f.loadSessionUnlocked(sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpointsDir := filepath.Join(f.baseDir, meta.AgentName, "checkpoints")
+	ids, err := checkpointIDs(checkpointsDir, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(ids))
+	for _, id := range ids {
+		data, err := os.ReadFile(filepath.Join(checkpointsDir, id+".json")) // #nosec G304 - IDs were validated when saved
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("read checkpoint: %w", err)
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			continue
+		}
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// scanCheckpoints reads every checkpoint file in dir and returns those of
+// sessionID, oldest first
+func scanCheckpoints(dir, sessionID string) ([]*Checkpoint, error) {
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return []*Checkpoint{}, nil
+		}
+		return nil, fmt.Errorf("read checkpoints directory: %w", err)
+	}
+
+	checkpoints := []*Checkpoint{}
+	for _, file := range files {
+		if file.IsDir() || filepath.Ext(file.Name()) != ".json" {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(dir, file.Name())) // #nosec G304 - names come from the directory listing
+		if err != nil {
+			return nil, fmt.Errorf("read checkpoint: %w", err)
+		}
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil {
+			continue
+		}
+		if checkpoint.SessionID == sessionID {
+			checkpoints = append(checkpoints, &checkpoint)
+		}
+	}
+
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint removes a checkpoint.
+func (f *FileBackend) DeleteCheckpoint(ctx context.Context, checkpointID string) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.closed {
+		return ErrStorageClosed
+	}
+
+	// Validate checkpoint ID to prevent path traversal
+	if err := validatePathComponent(checkpointID); err != nil {
+		return fmt.Errorf("invalid checkpoint ID: %w", err)
+	}
+
+	entries, err := os.ReadDir(f.baseDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return ErrCheckpointNotFound
+		}
+		return fmt.Errorf("read base directory: %w", err)
+	}
+
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+
+		checkpointsDir := filepath.Join(f.baseDir, entry.Name(), "checkpoints")
+		checkpointPath := filepath.Join(checkpointsDir, checkpointID+".json")
+		data, err := os.ReadFile(checkpointPath) // #nosec G304 - path components validated to prevent traversal
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return fmt.Errorf("read checkpoint: %w", err)
+		}
+		if err := os.Remove(checkpointPath); err != nil {
+			return fmt.Errorf("delete checkpoint: %w", err)
+		}
+
+		var checkpoint Checkpoint
+		if err := json.Unmarshal(data, &checkpoint); err != nil || validatePathComponent(checkpoint.SessionID) != nil {
+			return nil
+		}
+		indexPath := filepath.Join(checkpointsDir, checkpoint.SessionID+".index")
+		ids, indexed, err := readCheckpointIndex(indexPath)
+		if err != nil || !indexed {
+			return err
+		}
+		return writeCheckpointIndex(indexPath, slices.DeleteFunc(ids, func(id string) bool { return id == checkpointID }))
+	}
+
+	return ErrCheckpointNotFound
+}
+
+// checkpointIDs returns the IDs in a session's checkpoint index. Sessions
+// checkpointed before the index existed are scanned once to build it.
+func checkpointIDs(dir, sessionID string) ([]string, error) {
+	indexPath := filepath.Join(dir, sessionID+".index")
+	ids, indexed, err := readCheckpointIndex(indexPath)
+	if err != nil || indexed {
+		return ids, err
+	}
+
+	checkpoints, err := scanCheckpoints(dir, sessionID)
+	if err != nil || len(checkpoints) == 0 {
+		return nil, err
+	}
+	ids = make([]string, len(checkpoints))
+	for i, checkpoint := range checkpoints {
+		ids[i] = checkpoint.ID
+	}
+	return ids, writeCheckpointIndex(indexPath, ids)
+}
+
+// readCheckpointIndex returns the checkpoint IDs listed in a session's index
+// and whether the index exists
+func readCheckpointIndex(path string) ([]string, bool, error) {
+	data, err := os.ReadFile(path) // #nosec G304 - path components validated to prevent traversal
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, false, nil
+		}
+		return nil, false, fmt.Errorf("read checkpoint index: %w", err)
+	}
+	var ids []string
+	if err := json.Unmarshal(data, &ids); err != nil {
+		return nil, false, fmt.Errorf("parse checkpoint index: %w", err)
+	}
+	return ids, true, nil
+}
+
+// writeCheckpointIndex replaces a session's index, via a rename so readers
+// never see a partial file
+func writeCheckpointIndex(path string, ids []string) error {
+	data, err := json.Marshal(ids)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint index: %w", err)
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0600); err != nil {
+		return fmt.Errorf("write checkpoint index: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		return fmt.Errorf("write checkpoint index: %w", err)
+	}
+	return nil
+}
+
 // Close releases any resources held by the backend.
 func (f *FileBackend) Close() error {
 	f.mu.Lock()
@@ -0,0 +1,78 @@
+package session
+
+import (
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+// MetadataKeyHistory is the input metadata key CallWithSession stores the
+// session's prior turns under, as a list of {"role", "content"} maps.
+const MetadataKeyHistory = "history"
+
+// MaxHistoryMessages is how many of the most recent session messages
+// WithHistory injects, keeping long sessions from overflowing the prompt.
+const MaxHistoryMessages = 50
+
+// WithHistory returns a copy of input carrying the last MaxHistoryMessages
+// of history as a conversation array under MetadataKeyHistory. The input
+// itself is left unchanged so it can be stored in the session without the
+// history attached.
+func WithHistory(input *agent.Message, history []*agent.Message) *agent.Message {
+	if input == nil {
+		return nil
+	}
+	if len(history) > MaxHistoryMessages {
+		history = history[len(history)-MaxHistoryMessages:]
+	}
+	turns := make([]any, 0, len(history))
+	for _, msg := range history {
+		if msg == nil {
+			continue
+		}
+		turns = append(turns, map[string]any{
+			"role":    historyRole(msg.Type),
+			"content": payloadText(msg),
+		})
+	}
+
+	out := input.Clone()
+	out.Metadata[MetadataKeyHistory] = turns
+	return out
+}
+
+// HistoryFromMessage returns the conversation injected by CallWithSession
+// as provider messages, or nil if msg carries no history.
+func HistoryFromMessage(msg *agent.Message) []provider.Message {
+	if msg == nil {
+		return nil
+	}
+	turns, _ := msg.Metadata[MetadataKeyHistory].([]any)
+	if len(turns) == 0 {
+		return nil
+	}
+	messages := make([]provider.Message, 0, len(turns))
+	for _, turn := range turns {
+		m, ok := turn.(map[string]any)
+		if !ok {
+			continue
+		}
+		role, _ := m["role"].(string)
+		content, _ := m["content"].(string)
+		messages = append(messages, provider.Message{Role: role, Content: content})
+	}
+	return messages
+}
+
+// historyRole maps a session message type to a chat role. Anything not
+// known to come from the caller is an agent's output, so it is never
+// replayed as user instructions.
+func historyRole(msgType string) string {
+	switch msgType {
+	case "user", "human", "request", "query", "input":
+		return "user"
+	case "system", MessageTypeSummary:
+		return "system"
+	default:
+		return "assistant"
+	}
+}
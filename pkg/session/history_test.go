@@ -0,0 +1,44 @@
+package session
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/agent"
+)
+
+func TestWithHistoryRoles(t *testing.T) {
+	history := []*agent.Message{
+		agent.NewMessage("user", "classify this"),
+		agent.NewMessage("classification", "billing"),
+		agent.NewMessage(MessageTypeSummary, "earlier turns"),
+		agent.NewMessage("request", "and this"),
+		agent.NewMessage("aggregation_result", "done"),
+	}
+
+	got := HistoryFromMessage(WithHistory(agent.NewMessage("user", "next"), history))
+	want := []string{"user", "assistant", "system", "user", "assistant"}
+	if len(got) != len(want) {
+		t.Fatalf("HistoryFromMessage() returned %d turns, want %d", len(got), len(want))
+	}
+	for i, role := range want {
+		if got[i].Role != role {
+			t.Errorf("turn %d (%s) role = %q, want %q", i, history[i].Type, got[i].Role, role)
+		}
+	}
+}
+
+func TestWithHistoryCap(t *testing.T) {
+	history := make([]*agent.Message, MaxHistoryMessages+10)
+	for i := range history {
+		history[i] = agent.NewMessage("user", fmt.Sprintf("turn %d", i))
+	}
+
+	got := HistoryFromMessage(WithHistory(agent.NewMessage("user", "next"), history))
+	if len(got) != MaxHistoryMessages {
+		t.Fatalf("HistoryFromMessage() returned %d turns, want %d", len(got), MaxHistoryMessages)
+	}
+	if got[0].Content != "turn 10" {
+		t.Errorf("oldest injected turn = %q, want turn 10", got[0].Content)
+	}
+}
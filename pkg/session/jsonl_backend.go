@@ -0,0 +1,404 @@
+package session
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+)
+
+// Record kinds written by JSONLBackend
+const (
+	jsonlRecordSession          = "session"
+	jsonlRecordEntry            = "entry"
+	jsonlRecordCheckpoint       = "checkpoint"
+	jsonlRecordDelete           = "delete"
+	jsonlRecordDeleteCheckpoint = "delete-checkpoint"
+)
+
+// jsonlRecord is one line of a JSONL session log
+type jsonlRecord struct {
+	Kind       string           `json:"kind"`
+	SessionID  string           `json:"sessionId,omitempty"`
+	Session    *SessionMetadata `json:"session,omitempty"`
+	Entry      *SessionEntry    `json:"entry,omitempty"`
+	Checkpoint *Checkpoint      `json:"checkpoint,omitempty"`
+	// CheckpointID names the checkpoint a delete-checkpoint record removes.
+	CheckpointID string `json:"checkpointId,omitempty"`
+	// Offset is the number of session entries a checkpoint covers.
+	Offset int `json:"offset,omitempty"`
+}
+
+// JSONLBackend implements StorageBackend as a single append-only JSON Lines
+// file, suited to human-readable audit logs. Every operation appends one
+// line; nothing is ever rewritten. Session metadata updates, entries,
+// checkpoints (with the entry offset they cover) and deletions are all
+// recorded, and the file is replayed on open to rebuild session state.
+// Since entries are never truncated, LoadEntries returns the branch ending
+// at the session's CurrentLeaf, so a restored checkpoint survives reopening.
+//
+// Unlike FileBackend, there is no index or per-session layout: use one
+// JSONLBackend per session to keep one log file per session.
+type JSONLBackend struct {
+	path        string
+	file        *os.File
+	sessions    map[string]*SessionMetadata
+	entries     map[string][]*SessionEntry
+	checkpoints map[string]*Checkpoint
+	mu          sync.RWMutex
+	closed      bool
+}
+
+// NewJSONLBackend opens or creates the log file at path and replays it.
+func NewJSONLBackend(path string) (*JSONLBackend, error) {
+	if path == "" {
+		return nil, fmt.Errorf("jsonl backend path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create log directory: %w", err)
+	}
+
+	j := &JSONLBackend{
+		path:        path,
+		sessions:    make(map[string]*SessionMetadata),
+		entries:     make(map[string][]*SessionEntry),
+		checkpoints: make(map[string]*Checkpoint),
+	}
+	tail, err := j.replay()
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600) // #nosec G304 - path is chosen by the caller
+	if err != nil {
+		return nil, fmt.Errorf("open session log: %w", err)
+	}
+	if err := tail.repair(file); err != nil {
+		_ = file.Close()
+		return nil, err
+	}
+	j.file = file
+	return j, nil
+}
+
+// jsonlTail describes how the last line of a log file ended
+type jsonlTail struct {
+	// tornAt is the offset of a final line that was cut short by a crash,
+	// or -1 if there is none
+	tornAt int64
+	// unterminated is set when the final record parsed but lacks its newline
+	unterminated bool
+}
+
+// repair truncates a torn final line and terminates an unterminated one so
+// the next record starts on a line of its own
+func (t jsonlTail) repair(file *os.File) error {
+	if t.tornAt >= 0 {
+		if err := file.Truncate(t.tornAt); err != nil {
+			return fmt.Errorf("truncate torn session log line: %w", err)
+		}
+	}
+	if t.unterminated {
+		if _, err := file.Write([]byte{'\n'}); err != nil {
+			return fmt.Errorf("terminate session log line: %w", err)
+		}
+	}
+	return nil
+}
+
+// replay rebuilds in-memory state from the log file. A final line without
+// a newline that does not parse is a write torn by a crash: it is skipped
+// and reported in the returned tail so it can be truncated. Unparseable
+// lines anywhere else are an error.
+func (j *JSONLBackend) replay() (jsonlTail, error) {
+	tail := jsonlTail{tornAt: -1}
+	file, err := os.Open(j.path) // #nosec G304 - path is chosen by the caller
+	if err != nil {
+		if os.IsNotExist(err) {
+			return tail, nil
+		}
+		return tail, fmt.Errorf("open session log: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	reader := bufio.NewReader(file)
+	var offset int64
+	for line := 1; ; line++ {
+		data, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return tail, fmt.Errorf("read session log: %w", readErr)
+		}
+		terminated := readErr == nil
+		if record := bytes.TrimSpace(data); len(record) > 0 {
+			var rec jsonlRecord
+			if err := json.Unmarshal(record, &rec); err != nil {
+				if terminated {
+					return tail, fmt.Errorf("parse session log line %d: %w", line, err)
+				}
+				tail.tornAt = offset
+				return tail, nil
+			}
+			j.apply(&rec)
+			tail.unterminated = !terminated
+		}
+		if !terminated {
+			return tail, nil
+		}
+		offset += int64(len(data))
+	}
+}
+
+// apply updates in-memory state with one record
+func (j *JSONLBackend) apply(rec *jsonlRecord) {
+	switch rec.Kind {
+	case jsonlRecordSession:
+		if rec.Session != nil {
+			j.sessions[rec.Session.ID] = rec.Session
+		}
+	case jsonlRecordEntry:
+		if rec.Entry != nil {
+			j.entries[rec.SessionID] = append(j.entries[rec.SessionID], rec.Entry)
+		}
+	case jsonlRecordCheckpoint:
+		if rec.Checkpoint != nil {
+			j.checkpoints[rec.Checkpoint.ID] = rec.Checkpoint
+		}
+	case jsonlRecordDeleteCheckpoint:
+		delete(j.checkpoints, rec.CheckpointID)
+	case jsonlRecordDelete:
+		delete(j.sessions, rec.SessionID)
+		delete(j.entries, rec.SessionID)
+		for id, cp := range j.checkpoints {
+			if cp.SessionID == rec.SessionID {
+				delete(j.checkpoints, id)
+			}
+		}
+	}
+}
+
+// write appends a record to the log and applies it. Caller must hold the
+// write lock.
+func (j *JSONLBackend) write(rec *jsonlRecord) error {
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return fmt.Errorf("marshal %s record: %w", rec.Kind, err)
+	}
+	if _, err := j.file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("write %s record: %w", rec.Kind, err)
+	}
+	j.apply(rec)
+	return nil
+}
+
+// SaveSession creates or updates session metadata.
+func (j *JSONLBackend) SaveSession(ctx context.Context, meta *SessionMetadata) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrStorageClosed
+	}
+
+	cp := *meta
+	return j.write(&jsonlRecord{Kind: jsonlRecordSession, Session: &cp})
+}
+
+// LoadSession retrieves session metadata by ID.
+func (j *JSONLBackend) LoadSession(ctx context.Context, sessionID string) (*SessionMetadata, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.closed {
+		return nil, ErrStorageClosed
+	}
+
+	meta, ok := j.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+	cp := *meta
+	return &cp, nil
+}
+
+// DeleteSession records the deletion of a session. Earlier lines stay in
+// the log but are ignored on replay.
+func (j *JSONLBackend) DeleteSession(ctx context.Context, sessionID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrStorageClosed
+	}
+	if _, ok := j.sessions[sessionID]; !ok {
+		return ErrSessionNotFound
+	}
+
+	return j.write(&jsonlRecord{Kind: jsonlRecordDelete, SessionID: sessionID})
+}
+
+// ListSessions returns sessions for an agent matching the filter options.
+func (j *JSONLBackend) ListSessions(ctx context.Context, agentName string, opts ListOptions) ([]*SessionMetadata, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.closed {
+		return nil, ErrStorageClosed
+	}
+
+	sessions := []*SessionMetadata{}
+	for _, meta := range j.sessions {
+		if meta.AgentName != agentName || !opts.matches(meta) {
+			continue
+		}
+		cp := *meta
+		sessions = append(sessions, &cp)
+	}
+
+	// Sort by updated time (most recent first)
+	sort.Slice(sessions, func(a, b int) bool {
+		return sessions[a].UpdatedAt.After(sessions[b].UpdatedAt)
+	})
+
+	if opts.Offset > 0 {
+		if opts.Offset >= len(sessions) {
+			return []*SessionMetadata{}, nil
+		}
+		sessions = sessions[opts.Offset:]
+	}
+	if opts.Limit > 0 && opts.Limit < len(sessions) {
+		sessions = sessions[:opts.Limit]
+	}
+
+	return sessions, nil
+}
+
+// AppendEntry adds an entry to a session (append-only).
+func (j *JSONLBackend) AppendEntry(ctx context.Context, sessionID string, entry *SessionEntry) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrStorageClosed
+	}
+	if _, ok := j.sessions[sessionID]; !ok {
+		return ErrSessionNotFound
+	}
+
+	return j.write(&jsonlRecord{Kind: jsonlRecordEntry, SessionID: sessionID, Entry: entry})
+}
+
+// LoadEntries retrieves the entries leading to the session's current leaf
+// in order.
+func (j *JSONLBackend) LoadEntries(ctx context.Context, sessionID string) ([]*SessionEntry, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.closed {
+		return nil, ErrStorageClosed
+	}
+	meta, ok := j.sessions[sessionID]
+	if !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	return ActiveBranch(j.entries[sessionID], meta.CurrentLeaf), nil
+}
+
+// SaveCheckpoint records a checkpoint together with the number of session
+// entries written so far.
+func (j *JSONLBackend) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrStorageClosed
+	}
+	if _, ok := j.sessions[checkpoint.SessionID]; !ok {
+		return ErrSessionNotFound
+	}
+
+	return j.write(&jsonlRecord{
+		Kind:       jsonlRecordCheckpoint,
+		SessionID:  checkpoint.SessionID,
+		Checkpoint: checkpoint,
+		Offset:     len(j.entries[checkpoint.SessionID]),
+	})
+}
+
+// LoadCheckpoint retrieves a checkpoint by ID.
+func (j *JSONLBackend) LoadCheckpoint(ctx context.Context, checkpointID string) (*Checkpoint, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.closed {
+		return nil, ErrStorageClosed
+	}
+
+	checkpoint, ok := j.checkpoints[checkpointID]
+	if !ok {
+		return nil, ErrCheckpointNotFound
+	}
+	cp := *checkpoint
+	return &cp, nil
+}
+
+// ListCheckpoints returns a session's checkpoints, oldest first.
+func (j *JSONLBackend) ListCheckpoints(ctx context.Context, sessionID string) ([]*Checkpoint, error) {
+	j.mu.RLock()
+	defer j.mu.RUnlock()
+
+	if j.closed {
+		return nil, ErrStorageClosed
+	}
+	if _, ok := j.sessions[sessionID]; !ok {
+		return nil, ErrSessionNotFound
+	}
+
+	checkpoints := []*Checkpoint{}
+	for _, checkpoint := range j.checkpoints {
+		if checkpoint.SessionID == sessionID {
+			cp := *checkpoint
+			checkpoints = append(checkpoints, &cp)
+		}
+	}
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint records the deletion of a checkpoint.
+func (j *JSONLBackend) DeleteCheckpoint(ctx context.Context, checkpointID string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return ErrStorageClosed
+	}
+	checkpoint, ok := j.checkpoints[checkpointID]
+	if !ok {
+		return ErrCheckpointNotFound
+	}
+
+	return j.write(&jsonlRecord{
+		Kind:         jsonlRecordDeleteCheckpoint,
+		SessionID:    checkpoint.SessionID,
+		CheckpointID: checkpointID,
+	})
+}
+
+// Close closes the log file.
+func (j *JSONLBackend) Close() error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if j.closed {
+		return nil
+	}
+	j.closed = true
+	return j.file.Close()
+}
@@ -0,0 +1,235 @@
+package session
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/agent"
+)
+
+func TestJSONLBackendReplay(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "audit", "session.jsonl")
+
+	backend, err := NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() error = %v", err)
+	}
+	mgr := NewManager(backend)
+
+	sess, err := mgr.Create(ctx, "test-agent", CreateOptions{UserID: "user-123"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, text := range []string{"Hello", "Hi!"} {
+		if err := sess.AppendMessage(ctx, agent.NewMessage("user", text)); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+	checkpoint, err := sess.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "discarded")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	if err := sess.Restore(ctx, checkpoint.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "after restore")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	want := messageTexts(t, sess)
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// The log keeps every write, including the discarded message
+	lines := readJSONLRecords(t, path)
+	var checkpointOffset, entryLines int
+	for _, rec := range lines {
+		switch rec.Kind {
+		case jsonlRecordEntry:
+			entryLines++
+		case jsonlRecordCheckpoint:
+			checkpointOffset = rec.Offset
+		}
+	}
+	if entryLines != 4 {
+		t.Errorf("log has %d entry lines, want 4", entryLines)
+	}
+	if checkpointOffset != 2 {
+		t.Errorf("checkpoint offset = %d, want 2", checkpointOffset)
+	}
+
+	// Reopening replays the log into the same history
+	backend, err = NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() reopen error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr = NewManager(backend)
+
+	reopened, err := mgr.Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got := messageTexts(t, reopened)
+	if len(got) != 3 || len(got) != len(want) {
+		t.Fatalf("reopened history = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reopened history = %v, want %v", got, want)
+		}
+	}
+
+	if _, err := backend.LoadCheckpoint(ctx, checkpoint.ID); err != nil {
+		t.Errorf("LoadCheckpoint() after reopen error = %v", err)
+	}
+	sessions, err := mgr.List(ctx, "test-agent", ListOptions{UserID: "user-123"})
+	if err != nil || len(sessions) != 1 {
+		t.Errorf("List() = %d sessions, %v; want 1", len(sessions), err)
+	}
+
+	// Deletion is recorded and survives replay too
+	if err := mgr.Delete(ctx, sess.ID()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	_ = backend.Close()
+	backend, err = NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() reopen error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	if _, err := backend.LoadSession(ctx, sess.ID()); !errors.Is(err, ErrSessionNotFound) {
+		t.Errorf("LoadSession() after delete error = %v, want ErrSessionNotFound", err)
+	}
+}
+
+func TestJSONLBackendTornLastLine(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "session.jsonl")
+
+	backend, err := NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() error = %v", err)
+	}
+	mgr := NewManager(backend)
+	sess, err := mgr.Create(ctx, "test-agent", CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "kept")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	_ = mgr.Close()
+
+	// Simulate a crash part way through writing the next record
+	appendRaw := func(data string) {
+		t.Helper()
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			t.Fatalf("open log: %v", err)
+		}
+		if _, err := file.WriteString(data); err != nil {
+			t.Fatalf("write log: %v", err)
+		}
+		_ = file.Close()
+	}
+	appendRaw(`{"kind":"entry","sessionId":"` + sess.ID() + `","entry":{"id":`)
+
+	backend, err = NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() with torn last line error = %v", err)
+	}
+	mgr = NewManager(backend)
+	reopened, err := mgr.Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if err := reopened.AppendMessage(ctx, agent.NewMessage("user", "after crash")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	want := messageTexts(t, reopened)
+	_ = mgr.Close()
+
+	// The torn line was truncated, so every remaining line is a record
+	readJSONLRecords(t, path)
+	backend, err = NewJSONLBackend(path)
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() reopen error = %v", err)
+	}
+	mgr = NewManager(backend)
+	reopened, err = mgr.Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got := messageTexts(t, reopened); len(got) != 2 || got[0] != want[0] || got[1] != want[1] {
+		t.Errorf("history after torn write = %v, want %v", got, want)
+	}
+	_ = mgr.Close()
+
+	// A broken line followed by more records is corruption, not a torn write
+	appendRaw("not json\n{}\n")
+	if _, err := NewJSONLBackend(path); err == nil {
+		t.Error("NewJSONLBackend() with a corrupt middle line should fail")
+	}
+}
+
+func TestJSONLBackendClosed(t *testing.T) {
+	backend, err := NewJSONLBackend(filepath.Join(t.TempDir(), "session.jsonl"))
+	if err != nil {
+		t.Fatalf("NewJSONLBackend() error = %v", err)
+	}
+	_ = backend.Close()
+
+	ctx := context.Background()
+	if err := backend.SaveSession(ctx, &SessionMetadata{ID: "s"}); !errors.Is(err, ErrStorageClosed) {
+		t.Errorf("SaveSession() error = %v, want ErrStorageClosed", err)
+	}
+	if _, err := backend.LoadEntries(ctx, "s"); !errors.Is(err, ErrStorageClosed) {
+		t.Errorf("LoadEntries() error = %v, want ErrStorageClosed", err)
+	}
+	if _, err := NewJSONLBackend(""); err == nil {
+		t.Error("NewJSONLBackend(\"\") should fail")
+	}
+}
+
+func messageTexts(t *testing.T, sess Session) []string {
+	t.Helper()
+	messages, err := sess.GetMessages(context.Background())
+	if err != nil {
+		t.Fatalf("GetMessages() error = %v", err)
+	}
+	texts := make([]string, len(messages))
+	for i, msg := range messages {
+		texts[i] = msg.Payload
+	}
+	return texts
+}
+
+func readJSONLRecords(t *testing.T, path string) []jsonlRecord {
+	t.Helper()
+	file, err := os.Open(path)
+	if err != nil {
+		t.Fatalf("open log: %v", err)
+	}
+	defer func() { _ = file.Close() }()
+
+	var records []jsonlRecord
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var rec jsonlRecord
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			t.Fatalf("log line %q is not JSON: %v", scanner.Text(), err)
+		}
+		records = append(records, rec)
+	}
+	return records
+}
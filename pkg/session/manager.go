@@ -39,6 +39,13 @@ type CreateOptions struct {
 	UserID string
 	// Metadata contains optional session metadata.
 	Metadata map[string]any
+	// AutoCheckpointEvery creates a checkpoint automatically after every
+	// N appended messages. Zero disables automatic checkpoints.
+	AutoCheckpointEvery int
+	// MaxCheckpoints is the number of automatic checkpoints to keep; older
+	// ones are deleted as new ones are created. Manual checkpoints are never
+	// pruned. Zero keeps them all.
+	MaxCheckpoints int
 }
 
 // managerImpl is the concrete implementation of Manager.
@@ -67,6 +74,9 @@ func (m *managerImpl) Create(ctx context.Context, agentName string, opts CreateO
 		CreatedAt:    now,
 		UpdatedAt:    now,
 		MessageCount: 0,
+
+		AutoCheckpointEvery: opts.AutoCheckpointEvery,
+		MaxCheckpoints:      opts.MaxCheckpoints,
 	}
 
 	// Persist metadata
@@ -2,12 +2,16 @@ package session
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
 )
 
 func TestNewManager(t *testing.T) {
@@ -836,3 +840,224 @@ func TestPathTraversalPrevention(t *testing.T) {
 		}
 	})
 }
+
+func TestSessionAutoCheckpoint(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	backends := []struct {
+		name string
+		open func(t *testing.T, dir string) StorageBackend
+	}{
+		{"file", func(t *testing.T, dir string) StorageBackend {
+			b, err := NewFileBackend(dir)
+			if err != nil {
+				t.Fatalf("NewFileBackend() error = %v", err)
+			}
+			return b
+		}},
+		{"jsonl", func(t *testing.T, dir string) StorageBackend {
+			b, err := NewJSONLBackend(filepath.Join(dir, "session.jsonl"))
+			if err != nil {
+				t.Fatalf("NewJSONLBackend() error = %v", err)
+			}
+			return b
+		}},
+		{"redis", func(t *testing.T, dir string) StorageBackend {
+			return NewRedisBackendFromClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}), dir+":", 0)
+		}},
+	}
+
+	for _, bt := range backends {
+		t.Run(bt.name, func(t *testing.T) {
+			ctx := context.Background()
+			dir := t.TempDir()
+
+			backend := bt.open(t, dir)
+			sess, err := NewManager(backend).Create(ctx, "assistant", CreateOptions{
+				AutoCheckpointEvery: 2,
+				MaxCheckpoints:      2,
+			})
+			if err != nil {
+				t.Fatalf("Create() error = %v", err)
+			}
+
+			appendN := func(sess Session, n int) {
+				t.Helper()
+				for i := 0; i < n; i++ {
+					if err := sess.AppendMessage(ctx, agent.NewMessage("user", "message")); err != nil {
+						t.Fatalf("AppendMessage() error = %v", err)
+					}
+				}
+			}
+
+			appendN(sess, 1)
+			if cps, _ := sess.ListCheckpoints(ctx); len(cps) != 0 {
+				t.Fatalf("ListCheckpoints() after 1 message = %d, want 0", len(cps))
+			}
+			appendN(sess, 1)
+			cps, err := sess.ListCheckpoints(ctx)
+			if err != nil {
+				t.Fatalf("ListCheckpoints() error = %v", err)
+			}
+			if len(cps) != 1 || !isAutoCheckpoint(cps[0]) {
+				t.Fatalf("ListCheckpoints() after 2 messages = %+v, want 1 auto checkpoint", cps)
+			}
+
+			// Manual checkpoints are kept regardless of MaxCheckpoints
+			manual, err := sess.Checkpoint(ctx)
+			if err != nil {
+				t.Fatalf("Checkpoint() error = %v", err)
+			}
+
+			// Auto checkpoints at messages 4 and 6; the one at 2 is pruned
+			appendN(sess, 4)
+			assertCheckpoints(t, ctx, sess, manual.ID, 2)
+
+			// A reopened session keeps the cadence and pruning settings
+			_ = backend.Close()
+			backend = bt.open(t, dir)
+			defer func() { _ = backend.Close() }()
+			sess, err = NewManager(backend).Get(ctx, sess.ID())
+			if err != nil {
+				t.Fatalf("Get() error = %v", err)
+			}
+			appendN(sess, 1)
+			assertCheckpoints(t, ctx, sess, manual.ID, 2)
+			appendN(sess, 1)
+			cps = assertCheckpoints(t, ctx, sess, manual.ID, 2)
+
+			// The newest auto checkpoint restores the full 8 messages
+			if err := sess.Restore(ctx, cps[len(cps)-1].ID); err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			messages, err := sess.GetMessages(ctx)
+			if err != nil {
+				t.Fatalf("GetMessages() error = %v", err)
+			}
+			if len(messages) != 8 {
+				t.Errorf("GetMessages() after Restore() = %d messages, want 8", len(messages))
+			}
+		})
+	}
+}
+
+// assertCheckpoints checks that sess holds the manual checkpoint plus wantAuto
+// automatic ones, and returns the automatic checkpoints oldest first.
+func assertCheckpoints(t *testing.T, ctx context.Context, sess Session, manualID string, wantAuto int) []*Checkpoint {
+	t.Helper()
+
+	cps, err := sess.ListCheckpoints(ctx)
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error = %v", err)
+	}
+
+	var auto []*Checkpoint
+	foundManual := false
+	for _, cp := range cps {
+		switch {
+		case cp.ID == manualID:
+			foundManual = true
+		case isAutoCheckpoint(cp):
+			auto = append(auto, cp)
+		}
+	}
+	if !foundManual {
+		t.Error("manual checkpoint was pruned")
+	}
+	if len(auto) != wantAuto {
+		t.Errorf("auto checkpoints = %d, want %d", len(auto), wantAuto)
+	}
+	return auto
+}
+
+// failingCheckpointBackend fails every checkpoint write
+type failingCheckpointBackend struct {
+	StorageBackend
+}
+
+func (failingCheckpointBackend) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoint) error {
+	return errors.New("disk full")
+}
+
+func TestSessionAutoCheckpointFailureKeepsMessage(t *testing.T) {
+	ctx := context.Background()
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+
+	sess, err := NewManager(failingCheckpointBackend{backend}).Create(ctx, "assistant", CreateOptions{AutoCheckpointEvery: 1})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "hello")); err != nil {
+		t.Fatalf("AppendMessage() error = %v, want nil when only the checkpoint fails", err)
+	}
+	messages, err := sess.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("GetMessages() error = %v", err)
+	}
+	if len(messages) != 1 {
+		t.Errorf("GetMessages() = %d messages, want 1", len(messages))
+	}
+}
+
+func TestFileBackendCheckpointIndex(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	backend, err := NewFileBackend(dir)
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr := NewManager(backend)
+
+	// Two sessions of one agent share the checkpoints directory
+	var sessions []Session
+	for range 2 {
+		sess, err := mgr.Create(ctx, "assistant", CreateOptions{})
+		if err != nil {
+			t.Fatalf("Create() error = %v", err)
+		}
+		for range 2 {
+			if _, err := sess.Checkpoint(ctx); err != nil {
+				t.Fatalf("Checkpoint() error = %v", err)
+			}
+		}
+		sessions = append(sessions, sess)
+	}
+
+	// Only indexed checkpoints are read, not every file in the directory
+	stray := fmt.Sprintf(`{"id":"stray","sessionId":%q}`, sessions[0].ID())
+	if err := os.WriteFile(filepath.Join(dir, "assistant", "checkpoints", "stray.json"), []byte(stray), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	cps, err := backend.ListCheckpoints(ctx, sessions[0].ID())
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error = %v", err)
+	}
+	if len(cps) != 2 {
+		t.Fatalf("ListCheckpoints() = %d checkpoints, want 2", len(cps))
+	}
+
+	if err := backend.DeleteCheckpoint(ctx, cps[0].ID); err != nil {
+		t.Fatalf("DeleteCheckpoint() error = %v", err)
+	}
+	if cps, _ = backend.ListCheckpoints(ctx, sessions[0].ID()); len(cps) != 1 {
+		t.Errorf("ListCheckpoints() after delete = %d checkpoints, want 1", len(cps))
+	}
+
+	// Checkpoints saved before the index existed are found and indexed
+	indexPath := filepath.Join(dir, "assistant", "checkpoints", sessions[1].ID()+".index")
+	if err := os.Remove(indexPath); err != nil {
+		t.Fatalf("remove index: %v", err)
+	}
+	if cps, _ = backend.ListCheckpoints(ctx, sessions[1].ID()); len(cps) != 2 {
+		t.Errorf("ListCheckpoints() without an index = %d checkpoints, want 2", len(cps))
+	}
+	if _, err := os.Stat(indexPath); err != nil {
+		t.Errorf("index was not rebuilt: %v", err)
+	}
+}
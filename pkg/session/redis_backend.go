@@ -256,37 +256,52 @@ func (b *RedisBackend) ListSessions(ctx context.Context, agentName string, opts
 	// Sort session IDs for deterministic pagination (Redis sets are unordered)
 	sort.Strings(sessionIDs)
 
-	// Apply offset and limit
-	start := opts.Offset
-	if start >= len(sessionIDs) {
-		return []*SessionMetadata{}, nil
-	}
-
-	end := len(sessionIDs)
-	if opts.Limit > 0 && start+opts.Limit < end {
-		end = start + opts.Limit
+	// Time filters need the metadata, so paginate after loading it
+	byTime := !opts.UpdatedAfter.IsZero() || !opts.UpdatedBefore.IsZero()
+	if !byTime {
+		sessionIDs = paginate(sessionIDs, opts)
 	}
 
-	sessionIDs = sessionIDs[start:end]
-
 	// Load metadata for each session
 	sessions := make([]*SessionMetadata, 0, len(sessionIDs))
 	for _, id := range sessionIDs {
 		meta, err := b.LoadSession(ctx, id)
 		if err != nil {
 			if errors.Is(err, ErrSessionNotFound) {
-				// Session was deleted, clean up index
+				// Session was deleted or expired, clean up indexes
 				b.client.SRem(ctx, b.agentIndexKey(agentName), id)
+				if opts.UserID != "" {
+					b.client.SRem(ctx, b.userIndexKey(opts.UserID), id)
+				}
 				continue
 			}
 			return nil, err
 		}
-		sessions = append(sessions, meta)
+		if opts.matches(meta) {
+			sessions = append(sessions, meta)
+		}
 	}
 
+	if byTime {
+		sessions = paginate(sessions, opts)
+	}
 	return sessions, nil
 }
 
+// paginate applies the offset and limit of opts to items
+func paginate[T any](items []T, opts ListOptions) []T {
+	start := opts.Offset
+	if start >= len(items) {
+		return []T{}
+	}
+
+	end := len(items)
+	if opts.Limit > 0 && start+opts.Limit < end {
+		end = start + opts.Limit
+	}
+	return items[start:end]
+}
+
 // AppendEntry adds an entry to a session.
 func (b *RedisBackend) AppendEntry(ctx context.Context, sessionID string, entry *SessionEntry) error {
 	b.mu.RLock()
@@ -366,8 +381,11 @@ func (b *RedisBackend) SaveCheckpoint(ctx context.Context, checkpoint *Checkpoin
 		pipe.Set(ctx, b.checkpointKey(checkpoint.ID), data, 0)
 	}
 
-	// Add to session's checkpoint index
+	// Add to session's checkpoint index, expiring with the session
 	pipe.SAdd(ctx, b.sessionCheckpointsKey(checkpoint.SessionID), checkpoint.ID)
+	if b.ttl > 0 {
+		pipe.Expire(ctx, b.sessionCheckpointsKey(checkpoint.SessionID), b.ttl)
+	}
 
 	_, err = pipe.Exec(ctx)
 	if err != nil {
@@ -402,6 +420,55 @@ func (b *RedisBackend) LoadCheckpoint(ctx context.Context, checkpointID string)
 	return &checkpoint, nil
 }
 
+// ListCheckpoints returns a session's checkpoints, oldest first.
+func (b *RedisBackend) ListCheckpoints(ctx context.Context, sessionID string) ([]*Checkpoint, error) {
+	b.mu.RLock()
+	if b.closed {
+		b.mu.RUnlock()
+		return nil, ErrStorageClosed
+	}
+	b.mu.RUnlock()
+
+	ids, err := b.client.SMembers(ctx, b.sessionCheckpointsKey(sessionID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	checkpoints := make([]*Checkpoint, 0, len(ids))
+	for _, id := range ids {
+		checkpoint, err := b.LoadCheckpoint(ctx, id)
+		if err != nil {
+			if errors.Is(err, ErrCheckpointNotFound) {
+				// Checkpoint expired, clean up the index
+				b.client.SRem(ctx, b.sessionCheckpointsKey(sessionID), id)
+				continue
+			}
+			return nil, err
+		}
+		checkpoints = append(checkpoints, checkpoint)
+	}
+
+	sortCheckpoints(checkpoints)
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint removes a checkpoint and its index entry.
+func (b *RedisBackend) DeleteCheckpoint(ctx context.Context, checkpointID string) error {
+	checkpoint, err := b.LoadCheckpoint(ctx, checkpointID)
+	if err != nil {
+		return err
+	}
+
+	pipe := b.client.Pipeline()
+	pipe.Del(ctx, b.checkpointKey(checkpointID))
+	pipe.SRem(ctx, b.sessionCheckpointsKey(checkpoint.SessionID), checkpointID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("delete checkpoint: %w", err)
+	}
+
+	return nil
+}
+
 // Close releases resources held by the backend.
 func (b *RedisBackend) Close() error {
 	b.mu.Lock()
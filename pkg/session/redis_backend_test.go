@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/aixgo-dev/aixgo/agent"
 	"github.com/alicebob/miniredis/v2"
 	"github.com/redis/go-redis/v9"
 )
@@ -334,3 +335,134 @@ func TestRedisBackend_TTL(t *testing.T) {
 		t.Errorf("expected ErrSessionNotFound after TTL expiry, got %v", err)
 	}
 }
+
+func TestRedisBackend_TTLExpiresCheckpoints(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	backend := NewRedisBackendFromClient(client, "test:", time.Hour)
+	defer func() { _ = backend.Close() }()
+
+	ctx := context.Background()
+	meta := &SessionMetadata{ID: "sess-ttl", AgentName: "test-agent", UserID: "user-1"}
+	if err := backend.SaveSession(ctx, meta); err != nil {
+		t.Fatalf("SaveSession failed: %v", err)
+	}
+	if err := backend.AppendEntry(ctx, meta.ID, &SessionEntry{ID: "e1", Type: EntryTypeMessage}); err != nil {
+		t.Fatalf("AppendEntry failed: %v", err)
+	}
+	if err := backend.SaveCheckpoint(ctx, &Checkpoint{ID: "cp1", SessionID: meta.ID}); err != nil {
+		t.Fatalf("SaveCheckpoint failed: %v", err)
+	}
+
+	mr.FastForward(2 * time.Hour)
+
+	for _, key := range []string{"test:meta:sess-ttl", "test:entries:sess-ttl", "test:checkpoint:cp1", "test:session-checkpoints:sess-ttl"} {
+		if mr.Exists(key) {
+			t.Errorf("key %s still exists after TTL", key)
+		}
+	}
+
+	// Expired sessions are dropped from the listing indexes
+	sessions, err := backend.ListSessions(ctx, "test-agent", ListOptions{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("ListSessions failed: %v", err)
+	}
+	if len(sessions) != 0 {
+		t.Errorf("ListSessions returned %d expired sessions", len(sessions))
+	}
+	if members, _ := mr.Members("test:user:user-1"); len(members) != 0 {
+		t.Errorf("user index still holds %v", members)
+	}
+}
+
+// TestBackendResumeAfterRestart checks that a session written by one
+// process can be resumed by a new manager and backend over the same storage,
+// with identical behavior for the file and Redis backends.
+func TestBackendResumeAfterRestart(t *testing.T) {
+	mr := miniredis.RunT(t)
+	dir := t.TempDir()
+
+	backends := []struct {
+		name string
+		open func(t *testing.T) StorageBackend
+	}{
+		{"file", func(t *testing.T) StorageBackend {
+			b, err := NewFileBackend(dir)
+			if err != nil {
+				t.Fatalf("NewFileBackend() error = %v", err)
+			}
+			return b
+		}},
+		{"redis", func(t *testing.T) StorageBackend {
+			return NewRedisBackendFromClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "", 0)
+		}},
+	}
+
+	for _, bt := range backends {
+		t.Run(bt.name, func(t *testing.T) {
+			ctx := context.Background()
+
+			// First process: create, append, checkpoint, append more
+			mgr := NewManager(bt.open(t))
+			sess, err := mgr.GetOrCreate(ctx, "assistant", "user-1")
+			if err != nil {
+				t.Fatalf("GetOrCreate() error = %v", err)
+			}
+			for _, text := range []string{"hello", "hi there"} {
+				if err := sess.AppendMessage(ctx, agent.NewMessage("user", text)); err != nil {
+					t.Fatalf("AppendMessage() error = %v", err)
+				}
+			}
+			checkpoint, err := sess.Checkpoint(ctx)
+			if err != nil {
+				t.Fatalf("Checkpoint() error = %v", err)
+			}
+			if err := sess.AppendMessage(ctx, agent.NewMessage("user", "after checkpoint")); err != nil {
+				t.Fatalf("AppendMessage() error = %v", err)
+			}
+			sessionID := sess.ID()
+			if err := mgr.Close(); err != nil {
+				t.Fatalf("Close() error = %v", err)
+			}
+
+			// Second process: resume by user and restore the checkpoint
+			mgr = NewManager(bt.open(t))
+			defer func() { _ = mgr.Close() }()
+
+			resumed, err := mgr.GetOrCreate(ctx, "assistant", "user-1")
+			if err != nil {
+				t.Fatalf("GetOrCreate() after restart error = %v", err)
+			}
+			if resumed.ID() != sessionID {
+				t.Fatalf("resumed session %s, want %s", resumed.ID(), sessionID)
+			}
+
+			messages, err := resumed.GetMessages(ctx)
+			if err != nil {
+				t.Fatalf("GetMessages() error = %v", err)
+			}
+			if len(messages) != 3 {
+				t.Fatalf("GetMessages() returned %d messages, want 3", len(messages))
+			}
+
+			list, err := mgr.List(ctx, "assistant", ListOptions{})
+			if err != nil {
+				t.Fatalf("List() error = %v", err)
+			}
+			if len(list) != 1 || list[0].MessageCount != 3 {
+				t.Errorf("List() = %v, want one session with 3 messages", list)
+			}
+
+			if err := resumed.Restore(ctx, checkpoint.ID); err != nil {
+				t.Fatalf("Restore() error = %v", err)
+			}
+			messages, err = resumed.GetMessages(ctx)
+			if err != nil {
+				t.Fatalf("GetMessages() after Restore() error = %v", err)
+			}
+			if len(messages) != 2 {
+				t.Errorf("GetMessages() after Restore() returned %d messages, want 2", len(messages))
+			}
+		})
+	}
+}
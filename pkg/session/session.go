@@ -4,12 +4,15 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
+	"log"
 	"maps"
 	"sync"
 	"time"
 
 	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
 	"github.com/google/uuid"
 )
 
@@ -29,11 +32,21 @@ type Session interface {
 	AppendMessage(ctx context.Context, msg *agent.Message) error
 
 	// GetMessages retrieves all messages in the session.
+	// Messages replaced by Summarize are returned as their summary.
 	GetMessages(ctx context.Context) ([]*agent.Message, error)
 
+	// Summarize replaces older messages with a single summary message
+	// generated by p. It checkpoints first and returns the checkpoint, so
+	// the summary can be undone with Restore. It returns nil if the
+	// history is too short to summarize.
+	Summarize(ctx context.Context, p provider.Provider, opts SummarizeOptions) (*Checkpoint, error)
+
 	// Checkpoint creates a restorable state snapshot.
 	Checkpoint(ctx context.Context) (*Checkpoint, error)
 
+	// ListCheckpoints returns the session's checkpoints, oldest first.
+	ListCheckpoints(ctx context.Context) ([]*Checkpoint, error)
+
 	// Restore reverts the session to a previous checkpoint.
 	Restore(ctx context.Context, checkpointID string) error
 
@@ -112,6 +125,15 @@ func (s *sessionImpl) AppendMessage(ctx context.Context, msg *agent.Message) err
 		return fmt.Errorf("save session metadata: %w", err)
 	}
 
+	// Counting from MessageCount keeps the cadence across restarts. The
+	// message is already stored, so a failed checkpoint must not fail the
+	// append and make callers retry it.
+	if every := s.meta.AutoCheckpointEvery; every > 0 && s.meta.MessageCount%every == 0 {
+		if err := s.autoCheckpointLocked(ctx); err != nil {
+			log.Printf("session %s: auto checkpoint failed: %v", s.meta.ID, err)
+		}
+	}
+
 	return nil
 }
 
@@ -134,14 +156,10 @@ func (s *sessionImpl) GetMessages(ctx context.Context) ([]*agent.Message, error)
 	}
 
 	// Convert entries to messages
-	messages := make([]*agent.Message, 0, len(s.entries))
-	for _, entry := range s.entries {
-		if entry.Type == EntryTypeMessage {
-			msg := dataToMessage(entry.Data)
-			if msg != nil {
-				messages = append(messages, msg)
-			}
-		}
+	history := s.historyLocked()
+	messages := make([]*agent.Message, 0, len(history))
+	for _, item := range history {
+		messages = append(messages, item.msg)
 	}
 
 	return messages, nil
@@ -152,6 +170,11 @@ func (s *sessionImpl) Checkpoint(ctx context.Context) (*Checkpoint, error) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
+	return s.checkpointLocked(ctx, nil)
+}
+
+// checkpointLocked saves a checkpoint at the current leaf. Caller must hold mu.
+func (s *sessionImpl) checkpointLocked(ctx context.Context, metadata map[string]any) (*Checkpoint, error) {
 	// Compute checksum of all entries
 	checksum := s.computeChecksum()
 
@@ -167,6 +190,7 @@ func (s *sessionImpl) Checkpoint(ctx context.Context) (*Checkpoint, error) {
 		Timestamp: time.Now().UTC(),
 		EntryID:   entryID,
 		Checksum:  checksum,
+		Metadata:  metadata,
 	}
 
 	if err := s.backend.SaveCheckpoint(ctx, checkpoint); err != nil {
@@ -176,6 +200,58 @@ func (s *sessionImpl) Checkpoint(ctx context.Context) (*Checkpoint, error) {
 	return checkpoint, nil
 }
 
+// autoCheckpointLocked creates an automatic checkpoint and deletes the
+// oldest automatic ones beyond MaxCheckpoints. Caller must hold mu.
+func (s *sessionImpl) autoCheckpointLocked(ctx context.Context) error {
+	if _, err := s.checkpointLocked(ctx, map[string]any{autoCheckpointKey: true}); err != nil {
+		return err
+	}
+	if s.meta.MaxCheckpoints <= 0 {
+		return nil
+	}
+
+	checkpoints, err := s.backend.ListCheckpoints(ctx, s.meta.ID)
+	if err != nil {
+		return fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	var auto []*Checkpoint
+	for _, checkpoint := range checkpoints {
+		if isAutoCheckpoint(checkpoint) {
+			auto = append(auto, checkpoint)
+		}
+	}
+	for len(auto) > s.meta.MaxCheckpoints {
+		if err := s.backend.DeleteCheckpoint(ctx, auto[0].ID); err != nil && !errors.Is(err, ErrCheckpointNotFound) {
+			return fmt.Errorf("prune checkpoint: %w", err)
+		}
+		auto = auto[1:]
+	}
+
+	return nil
+}
+
+// autoCheckpointKey marks checkpoints created by AutoCheckpointEvery
+const autoCheckpointKey = "auto"
+
+// isAutoCheckpoint reports whether the checkpoint was created automatically.
+func isAutoCheckpoint(checkpoint *Checkpoint) bool {
+	auto, _ := checkpoint.Metadata[autoCheckpointKey].(bool)
+	return auto
+}
+
+// ListCheckpoints returns the session's checkpoints, oldest first.
+func (s *sessionImpl) ListCheckpoints(ctx context.Context) ([]*Checkpoint, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	checkpoints, err := s.backend.ListCheckpoints(ctx, s.meta.ID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+	return checkpoints, nil
+}
+
 // Restore reverts the session to a previous checkpoint.
 func (s *sessionImpl) Restore(ctx context.Context, checkpointID string) error {
 	s.mu.Lock()
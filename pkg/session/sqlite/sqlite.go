@@ -0,0 +1,404 @@
+// Package sqlite provides a session.StorageBackend in a single SQLite
+// database file. It lives in its own package so only programs that use it
+// link the SQLite driver.
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/session"
+	_ "modernc.org/sqlite" // registers the "sqlite" database/sql driver
+)
+
+// schema creates the tables and indexes used by Backend.
+// Timestamps are stored as Unix nanoseconds so range filters use the index.
+var schema = []string{
+	`CREATE TABLE IF NOT EXISTS sessions (
+	id TEXT PRIMARY KEY,
+	agent_name TEXT NOT NULL,
+	user_id TEXT NOT NULL DEFAULT '',
+	created_at INTEGER NOT NULL,
+	updated_at INTEGER NOT NULL,
+	data TEXT NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS sessions_agent_updated ON sessions (agent_name, updated_at)`,
+	`CREATE INDEX IF NOT EXISTS sessions_agent_user_updated ON sessions (agent_name, user_id, updated_at)`,
+	`CREATE TABLE IF NOT EXISTS entries (
+	seq INTEGER PRIMARY KEY AUTOINCREMENT,
+	session_id TEXT NOT NULL,
+	data TEXT NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS entries_session ON entries (session_id, seq)`,
+	`CREATE TABLE IF NOT EXISTS checkpoints (
+	id TEXT PRIMARY KEY,
+	session_id TEXT NOT NULL,
+	created_at INTEGER NOT NULL,
+	data TEXT NOT NULL
+)`,
+	`CREATE INDEX IF NOT EXISTS checkpoints_session ON checkpoints (session_id, created_at)`,
+}
+
+// Backend implements session.StorageBackend in a single SQLite database file.
+// Sessions, entries, and checkpoints live in indexed tables, so ListSessions
+// filters by agent, user, and update time without reading every session.
+// It sits between session.FileBackend and session.RedisBackend: durable and
+// fast to query, but limited to one node.
+//
+// Entries are never deleted by Restore; like session.JSONLBackend,
+// LoadEntries returns the branch ending at the session's CurrentLeaf, so a
+// restored checkpoint survives reopening the database.
+type Backend struct {
+	db     *sql.DB
+	mu     sync.RWMutex
+	closed bool
+}
+
+// New opens or creates the SQLite database at path and ensures its schema
+// exists.
+func New(path string) (*Backend, error) {
+	if path == "" {
+		return nil, errors.New("sqlite backend path is required")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("create database directory: %w", err)
+	}
+
+	dsn := "file:" + (&url.URL{Path: path}).EscapedPath() +
+		"?_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite database: %w", err)
+	}
+	// SQLite allows one writer at a time; a single connection avoids
+	// SQLITE_BUSY between the pool's own connections
+	db.SetMaxOpenConns(1)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for _, stmt := range schema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			_ = db.Close()
+			return nil, fmt.Errorf("create sqlite schema: %w", err)
+		}
+	}
+
+	return &Backend{db: db}, nil
+}
+
+// checkOpen returns session.ErrStorageClosed once Close has been called.
+func (s *Backend) checkOpen() error {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if s.closed {
+		return session.ErrStorageClosed
+	}
+	return nil
+}
+
+// SaveSession creates or updates session metadata.
+func (s *Backend) SaveSession(ctx context.Context, meta *session.SessionMetadata) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshal metadata: %w", err)
+	}
+
+	_, err = s.db.ExecContext(ctx, `INSERT INTO sessions (id, agent_name, user_id, created_at, updated_at, data)
+VALUES (?, ?, ?, ?, ?, ?)
+ON CONFLICT (id) DO UPDATE SET
+	agent_name = excluded.agent_name,
+	user_id = excluded.user_id,
+	created_at = excluded.created_at,
+	updated_at = excluded.updated_at,
+	data = excluded.data`,
+		meta.ID, meta.AgentName, meta.UserID, meta.CreatedAt.UnixNano(), meta.UpdatedAt.UnixNano(), string(data))
+	if err != nil {
+		return fmt.Errorf("save session: %w", err)
+	}
+	return nil
+}
+
+// LoadSession retrieves session metadata by ID.
+func (s *Backend) LoadSession(ctx context.Context, sessionID string) (*session.SessionMetadata, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM sessions WHERE id = ?`, sessionID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, session.ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get session: %w", err)
+	}
+
+	var meta session.SessionMetadata
+	if err := json.Unmarshal([]byte(data), &meta); err != nil {
+		return nil, fmt.Errorf("unmarshal metadata: %w", err)
+	}
+	return &meta, nil
+}
+
+// DeleteSession removes a session with its entries and checkpoints.
+func (s *Backend) DeleteSession(ctx context.Context, sessionID string) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `DELETE FROM sessions WHERE id = ?`, sessionID)
+	if err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return session.ErrSessionNotFound
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM entries WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("delete entries: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, `DELETE FROM checkpoints WHERE session_id = ?`, sessionID); err != nil {
+		return fmt.Errorf("delete checkpoints: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("delete session: %w", err)
+	}
+	return nil
+}
+
+// ListSessions returns sessions for an agent matching the filter options,
+// most recently updated first. Filtering and pagination run in SQL.
+func (s *Backend) ListSessions(ctx context.Context, agentName string, opts session.ListOptions) ([]*session.SessionMetadata, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	where := []string{"agent_name = ?"}
+	args := []any{agentName}
+	if opts.UserID != "" {
+		where = append(where, "user_id = ?")
+		args = append(args, opts.UserID)
+	}
+	if !opts.UpdatedAfter.IsZero() {
+		where = append(where, "updated_at >= ?")
+		args = append(args, opts.UpdatedAfter.UnixNano())
+	}
+	if !opts.UpdatedBefore.IsZero() {
+		where = append(where, "updated_at < ?")
+		args = append(args, opts.UpdatedBefore.UnixNano())
+	}
+
+	// LIMIT -1 means no limit in SQLite
+	limit := -1
+	if opts.Limit > 0 {
+		limit = opts.Limit
+	}
+	args = append(args, limit, max(opts.Offset, 0))
+
+	query := `SELECT data FROM sessions WHERE ` + strings.Join(where, " AND ") +
+		` ORDER BY updated_at DESC, id LIMIT ? OFFSET ?`
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	sessions := []*session.SessionMetadata{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("list sessions: %w", err)
+		}
+		var meta session.SessionMetadata
+		if err := json.Unmarshal([]byte(data), &meta); err != nil {
+			return nil, fmt.Errorf("unmarshal metadata: %w", err)
+		}
+		sessions = append(sessions, &meta)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list sessions: %w", err)
+	}
+	return sessions, nil
+}
+
+// AppendEntry adds an entry to a session (append-only).
+func (s *Backend) AppendEntry(ctx context.Context, sessionID string, entry *session.SessionEntry) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return fmt.Errorf("marshal entry: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `INSERT INTO entries (session_id, data)
+SELECT ?, ? WHERE EXISTS (SELECT 1 FROM sessions WHERE id = ?)`, sessionID, string(data), sessionID)
+	if err != nil {
+		return fmt.Errorf("append entry: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return session.ErrSessionNotFound
+	}
+	return nil
+}
+
+// LoadEntries retrieves the entries leading to the session's current leaf
+// in order.
+func (s *Backend) LoadEntries(ctx context.Context, sessionID string) ([]*session.SessionEntry, error) {
+	meta, err := s.LoadSession(ctx, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM entries WHERE session_id = ? ORDER BY seq`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("load entries: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	var entries []*session.SessionEntry
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("load entries: %w", err)
+		}
+		var entry session.SessionEntry
+		if err := json.Unmarshal([]byte(data), &entry); err != nil {
+			return nil, fmt.Errorf("unmarshal entry: %w", err)
+		}
+		entries = append(entries, &entry)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("load entries: %w", err)
+	}
+
+	return session.ActiveBranch(entries, meta.CurrentLeaf), nil
+}
+
+// SaveCheckpoint stores a checkpoint.
+func (s *Backend) SaveCheckpoint(ctx context.Context, checkpoint *session.Checkpoint) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	data, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+
+	res, err := s.db.ExecContext(ctx, `INSERT INTO checkpoints (id, session_id, created_at, data)
+SELECT ?, ?, ?, ? WHERE EXISTS (SELECT 1 FROM sessions WHERE id = ?)
+ON CONFLICT (id) DO UPDATE SET
+	session_id = excluded.session_id,
+	created_at = excluded.created_at,
+	data = excluded.data`,
+		checkpoint.ID, checkpoint.SessionID, checkpoint.Timestamp.UnixNano(), string(data), checkpoint.SessionID)
+	if err != nil {
+		return fmt.Errorf("save checkpoint: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return session.ErrSessionNotFound
+	}
+	return nil
+}
+
+// LoadCheckpoint retrieves a checkpoint by ID.
+func (s *Backend) LoadCheckpoint(ctx context.Context, checkpointID string) (*session.Checkpoint, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	var data string
+	err := s.db.QueryRowContext(ctx, `SELECT data FROM checkpoints WHERE id = ?`, checkpointID).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, session.ErrCheckpointNotFound
+	}
+	if err != nil {
+		return nil, fmt.Errorf("get checkpoint: %w", err)
+	}
+
+	var checkpoint session.Checkpoint
+	if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+		return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	return &checkpoint, nil
+}
+
+// ListCheckpoints returns a session's checkpoints, oldest first.
+func (s *Backend) ListCheckpoints(ctx context.Context, sessionID string) ([]*session.Checkpoint, error) {
+	if err := s.checkOpen(); err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT data FROM checkpoints WHERE session_id = ? ORDER BY created_at, id`, sessionID)
+	if err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+	defer func() { _ = rows.Close() }()
+
+	checkpoints := []*session.Checkpoint{}
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return nil, fmt.Errorf("list checkpoints: %w", err)
+		}
+		var checkpoint session.Checkpoint
+		if err := json.Unmarshal([]byte(data), &checkpoint); err != nil {
+			return nil, fmt.Errorf("unmarshal checkpoint: %w", err)
+		}
+		checkpoints = append(checkpoints, &checkpoint)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("list checkpoints: %w", err)
+	}
+
+	return checkpoints, nil
+}
+
+// DeleteCheckpoint removes a checkpoint.
+func (s *Backend) DeleteCheckpoint(ctx context.Context, checkpointID string) error {
+	if err := s.checkOpen(); err != nil {
+		return err
+	}
+
+	res, err := s.db.ExecContext(ctx, `DELETE FROM checkpoints WHERE id = ?`, checkpointID)
+	if err != nil {
+		return fmt.Errorf("delete checkpoint: %w", err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return session.ErrCheckpointNotFound
+	}
+	return nil
+}
+
+// Close closes the database.
+func (s *Backend) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		return nil
+	}
+	s.closed = true
+	return s.db.Close()
+}
@@ -0,0 +1,241 @@
+package sqlite
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/session"
+)
+
+func TestReopen(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "db", "sessions.db")
+
+	backend, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	mgr := session.NewManager(backend)
+
+	sess, err := mgr.Create(ctx, "test-agent", session.CreateOptions{UserID: "user-123"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for _, text := range []string{"Hello", "Hi!"} {
+		if err := sess.AppendMessage(ctx, agent.NewMessage("user", text)); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+	checkpoint, err := sess.Checkpoint(ctx)
+	if err != nil {
+		t.Fatalf("Checkpoint() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "discarded")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	if err := sess.Restore(ctx, checkpoint.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if err := sess.AppendMessage(ctx, agent.NewMessage("user", "after restore")); err != nil {
+		t.Fatalf("AppendMessage() error = %v", err)
+	}
+	want := messageTexts(t, sess)
+	if err := mgr.Close(); err != nil {
+		t.Fatalf("Close() error = %v", err)
+	}
+
+	// Reopening the file yields the restored history
+	backend, err = New(path)
+	if err != nil {
+		t.Fatalf("New() reopen error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr = session.NewManager(backend)
+
+	reopened, err := mgr.Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	got := messageTexts(t, reopened)
+	if len(got) != 3 || len(got) != len(want) {
+		t.Fatalf("reopened history = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Fatalf("reopened history = %v, want %v", got, want)
+		}
+	}
+
+	loaded, err := backend.LoadCheckpoint(ctx, checkpoint.ID)
+	if err != nil {
+		t.Fatalf("LoadCheckpoint() after reopen error = %v", err)
+	}
+	if loaded.EntryID != checkpoint.EntryID || loaded.SessionID != sess.ID() {
+		t.Errorf("LoadCheckpoint() = %+v, want %+v", loaded, checkpoint)
+	}
+
+	// Deleting removes the entries and checkpoints with the session
+	if err := mgr.Delete(ctx, sess.ID()); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if _, err := backend.LoadSession(ctx, sess.ID()); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("LoadSession() after delete error = %v, want session.ErrSessionNotFound", err)
+	}
+	if _, err := backend.LoadCheckpoint(ctx, checkpoint.ID); !errors.Is(err, session.ErrCheckpointNotFound) {
+		t.Errorf("LoadCheckpoint() after delete error = %v, want session.ErrCheckpointNotFound", err)
+	}
+	if err := backend.DeleteSession(ctx, sess.ID()); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("DeleteSession() twice error = %v, want session.ErrSessionNotFound", err)
+	}
+}
+
+func TestUnknownSession(t *testing.T) {
+	backend, err := New(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+
+	ctx := context.Background()
+	if err := backend.AppendEntry(ctx, "missing", &session.SessionEntry{ID: "e1"}); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("AppendEntry() error = %v, want session.ErrSessionNotFound", err)
+	}
+	if err := backend.SaveCheckpoint(ctx, &session.Checkpoint{ID: "cp1", SessionID: "missing"}); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("SaveCheckpoint() error = %v, want session.ErrSessionNotFound", err)
+	}
+	if _, err := backend.LoadEntries(ctx, "missing"); !errors.Is(err, session.ErrSessionNotFound) {
+		t.Errorf("LoadEntries() error = %v, want session.ErrSessionNotFound", err)
+	}
+}
+
+func TestClosed(t *testing.T) {
+	backend, err := New(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	_ = backend.Close()
+
+	ctx := context.Background()
+	if err := backend.SaveSession(ctx, &session.SessionMetadata{ID: "s"}); !errors.Is(err, session.ErrStorageClosed) {
+		t.Errorf("SaveSession() error = %v, want session.ErrStorageClosed", err)
+	}
+	if _, err := backend.LoadEntries(ctx, "s"); !errors.Is(err, session.ErrStorageClosed) {
+		t.Errorf("LoadEntries() error = %v, want session.ErrStorageClosed", err)
+	}
+	if _, err := backend.ListSessions(ctx, "a", session.ListOptions{}); !errors.Is(err, session.ErrStorageClosed) {
+		t.Errorf("ListSessions() error = %v, want session.ErrStorageClosed", err)
+	}
+	if _, err := New(""); err == nil {
+		t.Error("New(\"\") should fail")
+	}
+}
+
+func TestListSessionsFilters(t *testing.T) {
+	ctx := context.Background()
+	backend, err := New(filepath.Join(t.TempDir(), "sessions.db"))
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	for _, meta := range []*session.SessionMetadata{
+		{ID: "s1", AgentName: "assistant", UserID: "alice", UpdatedAt: base},
+		{ID: "s2", AgentName: "assistant", UserID: "bob", UpdatedAt: base.Add(time.Hour)},
+		{ID: "s3", AgentName: "assistant", UserID: "alice", UpdatedAt: base.Add(2 * time.Hour)},
+		{ID: "s4", AgentName: "assistant", UserID: "alice", UpdatedAt: base.Add(3 * time.Hour)},
+		{ID: "s5", AgentName: "other", UserID: "alice", UpdatedAt: base.Add(time.Hour)},
+	} {
+		if err := backend.SaveSession(ctx, meta); err != nil {
+			t.Fatalf("SaveSession() error = %v", err)
+		}
+	}
+
+	tests := []struct {
+		name string
+		opts session.ListOptions
+		want []string
+	}{
+		{name: "by agent", opts: session.ListOptions{}, want: []string{"s4", "s3", "s2", "s1"}},
+		{name: "by user", opts: session.ListOptions{UserID: "alice"}, want: []string{"s4", "s3", "s1"}},
+		{name: "updated after", opts: session.ListOptions{UpdatedAfter: base.Add(time.Hour)}, want: []string{"s4", "s3", "s2"}},
+		{name: "updated window", opts: session.ListOptions{UpdatedAfter: base.Add(time.Hour), UpdatedBefore: base.Add(3 * time.Hour)}, want: []string{"s3", "s2"}},
+		{name: "user and time", opts: session.ListOptions{UserID: "alice", UpdatedBefore: base.Add(3 * time.Hour)}, want: []string{"s3", "s1"}},
+		{name: "paginated", opts: session.ListOptions{UserID: "alice", UpdatedAfter: base, Offset: 1, Limit: 1}, want: []string{"s3"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := backend.ListSessions(ctx, "assistant", tt.opts)
+			if err != nil {
+				t.Fatalf("ListSessions() error = %v", err)
+			}
+			ids := make([]string, len(got))
+			for i, meta := range got {
+				ids[i] = meta.ID
+			}
+			if len(ids) != len(tt.want) {
+				t.Fatalf("ListSessions() = %v, want %v", ids, tt.want)
+			}
+			for i := range ids {
+				if ids[i] != tt.want[i] {
+					t.Fatalf("ListSessions() = %v, want %v", ids, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestAutoCheckpoint(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "sessions.db")
+	backend, err := New(path)
+	if err != nil {
+		t.Fatalf("New() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+
+	sess, err := session.NewManager(backend).Create(ctx, "assistant", session.CreateOptions{
+		AutoCheckpointEvery: 2,
+		MaxCheckpoints:      2,
+	})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < 6; i++ {
+		if err := sess.AppendMessage(ctx, agent.NewMessage("user", "message")); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+
+	// Checkpoints at messages 2, 4 and 6; the oldest is pruned
+	checkpoints, err := sess.ListCheckpoints(ctx)
+	if err != nil {
+		t.Fatalf("ListCheckpoints() error = %v", err)
+	}
+	if len(checkpoints) != 2 {
+		t.Fatalf("ListCheckpoints() = %d checkpoints, want 2", len(checkpoints))
+	}
+	if err := sess.Restore(ctx, checkpoints[0].ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	if got := messageTexts(t, sess); len(got) != 4 {
+		t.Errorf("history after restoring the oldest checkpoint = %d messages, want 4", len(got))
+	}
+}
+
+func messageTexts(t *testing.T, sess session.Session) []string {
+	t.Helper()
+	messages, err := sess.GetMessages(context.Background())
+	if err != nil {
+		t.Fatalf("GetMessages() error = %v", err)
+	}
+	texts := make([]string, len(messages))
+	for i, msg := range messages {
+		texts[i] = msg.Payload
+	}
+	return texts
+}
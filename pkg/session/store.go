@@ -3,6 +3,9 @@ package session
 import (
 	"context"
 	"errors"
+	"slices"
+	"sort"
+	"time"
 )
 
 // Common errors for storage operations.
@@ -44,6 +47,13 @@ type StorageBackend interface {
 	// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
 	LoadCheckpoint(ctx context.Context, checkpointID string) (*Checkpoint, error)
 
+	// ListCheckpoints returns a session's checkpoints, oldest first.
+	ListCheckpoints(ctx context.Context, sessionID string) ([]*Checkpoint, error)
+
+	// DeleteCheckpoint removes a checkpoint.
+	// Returns ErrCheckpointNotFound if the checkpoint doesn't exist.
+	DeleteCheckpoint(ctx context.Context, checkpointID string) error
+
 	// Close releases any resources held by the backend.
 	Close() error
 }
@@ -52,8 +62,66 @@ type StorageBackend interface {
 type ListOptions struct {
 	// UserID filters sessions by user.
 	UserID string
+	// UpdatedAfter, if set, keeps sessions updated at or after this time.
+	UpdatedAfter time.Time
+	// UpdatedBefore, if set, keeps sessions updated before this time.
+	UpdatedBefore time.Time
 	// Limit caps the number of results.
 	Limit int
 	// Offset skips the first N results.
 	Offset int
 }
+
+// matches reports whether meta passes the user and time filters.
+func (o ListOptions) matches(meta *SessionMetadata) bool {
+	if o.UserID != "" && meta.UserID != o.UserID {
+		return false
+	}
+	if !o.UpdatedAfter.IsZero() && meta.UpdatedAt.Before(o.UpdatedAfter) {
+		return false
+	}
+	if !o.UpdatedBefore.IsZero() && !meta.UpdatedAt.Before(o.UpdatedBefore) {
+		return false
+	}
+	return true
+}
+
+// sortCheckpoints orders checkpoints oldest first, breaking ties by ID.
+func sortCheckpoints(checkpoints []*Checkpoint) {
+	sort.Slice(checkpoints, func(i, j int) bool {
+		if !checkpoints[i].Timestamp.Equal(checkpoints[j].Timestamp) {
+			return checkpoints[i].Timestamp.Before(checkpoints[j].Timestamp)
+		}
+		return checkpoints[i].ID < checkpoints[j].ID
+	})
+}
+
+// ActiveBranch follows ParentID links back from leaf and returns that chain
+// oldest first. All entries are returned if leaf is not among them.
+// Backends that never delete entries use it to resolve a session's history
+// from SessionMetadata.CurrentLeaf after a Restore.
+func ActiveBranch(entries []*SessionEntry, leaf string) []*SessionEntry {
+	if leaf == "" {
+		return []*SessionEntry{}
+	}
+	byID := make(map[string]*SessionEntry, len(entries))
+	for _, entry := range entries {
+		byID[entry.ID] = entry
+	}
+	if _, ok := byID[leaf]; !ok {
+		return append([]*SessionEntry{}, entries...)
+	}
+
+	var branch []*SessionEntry
+	for id := leaf; id != ""; {
+		entry, ok := byID[id]
+		if !ok {
+			break
+		}
+		branch = append(branch, entry)
+		delete(byID, id) // Guards against ParentID cycles
+		id = entry.ParentID
+	}
+	slices.Reverse(branch)
+	return branch
+}
@@ -0,0 +1,106 @@
+package session
+
+import (
+	"context"
+	"path/filepath"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+)
+
+func TestListSessionsFilters(t *testing.T) {
+	mr := miniredis.RunT(t)
+
+	backends := []struct {
+		name string
+		open func(t *testing.T) StorageBackend
+	}{
+		{"file", func(t *testing.T) StorageBackend {
+			b, err := NewFileBackend(t.TempDir())
+			if err != nil {
+				t.Fatalf("NewFileBackend() error = %v", err)
+			}
+			return b
+		}},
+		{"jsonl", func(t *testing.T) StorageBackend {
+			b, err := NewJSONLBackend(filepath.Join(t.TempDir(), "session.jsonl"))
+			if err != nil {
+				t.Fatalf("NewJSONLBackend() error = %v", err)
+			}
+			return b
+		}},
+		{"redis", func(t *testing.T) StorageBackend {
+			return NewRedisBackendFromClient(redis.NewClient(&redis.Options{Addr: mr.Addr()}), "filters:", 0)
+		}},
+	}
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	sessions := []*SessionMetadata{
+		{ID: "s1", AgentName: "assistant", UserID: "alice", UpdatedAt: base},
+		{ID: "s2", AgentName: "assistant", UserID: "bob", UpdatedAt: base.Add(time.Hour)},
+		{ID: "s3", AgentName: "assistant", UserID: "alice", UpdatedAt: base.Add(2 * time.Hour)},
+		{ID: "s4", AgentName: "assistant", UserID: "alice", UpdatedAt: base.Add(3 * time.Hour)},
+		{ID: "s5", AgentName: "other", UserID: "alice", UpdatedAt: base.Add(time.Hour)},
+	}
+
+	tests := []struct {
+		name string
+		opts ListOptions
+		want []string
+	}{
+		{name: "by agent", opts: ListOptions{}, want: []string{"s4", "s3", "s2", "s1"}},
+		{name: "by user", opts: ListOptions{UserID: "alice"}, want: []string{"s4", "s3", "s1"}},
+		{name: "updated after", opts: ListOptions{UpdatedAfter: base.Add(time.Hour)}, want: []string{"s4", "s3", "s2"}},
+		{name: "updated window", opts: ListOptions{UpdatedAfter: base.Add(time.Hour), UpdatedBefore: base.Add(3 * time.Hour)}, want: []string{"s3", "s2"}},
+		{name: "user and time", opts: ListOptions{UserID: "alice", UpdatedBefore: base.Add(3 * time.Hour)}, want: []string{"s3", "s1"}},
+		{name: "paginated", opts: ListOptions{UserID: "alice", UpdatedAfter: base, Offset: 1, Limit: 1}, want: []string{"s3"}},
+	}
+
+	for _, bt := range backends {
+		t.Run(bt.name, func(t *testing.T) {
+			ctx := context.Background()
+			backend := bt.open(t)
+			defer func() { _ = backend.Close() }()
+			for _, meta := range sessions {
+				if err := backend.SaveSession(ctx, meta); err != nil {
+					t.Fatalf("SaveSession() error = %v", err)
+				}
+			}
+
+			for _, tt := range tests {
+				got, err := backend.ListSessions(ctx, "assistant", tt.opts)
+				if err != nil {
+					t.Fatalf("%s: ListSessions() error = %v", tt.name, err)
+				}
+				ids := make([]string, len(got))
+				for i, meta := range got {
+					ids[i] = meta.ID
+				}
+				// Redis lists in ID order rather than by update time
+				if bt.name == "redis" {
+					ids = sortedCopy(ids)
+					tt.want = sortedCopy(tt.want)
+				}
+				if len(ids) != len(tt.want) {
+					t.Errorf("%s: ListSessions() = %v, want %v", tt.name, ids, tt.want)
+					continue
+				}
+				for i := range ids {
+					if ids[i] != tt.want[i] {
+						t.Errorf("%s: ListSessions() = %v, want %v", tt.name, ids, tt.want)
+						break
+					}
+				}
+			}
+		})
+	}
+}
+
+func sortedCopy(s []string) []string {
+	c := append([]string{}, s...)
+	slices.Sort(c)
+	return c
+}
@@ -0,0 +1,202 @@
+package session
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/google/uuid"
+)
+
+// MessageTypeSummary is the type of messages created by Summarize.
+const MessageTypeSummary = "summary"
+
+// DefaultKeepRecent is the number of recent messages Summarize keeps
+// verbatim when SummarizeOptions.KeepRecent is not set.
+const DefaultKeepRecent = 10
+
+// summaryPrompt instructs the model how to condense the conversation.
+const summaryPrompt = `Summarize the following conversation so it can replace the original messages as context for continuing it. Preserve facts, decisions, user preferences, and open questions. Reply with the summary only.`
+
+// SummarizeOptions controls which messages Summarize replaces.
+type SummarizeOptions struct {
+	// KeepRecent is the number of most recent messages kept verbatim.
+	// Defaults to DefaultKeepRecent.
+	KeepRecent int
+	// MaxMessages is the history length at which summarization starts.
+	// Shorter histories are left unchanged. Zero summarizes whenever there
+	// are messages older than KeepRecent.
+	MaxMessages int
+	// Model is the model used to write the summary. Empty uses the
+	// provider's default.
+	Model string
+}
+
+// historyItem is a visible message and the entry it came from.
+type historyItem struct {
+	entryID string
+	msg     *agent.Message
+}
+
+// historyLocked returns the visible message history, applying summary
+// entries in order. Caller must hold s.mu.
+func (s *sessionImpl) historyLocked() []historyItem {
+	history := make([]historyItem, 0, len(s.entries))
+	for _, entry := range s.entries {
+		switch entry.Type {
+		case EntryTypeMessage:
+			if msg := dataToMessage(entry.Data); msg != nil {
+				history = append(history, historyItem{entry.ID, msg})
+			}
+
+		case EntryTypeSummary:
+			history = applySummary(history, entry)
+		}
+	}
+	return history
+}
+
+// applySummary replaces the messages a summary entry covers with the
+// summary, placed where the oldest replaced message was.
+func applySummary(history []historyItem, entry *SessionEntry) []historyItem {
+	msgData, _ := entry.Data["message"].(map[string]any)
+	summary := dataToMessage(msgData)
+	if summary == nil {
+		return history
+	}
+
+	replaced := make(map[string]bool)
+	switch ids := entry.Data["replaces"].(type) {
+	case []string:
+		for _, id := range ids {
+			replaced[id] = true
+		}
+	case []any: // decoded from storage
+		for _, id := range ids {
+			if s, ok := id.(string); ok {
+				replaced[s] = true
+			}
+		}
+	}
+
+	out := make([]historyItem, 0, len(history)+1)
+	inserted := false
+	for _, item := range history {
+		if replaced[item.entryID] {
+			if !inserted {
+				out = append(out, historyItem{entry.ID, summary})
+				inserted = true
+			}
+			continue
+		}
+		out = append(out, item)
+	}
+	if !inserted {
+		out = append([]historyItem{{entry.ID, summary}}, out...)
+	}
+	return out
+}
+
+// Summarize replaces older messages with a single summary message.
+func (s *sessionImpl) Summarize(ctx context.Context, p provider.Provider, opts SummarizeOptions) (*Checkpoint, error) {
+	if p == nil {
+		return nil, fmt.Errorf("summarize: provider is required")
+	}
+	keep := opts.KeepRecent
+	if keep <= 0 {
+		keep = DefaultKeepRecent
+	}
+
+	// Ensure entries are loaded
+	if _, err := s.GetMessages(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	history := s.historyLocked()
+	s.mu.RUnlock()
+
+	if len(history) <= keep || (opts.MaxMessages > 0 && len(history) < opts.MaxMessages) {
+		return nil, nil
+	}
+	older := history[:len(history)-keep]
+
+	checkpoint, err := s.Checkpoint(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("checkpoint before summarize: %w", err)
+	}
+
+	var transcript strings.Builder
+	replaces := make([]string, 0, len(older))
+	for _, item := range older {
+		fmt.Fprintf(&transcript, "%s: %s\n", item.msg.Type, payloadText(item.msg))
+		replaces = append(replaces, item.entryID)
+	}
+
+	resp, err := p.CreateCompletion(ctx, provider.CompletionRequest{
+		Model: opts.Model,
+		Messages: []provider.Message{
+			{Role: "system", Content: summaryPrompt},
+			{Role: "user", Content: transcript.String()},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("generate summary: %w", err)
+	}
+
+	summary := agent.NewMessage(MessageTypeSummary, strings.TrimSpace(resp.Content))
+	summary.Metadata["summarized_messages"] = len(older)
+	if opts.Model != "" {
+		summary.Metadata[MetadataKeyModel] = opts.Model
+	}
+	summary.Metadata[MetadataKeyInputTokens] = resp.Usage.PromptTokens
+	summary.Metadata[MetadataKeyOutputTokens] = resp.Usage.CompletionTokens
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var parentID string
+	if len(s.entries) > 0 {
+		parentID = s.entries[len(s.entries)-1].ID
+	}
+
+	entry := &SessionEntry{
+		ID:        uuid.New().String(),
+		ParentID:  parentID,
+		Timestamp: time.Now().UTC(),
+		Type:      EntryTypeSummary,
+		Data: map[string]any{
+			"message":  messageToData(summary),
+			"replaces": replaces,
+		},
+	}
+
+	if err := s.backend.AppendEntry(ctx, s.meta.ID, entry); err != nil {
+		return nil, fmt.Errorf("append summary: %w", err)
+	}
+
+	s.entries = append(s.entries, entry)
+	s.meta.MessageCount = len(s.historyLocked())
+	s.meta.UpdatedAt = time.Now().UTC()
+	s.meta.CurrentLeaf = entry.ID
+	s.dirty = true
+
+	if err := s.backend.SaveSession(ctx, s.meta); err != nil {
+		return nil, fmt.Errorf("save session metadata: %w", err)
+	}
+
+	return checkpoint, nil
+}
+
+// payloadText returns a message payload as plain text, unwrapping JSON strings.
+func payloadText(msg *agent.Message) string {
+	var text string
+	if err := json.Unmarshal([]byte(msg.Payload), &text); err == nil {
+		return text
+	}
+	return msg.Payload
+}
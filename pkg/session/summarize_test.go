@@ -0,0 +1,162 @@
+package session
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+)
+
+func newSummarizeSession(t *testing.T, messages int) (Manager, Session) {
+	t.Helper()
+
+	backend, err := NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	t.Cleanup(func() { _ = backend.Close() })
+
+	mgr := NewManager(backend)
+	ctx := context.Background()
+
+	sess, err := mgr.Create(ctx, "test-agent", CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	for i := 0; i < messages; i++ {
+		if err := sess.AppendMessage(ctx, agent.NewMessage("user", fmt.Sprintf("message %d", i))); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+	return mgr, sess
+}
+
+func TestSessionSummarize(t *testing.T) {
+	mgr, sess := newSummarizeSession(t, 8)
+	ctx := context.Background()
+
+	mock := provider.NewMockProvider("mock")
+	mock.AddCompletionResponse(provider.MockCompletionResponse("the user counted to five"))
+
+	checkpoint, err := sess.Summarize(ctx, mock, SummarizeOptions{KeepRecent: 3})
+	if err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	if checkpoint == nil {
+		t.Fatal("Summarize() returned no checkpoint")
+	}
+
+	// The oldest messages were sent to the provider
+	if len(mock.CompletionCalls) != 1 {
+		t.Fatalf("provider called %d times, want 1", len(mock.CompletionCalls))
+	}
+	transcript := mock.CompletionCalls[0].Messages[1].Content
+	if !strings.Contains(transcript, "message 0") || strings.Contains(transcript, "message 5") {
+		t.Errorf("transcript = %q, want messages 0-4 only", transcript)
+	}
+
+	assertHistory := func(s Session) {
+		t.Helper()
+		messages, err := s.GetMessages(ctx)
+		if err != nil {
+			t.Fatalf("GetMessages() error = %v", err)
+		}
+		if len(messages) != 4 {
+			t.Fatalf("GetMessages() returned %d messages, want 4", len(messages))
+		}
+		if messages[0].Type != MessageTypeSummary || payloadText(messages[0]) != "the user counted to five" {
+			t.Errorf("first message = %s %q, want the summary", messages[0].Type, messages[0].Payload)
+		}
+		if payloadText(messages[1]) != "message 5" || payloadText(messages[3]) != "message 7" {
+			t.Errorf("recent messages = %q..%q, want message 5..message 7", messages[1].Payload, messages[3].Payload)
+		}
+	}
+	assertHistory(sess)
+
+	// The summary survives reloading from storage
+	reloaded, err := mgr.Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	assertHistory(reloaded)
+
+	// Restoring the checkpoint brings back the original messages
+	if err := sess.Restore(ctx, checkpoint.ID); err != nil {
+		t.Fatalf("Restore() error = %v", err)
+	}
+	messages, err := sess.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("GetMessages() error = %v", err)
+	}
+	if len(messages) != 8 {
+		t.Errorf("GetMessages() after Restore() returned %d messages, want 8", len(messages))
+	}
+}
+
+func TestSessionSummarizeRepeated(t *testing.T) {
+	_, sess := newSummarizeSession(t, 6)
+	ctx := context.Background()
+
+	mock := provider.NewMockProvider("mock")
+	mock.AddCompletionResponse(provider.MockCompletionResponse("first summary"))
+	mock.AddCompletionResponse(provider.MockCompletionResponse("second summary"))
+
+	opts := SummarizeOptions{KeepRecent: 2}
+	if _, err := sess.Summarize(ctx, mock, opts); err != nil {
+		t.Fatalf("Summarize() error = %v", err)
+	}
+	for i := 0; i < 3; i++ {
+		if err := sess.AppendMessage(ctx, agent.NewMessage("user", "more")); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+	if _, err := sess.Summarize(ctx, mock, opts); err != nil {
+		t.Fatalf("second Summarize() error = %v", err)
+	}
+
+	// The earlier summary is folded into the new one
+	if !strings.Contains(mock.CompletionCalls[1].Messages[1].Content, "summary: first summary") {
+		t.Errorf("second transcript = %q, want the first summary", mock.CompletionCalls[1].Messages[1].Content)
+	}
+
+	messages, err := sess.GetMessages(ctx)
+	if err != nil {
+		t.Fatalf("GetMessages() error = %v", err)
+	}
+	if len(messages) != 3 || payloadText(messages[0]) != "second summary" {
+		t.Errorf("GetMessages() = %d messages starting %q, want 3 starting with the second summary", len(messages), messages[0].Payload)
+	}
+}
+
+func TestSessionSummarizeShortHistory(t *testing.T) {
+	_, sess := newSummarizeSession(t, 5)
+	ctx := context.Background()
+	mock := provider.NewMockProvider("mock")
+
+	tests := []struct {
+		name string
+		opts SummarizeOptions
+	}{
+		{"within keep recent", SummarizeOptions{KeepRecent: 5}},
+		{"below max messages", SummarizeOptions{KeepRecent: 2, MaxMessages: 10}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			checkpoint, err := sess.Summarize(ctx, mock, tt.opts)
+			if err != nil {
+				t.Fatalf("Summarize() error = %v", err)
+			}
+			if checkpoint != nil {
+				t.Error("Summarize() summarized a short history")
+			}
+		})
+	}
+
+	if len(mock.CompletionCalls) != 0 {
+		t.Errorf("provider called %d times, want 0", len(mock.CompletionCalls))
+	}
+}
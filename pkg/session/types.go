@@ -17,6 +17,8 @@ const (
 	EntryTypeCheckpoint EntryType = "checkpoint"
 	// EntryTypeMetadata represents session metadata updates.
 	EntryTypeMetadata EntryType = "metadata"
+	// EntryTypeSummary replaces earlier messages with a summary message.
+	EntryTypeSummary EntryType = "summary"
 )
 
 // SessionEntry represents a single entry in the session log.
@@ -51,6 +53,11 @@ type SessionMetadata struct {
 	MessageCount int `json:"messageCount"`
 	// CurrentLeaf is the ID of the current leaf entry (for future branching).
 	CurrentLeaf string `json:"currentLeaf,omitempty"`
+	// AutoCheckpointEvery is the number of appended messages between
+	// automatic checkpoints (0 disables them).
+	AutoCheckpointEvery int `json:"autoCheckpointEvery,omitempty"`
+	// MaxCheckpoints caps the automatic checkpoints kept (0 keeps all).
+	MaxCheckpoints int `json:"maxCheckpoints,omitempty"`
 }
 
 // Checkpoint represents a restorable state snapshot.
@@ -0,0 +1,147 @@
+package session
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
+)
+
+// Metadata keys for per-turn usage on session messages. They are the keys
+// agents report usage under on their responses.
+const (
+	MetadataKeyModel        = agent.MetadataKeyModel
+	MetadataKeyInputTokens  = agent.MetadataKeyInputTokens
+	MetadataKeyOutputTokens = agent.MetadataKeyOutputTokens
+	MetadataKeyTotalTokens  = agent.MetadataKeyTotalTokens
+	MetadataKeyCostUSD      = agent.MetadataKeyCostUSD
+)
+
+// Usage is the token and cost usage recorded across a session.
+type Usage struct {
+	// SessionID is the session the report covers.
+	SessionID string `json:"sessionId"`
+	// UserID is the session's user, for per-user attribution.
+	UserID string `json:"userId,omitempty"`
+	// InputTokens is the total number of prompt tokens.
+	InputTokens int `json:"inputTokens"`
+	// OutputTokens is the total number of completion tokens.
+	OutputTokens int `json:"outputTokens"`
+	// TotalTokens is the total number of tokens.
+	TotalTokens int `json:"totalTokens"`
+	// CostUSD is the total cost of turns with known pricing.
+	CostUSD float64 `json:"costUsd"`
+	// Turns is the number of messages that reported usage.
+	Turns int `json:"turns"`
+	// ByModel breaks usage down by the model each turn reported.
+	ByModel map[string]*ModelUsage `json:"byModel,omitempty"`
+}
+
+// ModelUsage is the usage attributed to a single model.
+type ModelUsage struct {
+	InputTokens  int     `json:"inputTokens"`
+	OutputTokens int     `json:"outputTokens"`
+	TotalTokens  int     `json:"totalTokens"`
+	CostUSD      float64 `json:"costUsd"`
+	Turns        int     `json:"turns"`
+}
+
+// UsageReport sums the usage recorded in message metadata across the
+// session's history. Turns that report tokens and a model but no cost are
+// priced with cost.DefaultCalculator.
+func UsageReport(ctx context.Context, sess Session) (*Usage, error) {
+	messages, err := sess.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get messages: %w", err)
+	}
+
+	report := &Usage{
+		SessionID: sess.ID(),
+		UserID:    sess.UserID(),
+		ByModel:   make(map[string]*ModelUsage),
+	}
+
+	for _, msg := range messages {
+		if msg == nil || msg.Metadata == nil {
+			continue
+		}
+		md := msg.Metadata
+
+		in, hasIn := metadataInt(md, MetadataKeyInputTokens)
+		out, hasOut := metadataInt(md, MetadataKeyOutputTokens)
+		total, hasTotal := metadataInt(md, MetadataKeyTotalTokens)
+		usd, hasCost := agent.MetadataFloat(md, MetadataKeyCostUSD)
+		if !hasIn && !hasOut && !hasTotal && !hasCost {
+			continue
+		}
+		if !hasTotal {
+			total = in + out
+		}
+
+		model, _ := md[MetadataKeyModel].(string)
+		if !hasCost && model != "" {
+			if c, err := cost.DefaultCalculator.EstimateCost(model, in, out); err == nil {
+				usd = c.TotalCost
+			}
+		}
+
+		report.InputTokens += in
+		report.OutputTokens += out
+		report.TotalTokens += total
+		report.CostUSD += usd
+		report.Turns++
+
+		if model != "" {
+			mu, ok := report.ByModel[model]
+			if !ok {
+				mu = &ModelUsage{}
+				report.ByModel[model] = mu
+			}
+			mu.InputTokens += in
+			mu.OutputTokens += out
+			mu.TotalTokens += total
+			mu.CostUSD += usd
+			mu.Turns++
+		}
+	}
+
+	return report, nil
+}
+
+// StampUsage records the usage tracked for one turn in msg's metadata.
+// Messages that already report their own usage are left unchanged, as are
+// turns that made no tracked LLM calls.
+func StampUsage(msg *agent.Message, tracker *cost.Tracker) {
+	if msg == nil || tracker == nil || tracker.Calls() == 0 {
+		return
+	}
+
+	if msg.Metadata == nil {
+		msg.Metadata = make(map[string]any)
+	}
+	for _, key := range []string{MetadataKeyInputTokens, MetadataKeyOutputTokens, MetadataKeyTotalTokens, MetadataKeyCostUSD} {
+		if _, ok := msg.Metadata[key]; ok {
+			return
+		}
+	}
+
+	usage := tracker.Usage()
+	if usage.Model != "" {
+		if _, ok := msg.Metadata[MetadataKeyModel]; !ok {
+			msg.Metadata[MetadataKeyModel] = usage.Model
+		}
+	}
+	msg.Metadata[MetadataKeyInputTokens] = usage.InputTokens
+	msg.Metadata[MetadataKeyOutputTokens] = usage.OutputTokens
+	msg.Metadata[MetadataKeyTotalTokens] = usage.TotalTokens
+	// Leave cost unset when no call could be priced so reports can price it later
+	if usd := tracker.CostUSD(); usd > 0 {
+		msg.Metadata[MetadataKeyCostUSD] = usd
+	}
+}
+
+func metadataInt(md map[string]any, key string) (int, bool) {
+	f, ok := agent.MetadataFloat(md, key)
+	return int(f), ok
+}
@@ -0,0 +1,128 @@
+package session
+
+import (
+	"context"
+	"math"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/agent"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
+)
+
+func TestUsageReport(t *testing.T) {
+	tmpDir := t.TempDir()
+	backend, err := NewFileBackend(tmpDir)
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+
+	mgr := NewManager(backend)
+	ctx := context.Background()
+
+	sess, err := mgr.Create(ctx, "test-agent", CreateOptions{UserID: "user-1"})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	turn := func(md map[string]any) *agent.Message {
+		msg := agent.NewMessage("assistant", "reply")
+		for k, v := range md {
+			msg.Metadata[k] = v
+		}
+		return msg
+	}
+
+	messages := []*agent.Message{
+		agent.NewMessage("user", "hello"),
+		// Cost reported by the agent
+		turn(map[string]any{
+			MetadataKeyModel:        "gpt-4o",
+			MetadataKeyInputTokens:  100,
+			MetadataKeyOutputTokens: 50,
+			MetadataKeyCostUSD:      0.5,
+		}),
+		// Tokens only, priced from the default calculator
+		turn(map[string]any{
+			MetadataKeyModel:        "gpt-4o-mini",
+			MetadataKeyInputTokens:  1_000_000,
+			MetadataKeyOutputTokens: 0,
+		}),
+		// No model, attributed to the session total only
+		turn(map[string]any{
+			MetadataKeyInputTokens: 5,
+			MetadataKeyTotalTokens: 7,
+		}),
+	}
+	for _, msg := range messages {
+		if err := sess.AppendMessage(ctx, msg); err != nil {
+			t.Fatalf("AppendMessage() error = %v", err)
+		}
+	}
+
+	// Reload so metadata goes through storage decoding
+	reloaded, err := NewManager(backend).Get(ctx, sess.ID())
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+
+	report, err := UsageReport(ctx, reloaded)
+	if err != nil {
+		t.Fatalf("UsageReport() error = %v", err)
+	}
+
+	if report.SessionID != sess.ID() || report.UserID != "user-1" {
+		t.Errorf("report identifies %q/%q, want %q/user-1", report.SessionID, report.UserID, sess.ID())
+	}
+	if report.Turns != 3 {
+		t.Errorf("Turns = %d, want 3", report.Turns)
+	}
+	if report.InputTokens != 1_000_105 || report.OutputTokens != 50 || report.TotalTokens != 1_000_157 {
+		t.Errorf("tokens = %d in / %d out / %d total, want 1000105 / 50 / 1000157",
+			report.InputTokens, report.OutputTokens, report.TotalTokens)
+	}
+	if math.Abs(report.CostUSD-0.65) > 1e-9 {
+		t.Errorf("CostUSD = %f, want 0.65", report.CostUSD)
+	}
+	if len(report.ByModel) != 2 {
+		t.Fatalf("ByModel has %d models, want 2", len(report.ByModel))
+	}
+	if mini := report.ByModel["gpt-4o-mini"]; mini == nil || math.Abs(mini.CostUSD-0.15) > 1e-9 {
+		t.Errorf("ByModel[gpt-4o-mini] = %+v, want cost 0.15", mini)
+	}
+}
+
+func TestStampUsage(t *testing.T) {
+	tracker := cost.NewTracker()
+
+	// NewMessage sets only the request ID
+	msg := agent.NewMessage("assistant", "reply")
+	StampUsage(msg, tracker)
+	if len(msg.Metadata) != 1 {
+		t.Errorf("StampUsage() with no calls set metadata %v", msg.Metadata)
+	}
+
+	tracker.Record(&cost.Usage{Model: "gpt-4o", InputTokens: 10, OutputTokens: 5}, &cost.Cost{TotalCost: 0.25})
+	StampUsage(msg, tracker)
+
+	want := map[string]any{
+		MetadataKeyModel:        "gpt-4o",
+		MetadataKeyInputTokens:  10,
+		MetadataKeyOutputTokens: 5,
+		MetadataKeyTotalTokens:  15,
+		MetadataKeyCostUSD:      0.25,
+	}
+	for k, v := range want {
+		if msg.Metadata[k] != v {
+			t.Errorf("Metadata[%s] = %v, want %v", k, msg.Metadata[k], v)
+		}
+	}
+
+	// Agent-reported usage takes precedence
+	reported := agent.NewMessage("assistant", "reply")
+	reported.Metadata[MetadataKeyCostUSD] = 1.0
+	StampUsage(reported, tracker)
+	if _, ok := reported.Metadata[MetadataKeyInputTokens]; ok {
+		t.Error("StampUsage() overwrote usage reported by the agent")
+	}
+}
@@ -5,10 +5,17 @@ package tools
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
+
+	"github.com/aixgo-dev/aixgo/internal/llm"
 )
 
+// ErrInvalidArguments is returned by Invoke when arguments are not a JSON
+// object matching the tool's schema.
+var ErrInvalidArguments = errors.New("invalid tool arguments")
+
 // Tool represents a callable tool.
 type Tool struct {
 	Name                 string          `json:"name"`
@@ -16,11 +23,18 @@ type Tool struct {
 	Parameters           json.RawMessage `json:"parameters"` // JSON Schema
 	Handler              ToolHandler     `json:"-"`
 	RequiresConfirmation bool            `json:"requires_confirmation"`
+
+	// fn is set for tools registered with RegisterFunc
+	fn FuncHandler
 }
 
 // ToolHandler is a function that handles tool invocations.
 type ToolHandler func(ctx context.Context, args map[string]any) (any, error)
 
+// FuncHandler is an in-process tool implemented as a Go function taking and
+// returning raw JSON.
+type FuncHandler func(ctx context.Context, args json.RawMessage) (json.RawMessage, error)
+
 // ConfirmationHandler handles confirmation prompts for tools that require user approval.
 type ConfirmationHandler interface {
 	Confirm(ctx context.Context, tool *Tool, args map[string]any) (bool, error)
@@ -54,6 +68,47 @@ func (r *Registry) Register(tool *Tool) {
 	r.tools[tool.Name] = tool
 }
 
+// RegisterFunc registers fn as a tool, without the need for an MCP server.
+// schema is the JSON Schema of its arguments, which Invoke and Call validate
+// before calling fn; a nil schema accepts any object. When called with Call,
+// its result is decoded from JSON.
+func (r *Registry) RegisterFunc(name string, schema json.RawMessage, fn FuncHandler) error {
+	if name == "" {
+		return fmt.Errorf("tool name is required")
+	}
+	if fn == nil {
+		return fmt.Errorf("tool %s: handler is required", name)
+	}
+	if schema != nil {
+		var s map[string]any
+		if err := json.Unmarshal(schema, &s); err != nil {
+			return fmt.Errorf("tool %s: invalid schema: %w", name, err)
+		}
+	}
+
+	r.Register(&Tool{
+		Name:       name,
+		Parameters: schema,
+		fn:         fn,
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			raw, err := json.Marshal(args)
+			if err != nil {
+				return nil, fmt.Errorf("marshal arguments: %w", err)
+			}
+			out, err := fn(ctx, raw)
+			if err != nil || len(out) == 0 {
+				return nil, err
+			}
+			var result any
+			if err := json.Unmarshal(out, &result); err != nil {
+				return nil, fmt.Errorf("decode result: %w", err)
+			}
+			return result, nil
+		},
+	})
+	return nil
+}
+
 // Get retrieves a tool by name.
 func (r *Registry) Get(name string) (*Tool, bool) {
 	r.mu.RLock()
@@ -85,20 +140,96 @@ func (r *Registry) Call(ctx context.Context, name string, args map[string]any) (
 		return nil, fmt.Errorf("tool has no handler: %s", name)
 	}
 
-	// Check if confirmation is required
-	if tool.RequiresConfirmation && r.confirmationHandler != nil {
-		confirmed, err := r.confirmationHandler.Confirm(ctx, tool, args)
-		if err != nil {
-			return nil, fmt.Errorf("confirmation failed: %w", err)
-		}
-		if !confirmed {
-			return nil, fmt.Errorf("tool execution cancelled by user")
+	// Function tools are validated on every path; other tools have
+	// historically been called with unchecked arguments
+	if tool.fn != nil {
+		if err := validateArgs(tool, args); err != nil {
+			return nil, err
 		}
 	}
 
+	if err := r.confirm(ctx, tool, args); err != nil {
+		return nil, err
+	}
+
 	return tool.Handler(ctx, args)
 }
 
+// Invoke calls a tool with JSON arguments, validating them against the
+// tool's schema first, and returns its result as JSON.
+func (r *Registry) Invoke(ctx context.Context, name string, args json.RawMessage) (json.RawMessage, error) {
+	tool, ok := r.Get(name)
+	if !ok {
+		return nil, fmt.Errorf("tool not found: %s", name)
+	}
+
+	if len(args) == 0 {
+		args = json.RawMessage("{}")
+	}
+	var input map[string]any
+	if err := json.Unmarshal(args, &input); err != nil || input == nil {
+		return nil, fmt.Errorf("%w for %s: want a JSON object", ErrInvalidArguments, name)
+	}
+	if err := validateArgs(tool, input); err != nil {
+		return nil, err
+	}
+
+	if tool.fn != nil {
+		if err := r.confirm(ctx, tool, input); err != nil {
+			return nil, err
+		}
+		return tool.fn(ctx, args)
+	}
+
+	result, err := r.Call(ctx, name, input)
+	if err != nil {
+		return nil, err
+	}
+	out, err := json.Marshal(result)
+	if err != nil {
+		return nil, fmt.Errorf("marshal result: %w", err)
+	}
+	return out, nil
+}
+
+// validateArgs checks args against the tool's schema, if it has one
+func validateArgs(tool *Tool, args map[string]any) error {
+	if tool.Parameters == nil {
+		return nil
+	}
+	var schema map[string]any
+	if err := json.Unmarshal(tool.Parameters, &schema); err != nil {
+		return fmt.Errorf("tool %s: invalid schema: %w", tool.Name, err)
+	}
+	if args == nil {
+		args = map[string]any{}
+	}
+	if err := llm.NewValidator(schema).Validate(args); err != nil {
+		return fmt.Errorf("%w for %s: %v", ErrInvalidArguments, tool.Name, err)
+	}
+	return nil
+}
+
+// confirm asks the confirmation handler, if any, to approve a tool call
+// that requires confirmation.
+func (r *Registry) confirm(ctx context.Context, tool *Tool, args map[string]any) error {
+	r.mu.RLock()
+	handler := r.confirmationHandler
+	r.mu.RUnlock()
+
+	if !tool.RequiresConfirmation || handler == nil {
+		return nil
+	}
+	confirmed, err := handler.Confirm(ctx, tool, args)
+	if err != nil {
+		return fmt.Errorf("confirmation failed: %w", err)
+	}
+	if !confirmed {
+		return fmt.Errorf("tool execution cancelled by user")
+	}
+	return nil
+}
+
 // ToMCPTools converts tools to MCP-compatible format.
 func (r *Registry) ToMCPTools() []map[string]any {
 	r.mu.RLock()
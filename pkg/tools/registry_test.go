@@ -0,0 +1,113 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+const calculatorSchema = `{
+	"type": "object",
+	"properties": {
+		"op": {"type": "string"},
+		"a":  {"type": "number"},
+		"b":  {"type": "number"}
+	},
+	"required": ["op", "a", "b"]
+}`
+
+func calculator(ctx context.Context, args json.RawMessage) (json.RawMessage, error) {
+	var in struct {
+		Op string  `json:"op"`
+		A  float64 `json:"a"`
+		B  float64 `json:"b"`
+	}
+	if err := json.Unmarshal(args, &in); err != nil {
+		return nil, err
+	}
+
+	var result float64
+	switch in.Op {
+	case "add":
+		result = in.A + in.B
+	case "mul":
+		result = in.A * in.B
+	default:
+		return nil, fmt.Errorf("unsupported op %q", in.Op)
+	}
+	return json.Marshal(map[string]float64{"result": result})
+}
+
+func TestRegistryInvoke(t *testing.T) {
+	ctx := context.Background()
+	reg := NewRegistry()
+	if err := reg.RegisterFunc("calculator", json.RawMessage(calculatorSchema), calculator); err != nil {
+		t.Fatalf("RegisterFunc() error = %v", err)
+	}
+
+	out, err := reg.Invoke(ctx, "calculator", json.RawMessage(`{"op":"add","a":2,"b":3}`))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if string(out) != `{"result":5}` {
+		t.Errorf("Invoke() = %s, want {\"result\":5}", out)
+	}
+
+	invalid := map[string]string{
+		"wrong type":       `{"op":"add","a":"two","b":3}`,
+		"missing required": `{"op":"add","a":2}`,
+		"unknown field":    `{"op":"add","a":2,"b":3,"c":4}`,
+		"not an object":    `[1,2]`,
+	}
+	for name, args := range invalid {
+		if _, err := reg.Invoke(ctx, "calculator", json.RawMessage(args)); !errors.Is(err, ErrInvalidArguments) {
+			t.Errorf("%s: Invoke() error = %v, want ErrInvalidArguments", name, err)
+		}
+	}
+
+	if _, err := reg.Invoke(ctx, "calculator", json.RawMessage(`{"op":"pow","a":2,"b":3}`)); err == nil {
+		t.Error("Invoke() should return the handler's error")
+	}
+	if _, err := reg.Invoke(ctx, "missing", nil); err == nil {
+		t.Error("Invoke() of an unregistered tool should fail")
+	}
+
+	// The same tool is callable with map arguments
+	result, err := reg.Call(ctx, "calculator", map[string]any{"op": "mul", "a": 4.0, "b": 2.5})
+	if err != nil {
+		t.Fatalf("Call() error = %v", err)
+	}
+	if got := result.(map[string]any)["result"]; got != 10.0 {
+		t.Errorf("Call() result = %v, want 10", got)
+	}
+	if _, err := reg.Call(ctx, "calculator", map[string]any{"op": "add", "a": "two", "b": 3.0}); !errors.Is(err, ErrInvalidArguments) {
+		t.Errorf("Call() with invalid arguments error = %v, want ErrInvalidArguments", err)
+	}
+	if _, err := reg.Call(ctx, "calculator", nil); !errors.Is(err, ErrInvalidArguments) {
+		t.Errorf("Call() with no arguments error = %v, want ErrInvalidArguments", err)
+	}
+}
+
+func TestRegistryInvokeHandlerTool(t *testing.T) {
+	reg := NewRegistry()
+	reg.Register(&Tool{
+		Name: "echo",
+		Handler: func(ctx context.Context, args map[string]any) (any, error) {
+			return args, nil
+		},
+	})
+
+	out, err := reg.Invoke(context.Background(), "echo", json.RawMessage(`{"text":"hi"}`))
+	if err != nil {
+		t.Fatalf("Invoke() error = %v", err)
+	}
+	if string(out) != `{"text":"hi"}` {
+		t.Errorf("Invoke() = %s", out)
+	}
+
+	if err := reg.RegisterFunc("bad", json.RawMessage(`not json`), calculator); err == nil {
+		t.Error("RegisterFunc() should reject an invalid schema")
+	}
+}
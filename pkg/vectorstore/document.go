@@ -2,6 +2,7 @@ package vectorstore
 
 import (
 	"encoding/base64"
+	"errors"
 	"fmt"
 	"time"
 )
@@ -49,6 +50,12 @@ type Document struct {
 	// Keys should be alphanumeric with underscores (no special chars).
 	Metadata map[string]any
 
+	// Version is the stored revision of the document, used for optimistic
+	// concurrency with Collection.UpsertIfVersion. Backends that support
+	// versioning increment it on every write, ignoring the value passed in;
+	// zero means the document has never been stored.
+	Version int64
+
 	// Score is the similarity score (populated during queries).
 	// Not stored, only returned in query results.
 	Score float32 `json:"-"`
@@ -148,7 +155,8 @@ func NewVideoContent(data []byte, mimeType string) *Content {
 }
 
 // String returns a string representation of the content.
-// For text, returns the text. For binary, returns a summary.
+// For text, returns the text. For binary, returns a placeholder naming the
+// type, MIME type, and size, e.g. "[image image/png: 2048 bytes]".
 func (c *Content) String() string {
 	if c == nil {
 		return ""
@@ -158,13 +166,17 @@ func (c *Content) String() string {
 	case ContentTypeText:
 		return c.Text
 	case ContentTypeImage, ContentTypeAudio, ContentTypeVideo:
+		kind := string(c.Type)
+		if c.MimeType != "" {
+			kind += " " + c.MimeType
+		}
 		if c.URL != "" {
-			return fmt.Sprintf("[%s: %s]", c.Type, c.URL)
+			return fmt.Sprintf("[%s: %s]", kind, c.URL)
 		}
 		if len(c.Data) > 0 {
-			return fmt.Sprintf("[%s: %d bytes]", c.Type, len(c.Data))
+			return fmt.Sprintf("[%s: %d bytes]", kind, len(c.Data))
 		}
-		return fmt.Sprintf("[%s]", c.Type)
+		return fmt.Sprintf("[%s]", kind)
 	default:
 		return fmt.Sprintf("[%s]", c.Type)
 	}
@@ -324,6 +336,42 @@ func (s *Scope) Match(other *Scope) bool {
 	return true
 }
 
+// Satisfies reports whether a document with this scope passes filter.
+// Unlike Match, it is strict: every non-empty field of filter, including
+// each Custom entry, must equal the document's value, so an unscoped
+// document never passes a tenant filter.
+func (s *Scope) Satisfies(filter *Scope) bool {
+	if filter == nil {
+		return true
+	}
+	if s == nil {
+		s = &Scope{}
+	}
+
+	if filter.Tenant != "" && s.Tenant != filter.Tenant {
+		return false
+	}
+	if filter.User != "" && s.User != filter.User {
+		return false
+	}
+	if filter.Session != "" && s.Session != filter.Session {
+		return false
+	}
+	if filter.Agent != "" && s.Agent != filter.Agent {
+		return false
+	}
+	if filter.Thread != "" && s.Thread != filter.Thread {
+		return false
+	}
+	for k, v := range filter.Custom {
+		if s.Custom[k] != v {
+			return false
+		}
+	}
+
+	return true
+}
+
 // Temporal contains time-related information for a document.
 // This enables TTL, time-based queries, and event ordering.
 type Temporal struct {
@@ -558,6 +606,32 @@ func ValidateEmbedding(e *Embedding) error {
 	return nil
 }
 
+// ErrDimensionMismatch is returned when an embedding's dimensions differ from
+// those of the collection it is stored in or queried against.
+var ErrDimensionMismatch = errors.New("embedding dimension mismatch")
+
+// ErrVersionConflict is returned by UpsertIfVersion when the stored version
+// of a document differs from the expected one.
+var ErrVersionConflict = errors.New("document version conflict")
+
+// ErrVersioningNotSupported is returned by UpsertIfVersion on backends that
+// cannot compare and write a document atomically.
+var ErrVersioningNotSupported = errors.New("versioned upsert not supported")
+
+// ErrHybridSearchNotSupported is returned by Query on backends that cannot
+// rank by Query.Keywords.
+var ErrHybridSearchNotSupported = errors.New("hybrid keyword search not supported")
+
+// CheckDimensions returns an error wrapping ErrDimensionMismatch if actual
+// differs from expected. An expected of 0 means the collection's dimensions
+// are not known yet, so any actual is accepted.
+func CheckDimensions(expected, actual int) error {
+	if expected > 0 && actual != expected {
+		return fmt.Errorf("%w: expected %d, got %d", ErrDimensionMismatch, expected, actual)
+	}
+	return nil
+}
+
 // ValidateMetadataKey validates a metadata key.
 func ValidateMetadataKey(key string) error {
 	if key == "" {
@@ -8,6 +8,7 @@ import (
 	"math"
 	"sort"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"cloud.google.com/go/firestore"
@@ -126,6 +127,7 @@ func (f *FirestoreVectorStore) Collection(name string, opts ...vectorstore.Colle
 		createdAt: time.Now(),
 		updatedAt: time.Now(),
 	}
+	coll.dimensions.Store(int64(config.EmbeddingDimensions))
 
 	f.collections[name] = coll
 	return coll
@@ -188,12 +190,14 @@ func (f *FirestoreVectorStore) Stats(ctx context.Context) (*vectorstore.StoreSta
 	defer f.mu.RUnlock()
 
 	var totalDocs int64
+	var metrics vectorstore.CollectionMetrics
 	for _, coll := range f.collections {
 		stats, err := coll.Stats(ctx)
 		if err != nil {
 			return nil, err
 		}
 		totalDocs += stats.Documents
+		metrics = metrics.Add(coll.Metrics())
 	}
 
 	return &vectorstore.StoreStats{
@@ -202,6 +206,7 @@ func (f *FirestoreVectorStore) Stats(ctx context.Context) (*vectorstore.StoreSta
 		StorageBytes: 0, // Firestore doesn't expose storage size
 		Provider:     "firestore",
 		Version:      "1.0.0",
+		Metrics:      metrics,
 		Extra: map[string]any{
 			"project_id": f.projectID,
 		},
@@ -221,9 +226,32 @@ type FirestoreCollection struct {
 	collRef   *firestore.CollectionRef
 	createdAt time.Time
 	updatedAt time.Time
+	metrics   vectorstore.MetricsRecorder
 	mu        sync.RWMutex
+
+	// nativeUnavailable is set once FindNearest fails for lack of a vector
+	// index, so later queries go straight to the in-memory scan.
+	nativeUnavailable atomic.Bool
+
+	// dimensions is the expected embedding size: configured, or learned from
+	// the first upsert or from stored documents (0 = not known yet).
+	dimensions atomic.Int64
 }
 
+const (
+	// embeddingField is the document field holding the embedding vector
+	embeddingField = "embedding"
+
+	// distanceResultField receives the distance computed by FindNearest
+	distanceResultField = "vector_distance"
+
+	// maxNativeVectorDimensions is the largest vector Firestore can index
+	maxNativeVectorDimensions = 2048
+
+	// maxNativeQueryLimit is the largest limit Firestore accepts for FindNearest
+	maxNativeQueryLimit = 1000
+)
+
 // firestoreDocument represents the structure of a document in Firestore.
 // All fields are stored at the top level for efficient querying.
 type firestoreDocument struct {
@@ -267,6 +295,9 @@ type firestoreDocument struct {
 
 	// Deduplication
 	ContentHash string `firestore:"content_hash,omitempty"`
+
+	// Optimistic concurrency
+	Version int64 `firestore:"version,omitempty"`
 }
 
 // Name returns the collection name.
@@ -289,12 +320,10 @@ func (c *FirestoreCollection) Upsert(ctx context.Context, documents ...*vectorst
 			return nil, fmt.Errorf("invalid document at index %d: %w", i, err)
 		}
 
-		// Validate embedding dimensions if configured
-		if c.config.EmbeddingDimensions > 0 && doc.Embedding != nil {
-			if doc.Embedding.Dimensions != c.config.EmbeddingDimensions {
-				return nil, fmt.Errorf("document %s embedding dimension mismatch: expected %d, got %d",
-					doc.ID, c.config.EmbeddingDimensions, doc.Embedding.Dimensions)
-			}
+		// Firestore vector indexes are limited to 2048 dimensions
+		if c.config.UseNativeVectorSearch && doc.Embedding != nil && len(doc.Embedding.Vector) > maxNativeVectorDimensions {
+			return nil, fmt.Errorf("document %s embedding has %d dimensions, native vector search supports at most %d",
+				doc.ID, len(doc.Embedding.Vector), maxNativeVectorDimensions)
 		}
 
 		// Validate required scope fields
@@ -302,11 +331,26 @@ func (c *FirestoreCollection) Upsert(ctx context.Context, documents ...*vectorst
 			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
 		}
 	}
-	validationTime := time.Since(validationStart)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	// Validate embedding dimensions before writing anything, learning them
+	// from the first embedding if not known
+	dimensions := int(c.dimensions.Load())
+	for _, doc := range documents {
+		if doc.Embedding == nil {
+			continue
+		}
+		if dimensions == 0 {
+			dimensions = doc.Embedding.Dimensions
+		}
+		if err := vectorstore.CheckDimensions(dimensions, doc.Embedding.Dimensions); err != nil {
+			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	validationTime := time.Since(validationStart)
+
 	storageStart := time.Now()
 	result := &vectorstore.UpsertResult{}
 
@@ -349,27 +393,20 @@ func (c *FirestoreCollection) Upsert(ctx context.Context, documents ...*vectorst
 		docSnap, err := docRef.Get(ctx)
 		exists := err == nil && docSnap.Exists()
 
-		// Set temporal information
-		now := time.Now()
-		if doc.Temporal == nil {
-			doc.Temporal = &vectorstore.Temporal{
-				CreatedAt: now,
-				UpdatedAt: now,
-			}
-		} else {
-			if doc.Temporal.CreatedAt.IsZero() {
-				doc.Temporal.CreatedAt = now
+		// Bump the stored version rather than trusting the caller's, so a
+		// stale copy cannot rewind it under UpsertIfVersion
+		var stored int64
+		if exists {
+			var current firestoreDocument
+			if err := docSnap.DataTo(&current); err == nil {
+				stored = current.Version
 			}
-			doc.Temporal.UpdatedAt = now
 		}
 
-		// Apply TTL if configured
-		if c.config.TTL > 0 && doc.Temporal.ExpiresAt == nil {
-			expiresAt := now.Add(c.config.TTL)
-			doc.Temporal.ExpiresAt = &expiresAt
-		}
+		c.setTemporal(doc)
 
 		// Convert to Firestore document
+		doc.Version = stored + 1
 		fsDoc := c.vectorstoreToFirestoreDoc(doc)
 
 		// Queue write
@@ -386,6 +423,7 @@ func (c *FirestoreCollection) Upsert(ctx context.Context, documents ...*vectorst
 
 	storageTime := time.Since(storageStart)
 
+	c.dimensions.CompareAndSwap(0, int64(dimensions))
 	c.updatedAt = time.Now()
 
 	result.Timing = &vectorstore.OperationTiming{
@@ -393,10 +431,118 @@ func (c *FirestoreCollection) Upsert(ctx context.Context, documents ...*vectorst
 		Validation: validationTime,
 		Storage:    storageTime,
 	}
+	c.metrics.RecordUpsert("firestore", c.name, int(result.Inserted+result.Updated), result.Timing.Total)
 
 	return result, nil
 }
 
+// UpsertIfVersion writes doc only if its stored version equals
+// expectedVersion, reading and writing the document in one transaction.
+func (c *FirestoreCollection) UpsertIfVersion(ctx context.Context, doc *vectorstore.Document, expectedVersion int64) (*vectorstore.UpsertResult, error) {
+	startTime := time.Now()
+
+	if err := vectorstore.Validate(doc); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if c.config.UseNativeVectorSearch && doc.Embedding != nil && len(doc.Embedding.Vector) > maxNativeVectorDimensions {
+		return nil, fmt.Errorf("document %s embedding has %d dimensions, native vector search supports at most %d",
+			doc.ID, len(doc.Embedding.Vector), maxNativeVectorDimensions)
+	}
+	if err := c.validateRequiredScope(doc); err != nil {
+		return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dimensions := int(c.dimensions.Load())
+	if doc.Embedding != nil {
+		if dimensions == 0 {
+			dimensions = doc.Embedding.Dimensions
+		}
+		if err := vectorstore.CheckDimensions(dimensions, doc.Embedding.Dimensions); err != nil {
+			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	validationTime := time.Since(startTime)
+
+	storageStart := time.Now()
+	c.setTemporal(doc)
+	fsDoc := c.vectorstoreToFirestoreDoc(doc)
+	fsDoc.Version = expectedVersion + 1
+
+	docRef := c.collRef.Doc(doc.ID)
+	var exists bool
+	err := c.client.RunTransaction(ctx, func(ctx context.Context, tx *firestore.Transaction) error {
+		exists = false
+		var stored int64
+		snap, err := tx.Get(docRef)
+		switch {
+		case status.Code(err) == codes.NotFound:
+		case err != nil:
+			return fmt.Errorf("failed to get document %s: %w", doc.ID, err)
+		default:
+			exists = true
+			var current firestoreDocument
+			if err := snap.DataTo(&current); err != nil {
+				return fmt.Errorf("failed to unmarshal document %s: %w", doc.ID, err)
+			}
+			stored = current.Version
+		}
+
+		if stored != expectedVersion {
+			return fmt.Errorf("%w: document %s is at version %d, expected %d",
+				vectorstore.ErrVersionConflict, doc.ID, stored, expectedVersion)
+		}
+		return tx.Set(docRef, fsDoc)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	doc.Version = fsDoc.Version
+	c.dimensions.CompareAndSwap(0, int64(dimensions))
+	c.updatedAt = time.Now()
+
+	result := &vectorstore.UpsertResult{}
+	if exists {
+		result.Updated++
+	} else {
+		result.Inserted++
+	}
+	result.Timing = &vectorstore.OperationTiming{
+		Total:      time.Since(startTime),
+		Validation: validationTime,
+		Storage:    time.Since(storageStart),
+	}
+	c.metrics.RecordUpsert("firestore", c.name, 1, result.Timing.Total)
+
+	return result, nil
+}
+
+// setTemporal fills in doc's creation and update times and applies the
+// collection TTL.
+func (c *FirestoreCollection) setTemporal(doc *vectorstore.Document) {
+	now := time.Now()
+	if doc.Temporal == nil {
+		doc.Temporal = &vectorstore.Temporal{
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	} else {
+		if doc.Temporal.CreatedAt.IsZero() {
+			doc.Temporal.CreatedAt = now
+		}
+		doc.Temporal.UpdatedAt = now
+	}
+
+	// Apply TTL if configured
+	if c.config.TTL > 0 && doc.Temporal.ExpiresAt == nil {
+		expiresAt := now.Add(c.config.TTL)
+		doc.Temporal.ExpiresAt = &expiresAt
+	}
+}
+
 // UpsertBatch performs batch upsert with progress tracking.
 func (c *FirestoreCollection) UpsertBatch(ctx context.Context, documents []*vectorstore.Document, opts ...vectorstore.BatchOption) (*vectorstore.UpsertResult, error) {
 	if len(documents) == 0 {
@@ -468,6 +614,9 @@ func (c *FirestoreCollection) Query(ctx context.Context, query *vectorstore.Quer
 	if err := query.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid query: %w", err)
 	}
+	if len(query.Keywords) > 0 {
+		return nil, fmt.Errorf("firestore: %w", vectorstore.ErrHybridSearchNotSupported)
+	}
 
 	startTime := time.Now()
 	timing := &vectorstore.QueryTiming{}
@@ -475,6 +624,10 @@ func (c *FirestoreCollection) Query(ctx context.Context, query *vectorstore.Quer
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	if err := c.checkQueryDimensions(query); err != nil {
+		return nil, err
+	}
+
 	// Start with base query
 	fsQuery := c.collRef.Query
 
@@ -483,6 +636,19 @@ func (c *FirestoreCollection) Query(ctx context.Context, query *vectorstore.Quer
 	fsQuery = c.applyFilters(fsQuery, query.Filters)
 	timing.FilterApplication = time.Since(filterStart)
 
+	// Use Firestore KNN when enabled; fall back to the scan if no index exists
+	if c.useNativeSearch(query) {
+		result, err := c.queryNative(ctx, fsQuery, query, timing, startTime)
+		if err == nil {
+			c.metrics.RecordQuery("firestore", c.name, timing.Total, len(result.Matches))
+			return result, nil
+		}
+		if status.Code(err) != codes.FailedPrecondition {
+			return nil, err
+		}
+		c.nativeUnavailable.Store(true)
+	}
+
 	// Execute query
 	retrievalStart := time.Now()
 	iter := fsQuery.Documents(ctx)
@@ -506,6 +672,20 @@ func (c *FirestoreCollection) Query(ctx context.Context, query *vectorstore.Quer
 	}
 	timing.Retrieval = time.Since(retrievalStart)
 
+	// Learn the dimensions from stored documents, which another process may
+	// have written, and check the query against them
+	if c.dimensions.Load() == 0 {
+		for _, fsDoc := range fsDocs {
+			if fsDoc.EmbeddingDimension > 0 {
+				c.dimensions.CompareAndSwap(0, int64(fsDoc.EmbeddingDimension))
+				break
+			}
+		}
+		if err := c.checkQueryDimensions(query); err != nil {
+			return nil, err
+		}
+	}
+
 	// Convert to vectorstore documents and calculate scores
 	scoringStart := time.Now()
 	var matches []*vectorstore.Match
@@ -568,10 +748,124 @@ func (c *FirestoreCollection) Query(ctx context.Context, query *vectorstore.Quer
 		Limit:   query.Limit,
 		Timing:  timing,
 	}
+	c.metrics.RecordQuery("firestore", c.name, timing.Total, len(matches))
 
 	return result, nil
 }
 
+// checkQueryDimensions returns an error wrapping
+// vectorstore.ErrDimensionMismatch if the query embedding does not match the
+// collection's dimensions.
+func (c *FirestoreCollection) checkQueryDimensions(query *vectorstore.Query) error {
+	if query.Embedding == nil {
+		return nil
+	}
+	if err := vectorstore.CheckDimensions(int(c.dimensions.Load()), len(query.Embedding.Vector)); err != nil {
+		return fmt.Errorf("query: %w", err)
+	}
+	return nil
+}
+
+// useNativeSearch reports whether query can be served by Firestore FindNearest.
+func (c *FirestoreCollection) useNativeSearch(query *vectorstore.Query) bool {
+	if !c.config.UseNativeVectorSearch || c.nativeUnavailable.Load() || query.Embedding == nil {
+		return false
+	}
+	_, ok := distanceMeasure(query.Metric)
+	return ok
+}
+
+// queryNative runs a server-side nearest-neighbor search with FindNearest.
+// Filters already applied to fsQuery become pre-filters, which Firestore
+// requires a composite vector index for.
+func (c *FirestoreCollection) queryNative(ctx context.Context, fsQuery firestore.Query, query *vectorstore.Query, timing *vectorstore.QueryTiming, startTime time.Time) (*vectorstore.QueryResult, error) {
+	vector := query.Embedding.Vector
+	measure, _ := distanceMeasure(query.Metric)
+
+	limit := query.Limit + query.Offset
+	if query.Limit <= 0 || limit > maxNativeQueryLimit {
+		limit = maxNativeQueryLimit
+	}
+
+	opts := &firestore.FindNearestOptions{DistanceResultField: distanceResultField}
+	if query.MinScore > 0 {
+		threshold := scoreToDistance(query.MinScore, query.Metric)
+		opts.DistanceThreshold = &threshold
+	}
+
+	retrievalStart := time.Now()
+	iter := fsQuery.FindNearest(embeddingField, firestore.Vector32(vector), limit, measure, opts).Documents(ctx)
+	defer iter.Stop()
+
+	scoringStart := time.Now()
+	var matches []*vectorstore.Match
+	for {
+		snap, err := iter.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to run vector query: %w", err)
+		}
+
+		var fsDoc firestoreDocument
+		if err := snap.DataTo(&fsDoc); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal document: %w", err)
+		}
+
+		raw, err := snap.DataAt(distanceResultField)
+		if err != nil {
+			return nil, fmt.Errorf("document %s missing vector distance: %w", snap.Ref.ID, err)
+		}
+		rawDistance, ok := raw.(float64)
+		if !ok {
+			return nil, fmt.Errorf("document %s has invalid vector distance %T", snap.Ref.ID, raw)
+		}
+
+		score, distance := nativeScore(rawDistance, query.Metric)
+		if query.MinScore > 0 && score < query.MinScore {
+			continue
+		}
+
+		matches = append(matches, &vectorstore.Match{
+			Document: c.firestoreToVectorstoreDoc(&fsDoc),
+			Score:    score,
+			Distance: distance,
+		})
+	}
+	timing.Retrieval = time.Since(retrievalStart)
+
+	// Firestore returns nearest first, but keep ordering identical to the scan path
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].Score > matches[j].Score
+	})
+	timing.Scoring = time.Since(scoringStart)
+
+	total := int64(len(matches))
+	if query.Offset >= len(matches) {
+		matches = nil
+	} else if query.Offset > 0 {
+		matches = matches[query.Offset:]
+	}
+	if query.Limit > 0 && query.Limit < len(matches) {
+		matches = matches[:query.Limit]
+	}
+
+	for i, match := range matches {
+		match.Rank = i + 1 + query.Offset
+	}
+
+	timing.Total = time.Since(startTime)
+
+	return &vectorstore.QueryResult{
+		Matches: matches,
+		Total:   total,
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Timing:  timing,
+	}, nil
+}
+
 // QueryStream performs similarity search and streams results via an iterator.
 func (c *FirestoreCollection) QueryStream(ctx context.Context, query *vectorstore.Query) (vectorstore.ResultIterator, error) {
 	// For Firestore, we materialize all results and return a slice iterator
@@ -747,6 +1041,11 @@ func (c *FirestoreCollection) Count(ctx context.Context, filter vectorstore.Filt
 	return countValue.GetIntegerValue(), nil
 }
 
+// Metrics returns the query and upsert metrics recorded by the collection.
+func (c *FirestoreCollection) Metrics() vectorstore.CollectionMetrics {
+	return c.metrics.Snapshot()
+}
+
 // Stats returns statistics about the collection.
 func (c *FirestoreCollection) Stats(ctx context.Context) (*vectorstore.CollectionStats, error) {
 	c.mu.RLock()
@@ -804,6 +1103,7 @@ func (c *FirestoreCollection) Clear(ctx context.Context) error {
 		}
 	}
 
+	c.dimensions.Store(int64(c.config.EmbeddingDimensions))
 	c.updatedAt = time.Now()
 	return nil
 }
@@ -910,6 +1210,15 @@ func (c *FirestoreCollection) applyFilters(query firestore.Query, filter vectors
 		if scope.Thread != "" {
 			query = query.Where("scope_thread", "==", scope.Thread)
 		}
+		// Sorted so the same filter always builds the same query
+		keys := make([]string, 0, len(scope.Custom))
+		for key := range scope.Custom {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			query = query.WherePath(firestore.FieldPath{"scope_custom", key}, "==", scope.Custom[key])
+		}
 		return query
 	}
 
@@ -996,6 +1305,7 @@ func (c *FirestoreCollection) applyTimeFilter(query firestore.Query, field vecto
 func (c *FirestoreCollection) vectorstoreToFirestoreDoc(doc *vectorstore.Document) *firestoreDocument {
 	fsDoc := &firestoreDocument{
 		ID:       doc.ID,
+		Version:  doc.Version,
 		Tags:     doc.Tags,
 		Metadata: make(map[string]interface{}),
 	}
@@ -1010,9 +1320,14 @@ func (c *FirestoreCollection) vectorstoreToFirestoreDoc(doc *vectorstore.Documen
 		fsDoc.ContentChunks = doc.Content.Chunks
 	}
 
-	// Convert embedding to Firestore vector type
+	// Convert embedding to Firestore vector type. FindNearest only considers
+	// fields stored as a native vector, so use one when native search is enabled.
 	if doc.Embedding != nil {
-		fsDoc.Embedding = float32SliceToFirestoreArray(doc.Embedding.Vector)
+		if c.config.UseNativeVectorSearch {
+			fsDoc.Embedding = firestore.Vector32(doc.Embedding.Vector)
+		} else {
+			fsDoc.Embedding = float32SliceToFirestoreArray(doc.Embedding.Vector)
+		}
 		fsDoc.EmbeddingModel = doc.Embedding.Model
 		fsDoc.EmbeddingDimension = doc.Embedding.Dimensions
 		fsDoc.EmbeddingNormalize = doc.Embedding.Normalized
@@ -1060,6 +1375,7 @@ func (c *FirestoreCollection) vectorstoreToFirestoreDoc(doc *vectorstore.Documen
 func (c *FirestoreCollection) firestoreToVectorstoreDoc(fsDoc *firestoreDocument) *vectorstore.Document {
 	doc := &vectorstore.Document{
 		ID:       fsDoc.ID,
+		Version:  fsDoc.Version,
 		Tags:     fsDoc.Tags,
 		Metadata: fsDoc.Metadata,
 	}
@@ -1183,6 +1499,18 @@ func extractEmbeddingFromFirestore(embedding interface{}) []float32 {
 		return slice
 	}
 
+	// Handle native Firestore vectors (decoded as Vector64 into interface{})
+	if vec, ok := embedding.(firestore.Vector64); ok {
+		result := make([]float32, len(vec))
+		for i, v := range vec {
+			result[i] = float32(v)
+		}
+		return result
+	}
+	if vec, ok := embedding.(firestore.Vector32); ok {
+		return []float32(vec)
+	}
+
 	return nil
 }
 
@@ -1190,9 +1518,11 @@ func extractEmbeddingFromFirestore(embedding interface{}) []float32 {
 func calculateContentHash(doc *vectorstore.Document) string {
 	h := sha256.New()
 
-	// Hash content
+	// Hash content, including binary data so distinct images of the same
+	// size do not collide
 	if doc.Content != nil {
 		h.Write([]byte(doc.Content.String()))
+		h.Write(doc.Content.Data)
 	}
 
 	// Hash embedding
@@ -1234,6 +1564,51 @@ func calculateSimilarity(vec1, vec2 []float32, metric vectorstore.DistanceMetric
 	}
 }
 
+// distanceMeasure maps a vectorstore metric to the Firestore distance measure.
+// It returns false for metrics Firestore cannot compute natively.
+func distanceMeasure(metric vectorstore.DistanceMetric) (firestore.DistanceMeasure, bool) {
+	switch metric {
+	case "", vectorstore.DistanceMetricCosine:
+		return firestore.DistanceMeasureCosine, true
+	case vectorstore.DistanceMetricEuclidean:
+		return firestore.DistanceMeasureEuclidean, true
+	case vectorstore.DistanceMetricDotProduct:
+		return firestore.DistanceMeasureDotProduct, true
+	default:
+		return 0, false
+	}
+}
+
+// nativeScore converts a FindNearest distance into the score and distance
+// calculateSimilarity would produce for the same vectors.
+func nativeScore(raw float64, metric vectorstore.DistanceMetric) (score float32, distance float32) {
+	switch metric {
+	case vectorstore.DistanceMetricEuclidean:
+		distance = float32(raw)
+		return 1.0 / (1.0 + distance), distance
+	case vectorstore.DistanceMetricDotProduct:
+		// Firestore reports the dot product itself; larger is more similar
+		score = float32(raw)
+		return score, -score
+	default:
+		// Firestore cosine distance is 1 - cosine similarity
+		distance = float32(raw)
+		return 1.0 - distance, distance
+	}
+}
+
+// scoreToDistance converts a minimum score into a FindNearest distance threshold.
+func scoreToDistance(minScore float32, metric vectorstore.DistanceMetric) float64 {
+	switch metric {
+	case vectorstore.DistanceMetricEuclidean:
+		return 1.0/float64(minScore) - 1.0
+	case vectorstore.DistanceMetricDotProduct:
+		return float64(minScore)
+	default:
+		return 1.0 - float64(minScore)
+	}
+}
+
 // cosineSimilarity calculates cosine similarity between two vectors.
 func cosineSimilarity(a, b []float32) float32 {
 	if len(a) != len(b) || len(a) == 0 {
@@ -1,9 +1,11 @@
 package firestore
 
 import (
+	"context"
 	"math"
 	"testing"
 
+	"cloud.google.com/go/firestore"
 	"cloud.google.com/go/firestore/apiv1/firestorepb"
 	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
 	"github.com/stretchr/testify/assert"
@@ -227,6 +229,16 @@ func TestExtractEmbeddingFromFirestore(t *testing.T) {
 			input:    []interface{}{1.0, 2.0, 3.0},
 			expected: []float32{1.0, 2.0, 3.0},
 		},
+		{
+			name:     "native vector64",
+			input:    firestore.Vector64{1.0, 2.0, 3.0},
+			expected: []float32{1.0, 2.0, 3.0},
+		},
+		{
+			name:     "native vector32",
+			input:    firestore.Vector32{1.0, 2.0, 3.0},
+			expected: []float32{1.0, 2.0, 3.0},
+		},
 	}
 
 	for _, tt := range tests {
@@ -237,6 +249,91 @@ func TestExtractEmbeddingFromFirestore(t *testing.T) {
 	}
 }
 
+// TestDistanceMeasure tests mapping vectorstore metrics to Firestore measures.
+func TestDistanceMeasure(t *testing.T) {
+	tests := []struct {
+		metric   vectorstore.DistanceMetric
+		expected firestore.DistanceMeasure
+		ok       bool
+	}{
+		{"", firestore.DistanceMeasureCosine, true},
+		{vectorstore.DistanceMetricCosine, firestore.DistanceMeasureCosine, true},
+		{vectorstore.DistanceMetricEuclidean, firestore.DistanceMeasureEuclidean, true},
+		{vectorstore.DistanceMetricDotProduct, firestore.DistanceMeasureDotProduct, true},
+		{vectorstore.DistanceMetricManhattan, 0, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			measure, ok := distanceMeasure(tt.metric)
+			assert.Equal(t, tt.ok, ok)
+			assert.Equal(t, tt.expected, measure)
+		})
+	}
+}
+
+// TestNativeScoreMatchesScan tests that FindNearest distances score the same as the in-memory scan.
+func TestNativeScoreMatchesScan(t *testing.T) {
+	query := []float32{1.0, 2.0, 3.0}
+	doc := []float32{2.0, 1.0, 0.5}
+
+	tests := []struct {
+		metric vectorstore.DistanceMetric
+		raw    float64 // distance as Firestore reports it
+	}{
+		{vectorstore.DistanceMetricCosine, float64(1 - cosineSimilarity(query, doc))},
+		{vectorstore.DistanceMetricEuclidean, float64(euclideanDistance(query, doc))},
+		{vectorstore.DistanceMetricDotProduct, float64(dotProduct(query, doc))},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.metric), func(t *testing.T) {
+			wantScore, wantDistance := calculateSimilarity(query, doc, tt.metric)
+			score, distance := nativeScore(tt.raw, tt.metric)
+			assert.InDelta(t, wantScore, score, 0.0001)
+			assert.InDelta(t, wantDistance, distance, 0.0001)
+		})
+	}
+}
+
+// TestScoreToDistance tests converting MinScore into a FindNearest threshold.
+func TestScoreToDistance(t *testing.T) {
+	metrics := []vectorstore.DistanceMetric{
+		vectorstore.DistanceMetricCosine,
+		vectorstore.DistanceMetricEuclidean,
+		vectorstore.DistanceMetricDotProduct,
+	}
+
+	for _, metric := range metrics {
+		t.Run(string(metric), func(t *testing.T) {
+			threshold := scoreToDistance(0.8, metric)
+			score, _ := nativeScore(threshold, metric)
+			assert.InDelta(t, 0.8, score, 0.0001)
+		})
+	}
+}
+
+// TestDimensionMismatch tests that mismatched embeddings are rejected before
+// Firestore is called.
+func TestDimensionMismatch(t *testing.T) {
+	ctx := context.Background()
+	coll := &FirestoreCollection{name: "test", config: vectorstore.ApplyOptions(nil)}
+	coll.dimensions.Store(384)
+
+	query := vectorstore.NewQuery(vectorstore.NewEmbedding(make([]float32, 1536), "test-model"))
+	_, err := coll.Query(ctx, query)
+	assert.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+	assert.Contains(t, err.Error(), "expected 384, got 1536")
+
+	doc := &vectorstore.Document{
+		ID:        "doc1",
+		Content:   vectorstore.NewTextContent("content"),
+		Embedding: vectorstore.NewEmbedding(make([]float32, 1536), "test-model"),
+	}
+	_, err = coll.Upsert(ctx, doc)
+	assert.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+}
+
 // BenchmarkCosineSimilarity benchmarks cosine similarity calculation.
 func BenchmarkCosineSimilarity(b *testing.B) {
 	vec1 := make([]float32, 768)
@@ -294,3 +391,42 @@ func BenchmarkFloat32SliceToFirestoreArray(b *testing.B) {
 		_ = float32SliceToFirestoreArray(slice)
 	}
 }
+
+// TestImageDocumentConversion tests that image content survives conversion to
+// and from the Firestore representation.
+func TestImageDocumentConversion(t *testing.T) {
+	c := &FirestoreCollection{config: &vectorstore.CollectionConfig{EnableDeduplication: true}}
+	png := []byte{0x89, 'P', 'N', 'G'}
+	doc := &vectorstore.Document{
+		ID:        "img1",
+		Content:   vectorstore.NewImageContent(png, "image/png"),
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0}, "clip-vit-base-patch32"),
+	}
+
+	fsDoc := c.vectorstoreToFirestoreDoc(doc)
+	assert.Equal(t, "image", fsDoc.ContentType)
+	assert.Equal(t, png, fsDoc.ContentData)
+	assert.Equal(t, "image/png", fsDoc.ContentMimeType)
+
+	other := vectorstore.NewImageContent([]byte{0x89, 'P', 'N', 'H'}, "image/png")
+	otherHash := c.vectorstoreToFirestoreDoc(&vectorstore.Document{ID: "img2", Content: other, Embedding: doc.Embedding}).ContentHash
+	assert.NotEqual(t, fsDoc.ContentHash, otherHash, "distinct images must hash differently")
+
+	back := c.firestoreToVectorstoreDoc(fsDoc)
+	assert.Equal(t, vectorstore.ContentTypeImage, back.Content.Type)
+	assert.Equal(t, png, back.Content.Data)
+	assert.Equal(t, "image/png", back.Content.MimeType)
+}
+
+func TestVersionConversion(t *testing.T) {
+	c := &FirestoreCollection{config: &vectorstore.CollectionConfig{}}
+	doc := &vectorstore.Document{
+		ID:      "doc1",
+		Content: vectorstore.NewTextContent("content"),
+		Version: 3,
+	}
+
+	fsDoc := c.vectorstoreToFirestoreDoc(doc)
+	assert.Equal(t, int64(3), fsDoc.Version)
+	assert.Equal(t, int64(3), c.firestoreToVectorstoreDoc(fsDoc).Version)
+}
@@ -0,0 +1,142 @@
+package memory
+
+import (
+	"math"
+	"strings"
+	"unicode"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+// BM25 parameters: term frequency saturation and length normalization
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// docTerms is a document's tokenized text, computed when it is stored
+type docTerms struct {
+	freqs  map[string]int // Occurrences of each token
+	length int            // Number of tokens
+}
+
+// newDocTerms tokenizes the text of doc, returning nil if it has none
+func newDocTerms(doc *vectorstore.Document) *docTerms {
+	tokens := tokenize(documentText(doc))
+	if len(tokens) == 0 {
+		return nil
+	}
+	freqs := make(map[string]int)
+	for _, token := range tokens {
+		freqs[token]++
+	}
+	return &docTerms{freqs: freqs, length: len(tokens)}
+}
+
+// keywordScores returns the BM25 score of each candidate for keywords,
+// normalized so the best match scores 1. Corpus statistics are taken over
+// the candidates, so filters narrow the vocabulary a query is ranked against.
+func (c *MemoryCollection) keywordScores(candidates []string, keywords []string) map[string]float32 {
+	terms := uniqueTerms(keywords)
+	if len(terms) == 0 {
+		return nil
+	}
+
+	// Term frequencies per document, and document frequency per term
+	freqs := make(map[string]map[string]int, len(candidates))
+	lengths := make(map[string]int, len(candidates))
+	docFreq := make(map[string]int, len(terms))
+	totalLength := 0
+	for _, docID := range candidates {
+		if _, exists := c.documents[docID]; !exists {
+			continue
+		}
+		var tf map[string]int
+		length := 0
+		if dt := c.terms[docID]; dt != nil {
+			tf, length = dt.freqs, dt.length
+		}
+		for _, term := range terms {
+			if tf[term] > 0 {
+				docFreq[term]++
+			}
+		}
+		freqs[docID] = tf
+		lengths[docID] = length
+		totalLength += length
+	}
+	if len(freqs) == 0 || totalLength == 0 {
+		return nil
+	}
+
+	n := float64(len(freqs))
+	avgLength := float64(totalLength) / n
+	scores := make(map[string]float32, len(freqs))
+	var best float64
+	for docID, tf := range freqs {
+		var score float64
+		norm := bm25K1 * (1 - bm25B + bm25B*float64(lengths[docID])/avgLength)
+		for _, term := range terms {
+			f := float64(tf[term])
+			if f == 0 {
+				continue
+			}
+			df := float64(docFreq[term])
+			idf := math.Log(1 + (n-df+0.5)/(df+0.5))
+			score += idf * f * (bm25K1 + 1) / (f + norm)
+		}
+		if score > 0 {
+			scores[docID] = float32(score)
+			best = math.Max(best, score)
+		}
+	}
+
+	for docID, score := range scores {
+		scores[docID] = float32(float64(score) / best)
+	}
+	return scores
+}
+
+// fuseScores blends a vector score with a normalized keyword score. Cosine
+// and dot product scores are mapped from [-1, 1] to [0, 1] first so both
+// signals share a scale.
+func fuseScores(vectorScore, keywordScore float32, alpha float64, metric vectorstore.DistanceMetric) float32 {
+	v := float64(vectorScore)
+	if metric != vectorstore.DistanceMetricEuclidean {
+		v = math.Max(0, math.Min(1, (v+1)/2))
+	}
+	return float32(alpha*v + (1-alpha)*float64(keywordScore))
+}
+
+// documentText returns the text searched by keyword queries
+func documentText(doc *vectorstore.Document) string {
+	if doc.Content == nil || doc.Content.Type != vectorstore.ContentTypeText {
+		return ""
+	}
+	if doc.Content.Text != "" {
+		return doc.Content.Text
+	}
+	return strings.Join(doc.Content.Chunks, " ")
+}
+
+// tokenize lowercases text and splits it into letter and digit runs
+func tokenize(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// uniqueTerms tokenizes keywords, dropping repeated terms
+func uniqueTerms(keywords []string) []string {
+	seen := make(map[string]bool)
+	var terms []string
+	for _, keyword := range keywords {
+		for _, term := range tokenize(keyword) {
+			if !seen[term] {
+				seen[term] = true
+				terms = append(terms, term)
+			}
+		}
+	}
+	return terms
+}
@@ -24,18 +24,44 @@ import (
 //   - Indexed lookups for scope/temporal fields
 //   - Thread-safe operations
 //   - Streaming query support
+//   - Hybrid vector + keyword (BM25) search
 type MemoryVectorStore struct {
-	collections map[string]*MemoryCollection
-	mu          sync.RWMutex
-	stopCleanup chan struct{}
-	wg          sync.WaitGroup
+	collections   map[string]*MemoryCollection
+	mu            sync.RWMutex
+	sweepInterval time.Duration
+	stopCleanup   chan struct{}
+	closeOnce     sync.Once
+	wg            sync.WaitGroup
+}
+
+// DefaultSweepInterval is how often expired documents are removed when
+// WithSweepInterval is not given.
+const DefaultSweepInterval = time.Minute
+
+// Option configures a MemoryVectorStore.
+type Option func(*MemoryVectorStore)
+
+// WithSweepInterval sets how often the background sweeper removes expired
+// documents. Expired documents are excluded from reads even between sweeps,
+// so the interval only bounds how long they keep using memory.
+// Non-positive values are ignored.
+func WithSweepInterval(d time.Duration) Option {
+	return func(m *MemoryVectorStore) {
+		if d > 0 {
+			m.sweepInterval = d
+		}
+	}
 }
 
 // New creates a new MemoryVectorStore.
-func New() (vectorstore.VectorStore, error) {
+func New(opts ...Option) (vectorstore.VectorStore, error) {
 	store := &MemoryVectorStore{
-		collections: make(map[string]*MemoryCollection),
-		stopCleanup: make(chan struct{}),
+		collections:   make(map[string]*MemoryCollection),
+		sweepInterval: DefaultSweepInterval,
+		stopCleanup:   make(chan struct{}),
+	}
+	for _, opt := range opts {
+		opt(store)
 	}
 
 	// Start TTL cleanup goroutine
@@ -61,10 +87,12 @@ func (m *MemoryVectorStore) Collection(name string, opts ...vectorstore.Collecti
 		name:       name,
 		config:     config,
 		documents:  make(map[string]*vectorstore.Document),
+		terms:      make(map[string]*docTerms),
 		scopeIndex: newScopeIndex(),
 		timeIndex:  newTimeIndex(),
 		tagIndex:   newTagIndex(),
 		hashIndex:  make(map[string]string),
+		dimensions: config.EmbeddingDimensions,
 		createdAt:  time.Now(),
 		updatedAt:  time.Now(),
 	}
@@ -107,12 +135,18 @@ func (m *MemoryVectorStore) Stats(ctx context.Context) (*vectorstore.StoreStats,
 
 	var totalDocs int64
 	var totalBytes int64
+	var metrics vectorstore.CollectionMetrics
 
+	now := time.Now()
 	for _, coll := range m.collections {
+		metrics = metrics.Add(coll.Metrics())
 		coll.mu.RLock()
-		totalDocs += int64(len(coll.documents))
 		// Estimate storage bytes (rough approximation)
 		for _, doc := range coll.documents {
+			if isExpired(doc, now) {
+				continue
+			}
+			totalDocs++
 			totalBytes += estimateDocumentSize(doc)
 		}
 		coll.mu.RUnlock()
@@ -124,12 +158,14 @@ func (m *MemoryVectorStore) Stats(ctx context.Context) (*vectorstore.StoreStats,
 		StorageBytes: totalBytes,
 		Provider:     "memory",
 		Version:      "1.0.0",
+		Metrics:      metrics,
 	}, nil
 }
 
-// Close closes the vector store and releases resources.
+// Close stops the expiry sweeper and releases resources. It is safe to call
+// more than once.
 func (m *MemoryVectorStore) Close() error {
-	close(m.stopCleanup)
+	m.closeOnce.Do(func() { close(m.stopCleanup) })
 	m.wg.Wait()
 	return nil
 }
@@ -138,7 +174,7 @@ func (m *MemoryVectorStore) Close() error {
 func (m *MemoryVectorStore) cleanupExpiredDocuments() {
 	defer m.wg.Done()
 
-	ticker := time.NewTicker(1 * time.Minute)
+	ticker := time.NewTicker(m.sweepInterval)
 	defer ticker.Stop()
 
 	for {
@@ -167,12 +203,15 @@ type MemoryCollection struct {
 	name       string
 	config     *vectorstore.CollectionConfig
 	documents  map[string]*vectorstore.Document
+	terms      map[string]*docTerms // Tokenized text for keyword search
 	scopeIndex *scopeIndex
 	timeIndex  *timeIndex
 	tagIndex   *tagIndex
 	hashIndex  map[string]string // content hash -> document ID
+	dimensions int               // configured, or learned from the first embedding
 	createdAt  time.Time
 	updatedAt  time.Time
+	metrics    vectorstore.MetricsRecorder
 	mu         sync.RWMutex
 }
 
@@ -196,24 +235,20 @@ func (c *MemoryCollection) Upsert(ctx context.Context, documents ...*vectorstore
 			return nil, fmt.Errorf("invalid document at index %d: %w", i, err)
 		}
 
-		// Validate embedding dimensions if configured
-		if c.config.EmbeddingDimensions > 0 && doc.Embedding != nil {
-			if doc.Embedding.Dimensions != c.config.EmbeddingDimensions {
-				return nil, fmt.Errorf("document %s embedding dimension mismatch: expected %d, got %d",
-					doc.ID, c.config.EmbeddingDimensions, doc.Embedding.Dimensions)
-			}
-		}
-
 		// Validate required scope fields
 		if err := c.validateRequiredScope(doc); err != nil {
 			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
 		}
 	}
-	validationTime := time.Since(validationStart)
 
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
+	if err := c.checkDimensions(documents); err != nil {
+		return nil, err
+	}
+	validationTime := time.Since(validationStart)
+
 	storageStart := time.Now()
 	result := &vectorstore.UpsertResult{}
 
@@ -236,64 +271,142 @@ func (c *MemoryCollection) Upsert(ctx context.Context, documents ...*vectorstore
 			}
 		}
 
-		// Check if document exists
-		_, exists := c.documents[doc.ID]
-
-		// Set temporal information
-		now := time.Now()
-		if doc.Temporal == nil {
-			doc.Temporal = &vectorstore.Temporal{
-				CreatedAt: now,
-				UpdatedAt: now,
-			}
+		if c.store(doc) {
+			result.Updated++
 		} else {
-			if doc.Temporal.CreatedAt.IsZero() {
-				doc.Temporal.CreatedAt = now
-			}
-			doc.Temporal.UpdatedAt = now
+			result.Inserted++
 		}
+	}
 
-		// Apply TTL if configured
-		if c.config.TTL > 0 && doc.Temporal.ExpiresAt == nil {
-			expiresAt := now.Add(c.config.TTL)
-			doc.Temporal.ExpiresAt = &expiresAt
-		}
+	storageTime := time.Since(storageStart)
 
-		// Deep copy document to prevent external mutation
-		docCopy := deepCopyDocument(doc)
+	c.updatedAt = time.Now()
 
-		// Store document
-		c.documents[doc.ID] = docCopy
+	result.Timing = &vectorstore.OperationTiming{
+		Total:      time.Since(startTime),
+		Validation: validationTime,
+		Storage:    storageTime,
+	}
+	c.metrics.RecordUpsert("memory", c.name, int(result.Inserted+result.Updated), result.Timing.Total)
 
-		// Update indexes
-		c.scopeIndex.add(doc.ID, doc.Scope)
-		c.timeIndex.add(doc.ID, doc.Temporal)
-		c.tagIndex.add(doc.ID, doc.Tags)
-		if c.config.EnableDeduplication {
-			contentHash := calculateContentHash(doc)
-			c.hashIndex[contentHash] = doc.ID
-		}
+	return result, nil
+}
 
-		if exists {
-			result.Updated++
-		} else {
-			result.Inserted++
-		}
+// UpsertIfVersion writes doc only if its stored version equals
+// expectedVersion. The check and write happen under the collection lock.
+func (c *MemoryCollection) UpsertIfVersion(ctx context.Context, doc *vectorstore.Document, expectedVersion int64) (*vectorstore.UpsertResult, error) {
+	startTime := time.Now()
+
+	if err := vectorstore.Validate(doc); err != nil {
+		return nil, fmt.Errorf("invalid document: %w", err)
+	}
+	if err := c.validateRequiredScope(doc); err != nil {
+		return nil, fmt.Errorf("document %s: %w", doc.ID, err)
 	}
 
-	storageTime := time.Since(storageStart)
+	c.mu.Lock()
+	defer c.mu.Unlock()
 
+	if err := c.checkDimensions([]*vectorstore.Document{doc}); err != nil {
+		return nil, err
+	}
+
+	var stored int64
+	if existing, ok := c.documents[doc.ID]; ok && !isExpired(existing, time.Now()) {
+		stored = existing.Version
+	}
+	if stored != expectedVersion {
+		return nil, fmt.Errorf("%w: document %s is at version %d, expected %d",
+			vectorstore.ErrVersionConflict, doc.ID, stored, expectedVersion)
+	}
+	validationTime := time.Since(startTime)
+
+	storageStart := time.Now()
+	result := &vectorstore.UpsertResult{}
+	if c.store(doc) {
+		result.Updated++
+	} else {
+		result.Inserted++
+	}
 	c.updatedAt = time.Now()
 
 	result.Timing = &vectorstore.OperationTiming{
 		Total:      time.Since(startTime),
 		Validation: validationTime,
-		Storage:    storageTime,
+		Storage:    time.Since(storageStart),
 	}
+	c.metrics.RecordUpsert("memory", c.name, 1, result.Timing.Total)
 
 	return result, nil
 }
 
+// checkDimensions validates embedding dimensions before storing anything,
+// learning them from the first embedding if not configured. The caller
+// must hold c.mu.
+func (c *MemoryCollection) checkDimensions(documents []*vectorstore.Document) error {
+	dimensions := c.dimensions
+	for _, doc := range documents {
+		if doc.Embedding == nil {
+			continue
+		}
+		if dimensions == 0 {
+			dimensions = doc.Embedding.Dimensions
+		}
+		if err := vectorstore.CheckDimensions(dimensions, doc.Embedding.Dimensions); err != nil {
+			return fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	c.dimensions = dimensions
+	return nil
+}
+
+// store sets doc's temporal fields and version, saves a copy, and updates
+// the indexes. The version is one past the stored one, whatever the caller
+// passed, so a stale copy can never rewind it. It reports whether a
+// document with the same ID already existed. The caller must hold c.mu.
+func (c *MemoryCollection) store(doc *vectorstore.Document) bool {
+	existing, exists := c.documents[doc.ID]
+
+	now := time.Now()
+	doc.Version = 1
+	if exists && !isExpired(existing, now) {
+		doc.Version = existing.Version + 1
+	}
+
+	// Set temporal information
+	if doc.Temporal == nil {
+		doc.Temporal = &vectorstore.Temporal{
+			CreatedAt: now,
+			UpdatedAt: now,
+		}
+	} else {
+		if doc.Temporal.CreatedAt.IsZero() {
+			doc.Temporal.CreatedAt = now
+		}
+		doc.Temporal.UpdatedAt = now
+	}
+
+	// Apply TTL if configured
+	if c.config.TTL > 0 && doc.Temporal.ExpiresAt == nil {
+		expiresAt := now.Add(c.config.TTL)
+		doc.Temporal.ExpiresAt = &expiresAt
+	}
+
+	// Deep copy document to prevent external mutation
+	c.documents[doc.ID] = deepCopyDocument(doc)
+	c.terms[doc.ID] = newDocTerms(doc)
+
+	// Update indexes
+	c.scopeIndex.add(doc.ID, doc.Scope)
+	c.timeIndex.add(doc.ID, doc.Temporal)
+	c.tagIndex.add(doc.ID, doc.Tags)
+	if c.config.EnableDeduplication {
+		c.hashIndex[calculateContentHash(doc)] = doc.ID
+	}
+
+	return exists
+}
+
 // UpsertBatch performs batch upsert with progress tracking.
 func (c *MemoryCollection) UpsertBatch(ctx context.Context, documents []*vectorstore.Document, opts ...vectorstore.BatchOption) (*vectorstore.UpsertResult, error) {
 	if len(documents) == 0 {
@@ -366,6 +479,12 @@ func (c *MemoryCollection) Query(ctx context.Context, query *vectorstore.Query)
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	if query.Embedding != nil {
+		if err := vectorstore.CheckDimensions(c.dimensions, len(query.Embedding.Vector)); err != nil {
+			return nil, fmt.Errorf("query: %w", err)
+		}
+	}
+
 	// Apply filters first
 	filterStart := time.Now()
 	candidates := c.applyFilters(query.Filters)
@@ -416,6 +535,7 @@ func (c *MemoryCollection) Query(ctx context.Context, query *vectorstore.Query)
 		Limit:   query.Limit,
 		Timing:  timing,
 	}
+	c.metrics.RecordQuery("memory", c.name, timing.Total, len(matches))
 
 	return result, nil
 }
@@ -440,9 +560,10 @@ func (c *MemoryCollection) Get(ctx context.Context, ids ...string) ([]*vectorsto
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	now := time.Now()
 	documents := make([]*vectorstore.Document, 0, len(ids))
 	for _, id := range ids {
-		if doc, exists := c.documents[id]; exists {
+		if doc, exists := c.documents[id]; exists && !isExpired(doc, now) {
 			// Deep copy to prevent external mutation
 			docCopy := deepCopyDocument(doc)
 			documents = append(documents, docCopy)
@@ -483,6 +604,7 @@ func (c *MemoryCollection) Delete(ctx context.Context, ids ...string) (*vectorst
 		}
 
 		delete(c.documents, id)
+		delete(c.terms, id)
 		result.Deleted++
 	}
 
@@ -522,6 +644,7 @@ func (c *MemoryCollection) DeleteByFilter(ctx context.Context, filter vectorstor
 		}
 
 		delete(c.documents, id)
+		delete(c.terms, id)
 		result.Deleted++
 	}
 
@@ -539,23 +662,30 @@ func (c *MemoryCollection) Count(ctx context.Context, filter vectorstore.Filter)
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
-	if filter == nil {
-		return int64(len(c.documents)), nil
-	}
-
 	candidates := c.applyFilters(filter)
 	return int64(len(candidates)), nil
 }
 
+// Metrics returns the query and upsert metrics recorded by the collection.
+func (c *MemoryCollection) Metrics() vectorstore.CollectionMetrics {
+	return c.metrics.Snapshot()
+}
+
 // Stats returns statistics about the collection.
 func (c *MemoryCollection) Stats(ctx context.Context) (*vectorstore.CollectionStats, error) {
 	c.mu.RLock()
 	defer c.mu.RUnlock()
 
+	var totalDocs int64
 	var totalBytes int64
 	var embeddingDims int
 
+	now := time.Now()
 	for _, doc := range c.documents {
+		if isExpired(doc, now) {
+			continue
+		}
+		totalDocs++
 		totalBytes += estimateDocumentSize(doc)
 		if doc.Embedding != nil && embeddingDims == 0 {
 			embeddingDims = doc.Embedding.Dimensions
@@ -564,7 +694,7 @@ func (c *MemoryCollection) Stats(ctx context.Context) (*vectorstore.CollectionSt
 
 	return &vectorstore.CollectionStats{
 		Name:                c.name,
-		Documents:           int64(len(c.documents)),
+		Documents:           totalDocs,
 		StorageBytes:        totalBytes,
 		EmbeddingDimensions: embeddingDims,
 		IndexType:           string(c.config.IndexType),
@@ -579,10 +709,12 @@ func (c *MemoryCollection) Clear(ctx context.Context) error {
 	defer c.mu.Unlock()
 
 	c.documents = make(map[string]*vectorstore.Document)
+	c.terms = make(map[string]*docTerms)
 	c.scopeIndex = newScopeIndex()
 	c.timeIndex = newTimeIndex()
 	c.tagIndex = newTagIndex()
 	c.hashIndex = make(map[string]string)
+	c.dimensions = c.config.EmbeddingDimensions
 	c.updatedAt = time.Now()
 
 	return nil
@@ -629,20 +761,27 @@ func (c *MemoryCollection) validateRequiredScope(doc *vectorstore.Document) erro
 }
 
 // applyFilters applies filters and returns matching document IDs.
+// Expired documents are never returned, even before the sweeper removes them.
 func (c *MemoryCollection) applyFilters(filter vectorstore.Filter) []string {
+	now := time.Now()
+
 	if filter == nil {
-		// No filter - return all document IDs
+		// No filter - return all live document IDs
 		ids := make([]string, 0, len(c.documents))
-		for id := range c.documents {
-			ids = append(ids, id)
+		for id, doc := range c.documents {
+			if !isExpired(doc, now) {
+				ids = append(ids, id)
+			}
 		}
 		return ids
 	}
 
-	// Get all candidates first
+	// Get all live candidates first
 	candidates := make(map[string]bool)
-	for id := range c.documents {
-		candidates[id] = true
+	for id, doc := range c.documents {
+		if !isExpired(doc, now) {
+			candidates[id] = true
+		}
 	}
 
 	// Apply filter
@@ -824,10 +963,7 @@ func (c *MemoryCollection) matchesTagFilter(doc *vectorstore.Document, tag strin
 
 // matchesScopeFilter checks if document matches scope filter.
 func (c *MemoryCollection) matchesScopeFilter(doc *vectorstore.Document, filterScope *vectorstore.Scope) bool {
-	if doc.Scope == nil {
-		return filterScope == nil
-	}
-	return doc.Scope.Match(filterScope)
+	return doc.Scope.Satisfies(filterScope)
 }
 
 // matchesTimeFilter checks if document matches time filter.
@@ -892,6 +1028,13 @@ func (c *MemoryCollection) calculateScores(candidates []string, query *vectorsto
 		metric = vectorstore.DistanceMetricCosine
 	}
 
+	// Hybrid search blends in a keyword score
+	hybrid := len(query.Keywords) > 0
+	var keywordScores map[string]float32
+	if hybrid {
+		keywordScores = c.keywordScores(candidates, query.Keywords)
+	}
+
 	matches := make([]*vectorstore.Match, 0, len(candidates))
 
 	for _, docID := range candidates {
@@ -901,6 +1044,9 @@ func (c *MemoryCollection) calculateScores(candidates []string, query *vectorsto
 		}
 
 		score, distance := calculateSimilarity(query.Embedding.Vector, doc.Embedding.Vector, metric)
+		if hybrid {
+			score = fuseScores(score, keywordScores[docID], query.HybridAlpha(), metric)
+		}
 
 		// Apply minimum score filter
 		if query.MinScore > 0 && score < query.MinScore {
@@ -924,12 +1070,9 @@ func (c *MemoryCollection) calculateScores(candidates []string, query *vectorsto
 	return matches
 }
 
-// cleanupExpired removes expired documents.
+// cleanupExpired removes expired documents. Documents can carry their own
+// expiry, so collections without a TTL are swept as well.
 func (c *MemoryCollection) cleanupExpired() {
-	if c.config.TTL == 0 {
-		return
-	}
-
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
@@ -937,7 +1080,7 @@ func (c *MemoryCollection) cleanupExpired() {
 	expiredIDs := make([]string, 0)
 
 	for id, doc := range c.documents {
-		if doc.Temporal != nil && doc.Temporal.IsExpired() {
+		if isExpired(doc, now) {
 			expiredIDs = append(expiredIDs, id)
 		}
 	}
@@ -955,6 +1098,7 @@ func (c *MemoryCollection) cleanupExpired() {
 		}
 
 		delete(c.documents, id)
+		delete(c.terms, id)
 	}
 
 	if len(expiredIDs) > 0 {
@@ -962,6 +1106,11 @@ func (c *MemoryCollection) cleanupExpired() {
 	}
 }
 
+// isExpired reports whether doc expired at or before now.
+func isExpired(doc *vectorstore.Document, now time.Time) bool {
+	return doc.Temporal != nil && doc.Temporal.ExpiresAt != nil && !now.Before(*doc.Temporal.ExpiresAt)
+}
+
 // Indexes
 
 // scopeIndex provides indexed lookups for scope fields.
@@ -1101,9 +1250,11 @@ func (idx *tagIndex) remove(docID string) {
 func calculateContentHash(doc *vectorstore.Document) string {
 	h := sha256.New()
 
-	// Hash content
+	// Hash content, including binary data so distinct images of the same
+	// size do not collide
 	if doc.Content != nil {
 		h.Write([]byte(doc.Content.String()))
+		h.Write(doc.Content.Data)
 	}
 
 	// Hash embedding
@@ -1202,7 +1353,8 @@ func deepCopyDocument(doc *vectorstore.Document) *vectorstore.Document {
 	}
 
 	copy := &vectorstore.Document{
-		ID: doc.ID,
+		ID:      doc.ID,
+		Version: doc.Version,
 	}
 
 	// Copy content
@@ -2,11 +2,13 @@ package memory
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sync"
 	"testing"
 	"time"
 
+	"github.com/aixgo-dev/aixgo/pkg/observability"
 	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -209,6 +211,110 @@ func TestUpsertWithTTL(t *testing.T) {
 	assert.Equal(t, int64(0), count)
 }
 
+func TestSweepExpiredDocuments(t *testing.T) {
+	ctx := context.Background()
+	store, err := New(WithSweepInterval(10 * time.Millisecond))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	coll := store.Collection("test-sweep", vectorstore.WithTTL(50*time.Millisecond))
+
+	_, err = coll.Upsert(ctx,
+		createTestDoc("short", "expires", []float32{1, 0, 0}),
+	)
+	require.NoError(t, err)
+
+	keep := createTestDoc("keep", "stays", []float32{0, 1, 0})
+	keep.Temporal = vectorstore.NewTemporalWithTTL(time.Hour)
+	_, err = coll.Upsert(ctx, keep)
+	require.NoError(t, err)
+
+	query := &vectorstore.Query{
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test-model"),
+		Limit:     10,
+	}
+
+	result, err := coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Len(t, result.Matches, 2)
+
+	// The sweeper removes the document rather than just hiding it
+	memColl := coll.(*MemoryCollection)
+	require.Eventually(t, func() bool {
+		memColl.mu.RLock()
+		defer memColl.mu.RUnlock()
+		_, stored := memColl.documents["short"]
+		return !stored
+	}, time.Second, 10*time.Millisecond)
+
+	result, err = coll.Query(ctx, query)
+	require.NoError(t, err)
+	require.Len(t, result.Matches, 1)
+	assert.Equal(t, "keep", result.Matches[0].Document.ID)
+
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Documents)
+
+	collStats, err := coll.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), collStats.Documents)
+}
+
+func TestExpiredDocumentsHiddenBetweenSweeps(t *testing.T) {
+	ctx := context.Background()
+	store, err := New(WithSweepInterval(time.Hour))
+	require.NoError(t, err)
+	defer func() { _ = store.Close() }()
+
+	coll := store.Collection("test-lazy")
+
+	doc := createTestDoc("doc1", "content", []float32{1, 0, 0})
+	doc.Temporal = vectorstore.NewTemporal()
+	doc.Temporal.SetExpiry(-time.Second)
+	_, err = coll.Upsert(ctx, doc)
+	require.NoError(t, err)
+
+	result, err := coll.Query(ctx, &vectorstore.Query{
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test-model"),
+		Limit:     10,
+	})
+	require.NoError(t, err)
+	assert.Empty(t, result.Matches)
+
+	docs, err := coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Empty(t, docs)
+
+	count, err := coll.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Zero(t, count)
+
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, stats.Documents)
+}
+
+func TestCloseStopsSweeper(t *testing.T) {
+	store, err := New(WithSweepInterval(time.Millisecond))
+	require.NoError(t, err)
+
+	done := make(chan struct{})
+	go func() {
+		_ = store.Close()
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Close did not stop the sweeper")
+	}
+
+	// Closing again is a no-op
+	assert.NoError(t, store.Close())
+}
+
 func TestDeduplication(t *testing.T) {
 	ctx := context.Background()
 	store, _ := New()
@@ -237,6 +343,167 @@ func TestDeduplication(t *testing.T) {
 	assert.Equal(t, int64(1), count)
 }
 
+func TestImageContent(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("media", vectorstore.WithDeduplication(true))
+
+	png := []byte{0x89, 'P', 'N', 'G', 0x0d, 0x0a, 0x1a, 0x0a}
+	doc := &vectorstore.Document{
+		ID:        "img1",
+		Content:   vectorstore.NewImageContent(png, "image/png"),
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "clip-vit-base-patch32"),
+		Metadata:  map[string]any{"caption": "A lighthouse at dusk"},
+	}
+	_, err := coll.Upsert(ctx, doc)
+	require.NoError(t, err)
+
+	t.Run("round trip preserves content", func(t *testing.T) {
+		retrieved, err := coll.Get(ctx, "img1")
+		require.NoError(t, err)
+		require.Len(t, retrieved, 1)
+		content := retrieved[0].Content
+		assert.Equal(t, vectorstore.ContentTypeImage, content.Type)
+		assert.Equal(t, "image/png", content.MimeType)
+		assert.Equal(t, png, content.Data)
+		assert.Equal(t, "[image image/png: 8 bytes]", content.String())
+		assert.Equal(t, "A lighthouse at dusk", retrieved[0].Metadata["caption"])
+
+		// The stored bytes are not shared with callers
+		content.Data[0] = 0
+		again, err := coll.Get(ctx, "img1")
+		require.NoError(t, err)
+		assert.Equal(t, png, again[0].Content.Data)
+	})
+
+	t.Run("query returns image", func(t *testing.T) {
+		result, err := coll.Query(ctx, &vectorstore.Query{
+			Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "clip-vit-base-patch32"),
+			Limit:     1,
+		})
+		require.NoError(t, err)
+		require.Equal(t, 1, result.Count())
+		assert.Equal(t, vectorstore.ContentTypeImage, result.Matches[0].Document.Content.Type)
+		assert.Equal(t, png, result.Matches[0].Document.Content.Data)
+	})
+
+	t.Run("distinct images of equal size are not deduplicated", func(t *testing.T) {
+		other := append([]byte(nil), png...)
+		other[7] = 0xff
+		result, err := coll.Upsert(ctx, &vectorstore.Document{
+			ID:        "img2",
+			Content:   vectorstore.NewImageContent(other, "image/png"),
+			Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "clip-vit-base-patch32"),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), result.Inserted)
+		assert.Equal(t, int64(0), result.Deduplicated)
+	})
+}
+
+func TestUpsertIfVersion(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("test")
+
+	doc := createTestDoc("doc1", "content", []float32{1, 0, 0})
+	result, err := coll.UpsertIfVersion(ctx, doc, 0)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Inserted)
+	assert.Equal(t, int64(1), doc.Version)
+
+	_, err = coll.UpsertIfVersion(ctx, createTestDoc("doc1", "stale", []float32{1, 0, 0}), 0)
+	assert.ErrorIs(t, err, vectorstore.ErrVersionConflict)
+
+	docs, err := coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "content", docs[0].Content.Text, "a conflicting write must not be stored")
+	assert.Equal(t, int64(1), docs[0].Version)
+
+	docs[0].Metadata = map[string]any{"views": 1}
+	result, err = coll.UpsertIfVersion(ctx, docs[0], docs[0].Version)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Updated)
+
+	docs, err = coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), docs[0].Version)
+	assert.Equal(t, 1, docs[0].Metadata["views"])
+}
+
+func TestUpsertBumpsVersion(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("test")
+
+	_, err := coll.UpsertIfVersion(ctx, createTestDoc("doc1", "v1", []float32{1, 0, 0}), 0)
+	require.NoError(t, err)
+	docs, err := coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	stale := docs[0]
+
+	_, err = coll.UpsertIfVersion(ctx, createTestDoc("doc1", "v2", []float32{1, 0, 0}), 1)
+	require.NoError(t, err)
+
+	// A plain Upsert of the stale copy bumps the version instead of
+	// rewinding it to the copy's
+	stale.Content.Text = "plain write"
+	_, err = coll.Upsert(ctx, stale)
+	require.NoError(t, err)
+	docs, err = coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), docs[0].Version)
+
+	_, err = coll.UpsertIfVersion(ctx, createTestDoc("doc1", "lost update", []float32{1, 0, 0}), 1)
+	assert.ErrorIs(t, err, vectorstore.ErrVersionConflict)
+}
+
+func TestUpsertIfVersionRace(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("test")
+
+	doc := createTestDoc("doc1", "content", []float32{1, 0, 0})
+	_, err := coll.UpsertIfVersion(ctx, doc, 0)
+	require.NoError(t, err)
+
+	const updaters = 8
+	var wg sync.WaitGroup
+	errs := make([]error, updaters)
+	start := make(chan struct{})
+	for i := 0; i < updaters; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			update := createTestDoc("doc1", fmt.Sprintf("update %d", i), []float32{1, 0, 0})
+			<-start
+			_, errs[i] = coll.UpsertIfVersion(ctx, update, 1)
+		}(i)
+	}
+	close(start)
+	wg.Wait()
+
+	succeeded := 0
+	for _, err := range errs {
+		switch {
+		case err == nil:
+			succeeded++
+		case !errors.Is(err, vectorstore.ErrVersionConflict):
+			t.Errorf("UpsertIfVersion() error = %v, want ErrVersionConflict", err)
+		}
+	}
+	assert.Equal(t, 1, succeeded, "exactly one racing updater must win")
+
+	docs, err := coll.Get(ctx, "doc1")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), docs[0].Version)
+}
+
 func TestUpsertBatch(t *testing.T) {
 	ctx := context.Background()
 	store, _ := New()
@@ -534,6 +801,60 @@ func TestScopeFilters(t *testing.T) {
 	})
 }
 
+func TestTenantIsolation(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("test")
+
+	docs := []*vectorstore.Document{
+		createTestDocWithScope("a1", "a1", []float32{1, 0, 0}, "tenantA", "user1", ""),
+		createTestDocWithScope("a2", "a2", []float32{0.9, 0.1, 0}, "tenantA", "user2", ""),
+		createTestDocWithScope("b1", "b1", []float32{1, 0, 0}, "tenantB", "user1", ""),
+		createTestDocWithScope("b2", "b2", []float32{0.9, 0.1, 0}, "tenantB", "user2", ""),
+		createTestDocWithScope("partial", "partial", []float32{1, 0, 0}, "", "user1", ""),
+		createTestDoc("unscoped", "unscoped", []float32{1, 0, 0}),
+	}
+	docs[0].Tags = []string{"faq"}
+	docs[2].Tags = []string{"faq"}
+	docs[3].Scope.Custom = map[string]string{"region": "eu"}
+	_, err := coll.Upsert(ctx, docs...)
+	require.NoError(t, err)
+
+	tests := []struct {
+		name    string
+		filter  vectorstore.Filter
+		wantIDs []string
+	}{
+		{name: "tenant", filter: vectorstore.TenantFilter("tenantA"), wantIDs: []string{"a1", "a2"}},
+		{name: "tenant and user", filter: vectorstore.ScopeFilter(&vectorstore.Scope{Tenant: "tenantA", User: "user1"}), wantIDs: []string{"a1"}},
+		{name: "tenant and tag", filter: vectorstore.And(vectorstore.TenantFilter("tenantA"), vectorstore.TagFilter("faq")), wantIDs: []string{"a1"}},
+		{name: "custom dimension", filter: vectorstore.ScopeFilter(&vectorstore.Scope{Tenant: "tenantB", Custom: map[string]string{"region": "eu"}}), wantIDs: []string{"b2"}},
+		{name: "unknown tenant", filter: vectorstore.TenantFilter("tenantC"), wantIDs: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := coll.Query(ctx, &vectorstore.Query{
+				Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test"),
+				Limit:     10,
+				Filters:   tt.filter,
+			})
+			require.NoError(t, err)
+
+			var ids []string
+			for _, m := range result.Matches {
+				ids = append(ids, m.Document.ID)
+			}
+			assert.ElementsMatch(t, tt.wantIDs, ids)
+
+			count, err := coll.Count(ctx, tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tt.wantIDs)), count)
+		})
+	}
+}
+
 func TestTimeFilters(t *testing.T) {
 	ctx := context.Background()
 	store, _ := New()
@@ -832,6 +1153,94 @@ func TestClear(t *testing.T) {
 	assert.Equal(t, int64(0), count)
 }
 
+func TestMetrics(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("test")
+
+	assert.Zero(t, coll.Metrics().Queries)
+
+	_, err := coll.Upsert(ctx,
+		createTestDocWithTags("doc1", "content1", []float32{1, 0, 0}, []string{"a"}),
+		createTestDocWithTags("doc2", "content2", []float32{0, 1, 0}, []string{"a"}),
+	)
+	require.NoError(t, err)
+
+	for i := 0; i < 3; i++ {
+		_, err := coll.Query(ctx, &vectorstore.Query{
+			Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test"),
+			Limit:     10,
+			Filters:   vectorstore.TagFilter("a"),
+		})
+		require.NoError(t, err)
+	}
+	_, err = coll.Query(ctx, &vectorstore.Query{
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test"),
+		Limit:     10,
+		Filters:   vectorstore.TagFilter("missing"),
+	})
+	require.NoError(t, err)
+
+	m := coll.Metrics()
+	assert.Equal(t, int64(4), m.Queries)
+	assert.Positive(t, m.AvgQueryLatency())
+	assert.InDelta(t, 1.5, m.AvgResults(), 0.001)
+	assert.InDelta(t, 0.75, m.HitRate(), 0.001)
+	assert.Equal(t, int64(2), m.UpsertedDocuments)
+	assert.Positive(t, m.UpsertThroughput())
+
+	_, err = store.Collection("other").Query(ctx, &vectorstore.Query{Filters: vectorstore.TagFilter("a"), Limit: 1})
+	require.NoError(t, err)
+
+	stats, err := store.Stats(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), stats.Metrics.Queries)
+}
+
+// recordingObserver counts the queries and upserts it observes
+type recordingObserver struct {
+	mu      sync.Mutex
+	queries map[string]int
+	upserts map[string]int
+}
+
+func (o *recordingObserver) ObserveQuery(provider, collection string, latency time.Duration, results int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.queries[provider+"/"+collection]++
+}
+
+func (o *recordingObserver) ObserveUpsert(provider, collection string, documents int, latency time.Duration) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.upserts[provider+"/"+collection] += documents
+}
+
+func TestMetricsObserver(t *testing.T) {
+	// The Prometheus exporter is installed the same way
+	var _ vectorstore.MetricsObserver = observability.VectorStoreMetrics{}
+
+	observer := &recordingObserver{queries: map[string]int{}, upserts: map[string]int{}}
+	vectorstore.SetMetricsObserver(observer)
+	defer vectorstore.SetMetricsObserver(nil)
+
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+	coll := store.Collection("observed")
+
+	_, err := coll.Upsert(ctx, createTestDoc("doc1", "content1", []float32{1, 0, 0}))
+	require.NoError(t, err)
+	_, err = coll.Query(ctx, &vectorstore.Query{Embedding: vectorstore.NewEmbedding([]float32{1, 0, 0}, "test"), Limit: 1})
+	require.NoError(t, err)
+
+	observer.mu.Lock()
+	defer observer.mu.Unlock()
+	assert.Equal(t, 1, observer.queries["memory/observed"])
+	assert.Equal(t, 1, observer.upserts["memory/observed"])
+}
+
 func TestDistanceMetrics(t *testing.T) {
 	ctx := context.Background()
 	store, _ := New()
@@ -1016,6 +1425,79 @@ func TestEmbeddingDimensions(t *testing.T) {
 	})
 }
 
+func TestQueryDimensionMismatch(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+
+	// Dimensions are learned from the first upsert when not configured
+	coll := store.Collection("test")
+	_, err := coll.Upsert(ctx, createTestDoc("doc1", "content", make([]float32, 384)))
+	require.NoError(t, err)
+
+	_, err = coll.Query(ctx, vectorstore.NewQuery(vectorstore.NewEmbedding(make([]float32, 1536), "test-model")))
+	require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+	assert.Contains(t, err.Error(), "expected 384, got 1536")
+
+	_, err = coll.Upsert(ctx, createTestDoc("doc2", "content", make([]float32, 1536)))
+	require.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+
+	// Clearing the collection forgets the learned dimensions
+	require.NoError(t, coll.Clear(ctx))
+	_, err = coll.Upsert(ctx, createTestDoc("doc2", "content", make([]float32, 1536)))
+	require.NoError(t, err)
+}
+
+func TestHybridQuery(t *testing.T) {
+	ctx := context.Background()
+	store, _ := New()
+	defer func() { _ = store.Close() }()
+
+	coll := store.Collection("test")
+	_, err := coll.Upsert(ctx,
+		createTestDoc("semantic", "How to troubleshoot connection failures", []float32{1, 0.1, 0}),
+		createTestDoc("exact", "The service returns ERR-4021 when the quota is exhausted", []float32{0.6, 0.8, 0}),
+		createTestDoc("other", "Release notes for the new dashboard", []float32{0, 0, 1}),
+	)
+	require.NoError(t, err)
+
+	query := vectorstore.NewQuery(vectorstore.NewEmbedding([]float32{1, 0, 0}, "test-model"))
+	result, err := coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "semantic", result.Matches[0].Document.ID, "pure vector search prefers the closer embedding")
+
+	query.Keywords = []string{"err-4021"}
+	result, err = coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "exact", result.Matches[0].Document.ID, "the exact rare token should outrank the closer embedding")
+	assert.Equal(t, "semantic", result.Matches[1].Document.ID)
+
+	// A high alpha leans back towards the vector score
+	alpha := 0.9
+	query.Alpha = &alpha
+	result, err = coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "semantic", result.Matches[0].Document.ID)
+
+	// Alpha 0 ranks by keywords alone
+	alpha = 0
+	result, err = coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, "exact", result.Matches[0].Document.ID)
+	assert.Equal(t, float32(0), result.Matches[1].Score)
+
+	// Updated text is re-tokenized
+	_, err = coll.Upsert(ctx, createTestDoc("exact", "The service is healthy", []float32{0.6, 0.8, 0}))
+	require.NoError(t, err)
+	result, err = coll.Query(ctx, query)
+	require.NoError(t, err)
+	assert.Equal(t, float32(0), result.Matches[0].Score, "no document mentions the keyword any more")
+
+	alpha = 1.5
+	_, err = coll.Query(ctx, query)
+	assert.Error(t, err)
+}
+
 func TestFilterOnlyQuery(t *testing.T) {
 	ctx := context.Background()
 	store, _ := New()
@@ -0,0 +1,138 @@
+package vectorstore
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CollectionMetrics holds running totals of the queries and upserts served
+// by a collection since it was opened.
+type CollectionMetrics struct {
+	// Queries is the number of successful queries
+	Queries int64
+
+	// QueryLatency is the total time spent serving those queries
+	QueryLatency time.Duration
+
+	// Results is the total number of matches returned
+	Results int64
+
+	// Hits is the number of queries that returned at least one match
+	Hits int64
+
+	// UpsertedDocuments is the number of documents inserted or updated
+	UpsertedDocuments int64
+
+	// UpsertLatency is the total time spent in successful upserts
+	UpsertLatency time.Duration
+}
+
+// AvgQueryLatency returns the mean latency of a query, or 0 if there were none.
+func (m CollectionMetrics) AvgQueryLatency() time.Duration {
+	if m.Queries == 0 {
+		return 0
+	}
+	return m.QueryLatency / time.Duration(m.Queries)
+}
+
+// AvgResults returns the mean number of matches per query.
+func (m CollectionMetrics) AvgResults() float64 {
+	if m.Queries == 0 {
+		return 0
+	}
+	return float64(m.Results) / float64(m.Queries)
+}
+
+// HitRate returns the fraction of queries that returned at least one match.
+func (m CollectionMetrics) HitRate() float64 {
+	if m.Queries == 0 {
+		return 0
+	}
+	return float64(m.Hits) / float64(m.Queries)
+}
+
+// UpsertThroughput returns the documents written per second of upsert time.
+func (m CollectionMetrics) UpsertThroughput() float64 {
+	if m.UpsertLatency <= 0 {
+		return 0
+	}
+	return float64(m.UpsertedDocuments) / m.UpsertLatency.Seconds()
+}
+
+// Add returns the sum of m and other, for aggregating several collections.
+func (m CollectionMetrics) Add(other CollectionMetrics) CollectionMetrics {
+	return CollectionMetrics{
+		Queries:           m.Queries + other.Queries,
+		QueryLatency:      m.QueryLatency + other.QueryLatency,
+		Results:           m.Results + other.Results,
+		Hits:              m.Hits + other.Hits,
+		UpsertedDocuments: m.UpsertedDocuments + other.UpsertedDocuments,
+		UpsertLatency:     m.UpsertLatency + other.UpsertLatency,
+	}
+}
+
+// MetricsObserver receives every query and upsert recorded by a
+// MetricsRecorder, for export to a monitoring system.
+type MetricsObserver interface {
+	ObserveQuery(provider, collection string, latency time.Duration, results int)
+	ObserveUpsert(provider, collection string, documents int, latency time.Duration)
+}
+
+var metricsObserver atomic.Pointer[MetricsObserver]
+
+// SetMetricsObserver installs o as the process-wide metrics observer.
+// Passing nil removes it. To export to Prometheus, install
+// observability.VectorStoreMetrics:
+//
+//	observability.InitMetrics()
+//	vectorstore.SetMetricsObserver(observability.VectorStoreMetrics{})
+func SetMetricsObserver(o MetricsObserver) {
+	if o == nil {
+		metricsObserver.Store(nil)
+		return
+	}
+	metricsObserver.Store(&o)
+}
+
+// MetricsRecorder accumulates CollectionMetrics for one collection. The
+// zero value is ready to use and safe for concurrent use.
+type MetricsRecorder struct {
+	mu      sync.Mutex
+	metrics CollectionMetrics
+}
+
+// RecordQuery records one successful query that returned results matches.
+func (r *MetricsRecorder) RecordQuery(provider, collection string, latency time.Duration, results int) {
+	r.mu.Lock()
+	r.metrics.Queries++
+	r.metrics.QueryLatency += latency
+	r.metrics.Results += int64(results)
+	if results > 0 {
+		r.metrics.Hits++
+	}
+	r.mu.Unlock()
+
+	if o := metricsObserver.Load(); o != nil {
+		(*o).ObserveQuery(provider, collection, latency, results)
+	}
+}
+
+// RecordUpsert records one successful upsert that wrote documents documents.
+func (r *MetricsRecorder) RecordUpsert(provider, collection string, documents int, latency time.Duration) {
+	r.mu.Lock()
+	r.metrics.UpsertedDocuments += int64(documents)
+	r.metrics.UpsertLatency += latency
+	r.mu.Unlock()
+
+	if o := metricsObserver.Load(); o != nil {
+		(*o).ObserveUpsert(provider, collection, documents, latency)
+	}
+}
+
+// Snapshot returns the metrics recorded so far.
+func (r *MetricsRecorder) Snapshot() CollectionMetrics {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.metrics
+}
@@ -66,6 +66,12 @@ type CollectionConfig struct {
 	// EnableAuditLog enables audit logging for all operations.
 	EnableAuditLog bool
 
+	// UseNativeVectorSearch asks the provider to use its server-side
+	// nearest-neighbor search (e.g. Firestore FindNearest) instead of
+	// scanning documents in memory. Providers without native support, or
+	// without a matching vector index, fall back to the in-memory scan.
+	UseNativeVectorSearch bool
+
 	// Metadata contains additional provider-specific configuration.
 	Metadata map[string]any
 }
@@ -238,6 +244,22 @@ func WithAuditLog(enabled bool) CollectionOption {
 	}
 }
 
+// WithNativeVectorSearch enables server-side nearest-neighbor search.
+// The provider must have a vector index on the embedding field; see the
+// provider documentation for how to create one.
+//
+// Example:
+//
+//	docs := store.Collection("docs",
+//	    WithDimensions(768),
+//	    WithNativeVectorSearch(true),
+//	)
+func WithNativeVectorSearch(enabled bool) CollectionOption {
+	return func(c *CollectionConfig) {
+		c.UseNativeVectorSearch = enabled
+	}
+}
+
 // WithMetadata adds custom metadata to the collection config.
 //
 // Example:
@@ -0,0 +1,247 @@
+package pgvector
+
+import (
+	"encoding/json"
+	"fmt"
+	"maps"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+// sqlBuilder accumulates positional query arguments while filters are
+// translated into SQL. All user-supplied values, including metadata keys,
+// are passed as arguments and never interpolated into the query text.
+type sqlBuilder struct {
+	args []any
+
+	// scoreExpr is the SQL expression score filters are evaluated against.
+	// It is "1" for filter-only queries, matching the score they report.
+	scoreExpr string
+}
+
+// arg appends a query argument and returns its placeholder.
+func (b *sqlBuilder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// where translates f into a SQL boolean expression. A nil filter is "TRUE".
+func (b *sqlBuilder) where(f vectorstore.Filter) (string, error) {
+	if f == nil {
+		return "TRUE", nil
+	}
+
+	switch {
+	case vectorstore.IsAndFilter(f):
+		return b.join(vectorstore.GetFilters(f), " AND ", "TRUE")
+
+	case vectorstore.IsOrFilter(f):
+		return b.join(vectorstore.GetFilters(f), " OR ", "FALSE")
+
+	case vectorstore.IsNotFilter(f):
+		inner, _ := vectorstore.GetNotFilter(f)
+		cond, err := b.where(inner)
+		if err != nil {
+			return "", err
+		}
+		return "NOT (" + cond + ")", nil
+	}
+
+	if field, op, value, ok := vectorstore.GetFieldFilter(f); ok {
+		return b.fieldCondition(field, op, value)
+	}
+
+	if tag, ok := vectorstore.GetTagFilter(f); ok {
+		return "tags @> ARRAY[" + b.arg(tag) + "]::text[]", nil
+	}
+
+	if scope, ok := vectorstore.GetScopeFilter(f); ok {
+		return b.scopeCondition(scope), nil
+	}
+
+	if field, op, value, ok := vectorstore.GetTimeFilter(f); ok {
+		column, ok := timeColumns[field]
+		if !ok {
+			return "", fmt.Errorf("unsupported time field: %s", field)
+		}
+		cmp, ok := comparisonOps[op]
+		if !ok {
+			return "", fmt.Errorf("filter operator %s is not supported on %s", op, field)
+		}
+		return column + " " + cmp + " " + b.arg(value), nil
+	}
+
+	if op, value, ok := vectorstore.GetScoreFilter(f); ok {
+		cmp, ok := comparisonOps[op]
+		if !ok {
+			return "", fmt.Errorf("filter operator %s is not supported on score", op)
+		}
+		return "(" + b.scoreExpr + ") " + cmp + " " + b.arg(value), nil
+	}
+
+	return "", fmt.Errorf("unsupported filter type %T", f)
+}
+
+func (b *sqlBuilder) join(filters []vectorstore.Filter, sep, empty string) (string, error) {
+	if len(filters) == 0 {
+		return empty, nil
+	}
+	conds := make([]string, 0, len(filters))
+	for _, f := range filters {
+		cond, err := b.where(f)
+		if err != nil {
+			return "", err
+		}
+		conds = append(conds, "("+cond+")")
+	}
+	return strings.Join(conds, sep), nil
+}
+
+// fieldCondition converts a metadata field filter. Equality uses jsonb
+// containment so values keep their JSON type; ordering comparisons cast
+// numeric values and compare strings as text.
+func (b *sqlBuilder) fieldCondition(field string, op vectorstore.FilterOperator, value any) (string, error) {
+	switch op {
+	case vectorstore.OpEqual, vectorstore.OpNotEqual:
+		doc, err := json.Marshal(map[string]any{field: value})
+		if err != nil {
+			return "", fmt.Errorf("invalid value for %s: %w", field, err)
+		}
+		cond := "metadata @> " + b.arg(string(doc)) + "::jsonb"
+		if op == vectorstore.OpNotEqual {
+			return "NOT COALESCE(" + cond + ", FALSE)", nil
+		}
+		return cond, nil
+
+	case vectorstore.OpExists:
+		return "COALESCE(metadata ? " + b.arg(field) + ", FALSE)", nil
+
+	case vectorstore.OpNotExists:
+		return "NOT COALESCE(metadata ? " + b.arg(field) + ", FALSE)", nil
+	}
+
+	text := "metadata->>" + b.arg(field)
+
+	switch op {
+	case vectorstore.OpGreaterThan, vectorstore.OpGreaterThanOrEqual,
+		vectorstore.OpLessThan, vectorstore.OpLessThanOrEqual:
+		cmp := comparisonOps[op]
+		if n, ok := toFloat(value); ok {
+			return "(" + text + ")::double precision " + cmp + " " + b.arg(n), nil
+		}
+		if s, ok := value.(string); ok {
+			return text + " " + cmp + " " + b.arg(s), nil
+		}
+		return "", fmt.Errorf("range filter on %s requires a numeric or string value, got %T", field, value)
+
+	case vectorstore.OpIn:
+		return text + " = ANY(" + b.arg(toStrings(value)) + "::text[])", nil
+
+	case vectorstore.OpNotIn:
+		return "NOT COALESCE(" + text + " = ANY(" + b.arg(toStrings(value)) + "::text[]), FALSE)", nil
+
+	case vectorstore.OpContains:
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("contains filter on %s requires a string value, got %T", field, value)
+		}
+		return "strpos(" + text + ", " + b.arg(s) + ") > 0", nil
+
+	case vectorstore.OpStartsWith:
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("startswith filter on %s requires a string value, got %T", field, value)
+		}
+		return "starts_with(" + text + ", " + b.arg(s) + ")", nil
+
+	case vectorstore.OpEndsWith:
+		s, ok := value.(string)
+		if !ok {
+			return "", fmt.Errorf("endswith filter on %s requires a string value, got %T", field, value)
+		}
+		p := b.arg(s)
+		return "right(" + text + ", length(" + p + ")) = " + p, nil
+
+	default:
+		return "", fmt.Errorf("filter operator %s is not supported by pgvector", op)
+	}
+}
+
+// scopeCondition requires every non-empty scope field to match.
+func (b *sqlBuilder) scopeCondition(scope *vectorstore.Scope) string {
+	var conds []string
+	add := func(column, value string) {
+		if value != "" {
+			conds = append(conds, column+" = "+b.arg(value))
+		}
+	}
+	add("scope_tenant", scope.Tenant)
+	add("scope_user", scope.User)
+	add("scope_session", scope.Session)
+	add("scope_agent", scope.Agent)
+	add("scope_thread", scope.Thread)
+	for _, k := range slices.Sorted(maps.Keys(scope.Custom)) {
+		conds = append(conds, "scope_custom->>"+b.arg(k)+" = "+b.arg(scope.Custom[k]))
+	}
+
+	if len(conds) == 0 {
+		return "TRUE"
+	}
+	return strings.Join(conds, " AND ")
+}
+
+// timeColumns maps time fields to their columns. Using a fixed map keeps
+// column names out of user control.
+var timeColumns = map[vectorstore.TimeField]string{
+	vectorstore.TimeFieldCreatedAt:  "created_at",
+	vectorstore.TimeFieldUpdatedAt:  "updated_at",
+	vectorstore.TimeFieldExpiresAt:  "expires_at",
+	vectorstore.TimeFieldEventTime:  "event_time",
+	vectorstore.TimeFieldValidFrom:  "valid_from",
+	vectorstore.TimeFieldValidUntil: "valid_until",
+}
+
+var comparisonOps = map[vectorstore.FilterOperator]string{
+	vectorstore.OpEqual:              "=",
+	vectorstore.OpNotEqual:           "<>",
+	vectorstore.OpGreaterThan:        ">",
+	vectorstore.OpGreaterThanOrEqual: ">=",
+	vectorstore.OpLessThan:           "<",
+	vectorstore.OpLessThanOrEqual:    "<=",
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+// toStrings converts an In/NotIn value to the text form returned by ->>.
+func toStrings(value any) []string {
+	switch v := value.(type) {
+	case []string:
+		return v
+	case []any:
+		out := make([]string, len(v))
+		for i, item := range v {
+			out[i] = fmt.Sprint(item)
+		}
+		return out
+	default:
+		return []string{fmt.Sprint(value)}
+	}
+}
@@ -0,0 +1,1206 @@
+package pgvector
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// DefaultTablePrefix is prepended to collection names to form table names
+const DefaultTablePrefix = "vectorstore_"
+
+// PostgreSQL error codes
+const (
+	pgUndefinedTable = "42P01"
+)
+
+// maxIdentifierLength is PostgreSQL's limit on table names (NAMEDATALEN - 1)
+const maxIdentifierLength = 63
+
+// collectionNamePattern restricts collection names to characters that are
+// safe in table and index names.
+var collectionNamePattern = regexp.MustCompile(`^[A-Za-z0-9_]+$`)
+
+// DBTX is the subset of the pgx API used by the store. It is satisfied by
+// *pgxpool.Pool, *pgx.Conn, and pgx.Tx, so a store can run inside a
+// caller-managed transaction.
+type DBTX interface {
+	Exec(ctx context.Context, sql string, args ...any) (pgconn.CommandTag, error)
+	Query(ctx context.Context, sql string, args ...any) (pgx.Rows, error)
+	QueryRow(ctx context.Context, sql string, args ...any) pgx.Row
+}
+
+// PgVectorStore implements vectorstore.VectorStore on PostgreSQL with the
+// pgvector extension.
+//
+// Features:
+//   - One table per collection with typed columns for tags, scope, and time
+//   - Server-side similarity search (ORDER BY embedding <=> $1 LIMIT k)
+//   - Tag filters via array containment, metadata filters via jsonb
+//   - Works with a connection pool or inside a caller-managed transaction
+//
+// Important Notes:
+//   - The pgvector extension must be available on the server
+//   - Tables are created on first Upsert, or explicitly with EnsureSchema
+//   - The distance metric is fixed per store (see WithDistance) and determines the index operator class
+type PgVectorStore struct {
+	db          DBTX
+	pool        *pgxpool.Pool // non-nil when the store owns the pool
+	config      *Config
+	collections map[string]*PgVectorCollection
+	mu          sync.RWMutex
+}
+
+// Config holds configuration for PgVectorStore.
+type Config struct {
+	// ConnString is a PostgreSQL connection string used to open a pool.
+	// Ignored when DB is set.
+	ConnString string
+
+	// DB is an existing connection, pool, or transaction to use
+	DB DBTX
+
+	// TablePrefix is prepended to collection names (default: DefaultTablePrefix)
+	TablePrefix string
+
+	// Distance is the similarity metric used for queries and indexes (default: cosine)
+	Distance vectorstore.DistanceMetric
+
+	// CollectionDefaults are applied to every collection before its own options
+	CollectionDefaults []vectorstore.CollectionOption
+}
+
+// Option is a functional option for configuring PgVectorStore.
+type Option func(*Config)
+
+// WithConnString sets the PostgreSQL connection string.
+func WithConnString(connString string) Option {
+	return func(c *Config) {
+		c.ConnString = connString
+	}
+}
+
+// WithDB uses an existing pool, connection, or transaction. The store does
+// not close it.
+func WithDB(db DBTX) Option {
+	return func(c *Config) {
+		c.DB = db
+	}
+}
+
+// WithTablePrefix sets the prefix used to derive table names from collection names.
+func WithTablePrefix(prefix string) Option {
+	return func(c *Config) {
+		c.TablePrefix = prefix
+	}
+}
+
+// WithDistance sets the similarity metric used for queries and indexes.
+func WithDistance(metric vectorstore.DistanceMetric) Option {
+	return func(c *Config) {
+		c.Distance = metric
+	}
+}
+
+// WithCollectionDefaults sets options applied to every collection.
+// Options passed to Collection are applied after the defaults.
+func WithCollectionDefaults(opts ...vectorstore.CollectionOption) Option {
+	return func(c *Config) {
+		c.CollectionDefaults = append(c.CollectionDefaults, opts...)
+	}
+}
+
+// New creates a new PgVectorStore and verifies the database is reachable.
+//
+// Example:
+//
+//	store, err := pgvector.New(ctx,
+//	    pgvector.WithConnString(os.Getenv("DATABASE_URL")),
+//	    pgvector.WithCollectionDefaults(vectorstore.WithDimensions(768)),
+//	)
+func New(ctx context.Context, opts ...Option) (vectorstore.VectorStore, error) {
+	config := &Config{
+		TablePrefix: DefaultTablePrefix,
+		Distance:    vectorstore.DistanceMetricCosine,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.TablePrefix != "" && !collectionNamePattern.MatchString(config.TablePrefix) {
+		return nil, fmt.Errorf("invalid table prefix %q: only letters, digits, and underscores are allowed", config.TablePrefix)
+	}
+	if _, ok := distanceOperator(config.Distance); !ok {
+		return nil, fmt.Errorf("unsupported distance metric: %s", config.Distance)
+	}
+
+	store := &PgVectorStore{
+		db:          config.DB,
+		config:      config,
+		collections: make(map[string]*PgVectorCollection),
+	}
+
+	if store.db == nil {
+		if config.ConnString == "" {
+			return nil, fmt.Errorf("connection string or DB is required")
+		}
+		pool, err := pgxpool.New(ctx, config.ConnString)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create connection pool: %w", err)
+		}
+		store.db = pool
+		store.pool = pool
+	}
+
+	// Verify connectivity
+	var one int
+	if err := store.db.QueryRow(ctx, "SELECT 1").Scan(&one); err != nil {
+		_ = store.Close()
+		return nil, fmt.Errorf("failed to connect to PostgreSQL: %w", err)
+	}
+
+	return store, nil
+}
+
+// Collection returns a collection with the specified name and options.
+// Invalid names yield a collection whose operations all return an error.
+func (s *PgVectorStore) Collection(name string, opts ...vectorstore.CollectionOption) vectorstore.Collection {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if coll, exists := s.collections[name]; exists {
+		return coll
+	}
+
+	all := append(append([]vectorstore.CollectionOption{}, s.config.CollectionDefaults...), opts...)
+	coll := &PgVectorCollection{
+		name:      name,
+		table:     s.config.TablePrefix + name,
+		config:    vectorstore.ApplyOptions(all),
+		db:        s.db,
+		distance:  s.config.Distance,
+		createdAt: time.Now(),
+		updatedAt: time.Now(),
+	}
+	coll.nameErr = validateTableName(name, coll.table)
+
+	s.collections[name] = coll
+	return coll
+}
+
+// ListCollections returns the names of all collections, derived from the
+// tables carrying the store's prefix.
+func (s *PgVectorStore) ListCollections(ctx context.Context) ([]string, error) {
+	tables, err := s.tables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	names := make([]string, len(tables))
+	for i, table := range tables {
+		names[i] = strings.TrimPrefix(table, s.config.TablePrefix)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteCollection permanently deletes a collection and all its documents.
+func (s *PgVectorStore) DeleteCollection(ctx context.Context, name string) error {
+	table := s.config.TablePrefix + name
+	if err := validateTableName(name, table); err != nil {
+		return err
+	}
+
+	_, err := s.db.Exec(ctx, "DROP TABLE "+quote(table))
+	if err != nil {
+		if isUndefinedTable(err) {
+			return fmt.Errorf("collection %q does not exist", name)
+		}
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+
+	s.mu.Lock()
+	if coll, ok := s.collections[name]; ok {
+		coll.mu.Lock()
+		coll.ensured = false
+		coll.mu.Unlock()
+		delete(s.collections, name)
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+// Stats returns statistics about the vector store.
+func (s *PgVectorStore) Stats(ctx context.Context) (*vectorstore.StoreStats, error) {
+	tables, err := s.tables(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	var totalDocs int64
+	for _, table := range tables {
+		var count int64
+		if err := s.db.QueryRow(ctx, "SELECT count(*) FROM "+quote(table)).Scan(&count); err != nil {
+			return nil, fmt.Errorf("failed to count %s: %w", table, err)
+		}
+		totalDocs += count
+	}
+
+	return &vectorstore.StoreStats{
+		Collections: int64(len(tables)),
+		Documents:   totalDocs,
+		Provider:    "pgvector",
+		Version:     "1.0.0",
+	}, nil
+}
+
+// Close closes the connection pool if the store opened it. Connections
+// passed with WithDB are left open.
+func (s *PgVectorStore) Close() error {
+	if s.pool != nil {
+		s.pool.Close()
+	}
+	return nil
+}
+
+// tables returns the collection tables in the current schema. A table
+// belongs to the store if it carries the prefix and has an embedding column.
+func (s *PgVectorStore) tables(ctx context.Context) ([]string, error) {
+	rows, err := s.db.Query(ctx, `
+		SELECT c.table_name::text
+		FROM information_schema.columns c
+		WHERE c.table_schema = current_schema()
+		  AND c.column_name = 'embedding'
+		  AND starts_with(c.table_name::text, $1)
+		ORDER BY 1`, s.config.TablePrefix)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
+
+// PgVectorCollection implements vectorstore.Collection for a PostgreSQL table.
+type PgVectorCollection struct {
+	name      string
+	table     string
+	nameErr   error
+	config    *vectorstore.CollectionConfig
+	db        DBTX
+	distance  vectorstore.DistanceMetric
+	ensured   bool
+	createdAt time.Time
+	updatedAt time.Time
+	metrics   vectorstore.MetricsRecorder
+	mu        sync.RWMutex
+}
+
+// Name returns the collection name.
+func (c *PgVectorCollection) Name() string {
+	return c.name
+}
+
+// EnsureSchema creates the pgvector extension, the collection table with a
+// vector(dim) embedding column, and its indexes if they do not exist. It is
+// called automatically on first Upsert but can be run ahead of time as a
+// migration step.
+func (c *PgVectorCollection) EnsureSchema(ctx context.Context, dim int) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ensureSchema(ctx, dim)
+}
+
+// ensureSchema must be called with c.mu held.
+func (c *PgVectorCollection) ensureSchema(ctx context.Context, dim int) error {
+	if c.nameErr != nil {
+		return c.nameErr
+	}
+	if c.ensured {
+		return nil
+	}
+	if dim <= 0 {
+		return fmt.Errorf("embedding dimensions must be positive, got %d", dim)
+	}
+
+	for _, stmt := range schemaStatements(c.table, dim, c.distance) {
+		if _, err := c.db.Exec(ctx, stmt); err != nil {
+			return fmt.Errorf("failed to ensure schema: %w", err)
+		}
+	}
+
+	c.ensured = true
+	return nil
+}
+
+// Upsert inserts or updates documents in the collection.
+func (c *PgVectorCollection) Upsert(ctx context.Context, documents ...*vectorstore.Document) (*vectorstore.UpsertResult, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+	if len(documents) == 0 {
+		return &vectorstore.UpsertResult{}, nil
+	}
+
+	startTime := time.Now()
+
+	validationStart := time.Now()
+	for i, doc := range documents {
+		if err := vectorstore.Validate(doc); err != nil {
+			return nil, fmt.Errorf("invalid document at index %d: %w", i, err)
+		}
+
+		if doc.Embedding == nil && c.config.AutoGenerateEmbeddings && c.config.EmbeddingFunction != nil {
+			embedding, err := c.config.EmbeddingFunction(doc.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate embedding for document %s: %w", doc.ID, err)
+			}
+			doc.Embedding = embedding
+		}
+		if doc.Embedding == nil {
+			return nil, fmt.Errorf("document %s has no embedding; pgvector requires a vector for every row", doc.ID)
+		}
+
+		if err := vectorstore.CheckDimensions(c.config.EmbeddingDimensions, len(doc.Embedding.Vector)); err != nil {
+			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+
+		if err := c.validateRequiredScope(doc); err != nil {
+			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	validationTime := time.Since(validationStart)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	dim := c.config.EmbeddingDimensions
+	if dim == 0 {
+		dim = len(documents[0].Embedding.Vector)
+	}
+	if err := c.ensureSchema(ctx, dim); err != nil {
+		return nil, err
+	}
+
+	storageStart := time.Now()
+	now := time.Now()
+	result := &vectorstore.UpsertResult{}
+	stmt := upsertStatement(c.table)
+
+	for _, doc := range documents {
+		// Stamp timestamps on a copy so callers' documents are not modified
+		stored := *doc
+		temporal := vectorstore.Temporal{CreatedAt: now}
+		if doc.Temporal != nil {
+			temporal = *doc.Temporal
+			if temporal.CreatedAt.IsZero() {
+				temporal.CreatedAt = now
+			}
+		}
+		temporal.UpdatedAt = now
+		if c.config.TTL > 0 && temporal.ExpiresAt == nil {
+			temporal.SetExpiry(c.config.TTL)
+		}
+		stored.Temporal = &temporal
+
+		args, err := documentArgs(&stored)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode document %s: %w", doc.ID, err)
+		}
+
+		var inserted bool
+		if err := c.db.QueryRow(ctx, stmt, args...).Scan(&inserted); err != nil {
+			return nil, fmt.Errorf("failed to upsert document %s: %w", doc.ID, err)
+		}
+		if inserted {
+			result.Inserted++
+		} else {
+			result.Updated++
+		}
+	}
+
+	c.updatedAt = now
+	result.Timing = &vectorstore.OperationTiming{
+		Total:      time.Since(startTime),
+		Validation: validationTime,
+		Storage:    time.Since(storageStart),
+	}
+	c.metrics.RecordUpsert("pgvector", c.name, int(result.Inserted+result.Updated), result.Timing.Total)
+	return result, nil
+}
+
+// UpsertIfVersion is not supported: the table schema has no version
+// column.
+func (c *PgVectorCollection) UpsertIfVersion(ctx context.Context, doc *vectorstore.Document, expectedVersion int64) (*vectorstore.UpsertResult, error) {
+	return nil, fmt.Errorf("pgvector: %w", vectorstore.ErrVersioningNotSupported)
+}
+
+// UpsertBatch performs batch upsert with progress tracking.
+func (c *PgVectorCollection) UpsertBatch(ctx context.Context, documents []*vectorstore.Document, opts ...vectorstore.BatchOption) (*vectorstore.UpsertResult, error) {
+	if len(documents) == 0 {
+		return &vectorstore.UpsertResult{}, nil
+	}
+
+	config := vectorstore.ApplyBatchOptions(opts)
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	totalResult := &vectorstore.UpsertResult{}
+	processed := 0
+
+	for i := 0; i < len(documents); i += config.BatchSize {
+		end := min(i+config.BatchSize, len(documents))
+		batch := documents[i:end]
+
+		batchResult, err := c.Upsert(ctx, batch...)
+		if err != nil {
+			if !config.ContinueOnError {
+				return totalResult, err
+			}
+			totalResult.Failed += int64(len(batch))
+			for _, doc := range batch {
+				totalResult.FailedIDs = append(totalResult.FailedIDs, doc.ID)
+				totalResult.Errors = append(totalResult.Errors, err)
+			}
+		} else {
+			totalResult.Inserted += batchResult.Inserted
+			totalResult.Updated += batchResult.Updated
+		}
+
+		processed += len(batch)
+		if config.ProgressCallback != nil {
+			config.ProgressCallback(processed, len(documents))
+		}
+
+		if err := ctx.Err(); err != nil {
+			return totalResult, err
+		}
+	}
+
+	return totalResult, nil
+}
+
+// Query performs similarity search and returns matching documents.
+func (c *PgVectorCollection) Query(ctx context.Context, query *vectorstore.Query) (*vectorstore.QueryResult, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if query.Metric != "" && query.Metric != c.distance {
+		return nil, fmt.Errorf("collection %s uses %s distance, query requested %s", c.name, c.distance, query.Metric)
+	}
+	if len(query.Keywords) > 0 {
+		return nil, fmt.Errorf("pgvector: %w", vectorstore.ErrHybridSearchNotSupported)
+	}
+
+	startTime := time.Now()
+	timing := &vectorstore.QueryTiming{}
+
+	filterStart := time.Now()
+	sql, args, err := buildQuery(c.table, query, c.distance)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	timing.FilterApplication = time.Since(filterStart)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	searchStart := time.Now()
+	rows, err := c.db.Query(ctx, sql, args...)
+	if err != nil {
+		if isUndefinedTable(err) {
+			timing.Total = time.Since(startTime)
+			c.metrics.RecordQuery("pgvector", c.name, timing.Total, 0)
+			return &vectorstore.QueryResult{
+				Matches: []*vectorstore.Match{},
+				Offset:  query.Offset,
+				Limit:   query.Limit,
+				Timing:  timing,
+			}, nil
+		}
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	matches := []*vectorstore.Match{}
+	for rows.Next() {
+		var r row
+		var score, distance float64
+		if err := rows.Scan(append(r.dest(), &score, &distance)...); err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		doc, err := r.document(query.IncludeEmbeddings)
+		if err != nil {
+			rows.Close()
+			return nil, err
+		}
+		matches = append(matches, &vectorstore.Match{
+			Document: doc,
+			Score:    float32(score),
+			Distance: float32(distance),
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to query: %w", err)
+	}
+
+	if query.Embedding != nil {
+		timing.VectorSearch = time.Since(searchStart)
+	} else {
+		timing.Retrieval = time.Since(searchStart)
+	}
+
+	for i, m := range matches {
+		m.Rank = i + 1 + query.Offset
+		m.Document.Score = m.Score
+		m.Document.Distance = m.Distance
+	}
+
+	timing.Total = time.Since(startTime)
+	c.metrics.RecordQuery("pgvector", c.name, timing.Total, len(matches))
+
+	return &vectorstore.QueryResult{
+		Matches: matches,
+		Total:   int64(len(matches)),
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Timing:  timing,
+	}, nil
+}
+
+// QueryStream performs similarity search and streams results via an iterator.
+func (c *PgVectorCollection) QueryStream(ctx context.Context, query *vectorstore.Query) (vectorstore.ResultIterator, error) {
+	result, err := c.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstore.NewSliceIterator(result.Matches), nil
+}
+
+// Get retrieves documents by their IDs.
+func (c *PgVectorCollection) Get(ctx context.Context, ids ...string) ([]*vectorstore.Document, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+	if len(ids) == 0 {
+		return []*vectorstore.Document{}, nil
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	rows, err := c.db.Query(ctx, "SELECT "+selectColumns+" FROM "+quote(c.table)+" WHERE id = ANY($1) AND "+notExpired, ids)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return []*vectorstore.Document{}, nil
+		}
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	defer rows.Close()
+
+	docs := make([]*vectorstore.Document, 0, len(ids))
+	for rows.Next() {
+		var r row
+		if err := rows.Scan(r.dest()...); err != nil {
+			return nil, fmt.Errorf("failed to scan row: %w", err)
+		}
+		doc, err := r.document(true)
+		if err != nil {
+			return nil, err
+		}
+		docs = append(docs, doc)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+	return docs, nil
+}
+
+// Delete removes documents by their IDs.
+func (c *PgVectorCollection) Delete(ctx context.Context, ids ...string) (*vectorstore.DeleteResult, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+	if len(ids) == 0 {
+		return &vectorstore.DeleteResult{}, nil
+	}
+
+	startTime := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	result := &vectorstore.DeleteResult{}
+	found := make(map[string]bool, len(ids))
+
+	rows, err := c.db.Query(ctx, "DELETE FROM "+quote(c.table)+" WHERE id = ANY($1) RETURNING id", ids)
+	if err != nil && !isUndefinedTable(err) {
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+	if err == nil {
+		deleted, err := pgx.CollectRows(rows, pgx.RowTo[string])
+		if err != nil {
+			return nil, fmt.Errorf("failed to delete documents: %w", err)
+		}
+		for _, id := range deleted {
+			found[id] = true
+		}
+		result.Deleted = int64(len(deleted))
+	}
+
+	for _, id := range ids {
+		if !found[id] {
+			result.NotFound++
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+		}
+	}
+	if result.Deleted > 0 {
+		c.updatedAt = time.Now()
+	}
+
+	result.Timing = &vectorstore.OperationTiming{Total: time.Since(startTime)}
+	return result, nil
+}
+
+// DeleteByFilter removes all documents matching the filter.
+func (c *PgVectorCollection) DeleteByFilter(ctx context.Context, f vectorstore.Filter) (*vectorstore.DeleteResult, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+
+	startTime := time.Now()
+
+	b := &sqlBuilder{scoreExpr: "1"}
+	where, err := b.where(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	tag, err := c.db.Exec(ctx, "DELETE FROM "+quote(c.table)+" WHERE "+where, b.args...)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return &vectorstore.DeleteResult{Timing: &vectorstore.OperationTiming{Total: time.Since(startTime)}}, nil
+		}
+		return nil, fmt.Errorf("failed to delete documents: %w", err)
+	}
+	c.updatedAt = time.Now()
+
+	return &vectorstore.DeleteResult{
+		Deleted: tag.RowsAffected(),
+		Timing:  &vectorstore.OperationTiming{Total: time.Since(startTime)},
+	}, nil
+}
+
+// Count returns the number of documents in the collection.
+func (c *PgVectorCollection) Count(ctx context.Context, f vectorstore.Filter) (int64, error) {
+	if c.nameErr != nil {
+		return 0, c.nameErr
+	}
+
+	b := &sqlBuilder{scoreExpr: "1"}
+	where, err := b.where(f)
+	if err != nil {
+		return 0, fmt.Errorf("invalid filter: %w", err)
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var count int64
+	if err := c.db.QueryRow(ctx, "SELECT count(*) FROM "+quote(c.table)+" WHERE "+unexpired(where), b.args...).Scan(&count); err != nil {
+		if isUndefinedTable(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	return count, nil
+}
+
+// Metrics returns the query and upsert metrics recorded by the collection.
+func (c *PgVectorCollection) Metrics() vectorstore.CollectionMetrics {
+	return c.metrics.Snapshot()
+}
+
+// Stats returns statistics about the collection.
+func (c *PgVectorCollection) Stats(ctx context.Context) (*vectorstore.CollectionStats, error) {
+	if c.nameErr != nil {
+		return nil, c.nameErr
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := &vectorstore.CollectionStats{
+		Name:                c.name,
+		EmbeddingDimensions: c.config.EmbeddingDimensions,
+		IndexType:           string(vectorstore.IndexTypeHNSW),
+		CreatedAt:           vectorstore.NewTimestamp(c.createdAt),
+		UpdatedAt:           vectorstore.NewTimestamp(c.updatedAt),
+	}
+
+	var count, sizeBytes int64
+	err := c.db.QueryRow(ctx,
+		"SELECT count(*), pg_total_relation_size($1::regclass) FROM "+quote(c.table),
+		quote(c.table)).Scan(&count, &sizeBytes)
+	if err != nil {
+		if isUndefinedTable(err) {
+			return stats, nil
+		}
+		return nil, fmt.Errorf("failed to get collection stats: %w", err)
+	}
+
+	stats.Documents = count
+	stats.StorageBytes = sizeBytes
+	stats.Extra = map[string]any{
+		"table":    c.table,
+		"distance": string(c.distance),
+	}
+	return stats, nil
+}
+
+// Clear removes all documents from the collection.
+func (c *PgVectorCollection) Clear(ctx context.Context) error {
+	if c.nameErr != nil {
+		return c.nameErr
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, err := c.db.Exec(ctx, "DELETE FROM "+quote(c.table)); err != nil {
+		if isUndefinedTable(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to clear collection: %w", err)
+	}
+	c.updatedAt = time.Now()
+	return nil
+}
+
+// validateRequiredScope validates that document has required scope fields.
+func (c *PgVectorCollection) validateRequiredScope(doc *vectorstore.Document) error {
+	if len(c.config.ScopeRequired) == 0 {
+		return nil
+	}
+	if doc.Scope == nil {
+		return fmt.Errorf("scope is required but not provided")
+	}
+
+	for _, field := range c.config.ScopeRequired {
+		var value string
+		switch field {
+		case "tenant":
+			value = doc.Scope.Tenant
+		case "user":
+			value = doc.Scope.User
+		case "session":
+			value = doc.Scope.Session
+		case "agent":
+			value = doc.Scope.Agent
+		case "thread":
+			value = doc.Scope.Thread
+		default:
+			value = doc.Scope.Custom[field]
+		}
+		if value == "" {
+			return fmt.Errorf("required scope field '%s' is empty", field)
+		}
+	}
+	return nil
+}
+
+// SQL
+
+// schemaStatements returns the DDL for a collection table and its indexes.
+func schemaStatements(table string, dim int, metric vectorstore.DistanceMetric) []string {
+	opClass := indexOperatorClass(metric)
+	t := quote(table)
+	return []string{
+		"CREATE EXTENSION IF NOT EXISTS vector",
+		fmt.Sprintf(`CREATE TABLE IF NOT EXISTS %s (
+	id text PRIMARY KEY,
+	embedding vector(%d) NOT NULL,
+	embedding_model text,
+	embedding_normalized boolean NOT NULL DEFAULT false,
+	content jsonb,
+	tags text[] NOT NULL DEFAULT '{}',
+	scope_tenant text,
+	scope_user text,
+	scope_session text,
+	scope_agent text,
+	scope_thread text,
+	scope_custom jsonb,
+	metadata jsonb,
+	created_at timestamptz NOT NULL DEFAULT now(),
+	updated_at timestamptz NOT NULL DEFAULT now(),
+	expires_at timestamptz,
+	event_time timestamptz,
+	valid_from timestamptz,
+	valid_until timestamptz
+)`, t, dim),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING hnsw (embedding %s)", quote(table+"_embedding_idx"), t, opClass),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s USING gin (tags)", quote(table+"_tags_idx"), t),
+		fmt.Sprintf("CREATE INDEX IF NOT EXISTS %s ON %s (scope_tenant, scope_user)", quote(table+"_scope_idx"), t),
+	}
+}
+
+// upsertStatement inserts a row or replaces it by id. The returned boolean
+// is true for inserts (xmax is zero for freshly inserted tuples).
+func upsertStatement(table string) string {
+	return `INSERT INTO ` + quote(table) + ` (` + insertColumns + `)
+VALUES ($1, $2::vector, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18, $19)
+ON CONFLICT (id) DO UPDATE SET
+	embedding = EXCLUDED.embedding,
+	embedding_model = EXCLUDED.embedding_model,
+	embedding_normalized = EXCLUDED.embedding_normalized,
+	content = EXCLUDED.content,
+	tags = EXCLUDED.tags,
+	scope_tenant = EXCLUDED.scope_tenant,
+	scope_user = EXCLUDED.scope_user,
+	scope_session = EXCLUDED.scope_session,
+	scope_agent = EXCLUDED.scope_agent,
+	scope_thread = EXCLUDED.scope_thread,
+	scope_custom = EXCLUDED.scope_custom,
+	metadata = EXCLUDED.metadata,
+	created_at = EXCLUDED.created_at,
+	updated_at = EXCLUDED.updated_at,
+	expires_at = EXCLUDED.expires_at,
+	event_time = EXCLUDED.event_time,
+	valid_from = EXCLUDED.valid_from,
+	valid_until = EXCLUDED.valid_until
+RETURNING (xmax = 0)`
+}
+
+const insertColumns = "id, embedding, embedding_model, embedding_normalized, content, tags, " +
+	"scope_tenant, scope_user, scope_session, scope_agent, scope_thread, scope_custom, metadata, " +
+	"created_at, updated_at, expires_at, event_time, valid_from, valid_until"
+
+// selectColumns matches the field order of row.dest.
+const selectColumns = "id, embedding::text, embedding_model, embedding_normalized, content, tags, " +
+	"scope_tenant, scope_user, scope_session, scope_agent, scope_thread, scope_custom, metadata, " +
+	"created_at, updated_at, expires_at, event_time, valid_from, valid_until"
+
+// notExpired matches rows whose TTL has not passed. Expired rows stay in the
+// table until deleted but are never returned or counted.
+const notExpired = "(expires_at IS NULL OR expires_at > now())"
+
+// unexpired restricts a WHERE condition to rows that have not expired
+func unexpired(where string) string {
+	return "(" + where + ") AND " + notExpired
+}
+
+// buildQuery builds the SELECT for a query. Vector queries are ordered by
+// distance so the HNSW index can be used; filter-only queries report a
+// score of 1 and are ordered by id.
+func buildQuery(table string, query *vectorstore.Query, metric vectorstore.DistanceMetric) (string, []any, error) {
+	b := &sqlBuilder{scoreExpr: "1"}
+
+	distanceExpr, scoreExpr := "0::float8", "1::float8"
+	orderBy := "id"
+	if query.Embedding != nil {
+		op, _ := distanceOperator(metric)
+		distanceExpr = "(embedding " + op + " " + b.arg(encodeVector(query.Embedding.Vector)) + "::vector)"
+		scoreExpr = scoreExpression(distanceExpr, metric)
+		b.scoreExpr = scoreExpr
+		orderBy = distanceExpr
+	}
+
+	where, err := b.where(query.Filters)
+	if err != nil {
+		return "", nil, err
+	}
+	if query.MinScore > 0 {
+		where = "(" + where + ") AND " + scoreExpr + " >= " + b.arg(float64(query.MinScore))
+	}
+
+	sql := "SELECT " + selectColumns + ", " + scoreExpr + " AS score, " + distanceExpr + " AS distance" +
+		" FROM " + quote(table) +
+		" WHERE " + unexpired(where) +
+		" ORDER BY " + orderBy +
+		" LIMIT " + b.arg(query.Limit) +
+		" OFFSET " + b.arg(query.Offset)
+	return sql, b.args, nil
+}
+
+// Scoring
+
+// distanceOperator maps a vectorstore metric to the pgvector distance operator.
+func distanceOperator(metric vectorstore.DistanceMetric) (string, bool) {
+	switch metric {
+	case "", vectorstore.DistanceMetricCosine:
+		return "<=>", true
+	case vectorstore.DistanceMetricEuclidean:
+		return "<->", true
+	case vectorstore.DistanceMetricDotProduct:
+		return "<#>", true
+	default:
+		return "", false
+	}
+}
+
+// indexOperatorClass maps a vectorstore metric to the HNSW operator class.
+func indexOperatorClass(metric vectorstore.DistanceMetric) string {
+	switch metric {
+	case vectorstore.DistanceMetricEuclidean:
+		return "vector_l2_ops"
+	case vectorstore.DistanceMetricDotProduct:
+		return "vector_ip_ops"
+	default:
+		return "vector_cosine_ops"
+	}
+}
+
+// scoreExpression converts a pgvector distance into the score conventions
+// used by the other vector stores: cosine similarity for cosine, the dot
+// product for dot (<#> returns its negation), and 1 / (1 + distance) for
+// Euclidean.
+func scoreExpression(distanceExpr string, metric vectorstore.DistanceMetric) string {
+	switch metric {
+	case vectorstore.DistanceMetricEuclidean:
+		return "(1.0 / (1.0 + " + distanceExpr + "))"
+	case vectorstore.DistanceMetricDotProduct:
+		return "(-1.0 * " + distanceExpr + ")"
+	default:
+		return "(1.0 - " + distanceExpr + ")"
+	}
+}
+
+// Conversion
+
+// row holds the scanned columns of a collection row.
+type row struct {
+	id         string
+	embedding  string
+	model      *string
+	normalized bool
+	content    []byte
+	tags       []string
+	tenant     *string
+	user       *string
+	session    *string
+	agent      *string
+	thread     *string
+	custom     []byte
+	metadata   []byte
+	createdAt  time.Time
+	updatedAt  time.Time
+	expiresAt  *time.Time
+	eventTime  *time.Time
+	validFrom  *time.Time
+	validUntil *time.Time
+}
+
+// dest returns scan destinations in selectColumns order.
+func (r *row) dest() []any {
+	return []any{
+		&r.id, &r.embedding, &r.model, &r.normalized, &r.content, &r.tags,
+		&r.tenant, &r.user, &r.session, &r.agent, &r.thread, &r.custom, &r.metadata,
+		&r.createdAt, &r.updatedAt, &r.expiresAt, &r.eventTime, &r.validFrom, &r.validUntil,
+	}
+}
+
+// document converts a scanned row to a vectorstore.Document.
+func (r *row) document(withEmbedding bool) (*vectorstore.Document, error) {
+	doc := &vectorstore.Document{
+		ID:   r.id,
+		Tags: r.tags,
+		Temporal: &vectorstore.Temporal{
+			CreatedAt:  r.createdAt,
+			UpdatedAt:  r.updatedAt,
+			ExpiresAt:  r.expiresAt,
+			EventTime:  r.eventTime,
+			ValidFrom:  r.validFrom,
+			ValidUntil: r.validUntil,
+		},
+	}
+	if len(doc.Tags) == 0 {
+		doc.Tags = nil
+	}
+
+	if len(r.content) > 0 {
+		doc.Content = &vectorstore.Content{}
+		if err := json.Unmarshal(r.content, doc.Content); err != nil {
+			return nil, fmt.Errorf("failed to decode content of %s: %w", r.id, err)
+		}
+	}
+
+	if withEmbedding {
+		vector, err := decodeVector(r.embedding)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decode embedding of %s: %w", r.id, err)
+		}
+		doc.Embedding = &vectorstore.Embedding{
+			Vector:     vector,
+			Model:      deref(r.model),
+			Dimensions: len(vector),
+			Normalized: r.normalized,
+		}
+	}
+
+	scope := &vectorstore.Scope{
+		Tenant:  deref(r.tenant),
+		User:    deref(r.user),
+		Session: deref(r.session),
+		Agent:   deref(r.agent),
+		Thread:  deref(r.thread),
+	}
+	if len(r.custom) > 0 {
+		if err := json.Unmarshal(r.custom, &scope.Custom); err != nil {
+			return nil, fmt.Errorf("failed to decode scope of %s: %w", r.id, err)
+		}
+	}
+	if scope.Tenant != "" || scope.User != "" || scope.Session != "" ||
+		scope.Agent != "" || scope.Thread != "" || len(scope.Custom) > 0 {
+		doc.Scope = scope
+	}
+
+	if len(r.metadata) > 0 {
+		if err := json.Unmarshal(r.metadata, &doc.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to decode metadata of %s: %w", r.id, err)
+		}
+	}
+
+	return doc, nil
+}
+
+// documentArgs returns the upsert arguments for doc in insertColumns order.
+func documentArgs(doc *vectorstore.Document) ([]any, error) {
+	var content, custom, metadata []byte
+	var err error
+
+	if doc.Content != nil {
+		if content, err = json.Marshal(doc.Content); err != nil {
+			return nil, err
+		}
+	}
+
+	var tenant, user, session, agent, thread *string
+	if doc.Scope != nil {
+		tenant = nullable(doc.Scope.Tenant)
+		user = nullable(doc.Scope.User)
+		session = nullable(doc.Scope.Session)
+		agent = nullable(doc.Scope.Agent)
+		thread = nullable(doc.Scope.Thread)
+		if len(doc.Scope.Custom) > 0 {
+			if custom, err = json.Marshal(doc.Scope.Custom); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if len(doc.Metadata) > 0 {
+		if metadata, err = json.Marshal(doc.Metadata); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := doc.Tags
+	if tags == nil {
+		tags = []string{}
+	}
+
+	t := doc.Temporal
+	return []any{
+		doc.ID,
+		encodeVector(doc.Embedding.Vector),
+		nullable(doc.Embedding.Model),
+		doc.Embedding.Normalized,
+		content,
+		tags,
+		tenant, user, session, agent, thread,
+		custom,
+		metadata,
+		t.CreatedAt, t.UpdatedAt, t.ExpiresAt, t.EventTime, t.ValidFrom, t.ValidUntil,
+	}, nil
+}
+
+// encodeVector formats a vector in pgvector's text representation, e.g. "[1,2.5,3]".
+func encodeVector(v []float32) string {
+	var sb strings.Builder
+	sb.Grow(len(v)*8 + 2)
+	sb.WriteByte('[')
+	for i, f := range v {
+		if i > 0 {
+			sb.WriteByte(',')
+		}
+		sb.WriteString(strconv.FormatFloat(float64(f), 'g', -1, 32))
+	}
+	sb.WriteByte(']')
+	return sb.String()
+}
+
+// decodeVector parses pgvector's text representation.
+func decodeVector(s string) ([]float32, error) {
+	s = strings.TrimSpace(s)
+	if len(s) < 2 || s[0] != '[' || s[len(s)-1] != ']' {
+		return nil, fmt.Errorf("invalid vector literal %q", s)
+	}
+	s = s[1 : len(s)-1]
+	if s == "" {
+		return []float32{}, nil
+	}
+
+	parts := strings.Split(s, ",")
+	out := make([]float32, len(parts))
+	for i, p := range parts {
+		f, err := strconv.ParseFloat(strings.TrimSpace(p), 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid vector element %q: %w", p, err)
+		}
+		out[i] = float32(f)
+	}
+	return out, nil
+}
+
+func validateTableName(name, table string) error {
+	if !collectionNamePattern.MatchString(name) {
+		return fmt.Errorf("invalid collection name %q: only letters, digits, and underscores are allowed", name)
+	}
+	// Leave room for the index name suffixes
+	if len(table)+len("_embedding_idx") > maxIdentifierLength {
+		return fmt.Errorf("collection name %q is too long", name)
+	}
+	return nil
+}
+
+func quote(identifier string) string {
+	return pgx.Identifier{identifier}.Sanitize()
+}
+
+func isUndefinedTable(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == pgUndefinedTable
+}
+
+func nullable(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
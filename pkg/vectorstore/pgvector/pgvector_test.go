@@ -0,0 +1,334 @@
+package pgvector
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWhere(t *testing.T) {
+	ts := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		filter   vectorstore.Filter
+		expected string
+		args     []any
+	}{
+		{
+			name:     "nil",
+			filter:   nil,
+			expected: "TRUE",
+		},
+		{
+			name:     "tag",
+			filter:   vectorstore.TagFilter("docs"),
+			expected: "tags @> ARRAY[$1]::text[]",
+			args:     []any{"docs"},
+		},
+		{
+			name:     "all tags",
+			filter:   vectorstore.TagsFilter("a", "b"),
+			expected: "(tags @> ARRAY[$1]::text[]) AND (tags @> ARRAY[$2]::text[])",
+			args:     []any{"a", "b"},
+		},
+		{
+			name:     "any tag",
+			filter:   vectorstore.AnyTagFilter("a", "b"),
+			expected: "(tags @> ARRAY[$1]::text[]) OR (tags @> ARRAY[$2]::text[])",
+			args:     []any{"a", "b"},
+		},
+		{
+			name:     "not tag",
+			filter:   vectorstore.Not(vectorstore.TagFilter("draft")),
+			expected: "NOT (tags @> ARRAY[$1]::text[])",
+			args:     []any{"draft"},
+		},
+		{
+			name:     "scope",
+			filter:   vectorstore.ScopeFilter(&vectorstore.Scope{Tenant: "t1", User: "u1", Custom: map[string]string{"b": "2", "a": "1"}}),
+			expected: "scope_tenant = $1 AND scope_user = $2 AND scope_custom->>$3 = $4 AND scope_custom->>$5 = $6",
+			args:     []any{"t1", "u1", "a", "1", "b", "2"},
+		},
+		{
+			name:     "metadata equality",
+			filter:   vectorstore.Eq("category", "news"),
+			expected: "metadata @> $1::jsonb",
+			args:     []any{`{"category":"news"}`},
+		},
+		{
+			name:     "metadata inequality",
+			filter:   vectorstore.Ne("rating", 5),
+			expected: "NOT COALESCE(metadata @> $1::jsonb, FALSE)",
+			args:     []any{`{"rating":5}`},
+		},
+		{
+			name:     "numeric range",
+			filter:   vectorstore.Gte("rating", 4),
+			expected: "(metadata->>$1)::double precision >= $2",
+			args:     []any{"rating", 4.0},
+		},
+		{
+			name:     "string range",
+			filter:   vectorstore.Lt("name", "m"),
+			expected: "metadata->>$1 < $2",
+			args:     []any{"name", "m"},
+		},
+		{
+			name:     "in",
+			filter:   vectorstore.In("status", "open", "pending"),
+			expected: "metadata->>$1 = ANY($2::text[])",
+			args:     []any{"status", []string{"open", "pending"}},
+		},
+		{
+			name:     "contains",
+			filter:   vectorstore.Contains("title", "go"),
+			expected: "strpos(metadata->>$1, $2) > 0",
+			args:     []any{"title", "go"},
+		},
+		{
+			name:     "ends with",
+			filter:   vectorstore.EndsWith("file", ".md"),
+			expected: "right(metadata->>$1, length($2)) = $2",
+			args:     []any{"file", ".md"},
+		},
+		{
+			name:     "exists",
+			filter:   vectorstore.Exists("author"),
+			expected: "COALESCE(metadata ? $1, FALSE)",
+			args:     []any{"author"},
+		},
+		{
+			name:     "time",
+			filter:   vectorstore.CreatedAfter(ts),
+			expected: "created_at > $1",
+			args:     []any{ts},
+		},
+		{
+			name:     "score",
+			filter:   vectorstore.ScoreAtLeast(0.5),
+			expected: "(1) >= $1",
+			args:     []any{float32(0.5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &sqlBuilder{scoreExpr: "1"}
+			sql, err := b.where(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, sql)
+			assert.Equal(t, tt.args, b.args)
+		})
+	}
+}
+
+func TestWhere_Errors(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter vectorstore.Filter
+	}{
+		{"range on bool", vectorstore.Gt("flag", true)},
+		{"contains on number", vectorstore.FieldFilter("n", vectorstore.OpContains, 5)},
+		{"unsupported time operator", vectorstore.TimeFilter(vectorstore.TimeFieldCreatedAt, vectorstore.OpIn, time.Now())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := &sqlBuilder{scoreExpr: "1"}
+			_, err := b.where(tt.filter)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestBuildQuery(t *testing.T) {
+	query := &vectorstore.Query{
+		Embedding: vectorstore.NewEmbedding([]float32{0.1, 0.2, 0.3}, "test"),
+		Filters:   vectorstore.TagFilter("docs"),
+		Limit:     5,
+		MinScore:  0.7,
+	}
+
+	sql, args, err := buildQuery("vectorstore_docs", query, vectorstore.DistanceMetricCosine)
+	require.NoError(t, err)
+
+	assert.Contains(t, sql, `FROM "vectorstore_docs"`)
+	assert.Contains(t, sql, "(1.0 - (embedding <=> $1::vector)) AS score")
+	assert.Contains(t, sql, "WHERE ((tags @> ARRAY[$2]::text[]) AND (1.0 - (embedding <=> $1::vector)) >= $3) AND (expires_at IS NULL OR expires_at > now())")
+	assert.Contains(t, sql, "ORDER BY (embedding <=> $1::vector) LIMIT $4 OFFSET $5")
+	assert.Equal(t, []any{"[0.1,0.2,0.3]", "docs", float64(float32(0.7)), 5, 0}, args)
+}
+
+func TestBuildQuery_Metrics(t *testing.T) {
+	query := &vectorstore.Query{
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0}, "test"),
+		Filters:   vectorstore.ScoreAbove(0.5),
+		Limit:     1,
+	}
+
+	sql, _, err := buildQuery("t", query, vectorstore.DistanceMetricEuclidean)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "ORDER BY (embedding <-> $1::vector)")
+	assert.Contains(t, sql, "((1.0 / (1.0 + (embedding <-> $1::vector)))) > $2")
+
+	sql, _, err = buildQuery("t", query, vectorstore.DistanceMetricDotProduct)
+	require.NoError(t, err)
+	assert.Contains(t, sql, "ORDER BY (embedding <#> $1::vector)")
+	assert.Contains(t, sql, "((-1.0 * (embedding <#> $1::vector))) > $2")
+}
+
+func TestBuildQuery_FilterOnly(t *testing.T) {
+	query := &vectorstore.Query{
+		Filters: vectorstore.TenantFilter("acme"),
+		Limit:   10,
+		Offset:  20,
+	}
+
+	sql, args, err := buildQuery("t", query, vectorstore.DistanceMetricCosine)
+	require.NoError(t, err)
+	assert.NotContains(t, sql, "<=>")
+	assert.Contains(t, sql, "1::float8 AS score, 0::float8 AS distance")
+	assert.Contains(t, sql, "WHERE (scope_tenant = $1) AND (expires_at IS NULL OR expires_at > now()) ORDER BY id LIMIT $2 OFFSET $3")
+	assert.Equal(t, []any{"acme", 10, 20}, args)
+}
+
+func TestSchemaStatements(t *testing.T) {
+	stmts := schemaStatements("vectorstore_docs", 768, vectorstore.DistanceMetricCosine)
+	require.Len(t, stmts, 5)
+
+	assert.Equal(t, "CREATE EXTENSION IF NOT EXISTS vector", stmts[0])
+	assert.Contains(t, stmts[1], `CREATE TABLE IF NOT EXISTS "vectorstore_docs"`)
+	assert.Contains(t, stmts[1], "embedding vector(768) NOT NULL")
+	assert.Contains(t, stmts[1], "tags text[]")
+	assert.Contains(t, stmts[2], "USING hnsw (embedding vector_cosine_ops)")
+	assert.Contains(t, stmts[3], "USING gin (tags)")
+
+	stmts = schemaStatements("t", 3, vectorstore.DistanceMetricEuclidean)
+	assert.Contains(t, stmts[2], "vector_l2_ops")
+}
+
+func TestVectorEncoding(t *testing.T) {
+	v := []float32{0.1, -2.5, 3, 1e-7}
+	encoded := encodeVector(v)
+	assert.Equal(t, "[0.1,-2.5,3,1e-07]", encoded)
+
+	decoded, err := decodeVector(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, v, decoded)
+
+	decoded, err = decodeVector("[]")
+	require.NoError(t, err)
+	assert.Empty(t, decoded)
+
+	_, err = decodeVector("1,2")
+	assert.Error(t, err)
+	_, err = decodeVector("[1,x]")
+	assert.Error(t, err)
+}
+
+func TestDocumentRowRoundTrip(t *testing.T) {
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	expires := created.Add(time.Hour)
+	doc := &vectorstore.Document{
+		ID:        "doc-1",
+		Content:   vectorstore.NewTextContent("hello"),
+		Embedding: vectorstore.NewEmbedding([]float32{1, 2, 3}, "model-x"),
+		Tags:      []string{"a", "b"},
+		Scope:     &vectorstore.Scope{Tenant: "t1", Custom: map[string]string{"team": "x"}},
+		Temporal:  &vectorstore.Temporal{CreatedAt: created, UpdatedAt: created, ExpiresAt: &expires},
+		Metadata:  map[string]any{"rating": 4.5},
+	}
+
+	args, err := documentArgs(doc)
+	require.NoError(t, err)
+	require.Len(t, args, len(strings.Split(insertColumns, ",")))
+
+	// Simulate what the database returns for the row
+	r := row{
+		id:         args[0].(string),
+		embedding:  args[1].(string),
+		model:      args[2].(*string),
+		normalized: args[3].(bool),
+		content:    args[4].([]byte),
+		tags:       args[5].([]string),
+		tenant:     args[6].(*string),
+		custom:     args[11].([]byte),
+		metadata:   args[12].([]byte),
+		createdAt:  created,
+		updatedAt:  created,
+		expiresAt:  &expires,
+	}
+	assert.Nil(t, args[7], "empty scope fields are stored as NULL")
+
+	got, err := r.document(true)
+	require.NoError(t, err)
+	assert.Equal(t, doc.ID, got.ID)
+	assert.Equal(t, doc.Content, got.Content)
+	assert.Equal(t, doc.Embedding.Vector, got.Embedding.Vector)
+	assert.Equal(t, "model-x", got.Embedding.Model)
+	assert.Equal(t, doc.Tags, got.Tags)
+	assert.Equal(t, doc.Scope, got.Scope)
+	assert.Equal(t, doc.Metadata, got.Metadata)
+	assert.Equal(t, expires, *got.Temporal.ExpiresAt)
+
+	got, err = r.document(false)
+	require.NoError(t, err)
+	assert.Nil(t, got.Embedding)
+}
+
+func TestValidateTableName(t *testing.T) {
+	assert.NoError(t, validateTableName("docs_2", "vectorstore_docs_2"))
+	assert.Error(t, validateTableName("docs; DROP TABLE x", "vectorstore_docs; DROP TABLE x"))
+	assert.Error(t, validateTableName("", "vectorstore_"))
+
+	long := strings.Repeat("a", 60)
+	assert.Error(t, validateTableName(long, "vectorstore_"+long))
+}
+
+func TestNew_Errors(t *testing.T) {
+	ctx := context.Background()
+
+	_, err := New(ctx)
+	assert.ErrorContains(t, err, "connection string or DB is required")
+
+	_, err = New(ctx, WithConnString("postgres://localhost/db"), WithDistance("manhattan"))
+	assert.ErrorContains(t, err, "unsupported distance metric")
+
+	_, err = New(ctx, WithConnString("postgres://localhost/db"), WithTablePrefix("bad-prefix"))
+	assert.ErrorContains(t, err, "invalid table prefix")
+}
+
+func TestCollection_InvalidName(t *testing.T) {
+	store := &PgVectorStore{
+		config:      &Config{TablePrefix: DefaultTablePrefix},
+		collections: make(map[string]*PgVectorCollection),
+	}
+	coll := store.Collection("bad name")
+
+	_, err := coll.Query(context.Background(), vectorstore.NewFilterQuery(nil))
+	assert.ErrorContains(t, err, "invalid collection name")
+
+	err = coll.(*PgVectorCollection).EnsureSchema(context.Background(), 3)
+	assert.ErrorContains(t, err, "invalid collection name")
+}
+
+func TestUpsert_DimensionMismatch(t *testing.T) {
+	store := &PgVectorStore{
+		config:      &Config{TablePrefix: DefaultTablePrefix},
+		collections: make(map[string]*PgVectorCollection),
+	}
+	coll := store.Collection("docs", vectorstore.WithDimensions(3))
+
+	_, err := coll.Upsert(context.Background(), &vectorstore.Document{
+		ID:        "a",
+		Content:   vectorstore.NewTextContent("alpha"),
+		Embedding: vectorstore.NewEmbedding([]float32{1, 0}, "test"),
+	})
+	assert.ErrorIs(t, err, vectorstore.ErrDimensionMismatch)
+}
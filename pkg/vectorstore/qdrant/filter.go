@@ -0,0 +1,321 @@
+package qdrant
+
+import (
+	"fmt"
+	"maps"
+	"slices"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+)
+
+// filter is a Qdrant filter clause. A filter may itself be used as a
+// condition inside another filter, which is how nested And/Or/Not are expressed.
+type filter struct {
+	Must    []any `json:"must,omitempty"`
+	Should  []any `json:"should,omitempty"`
+	MustNot []any `json:"must_not,omitempty"`
+}
+
+// fieldCondition matches a single payload key.
+type fieldCondition struct {
+	Key   string      `json:"key"`
+	Match *matchValue `json:"match,omitempty"`
+	Range *rangeValue `json:"range,omitempty"`
+}
+
+// matchValue is a Qdrant match clause; exactly one field is set.
+type matchValue struct {
+	Value  any    `json:"value,omitempty"`
+	Any    []any  `json:"any,omitempty"`
+	Except []any  `json:"except,omitempty"`
+	Text   string `json:"text,omitempty"`
+}
+
+// rangeValue is a Qdrant numeric range clause.
+type rangeValue struct {
+	Gt  *float64 `json:"gt,omitempty"`
+	Gte *float64 `json:"gte,omitempty"`
+	Lt  *float64 `json:"lt,omitempty"`
+	Lte *float64 `json:"lte,omitempty"`
+}
+
+// isEmptyCondition matches points where key is missing, null, or an empty array.
+type isEmptyCondition struct {
+	IsEmpty struct {
+		Key string `json:"key"`
+	} `json:"is_empty"`
+}
+
+// hasIDCondition matches points by point ID.
+type hasIDCondition struct {
+	HasID []string `json:"has_id"`
+}
+
+// scoreCondition is a score filter evaluated client-side after normalization.
+type scoreCondition struct {
+	op    vectorstore.FilterOperator
+	value float32
+}
+
+// matches reports whether a normalized score satisfies the condition.
+func (s scoreCondition) matches(score float32) bool {
+	switch s.op {
+	case vectorstore.OpGreaterThan:
+		return score > s.value
+	case vectorstore.OpGreaterThanOrEqual:
+		return score >= s.value
+	case vectorstore.OpLessThan:
+		return score < s.value
+	case vectorstore.OpLessThanOrEqual:
+		return score <= s.value
+	case vectorstore.OpEqual:
+		return score == s.value
+	case vectorstore.OpNotEqual:
+		return score != s.value
+	default:
+		return true
+	}
+}
+
+// translateFilter converts a vectorstore filter into a Qdrant filter.
+// Score filters are only supported at the top level (directly or inside a
+// top-level And) because Qdrant cannot evaluate them on normalized scores;
+// they are returned separately and applied to the search results.
+func translateFilter(f vectorstore.Filter) (*filter, []scoreCondition, error) {
+	if f == nil {
+		return nil, nil, nil
+	}
+
+	var scores []scoreCondition
+	topLevel := []vectorstore.Filter{f}
+	if vectorstore.IsAndFilter(f) {
+		topLevel = vectorstore.GetFilters(f)
+	}
+
+	qf := &filter{}
+	for _, child := range topLevel {
+		if op, value, ok := vectorstore.GetScoreFilter(child); ok {
+			scores = append(scores, scoreCondition{op: op, value: value})
+			continue
+		}
+		cond, err := toCondition(child)
+		if err != nil {
+			return nil, nil, err
+		}
+		qf.Must = append(qf.Must, cond)
+	}
+
+	if len(qf.Must) == 0 {
+		return nil, scores, nil
+	}
+	// Unwrap a single nested filter so Or/Not at the top level stay flat
+	if len(qf.Must) == 1 {
+		if inner, ok := qf.Must[0].(*filter); ok {
+			return inner, scores, nil
+		}
+	}
+	return qf, scores, nil
+}
+
+// toCondition converts a single vectorstore filter into a Qdrant condition.
+func toCondition(f vectorstore.Filter) (any, error) {
+	switch {
+	case vectorstore.IsAndFilter(f):
+		conds, err := toConditions(vectorstore.GetFilters(f))
+		if err != nil {
+			return nil, err
+		}
+		return &filter{Must: conds}, nil
+
+	case vectorstore.IsOrFilter(f):
+		conds, err := toConditions(vectorstore.GetFilters(f))
+		if err != nil {
+			return nil, err
+		}
+		return &filter{Should: conds}, nil
+
+	case vectorstore.IsNotFilter(f):
+		inner, _ := vectorstore.GetNotFilter(f)
+		cond, err := toCondition(inner)
+		if err != nil {
+			return nil, err
+		}
+		return &filter{MustNot: []any{cond}}, nil
+	}
+
+	if field, op, value, ok := vectorstore.GetFieldFilter(f); ok {
+		return fieldFilterCondition(payloadMetadata+"."+field, op, value)
+	}
+
+	if tag, ok := vectorstore.GetTagFilter(f); ok {
+		return &fieldCondition{Key: payloadTags, Match: &matchValue{Value: tag}}, nil
+	}
+
+	if scope, ok := vectorstore.GetScopeFilter(f); ok {
+		return scopeCondition(scope), nil
+	}
+
+	if field, op, value, ok := vectorstore.GetTimeFilter(f); ok {
+		return rangeCondition(string(field), op, float64(value.UnixMilli()))
+	}
+
+	if _, _, ok := vectorstore.GetScoreFilter(f); ok {
+		return nil, fmt.Errorf("score filters are only supported at the top level of a query")
+	}
+
+	return nil, fmt.Errorf("unsupported filter type %T", f)
+}
+
+func toConditions(filters []vectorstore.Filter) ([]any, error) {
+	conds := make([]any, 0, len(filters))
+	for _, f := range filters {
+		cond, err := toCondition(f)
+		if err != nil {
+			return nil, err
+		}
+		conds = append(conds, cond)
+	}
+	return conds, nil
+}
+
+// fieldFilterCondition converts a metadata field filter.
+func fieldFilterCondition(key string, op vectorstore.FilterOperator, value any) (any, error) {
+	switch op {
+	case vectorstore.OpEqual:
+		return equalCondition(key, value), nil
+	case vectorstore.OpNotEqual:
+		return &filter{MustNot: []any{equalCondition(key, value)}}, nil
+	case vectorstore.OpGreaterThan, vectorstore.OpGreaterThanOrEqual,
+		vectorstore.OpLessThan, vectorstore.OpLessThanOrEqual:
+		n, ok := toFloat(value)
+		if !ok {
+			return nil, fmt.Errorf("range filter on %s requires a numeric value, got %T", key, value)
+		}
+		return rangeCondition(key, op, n)
+	case vectorstore.OpIn:
+		return &fieldCondition{Key: key, Match: &matchValue{Any: toSlice(value)}}, nil
+	case vectorstore.OpNotIn:
+		return &fieldCondition{Key: key, Match: &matchValue{Except: toSlice(value)}}, nil
+	case vectorstore.OpContains:
+		s, ok := value.(string)
+		if !ok {
+			return nil, fmt.Errorf("contains filter on %s requires a string value, got %T", key, value)
+		}
+		return &fieldCondition{Key: key, Match: &matchValue{Text: s}}, nil
+	case vectorstore.OpExists:
+		return &filter{MustNot: []any{isEmpty(key)}}, nil
+	case vectorstore.OpNotExists:
+		return isEmpty(key), nil
+	default:
+		return nil, fmt.Errorf("filter operator %s is not supported by Qdrant", op)
+	}
+}
+
+// equalCondition matches key == value. Qdrant only supports exact match on
+// strings, integers, and booleans, so floats are expressed as a closed range.
+func equalCondition(key string, value any) any {
+	switch v := value.(type) {
+	case float32, float64:
+		n, _ := toFloat(v)
+		return &fieldCondition{Key: key, Range: &rangeValue{Gte: &n, Lte: &n}}
+	default:
+		return &fieldCondition{Key: key, Match: &matchValue{Value: value}}
+	}
+}
+
+// rangeCondition builds a one-sided range on key.
+func rangeCondition(key string, op vectorstore.FilterOperator, n float64) (any, error) {
+	r := &rangeValue{}
+	switch op {
+	case vectorstore.OpGreaterThan:
+		r.Gt = &n
+	case vectorstore.OpGreaterThanOrEqual:
+		r.Gte = &n
+	case vectorstore.OpLessThan:
+		r.Lt = &n
+	case vectorstore.OpLessThanOrEqual:
+		r.Lte = &n
+	case vectorstore.OpEqual:
+		r.Gte, r.Lte = &n, &n
+	default:
+		return nil, fmt.Errorf("filter operator %s is not supported on %s", op, key)
+	}
+	return &fieldCondition{Key: key, Range: r}, nil
+}
+
+// scopeCondition requires every non-empty scope field to match.
+func scopeCondition(scope *vectorstore.Scope) any {
+	f := &filter{}
+	add := func(key, value string) {
+		if value != "" {
+			f.Must = append(f.Must, &fieldCondition{Key: key, Match: &matchValue{Value: value}})
+		}
+	}
+	add(payloadScopeTenant, scope.Tenant)
+	add(payloadScopeUser, scope.User)
+	add(payloadScopeSession, scope.Session)
+	add(payloadScopeAgent, scope.Agent)
+	add(payloadScopeThread, scope.Thread)
+	for _, k := range slices.Sorted(maps.Keys(scope.Custom)) {
+		add(payloadScopeCustom+"."+k, scope.Custom[k])
+	}
+	return f
+}
+
+func isEmpty(key string) *isEmptyCondition {
+	c := &isEmptyCondition{}
+	c.IsEmpty.Key = key
+	return c
+}
+
+// unexpired narrows qf to points that have no expiry or expire after now, so
+// documents past their TTL are hidden from reads.
+func unexpired(qf *filter, now time.Time) *filter {
+	key := string(vectorstore.TimeFieldExpiresAt)
+	ms := float64(now.UnixMilli())
+	live := &filter{Should: []any{
+		isEmpty(key),
+		&fieldCondition{Key: key, Range: &rangeValue{Gt: &ms}},
+	}}
+	if qf == nil {
+		return &filter{Must: []any{live}}
+	}
+	return &filter{
+		Must:    append(slices.Clone(qf.Must), live),
+		Should:  qf.Should,
+		MustNot: qf.MustNot,
+	}
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
+
+func toSlice(value any) []any {
+	switch v := value.(type) {
+	case []any:
+		return v
+	case []string:
+		out := make([]any, len(v))
+		for i, s := range v {
+			out[i] = s
+		}
+		return out
+	default:
+		return []any{value}
+	}
+}
@@ -0,0 +1,1228 @@
+package qdrant
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultURL is the Qdrant REST endpoint used when WithURL is not set
+	DefaultURL = "http://localhost:6333"
+
+	defaultTimeout = 30 * time.Second
+)
+
+// Payload keys. Fields are flattened at the top level so they can be filtered
+// and indexed in Qdrant.
+const (
+	payloadDocID         = "doc_id"
+	payloadContentType   = "content_type"
+	payloadContentText   = "content_text"
+	payloadContentData   = "content_data"
+	payloadContentMime   = "content_mimetype"
+	payloadContentURL    = "content_url"
+	payloadContentChunks = "content_chunks"
+	payloadModel         = "embedding_model"
+	payloadNormalized    = "embedding_normalized"
+	payloadTags          = "tags"
+	payloadScopeTenant   = "scope_tenant"
+	payloadScopeUser     = "scope_user"
+	payloadScopeSession  = "scope_session"
+	payloadScopeAgent    = "scope_agent"
+	payloadScopeThread   = "scope_thread"
+	payloadScopeCustom   = "scope_custom"
+	payloadMetadata      = "metadata"
+)
+
+// pointNamespace derives deterministic Qdrant point IDs from document IDs.
+// Qdrant only accepts unsigned integers or UUIDs as point IDs.
+var pointNamespace = uuid.MustParse("6f1c2a8e-3b4d-5e6f-8a9b-0c1d2e3f4a5b")
+
+// QdrantVectorStore implements vectorstore.VectorStore on top of the Qdrant REST API.
+//
+// Features:
+//   - Collection-based isolation (one Qdrant collection per vectorstore collection)
+//   - Server-side similarity search with filter translation
+//   - Scope, tag, metadata, and temporal filters
+//   - Thread-safe operations
+//
+// Important Notes:
+//   - The distance metric is fixed per collection when it is created (see WithDistance)
+//   - Collections are created lazily on first Upsert, sized from the first embedding
+//   - Temporal fields are stored as Unix milliseconds
+//   - Expired documents are hidden from Query, Get, and Count but stay stored until deleted
+//   - Document IDs are mapped to UUIDv5 point IDs; the original ID is kept in the payload
+type QdrantVectorStore struct {
+	client      *client
+	config      *Config
+	collections map[string]*QdrantCollection
+	mu          sync.RWMutex
+}
+
+// Config holds configuration for QdrantVectorStore.
+type Config struct {
+	// URL is the Qdrant REST endpoint (default: DefaultURL)
+	URL string
+
+	// APIKey is sent in the api-key header when set (required by Qdrant Cloud)
+	APIKey string
+
+	// Distance is the metric new collections are created with (default: cosine)
+	Distance vectorstore.DistanceMetric
+
+	// CollectionDefaults are applied to every collection before its own options
+	CollectionDefaults []vectorstore.CollectionOption
+
+	// HTTPClient is used for all requests (default: 30s timeout client)
+	HTTPClient *http.Client
+}
+
+// Option is a functional option for configuring QdrantVectorStore.
+type Option func(*Config)
+
+// WithURL sets the Qdrant REST endpoint.
+func WithURL(url string) Option {
+	return func(c *Config) {
+		c.URL = url
+	}
+}
+
+// WithAPIKey sets the API key used to authenticate with Qdrant.
+func WithAPIKey(apiKey string) Option {
+	return func(c *Config) {
+		c.APIKey = apiKey
+	}
+}
+
+// WithDistance sets the distance metric used when creating collections.
+func WithDistance(metric vectorstore.DistanceMetric) Option {
+	return func(c *Config) {
+		c.Distance = metric
+	}
+}
+
+// WithCollectionDefaults sets options applied to every collection.
+// Options passed to Collection are applied after the defaults.
+func WithCollectionDefaults(opts ...vectorstore.CollectionOption) Option {
+	return func(c *Config) {
+		c.CollectionDefaults = append(c.CollectionDefaults, opts...)
+	}
+}
+
+// WithHTTPClient sets the HTTP client used for requests.
+func WithHTTPClient(httpClient *http.Client) Option {
+	return func(c *Config) {
+		c.HTTPClient = httpClient
+	}
+}
+
+// New creates a new QdrantVectorStore and verifies the server is reachable.
+//
+// Example:
+//
+//	store, err := qdrant.New(ctx,
+//	    qdrant.WithURL("https://xyz.cloud.qdrant.io:6333"),
+//	    qdrant.WithAPIKey(os.Getenv("QDRANT_API_KEY")),
+//	    qdrant.WithCollectionDefaults(vectorstore.WithDimensions(768)),
+//	)
+func New(ctx context.Context, opts ...Option) (vectorstore.VectorStore, error) {
+	config := &Config{
+		URL:      DefaultURL,
+		Distance: vectorstore.DistanceMetricCosine,
+	}
+	for _, opt := range opts {
+		opt(config)
+	}
+
+	if config.URL == "" {
+		return nil, fmt.Errorf("URL is required")
+	}
+	if _, err := url.Parse(config.URL); err != nil {
+		return nil, fmt.Errorf("invalid URL: %w", err)
+	}
+	if _, ok := qdrantDistance(config.Distance); !ok {
+		return nil, fmt.Errorf("unsupported distance metric: %s", config.Distance)
+	}
+	if config.HTTPClient == nil {
+		config.HTTPClient = &http.Client{Timeout: defaultTimeout}
+	}
+
+	c := &client{
+		baseURL: strings.TrimRight(config.URL, "/"),
+		apiKey:  config.APIKey,
+		http:    config.HTTPClient,
+	}
+
+	// Verify connectivity
+	if _, err := c.listCollections(ctx); err != nil {
+		return nil, fmt.Errorf("failed to connect to Qdrant: %w", err)
+	}
+
+	return &QdrantVectorStore{
+		client:      c,
+		config:      config,
+		collections: make(map[string]*QdrantCollection),
+	}, nil
+}
+
+// Collection returns a collection with the specified name and options.
+func (q *QdrantVectorStore) Collection(name string, opts ...vectorstore.CollectionOption) vectorstore.Collection {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	if coll, exists := q.collections[name]; exists {
+		return coll
+	}
+
+	all := append(append([]vectorstore.CollectionOption{}, q.config.CollectionDefaults...), opts...)
+	coll := &QdrantCollection{
+		name:      name,
+		config:    vectorstore.ApplyOptions(all),
+		client:    q.client,
+		distance:  q.config.Distance,
+		createdAt: time.Now(),
+		updatedAt: time.Now(),
+	}
+
+	q.collections[name] = coll
+	return coll
+}
+
+// ListCollections returns the names of all collections.
+func (q *QdrantVectorStore) ListCollections(ctx context.Context) ([]string, error) {
+	names, err := q.client.listCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// DeleteCollection permanently deletes a collection and all its documents.
+func (q *QdrantVectorStore) DeleteCollection(ctx context.Context, name string) error {
+	var deleted bool
+	if err := q.client.do(ctx, http.MethodDelete, collectionPath(name), nil, &deleted); err != nil {
+		if isNotFound(err) {
+			return fmt.Errorf("collection %q does not exist", name)
+		}
+		return fmt.Errorf("failed to delete collection: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("collection %q does not exist", name)
+	}
+
+	q.mu.Lock()
+	if coll, ok := q.collections[name]; ok {
+		coll.mu.Lock()
+		coll.ensured = false
+		coll.mu.Unlock()
+		delete(q.collections, name)
+	}
+	q.mu.Unlock()
+
+	return nil
+}
+
+// Stats returns statistics about the vector store.
+func (q *QdrantVectorStore) Stats(ctx context.Context) (*vectorstore.StoreStats, error) {
+	names, err := q.client.listCollections(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collections: %w", err)
+	}
+
+	var totalDocs int64
+	for _, name := range names {
+		info, err := q.client.collectionInfo(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get collection %s: %w", name, err)
+		}
+		totalDocs += info.PointsCount
+	}
+
+	return &vectorstore.StoreStats{
+		Collections: int64(len(names)),
+		Documents:   totalDocs,
+		Provider:    "qdrant",
+		Version:     "1.0.0",
+	}, nil
+}
+
+// Close releases idle HTTP connections.
+func (q *QdrantVectorStore) Close() error {
+	q.client.http.CloseIdleConnections()
+	return nil
+}
+
+// QdrantCollection implements vectorstore.Collection for a Qdrant collection.
+type QdrantCollection struct {
+	name      string
+	config    *vectorstore.CollectionConfig
+	client    *client
+	distance  vectorstore.DistanceMetric
+	ensured   bool
+	createdAt time.Time
+	updatedAt time.Time
+	metrics   vectorstore.MetricsRecorder
+	mu        sync.RWMutex
+}
+
+// Name returns the collection name.
+func (c *QdrantCollection) Name() string {
+	return c.name
+}
+
+// Upsert inserts or updates documents in the collection.
+func (c *QdrantCollection) Upsert(ctx context.Context, documents ...*vectorstore.Document) (*vectorstore.UpsertResult, error) {
+	if len(documents) == 0 {
+		return &vectorstore.UpsertResult{}, nil
+	}
+
+	startTime := time.Now()
+
+	validationStart := time.Now()
+	for i, doc := range documents {
+		if err := vectorstore.Validate(doc); err != nil {
+			return nil, fmt.Errorf("invalid document at index %d: %w", i, err)
+		}
+
+		if doc.Embedding == nil && c.config.AutoGenerateEmbeddings && c.config.EmbeddingFunction != nil {
+			embedding, err := c.config.EmbeddingFunction(doc.Content)
+			if err != nil {
+				return nil, fmt.Errorf("failed to generate embedding for document %s: %w", doc.ID, err)
+			}
+			doc.Embedding = embedding
+		}
+		if doc.Embedding == nil {
+			return nil, fmt.Errorf("document %s has no embedding; Qdrant requires a vector for every point", doc.ID)
+		}
+
+		if c.config.EmbeddingDimensions > 0 && len(doc.Embedding.Vector) != c.config.EmbeddingDimensions {
+			return nil, fmt.Errorf("document %s embedding dimension mismatch: expected %d, got %d",
+				doc.ID, c.config.EmbeddingDimensions, len(doc.Embedding.Vector))
+		}
+
+		if err := c.validateRequiredScope(doc); err != nil {
+			return nil, fmt.Errorf("document %s: %w", doc.ID, err)
+		}
+	}
+	validationTime := time.Since(validationStart)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if err := c.ensureCollection(ctx, len(documents[0].Embedding.Vector)); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, len(documents))
+	for i, doc := range documents {
+		ids[i] = doc.ID
+	}
+	existing, err := c.retrieve(ctx, ids, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check existing documents: %w", err)
+	}
+
+	now := time.Now()
+	points := make([]point, len(documents))
+	for i, doc := range documents {
+		// Stamp timestamps on a copy so callers' documents are not modified
+		stored := *doc
+		temporal := vectorstore.Temporal{CreatedAt: now}
+		if doc.Temporal != nil {
+			temporal = *doc.Temporal
+			if temporal.CreatedAt.IsZero() {
+				temporal.CreatedAt = now
+			}
+		}
+		temporal.UpdatedAt = now
+		if c.config.TTL > 0 && temporal.ExpiresAt == nil {
+			temporal.SetExpiry(c.config.TTL)
+		}
+		stored.Temporal = &temporal
+		points[i] = documentToPoint(&stored)
+	}
+
+	storageStart := time.Now()
+	body := map[string]any{"points": points}
+	if err := c.client.do(ctx, http.MethodPut, collectionPath(c.name)+"/points?wait=true", body, nil); err != nil {
+		return nil, fmt.Errorf("failed to upsert points: %w", err)
+	}
+
+	c.updatedAt = now
+
+	result := &vectorstore.UpsertResult{
+		Updated:  int64(len(existing)),
+		Inserted: int64(len(documents) - len(existing)),
+		Timing: &vectorstore.OperationTiming{
+			Total:      time.Since(startTime),
+			Validation: validationTime,
+			Storage:    time.Since(storageStart),
+		},
+	}
+	c.metrics.RecordUpsert("qdrant", c.name, len(documents), result.Timing.Total)
+	return result, nil
+}
+
+// UpsertIfVersion is not supported: Qdrant has no conditional point
+// write to compare and set a version atomically.
+func (c *QdrantCollection) UpsertIfVersion(ctx context.Context, doc *vectorstore.Document, expectedVersion int64) (*vectorstore.UpsertResult, error) {
+	return nil, fmt.Errorf("qdrant: %w", vectorstore.ErrVersioningNotSupported)
+}
+
+// UpsertBatch performs batch upsert with progress tracking.
+func (c *QdrantCollection) UpsertBatch(ctx context.Context, documents []*vectorstore.Document, opts ...vectorstore.BatchOption) (*vectorstore.UpsertResult, error) {
+	if len(documents) == 0 {
+		return &vectorstore.UpsertResult{}, nil
+	}
+
+	config := vectorstore.ApplyBatchOptions(opts)
+	if config.BatchSize <= 0 {
+		config.BatchSize = 100
+	}
+
+	totalResult := &vectorstore.UpsertResult{}
+	processed := 0
+
+	for i := 0; i < len(documents); i += config.BatchSize {
+		end := min(i+config.BatchSize, len(documents))
+		batch := documents[i:end]
+
+		batchResult, err := c.Upsert(ctx, batch...)
+		if err != nil {
+			if !config.ContinueOnError {
+				return totalResult, err
+			}
+			totalResult.Failed += int64(len(batch))
+			for _, doc := range batch {
+				totalResult.FailedIDs = append(totalResult.FailedIDs, doc.ID)
+				totalResult.Errors = append(totalResult.Errors, err)
+			}
+		} else {
+			totalResult.Inserted += batchResult.Inserted
+			totalResult.Updated += batchResult.Updated
+		}
+
+		processed += len(batch)
+		if config.ProgressCallback != nil {
+			config.ProgressCallback(processed, len(documents))
+		}
+
+		if err := ctx.Err(); err != nil {
+			return totalResult, err
+		}
+	}
+
+	return totalResult, nil
+}
+
+// Query performs similarity search and returns matching documents.
+// Vector queries use Qdrant search; filter-only queries use scroll.
+func (c *QdrantCollection) Query(ctx context.Context, query *vectorstore.Query) (*vectorstore.QueryResult, error) {
+	if err := query.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid query: %w", err)
+	}
+	if query.Metric != "" && query.Metric != c.distance {
+		return nil, fmt.Errorf("collection %s uses %s distance, query requested %s", c.name, c.distance, query.Metric)
+	}
+	if len(query.Keywords) > 0 {
+		return nil, fmt.Errorf("qdrant: %w", vectorstore.ErrHybridSearchNotSupported)
+	}
+
+	startTime := time.Now()
+	timing := &vectorstore.QueryTiming{}
+
+	filterStart := time.Now()
+	qf, scoreConds, err := translateFilter(query.Filters)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	timing.FilterApplication = time.Since(filterStart)
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var matches []*vectorstore.Match
+	searchStart := time.Now()
+
+	if query.Embedding != nil {
+		body := map[string]any{
+			"vector":       query.Embedding.Vector,
+			"limit":        query.Limit,
+			"offset":       query.Offset,
+			"with_payload": true,
+			"with_vector":  query.IncludeEmbeddings,
+		}
+		body["filter"] = unexpired(qf, time.Now())
+
+		var points []scoredPoint
+		if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/search", body, &points); err != nil {
+			if isNotFound(err) {
+				return c.emptyResult(query, timing, startTime), nil
+			}
+			return nil, fmt.Errorf("failed to search: %w", err)
+		}
+		timing.VectorSearch = time.Since(searchStart)
+
+		scoringStart := time.Now()
+		for _, p := range points {
+			score, distance := normalizeScore(p.Score, c.distance)
+			matches = append(matches, &vectorstore.Match{
+				Document: pointToDocument(p.Payload, p.Vector),
+				Score:    score,
+				Distance: distance,
+			})
+		}
+		timing.Scoring = time.Since(scoringStart)
+	} else {
+		body := map[string]any{
+			"limit":        query.Limit + query.Offset,
+			"with_payload": true,
+			"with_vector":  query.IncludeEmbeddings,
+		}
+		body["filter"] = unexpired(qf, time.Now())
+
+		var page struct {
+			Points []scoredPoint `json:"points"`
+		}
+		if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/scroll", body, &page); err != nil {
+			if isNotFound(err) {
+				return c.emptyResult(query, timing, startTime), nil
+			}
+			return nil, fmt.Errorf("failed to scroll: %w", err)
+		}
+		timing.Retrieval = time.Since(searchStart)
+
+		points := page.Points
+		if query.Offset >= len(points) {
+			points = nil
+		} else {
+			points = points[query.Offset:]
+		}
+		for _, p := range points {
+			matches = append(matches, &vectorstore.Match{
+				Document: pointToDocument(p.Payload, p.Vector),
+				Score:    1.0,
+			})
+		}
+	}
+
+	// Apply MinScore and top-level score filters on normalized scores
+	filtered := matches[:0]
+	for _, m := range matches {
+		if query.MinScore > 0 && m.Score < query.MinScore {
+			continue
+		}
+		keep := true
+		for _, sc := range scoreConds {
+			if !sc.matches(m.Score) {
+				keep = false
+				break
+			}
+		}
+		if keep {
+			filtered = append(filtered, m)
+		}
+	}
+	matches = filtered
+
+	for i, m := range matches {
+		m.Rank = i + 1 + query.Offset
+		m.Document.Score = m.Score
+		m.Document.Distance = m.Distance
+	}
+
+	timing.Total = time.Since(startTime)
+	c.metrics.RecordQuery("qdrant", c.name, timing.Total, len(matches))
+
+	return &vectorstore.QueryResult{
+		Matches: matches,
+		Total:   int64(len(matches)),
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Timing:  timing,
+	}, nil
+}
+
+// QueryStream performs similarity search and streams results via an iterator.
+func (c *QdrantCollection) QueryStream(ctx context.Context, query *vectorstore.Query) (vectorstore.ResultIterator, error) {
+	result, err := c.Query(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return vectorstore.NewSliceIterator(result.Matches), nil
+}
+
+// Get retrieves documents by their IDs. Expired documents are omitted.
+func (c *QdrantCollection) Get(ctx context.Context, ids ...string) ([]*vectorstore.Document, error) {
+	if len(ids) == 0 {
+		return []*vectorstore.Document{}, nil
+	}
+
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = pointID(id)
+	}
+
+	// Point retrieval cannot filter, so look the IDs up with a scroll
+	body := map[string]any{
+		"filter":       unexpired(&filter{Must: []any{hasIDCondition{HasID: pointIDs}}}, time.Now()),
+		"limit":        len(pointIDs),
+		"with_payload": true,
+		"with_vector":  true,
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var page struct {
+		Points []scoredPoint `json:"points"`
+	}
+	if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/scroll", body, &page); err != nil {
+		if isNotFound(err) {
+			return []*vectorstore.Document{}, nil
+		}
+		return nil, fmt.Errorf("failed to get documents: %w", err)
+	}
+
+	docs := make([]*vectorstore.Document, 0, len(page.Points))
+	for _, p := range page.Points {
+		docs = append(docs, pointToDocument(p.Payload, p.Vector))
+	}
+	return docs, nil
+}
+
+// Delete removes documents by their IDs.
+func (c *QdrantCollection) Delete(ctx context.Context, ids ...string) (*vectorstore.DeleteResult, error) {
+	if len(ids) == 0 {
+		return &vectorstore.DeleteResult{}, nil
+	}
+
+	startTime := time.Now()
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	points, err := c.retrieve(ctx, ids, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check documents: %w", err)
+	}
+
+	found := make(map[string]bool, len(points))
+	pointIDs := make([]string, 0, len(points))
+	for _, p := range points {
+		id, _ := p.Payload[payloadDocID].(string)
+		found[id] = true
+		pointIDs = append(pointIDs, pointID(id))
+	}
+
+	result := &vectorstore.DeleteResult{}
+	for _, id := range ids {
+		if !found[id] {
+			result.NotFound++
+			result.NotFoundIDs = append(result.NotFoundIDs, id)
+		}
+	}
+
+	if len(pointIDs) > 0 {
+		body := map[string]any{"points": pointIDs}
+		if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/delete?wait=true", body, nil); err != nil {
+			return nil, fmt.Errorf("failed to delete points: %w", err)
+		}
+		result.Deleted = int64(len(pointIDs))
+		c.updatedAt = time.Now()
+	}
+
+	result.Timing = &vectorstore.OperationTiming{Total: time.Since(startTime)}
+	return result, nil
+}
+
+// DeleteByFilter removes all documents matching the filter.
+func (c *QdrantCollection) DeleteByFilter(ctx context.Context, f vectorstore.Filter) (*vectorstore.DeleteResult, error) {
+	startTime := time.Now()
+
+	qf, scoreConds, err := translateFilter(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid filter: %w", err)
+	}
+	if len(scoreConds) > 0 {
+		return nil, fmt.Errorf("score filters cannot be used to delete documents")
+	}
+	if qf == nil {
+		qf = &filter{}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	count, err := c.count(ctx, qf)
+	if err != nil {
+		if isNotFound(err) {
+			return &vectorstore.DeleteResult{Timing: &vectorstore.OperationTiming{Total: time.Since(startTime)}}, nil
+		}
+		return nil, fmt.Errorf("failed to count documents: %w", err)
+	}
+
+	body := map[string]any{"filter": qf}
+	if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/delete?wait=true", body, nil); err != nil {
+		return nil, fmt.Errorf("failed to delete points: %w", err)
+	}
+	c.updatedAt = time.Now()
+
+	return &vectorstore.DeleteResult{
+		Deleted: count,
+		Timing:  &vectorstore.OperationTiming{Total: time.Since(startTime)},
+	}, nil
+}
+
+// Count returns the number of documents in the collection.
+func (c *QdrantCollection) Count(ctx context.Context, f vectorstore.Filter) (int64, error) {
+	qf, scoreConds, err := translateFilter(f)
+	if err != nil {
+		return 0, fmt.Errorf("invalid filter: %w", err)
+	}
+	if len(scoreConds) > 0 {
+		return 0, fmt.Errorf("score filters cannot be used to count documents")
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	count, err := c.count(ctx, unexpired(qf, time.Now()))
+	if err != nil {
+		if isNotFound(err) {
+			return 0, nil
+		}
+		return 0, fmt.Errorf("failed to count documents: %w", err)
+	}
+	return count, nil
+}
+
+// Metrics returns the query and upsert metrics recorded by the collection.
+func (c *QdrantCollection) Metrics() vectorstore.CollectionMetrics {
+	return c.metrics.Snapshot()
+}
+
+// Stats returns statistics about the collection.
+func (c *QdrantCollection) Stats(ctx context.Context) (*vectorstore.CollectionStats, error) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	stats := &vectorstore.CollectionStats{
+		Name:                c.name,
+		EmbeddingDimensions: c.config.EmbeddingDimensions,
+		IndexType:           string(vectorstore.IndexTypeHNSW),
+		CreatedAt:           vectorstore.NewTimestamp(c.createdAt),
+		UpdatedAt:           vectorstore.NewTimestamp(c.updatedAt),
+	}
+
+	info, err := c.client.collectionInfo(ctx, c.name)
+	if err != nil {
+		if isNotFound(err) {
+			return stats, nil
+		}
+		return nil, fmt.Errorf("failed to get collection info: %w", err)
+	}
+
+	stats.Documents = info.PointsCount
+	stats.EmbeddingDimensions = info.Config.Params.Vectors.Size
+	stats.Extra = map[string]any{
+		"status":   info.Status,
+		"distance": info.Config.Params.Vectors.Distance,
+	}
+	return stats, nil
+}
+
+// Clear removes all documents from the collection.
+func (c *QdrantCollection) Clear(ctx context.Context) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	body := map[string]any{"filter": &filter{}}
+	if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/delete?wait=true", body, nil); err != nil {
+		if isNotFound(err) {
+			return nil
+		}
+		return fmt.Errorf("failed to clear collection: %w", err)
+	}
+	c.updatedAt = time.Now()
+	return nil
+}
+
+// Helper methods
+
+// ensureCollection creates the Qdrant collection if it does not exist yet.
+// Must be called with c.mu held.
+func (c *QdrantCollection) ensureCollection(ctx context.Context, dimensions int) error {
+	if c.ensured {
+		return nil
+	}
+
+	if _, err := c.client.collectionInfo(ctx, c.name); err == nil {
+		c.ensured = true
+		return nil
+	} else if !isNotFound(err) {
+		return fmt.Errorf("failed to get collection info: %w", err)
+	}
+
+	if c.config.EmbeddingDimensions > 0 {
+		dimensions = c.config.EmbeddingDimensions
+	}
+	distance, _ := qdrantDistance(c.distance)
+
+	body := map[string]any{
+		"vectors": map[string]any{
+			"size":     dimensions,
+			"distance": distance,
+		},
+	}
+	if err := c.client.do(ctx, http.MethodPut, collectionPath(c.name), body, nil); err != nil {
+		return fmt.Errorf("failed to create collection: %w", err)
+	}
+
+	c.ensured = true
+	return nil
+}
+
+// retrieve fetches points by document ID. Missing IDs are omitted.
+func (c *QdrantCollection) retrieve(ctx context.Context, ids []string, withVector bool) ([]scoredPoint, error) {
+	pointIDs := make([]string, len(ids))
+	for i, id := range ids {
+		pointIDs[i] = pointID(id)
+	}
+
+	body := map[string]any{
+		"ids":          pointIDs,
+		"with_payload": true,
+		"with_vector":  withVector,
+	}
+
+	var points []scoredPoint
+	if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points", body, &points); err != nil {
+		if isNotFound(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return points, nil
+}
+
+func (c *QdrantCollection) count(ctx context.Context, qf *filter) (int64, error) {
+	body := map[string]any{"exact": true}
+	if qf != nil {
+		body["filter"] = qf
+	}
+
+	var result struct {
+		Count int64 `json:"count"`
+	}
+	if err := c.client.do(ctx, http.MethodPost, collectionPath(c.name)+"/points/count", body, &result); err != nil {
+		return 0, err
+	}
+	return result.Count, nil
+}
+
+// validateRequiredScope validates that document has required scope fields.
+func (c *QdrantCollection) validateRequiredScope(doc *vectorstore.Document) error {
+	if len(c.config.ScopeRequired) == 0 {
+		return nil
+	}
+	if doc.Scope == nil {
+		return fmt.Errorf("scope is required but not provided")
+	}
+
+	for _, field := range c.config.ScopeRequired {
+		var value string
+		switch field {
+		case "tenant":
+			value = doc.Scope.Tenant
+		case "user":
+			value = doc.Scope.User
+		case "session":
+			value = doc.Scope.Session
+		case "agent":
+			value = doc.Scope.Agent
+		case "thread":
+			value = doc.Scope.Thread
+		default:
+			value = doc.Scope.Custom[field]
+		}
+		if value == "" {
+			return fmt.Errorf("required scope field '%s' is empty", field)
+		}
+	}
+	return nil
+}
+
+func (c *QdrantCollection) emptyResult(query *vectorstore.Query, timing *vectorstore.QueryTiming, startTime time.Time) *vectorstore.QueryResult {
+	timing.Total = time.Since(startTime)
+	c.metrics.RecordQuery("qdrant", c.name, timing.Total, 0)
+	return &vectorstore.QueryResult{
+		Matches: []*vectorstore.Match{},
+		Offset:  query.Offset,
+		Limit:   query.Limit,
+		Timing:  timing,
+	}
+}
+
+// Conversion
+
+// point is a Qdrant point as sent on upsert.
+type point struct {
+	ID      string         `json:"id"`
+	Vector  []float32      `json:"vector"`
+	Payload map[string]any `json:"payload"`
+}
+
+// scoredPoint is a Qdrant point as returned by search, scroll, and retrieve.
+type scoredPoint struct {
+	ID      any            `json:"id"`
+	Score   float32        `json:"score"`
+	Payload map[string]any `json:"payload"`
+	Vector  []float32      `json:"vector,omitempty"`
+}
+
+// pointID maps a document ID to a deterministic Qdrant point ID.
+func pointID(docID string) string {
+	return uuid.NewSHA1(pointNamespace, []byte(docID)).String()
+}
+
+// documentToPoint converts a vectorstore.Document to a Qdrant point.
+func documentToPoint(doc *vectorstore.Document) point {
+	payload := map[string]any{payloadDocID: doc.ID}
+
+	if doc.Content != nil {
+		payload[payloadContentType] = string(doc.Content.Type)
+		setString(payload, payloadContentText, doc.Content.Text)
+		setString(payload, payloadContentMime, doc.Content.MimeType)
+		setString(payload, payloadContentURL, doc.Content.URL)
+		if len(doc.Content.Data) > 0 {
+			payload[payloadContentData] = base64.StdEncoding.EncodeToString(doc.Content.Data)
+		}
+		if len(doc.Content.Chunks) > 0 {
+			payload[payloadContentChunks] = doc.Content.Chunks
+		}
+	}
+
+	setString(payload, payloadModel, doc.Embedding.Model)
+	if doc.Embedding.Normalized {
+		payload[payloadNormalized] = true
+	}
+
+	if len(doc.Tags) > 0 {
+		payload[payloadTags] = doc.Tags
+	}
+
+	if doc.Scope != nil {
+		setString(payload, payloadScopeTenant, doc.Scope.Tenant)
+		setString(payload, payloadScopeUser, doc.Scope.User)
+		setString(payload, payloadScopeSession, doc.Scope.Session)
+		setString(payload, payloadScopeAgent, doc.Scope.Agent)
+		setString(payload, payloadScopeThread, doc.Scope.Thread)
+		if len(doc.Scope.Custom) > 0 {
+			payload[payloadScopeCustom] = doc.Scope.Custom
+		}
+	}
+
+	if t := doc.Temporal; t != nil {
+		setTime(payload, string(vectorstore.TimeFieldCreatedAt), &t.CreatedAt)
+		setTime(payload, string(vectorstore.TimeFieldUpdatedAt), &t.UpdatedAt)
+		setTime(payload, string(vectorstore.TimeFieldExpiresAt), t.ExpiresAt)
+		setTime(payload, string(vectorstore.TimeFieldEventTime), t.EventTime)
+		setTime(payload, string(vectorstore.TimeFieldValidFrom), t.ValidFrom)
+		setTime(payload, string(vectorstore.TimeFieldValidUntil), t.ValidUntil)
+	}
+
+	if len(doc.Metadata) > 0 {
+		payload[payloadMetadata] = doc.Metadata
+	}
+
+	return point{
+		ID:      pointID(doc.ID),
+		Vector:  doc.Embedding.Vector,
+		Payload: payload,
+	}
+}
+
+// pointToDocument converts a Qdrant payload (and optional vector) to a vectorstore.Document.
+func pointToDocument(payload map[string]any, vector []float32) *vectorstore.Document {
+	doc := &vectorstore.Document{
+		ID:   getString(payload, payloadDocID),
+		Tags: getStrings(payload, payloadTags),
+	}
+
+	if contentType := getString(payload, payloadContentType); contentType != "" {
+		doc.Content = &vectorstore.Content{
+			Type:     vectorstore.ContentType(contentType),
+			Text:     getString(payload, payloadContentText),
+			MimeType: getString(payload, payloadContentMime),
+			URL:      getString(payload, payloadContentURL),
+			Chunks:   getStrings(payload, payloadContentChunks),
+		}
+		if data := getString(payload, payloadContentData); data != "" {
+			if decoded, err := base64.StdEncoding.DecodeString(data); err == nil {
+				doc.Content.Data = decoded
+			}
+		}
+	}
+
+	if len(vector) > 0 {
+		normalized, _ := payload[payloadNormalized].(bool)
+		doc.Embedding = &vectorstore.Embedding{
+			Vector:     vector,
+			Model:      getString(payload, payloadModel),
+			Dimensions: len(vector),
+			Normalized: normalized,
+		}
+	}
+
+	scope := &vectorstore.Scope{
+		Tenant:  getString(payload, payloadScopeTenant),
+		User:    getString(payload, payloadScopeUser),
+		Session: getString(payload, payloadScopeSession),
+		Agent:   getString(payload, payloadScopeAgent),
+		Thread:  getString(payload, payloadScopeThread),
+	}
+	if custom, ok := payload[payloadScopeCustom].(map[string]any); ok {
+		scope.Custom = make(map[string]string, len(custom))
+		for k, v := range custom {
+			if s, ok := v.(string); ok {
+				scope.Custom[k] = s
+			}
+		}
+	}
+	if scope.Tenant != "" || scope.User != "" || scope.Session != "" ||
+		scope.Agent != "" || scope.Thread != "" || len(scope.Custom) > 0 {
+		doc.Scope = scope
+	}
+
+	if created := getTime(payload, string(vectorstore.TimeFieldCreatedAt)); created != nil {
+		doc.Temporal = &vectorstore.Temporal{
+			CreatedAt:  *created,
+			ExpiresAt:  getTime(payload, string(vectorstore.TimeFieldExpiresAt)),
+			EventTime:  getTime(payload, string(vectorstore.TimeFieldEventTime)),
+			ValidFrom:  getTime(payload, string(vectorstore.TimeFieldValidFrom)),
+			ValidUntil: getTime(payload, string(vectorstore.TimeFieldValidUntil)),
+		}
+		if updated := getTime(payload, string(vectorstore.TimeFieldUpdatedAt)); updated != nil {
+			doc.Temporal.UpdatedAt = *updated
+		}
+	}
+
+	if metadata, ok := payload[payloadMetadata].(map[string]any); ok {
+		doc.Metadata = metadata
+	}
+
+	return doc
+}
+
+func setString(payload map[string]any, key, value string) {
+	if value != "" {
+		payload[key] = value
+	}
+}
+
+func setTime(payload map[string]any, key string, t *time.Time) {
+	if t != nil && !t.IsZero() {
+		payload[key] = t.UnixMilli()
+	}
+}
+
+func getString(payload map[string]any, key string) string {
+	s, _ := payload[key].(string)
+	return s
+}
+
+func getStrings(payload map[string]any, key string) []string {
+	values, ok := payload[key].([]any)
+	if !ok {
+		return nil
+	}
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if s, ok := v.(string); ok {
+			out = append(out, s)
+		}
+	}
+	return out
+}
+
+func getTime(payload map[string]any, key string) *time.Time {
+	ms, ok := toFloat(payload[key])
+	if !ok {
+		return nil
+	}
+	t := time.UnixMilli(int64(ms))
+	return &t
+}
+
+// Scoring
+
+// qdrantDistance maps a vectorstore metric to the Qdrant distance name.
+func qdrantDistance(metric vectorstore.DistanceMetric) (string, bool) {
+	switch metric {
+	case "", vectorstore.DistanceMetricCosine:
+		return "Cosine", true
+	case vectorstore.DistanceMetricEuclidean:
+		return "Euclid", true
+	case vectorstore.DistanceMetricDotProduct:
+		return "Dot", true
+	default:
+		return "", false
+	}
+}
+
+// normalizeScore converts a raw Qdrant score into the score and distance
+// conventions used by the other vector stores: higher scores are more similar,
+// and Euclidean distance is mapped to 1 / (1 + distance).
+func normalizeScore(raw float32, metric vectorstore.DistanceMetric) (score float32, distance float32) {
+	switch metric {
+	case vectorstore.DistanceMetricEuclidean:
+		// Qdrant reports the Euclidean distance itself for Euclid collections
+		return 1.0 / (1.0 + raw), raw
+	case vectorstore.DistanceMetricDotProduct:
+		return raw, -raw
+	default:
+		return raw, 1.0 - raw
+	}
+}
+
+// HTTP client
+
+// client is a minimal Qdrant REST client.
+type client struct {
+	baseURL string
+	apiKey  string
+	http    *http.Client
+}
+
+// apiError is returned for non-2xx Qdrant responses.
+type apiError struct {
+	StatusCode int
+	Message    string
+}
+
+func (e *apiError) Error() string {
+	return fmt.Sprintf("qdrant: HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+func isNotFound(err error) bool {
+	var apiErr *apiError
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// do sends a request and decodes the "result" field of the response into out.
+func (c *client) do(ctx context.Context, method, path string, body any, out any) error {
+	var reader io.Reader
+	if body != nil {
+		data, err := json.Marshal(body)
+		if err != nil {
+			return fmt.Errorf("failed to marshal request: %w", err)
+		}
+		reader = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, reader)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	if c.apiKey != "" {
+		req.Header.Set("api-key", c.apiKey)
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response: %w", err)
+	}
+
+	var envelope struct {
+		Result json.RawMessage `json:"result"`
+		Status json.RawMessage `json:"status"`
+	}
+	_ = json.Unmarshal(data, &envelope)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		msg := strings.TrimSpace(string(data))
+		var status struct {
+			Error string `json:"error"`
+		}
+		if json.Unmarshal(envelope.Status, &status) == nil && status.Error != "" {
+			msg = status.Error
+		}
+		return &apiError{StatusCode: resp.StatusCode, Message: msg}
+	}
+
+	if out == nil || len(envelope.Result) == 0 {
+		return nil
+	}
+	if err := json.Unmarshal(envelope.Result, out); err != nil {
+		return fmt.Errorf("failed to decode response: %w", err)
+	}
+	return nil
+}
+
+func (c *client) listCollections(ctx context.Context) ([]string, error) {
+	var result struct {
+		Collections []struct {
+			Name string `json:"name"`
+		} `json:"collections"`
+	}
+	if err := c.do(ctx, http.MethodGet, "/collections", nil, &result); err != nil {
+		return nil, err
+	}
+
+	names := make([]string, len(result.Collections))
+	for i, coll := range result.Collections {
+		names[i] = coll.Name
+	}
+	return names, nil
+}
+
+// collectionInfo is the subset of Qdrant's collection info we use.
+type collectionInfo struct {
+	Status      string `json:"status"`
+	PointsCount int64  `json:"points_count"`
+	Config      struct {
+		Params struct {
+			Vectors struct {
+				Size     int    `json:"size"`
+				Distance string `json:"distance"`
+			} `json:"vectors"`
+		} `json:"params"`
+	} `json:"config"`
+}
+
+func (c *client) collectionInfo(ctx context.Context, name string) (*collectionInfo, error) {
+	var info collectionInfo
+	if err := c.do(ctx, http.MethodGet, collectionPath(name), nil, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
+
+func collectionPath(name string) string {
+	return "/collections/" + url.PathEscape(name)
+}
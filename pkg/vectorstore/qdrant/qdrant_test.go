@@ -0,0 +1,493 @@
+package qdrant
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aixgo-dev/aixgo/pkg/vectorstore"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helper functions
+
+// recordedRequest is a request captured by mockTransport.
+type recordedRequest struct {
+	Method string
+	Path   string
+	Header http.Header
+	Body   map[string]any
+}
+
+// mockTransport serves canned Qdrant responses keyed by "METHOD /path".
+type mockTransport struct {
+	mu        sync.Mutex
+	responses map[string]mockResponse
+	requests  []recordedRequest
+}
+
+type mockResponse struct {
+	status int
+	body   string
+}
+
+func newMockTransport() *mockTransport {
+	return &mockTransport{
+		responses: map[string]mockResponse{
+			"GET /collections": {200, `{"result":{"collections":[]},"status":"ok"}`},
+		},
+	}
+}
+
+func (m *mockTransport) on(method, path string, status int, body string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.responses[method+" "+path] = mockResponse{status, body}
+}
+
+func (m *mockTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	rec := recordedRequest{Method: req.Method, Path: req.URL.Path, Header: req.Header}
+	if req.Body != nil {
+		data, _ := io.ReadAll(req.Body)
+		_ = json.Unmarshal(data, &rec.Body)
+	}
+
+	m.mu.Lock()
+	m.requests = append(m.requests, rec)
+	resp, ok := m.responses[req.Method+" "+req.URL.Path]
+	m.mu.Unlock()
+
+	if !ok {
+		resp = mockResponse{404, `{"status":{"error":"Not found"}}`}
+	}
+	return &http.Response{
+		StatusCode: resp.status,
+		Body:       io.NopCloser(strings.NewReader(resp.body)),
+		Header:     make(http.Header),
+		Request:    req,
+	}, nil
+}
+
+func (m *mockTransport) last(method, path string) *recordedRequest {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for i := len(m.requests) - 1; i >= 0; i-- {
+		if m.requests[i].Method == method && m.requests[i].Path == path {
+			return &m.requests[i]
+		}
+	}
+	return nil
+}
+
+func newTestStore(t *testing.T, transport *mockTransport, opts ...Option) vectorstore.VectorStore {
+	t.Helper()
+	opts = append([]Option{
+		WithURL("http://qdrant.test:6333"),
+		WithHTTPClient(&http.Client{Transport: transport}),
+	}, opts...)
+	store, err := New(context.Background(), opts...)
+	require.NoError(t, err)
+	return store
+}
+
+func toJSON(t *testing.T, v any) string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	require.NoError(t, err)
+	return string(data)
+}
+
+// Filter translation
+
+func TestTranslateFilter(t *testing.T) {
+	tests := []struct {
+		name     string
+		filter   vectorstore.Filter
+		expected string
+	}{
+		{
+			name:     "tag",
+			filter:   vectorstore.TagFilter("docs"),
+			expected: `{"must":[{"key":"tags","match":{"value":"docs"}}]}`,
+		},
+		{
+			name:     "all tags",
+			filter:   vectorstore.TagsFilter("a", "b"),
+			expected: `{"must":[{"key":"tags","match":{"value":"a"}},{"key":"tags","match":{"value":"b"}}]}`,
+		},
+		{
+			name:     "any tag",
+			filter:   vectorstore.AnyTagFilter("a", "b"),
+			expected: `{"should":[{"key":"tags","match":{"value":"a"}},{"key":"tags","match":{"value":"b"}}]}`,
+		},
+		{
+			name:     "not tag",
+			filter:   vectorstore.Not(vectorstore.TagFilter("draft")),
+			expected: `{"must_not":[{"key":"tags","match":{"value":"draft"}}]}`,
+		},
+		{
+			name:     "tag and scope",
+			filter:   vectorstore.And(vectorstore.TagFilter("docs"), vectorstore.UserFilter("u1")),
+			expected: `{"must":[{"key":"tags","match":{"value":"docs"}},{"must":[{"key":"scope_user","match":{"value":"u1"}}]}]}`,
+		},
+		{
+			name:     "metadata equal string",
+			filter:   vectorstore.Eq("lang", "en"),
+			expected: `{"must":[{"key":"metadata.lang","match":{"value":"en"}}]}`,
+		},
+		{
+			name:     "metadata equal float",
+			filter:   vectorstore.Eq("rating", 4.5),
+			expected: `{"must":[{"key":"metadata.rating","range":{"gte":4.5,"lte":4.5}}]}`,
+		},
+		{
+			name:     "metadata not equal",
+			filter:   vectorstore.Ne("lang", "en"),
+			expected: `{"must_not":[{"key":"metadata.lang","match":{"value":"en"}}]}`,
+		},
+		{
+			name:     "metadata range",
+			filter:   vectorstore.Gte("year", 2020),
+			expected: `{"must":[{"key":"metadata.year","range":{"gte":2020}}]}`,
+		},
+		{
+			name:     "metadata in",
+			filter:   vectorstore.In("lang", "en", "de"),
+			expected: `{"must":[{"key":"metadata.lang","match":{"any":["en","de"]}}]}`,
+		},
+		{
+			name:     "metadata not in",
+			filter:   vectorstore.NotIn("lang", "fr"),
+			expected: `{"must":[{"key":"metadata.lang","match":{"except":["fr"]}}]}`,
+		},
+		{
+			name:     "metadata exists",
+			filter:   vectorstore.Exists("author"),
+			expected: `{"must_not":[{"is_empty":{"key":"metadata.author"}}]}`,
+		},
+		{
+			name:     "time filter",
+			filter:   vectorstore.CreatedAfter(time.UnixMilli(1700000000000)),
+			expected: `{"must":[{"key":"created_at","range":{"gt":1700000000000}}]}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			qf, scores, err := translateFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Empty(t, scores)
+			assert.JSONEq(t, tt.expected, toJSON(t, qf))
+		})
+	}
+}
+
+func TestTranslateFilter_ScoreFilters(t *testing.T) {
+	qf, scores, err := translateFilter(vectorstore.And(
+		vectorstore.TagFilter("docs"),
+		vectorstore.ScoreAtLeast(0.8),
+	))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"must":[{"key":"tags","match":{"value":"docs"}}]}`, toJSON(t, qf))
+	require.Len(t, scores, 1)
+	assert.True(t, scores[0].matches(0.8))
+	assert.False(t, scores[0].matches(0.79))
+
+	// Score-only filters produce no Qdrant filter
+	qf, scores, err = translateFilter(vectorstore.ScoreAbove(0.5))
+	require.NoError(t, err)
+	assert.Nil(t, qf)
+	assert.Len(t, scores, 1)
+}
+
+func TestTranslateFilter_Unsupported(t *testing.T) {
+	tests := []struct {
+		name   string
+		filter vectorstore.Filter
+	}{
+		{"nested score filter", vectorstore.Or(vectorstore.TagFilter("a"), vectorstore.ScoreAbove(0.5))},
+		{"starts with", vectorstore.StartsWith("title", "Intro")},
+		{"range on string", vectorstore.Gt("title", "a")},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := translateFilter(tt.filter)
+			assert.Error(t, err)
+		})
+	}
+}
+
+// Score normalization
+
+func TestNormalizeScore(t *testing.T) {
+	tests := []struct {
+		name             string
+		metric           vectorstore.DistanceMetric
+		raw              float32
+		expectedScore    float32
+		expectedDistance float32
+	}{
+		{"cosine", vectorstore.DistanceMetricCosine, 0.9, 0.9, 0.1},
+		{"default is cosine", "", 0.5, 0.5, 0.5},
+		{"dot product", vectorstore.DistanceMetricDotProduct, 3.0, 3.0, -3.0},
+		{"euclidean identical", vectorstore.DistanceMetricEuclidean, 0, 1.0, 0},
+		{"euclidean far", vectorstore.DistanceMetricEuclidean, 3.0, 0.25, 3.0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			score, distance := normalizeScore(tt.raw, tt.metric)
+			assert.InDelta(t, tt.expectedScore, score, 0.0001)
+			assert.InDelta(t, tt.expectedDistance, distance, 0.0001)
+		})
+	}
+}
+
+// Store and collection operations
+
+func TestNew(t *testing.T) {
+	transport := newMockTransport()
+	newTestStore(t, transport, WithAPIKey("secret"))
+
+	req := transport.last("GET", "/collections")
+	require.NotNil(t, req)
+	assert.Equal(t, "secret", req.Header.Get("api-key"))
+}
+
+func TestNew_Errors(t *testing.T) {
+	_, err := New(context.Background(), WithURL(""))
+	assert.Error(t, err)
+
+	_, err = New(context.Background(), WithDistance(vectorstore.DistanceMetricManhattan))
+	assert.Error(t, err)
+
+	transport := newMockTransport()
+	transport.on("GET", "/collections", 401, `{"status":{"error":"unauthorized"}}`)
+	_, err = New(context.Background(), WithHTTPClient(&http.Client{Transport: transport}))
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unauthorized")
+}
+
+func TestUpsert(t *testing.T) {
+	transport := newMockTransport()
+	transport.on("PUT", "/collections/docs", 200, `{"result":true,"status":"ok"}`)
+	transport.on("POST", "/collections/docs/points", 200, `{"result":[],"status":"ok"}`)
+	transport.on("PUT", "/collections/docs/points", 200, `{"result":{"status":"completed"},"status":"ok"}`)
+
+	store := newTestStore(t, transport)
+	coll := store.Collection("docs")
+
+	doc := &vectorstore.Document{
+		ID:        "doc1",
+		Content:   vectorstore.NewTextContent("hello"),
+		Embedding: vectorstore.NewEmbedding([]float32{0.1, 0.2, 0.3}, "test-model"),
+		Tags:      []string{"greeting"},
+		Scope:     &vectorstore.Scope{Tenant: "acme", User: "u1"},
+		Metadata:  map[string]any{"lang": "en"},
+	}
+
+	result, err := coll.Upsert(context.Background(), doc)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), result.Inserted)
+	assert.Nil(t, doc.Temporal, "caller's document should not be modified")
+
+	// Collection is created from the first embedding's dimensions
+	create := transport.last("PUT", "/collections/docs")
+	require.NotNil(t, create)
+	assert.Equal(t, map[string]any{"size": float64(3), "distance": "Cosine"}, create.Body["vectors"])
+
+	upsert := transport.last("PUT", "/collections/docs/points")
+	require.NotNil(t, upsert)
+	points := upsert.Body["points"].([]any)
+	require.Len(t, points, 1)
+	p := points[0].(map[string]any)
+	assert.Equal(t, pointID("doc1"), p["id"])
+
+	payload := p["payload"].(map[string]any)
+	assert.Equal(t, "doc1", payload["doc_id"])
+	assert.Equal(t, "hello", payload["content_text"])
+	assert.Equal(t, "test-model", payload["embedding_model"])
+	assert.Equal(t, []any{"greeting"}, payload["tags"])
+	assert.Equal(t, "acme", payload["scope_tenant"])
+	assert.Equal(t, "u1", payload["scope_user"])
+	assert.Equal(t, map[string]any{"lang": "en"}, payload["metadata"])
+	assert.Contains(t, payload, "created_at")
+}
+
+func TestUpsert_RequiresEmbedding(t *testing.T) {
+	store := newTestStore(t, newMockTransport())
+	coll := store.Collection("docs")
+
+	_, err := coll.Upsert(context.Background(), &vectorstore.Document{
+		ID:      "doc1",
+		Content: vectorstore.NewTextContent("hello"),
+	})
+	assert.Error(t, err)
+}
+
+func TestQuery(t *testing.T) {
+	transport := newMockTransport()
+	transport.on("POST", "/collections/docs/points/search", 200, `{
+		"result": [
+			{"id": "a", "score": 0.0, "payload": {"doc_id": "near", "content_type": "text", "content_text": "near", "tags": ["docs"]}},
+			{"id": "b", "score": 1.0, "payload": {"doc_id": "mid", "content_type": "text", "content_text": "mid"}},
+			{"id": "c", "score": 9.0, "payload": {"doc_id": "far", "content_type": "text", "content_text": "far"}}
+		],
+		"status": "ok"
+	}`)
+
+	store := newTestStore(t, transport, WithDistance(vectorstore.DistanceMetricEuclidean))
+	coll := store.Collection("docs")
+
+	query := vectorstore.NewQuery(vectorstore.NewEmbedding([]float32{0.1, 0.2}, "test-model"))
+	query.Filters = vectorstore.TagFilter("docs")
+	query.MinScore = 0.4
+
+	result, err := coll.Query(context.Background(), query)
+	require.NoError(t, err)
+
+	// Euclidean distances are normalized to 1/(1+d); "far" (0.1) is below MinScore
+	require.Len(t, result.Matches, 2)
+	assert.Equal(t, "near", result.Matches[0].Document.ID)
+	assert.InDelta(t, 1.0, result.Matches[0].Score, 0.0001)
+	assert.Equal(t, 1, result.Matches[0].Rank)
+	assert.Equal(t, "mid", result.Matches[1].Document.ID)
+	assert.InDelta(t, 0.5, result.Matches[1].Score, 0.0001)
+	assert.InDelta(t, 1.0, result.Matches[1].Distance, 0.0001)
+	assert.Equal(t, "near", result.Matches[0].Document.Content.Text)
+	assert.Equal(t, []string{"docs"}, result.Matches[0].Document.Tags)
+
+	req := transport.last("POST", "/collections/docs/points/search")
+	require.NotNil(t, req)
+	assert.Equal(t, float64(10), req.Body["limit"])
+	filter := req.Body["filter"].(map[string]any)
+	must := filter["must"].([]any)
+	require.Len(t, must, 2)
+	assert.JSONEq(t, `{"key":"tags","match":{"value":"docs"}}`, toJSON(t, must[0]))
+	assertUnexpiredCondition(t, must[1])
+}
+
+// assertUnexpiredCondition checks that cond admits only points with no
+// expires_at or one after now.
+func assertUnexpiredCondition(t *testing.T, cond any) {
+	t.Helper()
+	should := cond.(map[string]any)["should"].([]any)
+	require.Len(t, should, 2)
+	assert.JSONEq(t, `{"is_empty":{"key":"expires_at"}}`, toJSON(t, should[0]))
+
+	expiry := should[1].(map[string]any)
+	assert.Equal(t, "expires_at", expiry["key"])
+	gt, ok := expiry["range"].(map[string]any)["gt"].(float64)
+	require.True(t, ok, "expires_at condition should be a gt range")
+	assert.InDelta(t, float64(time.Now().UnixMilli()), gt, float64(time.Minute.Milliseconds()))
+}
+
+func TestExpiredDocumentsAreFiltered(t *testing.T) {
+	transport := newMockTransport()
+	transport.on("POST", "/collections/docs/points/scroll", 200, `{
+		"result": {"points": [{"id": "a", "payload": {"doc_id": "live", "content_type": "text", "content_text": "live"}}]},
+		"status": "ok"
+	}`)
+	transport.on("POST", "/collections/docs/points/count", 200, `{"result":{"count":1},"status":"ok"}`)
+
+	store := newTestStore(t, transport)
+	coll := store.Collection("docs")
+	ctx := context.Background()
+
+	// A filter-only query scrolls with the expiry condition added
+	_, err := coll.Query(ctx, &vectorstore.Query{Filters: vectorstore.TagFilter("docs"), Limit: 5})
+	require.NoError(t, err)
+	req := transport.last("POST", "/collections/docs/points/scroll")
+	require.NotNil(t, req)
+	must := req.Body["filter"].(map[string]any)["must"].([]any)
+	require.Len(t, must, 2)
+	assertUnexpiredCondition(t, must[1])
+
+	docs, err := coll.Get(ctx, "live", "expired")
+	require.NoError(t, err)
+	require.Len(t, docs, 1)
+	assert.Equal(t, "live", docs[0].ID)
+	req = transport.last("POST", "/collections/docs/points/scroll")
+	must = req.Body["filter"].(map[string]any)["must"].([]any)
+	require.Len(t, must, 2)
+	assert.Equal(t, []any{pointID("live"), pointID("expired")}, must[0].(map[string]any)["has_id"])
+	assertUnexpiredCondition(t, must[1])
+
+	count, err := coll.Count(ctx, nil)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+	req = transport.last("POST", "/collections/docs/points/count")
+	require.NotNil(t, req)
+	must = req.Body["filter"].(map[string]any)["must"].([]any)
+	require.Len(t, must, 1)
+	assertUnexpiredCondition(t, must[0])
+}
+
+func TestQuery_MetricMismatch(t *testing.T) {
+	store := newTestStore(t, newMockTransport())
+	coll := store.Collection("docs")
+
+	query := vectorstore.NewQuery(vectorstore.NewEmbedding([]float32{0.1, 0.2}, "test-model"))
+	query.Metric = vectorstore.DistanceMetricDotProduct
+
+	_, err := coll.Query(context.Background(), query)
+	assert.Error(t, err)
+}
+
+func TestQuery_MissingCollection(t *testing.T) {
+	store := newTestStore(t, newMockTransport())
+	coll := store.Collection("missing")
+
+	result, err := coll.Query(context.Background(), vectorstore.NewQuery(vectorstore.NewEmbedding([]float32{0.1}, "m")))
+	require.NoError(t, err)
+	assert.Empty(t, result.Matches)
+}
+
+func TestDeleteCollection(t *testing.T) {
+	transport := newMockTransport()
+	transport.on("DELETE", "/collections/docs", 200, `{"result":true,"status":"ok"}`)
+	transport.on("DELETE", "/collections/gone", 200, `{"result":false,"status":"ok"}`)
+
+	store := newTestStore(t, transport)
+
+	assert.NoError(t, store.DeleteCollection(context.Background(), "docs"))
+	assert.Error(t, store.DeleteCollection(context.Background(), "gone"))
+}
+
+func TestPointID(t *testing.T) {
+	assert.Equal(t, pointID("doc1"), pointID("doc1"))
+	assert.NotEqual(t, pointID("doc1"), pointID("doc2"))
+}
+
+func TestPointRoundTrip(t *testing.T) {
+	created := time.UnixMilli(1700000000000)
+	doc := &vectorstore.Document{
+		ID:        "doc1",
+		Content:   vectorstore.NewTextContent("hello"),
+		Embedding: vectorstore.NewEmbedding([]float32{0.1, 0.2}, "test-model"),
+		Tags:      []string{"a", "b"},
+		Scope:     &vectorstore.Scope{Tenant: "acme", Custom: map[string]string{"region": "eu"}},
+		Temporal:  &vectorstore.Temporal{CreatedAt: created, UpdatedAt: created},
+		Metadata:  map[string]any{"lang": "en"},
+	}
+
+	// Round-trip through JSON as the payload would over the wire
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal([]byte(toJSON(t, documentToPoint(doc).Payload)), &payload))
+
+	got := pointToDocument(payload, doc.Embedding.Vector)
+	assert.Equal(t, doc.ID, got.ID)
+	assert.Equal(t, doc.Content.Text, got.Content.Text)
+	assert.Equal(t, doc.Tags, got.Tags)
+	assert.Equal(t, doc.Scope, got.Scope)
+	assert.Equal(t, "test-model", got.Embedding.Model)
+	assert.True(t, created.Equal(got.Temporal.CreatedAt))
+	assert.Equal(t, "en", got.Metadata["lang"])
+}
@@ -34,6 +34,17 @@ type Query struct {
 	// Default: Cosine similarity.
 	Metric DistanceMetric
 
+	// Keywords enables hybrid search: the vector score is blended with a
+	// BM25 keyword score over document text, so exact terms such as SKUs or
+	// error codes are not missed. Supported by the memory provider; others
+	// return ErrHybridSearchNotSupported.
+	Keywords []string
+
+	// Alpha is the weight of the vector score in hybrid search (0.0-1.0);
+	// the keyword score gets 1 - Alpha, so 0 ranks by keywords alone.
+	// Default: nil, 0.5 (DefaultHybridAlpha).
+	Alpha *float64
+
 	// IncludeEmbeddings controls whether to return embeddings in results.
 	// Default: false (embeddings are large and often not needed).
 	IncludeEmbeddings bool
@@ -51,6 +62,17 @@ type Query struct {
 	Explain bool
 }
 
+// DefaultHybridAlpha is the vector score weight used when Query.Alpha is nil.
+const DefaultHybridAlpha = 0.5
+
+// HybridAlpha returns the vector score weight for hybrid search.
+func (q *Query) HybridAlpha() float64 {
+	if q.Alpha == nil {
+		return DefaultHybridAlpha
+	}
+	return *q.Alpha
+}
+
 // NewQuery creates a query with an embedding vector.
 func NewQuery(embedding *Embedding) *Query {
 	return &Query{
@@ -105,6 +127,11 @@ func (q *Query) Validate() error {
 		return fmt.Errorf("MinScore must be between 0 and 1, got %f", q.MinScore)
 	}
 
+	// Validate hybrid blend weight
+	if q.Alpha != nil && (*q.Alpha < 0 || *q.Alpha > 1) {
+		return fmt.Errorf("alpha must be between 0 and 1, got %f", *q.Alpha)
+	}
+
 	// Validate metric
 	if q.Metric != "" {
 		switch q.Metric {
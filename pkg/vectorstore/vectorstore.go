@@ -108,6 +108,25 @@ type Collection interface {
 	//	)
 	UpsertBatch(ctx context.Context, documents []*Document, opts ...BatchOption) (*UpsertResult, error)
 
+	// UpsertIfVersion writes doc only if the stored version of the document
+	// equals expectedVersion, and fails with ErrVersionConflict otherwise.
+	// An expectedVersion of 0 matches a document that does not exist yet or
+	// was never written with UpsertIfVersion. On success the stored version
+	// becomes expectedVersion+1 and doc.Version is updated to match.
+	//
+	// Use it for read-modify-write updates that must not clobber a
+	// concurrent writer. Deduplication is not applied to versioned writes.
+	//
+	// Example:
+	//
+	//	docs, _ := coll.Get(ctx, "doc1")
+	//	doc := docs[0]
+	//	doc.Metadata["views"] = doc.Metadata["views"].(int) + 1
+	//	if _, err := coll.UpsertIfVersion(ctx, doc, doc.Version); errors.Is(err, ErrVersionConflict) {
+	//	    // Reload and retry
+	//	}
+	UpsertIfVersion(ctx context.Context, doc *Document, expectedVersion int64) (*UpsertResult, error)
+
 	// Query performs similarity search and returns matching documents.
 	// The query can include vector similarity, metadata filters, temporal constraints,
 	// scope filters, and more.
@@ -191,6 +210,16 @@ type Collection interface {
 	// This includes document count, storage size, index info, etc.
 	Stats(ctx context.Context) (*CollectionStats, error)
 
+	// Metrics returns the query and upsert activity recorded by this
+	// collection handle since it was opened: query count and latency,
+	// result counts, hit rate, and upsert throughput.
+	//
+	// Example:
+	//
+	//	m := coll.Metrics()
+	//	fmt.Printf("%d queries, avg %v\n", m.Queries, m.AvgQueryLatency())
+	Metrics() CollectionMetrics
+
 	// Clear removes all documents from the collection.
 	// This is primarily useful for testing.
 	//
@@ -238,6 +267,9 @@ type StoreStats struct {
 	// Version is the provider version
 	Version string
 
+	// Metrics sums the query and upsert metrics of all open collections
+	Metrics CollectionMetrics
+
 	// Extra contains provider-specific statistics
 	Extra map[string]any
 }
@@ -0,0 +1,124 @@
+package aixgo
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+)
+
+// Pool errors
+var (
+	// ErrPoolNotFound is returned when calling a role with no registered pool
+	ErrPoolNotFound = errors.New("agent pool not found")
+
+	// ErrNoReadyAgents is returned when no agent in a pool is ready
+	ErrNoReadyAgents = errors.New("no ready agents in pool")
+)
+
+// agentPool balances calls across replica agents by smooth weighted
+// round-robin: each pick adds every ready member's weight to its running
+// score and selects the highest, which then drops by the total. Picks
+// interleave rather than bunching, e.g. weights 2:1 give a, b, a, a, b, a.
+type agentPool struct {
+	mu      sync.Mutex
+	names   []string
+	weights []float64
+	current []float64
+}
+
+// pick selects the next member among those ready reports true for
+func (p *agentPool) pick(ready func(name string) bool) (string, bool) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	best := -1
+	total := 0.0
+	for i, name := range p.names {
+		if !ready(name) {
+			continue
+		}
+		p.current[i] += p.weights[i]
+		total += p.weights[i]
+		if best < 0 || p.current[i] > p.current[best] {
+			best = i
+		}
+	}
+	if best < 0 {
+		return "", false
+	}
+	p.current[best] -= total
+	return p.names[best], true
+}
+
+// RegisterPool registers agents as interchangeable replicas for role, so
+// CallRole can spread calls across them in proportion to weights. A nil
+// weights slice weights all agents equally. Agents not yet registered with
+// the runtime are registered; registering a pool again for the same role
+// replaces it.
+func (r *Runtime) RegisterPool(role string, agents []agent.Agent, weights []float64) error {
+	if len(agents) == 0 {
+		return fmt.Errorf("pool %s: no agents", role)
+	}
+	if weights == nil {
+		weights = make([]float64, len(agents))
+		for i := range weights {
+			weights[i] = 1
+		}
+	}
+	if len(weights) != len(agents) {
+		return fmt.Errorf("pool %s: %d weights for %d agents", role, len(weights), len(agents))
+	}
+
+	pool := &agentPool{
+		names:   make([]string, len(agents)),
+		weights: append([]float64(nil), weights...),
+		current: make([]float64, len(agents)),
+	}
+	for i, a := range agents {
+		if weights[i] <= 0 {
+			return fmt.Errorf("pool %s: weight for %s must be positive, got %v", role, a.Name(), weights[i])
+		}
+		pool.names[i] = a.Name()
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, a := range agents {
+		if existing, ok := r.agents[a.Name()]; ok && existing != a {
+			return fmt.Errorf("pool %s: %w: %s", role, ErrAgentAlreadyRegistered, a.Name())
+		}
+	}
+	for _, a := range agents {
+		name := a.Name()
+		r.agents[name] = a
+		if _, ok := r.channels[name]; !ok {
+			r.channels[name] = make(chan *agent.Message, r.config.ChannelBufferSize)
+		}
+	}
+	r.pools[role] = pool
+	return nil
+}
+
+// CallRole calls one agent of the role's pool, chosen by weighted
+// round-robin among the agents that are ready.
+func (r *Runtime) CallRole(ctx context.Context, role string, input *agent.Message) (*agent.Message, error) {
+	r.mu.RLock()
+	pool, ok := r.pools[role]
+	r.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrPoolNotFound, role)
+	}
+
+	name, ok := pool.pick(func(name string) bool {
+		a, err := r.Get(name)
+		return err == nil && a.Ready()
+	})
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrNoReadyAgents, role)
+	}
+	return r.Call(ctx, name, input)
+}
@@ -0,0 +1,109 @@
+package aixgo
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync/atomic"
+	"testing"
+
+	"github.com/aixgo-dev/aixgo/internal/agent"
+	pb "github.com/aixgo-dev/aixgo/proto"
+)
+
+// replicaAgent answers with its own name and can be marked not ready
+type replicaAgent struct {
+	name    string
+	unready atomic.Bool
+}
+
+func (a *replicaAgent) Name() string                    { return a.name }
+func (a *replicaAgent) Role() string                    { return "worker" }
+func (a *replicaAgent) Start(ctx context.Context) error { return nil }
+func (a *replicaAgent) Stop(ctx context.Context) error  { return nil }
+func (a *replicaAgent) Ready() bool                     { return !a.unready.Load() }
+func (a *replicaAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return &agent.Message{Message: &pb.Message{Payload: a.name}}, nil
+}
+
+func TestRuntime_CallRole_WeightedRoundRobin(t *testing.T) {
+	ctx := context.Background()
+	rt := NewRuntime()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = rt.Stop(ctx) }()
+
+	a, b, c := &replicaAgent{name: "a"}, &replicaAgent{name: "b"}, &replicaAgent{name: "c"}
+	if err := rt.RegisterPool("cheap-model", []agent.Agent{a, b, c}, []float64{5, 3, 2}); err != nil {
+		t.Fatalf("RegisterPool() error = %v", err)
+	}
+	for _, name := range []string{"a", "b", "c"} {
+		if _, _, err := rt.GetChannelStats(name); err != nil {
+			t.Errorf("GetChannelStats(%s) error = %v, want a channel like Register creates", name, err)
+		}
+	}
+
+	input := &agent.Message{Message: &pb.Message{Payload: "q"}}
+	call := func(n int) map[string]int {
+		counts := make(map[string]int)
+		for range n {
+			result, err := rt.CallRole(ctx, "cheap-model", input)
+			if err != nil {
+				t.Fatalf("CallRole() error = %v", err)
+			}
+			counts[result.Payload]++
+		}
+		return counts
+	}
+
+	const calls = 1000
+	counts := call(calls)
+	for name, weight := range map[string]float64{"a": 0.5, "b": 0.3, "c": 0.2} {
+		if got := float64(counts[name]) / calls; math.Abs(got-weight) > 0.02 {
+			t.Errorf("agent %s got %.3f of calls, want about %.1f", name, got, weight)
+		}
+	}
+
+	// Agents that are not ready are skipped
+	a.unready.Store(true)
+	counts = call(100)
+	if counts["a"] != 0 || counts["b"] != 60 || counts["c"] != 40 {
+		t.Errorf("counts with a not ready = %v, want b:60 c:40", counts)
+	}
+
+	b.unready.Store(true)
+	c.unready.Store(true)
+	if _, err := rt.CallRole(ctx, "cheap-model", input); !errors.Is(err, ErrNoReadyAgents) {
+		t.Errorf("CallRole() error = %v, want ErrNoReadyAgents", err)
+	}
+	if _, err := rt.CallRole(ctx, "missing", input); !errors.Is(err, ErrPoolNotFound) {
+		t.Errorf("CallRole() error = %v, want ErrPoolNotFound", err)
+	}
+}
+
+func TestRuntime_RegisterPool_Errors(t *testing.T) {
+	rt := NewRuntime()
+	a := &replicaAgent{name: "a"}
+
+	if err := rt.RegisterPool("r", nil, nil); err == nil {
+		t.Error("RegisterPool() with no agents should fail")
+	}
+	if err := rt.RegisterPool("r", []agent.Agent{a}, []float64{1, 2}); err == nil {
+		t.Error("RegisterPool() with mismatched weights should fail")
+	}
+	if err := rt.RegisterPool("r", []agent.Agent{a}, []float64{0}); err == nil {
+		t.Error("RegisterPool() with a zero weight should fail")
+	}
+
+	// Already registered agents can join a pool, other agents of the same name cannot
+	if err := rt.Register(a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+	if err := rt.RegisterPool("r", []agent.Agent{a}, nil); err != nil {
+		t.Errorf("RegisterPool() with registered agent error = %v", err)
+	}
+	if err := rt.RegisterPool("r", []agent.Agent{&replicaAgent{name: "a"}}, nil); !errors.Is(err, ErrAgentAlreadyRegistered) {
+		t.Errorf("RegisterPool() error = %v, want ErrAgentAlreadyRegistered", err)
+	}
+}
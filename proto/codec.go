@@ -0,0 +1,24 @@
+package proto
+
+import (
+	"encoding/json"
+
+	"google.golang.org/grpc/encoding"
+)
+
+// CodecName is the gRPC content subtype of the agent service codec.
+// Clients select it with grpc.CallContentSubtype(CodecName).
+const CodecName = "aixgo-json"
+
+// jsonCodec marshals the stub service types as JSON, since they are not
+// generated protobuf messages and cannot use the default proto codec.
+// TODO: Remove once the service types are generated by protoc
+type jsonCodec struct{}
+
+func (jsonCodec) Marshal(v any) ([]byte, error)      { return json.Marshal(v) }
+func (jsonCodec) Unmarshal(data []byte, v any) error { return json.Unmarshal(data, v) }
+func (jsonCodec) Name() string                       { return CodecName }
+
+func init() {
+	encoding.RegisterCodec(jsonCodec{})
+}
@@ -13,6 +13,9 @@ import (
 	"github.com/aixgo-dev/aixgo/internal/agent"
 	"github.com/aixgo-dev/aixgo/internal/graph"
 	"github.com/aixgo-dev/aixgo/internal/observability"
+	"github.com/aixgo-dev/aixgo/internal/orchestration"
+	internalruntime "github.com/aixgo-dev/aixgo/internal/runtime"
+	"github.com/aixgo-dev/aixgo/pkg/llm/cost"
 	"github.com/aixgo-dev/aixgo/pkg/session"
 	pb "github.com/aixgo-dev/aixgo/proto"
 	"go.opentelemetry.io/otel/attribute"
@@ -39,6 +42,9 @@ var (
 
 	// ErrSessionManagerNotConfigured is returned when calling session methods without a session manager
 	ErrSessionManagerNotConfigured = errors.New("session manager not configured")
+
+	// ErrCircuitOpen is returned when calling an agent whose circuit breaker is open
+	ErrCircuitOpen = errors.New("circuit breaker open")
 )
 
 // RuntimeConfig contains configuration options for creating a runtime
@@ -70,6 +76,15 @@ type RuntimeConfig struct {
 	// ChannelFullWarningThreshold triggers a warning when channel utilization exceeds this percentage
 	// Default: 80
 	ChannelFullWarningThreshold int
+
+	// CircuitBreaker is the default circuit breaker for agents called through Call
+	// Default: nil (no circuit breaking)
+	CircuitBreaker *CircuitBreakerConfig
+
+	// DeadLetterBufferSize sets how many undeliverable messages DeadLetters
+	// holds before further ones are dropped
+	// Default: 100
+	DeadLetterBufferSize int
 }
 
 // DefaultRuntimeConfig returns a RuntimeConfig with sensible defaults
@@ -82,6 +97,7 @@ func DefaultRuntimeConfig() *RuntimeConfig {
 		AgentStartTimeout:           30 * time.Second,
 		SendTimeout:                 5 * time.Second,
 		ChannelFullWarningThreshold: 80,
+		DeadLetterBufferSize:        internalruntime.DefaultDeadLetterBufferSize,
 	}
 }
 
@@ -132,6 +148,15 @@ func WithSendTimeout(timeout time.Duration) RuntimeOption {
 	}
 }
 
+// WithDeadLetterBufferSize sets the dead-letter channel buffer size
+func WithDeadLetterBufferSize(size int) RuntimeOption {
+	return func(cfg *RuntimeConfig) {
+		if size > 0 {
+			cfg.DeadLetterBufferSize = size
+		}
+	}
+}
+
 // Runtime is the unified in-memory runtime for agent orchestration.
 // It provides:
 //   - Agent registration and lifecycle management
@@ -139,6 +164,7 @@ func WithSendTimeout(timeout time.Duration) RuntimeOption {
 //   - Session persistence and session-aware execution
 //   - Optional observability (metrics and tracing)
 //   - Configurable concurrency limits
+//   - Agent middleware for cross-cutting concerns (see Use)
 //
 // This is the recommended runtime for single-process deployments.
 // For multi-node deployments, use DistributedRuntime.
@@ -153,6 +179,11 @@ type Runtime struct {
 	cancel         context.CancelFunc
 	semaphore      chan struct{} // For limiting concurrent calls
 	messagesSent   uint64        // Atomic counter for metrics
+	breakers       map[string]*circuitBreaker
+	breakersMu     sync.Mutex
+	pools          map[string]*agentPool // Replica pools by role, see RegisterPool
+	middleware     []AgentMiddleware     // Wraps every agent execution, see Use
+	deadLetters    *internalruntime.DeadLetterQueue
 }
 
 // NewRuntime creates a new Runtime with the given options.
@@ -184,10 +215,13 @@ func NewRuntime(opts ...RuntimeOption) *Runtime {
 	}
 
 	return &Runtime{
-		agents:    make(map[string]agent.Agent),
-		channels:  make(map[string]chan *agent.Message),
-		config:    cfg,
-		semaphore: sem,
+		agents:      make(map[string]agent.Agent),
+		channels:    make(map[string]chan *agent.Message),
+		config:      cfg,
+		semaphore:   sem,
+		breakers:    make(map[string]*circuitBreaker),
+		pools:       make(map[string]*agentPool),
+		deadLetters: internalruntime.NewDeadLetterQueue(cfg.DeadLetterBufferSize),
 	}
 }
 
@@ -254,8 +288,24 @@ func (r *Runtime) List() []string {
 
 // Send sends a message to a target agent asynchronously.
 // If the target channel doesn't exist, it will be created.
-// Returns an error if the channel is full after the send timeout.
+// Returns an error if the channel is full after the send timeout; the
+// message is then copied to DeadLetters.
 func (r *Runtime) Send(target string, msg *agent.Message) error {
+	err := r.send(target, msg)
+	if err != nil {
+		r.deadLetters.Add(target, msg, err)
+	}
+	return err
+}
+
+// DeadLetters returns a channel receiving copies of messages Send could not
+// deliver, tagged with the dead_letter_target and dead_letter_reason
+// metadata keys
+func (r *Runtime) DeadLetters() <-chan *agent.Message {
+	return r.deadLetters.Messages()
+}
+
+func (r *Runtime) send(target string, msg *agent.Message) error {
 	r.mu.RLock()
 	ch, ok := r.channels[target]
 	r.mu.RUnlock()
@@ -331,6 +381,9 @@ func (r *Runtime) Call(ctx context.Context, target string, input *agent.Message)
 		return nil, ErrRuntimeNotStarted
 	}
 
+	// Correlate everything this call triggers under one request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	// Acquire semaphore if concurrency limiting is enabled
 	if r.semaphore != nil {
 		select {
@@ -352,6 +405,12 @@ func (r *Runtime) Call(ctx context.Context, target string, input *agent.Message)
 		return nil, fmt.Errorf("%w: %s", ErrAgentNotReady, target)
 	}
 
+	// Fail fast while the agent's circuit is open
+	breaker := r.breakerFor(target)
+	if breaker != nil && !breaker.allow(time.Now()) {
+		return nil, fmt.Errorf("%w: %s", ErrCircuitOpen, target)
+	}
+
 	// Create span for observability (if enabled)
 	if r.config.EnableTracing {
 		var span trace.Span
@@ -366,8 +425,11 @@ func (r *Runtime) Call(ctx context.Context, target string, input *agent.Message)
 
 	// Execute agent
 	startTime := time.Now()
-	result, err := a.Execute(ctx, input)
+	result, err := r.execute(ctx, a, input)
 	duration := time.Since(startTime)
+	if breaker != nil {
+		breaker.record(err, time.Now())
+	}
 
 	// Record metrics (if enabled)
 	if r.config.EnableMetrics && r.config.EnableTracing {
@@ -385,6 +447,9 @@ func (r *Runtime) Call(ctx context.Context, target string, input *agent.Message)
 // CallParallel invokes multiple agents concurrently and returns all results.
 // The number of concurrent calls is limited by MaxConcurrentCalls if configured.
 func (r *Runtime) CallParallel(ctx context.Context, targets []string, input *agent.Message) (map[string]*agent.Message, map[string]error) {
+	// Every target shares the caller's request ID
+	ctx, input = agent.WithRequestID(ctx, input)
+
 	results := make(map[string]*agent.Message)
 	errs := make(map[string]error)
 	var mu sync.Mutex
@@ -570,9 +635,10 @@ func (r *Runtime) StartAgentsPhased(ctx context.Context, agentDefs map[string]ag
 					return fmt.Errorf("agent %s not registered: %w", name, err)
 				}
 
-				// Start agent in goroutine (non-blocking)
+				// Start agent in goroutine (non-blocking) with ctx rather than
+				// gctx, which is canceled once the phase ends
 				go func() {
-					if err := a.Start(gctx); err != nil {
+					if err := a.Start(ctx); err != nil {
 						log.Printf("[Runtime] Agent %s error: %v", name, err)
 					}
 				}()
@@ -644,10 +710,9 @@ func (r *Runtime) SessionManager() session.Manager {
 // The input message is appended to the session before execution,
 // and the result is appended after execution.
 //
-// If the agent implements session.SessionAwareAgent, it will receive
-// the full conversation history during execution. Otherwise, the
-// session is still used for persistence but the agent won't have
-// direct access to history.
+// The agent receives the prior turns as a conversation array under the
+// input's session.MetadataKeyHistory metadata (see session.HistoryFromMessage).
+// Agents implementing session.SessionAwareAgent also get the session itself.
 func (r *Runtime) CallWithSession(
 	ctx context.Context,
 	target string,
@@ -663,13 +728,24 @@ func (r *Runtime) CallWithSession(
 		return nil, fmt.Errorf("get session: %w", err)
 	}
 
+	// Load prior turns before the input joins the session
+	history, err := sess.GetMessages(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("get history: %w", err)
+	}
+
 	// Append input to session
 	if err := sess.AppendMessage(ctx, input); err != nil {
 		return nil, fmt.Errorf("append input: %w", err)
 	}
 
-	// Add session to context
+	// The agent sees the input with the history attached
+	input = session.WithHistory(input, history)
+
+	// Add session to context and track the LLM usage of this turn
 	ctx = session.ContextWithSession(ctx, sess)
+	tracker := cost.NewTracker()
+	ctx = cost.ContextWithTracker(ctx, tracker)
 
 	// Get the agent
 	r.mu.RLock()
@@ -700,7 +776,7 @@ func (r *Runtime) CallWithSession(
 			Message: toProtoMessage(input),
 		}
 
-		result, err := a.Execute(ctx, internalInput)
+		result, err := r.execute(ctx, a, internalInput)
 		if err != nil {
 			return nil, err
 		}
@@ -708,7 +784,8 @@ func (r *Runtime) CallWithSession(
 		pubResult = fromProtoMessage(result)
 	}
 
-	// Append result to session
+	// Append result to session with the turn's usage
+	session.StampUsage(pubResult, tracker)
 	if err := sess.AppendMessage(ctx, pubResult); err != nil {
 		return nil, fmt.Errorf("append result: %w", err)
 	}
@@ -732,6 +809,22 @@ func (r *Runtime) GetChannelStats(name string) (capacity, length int, err error)
 	return cap(ch), len(ch), nil
 }
 
+// BudgetStatus returns the tracked spend of every registered orchestrator that
+// reports it (see orchestration.BudgetReporter), keyed by name. A Router's
+// status includes per-route spend when route caps are set.
+func (r *Runtime) BudgetStatus() map[string]orchestration.BudgetStatus {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	status := make(map[string]orchestration.BudgetStatus)
+	for name, a := range r.agents {
+		if reporter, ok := a.(orchestration.BudgetReporter); ok {
+			status[name] = reporter.BudgetStatus()
+		}
+	}
+	return status
+}
+
 // MessagesSent returns the total number of messages sent via Send().
 func (r *Runtime) MessagesSent() uint64 {
 	return atomic.LoadUint64(&r.messagesSent)
@@ -1,12 +1,19 @@
 package aixgo
 
 import (
+	"context"
+	"fmt"
+	"math"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
+	pubagent "github.com/aixgo-dev/aixgo/agent"
 	"github.com/aixgo-dev/aixgo/internal/agent"
+	"github.com/aixgo-dev/aixgo/internal/orchestration"
+	"github.com/aixgo-dev/aixgo/pkg/llm/provider"
+	"github.com/aixgo-dev/aixgo/pkg/session"
 	pb "github.com/aixgo-dev/aixgo/proto"
 )
 
@@ -134,6 +141,16 @@ func TestRuntime_Send_FullChannel(t *testing.T) {
 	if !strings.Contains(err.Error(), "timeout") || !strings.Contains(err.Error(), target) {
 		t.Errorf("error = %v, want timeout error for %s", err, target)
 	}
+
+	// The undelivered message is dead-lettered
+	select {
+	case dl := <-rt.DeadLetters():
+		if dl.Id != "overflow" || dl.Metadata["dead_letter_target"] != target {
+			t.Errorf("dead letter = %+v, want the overflow message for %s", dl.Message, target)
+		}
+	default:
+		t.Error("undeliverable message not on the dead-letter channel")
+	}
 }
 
 func TestRuntime_Recv_CreateChannel(t *testing.T) {
@@ -514,3 +531,222 @@ func TestRuntime_StressTest(t *testing.T) {
 		t.Errorf("created %d channels, want %d", numCreatedChannels, numChannels)
 	}
 }
+
+// usageAgent is a session-aware agent that makes one instrumented LLM call
+type usageAgent struct {
+	llm provider.Provider
+}
+
+func (a *usageAgent) Name() string                    { return "usage-agent" }
+func (a *usageAgent) Role() string                    { return "test" }
+func (a *usageAgent) Start(ctx context.Context) error { return nil }
+func (a *usageAgent) Stop(ctx context.Context) error  { return nil }
+func (a *usageAgent) Ready() bool                     { return true }
+func (a *usageAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return input, nil
+}
+
+func (a *usageAgent) ExecuteWithSession(ctx context.Context, input *pubagent.Message, sess session.Session) (*pubagent.Message, error) {
+	resp, err := a.llm.CreateCompletion(ctx, provider.CompletionRequest{Model: "gpt-4o"})
+	if err != nil {
+		return nil, err
+	}
+	return pubagent.NewMessage("assistant", resp.Content), nil
+}
+
+func TestRuntime_CallWithSession_StampsUsage(t *testing.T) {
+	ctx := context.Background()
+
+	backend, err := session.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr := session.NewManager(backend)
+
+	mock := provider.NewMockProvider("mock")
+	for range 2 {
+		mock.AddCompletionResponse(&provider.CompletionResponse{
+			Content: "hi",
+			Usage:   provider.Usage{PromptTokens: 1000, CompletionTokens: 100, TotalTokens: 1100},
+		})
+	}
+
+	rt := NewRuntime()
+	rt.SetSessionManager(mgr)
+	if err := rt.Register(&usageAgent{llm: provider.WrapProvider(mock)}); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	sess, err := mgr.Create(ctx, "usage-agent", session.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for range 2 {
+		result, err := rt.CallWithSession(ctx, "usage-agent", pubagent.NewMessage("user", "hello"), sess.ID())
+		if err != nil {
+			t.Fatalf("CallWithSession() error = %v", err)
+		}
+		if result.Metadata[session.MetadataKeyInputTokens] != 1000 {
+			t.Errorf("result input tokens = %v, want 1000", result.Metadata[session.MetadataKeyInputTokens])
+		}
+	}
+
+	report, err := session.UsageReport(ctx, sess)
+	if err != nil {
+		t.Fatalf("UsageReport() error = %v", err)
+	}
+	if report.Turns != 2 || report.TotalTokens != 2200 {
+		t.Errorf("report = %d turns / %d tokens, want 2 / 2200", report.Turns, report.TotalTokens)
+	}
+	if report.CostUSD <= 0 {
+		t.Errorf("CostUSD = %f, want > 0", report.CostUSD)
+	}
+}
+
+// historyAgent replies with the number of prior turns it was given
+type historyAgent struct {
+	seen [][]provider.Message
+}
+
+func (a *historyAgent) Name() string                    { return "history-agent" }
+func (a *historyAgent) Role() string                    { return "test" }
+func (a *historyAgent) Start(ctx context.Context) error { return nil }
+func (a *historyAgent) Stop(ctx context.Context) error  { return nil }
+func (a *historyAgent) Ready() bool                     { return true }
+func (a *historyAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	history := session.HistoryFromMessage(fromProtoMessage(input))
+	a.seen = append(a.seen, history)
+	return &agent.Message{Message: &pb.Message{
+		Type:    "assistant",
+		Payload: fmt.Sprintf("reply %d", len(a.seen)),
+	}}, nil
+}
+
+func TestRuntime_CallWithSession_InjectsHistory(t *testing.T) {
+	ctx := context.Background()
+
+	backend, err := session.NewFileBackend(t.TempDir())
+	if err != nil {
+		t.Fatalf("NewFileBackend() error = %v", err)
+	}
+	defer func() { _ = backend.Close() }()
+	mgr := session.NewManager(backend)
+
+	rt := NewRuntime()
+	rt.SetSessionManager(mgr)
+	a := &historyAgent{}
+	if err := rt.Register(a); err != nil {
+		t.Fatalf("Register() error = %v", err)
+	}
+
+	sess, err := mgr.Create(ctx, "history-agent", session.CreateOptions{})
+	if err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	for i, text := range []string{"hello", "how are you?", "bye"} {
+		if _, err := rt.CallWithSession(ctx, "history-agent", pubagent.NewMessage("user", text), sess.ID()); err != nil {
+			t.Fatalf("CallWithSession() error = %v", err)
+		}
+		messages, err := sess.GetMessages(ctx)
+		if err != nil {
+			t.Fatalf("GetMessages() error = %v", err)
+		}
+		if len(messages) != 2*(i+1) {
+			t.Errorf("after call %d session has %d messages, want %d", i+1, len(messages), 2*(i+1))
+		}
+		if _, ok := messages[len(messages)-2].Metadata[session.MetadataKeyHistory]; ok {
+			t.Error("stored input should not carry the injected history")
+		}
+	}
+
+	want := []provider.Message{
+		{Role: "user", Content: "hello"},
+		{Role: "assistant", Content: "reply 1"},
+		{Role: "user", Content: "how are you?"},
+		{Role: "assistant", Content: "reply 2"},
+	}
+	if len(a.seen[0]) != 0 {
+		t.Errorf("first call saw history %v, want none", a.seen[0])
+	}
+	last := a.seen[2]
+	if len(last) != len(want) {
+		t.Fatalf("third call saw %v, want %v", last, want)
+	}
+	for i := range want {
+		if last[i].Role != want[i].Role || last[i].Content != want[i].Content {
+			t.Errorf("history[%d] = %+v, want %+v", i, last[i], want[i])
+		}
+	}
+}
+
+// pricedAgent replies with a fixed payload and reports a fixed cost
+type pricedAgent struct {
+	name  string
+	reply string
+	usd   float64
+}
+
+func (a *pricedAgent) Name() string                    { return a.name }
+func (a *pricedAgent) Role() string                    { return "test" }
+func (a *pricedAgent) Start(ctx context.Context) error { return nil }
+func (a *pricedAgent) Stop(ctx context.Context) error  { return nil }
+func (a *pricedAgent) Ready() bool                     { return true }
+func (a *pricedAgent) Execute(ctx context.Context, input *agent.Message) (*agent.Message, error) {
+	return &agent.Message{Message: &pb.Message{
+		Payload:  a.reply,
+		Metadata: map[string]interface{}{agent.MetadataKeyCostUSD: a.usd},
+	}}, nil
+}
+
+func TestRuntime_BudgetStatus(t *testing.T) {
+	rt := NewRuntime()
+	for _, a := range []*pricedAgent{
+		{name: "classifier", reply: "complex"},
+		{name: "expensive", reply: "deep answer", usd: 1},
+		{name: "cheap", reply: "quick answer", usd: 0.01},
+	} {
+		if err := rt.Register(a); err != nil {
+			t.Fatalf("Register() error = %v", err)
+		}
+	}
+
+	router := orchestration.NewRouter("router", rt, "classifier",
+		map[string]string{"simple": "cheap", "complex": "expensive"},
+		orchestration.WithBudget(10, nil),
+		orchestration.WithRouteCap("complex", orchestration.RouteCap{MaxUSDPerHour: 1.5, Fallback: "simple"}),
+	)
+	if err := rt.Register(router); err != nil {
+		t.Fatalf("Register(router) error = %v", err)
+	}
+
+	ctx := context.Background()
+	if err := rt.Start(ctx); err != nil {
+		t.Fatalf("Start() error = %v", err)
+	}
+	defer func() { _ = rt.Stop(ctx) }()
+
+	input := &agent.Message{Message: &pb.Message{Payload: "hi"}}
+	for i := range 3 {
+		if _, err := router.Execute(ctx, input); err != nil {
+			t.Fatalf("Execute() #%d error = %v", i+1, err)
+		}
+	}
+
+	status := rt.BudgetStatus()
+	if len(status) != 1 {
+		t.Fatalf("BudgetStatus() = %v, want only the router", status)
+	}
+	got := status["router"]
+	if math.Abs(got.SpentUSD-2.01) > 1e-9 || got.MaxUSD != 10 {
+		t.Errorf("router spend = $%v of $%v, want $2.01 of $10", got.SpentUSD, got.MaxUSD)
+	}
+	if complex := got.Routes["complex"]; math.Abs(complex.HourUSD-2) > 1e-9 || !complex.Capped {
+		t.Errorf("Routes[complex] = %+v, want $2.00 this hour and capped", complex)
+	}
+	if simple := got.Routes["simple"]; math.Abs(simple.TotalUSD-0.01) > 1e-9 || simple.Capped {
+		t.Errorf("Routes[simple] = %+v, want $0.01 and not capped", simple)
+	}
+}